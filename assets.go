@@ -0,0 +1,162 @@
+package httpx
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"maps"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+// Assets serves the files of an fs.FS, usually an embed.FS, under URLs
+// carrying a hash of their content, such as /assets/app.3f2a9c1be07d.js for
+// app.js. A new build changes the URL of every file it changes, so hashed
+// URLs are cached by browsers and CDNs for a year without revalidation.
+// Templates look the URLs up by file name with URL, or with the "asset"
+// function of FuncMap:
+//
+//	//go:embed static
+//	var static embed.FS
+//
+//	files, _ := fs.Sub(static, "static")
+//	assets, err := httpx.NewAssets(files, "/assets")
+//	if err != nil {
+//		return err
+//	}
+//	assets.Register(engine.Group(""))
+//	page := template.Must(template.New("page").Funcs(assets.FuncMap()).ParseFS(views, "*.html"))
+//
+//	<script src="{{ asset "app.js" }}"></script>
+//
+// The files are also served under their own names, revalidated on every
+// use, for URLs that cannot be rewritten, such as those in emails.
+type Assets struct {
+	prefix string // relative to the routers of Register
+	public string // prefix of the URLs
+	files  map[string]*asset
+	hashed map[string]*asset
+	// registered reports whether public includes the base path of the
+	// first Register.
+	registered bool
+}
+
+// asset is a file of Assets, held in memory.
+type asset struct {
+	name   string
+	hashed string
+	etag   string
+	data   []byte
+}
+
+// NewAssets reads the files of files, hashing each, to serve them under
+// prefix. It fails when the hashed name of a file is the name of another,
+// such as app.3f2a9c1be07d.js next to app.js, as one would hide the other.
+func NewAssets(files fs.FS, prefix string) (*Assets, error) {
+	prefix = JoinPaths("/", prefix)
+	a := &Assets{
+		prefix: prefix,
+		public: prefix,
+		files:  make(map[string]*asset),
+		hashed: make(map[string]*asset),
+	}
+	err := fs.WalkDir(files, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := fs.ReadFile(files, name)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(data)
+		hash := hex.EncodeToString(sum[:6])
+		ext := path.Ext(name)
+		f := &asset{
+			name:   name,
+			hashed: strings.TrimSuffix(name, ext) + "." + hash + ext,
+			etag:   `"` + hash + `"`,
+			data:   data,
+		}
+		if other, ok := a.hashed[f.hashed]; ok {
+			return fmt.Errorf("httpx: assets %s and %s have the same hashed name %s", other.name, f.name, f.hashed)
+		}
+		a.files[f.name] = f
+		a.hashed[f.hashed] = f
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range a.hashed {
+		if other, ok := a.files[f.hashed]; ok {
+			return nil, fmt.Errorf("httpx: hashed name of asset %s is the name of asset %s", f.name, other.name)
+		}
+	}
+	return a, nil
+}
+
+// URL returns the hashed URL of the file name, such as "app.js" or
+// "css/site.css". Unknown names get the unhashed URL they would have, so
+// that a missing file shows up as a 404 rather than a template error.
+func (a *Assets) URL(name string) string {
+	name = strings.TrimPrefix(name, "/")
+	if f, ok := a.files[name]; ok {
+		return JoinPaths(a.public, f.hashed)
+	}
+	return JoinPaths(a.public, name)
+}
+
+// Manifest returns the hashed URL of every file by name, for build tools
+// and clients that resolve assets themselves.
+func (a *Assets) Manifest() map[string]string {
+	manifest := make(map[string]string, len(a.files))
+	for name := range maps.Keys(a.files) {
+		manifest[name] = a.URL(name)
+	}
+	return manifest
+}
+
+// FuncMap returns the "asset" template function, which is URL.
+func (a *Assets) FuncMap() template.FuncMap {
+	return template.FuncMap{"asset": a.URL}
+}
+
+// Register serves the files on r, under the prefix of the Assets, which
+// is relative to r. The URLs returned from then on include the base path
+// of r, so Register must be called before the URLs are used, and before
+// serving. Registering on further routers serves the files there too,
+// while the URLs keep the base path of the first.
+func (a *Assets) Register(r Router) {
+	route, param := FixWildcardPathIfNeed(r, JoinPaths(a.prefix, "/*path"))
+	h := a.handler(param)
+	r.GET(route, h)
+	r.HEAD(route, h)
+	if !a.registered {
+		a.public = JoinPaths(JoinPaths("/", r.BasePath()), a.prefix)
+		a.registered = true
+	}
+}
+
+func (a *Assets) handler(param string) Handler {
+	return func(ctx Context) error {
+		name := strings.TrimPrefix(ctx.Param(param), "/")
+		f, ok := a.hashed[name]
+		if ok {
+			ctx.SetHeader("Cache-Control", "public, max-age=31536000, immutable")
+		} else if f, ok = a.files[name]; ok {
+			ctx.SetHeader("Cache-Control", "no-cache")
+		} else {
+			return NewNotFoundError(http.StatusText(http.StatusNotFound))
+		}
+		SetEntityTag(ctx, f.etag)
+		if strings.Contains(ctx.Header("If-None-Match"), f.etag) {
+			return ctx.NoContent(http.StatusNotModified)
+		}
+		return ServeContent(ctx, f.name, time.Time{}, bytes.NewReader(f.data))
+	}
+}
@@ -0,0 +1,64 @@
+package httpx
+
+import (
+	"bytes"
+	"html/template"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestAssets(t *testing.T) {
+	assets, err := NewAssets(fstest.MapFS{
+		"app.js":       {Data: []byte("console.log(1)")},
+		"css/site.css": {Data: []byte("body{}")},
+	}, "assets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	js := assets.URL("app.js")
+	if !strings.HasPrefix(js, "/assets/app.") || !strings.HasSuffix(js, ".js") || len(js) != len("/assets/app.js")+13 {
+		t.Fatalf("URL(app.js) = %q", js)
+	}
+	if got := assets.URL("/css/site.css"); !strings.HasPrefix(got, "/assets/css/site.") {
+		t.Fatalf("URL(/css/site.css) = %q", got)
+	}
+	if got := assets.URL("missing.png"); got != "/assets/missing.png" {
+		t.Fatalf("URL(missing.png) = %q", got)
+	}
+	if m := assets.Manifest(); len(m) != 2 || m["app.js"] != js {
+		t.Fatalf("Manifest() = %v", m)
+	}
+
+	changed, err := NewAssets(fstest.MapFS{"app.js": {Data: []byte("console.log(2)")}}, "/assets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed.URL("app.js") == js {
+		t.Fatalf("changed content kept URL %q", js)
+	}
+
+	var buf bytes.Buffer
+	tmpl := template.Must(template.New("page").Funcs(assets.FuncMap()).Parse(`<script src="{{ asset "app.js" }}"></script>`))
+	if err := tmpl.Execute(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != `<script src="`+js+`"></script>` {
+		t.Fatalf("template = %s", got)
+	}
+}
+
+func TestNewAssetsHashedNameCollision(t *testing.T) {
+	assets, err := NewAssets(fstest.MapFS{"app.js": {Data: []byte("console.log(1)")}}, "/assets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hashed := strings.TrimPrefix(assets.URL("app.js"), "/assets/")
+	_, err = NewAssets(fstest.MapFS{
+		"app.js": {Data: []byte("console.log(1)")},
+		hashed:   {Data: []byte("console.log(2)")},
+	}, "/assets")
+	if err == nil || !strings.Contains(err.Error(), hashed) {
+		t.Fatalf("NewAssets() error = %v, want a collision on %s", err, hashed)
+	}
+}
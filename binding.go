@@ -0,0 +1,682 @@
+package httpx
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/textproto"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+	durationType        = reflect.TypeFor[time.Duration]()
+	timeType            = reflect.TypeFor[time.Time]()
+)
+
+// BindTimeLayouts are the layouts tried in order when binding a time.Time
+// field without a time_format tag.
+var BindTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	time.DateOnly,
+}
+
+// valueLookup returns the values stored under key and whether key was present.
+type valueLookup func(key string) ([]string, bool)
+
+// BindValues decodes values, such as query or form values, into the struct
+// pointed to by dst, using the given struct tag for field names. It is the
+// query, form and header binder of the built-in Context and of the fiberx,
+// echox and hertzx adapters; ginx falls back to it for structs that
+// NeedsValueBinder reports. Like gin, it ignores spaces around numbers and
+// booleans.
+//
+// Besides strings, numbers, booleans and their pointers and slices, it
+// decodes:
+//
+//   - time.Time, using the field's time_format tag, which is a layout or one
+//     of "unix", "unixmilli" and "unixnano", or else BindTimeLayouts
+//   - time.Duration, using time.ParseDuration
+//   - encoding.TextUnmarshaler implementations
+//   - types registered with RegisterBindType
+//   - maps with string keys, from "name[key]" entries
+//   - slices also from "name[]" entries, as in "tag[]=a&tag[]=b"
+//   - structs whose field is tagged with a name, from "name[field]" entries,
+//     as in "filter[status]=open", nested to any depth
+//
+// Untagged struct fields are flattened: their fields are bound from the top
+// level names. Nil pointers to structs are left nil unless one of their
+// fields is bound, and recursive types, such as a Next *Node field of Node,
+// are bound only as deep as their "name[...]" entries go.
+//
+// A field missing from values, or given only an empty value, takes the
+// value of its default tag; slices split the default on commas. Fields whose
+// binding tag includes "required" and that are still missing make BindValues
+// return a *ValidationError failing them on the "required" rule, which
+// wraps a *MissingFieldsError listing their names.
+func BindValues(dst any, tag string, values map[string][]string) error {
+	return bindValues(dst, tag, lookupValues(values), values)
+}
+
+// BindHeaders binds header values like BindValues, using the "header" tag
+// and case-insensitive names.
+func BindHeaders(dst any, header map[string][]string) error {
+	return bindValues(dst, "header", func(key string) ([]string, bool) {
+		v, ok := header[textproto.CanonicalMIMEHeaderKey(key)]
+		return v, ok
+	}, nil)
+}
+
+// BindParams binds route parameters like BindValues, using the "uri" tag.
+// It is the path parameter binder of the built-in Context; the adapters use
+// it for structs with fields of registered bind types, see UsesBindTypes.
+func BindParams(dst any, params map[string]string) error {
+	return bindValues(dst, "uri", func(key string) ([]string, bool) {
+		v, ok := params[key]
+		if !ok {
+			return nil, false
+		}
+		return []string{v}, true
+	}, nil)
+}
+
+// BindTypeParser parses a single bound value into a value of its
+// registered type.
+type BindTypeParser func(value string) (any, error)
+
+var (
+	bindTypes     sync.Map // reflect.Type -> BindTypeParser
+	bindTypeUsers sync.Map // reflect.Type -> bool
+)
+
+// RegisterBindType registers parse as the decoder of t for BindValues,
+// BindHeaders and BindParams, so domain types such as UUIDs, amounts of
+// money or enums bind from query, form, header and path values on every
+// adapter, including pointers and slices of them:
+//
+//	httpx.RegisterBindType(reflect.TypeFor[uuid.UUID](), func(s string) (any, error) {
+//		return uuid.Parse(s)
+//	})
+//
+// parse must return a value assignable to t; an empty value binds the zero
+// value without calling it. Registered types take precedence over the
+// built-in decoding, including encoding.TextUnmarshaler. Register types
+// during initialization, before requests are bound.
+func RegisterBindType(t reflect.Type, parse BindTypeParser) {
+	bindTypes.Store(t, parse)
+	valueBinderTypes.Clear()
+	bindTypeUsers.Clear()
+}
+
+func bindTypeParser(t reflect.Type) (BindTypeParser, bool) {
+	parse, ok := bindTypes.Load(t)
+	if !ok {
+		return nil, false
+	}
+	return parse.(BindTypeParser), true
+}
+
+// UsesBindTypes reports whether the struct pointed to by dst has fields of
+// types registered with RegisterBindType, including pointers and slices of
+// them and the fields of nested structs. The adapters bind route parameters
+// of such structs with BindParams rather than the framework's binder. The
+// result is cached per type.
+func UsesBindTypes(dst any) bool {
+	t := reflect.TypeOf(dst)
+	if t == nil || t.Kind() != reflect.Pointer || t.Elem().Kind() != reflect.Struct {
+		return false
+	}
+	if uses, ok := bindTypeUsers.Load(t); ok {
+		return uses.(bool)
+	}
+	uses := usesBindTypes(t.Elem(), make(map[reflect.Type]bool))
+	bindTypeUsers.Store(t, uses)
+	return uses
+}
+
+func usesBindTypes(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if isBindType(field.Type) {
+			return true
+		}
+		if ft := field.Type; isNestedStruct(ft) {
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if usesBindTypes(ft, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isBindType reports whether t, or the element of t as a pointer or slice,
+// is a registered bind type.
+func isBindType(t reflect.Type) bool {
+	for {
+		if _, ok := bindTypeParser(t); ok {
+			return true
+		}
+		if t.Kind() != reflect.Pointer && t.Kind() != reflect.Slice {
+			return false
+		}
+		t = t.Elem()
+	}
+}
+
+// MissingFieldsError reports required fields absent from a request. It is a
+// StatusError with status 400. BindValues, BindHeaders and BindParams
+// return it wrapped in a *ValidationError, with status 422, so that the
+// fields are reported like those failing validation.
+type MissingFieldsError struct {
+	// Source is the struct tag of the binding: "query", "form", "header" or
+	// "uri".
+	Source string
+	// Fields lists the names of the missing fields.
+	Fields []string
+
+	// paths holds the paths of the fields in the bound struct, such as
+	// "Filter.Status" for "filter[status]", for their FieldError.
+	paths []string
+}
+
+func (e *MissingFieldsError) Error() string {
+	return fmt.Sprintf("httpx: missing required %s fields: %s", e.Source, strings.Join(e.Fields, ", "))
+}
+
+func (e *MissingFieldsError) GetStatus() int32 {
+	return http.StatusBadRequest
+}
+
+func (e *MissingFieldsError) GetMessage() string {
+	return fmt.Sprintf("missing required %s fields: %s", e.Source, strings.Join(e.Fields, ", "))
+}
+
+// bindError reports a value that cannot be decoded into its field, such as
+// "abc" for an int. BindValues, BindHeaders and BindParams return it wrapped
+// in a *ValidationError whose field fails the "type" rule.
+type bindError struct {
+	tag  string
+	name string // in the values, such as "filter[page]"
+	path string // in the bound struct, such as "Filter.Page"
+	err  error
+}
+
+func (e *bindError) Error() string {
+	return fmt.Sprintf("httpx: bind %s %q: %v", e.tag, e.name, e.err)
+}
+
+func (e *bindError) Unwrap() error {
+	return e.err
+}
+
+var valueBinderTypes sync.Map // reflect.Type -> bool
+
+// NeedsValueBinder reports whether the struct pointed to by dst has fields
+// that gin's binder decodes unlike BindValues: time.Time, maps, and
+// encoding.TextUnmarshaler implementations and types registered with
+// RegisterBindType, including pointers and slices of them, named struct
+// fields, and fields with a default tag or a required binding tag. The
+// result is cached per type.
+func NeedsValueBinder(dst any) bool {
+	t := reflect.TypeOf(dst)
+	if t == nil || t.Kind() != reflect.Pointer || t.Elem().Kind() != reflect.Struct {
+		return false
+	}
+	if needs, ok := valueBinderTypes.Load(t); ok {
+		return needs.(bool)
+	}
+	needs := needsValueBinder(t.Elem(), make(map[reflect.Type]bool))
+	valueBinderTypes.Store(t, needs)
+	return needs
+}
+
+func needsValueBinder(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if _, ok := field.Tag.Lookup("default"); ok || isRequired(field) || isBindType(field.Type) {
+			return true
+		}
+		ft := field.Type
+		for ft.Kind() == reflect.Pointer || ft.Kind() == reflect.Slice {
+			if reflect.PointerTo(ft).Implements(textUnmarshalerType) {
+				return true
+			}
+			ft = ft.Elem()
+		}
+		switch {
+		case ft.Kind() == reflect.Map, reflect.PointerTo(ft).Implements(textUnmarshalerType):
+			return true
+		case ft.Kind() == reflect.Struct && isNestedStruct(ft):
+			// gin flattens named struct fields, which BindValues binds from
+			// "name[field]" entries.
+			if hasValueTag(field) || needsValueBinder(ft, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// bindValues decodes string values into the exported fields of the struct
+// pointed to by dst. Field names come from the given struct tag, falling back
+// to the Go field name; a tag of "-" skips the field. Embedded structs are
+// flattened, and nested struct fields are decoded recursively. Map fields
+// are decoded from the "name[key]" entries of values, and are unsupported
+// when values is nil.
+func bindValues(dst any, tag string, lookup valueLookup, values map[string][]string) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return errors.New("httpx: bind destination must be a non-nil pointer")
+	}
+	rv = rv.Elem()
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("httpx: bind destination must point to a struct, got %s", rv.Kind())
+	}
+	var missing []missingField
+	if _, err := bindStruct(rv, tag, lookup, values, &missing, make(map[reflect.Type]bool)); err != nil {
+		return ToValidationError(err)
+	}
+	if len(missing) > 0 {
+		mfe := &MissingFieldsError{Source: tag}
+		for _, f := range missing {
+			mfe.Fields = append(mfe.Fields, f.name)
+			mfe.paths = append(mfe.paths, f.path)
+		}
+		return ToValidationError(mfe)
+	}
+	return nil
+}
+
+// missingField is a required field missing from the values bound, by its
+// name in the values and its path in the struct.
+type missingField struct {
+	name, path string
+}
+
+// hasValueTag reports whether field is named by a query, form or header tag.
+func hasValueTag(field reflect.StructField) bool {
+	for _, tag := range []string{"query", "form", "header"} {
+		if name, ok := field.Tag.Lookup(tag); ok && name != "-" {
+			return true
+		}
+	}
+	return false
+}
+
+// isRequired reports whether the binding tag of field includes "required",
+// as in `binding:"required,min=1"`.
+func isRequired(field reflect.StructField) bool {
+	for rule := range strings.SplitSeq(field.Tag.Get("binding"), ",") {
+		if strings.TrimSpace(rule) == "required" {
+			return true
+		}
+	}
+	return false
+}
+
+// isMissing reports whether values carry no usable value.
+func isMissing(values []string, ok bool) bool {
+	return !ok || len(values) == 0 || (len(values) == 1 && values[0] == "")
+}
+
+// bindStruct binds the fields of the struct rv and reports whether any was
+// bound from values, defaults aside. seen holds the struct types being bound
+// further up, so that recursive types are only descended into as deep as
+// values go.
+func bindStruct(rv reflect.Value, tag string, lookup valueLookup, all map[string][]string, missing *[]missingField, seen map[reflect.Type]bool) (bool, error) {
+	rt := rv.Type()
+	seen[rt] = true
+	defer delete(seen, rt)
+	bound := false
+	for i := range rt.NumField() {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, ok := field.Tag.Lookup(tag)
+		if name == "-" {
+			continue
+		}
+		fv := rv.Field(i)
+		if isNestedStruct(field.Type) {
+			var nestedMissing []missingField
+			nestedLookup, nestedAll := lookup, all
+			if ok {
+				nestedLookup = func(key string) ([]string, bool) {
+					return lookup(nestedName(name, key))
+				}
+				nestedAll = nestedValues(all, name)
+			}
+			target := fv
+			if fv.Kind() == reflect.Pointer {
+				// A recursive type is only bound from the entries nested
+				// under its name, which end; it is skipped when flattened
+				// or when values cannot be listed.
+				if seen[field.Type.Elem()] && (!ok || len(nestedAll) == 0) {
+					continue
+				}
+				// Nil pointers are only set when something is bound, but
+				// are descended into to report their required fields.
+				if fv.IsNil() {
+					target = reflect.New(field.Type.Elem())
+				}
+				target = target.Elem()
+			}
+			nestedBound, err := bindStruct(target, tag, nestedLookup, nestedAll, &nestedMissing, seen)
+			if err != nil {
+				var be *bindError
+				if errors.As(err, &be) {
+					if ok {
+						be.name = nestedName(name, be.name)
+					}
+					be.path = field.Name + "." + be.path
+				}
+				return false, err
+			}
+			if nestedBound && fv.Kind() == reflect.Pointer && fv.IsNil() {
+				fv.Set(target.Addr())
+			}
+			bound = bound || nestedBound
+			for _, f := range nestedMissing {
+				if ok {
+					f.name = nestedName(name, f.name)
+				}
+				f.path = field.Name + "." + f.path
+				*missing = append(*missing, f)
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		layout := field.Tag.Get("time_format")
+		if fv.Kind() == reflect.Map {
+			if err := setMap(fv, name, all, layout); err != nil {
+				return false, &bindError{tag: tag, name: name, path: field.Name, err: err}
+			}
+			if fv.Len() == 0 && isRequired(field) {
+				*missing = append(*missing, missingField{name, field.Name})
+			}
+			bound = bound || fv.Len() > 0
+			continue
+		}
+		values, ok := lookup(name)
+		if isMissing(values, ok) && fv.Kind() == reflect.Slice {
+			values, ok = lookup(name + "[]")
+		}
+		if isMissing(values, ok) {
+			def, hasDefault := field.Tag.Lookup("default")
+			switch {
+			case hasDefault && fv.Kind() == reflect.Slice:
+				values = strings.Split(def, ",")
+			case hasDefault:
+				values = []string{def}
+			case isRequired(field):
+				*missing = append(*missing, missingField{name, field.Name})
+				continue
+			}
+		} else {
+			bound = true
+		}
+		if len(values) == 0 {
+			continue
+		}
+		if err := setField(fv, values, layout); err != nil {
+			return false, &bindError{tag: tag, name: name, path: field.Name, err: err}
+		}
+	}
+	return bound, nil
+}
+
+// nestedName returns the name under which the entry key of the struct
+// field name is stored: "status" of "filter" is "filter[status]", and
+// "labels[env]" is "filter[labels][env]".
+func nestedName(name, key string) string {
+	field, rest, _ := strings.Cut(key, "[")
+	if rest != "" {
+		rest = "[" + rest
+	}
+	return name + "[" + field + "]" + rest
+}
+
+// nestedValues returns the "name[...]" entries of values with the name
+// prefix removed, undoing nestedName, or nil when values is nil.
+func nestedValues(values map[string][]string, name string) map[string][]string {
+	if values == nil {
+		return nil
+	}
+	nested := make(map[string][]string)
+	for key, vals := range values {
+		rest, ok := strings.CutPrefix(key, name+"[")
+		if !ok {
+			continue
+		}
+		field, rest, ok := strings.Cut(rest, "]")
+		if !ok {
+			continue
+		}
+		nested[field+rest] = vals
+	}
+	return nested
+}
+
+// HasBracketKeys reports whether values have "name[]" or "name[key]"
+// entries, which ginx binds with BindValues instead of gin's binder.
+func HasBracketKeys(values map[string][]string) bool {
+	for key := range values {
+		if strings.HasSuffix(key, "]") && strings.Contains(key, "[") {
+			return true
+		}
+	}
+	return false
+}
+
+// setMap decodes the "name[key]" entries of values into the map fv.
+func setMap(fv reflect.Value, name string, values map[string][]string, layout string) error {
+	if values == nil || fv.Type().Key().Kind() != reflect.String {
+		return fmt.Errorf("unsupported field type %s", fv.Type())
+	}
+	prefix := name + "["
+	for key, vals := range values {
+		mapKey, ok := strings.CutPrefix(key, prefix)
+		if !ok || !strings.HasSuffix(mapKey, "]") || len(vals) == 0 {
+			continue
+		}
+		mapKey = mapKey[:len(mapKey)-1]
+		if fv.IsNil() {
+			fv.Set(reflect.MakeMap(fv.Type()))
+		}
+		elem := reflect.New(fv.Type().Elem()).Elem()
+		if err := setField(elem, vals, layout); err != nil {
+			return err
+		}
+		fv.SetMapIndex(reflect.ValueOf(mapKey).Convert(fv.Type().Key()), elem)
+	}
+	return nil
+}
+
+// isNestedStruct reports whether t is a struct decoded field by field rather
+// than from a single value.
+func isNestedStruct(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	if _, ok := bindTypeParser(t); ok {
+		return false
+	}
+	return !reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+func setField(fv reflect.Value, values []string, layout string) error {
+	if _, ok := bindTypeParser(fv.Type()); ok {
+		return setValue(fv, values[0], layout)
+	}
+	if fv.Kind() == reflect.Slice && !fv.Addr().Type().Implements(textUnmarshalerType) {
+		slice := reflect.MakeSlice(fv.Type(), len(values), len(values))
+		for i, value := range values {
+			if err := setValue(slice.Index(i), value, layout); err != nil {
+				return err
+			}
+		}
+		fv.Set(slice)
+		return nil
+	}
+	return setValue(fv, values[0], layout)
+}
+
+func setValue(fv reflect.Value, value, layout string) error {
+	if parse, ok := bindTypeParser(fv.Type()); ok {
+		return setBindType(fv, value, parse)
+	}
+	if fv.Kind() == reflect.Pointer {
+		ptr := reflect.New(fv.Type().Elem())
+		if err := setValue(ptr.Elem(), value, layout); err != nil {
+			return err
+		}
+		fv.Set(ptr)
+		return nil
+	}
+	if fv.Type() == timeType {
+		if value == "" {
+			fv.SetZero()
+			return nil
+		}
+		t, err := parseTime(value, layout)
+		if err != nil {
+			return err
+		}
+		fv.Set(reflect.ValueOf(t))
+		return nil
+	}
+	if u, ok := fv.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(value))
+	}
+	if fv.Kind() != reflect.String {
+		// Like gin, ignore spaces around numbers and booleans.
+		value = strings.TrimSpace(value)
+	}
+	if value == "" && fv.Kind() != reflect.String {
+		// An empty value such as "?page=" decodes to the zero value.
+		fv.SetZero()
+		return nil
+	}
+	if fv.Type() == durationType {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		fv.SetInt(int64(d))
+		return nil
+	}
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported field type %s", fv.Type())
+	}
+	return nil
+}
+
+// setBindType decodes value into fv, of a registered bind type, with parse.
+func setBindType(fv reflect.Value, value string, parse BindTypeParser) error {
+	if value == "" {
+		fv.SetZero()
+		return nil
+	}
+	v, err := parse(value)
+	if err != nil {
+		return err
+	}
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() || !rv.Type().AssignableTo(fv.Type()) {
+		return fmt.Errorf("bind type parser of %s returned %T", fv.Type(), v)
+	}
+	fv.Set(rv)
+	return nil
+}
+
+// parseTime parses value with layout, or with BindTimeLayouts when layout is
+// empty. The layouts "unix", "unixmilli" and "unixnano" parse integer epoch
+// times.
+func parseTime(value, layout string) (time.Time, error) {
+	switch layout {
+	case "unix", "unixmilli", "unixnano":
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+		switch layout {
+		case "unix":
+			return time.Unix(n, 0), nil
+		case "unixmilli":
+			return time.UnixMilli(n), nil
+		default:
+			return time.Unix(0, n), nil
+		}
+	case "":
+		var err error
+		for _, l := range BindTimeLayouts {
+			var t time.Time
+			if t, err = time.Parse(l, value); err == nil {
+				return t, nil
+			}
+		}
+		if err == nil {
+			err = errors.New("no time layouts configured")
+		}
+		return time.Time{}, err
+	default:
+		return time.Parse(layout, value)
+	}
+}
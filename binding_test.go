@@ -0,0 +1,317 @@
+package httpx
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBindValues(t *testing.T) {
+	type Paging struct {
+		Page int `query:"page"`
+	}
+	type input struct {
+		Paging
+		Name    string        `query:"name"`
+		Tags    []string      `query:"tag"`
+		IDs     []uint16      `query:"id"`
+		Limit   *int          `query:"limit"`
+		Timeout time.Duration `query:"timeout"`
+		IP      net.IP        `query:"ip"`
+		Ratio   float64       `query:"ratio"`
+		Debug   bool          `query:"debug"`
+		Skipped string        `query:"-"`
+		Default string
+	}
+	values := map[string][]string{
+		"page":    {" 3"},
+		"name":    {"gopher"},
+		"tag":     {"a", "b"},
+		"id":      {"1", "2"},
+		"limit":   {"10 "},
+		"timeout": {"1.5s"},
+		"ip":      {"127.0.0.1"},
+		"ratio":   {"0.25"},
+		"debug":   {""},
+		"-":       {"nope"},
+		"Default": {"field-name"},
+	}
+	var got input
+	if err := BindValues(&got, "query", values); err != nil {
+		t.Fatalf("BindValues() error = %v", err)
+	}
+	if got.Page != 3 || got.Name != "gopher" || strings.Join(got.Tags, ",") != "a,b" {
+		t.Fatalf("unexpected scalars: %+v", got)
+	}
+	if len(got.IDs) != 2 || got.IDs[1] != 2 || got.Limit == nil || *got.Limit != 10 {
+		t.Fatalf("unexpected slices or pointers: %+v", got)
+	}
+	if got.Timeout != 1500*time.Millisecond || got.IP.String() != "127.0.0.1" || got.Ratio != 0.25 || got.Debug {
+		t.Fatalf("unexpected typed values: %+v", got)
+	}
+	if got.Skipped != "" || got.Default != "field-name" {
+		t.Fatalf("unexpected tag handling: %+v", got)
+	}
+}
+
+func TestBindValuesErrors(t *testing.T) {
+	var dst struct {
+		Page int `query:"page"`
+	}
+	err := BindValues(&dst, "query", map[string][]string{"page": {"x"}})
+	var ve *ValidationError
+	if !errors.As(err, &ve) || len(ve.Errors) != 1 || ve.Errors[0] != (FieldError{Field: "Page", Rule: "type", Message: "Page failed the type rule"}) {
+		t.Fatalf("BindValues() error = %v, want a type field error", err)
+	}
+	var numErr *strconv.NumError
+	if !errors.As(err, &numErr) || !strings.Contains(errors.Unwrap(err).Error(), `"page"`) {
+		t.Fatalf("BindValues() error = %v, want the cause in its chain", errors.Unwrap(err))
+	}
+	var nested struct {
+		Filter struct {
+			Page int `query:"page"`
+		} `query:"filter"`
+	}
+	err = BindValues(&nested, "query", map[string][]string{"filter[page]": {"x"}})
+	if !errors.As(err, &ve) || ve.Errors[0].Field != "Filter.Page" || !strings.Contains(errors.Unwrap(err).Error(), `"filter[page]"`) {
+		t.Fatalf("BindValues(nested) error = %v (%v)", err, errors.Unwrap(err))
+	}
+	if err := BindValues(dst, "query", nil); err == nil {
+		t.Fatalf("BindValues() on non-pointer should fail")
+	}
+	var mapDst struct {
+		Meta map[string]string `header:"meta"`
+	}
+	if err := bindValues(&mapDst, "header", lookupValues(nil), nil); err == nil {
+		t.Fatalf("bindValues() without values should reject map fields")
+	}
+}
+
+func TestBindValuesTimesAndMaps(t *testing.T) {
+	type input struct {
+		At      time.Time         `form:"at"`
+		Day     time.Time         `form:"day" time_format:"2006-01-02"`
+		Epoch   *time.Time        `form:"epoch" time_format:"unix"`
+		Dates   []time.Time       `form:"date"`
+		Meta    map[string]string `form:"meta"`
+		Scores  map[string]int    `form:"score"`
+		Filters map[string][]string
+	}
+	values := map[string][]string{
+		"at":              {"2024-05-01T10:00:00Z"},
+		"day":             {"2024-05-02"},
+		"epoch":           {"1700000000"},
+		"date":            {"2024-05-03", "2024-05-04 08:30:00"},
+		"meta[a]":         {"1"},
+		"meta[b]":         {"2"},
+		"score[x]":        {"7"},
+		"Filters[status]": {"open", "closed"},
+		"meta":            {"ignored"},
+	}
+	var got input
+	if err := BindValues(&got, "form", values); err != nil {
+		t.Fatalf("BindValues() error = %v", err)
+	}
+	if !got.At.Equal(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)) || got.Day.Day() != 2 || got.Epoch == nil || got.Epoch.Unix() != 1700000000 {
+		t.Fatalf("unexpected times: %+v", got)
+	}
+	if len(got.Dates) != 2 || got.Dates[1].Hour() != 8 {
+		t.Fatalf("unexpected time slice: %v", got.Dates)
+	}
+	if len(got.Meta) != 2 || got.Meta["b"] != "2" || got.Scores["x"] != 7 || strings.Join(got.Filters["status"], ",") != "open,closed" {
+		t.Fatalf("unexpected maps: %+v", got)
+	}
+}
+
+func TestBindValuesBrackets(t *testing.T) {
+	type Filter struct {
+		Status string            `query:"status"`
+		Tags   []string          `query:"tags"`
+		Labels map[string]string `query:"labels"`
+		Owner  *struct {
+			ID int `query:"id" binding:"required"`
+		} `query:"owner"`
+	}
+	type input struct {
+		IDs    []int   `query:"id"`
+		Filter Filter  `query:"filter"`
+		Or     *Filter `query:"or"`
+	}
+	values := map[string][]string{
+		"id[]":                {"1", "2"},
+		"filter[status]":      {"open"},
+		"filter[tags][]":      {"bug", "ui"},
+		"filter[labels][env]": {"prod"},
+		"filter[owner][id]":   {"7"},
+		"or[status]":          {"closed"},
+	}
+	var got input
+	err := BindValues(&got, "query", values)
+	var missing *MissingFieldsError
+	if !errors.As(err, &missing) || strings.Join(missing.Fields, ",") != "or[owner][id]" {
+		t.Fatalf("BindValues() error = %v, want or[owner][id] missing", err)
+	}
+	if len(got.IDs) != 2 || got.IDs[1] != 2 {
+		t.Fatalf("unexpected id[] slice: %v", got.IDs)
+	}
+	f := got.Filter
+	if f.Status != "open" || strings.Join(f.Tags, ",") != "bug,ui" || f.Labels["env"] != "prod" || f.Owner == nil || f.Owner.ID != 7 {
+		t.Fatalf("unexpected nested struct: %+v", f)
+	}
+	if got.Or == nil || got.Or.Status != "closed" {
+		t.Fatalf("unexpected nested pointer: %+v", got.Or)
+	}
+	if !HasBracketKeys(values) || HasBracketKeys(map[string][]string{"id": {"1"}}) {
+		t.Fatalf("HasBracketKeys misreported")
+	}
+}
+
+func TestNeedsValueBinder(t *testing.T) {
+	type plain struct {
+		Name string   `form:"name"`
+		Tags []string `form:"tag"`
+	}
+	type nested struct {
+		Inner struct {
+			At *time.Time `form:"at"`
+		}
+	}
+	type withMap struct {
+		Meta map[string]string `form:"meta"`
+	}
+	if NeedsValueBinder(&plain{}) {
+		t.Fatalf("plain struct should use the framework binder")
+	}
+	type named struct {
+		Filter struct {
+			Status string `form:"status"`
+		} `form:"filter"`
+	}
+	if !NeedsValueBinder(&nested{}) || !NeedsValueBinder(&withMap{}) {
+		t.Fatalf("time and map fields should use the value binder")
+	}
+	if !NeedsValueBinder(&named{}) {
+		t.Fatalf("named struct fields should use the value binder")
+	}
+	if NeedsValueBinder(plain{}) {
+		t.Fatalf("non-pointer should not use the value binder")
+	}
+}
+
+func TestBindValuesDefaultsAndRequired(t *testing.T) {
+	type input struct {
+		Page  int               `query:"page" default:"1"`
+		Sort  []string          `query:"sort" default:"name,id"`
+		Name  string            `query:"name" binding:"required"`
+		Token string            `query:"token" binding:"required,min=8"`
+		Meta  map[string]string `query:"meta" binding:"required"`
+		Limit int               `query:"limit" default:"20"`
+	}
+	var got input
+	err := BindValues(&got, "query", map[string][]string{"name": {""}, "limit": {"5"}})
+	var missing *MissingFieldsError
+	if !errors.As(err, &missing) {
+		t.Fatalf("BindValues() error = %v, want *MissingFieldsError", err)
+	}
+	if missing.Source != "query" || strings.Join(missing.Fields, ",") != "name,token,meta" {
+		t.Fatalf("missing = %+v", missing)
+	}
+	if _, status, _ := ParseError(err); status != 422 {
+		t.Fatalf("status = %d, want 422", status)
+	}
+	var ve *ValidationError
+	if !errors.As(err, &ve) || len(ve.Errors) != 3 || ve.Errors[0] != (FieldError{Field: "Name", Rule: "required", Message: "Name failed the required rule"}) {
+		t.Fatalf("BindValues() error = %#v, want a *ValidationError", err)
+	}
+	if got.Page != 1 || strings.Join(got.Sort, ",") != "name,id" || got.Limit != 5 {
+		t.Fatalf("defaults not applied: %+v", got)
+	}
+}
+
+// bindLevel is an enum bound through RegisterBindType, which takes precedence
+// over its UnmarshalText.
+type bindLevel int
+
+func (l *bindLevel) UnmarshalText([]byte) error {
+	return errors.New("UnmarshalText called")
+}
+
+func TestRegisterBindType(t *testing.T) {
+	levels := map[string]bindLevel{"low": 1, "high": 2}
+	RegisterBindType(reflect.TypeFor[bindLevel](), func(value string) (any, error) {
+		level, ok := levels[value]
+		if !ok {
+			return nil, errors.New("unknown level")
+		}
+		return level, nil
+	})
+	type input struct {
+		Level  bindLevel   `query:"level" uri:"level"`
+		Levels []bindLevel `query:"levels"`
+		Max    *bindLevel  `query:"max"`
+		Empty  bindLevel   `query:"empty"`
+	}
+	if !UsesBindTypes(&input{}) || !NeedsValueBinder(&input{}) || UsesBindTypes(&struct{ Name string }{}) {
+		t.Fatal("UsesBindTypes or NeedsValueBinder missed the registered type")
+	}
+	var got input
+	err := BindValues(&got, "query", map[string][]string{
+		"level":  {"high"},
+		"levels": {"low", "high"},
+		"max":    {"high"},
+		"empty":  {""},
+	})
+	if err != nil {
+		t.Fatalf("BindValues() error = %v", err)
+	}
+	if got.Level != 2 || len(got.Levels) != 2 || got.Levels[0] != 1 || got.Max == nil || *got.Max != 2 || got.Empty != 0 {
+		t.Fatalf("bound %+v", got)
+	}
+	if err := BindValues(&got, "query", map[string][]string{"level": {"medium"}}); err == nil || !strings.Contains(errors.Unwrap(err).Error(), "unknown level") {
+		t.Fatalf("BindValues(medium) error = %v", err)
+	}
+	got = input{}
+	if err := BindParams(&got, map[string]string{"level": "low"}); err != nil || got.Level != 1 || got.Max != nil {
+		t.Fatalf("BindParams() = %+v, %v", got, err)
+	}
+}
+
+func TestBindValuesRecursiveStruct(t *testing.T) {
+	type Node struct {
+		Name string `query:"name"`
+		Next *Node
+	}
+	var flat Node
+	if err := BindValues(&flat, "query", map[string][]string{"name": {"a"}}); err != nil {
+		t.Fatalf("BindValues() error = %v", err)
+	}
+	if flat.Name != "a" || flat.Next != nil {
+		t.Fatalf("unexpected flattened recursive struct: %+v", flat)
+	}
+	if err := BindHeaders(&struct {
+		Node *Node `header:"node"`
+	}{}, map[string][]string{"Name": {"a"}}); err != nil {
+		t.Fatalf("BindHeaders() error = %v", err)
+	}
+
+	type List struct {
+		Name string `query:"name"`
+		Next *List  `query:"next"`
+	}
+	var list List
+	err := BindValues(&list, "query", map[string][]string{
+		"name":             {"a"},
+		"next[name]":       {"b"},
+		"next[next][name]": {"c"},
+	})
+	if err != nil {
+		t.Fatalf("BindValues() error = %v", err)
+	}
+	if list.Next == nil || list.Next.Name != "b" || list.Next.Next == nil || list.Next.Next.Name != "c" || list.Next.Next.Next != nil {
+		t.Fatalf("unexpected nested recursive struct: %+v", list)
+	}
+}
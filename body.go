@@ -0,0 +1,92 @@
+package httpx
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// reusableBody is the in-memory request body installed by ReusableBody.
+type reusableBody struct {
+	*bytes.Reader
+	data []byte
+}
+
+func (b *reusableBody) Close() error {
+	return nil
+}
+
+// ReusableBody reads the body of r on first use and replaces it with an
+// in-memory copy, so that BodyRaw, BodyReader and the binders can each read
+// the full body, in any order. Later calls return the cached bytes and rewind
+// r.Body to its start.
+//
+// Contexts backed by net/http call it before reading the body; fiber and
+// hertz buffer request bodies themselves.
+func ReusableBody(r *http.Request) ([]byte, error) {
+	if b, ok := r.Body.(*reusableBody); ok {
+		b.Reset(b.data)
+		return b.data, nil
+	}
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, nil
+	}
+	data, err := io.ReadAll(r.Body)
+	_ = r.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	r.Body = &reusableBody{Reader: bytes.NewReader(data), data: data}
+	return data, nil
+}
+
+// ReusableBodyReader returns a reader over the body of r cached with
+// ReusableBody. Each reader starts at the beginning of the body, and reading
+// it leaves r.Body untouched.
+func ReusableBodyReader(r *http.Request) io.ReadCloser {
+	data, err := ReusableBody(r)
+	if err != nil {
+		return NewReadCloser(errReader{err}, nil)
+	}
+	if len(data) == 0 {
+		return http.NoBody
+	}
+	return NewReadCloser(bytes.NewReader(data), nil)
+}
+
+// SetRequestBody replaces the body of r with data, cached like ReusableBody,
+// and updates the headers as RequestBodySetter describes. Contexts backed by
+// net/http implement RequestBodySetter with it.
+func SetRequestBody(r *http.Request, data []byte) {
+	r.Body = &reusableBody{Reader: bytes.NewReader(data), data: data}
+	r.ContentLength = int64(len(data))
+	r.Header.Set("Content-Length", strconv.Itoa(len(data)))
+	r.Header.Del("Content-Encoding")
+}
+
+func isMultipart(r *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && strings.HasPrefix(mediaType, "multipart/")
+}
+
+// CacheFormBody caches the body of r with ReusableBody before form parsing
+// consumes it. Multipart bodies may hold large files, so they are left to be
+// parsed from the stream and cannot be read again afterwards.
+func CacheFormBody(r *http.Request) error {
+	if isMultipart(r) {
+		return nil
+	}
+	_, err := ReusableBody(r)
+	return err
+}
+
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
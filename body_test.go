@@ -0,0 +1,27 @@
+package httpx
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestReusableBody(t *testing.T) {
+	req := httptest.NewRequest("POST", "/", strings.NewReader("payload"))
+	if _, err := io.ReadAll(ReusableBodyReader(req)); err != nil {
+		t.Fatal(err)
+	}
+	partial := make([]byte, 3)
+	if _, err := io.ReadFull(req.Body, partial); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ReusableBody(req)
+	if err != nil || string(data) != "payload" {
+		t.Fatalf("ReusableBody = %q, %v", data, err)
+	}
+	rest, _ := io.ReadAll(req.Body)
+	if string(rest) != "payload" {
+		t.Fatalf("rewound body = %q, want payload", rest)
+	}
+}
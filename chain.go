@@ -0,0 +1,133 @@
+package httpx
+
+import (
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
+	"sync/atomic"
+)
+
+// ChainContext is implemented by adapter contexts that run handlers composed
+// by Compose.
+type ChainContext interface {
+	Context
+
+	// SetNext sets the handler that the next call to Next runs. Once it has
+	// run, Next falls back to the framework's own chain.
+	SetNext(next Handler)
+}
+
+// Compose composes middlewares around h once, when a route is registered, so
+// that serving a request runs them as nested calls instead of walking a list
+// of framework handlers. Middlewares run in order and continue the chain with
+// ctx.Next(), which returns the error of the rest of the chain; one that
+// returns without calling Next ends it. The composed handler must be called
+// with a ChainContext.
+func Compose(h Handler, middlewares ...Middleware) Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		h = chainStep(middlewares[i], h)
+	}
+	return h
+}
+
+func chainStep(m Middleware, next Handler) Handler {
+	return func(ctx Context) error {
+		ctx.(ChainContext).SetNext(next)
+		return m(ctx)
+	}
+}
+
+// Chain describes the chain of the matched route, see ChainInfo.
+type Chain struct {
+	// Names are the names of the route's router middleware, outermost
+	// first, followed by the name of its handler.
+	Names []string
+	// Index is the index in Names of the running layer.
+	Index int
+}
+
+// ChainEvent reports a layer of a traced chain starting or returning, see
+// TraceChain.
+type ChainEvent struct {
+	Index int
+	Name  string
+	// Exit is false when the layer starts and true when it returns.
+	Exit bool
+	// Err is the error the layer returned, when Exit is set.
+	Err error
+}
+
+type chainTrace struct {
+	chain   Chain
+	observe func(ChainEvent)
+}
+
+var (
+	chainTraceKey = NewKey[*chainTrace]("chain_trace")
+	// chainTracing is set once TraceChain is first called, so untraced
+	// applications do not look the trace up on each request.
+	chainTracing atomic.Bool
+)
+
+// TraceChain traces the chain of the route serving ctx: ChainInfo describes
+// it while it runs, and observe, when non-nil, is called as each of its
+// layers starts and returns. It must be called ahead of the route, from
+// engine middleware such as middleware.DebugChain. Engine middleware run by
+// the framework are not part of the traced chain.
+func TraceChain(ctx StateStore, observe func(ChainEvent)) {
+	chainTracing.Store(true)
+	SetTyped(ctx, chainTraceKey, &chainTrace{chain: Chain{Index: -1}, observe: observe})
+}
+
+// ChainInfo returns the chain of the matched route and the index of the
+// running layer, for diagnosing which middleware ran. It is only available
+// while a chain traced with TraceChain runs.
+func ChainInfo(ctx StateStore) (Chain, bool) {
+	t, ok := chainTraceKey.Get(ctx)
+	if !ok || t == nil || t.chain.Names == nil {
+		return Chain{}, false
+	}
+	return Chain{Names: slices.Clone(t.chain.Names), Index: t.chain.Index}, true
+}
+
+// composeTraced is Compose reporting each layer to the trace of the request.
+func composeTraced(h Handler, middlewares []Middleware) Handler {
+	h = traceLayer(len(middlewares), h)
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		h = chainStep(Middleware(traceLayer(i, Handler(middlewares[i]))), h)
+	}
+	return h
+}
+
+func traceLayer(index int, h Handler) Handler {
+	return func(ctx Context) error {
+		t, _ := chainTraceKey.Get(ctx)
+		parent := t.chain.Index
+		t.chain.Index = index
+		name := t.chain.Names[index]
+		if t.observe != nil {
+			t.observe(ChainEvent{Index: index, Name: name})
+		}
+		err := h(ctx)
+		if t.observe != nil {
+			t.observe(ChainEvent{Index: index, Name: name, Exit: true, Err: err})
+		}
+		t.chain.Index = parent
+		return err
+	}
+}
+
+// funcName returns the name of fn without its package path, such as
+// "middleware.Locale.func1".
+func funcName(fn any) string {
+	f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer())
+	if f == nil {
+		return "unknown"
+	}
+	name := f.Name()
+	if i := strings.LastIndexByte(name, '/'); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
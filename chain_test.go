@@ -0,0 +1,70 @@
+package httpx
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// chainInner names the embedded context of chainTestContext, whose field
+// could not be called Context next to the Context method.
+type chainInner = Context
+
+type chainTestContext struct {
+	chainInner
+	next Handler
+}
+
+func (c *chainTestContext) SetNext(next Handler) {
+	c.next = next
+}
+
+func (c *chainTestContext) Next() error {
+	next := c.next
+	if next == nil {
+		return nil
+	}
+	c.next = nil
+	return next(c)
+}
+
+func TestCompose(t *testing.T) {
+	var calls []string
+	trace := func(name string) Middleware {
+		return func(ctx Context) error {
+			calls = append(calls, name)
+			err := ctx.Next()
+			calls = append(calls, name+" done")
+			return err
+		}
+	}
+	errHandler := errors.New("handler")
+	h := Compose(func(ctx Context) error {
+		calls = append(calls, "handler")
+		return errHandler
+	}, trace("a"), trace("b"))
+
+	if err := h(&chainTestContext{}); !errors.Is(err, errHandler) {
+		t.Fatalf("err = %v, want the handler's error", err)
+	}
+	want := []string{"a", "b", "handler", "b done", "a done"}
+	if !slices.Equal(calls, want) {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+}
+
+func TestComposeStopsWithoutNext(t *testing.T) {
+	ran := false
+	h := Compose(func(ctx Context) error {
+		ran = true
+		return nil
+	}, func(ctx Context) error {
+		return nil
+	})
+	if err := h(&chainTestContext{}); err != nil {
+		t.Fatal(err)
+	}
+	if ran {
+		t.Fatal("handler ran although the middleware did not call Next")
+	}
+}
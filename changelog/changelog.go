@@ -0,0 +1,266 @@
+// Package changelog diffs route snapshots and renders release-note changelogs.
+//
+// A snapshot is the list of routes an Engine registered, as reported through
+// Engine.OnRouteRegistered. Snapshots are stored as JSON so that the routes of
+// two releases can be compared offline:
+//
+//	var routes []httpx.RouteInfo
+//	engine.OnRouteRegistered(func(r httpx.RouteInfo) {
+//		routes = append(routes, r)
+//	})
+//	engine.OnStop(func(context.Context) error {
+//		return changelog.WriteSnapshotFile("routes.json", routes)
+//	})
+//
+// A snapshot may also be an OpenAPI document, such as the one served by
+// openapix, in which case the parameters, request bodies and response
+// models of the routes are compared as well.
+package changelog
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/go-sphere/httpx"
+	"github.com/go-sphere/httpx/openapix"
+)
+
+// Snapshot is the serialized form of a route table.
+type Snapshot struct {
+	Routes []httpx.RouteInfo `json:"routes"`
+	// Document, if set, describes the operations of Routes, whose
+	// parameters, request bodies and responses Diff then compares.
+	Document *openapix.Document `json:"document,omitempty"`
+}
+
+// DocumentSnapshot returns the snapshot of the routes described by doc.
+func DocumentSnapshot(doc *openapix.Document) Snapshot {
+	s := Snapshot{Document: doc}
+	for path, item := range doc.Paths {
+		for method := range item {
+			s.Routes = append(s.Routes, httpx.RouteInfo{Method: strings.ToUpper(method), Path: path})
+		}
+	}
+	slices.SortFunc(s.Routes, compareRoutes)
+	return s
+}
+
+// ReadSnapshot decodes a snapshot from r, which holds either a snapshot or
+// an OpenAPI document.
+func ReadSnapshot(r io.Reader) (Snapshot, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("changelog: read snapshot: %w", err)
+	}
+	var probe struct {
+		OpenAPI string `json:"openapi"`
+	}
+	if json.Unmarshal(data, &probe) == nil && probe.OpenAPI != "" {
+		doc, err := openapix.LoadDocument(bytes.NewReader(data))
+		if err != nil {
+			return Snapshot{}, fmt.Errorf("changelog: %w", err)
+		}
+		return DocumentSnapshot(doc), nil
+	}
+	var s Snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return Snapshot{}, fmt.Errorf("changelog: decode snapshot: %w", err)
+	}
+	return s, nil
+}
+
+// ReadSnapshotFile decodes the snapshot stored at name.
+func ReadSnapshotFile(name string) (Snapshot, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	return ReadSnapshot(f)
+}
+
+// WriteSnapshot encodes routes as an indented snapshot, sorted by path and
+// method so that snapshots diff cleanly under version control.
+func WriteSnapshot(w io.Writer, routes []httpx.RouteInfo) error {
+	s := Snapshot{Routes: slices.Clone(routes)}
+	slices.SortFunc(s.Routes, compareRoutes)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(s)
+}
+
+// WriteSnapshotFile writes routes as a snapshot to name.
+func WriteSnapshotFile(name string, routes []httpx.RouteInfo) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if err := WriteSnapshot(f, routes); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Change describes a route that exists in both snapshots under the same
+// method and path shape but whose parameters, request body or responses
+// changed.
+type Change struct {
+	Method  string   `json:"method"`
+	OldPath string   `json:"old_path"`
+	NewPath string   `json:"new_path"`
+	Details []string `json:"details"`
+}
+
+// Changelog lists the differences between two snapshots.
+type Changelog struct {
+	Added   []httpx.RouteInfo `json:"added,omitempty"`
+	Removed []httpx.RouteInfo `json:"removed,omitempty"`
+	Changed []Change          `json:"changed,omitempty"`
+}
+
+// Empty reports whether the snapshots were equivalent.
+func (c Changelog) Empty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Changed) == 0
+}
+
+// Diff compares two snapshots.
+//
+// Routes are matched by method and path shape, where path parameters match
+// regardless of their name or syntax (":id", "{id}" and "*path" segments).
+// Matched routes whose parameter names differ are reported as changed;
+// everything else is reported as added or removed. When both snapshots
+// have a Document, matched routes are also reported as changed when their
+// parameters, request body schemas or response statuses and schemas
+// differ.
+func Diff(oldSnap, newSnap Snapshot) Changelog {
+	oldByKey := indexRoutes(oldSnap.Routes)
+	newByKey := indexRoutes(newSnap.Routes)
+
+	var c Changelog
+	for key, nr := range newByKey {
+		or, ok := oldByKey[key]
+		if !ok {
+			c.Added = append(c.Added, nr)
+			continue
+		}
+		details := paramChanges(or.Path, nr.Path)
+		details = append(details, operationChanges(oldSnap.Document, or, newSnap.Document, nr)...)
+		if len(details) > 0 {
+			c.Changed = append(c.Changed, Change{
+				Method:  nr.Method,
+				OldPath: or.Path,
+				NewPath: nr.Path,
+				Details: details,
+			})
+		}
+	}
+	for key, or := range oldByKey {
+		if _, ok := newByKey[key]; !ok {
+			c.Removed = append(c.Removed, or)
+		}
+	}
+
+	slices.SortFunc(c.Added, compareRoutes)
+	slices.SortFunc(c.Removed, compareRoutes)
+	slices.SortFunc(c.Changed, func(a, b Change) int {
+		return compareRoutes(
+			httpx.RouteInfo{Method: a.Method, Path: a.NewPath},
+			httpx.RouteInfo{Method: b.Method, Path: b.NewPath},
+		)
+	})
+	return c
+}
+
+// WriteMarkdown renders the changelog as Markdown release notes.
+func (c Changelog) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("## API changes\n")
+	if c.Empty() {
+		b.WriteString("\nNo route changes.\n")
+	}
+	if len(c.Added) > 0 {
+		b.WriteString("\n### Added\n\n")
+		for _, r := range c.Added {
+			fmt.Fprintf(&b, "- `%s %s`\n", r.Method, r.Path)
+		}
+	}
+	if len(c.Removed) > 0 {
+		b.WriteString("\n### Removed\n\n")
+		for _, r := range c.Removed {
+			fmt.Fprintf(&b, "- `%s %s`\n", r.Method, r.Path)
+		}
+	}
+	if len(c.Changed) > 0 {
+		b.WriteString("\n### Changed\n\n")
+		for _, ch := range c.Changed {
+			fmt.Fprintf(&b, "- `%s %s` (was `%s`)\n", ch.Method, ch.NewPath, ch.OldPath)
+			for _, d := range ch.Details {
+				fmt.Fprintf(&b, "  - %s\n", d)
+			}
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func indexRoutes(routes []httpx.RouteInfo) map[string]httpx.RouteInfo {
+	out := make(map[string]httpx.RouteInfo, len(routes))
+	for _, r := range routes {
+		out[routeKey(r)] = r
+	}
+	return out
+}
+
+func routeKey(r httpx.RouteInfo) string {
+	segments := strings.Split(r.Path, "/")
+	for i, seg := range segments {
+		if name, kind := paramSegment(seg); name != "" {
+			segments[i] = kind
+		}
+	}
+	return strings.ToUpper(r.Method) + " " + strings.Join(segments, "/")
+}
+
+// paramSegment returns the parameter name and a shape marker for a path
+// segment, or an empty name for a static segment.
+func paramSegment(seg string) (name, kind string) {
+	switch {
+	case strings.HasPrefix(seg, ":"):
+		return seg[1:], ":"
+	case strings.HasPrefix(seg, "*"):
+		if seg == "*" {
+			return "*", "*"
+		}
+		return seg[1:], "*"
+	case len(seg) > 2 && seg[0] == '{' && seg[len(seg)-1] == '}':
+		return seg[1 : len(seg)-1], ":"
+	}
+	return "", ""
+}
+
+func paramChanges(oldPath, newPath string) []string {
+	oldSegs := strings.Split(oldPath, "/")
+	newSegs := strings.Split(newPath, "/")
+	var details []string
+	for i := range min(len(oldSegs), len(newSegs)) {
+		oldName, _ := paramSegment(oldSegs[i])
+		newName, _ := paramSegment(newSegs[i])
+		if oldName != newName {
+			details = append(details, fmt.Sprintf("path parameter %q renamed to %q", oldName, newName))
+		}
+	}
+	return details
+}
+
+func compareRoutes(a, b httpx.RouteInfo) int {
+	return cmp.Or(cmp.Compare(a.Path, b.Path), cmp.Compare(a.Method, b.Method))
+}
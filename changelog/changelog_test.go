@@ -0,0 +1,223 @@
+package changelog
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/go-sphere/httpx"
+	"github.com/go-sphere/httpx/openapix"
+)
+
+func TestDiff(t *testing.T) {
+	oldSnap := Snapshot{Routes: []httpx.RouteInfo{
+		{Method: "GET", Path: "/users"},
+		{Method: "GET", Path: "/users/:id"},
+		{Method: "DELETE", Path: "/users/:id"},
+	}}
+	newSnap := Snapshot{Routes: []httpx.RouteInfo{
+		{Method: "GET", Path: "/users"},
+		{Method: "GET", Path: "/users/:userID"},
+		{Method: "POST", Path: "/users"},
+	}}
+
+	c := Diff(oldSnap, newSnap)
+	if len(c.Added) != 1 || c.Added[0] != (httpx.RouteInfo{Method: "POST", Path: "/users"}) {
+		t.Fatalf("Added = %v", c.Added)
+	}
+	if len(c.Removed) != 1 || c.Removed[0] != (httpx.RouteInfo{Method: "DELETE", Path: "/users/:id"}) {
+		t.Fatalf("Removed = %v", c.Removed)
+	}
+	if len(c.Changed) != 1 || c.Changed[0].OldPath != "/users/:id" || c.Changed[0].NewPath != "/users/:userID" {
+		t.Fatalf("Changed = %v", c.Changed)
+	}
+
+	var buf bytes.Buffer
+	if err := c.WriteMarkdown(&buf); err != nil {
+		t.Fatalf("WriteMarkdown() error = %v", err)
+	}
+	for _, want := range []string{"### Added", "- `POST /users`", "### Removed", "- `DELETE /users/:id`", `"id" renamed to "userID"`} {
+		if !strings.Contains(buf.String(), want) {
+			t.Fatalf("markdown missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestDiffEmpty(t *testing.T) {
+	snap := Snapshot{Routes: []httpx.RouteInfo{{Method: "GET", Path: "/files/*path"}}}
+	if c := Diff(snap, snap); !c.Empty() {
+		t.Fatalf("Diff() of equal snapshots = %+v, want empty", c)
+	}
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	routes := []httpx.RouteInfo{
+		{Method: "POST", Path: "/b"},
+		{Method: "GET", Path: "/a"},
+	}
+	var buf bytes.Buffer
+	if err := WriteSnapshot(&buf, routes); err != nil {
+		t.Fatalf("WriteSnapshot() error = %v", err)
+	}
+	s, err := ReadSnapshot(&buf)
+	if err != nil {
+		t.Fatalf("ReadSnapshot() error = %v", err)
+	}
+	if len(s.Routes) != 2 || s.Routes[0].Path != "/a" || s.Routes[1].Path != "/b" {
+		t.Fatalf("Routes = %v, want sorted by path", s.Routes)
+	}
+}
+
+func TestDiffOperations(t *testing.T) {
+	str := &openapix.Schema{Type: "string"}
+	integer := &openapix.Schema{Type: "integer", Format: "int64"}
+	user := func(props map[string]*openapix.Schema, required ...string) *openapix.Document {
+		return &openapix.Document{
+			OpenAPI: openapix.Version,
+			Components: &openapix.Components{Schemas: map[string]*openapix.Schema{
+				"User": {Type: "object", Properties: props, Required: required},
+			}},
+		}
+	}
+	jsonBody := func(s *openapix.Schema) map[string]*openapix.MediaType {
+		return map[string]*openapix.MediaType{"application/json": {Schema: s}}
+	}
+	ref := &openapix.Schema{Ref: "#/components/schemas/User"}
+
+	oldDoc := user(map[string]*openapix.Schema{"name": str, "age": integer, "nick": str}, "name")
+	oldDoc.Paths = map[string]openapix.PathItem{
+		"/users/{id}": {"get": {
+			Parameters: []*openapix.Parameter{
+				{Name: "id", In: "path", Required: true, Schema: integer},
+				{Name: "fields", In: "query", Schema: str},
+				{Name: "limit", In: "query", Schema: integer},
+				{Name: "X-Tenant", In: "header", Schema: str},
+			},
+			Responses: map[string]*openapix.Response{
+				"200": {Content: jsonBody(ref)},
+				"404": {},
+			},
+		}},
+		"/users": {"post": {
+			Responses: map[string]*openapix.Response{"201": {Content: jsonBody(ref)}},
+		}},
+	}
+	newDoc := user(map[string]*openapix.Schema{"name": str, "age": str, "email": str, "tags": {Type: "array", Items: str}}, "name", "email")
+	newDoc.Paths = map[string]openapix.PathItem{
+		"/users/{userID}": {"get": {
+			Parameters: []*openapix.Parameter{
+				{Name: "userID", In: "path", Required: true, Schema: integer},
+				{Name: "fields", In: "query", Required: true, Schema: str},
+				{Name: "limit", In: "query", Schema: str},
+				{Name: "expand", In: "query", Schema: str},
+			},
+			Responses: map[string]*openapix.Response{
+				"200": {Content: jsonBody(ref)},
+				"410": {},
+			},
+		}},
+		"/users": {"post": {
+			RequestBody: &openapix.RequestBody{Required: true, Content: jsonBody(ref)},
+			Responses:   map[string]*openapix.Response{"201": {Content: jsonBody(ref)}},
+		}},
+	}
+
+	c := Diff(DocumentSnapshot(oldDoc), DocumentSnapshot(newDoc))
+	if len(c.Added) != 0 || len(c.Removed) != 0 || len(c.Changed) != 2 {
+		t.Fatalf("Diff() = %+v, want two changed routes", c)
+	}
+	get, post := c.Changed[1], c.Changed[0]
+	if get.Method != "GET" || post.Method != "POST" {
+		t.Fatalf("Changed = %+v", c.Changed)
+	}
+	for _, want := range []string{
+		`path parameter "id" renamed to "userID"`,
+		`query parameter "fields" is now required`,
+		`query parameter "limit" type changed from integer (int64) to string`,
+		`query parameter "expand" added`,
+		`header parameter "X-Tenant" removed`,
+		`response 410 added`,
+		`response 404 removed`,
+		`response 200 (application/json) property "age" type changed from integer (int64) to string`,
+		`response 200 (application/json) property "email" added`,
+		`response 200 (application/json) property "tags" added`,
+		`response 200 (application/json) property "nick" removed`,
+	} {
+		if !slices.Contains(get.Details, want) {
+			t.Errorf("GET details missing %q: %q", want, get.Details)
+		}
+	}
+	if want := "request body added"; !slices.Contains(post.Details, want) {
+		t.Errorf("POST details missing %q: %q", want, post.Details)
+	}
+}
+
+func TestDiffRequestBodySchema(t *testing.T) {
+	doc := func(body *openapix.Schema, required bool) Snapshot {
+		return DocumentSnapshot(&openapix.Document{
+			OpenAPI: openapix.Version,
+			Paths: map[string]openapix.PathItem{"/orders": {"post": {
+				RequestBody: &openapix.RequestBody{Required: required, Content: map[string]*openapix.MediaType{
+					"application/json": {Schema: body},
+				}},
+			}}},
+		})
+	}
+	oldBody := &openapix.Schema{Type: "object", Properties: map[string]*openapix.Schema{
+		"items": {Type: "array", Items: &openapix.Schema{Type: "object", Properties: map[string]*openapix.Schema{
+			"sku": {Type: "string"},
+		}}},
+	}}
+	newBody := &openapix.Schema{Type: "object", Required: []string{"items"}, Properties: map[string]*openapix.Schema{
+		"items": {Type: "array", Items: &openapix.Schema{Type: "object", Properties: map[string]*openapix.Schema{
+			"sku": {Type: "integer"},
+		}}},
+	}}
+
+	c := Diff(doc(oldBody, false), doc(newBody, true))
+	if len(c.Changed) != 1 {
+		t.Fatalf("Changed = %+v", c.Changed)
+	}
+	want := []string{
+		`request body is now required`,
+		`request body (application/json) property "items" is now required`,
+		`request body (application/json) property "items[].sku" type changed from string to integer`,
+	}
+	if !slices.Equal(c.Changed[0].Details, want) {
+		t.Fatalf("Details = %q, want %q", c.Changed[0].Details, want)
+	}
+}
+
+func TestDiffRecursiveSchema(t *testing.T) {
+	node := &openapix.Document{
+		OpenAPI: openapix.Version,
+		Components: &openapix.Components{Schemas: map[string]*openapix.Schema{
+			"Node": {Type: "object", Properties: map[string]*openapix.Schema{
+				"next": {Ref: "#/components/schemas/Node"},
+			}},
+		}},
+		Paths: map[string]openapix.PathItem{"/nodes": {"get": {Responses: map[string]*openapix.Response{
+			"200": {Content: map[string]*openapix.MediaType{"application/json": {Schema: &openapix.Schema{Ref: "#/components/schemas/Node"}}}},
+		}}}},
+	}
+	if c := Diff(DocumentSnapshot(node), DocumentSnapshot(node)); !c.Empty() {
+		t.Fatalf("Diff() of equal documents = %+v, want empty", c)
+	}
+}
+
+func TestReadSnapshotDocument(t *testing.T) {
+	api := openapix.New(openapix.Info{Title: "test", Version: "1"})
+	api.Add("/users/:id", openapix.Spec{Method: "GET"})
+	data, err := api.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON() error = %v", err)
+	}
+	s, err := ReadSnapshot(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("ReadSnapshot() error = %v", err)
+	}
+	if s.Document == nil || len(s.Routes) != 1 || s.Routes[0] != (httpx.RouteInfo{Method: "GET", Path: "/users/{id}"}) {
+		t.Fatalf("ReadSnapshot() = %+v", s)
+	}
+}
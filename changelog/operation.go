@@ -0,0 +1,218 @@
+package changelog
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/go-sphere/httpx"
+	"github.com/go-sphere/httpx/openapix"
+)
+
+// maxSchemaDepth bounds the comparison of nested and recursive schemas.
+const maxSchemaDepth = 16
+
+// operationChanges describes how the operation of a route changed between
+// two documents. It is empty unless both documents describe the route.
+func operationChanges(oldDoc *openapix.Document, oldRoute httpx.RouteInfo, newDoc *openapix.Document, newRoute httpx.RouteInfo) []string {
+	oldOp, newOp := operation(oldDoc, oldRoute), operation(newDoc, newRoute)
+	if oldOp == nil || newOp == nil {
+		return nil
+	}
+	d := &differ{old: oldDoc, new: newDoc}
+	d.parameters(oldOp.Parameters, newOp.Parameters)
+	d.requestBody(oldOp.RequestBody, newOp.RequestBody)
+	d.responses(oldOp.Responses, newOp.Responses)
+	return d.details
+}
+
+func operation(doc *openapix.Document, r httpx.RouteInfo) *openapix.Operation {
+	if doc == nil {
+		return nil
+	}
+	return doc.Paths[r.Path][strings.ToLower(r.Method)]
+}
+
+// differ collects the changes between the operations of two documents,
+// against which it resolves schema references.
+type differ struct {
+	old, new *openapix.Document
+	details  []string
+}
+
+func (d *differ) addf(format string, args ...any) {
+	d.details = append(d.details, fmt.Sprintf(format, args...))
+}
+
+// parameters compares parameters by location and name. Path parameters are
+// matched by position through the route path instead, so only their schemas
+// are compared here.
+func (d *differ) parameters(oldParams, newParams []*openapix.Parameter) {
+	key := func(p *openapix.Parameter) string { return p.In + " " + p.Name }
+	oldByKey := make(map[string]*openapix.Parameter, len(oldParams))
+	for _, p := range oldParams {
+		oldByKey[key(p)] = p
+	}
+	newByKey := make(map[string]*openapix.Parameter, len(newParams))
+	for _, p := range newParams {
+		newByKey[key(p)] = p
+		op, ok := oldByKey[key(p)]
+		subject := fmt.Sprintf("%s parameter %q", p.In, p.Name)
+		switch {
+		case !ok && p.In != "path":
+			if p.Required {
+				d.addf("%s added (required)", subject)
+			} else {
+				d.addf("%s added", subject)
+			}
+		case ok:
+			d.required(subject, op.Required, p.Required)
+			d.schema(subject, "", op.Schema, p.Schema, 0)
+		}
+	}
+	for _, p := range oldParams {
+		if _, ok := newByKey[key(p)]; !ok && p.In != "path" {
+			d.addf("%s parameter %q removed", p.In, p.Name)
+		}
+	}
+}
+
+func (d *differ) required(subject string, was, is bool) {
+	switch {
+	case is && !was:
+		d.addf("%s is now required", subject)
+	case was && !is:
+		d.addf("%s is no longer required", subject)
+	}
+}
+
+func (d *differ) requestBody(oldBody, newBody *openapix.RequestBody) {
+	switch {
+	case oldBody == nil && newBody == nil:
+	case oldBody == nil:
+		d.addf("request body added")
+	case newBody == nil:
+		d.addf("request body removed")
+	default:
+		d.required("request body", oldBody.Required, newBody.Required)
+		d.content("request body", oldBody.Content, newBody.Content)
+	}
+}
+
+func (d *differ) responses(oldResps, newResps map[string]*openapix.Response) {
+	for _, code := range slices.Sorted(maps.Keys(newResps)) {
+		oldResp, ok := oldResps[code]
+		if !ok {
+			d.addf("response %s added", code)
+			continue
+		}
+		if oldResp != nil && newResps[code] != nil {
+			d.content("response "+code, oldResp.Content, newResps[code].Content)
+		}
+	}
+	for _, code := range slices.Sorted(maps.Keys(oldResps)) {
+		if _, ok := newResps[code]; !ok {
+			d.addf("response %s removed", code)
+		}
+	}
+}
+
+// content compares the bodies of a request or response by media type.
+func (d *differ) content(subject string, oldContent, newContent map[string]*openapix.MediaType) {
+	for _, mt := range slices.Sorted(maps.Keys(newContent)) {
+		oldMedia, ok := oldContent[mt]
+		if !ok {
+			d.addf("%s media type %s added", subject, mt)
+			continue
+		}
+		d.schema(subject+" ("+mt+")", "", mediaSchema(oldMedia), mediaSchema(newContent[mt]), 0)
+	}
+	for _, mt := range slices.Sorted(maps.Keys(oldContent)) {
+		if _, ok := newContent[mt]; !ok {
+			d.addf("%s media type %s removed", subject, mt)
+		}
+	}
+}
+
+func mediaSchema(m *openapix.MediaType) *openapix.Schema {
+	if m == nil {
+		return nil
+	}
+	return m.Schema
+}
+
+// schema compares the schemas of the value at field, a dotted property
+// path, of subject, with "[]" standing for the items of arrays.
+func (d *differ) schema(subject, field string, oldSchema, newSchema *openapix.Schema, depth int) {
+	oldSchema, newSchema = resolve(d.old, oldSchema), resolve(d.new, newSchema)
+	if oldSchema == nil || newSchema == nil || depth > maxSchemaDepth {
+		return
+	}
+	where := subject
+	if field != "" {
+		where = fmt.Sprintf("%s property %q", subject, field)
+	}
+	if oldType, newType := typeName(oldSchema), typeName(newSchema); oldType != newType {
+		d.addf("%s type changed from %s to %s", where, oldType, newType)
+		return
+	}
+	switch {
+	case newSchema.Items != nil:
+		d.schema(subject, field+"[]", oldSchema.Items, newSchema.Items, depth+1)
+	case newSchema.Properties != nil || oldSchema.Properties != nil:
+		for _, name := range slices.Sorted(maps.Keys(newSchema.Properties)) {
+			prop := joinField(field, name)
+			if _, ok := oldSchema.Properties[name]; !ok {
+				d.addf("%s property %q added", subject, prop)
+				continue
+			}
+			d.required(fmt.Sprintf("%s property %q", subject, prop),
+				slices.Contains(oldSchema.Required, name), slices.Contains(newSchema.Required, name))
+			d.schema(subject, prop, oldSchema.Properties[name], newSchema.Properties[name], depth+1)
+		}
+		for _, name := range slices.Sorted(maps.Keys(oldSchema.Properties)) {
+			if _, ok := newSchema.Properties[name]; !ok {
+				d.addf("%s property %q removed", subject, joinField(field, name))
+			}
+		}
+	}
+}
+
+func joinField(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
+
+// typeName names the type of s for changelogs, such as "string (date-time)".
+func typeName(s *openapix.Schema) string {
+	typ := s.Type
+	if typ == "" {
+		switch {
+		case s.Properties != nil:
+			typ = "object"
+		case s.Items != nil:
+			typ = "array"
+		default:
+			typ = "any"
+		}
+	}
+	if s.Format != "" {
+		typ += " (" + s.Format + ")"
+	}
+	return typ
+}
+
+// resolve follows a reference to the components of doc.
+func resolve(doc *openapix.Document, s *openapix.Schema) *openapix.Schema {
+	for n := 0; s != nil && s.Ref != "" && n < maxSchemaDepth; n++ {
+		name, ok := strings.CutPrefix(s.Ref, "#/components/schemas/")
+		if !ok || doc == nil || doc.Components == nil {
+			return nil
+		}
+		s = doc.Components.Schemas[name]
+	}
+	return s
+}
@@ -0,0 +1,126 @@
+package httpx
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// IsClientGone reports whether the request context of ctx was canceled,
+// which every adapter does when the client disconnects before the response
+// is complete. Passed deadlines, such as those of RequestTimeout, are not
+// reported. Handlers of long-running requests check it to stop work nobody
+// waits for; to be notified instead, wait on ctx.Context().Done().
+func IsClientGone(ctx Context) bool {
+	return errors.Is(ctx.Context().Err(), context.Canceled)
+}
+
+// WatchClient returns a context derived from parent that is canceled when
+// the client closes conn, for adapters whose server does not cancel request
+// contexts on disconnect, such as those built on fasthttp. The connection
+// is watched by peeking at the socket, without consuming data, and only
+// once the context's Done or Err is called, so requests that do not wait on
+// cancellation pay nothing for it.
+//
+// release must be called once the handler returns and before the server
+// reads from conn again. Connections that do not expose their socket, such
+// as in-memory test connections, and platforms other than unix are not
+// watched.
+func WatchClient(parent context.Context, conn net.Conn) (ctx context.Context, release func()) {
+	raw := rawConnOf(conn)
+	if raw == nil || !canPeek {
+		return parent, func() {}
+	}
+	c, cancel := context.WithCancel(parent)
+	w := &clientWatch{Context: c, cancel: cancel, conn: conn, raw: raw, done: make(chan struct{})}
+	return w, w.release
+}
+
+// rawConnOf returns the socket of conn, unwrapping TLS and other wrapping
+// connections exposing NetConn.
+func rawConnOf(conn net.Conn) syscall.RawConn {
+	for conn != nil {
+		if sc, ok := conn.(syscall.Conn); ok {
+			raw, err := sc.SyscallConn()
+			if err != nil {
+				return nil
+			}
+			return raw
+		}
+		u, ok := conn.(interface{ NetConn() net.Conn })
+		if !ok {
+			return nil
+		}
+		conn = u.NetConn()
+	}
+	return nil
+}
+
+// clientWatch is the context returned by WatchClient.
+type clientWatch struct {
+	context.Context
+	cancel  context.CancelFunc
+	conn    net.Conn
+	raw     syscall.RawConn
+	once    sync.Once
+	started atomic.Bool
+	// done is closed when the watching goroutine returns.
+	done chan struct{}
+}
+
+func (w *clientWatch) Done() <-chan struct{} {
+	w.once.Do(w.watch)
+	return w.Context.Done()
+}
+
+func (w *clientWatch) Err() error {
+	if !w.started.Load() && w.Context.Err() == nil {
+		// Not watched yet: check the socket once.
+		var closed bool
+		_ = w.raw.Control(func(fd uintptr) {
+			closed, _ = peekClosed(fd)
+		})
+		if closed {
+			w.cancel()
+		}
+	}
+	return w.Context.Err()
+}
+
+// watch waits in a goroutine until the socket is readable, then cancels the
+// context if the client closed it. A client sending its next request is not
+// gone; the wait ends there, as net/http's does.
+func (w *clientWatch) watch() {
+	w.started.Store(true)
+	go func() {
+		defer close(w.done)
+		var closed bool
+		err := w.raw.Read(func(fd uintptr) bool {
+			var readable bool
+			closed, readable = peekClosed(fd)
+			return readable
+		})
+		if closed || (err != nil && !errors.Is(err, os.ErrDeadlineExceeded)) {
+			w.cancel()
+		}
+	}()
+}
+
+// aLongTimeAgo is a read deadline in the past, which interrupts the wait of
+// watch.
+var aLongTimeAgo = time.Unix(1, 0)
+
+func (w *clientWatch) release() {
+	w.once.Do(func() {})
+	if w.started.Load() {
+		_ = w.conn.SetReadDeadline(aLongTimeAgo)
+		<-w.done
+		_ = w.conn.SetReadDeadline(time.Time{})
+	}
+	w.cancel()
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package httpx
+
+const canPeek = false
+
+func peekClosed(fd uintptr) (closed, readable bool) {
+	return false, false
+}
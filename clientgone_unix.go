@@ -0,0 +1,24 @@
+//go:build unix
+
+package httpx
+
+import "syscall"
+
+const canPeek = true
+
+// peekClosed peeks at the socket fd without blocking. readable reports
+// whether a read would not block; closed whether the peer closed the
+// connection or it failed.
+func peekClosed(fd uintptr) (closed, readable bool) {
+	var b [1]byte
+	n, _, err := syscall.Recvfrom(int(fd), b[:], syscall.MSG_PEEK)
+	switch {
+	case n > 0:
+		return false, true
+	case err == syscall.EAGAIN || err == syscall.EWOULDBLOCK || err == syscall.EINTR:
+		return false, false
+	default:
+		// n == 0 without an error is the end of the stream.
+		return true, true
+	}
+}
@@ -0,0 +1,42 @@
+// Command httpx-changelog diffs two route snapshots, or two OpenAPI
+// documents, and prints a Markdown changelog suitable for release notes.
+//
+// Usage:
+//
+//	httpx-changelog -old routes-v1.json -new routes-v2.json
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/go-sphere/httpx/changelog"
+)
+
+func main() {
+	oldFile := flag.String("old", "", "route snapshot or OpenAPI document of the previous release")
+	newFile := flag.String("new", "", "route snapshot or OpenAPI document of the current release")
+	flag.Parse()
+
+	if *oldFile == "" || *newFile == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := run(*oldFile, *newFile); err != nil {
+		fmt.Fprintln(os.Stderr, "httpx-changelog:", err)
+		os.Exit(1)
+	}
+}
+
+func run(oldFile, newFile string) error {
+	oldSnap, err := changelog.ReadSnapshotFile(oldFile)
+	if err != nil {
+		return err
+	}
+	newSnap, err := changelog.ReadSnapshotFile(newFile)
+	if err != nil {
+		return err
+	}
+	return changelog.Diff(oldSnap, newSnap).WriteMarkdown(os.Stdout)
+}
@@ -0,0 +1,59 @@
+package httpx
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// ErrPreconditionFailed is wrapped by the errors of CheckIfMatch.
+var ErrPreconditionFailed = errors.New(http.StatusText(http.StatusPreconditionFailed))
+
+// SetEntityTag sets the ETag header of the response to etag, quoting it
+// unless it is quoted already, as in "v7" or W/"v7". It does nothing when
+// etag is empty.
+func SetEntityTag(ctx Context, etag string) {
+	if etag == "" {
+		return
+	}
+	ctx.SetHeader("ETag", quoteEntityTag(etag))
+}
+
+// CheckIfMatch checks the If-Match header of the request against the
+// current entity tag of the resource, quoted or not, for optimistic
+// concurrency on updates:
+//
+//	if err := httpx.CheckIfMatch(ctx, doc.Version); err != nil {
+//		return err
+//	}
+//
+// It returns a status 412 error wrapping ErrPreconditionFailed when the
+// header lists no tag matching currentETag by strong comparison, or is "*"
+// and currentETag is empty because the resource does not exist. Requests
+// without If-Match pass.
+func CheckIfMatch(ctx RequestInfo, currentETag string) error {
+	header := ctx.Header("If-Match")
+	if header == "" {
+		return nil
+	}
+	current := quoteEntityTag(currentETag)
+	for tag := range strings.SplitSeq(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" && currentETag != "" {
+			return nil
+		}
+		// Strong comparison: weak tags never match.
+		if currentETag != "" && !strings.HasPrefix(current, "W/") && tag == current {
+			return nil
+		}
+	}
+	return WithStatus(http.StatusPreconditionFailed, ErrPreconditionFailed)
+}
+
+// quoteEntityTag returns etag as a quoted entity tag.
+func quoteEntityTag(etag string) string {
+	if strings.HasPrefix(etag, `"`) || strings.HasPrefix(etag, `W/"`) {
+		return etag
+	}
+	return `"` + etag + `"`
+}
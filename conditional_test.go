@@ -0,0 +1,51 @@
+package httpx
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCheckIfMatch(t *testing.T) {
+	tests := []struct {
+		name, ifMatch, current string
+		wantErr                bool
+	}{
+		{name: "no header", current: "v2"},
+		{name: "match", ifMatch: `"v2"`, current: "v2"},
+		{name: "match quoted current", ifMatch: `"v1", "v2"`, current: `"v2"`},
+		{name: "mismatch", ifMatch: `"v1"`, current: "v2", wantErr: true},
+		{name: "weak never matches", ifMatch: `W/"v2"`, current: `W/"v2"`, wantErr: true},
+		{name: "star", ifMatch: "*", current: "v2"},
+		{name: "star without resource", ifMatch: "*", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/docs/1", nil)
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			err := CheckIfMatch(newHTTPContext(httptest.NewRecorder(), req, nil), tt.current)
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("CheckIfMatch() = %v", err)
+				}
+				return
+			}
+			if _, status, _ := ParseError(err); !errors.Is(err, ErrPreconditionFailed) || status != http.StatusPreconditionFailed {
+				t.Fatalf("CheckIfMatch() = %v, want status 412", err)
+			}
+		})
+	}
+}
+
+func TestSetEntityTag(t *testing.T) {
+	for etag, want := range map[string]string{"v1": `"v1"`, `"v1"`: `"v1"`, `W/"v1"`: `W/"v1"`, "": ""} {
+		rec := httptest.NewRecorder()
+		SetEntityTag(newHTTPContext(rec, httptest.NewRequest(http.MethodGet, "/", nil), nil), etag)
+		if got := rec.Header().Get("ETag"); got != want {
+			t.Fatalf("SetEntityTag(%q) header = %q, want %q", etag, got, want)
+		}
+	}
+}
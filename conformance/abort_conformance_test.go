@@ -0,0 +1,89 @@
+package conformance
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-sphere/httpx"
+	"github.com/go-sphere/httpx/lambdax"
+)
+
+func TestAborterConformance(t *testing.T) {
+	engines := map[string]func(t *testing.T) httpx.Engine{
+		"lambdax": func(*testing.T) httpx.Engine { return lambdax.New() },
+	}
+	for _, name := range conformanceFrameworks {
+		engines[name] = func(t *testing.T) httpx.Engine { return newEphemeralEngine(t, name) }
+	}
+	for name, newEngine := range engines {
+		t.Run(name, func(t *testing.T) {
+			engine := newEngine(t)
+			var outerSaw, innerRan bool
+			engine.Use(func(ctx httpx.Context) error {
+				err := ctx.Next()
+				a, ok := httpx.AsAborter(ctx)
+				if !ok {
+					return errors.New("context does not implement httpx.Aborter")
+				}
+				outerSaw = a.IsAborted()
+				return err
+			})
+			engine.Use(func(ctx httpx.Context) error {
+				innerRan = true
+				return ctx.Next()
+			})
+			abort := func(ctx httpx.Context) error {
+				a, _ := httpx.AsAborter(ctx)
+				switch ctx.Query("mode") {
+				case "json":
+					if err := a.AbortWithStatusJSON(http.StatusForbidden, httpx.H{"error": "forbidden"}); err != nil {
+						return err
+					}
+				case "status":
+					if err := a.AbortWithStatus(http.StatusUnauthorized); err != nil {
+						return err
+					}
+				case "error":
+					a.Abort()
+					return errors.New("aborted with error")
+				default:
+					return ctx.Next()
+				}
+				// The rest of the chain does not run once aborted.
+				return ctx.Next()
+			}
+			var handlerRan bool
+			r := engine.Group("", abort)
+			r.GET("/", func(ctx httpx.Context) error {
+				handlerRan = true
+				return ctx.Text(http.StatusOK, "ok")
+			})
+
+			tests := []struct {
+				query      string
+				wantStatus int
+				wantBody   string
+				aborted    bool
+			}{
+				{query: "", wantStatus: http.StatusOK, wantBody: "ok"},
+				{query: "mode=json", wantStatus: http.StatusForbidden, wantBody: `{"error":"forbidden"}`, aborted: true},
+				{query: "mode=status", wantStatus: http.StatusUnauthorized, aborted: true},
+				{query: "mode=error", wantStatus: http.StatusInternalServerError, wantBody: `{"error":"aborted with error"}`, aborted: true},
+			}
+			do := doEngineTest(engine)
+			for _, tt := range tests {
+				outerSaw, innerRan, handlerRan = false, false, false
+				got := do(t, httptest.NewRequest(http.MethodGet, "http://example.com/?"+tt.query, nil))
+				if got.Status != tt.wantStatus || strings.TrimSpace(got.Body) != tt.wantBody {
+					t.Fatalf("%q: status %d, body %q, want %d %q", tt.query, got.Status, got.Body, tt.wantStatus, tt.wantBody)
+				}
+				if !innerRan || handlerRan == tt.aborted || outerSaw != tt.aborted {
+					t.Fatalf("%q: inner middleware ran %v, handler ran %v, outer middleware saw IsAborted %v", tt.query, innerRan, handlerRan, outerSaw)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package conformance
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-sphere/httpx"
+	"github.com/go-sphere/httpx/middleware"
+)
+
+func TestAccessLogConformance(t *testing.T) {
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			var logs syncBuffer
+			logger := slog.New(slog.NewJSONHandler(&logs, nil))
+			engine := newEphemeralEngine(t, name)
+			handler := func(ctx httpx.Context) error {
+				var in map[string]any
+				if err := ctx.BindJSON(&in); err != nil {
+					return err
+				}
+				return ctx.Text(http.StatusCreated, "ok")
+			}
+			failing := func(ctx httpx.Context) error {
+				return httpx.NewError(http.StatusConflict, 0, "taken", errors.New("taken"))
+			}
+			logged := engine.Group("/logged", middleware.AccessLog(logger,
+				middleware.WithAccessLogHeaders("Authorization", "X-Client"),
+				middleware.WithAccessLogRedactFields("card"),
+				middleware.WithAccessLogBody(64),
+			))
+			logged.POST("/users", handler)
+			sampled := engine.Group("/sampled", middleware.AccessLog(logger, middleware.WithAccessLogSampling(0, true)))
+			sampled.GET("/ok", func(ctx httpx.Context) error {
+				return ctx.Text(http.StatusOK, "ok")
+			})
+			sampled.GET("/fail", failing)
+			do := doEngineTest(engine)
+
+			req := httptest.NewRequest(http.MethodPost, "/logged/users?token=abc&page=2",
+				strings.NewReader(`{"name":"ada","password":"hunter2","payment":{"Card":"4242"},"note":"`+strings.Repeat("x", 64)+`"}`))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer secret")
+			req.Header.Set("X-Client", "cli")
+			if got := do(t, req); got.Status != http.StatusCreated {
+				t.Fatalf("POST = %d %s", got.Status, got.Body)
+			}
+			var record struct {
+				Level, Msg, Method, Path, Route, Query, Body string
+				Status                                       int
+				Headers                                      map[string]string
+				Truncated                                    bool `json:"body_truncated"`
+			}
+			line := logs.take()
+			if err := json.Unmarshal([]byte(line), &record); err != nil {
+				t.Fatalf("log %q: %v", line, err)
+			}
+			if record.Level != "INFO" || record.Msg != "request" || record.Method != http.MethodPost || record.Path != "/logged/users" ||
+				record.Route != "/logged/users" || record.Status != http.StatusCreated || record.Query != "page=2&token=%5BREDACTED%5D" {
+				t.Fatalf("record = %s", line)
+			}
+			if record.Headers["Authorization"] != middleware.Redacted || record.Headers["X-Client"] != "cli" {
+				t.Fatalf("headers = %v", record.Headers)
+			}
+			if strings.Contains(line, "hunter2") || strings.Contains(line, "4242") || strings.Contains(line, "secret") || !record.Truncated || len(record.Body) != 64 {
+				t.Fatalf("body not redacted and truncated: %s", line)
+			}
+
+			// Bodies beyond four times the logged size are not read.
+			req = httptest.NewRequest(http.MethodPost, "/logged/users",
+				strings.NewReader(`{"note":"`+strings.Repeat("x", 4*64)+`"}`))
+			req.Header.Set("Content-Type", "application/json")
+			if got := do(t, req); got.Status != http.StatusCreated {
+				t.Fatalf("POST large = %d %s", got.Status, got.Body)
+			}
+			record.Body, record.Truncated = "", false
+			line = logs.take()
+			if err := json.Unmarshal([]byte(line), &record); err != nil {
+				t.Fatalf("log %q: %v", line, err)
+			}
+			if record.Body != "" || !record.Truncated {
+				t.Fatalf("large body record = %s", line)
+			}
+
+			for range 5 {
+				do(t, httptest.NewRequest(http.MethodGet, "/sampled/ok", nil))
+			}
+			if line := logs.take(); line != "" {
+				t.Fatalf("unsampled successes logged: %s", line)
+			}
+			do(t, httptest.NewRequest(http.MethodGet, "/sampled/fail", nil))
+			if line := logs.take(); !strings.Contains(line, `"level":"WARN"`) || !strings.Contains(line, `"status":409`) {
+				t.Fatalf("failure record = %s", line)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package conformance
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/go-sphere/httpx"
+)
+
+var updateAllocBudgets = flag.Bool("allocs.update", false, "record the measured allocations as the budgets in "+allocBudgetsFile)
+
+// allocBudgetsFile maps Context methods to the allocations each adapter may
+// make per call.
+const allocBudgetsFile = "testdata/alloc_budgets.json"
+
+type allocBudgets map[string]map[string]int
+
+// contextMethod is a Context method call measured for allocations, on a
+// request built by newContextMethodRequest.
+type contextMethod struct {
+	name string
+	call func(ctx httpx.Context) error
+}
+
+type allocPayload struct {
+	Name  string   `json:"name"`
+	Age   int      `json:"age"`
+	Tags  []string `json:"tags"`
+	Admin bool     `json:"admin"`
+}
+
+var allocResponse = allocPayload{Name: "gopher", Age: 13, Tags: []string{"a", "b"}}
+
+var contextMethods = []contextMethod{
+	{name: "Param", call: func(ctx httpx.Context) error {
+		if ctx.Param("id") != "42" {
+			return errors.New("unexpected param")
+		}
+		return nil
+	}},
+	{name: "Query", call: func(ctx httpx.Context) error {
+		if ctx.Query("q") != "go" {
+			return errors.New("unexpected query")
+		}
+		return nil
+	}},
+	{name: "Header", call: func(ctx httpx.Context) error {
+		if ctx.Header("X-Alloc") != "1" {
+			return errors.New("unexpected header")
+		}
+		return nil
+	}},
+	{name: "Headers", call: func(ctx httpx.Context) error {
+		if len(ctx.Headers()) == 0 {
+			return errors.New("no headers")
+		}
+		return nil
+	}},
+	{name: "AllHeaders", call: func(ctx httpx.Context) error {
+		n := 0
+		for range ctx.AllHeaders() {
+			n++
+		}
+		if n == 0 {
+			return errors.New("no headers")
+		}
+		return nil
+	}},
+	{name: "BindJSON", call: func(ctx httpx.Context) error {
+		var in allocPayload
+		return ctx.BindJSON(&in)
+	}},
+	{name: "JSON", call: func(ctx httpx.Context) error {
+		return ctx.JSON(http.StatusOK, allocResponse)
+	}},
+}
+
+func newContextMethodRequest() *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "http://example.com/alloc/42?q=go", strings.NewReader(`{"name":"gopher","age":13,"tags":["a","b"],"admin":true}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Alloc", "1")
+	return req
+}
+
+// inContext runs fn in the handler of a request served by a new engine of
+// the framework.
+func inContext(tb testing.TB, name string, fn func(ctx httpx.Context)) {
+	tb.Helper()
+	h := newHarnessTB(tb, name)
+	h.Router.POST("/alloc/:id", func(ctx httpx.Context) error {
+		fn(ctx)
+		return nil
+	})
+	resp, err := h.Engine.Test(newContextMethodRequest())
+	if err != nil {
+		tb.Fatal(err)
+	}
+	_ = resp.Body.Close()
+}
+
+// BenchmarkContextMethods measures single Context method calls inside a
+// handler, without the cost of serving the request.
+func BenchmarkContextMethods(b *testing.B) {
+	for _, m := range contextMethods {
+		b.Run(m.name, func(b *testing.B) {
+			for _, name := range conformanceFrameworks {
+				b.Run(name, func(b *testing.B) {
+					// Handlers may run on another goroutine, where b.Fatal
+					// must not be called.
+					var callErr error
+					inContext(b, name, func(ctx httpx.Context) {
+						b.ReportAllocs()
+						for b.Loop() {
+							if err := m.call(ctx); err != nil {
+								callErr = err
+							}
+						}
+					})
+					if callErr != nil {
+						b.Fatal(callErr)
+					}
+				})
+			}
+		})
+	}
+}
+
+// TestAllocationBudgets fails when a Context method allocates more per call
+// than its budget. Run it with -allocs.update to record new budgets after an
+// intended change.
+func TestAllocationBudgets(t *testing.T) {
+	if raceEnabled {
+		t.Skip("the race detector changes allocation counts")
+	}
+	budgets := allocBudgets{}
+	if !*updateAllocBudgets {
+		data, err := os.ReadFile(allocBudgetsFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(data, &budgets); err != nil {
+			t.Fatalf("decode %s: %v", allocBudgetsFile, err)
+		}
+	}
+
+	measured := allocBudgets{}
+	for _, m := range contextMethods {
+		measured[m.name] = make(map[string]int)
+		for _, name := range conformanceFrameworks {
+			var allocs float64
+			var callErr error
+			inContext(t, name, func(ctx httpx.Context) {
+				allocs = testing.AllocsPerRun(100, func() {
+					if err := m.call(ctx); err != nil {
+						callErr = err
+					}
+				})
+			})
+			if callErr != nil {
+				t.Fatalf("%s on %s: %v", m.name, name, callErr)
+			}
+			measured[m.name][name] = int(allocs)
+			if *updateAllocBudgets {
+				continue
+			}
+			budget, ok := budgets[m.name][name]
+			if !ok {
+				t.Errorf("%s on %s: no budget in %s", m.name, name, allocBudgetsFile)
+				continue
+			}
+			if int(allocs) > budget {
+				t.Errorf("%s on %s: %d allocations per call, budget %d", m.name, name, int(allocs), budget)
+			}
+		}
+	}
+
+	if *updateAllocBudgets {
+		data, err := json.MarshalIndent(measured, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(allocBudgetsFile, append(data, '\n'), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		t.Logf("recorded %s", allocBudgetsFile)
+	}
+}
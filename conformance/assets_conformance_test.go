@@ -0,0 +1,62 @@
+package conformance
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-sphere/httpx"
+)
+
+func TestAssetsConformance(t *testing.T) {
+	files := fstest.MapFS{
+		"app.js":       {Data: []byte("console.log(1)")},
+		"css/site.css": {Data: []byte("body{}")},
+	}
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			assets, err := httpx.NewAssets(files, "/static")
+			if err != nil {
+				t.Fatal(err)
+			}
+			engine := newEphemeralEngine(t, name)
+			assets.Register(engine.Group("/web"))
+			assets.Register(engine.Group("/mirror"))
+			do := doEngineTest(engine)
+
+			css := assets.URL("css/site.css")
+			if !strings.HasPrefix(css, "/web/static/css/site.") {
+				t.Fatalf("URL = %q", css)
+			}
+			got := do(t, httptest.NewRequest(http.MethodGet, css, nil))
+			if got.Status != http.StatusOK || got.Body != "body{}" || !strings.HasPrefix(got.Headers.Get("Content-Type"), "text/css") ||
+				got.Headers.Get("Cache-Control") != "public, max-age=31536000, immutable" {
+				t.Fatalf("GET %s = %d %q, headers %v", css, got.Status, got.Body, got.Headers)
+			}
+			etag := got.Headers.Get("ETag")
+			mirrored := strings.Replace(css, "/web/", "/mirror/", 1)
+			if got := do(t, httptest.NewRequest(http.MethodGet, mirrored, nil)); got.Status != http.StatusOK || got.Body != "body{}" {
+				t.Fatalf("GET %s = %d %q", mirrored, got.Status, got.Body)
+			}
+
+			got = do(t, httptest.NewRequest(http.MethodGet, "/web/static/app.js", nil))
+			if got.Status != http.StatusOK || got.Body != "console.log(1)" || got.Headers.Get("Cache-Control") != "no-cache" {
+				t.Fatalf("GET unhashed = %d %q, headers %v", got.Status, got.Body, got.Headers)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, css, nil)
+			req.Header.Set("If-None-Match", etag)
+			if got := do(t, req); etag == "" || got.Status != http.StatusNotModified {
+				t.Fatalf("revalidation with %q = %d", etag, got.Status)
+			}
+			if got := do(t, httptest.NewRequest(http.MethodHead, css, nil)); got.Status != http.StatusOK || got.Body != "" {
+				t.Fatalf("HEAD = %d %q", got.Status, got.Body)
+			}
+			if got := do(t, httptest.NewRequest(http.MethodGet, "/web/static/missing.js", nil)); got.Status == http.StatusOK {
+				t.Fatalf("missing asset = %d", got.Status)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package conformance
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/go-sphere/httpx"
+	"github.com/go-sphere/httpx/echox"
+	"github.com/go-sphere/httpx/fiberx"
+	"github.com/go-sphere/httpx/ginx"
+	"github.com/go-sphere/httpx/hertzx"
+	"github.com/go-sphere/httpx/lambdax"
+)
+
+func TestBasePathConformance(t *testing.T) {
+	const basePath = "/service-a"
+	engines := map[string]func() httpx.Engine{
+		"lambdax": func() httpx.Engine { return lambdax.New(lambdax.WithBasePath(basePath)) },
+	}
+	for _, name := range conformanceFrameworks {
+		engines[name] = func() httpx.Engine {
+			return newEphemeralEngine(t, name,
+				ginx.WithBasePath(basePath),
+				fiberx.WithBasePath(basePath),
+				echox.WithBasePath(basePath),
+				hertzx.WithBasePath(basePath),
+			)
+		}
+	}
+	for name, newEngine := range engines {
+		t.Run(name, func(t *testing.T) {
+			engine := newEngine()
+			var routes []string
+			engine.OnRouteRegistered(func(info httpx.RouteInfo) {
+				routes = append(routes, info.Method+" "+info.Path)
+			})
+			api := engine.Group("/api")
+			api.GET("/items/:id", func(ctx httpx.Context) error {
+				return ctx.JSON(http.StatusOK, httpx.H{
+					"basePath": api.BasePath(),
+					"fullPath": ctx.FullPath(),
+					"id":       ctx.Param("id"),
+				})
+			})
+			root := engine.Group("")
+			root.GET("/ping", func(ctx httpx.Context) error {
+				return ctx.Text(http.StatusOK, root.BasePath())
+			})
+
+			want := []string{"GET /service-a/api/items/:id", "GET /service-a/ping"}
+			if !slices.Equal(routes, want) {
+				t.Fatalf("registered routes = %q, want %q", routes, want)
+			}
+
+			do := doEngineTest(engine)
+			got := do(t, httptest.NewRequest(http.MethodGet, "http://example.com/service-a/api/items/7", nil))
+			if got.Status != http.StatusOK {
+				t.Fatalf("status %d, body %s", got.Status, got.Body)
+			}
+			var body map[string]string
+			if err := json.Unmarshal([]byte(got.Body), &body); err != nil {
+				t.Fatalf("decode %s: %v", got.Body, err)
+			}
+			if body["basePath"] != "/service-a/api" || body["fullPath"] != "/service-a/api/items/:id" || body["id"] != "7" {
+				t.Fatalf("body = %v", body)
+			}
+			if got := do(t, httptest.NewRequest(http.MethodGet, "http://example.com/service-a/ping", nil)); got.Status != http.StatusOK || got.Body != basePath {
+				t.Fatalf("ping: status %d, body %q", got.Status, got.Body)
+			}
+			// Unmatched routes go through the error handler of some adapters,
+			// so only check the route is not served.
+			if got := do(t, httptest.NewRequest(http.MethodGet, "http://example.com/api/items/7", nil)); got.Status == http.StatusOK {
+				t.Fatalf("route without the base path was served: %s", got.Body)
+			}
+		})
+	}
+}
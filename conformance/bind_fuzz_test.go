@@ -0,0 +1,200 @@
+package conformance
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/go-sphere/httpx"
+)
+
+// The fuzz targets bind random payloads on every adapter and require the
+// outcome of ginx: the same bound value, or an error from all of them. Run
+// one with, for example:
+//
+//	go test -run '^$' -fuzz FuzzBindQuery -fuzztime 30s
+
+type fuzzJSONPayload struct {
+	Name   string            `json:"name"`
+	Age    int               `json:"age"`
+	Score  float64           `json:"score"`
+	Active bool              `json:"active"`
+	Tags   []string          `json:"tags"`
+	Meta   map[string]string `json:"meta"`
+	Nested *struct {
+		ID uint `json:"id"`
+	} `json:"nested"`
+}
+
+type fuzzValuesPayload struct {
+	Name   string   `query:"name" form:"name"`
+	Age    int      `query:"age" form:"age"`
+	Active bool     `query:"active" form:"active"`
+	Tags   []string `query:"tag" form:"tag"`
+}
+
+type fuzzHeaderPayload struct {
+	Name   string `header:"X-Name"`
+	Age    int    `header:"X-Age"`
+	Active bool   `header:"X-Active"`
+}
+
+// bindOutcome is what a fuzzed route responds with: the bound value, or only
+// the fact that binding failed, since error messages differ by framework.
+type bindOutcome struct {
+	Error bool `json:"error"`
+	Value any  `json:"value,omitempty"`
+}
+
+func bindRoute[T any](bind func(httpx.Context, any) error) httpx.Handler {
+	return func(ctx httpx.Context) error {
+		var in T
+		if err := bind(ctx, &in); err != nil {
+			return ctx.JSON(http.StatusOK, bindOutcome{Error: true})
+		}
+		return ctx.JSON(http.StatusOK, bindOutcome{Value: in})
+	}
+}
+
+func newBindFuzzHarnesses(f *testing.F, method, path string, h httpx.Handler) []frameworkHarness {
+	f.Helper()
+	harnesses := make([]frameworkHarness, 0, len(conformanceFrameworks))
+	for _, name := range conformanceFrameworks {
+		harness := newHarnessTB(f, name)
+		harness.Router.Handle(method, path, h)
+		harnesses = append(harnesses, harness)
+	}
+	return harnesses
+}
+
+func assertSameBindOutcome(t *testing.T, harnesses []frameworkHarness, newRequest func() *http.Request) {
+	t.Helper()
+	results := make(map[string]responseSnapshot, len(harnesses))
+	for _, h := range harnesses {
+		results[h.Name] = h.Do(t, newRequest())
+	}
+	assertMatchesGin(t, results)
+}
+
+func FuzzBindJSON(f *testing.F) {
+	for _, seed := range []string{
+		`{"name":"gopher","age":13,"score":1.5,"active":true,"tags":["a","b"],"meta":{"k":"v"},"nested":{"id":7}}`,
+		`{}`,
+		`null`,
+		`{"age":"13"}`,
+		`{"age":1.5}`,
+		`{"nested":{"id":-1}}`,
+		`{"tags":"a"}`,
+		`[1,2]`,
+		`{"name":`,
+		``,
+	} {
+		f.Add([]byte(seed))
+	}
+	harnesses := newBindFuzzHarnesses(f, http.MethodPost, "/bind", bindRoute[fuzzJSONPayload](httpx.Context.BindJSON))
+
+	f.Fuzz(func(t *testing.T, body []byte) {
+		if !utf8.Valid(body) {
+			t.Skip("JSON decoders differ on invalid UTF-8")
+		}
+		if hasTrailingData(body) {
+			t.Skip("streaming JSON decoders ignore data after the first value")
+		}
+		assertSameBindOutcome(t, harnesses, func() *http.Request {
+			req := httptest.NewRequest(http.MethodPost, "http://example.com/bind", strings.NewReader(string(body)))
+			req.Header.Set("Content-Type", "application/json")
+			return req
+		})
+	})
+}
+
+// hasTrailingData reports whether body holds a JSON value followed by more
+// than whitespace.
+func hasTrailingData(body []byte) bool {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	var v json.RawMessage
+	if err := dec.Decode(&v); err != nil {
+		return false
+	}
+	return len(bytes.TrimSpace(body[dec.InputOffset():])) > 0
+}
+
+func addValuesSeeds(f *testing.F) {
+	f.Add("gopher", "13", "true", "a")
+	f.Add("", "", "", "")
+	f.Add("go pher&x=1", "-7", "false", "a,b")
+	f.Add("gopher", "abc", "true", "a")
+	f.Add("gopher", "13", "maybe", "a")
+}
+
+func fuzzValues(name, age, active, tag string) url.Values {
+	return url.Values{"name": {name}, "age": {age}, "active": {active}, "tag": {tag, tag}}
+}
+
+func FuzzBindQuery(f *testing.F) {
+	addValuesSeeds(f)
+	harnesses := newBindFuzzHarnesses(f, http.MethodGet, "/bind", bindRoute[fuzzValuesPayload](httpx.Context.BindQuery))
+
+	f.Fuzz(func(t *testing.T, name, age, active, tag string) {
+		query := fuzzValues(name, age, active, tag).Encode()
+		assertSameBindOutcome(t, harnesses, func() *http.Request {
+			return httptest.NewRequest(http.MethodGet, "http://example.com/bind?"+query, nil)
+		})
+	})
+}
+
+func FuzzBindForm(f *testing.F) {
+	addValuesSeeds(f)
+	harnesses := newBindFuzzHarnesses(f, http.MethodPost, "/bind", bindRoute[fuzzValuesPayload](httpx.Context.BindForm))
+
+	f.Fuzz(func(t *testing.T, name, age, active, tag string) {
+		form := fuzzValues(name, age, active, tag).Encode()
+		assertSameBindOutcome(t, harnesses, func() *http.Request {
+			req := httptest.NewRequest(http.MethodPost, "http://example.com/bind", strings.NewReader(form))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			return req
+		})
+	})
+}
+
+func FuzzBindHeader(f *testing.F) {
+	f.Add("gopher", "13", "true")
+	f.Add("", "", "")
+	f.Add("go pher", "-7", "false")
+	f.Add("gopher", "1e3", "1")
+	harnesses := newBindFuzzHarnesses(f, http.MethodGet, "/bind", bindRoute[fuzzHeaderPayload](httpx.Context.BindHeader))
+
+	f.Fuzz(func(t *testing.T, name, age, active string) {
+		for _, v := range []string{name, age, active} {
+			if !wireHeaderValue(v) {
+				t.Skip("value does not survive the wire unchanged")
+			}
+		}
+		assertSameBindOutcome(t, harnesses, func() *http.Request {
+			req := httptest.NewRequest(http.MethodGet, "http://example.com/bind", nil)
+			req.Header.Set("X-Name", name)
+			req.Header.Set("X-Age", age)
+			req.Header.Set("X-Active", active)
+			return req
+		})
+	})
+}
+
+// wireHeaderValue reports whether v is sent unchanged as a header value:
+// printable ASCII without surrounding whitespace, which servers trim.
+func wireHeaderValue(v string) bool {
+	if strings.Trim(v, " \t") != v {
+		return false
+	}
+	for i := 0; i < len(v); i++ {
+		if v[i] < 0x20 || v[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,175 @@
+package conformance
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-sphere/httpx"
+)
+
+func TestReusableBodyConformance(t *testing.T) {
+	// readBody reads the whole body in middleware, as request loggers and
+	// signature checks do, before the handler binds it.
+	readBody := func(ctx httpx.Context) error {
+		raw, err := ctx.BodyRaw()
+		if err != nil {
+			return err
+		}
+		streamed, err := io.ReadAll(ctx.BodyReader())
+		if err != nil {
+			return err
+		}
+		ctx.Set("raw", string(raw))
+		ctx.Set("streamed", string(streamed))
+		return ctx.Next()
+	}
+	seen := func(ctx httpx.Context) (any, any) {
+		raw, _ := ctx.Get("raw")
+		streamed, _ := ctx.Get("streamed")
+		return raw, streamed
+	}
+
+	t.Run("JSON", func(t *testing.T) {
+		const payload = `{"name":"gopher","age":7}`
+		results := runAcrossFrameworks(t, func(r httpx.Router) {
+			r.Use(readBody)
+			r.POST("/json", func(ctx httpx.Context) error {
+				var dst struct {
+					Name string `json:"name"`
+					Age  int    `json:"age"`
+				}
+				if err := ctx.BindJSON(&dst); err != nil {
+					return err
+				}
+				after, err := ctx.BodyRaw()
+				if err != nil {
+					return err
+				}
+				raw, streamed := seen(ctx)
+				return ctx.JSON(http.StatusOK, map[string]any{
+					"name":     dst.Name,
+					"age":      dst.Age,
+					"raw":      raw,
+					"streamed": streamed,
+					"after":    string(after),
+				})
+			})
+		}, func() *http.Request {
+			req := httptest.NewRequest(http.MethodPost, "http://example.com/json", strings.NewReader(payload))
+			req.Header.Set("Content-Type", "application/json")
+			return req
+		})
+		assertMatchesGin(t, results)
+		for _, field := range []string{"raw", "streamed", "after"} {
+			assertJSONField(t, results, field, payload)
+		}
+		assertJSONField(t, results, "name", "gopher")
+	})
+
+	t.Run("Form", func(t *testing.T) {
+		const payload = "name=gopher&tag=a&tag=b"
+		results := runAcrossFrameworks(t, func(r httpx.Router) {
+			r.Use(readBody)
+			r.POST("/form", func(ctx httpx.Context) error {
+				var dst struct {
+					Name string   `form:"name"`
+					Tags []string `form:"tag"`
+				}
+				if err := ctx.BindForm(&dst); err != nil {
+					return err
+				}
+				after, err := ctx.BodyRaw()
+				if err != nil {
+					return err
+				}
+				raw, streamed := seen(ctx)
+				return ctx.JSON(http.StatusOK, map[string]any{
+					"name":     dst.Name,
+					"tags":     dst.Tags,
+					"value":    ctx.FormValue("name"),
+					"raw":      raw,
+					"streamed": streamed,
+					"after":    string(after),
+				})
+			})
+		}, func() *http.Request {
+			req := httptest.NewRequest(http.MethodPost, "http://example.com/form", strings.NewReader(payload))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			return req
+		})
+		assertMatchesGin(t, results)
+		for _, field := range []string{"raw", "streamed", "after"} {
+			assertJSONField(t, results, field, payload)
+		}
+		assertJSONField(t, results, "value", "gopher")
+	})
+}
+
+func TestKeptBodyRawConformance(t *testing.T) {
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			h := newHarness(t, name)
+			var kept [][]byte
+			h.Router.POST("/keep", func(ctx httpx.Context) error {
+				raw, err := ctx.BodyRaw()
+				if err != nil {
+					return err
+				}
+				kept = append(kept, raw)
+				return ctx.NoContent(http.StatusNoContent)
+			})
+
+			payloads := []string{"first-body", "other-body"}
+			for _, payload := range payloads {
+				resp := h.Do(t, httptest.NewRequest(http.MethodPost, "http://example.com/keep", strings.NewReader(payload)))
+				if resp.Status != http.StatusNoContent {
+					t.Fatalf("status = %d, body = %s", resp.Status, resp.Body)
+				}
+			}
+			// BodyRaw must not alias a buffer the framework reuses for the
+			// next request.
+			for i, payload := range payloads {
+				if string(kept[i]) != payload {
+					t.Fatalf("body %d kept from BodyRaw = %q, want %q", i, kept[i], payload)
+				}
+			}
+		})
+	}
+}
+
+func TestUnsafeBodyConformance(t *testing.T) {
+	const payload = "zero-copy"
+	supported := map[string]bool{"fiberx": true, "hertzx": true}
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			h := newHarness(t, name)
+			h.Router.POST("/unsafe", func(ctx httpx.Context) error {
+				_, ok := httpx.AsUnsafeBody(ctx)
+				body, err := httpx.BodyRawUnsafe(ctx)
+				if err != nil {
+					return err
+				}
+				return ctx.JSON(http.StatusOK, map[string]any{"supported": ok, "body": string(body)})
+			})
+
+			resp := h.Do(t, httptest.NewRequest(http.MethodPost, "http://example.com/unsafe", strings.NewReader(payload)))
+			if resp.Status != http.StatusOK {
+				t.Fatalf("status = %d, body = %s", resp.Status, resp.Body)
+			}
+			var got map[string]any
+			if err := json.Unmarshal([]byte(resp.Body), &got); err != nil {
+				t.Fatalf("decode %s: %v", resp.Body, err)
+			}
+			if got["supported"] != supported[name] {
+				t.Fatalf("AsUnsafeBody ok = %v, want %v", got["supported"], supported[name])
+			}
+			if got["body"] != payload {
+				t.Fatalf("body = %v, want %q", got["body"], payload)
+			}
+		})
+	}
+}
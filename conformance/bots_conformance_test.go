@@ -0,0 +1,52 @@
+package conformance
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-sphere/httpx"
+	"github.com/go-sphere/httpx/middleware"
+)
+
+func TestBotsConformance(t *testing.T) {
+	register := func(r httpx.Router) {
+		g := r.Group("", middleware.BlockBots([]string{"GPTBot", `^python-requests/`}))
+		g.GET("/page", func(ctx httpx.Context) error {
+			return ctx.Text(http.StatusOK, "page")
+		})
+		httpx.ServeRobotsTxt(r, httpx.RobotsPolicy{Rules: []httpx.RobotsRule{{Disallow: []string{"/admin/"}}}})
+		httpx.ServeSecurityTxt(r, httpx.SecurityTxt{
+			Contact: []string{"mailto:security@example.com"},
+			Expires: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+		})
+	}
+	tests := []struct {
+		path, userAgent string
+		wantStatus      int
+		wantBody        string
+	}{
+		{"/page", "Mozilla/5.0", http.StatusOK, "page"},
+		{"/page", "", http.StatusOK, "page"},
+		{"/page", "Mozilla/5.0 (compatible; gptbot/1.0)", http.StatusInternalServerError, ""},
+		{"/page", "python-requests/2.31", http.StatusInternalServerError, ""},
+		{"/page", "my python-requests/2.31", http.StatusOK, "page"},
+		{"/robots.txt", "", http.StatusOK, "User-agent: *\nDisallow: /admin/\n"},
+		{"/.well-known/security.txt", "", http.StatusOK, "Contact: mailto:security@example.com\nExpires: 2030-01-01T00:00:00Z\n"},
+	}
+	for _, tc := range tests {
+		results := runAcrossFrameworks(t, register, func() *http.Request {
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			req.Header.Set("User-Agent", tc.userAgent)
+			return req
+		})
+		got := results["ginx"]
+		if got.Status != tc.wantStatus || tc.wantBody != "" && got.Body != tc.wantBody {
+			t.Fatalf("%s (%q) = %d %q, want %d %q", tc.path, tc.userAgent, got.Status, got.Body, tc.wantStatus, tc.wantBody)
+		}
+		if tc.wantBody != "" && got.Headers.Get("Content-Type") != "text/plain; charset=utf-8" {
+			t.Fatalf("%s: Content-Type %q", tc.path, got.Headers.Get("Content-Type"))
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package conformance
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/go-sphere/httpx"
+)
+
+func get(target string) func() *http.Request {
+	return func() *http.Request {
+		return httptest.NewRequest(http.MethodGet, "http://example.com"+target, nil)
+	}
+}
+
+// Cases returns the built-in cases, covering routing, request access,
+// binding, responses and error handling.
+func Cases() []Case {
+	return []Case{
+		{
+			Name: "JSON",
+			Register: func(r httpx.Router) {
+				r.GET("/json", func(ctx httpx.Context) error {
+					return ctx.JSON(http.StatusOK, httpx.H{"name": "gopher", "tags": []string{"a", "b"}})
+				})
+			},
+			Request: get("/json"),
+		},
+		{
+			Name: "Text",
+			Register: func(r httpx.Router) {
+				r.GET("/text", func(ctx httpx.Context) error {
+					return ctx.Text(http.StatusAccepted, "hello")
+				})
+			},
+			Request: get("/text"),
+		},
+		{
+			Name: "NoContent",
+			Register: func(r httpx.Router) {
+				r.DELETE("/items/:id", func(ctx httpx.Context) error {
+					return ctx.NoContent(http.StatusNoContent)
+				})
+			},
+			Request: func() *http.Request {
+				return httptest.NewRequest(http.MethodDelete, "http://example.com/items/1", nil)
+			},
+		},
+		{
+			Name: "PathParams",
+			Register: func(r httpx.Router) {
+				r.GET("/users/:id/posts/:post", func(ctx httpx.Context) error {
+					return ctx.JSON(http.StatusOK, httpx.H{
+						"id":       ctx.Param("id"),
+						"post":     ctx.Param("post"),
+						"fullPath": ctx.FullPath(),
+					})
+				})
+			},
+			Request: get("/users/42/posts/7"),
+		},
+		{
+			Name: "Group",
+			Register: func(r httpx.Router) {
+				v1 := r.Group("/v1")
+				v1.GET("/ping", func(ctx httpx.Context) error {
+					return ctx.JSON(http.StatusOK, httpx.H{"path": ctx.Path(), "fullPath": ctx.FullPath()})
+				})
+			},
+			Request: get("/v1/ping"),
+		},
+		{
+			Name: "Query",
+			Register: func(r httpx.Router) {
+				r.GET("/search", func(ctx httpx.Context) error {
+					return ctx.JSON(http.StatusOK, httpx.H{
+						"q":       ctx.Query("q"),
+						"missing": ctx.Query("missing"),
+						"all":     ctx.Queries()["tag"],
+					})
+				})
+			},
+			Request: get("/search?q=go+lang&tag=a&tag=b"),
+		},
+		{
+			Name: "Headers",
+			Register: func(r httpx.Router) {
+				r.GET("/headers", func(ctx httpx.Context) error {
+					ctx.SetHeader("X-Trace", "trace-"+ctx.Header("X-Request"))
+					return ctx.JSON(http.StatusOK, httpx.H{"request": ctx.Header("x-request")})
+				})
+			},
+			Request: func() *http.Request {
+				req := get("/headers")()
+				req.Header.Set("X-Request", "abc")
+				return req
+			},
+		},
+		{
+			Name: "Cookies",
+			Register: func(r httpx.Router) {
+				r.GET("/cookies", func(ctx httpx.Context) error {
+					session, err := ctx.Cookie("session")
+					if err != nil {
+						return err
+					}
+					ctx.SetCookie(&http.Cookie{Name: "seen", Value: session, Path: "/"})
+					return ctx.JSON(http.StatusOK, httpx.H{"session": session})
+				})
+			},
+			Request: func() *http.Request {
+				req := get("/cookies")()
+				req.AddCookie(&http.Cookie{Name: "session", Value: "s1"})
+				return req
+			},
+		},
+		{
+			Name: "BindJSON",
+			Register: func(r httpx.Router) {
+				r.POST("/users", func(ctx httpx.Context) error {
+					var in struct {
+						Name string `json:"name"`
+						Age  int    `json:"age"`
+					}
+					if err := ctx.BindJSON(&in); err != nil {
+						return err
+					}
+					return ctx.JSON(http.StatusCreated, in)
+				})
+			},
+			Request: func() *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "http://example.com/users", strings.NewReader(`{"name":"gopher","age":13}`))
+				req.Header.Set("Content-Type", "application/json")
+				return req
+			},
+		},
+		{
+			Name: "BindQuery",
+			Register: func(r httpx.Router) {
+				r.GET("/page", func(ctx httpx.Context) error {
+					var in struct {
+						Page  int      `query:"page"`
+						Sort  string   `query:"sort" default:"name"`
+						Items []string `query:"item"`
+					}
+					if err := ctx.BindQuery(&in); err != nil {
+						return err
+					}
+					return ctx.JSON(http.StatusOK, in)
+				})
+			},
+			Request: get("/page?page=2&item=a&item=b"),
+		},
+		{
+			Name: "Redirect",
+			Register: func(r httpx.Router) {
+				r.GET("/old", func(ctx httpx.Context) error {
+					return ctx.Redirect(http.StatusFound, "/new")
+				})
+			},
+			Request: get("/old"),
+		},
+		{
+			Name: "Error",
+			Register: func(r httpx.Router) {
+				r.GET("/error", func(ctx httpx.Context) error {
+					return errors.New("boom")
+				})
+			},
+			Request: get("/error"),
+		},
+		{
+			Name: "MiddlewareState",
+			Register: func(r httpx.Router) {
+				r.Use(func(ctx httpx.Context) error {
+					ctx.Set("user", "gopher")
+					return ctx.Next()
+				})
+				r.GET("/state", func(ctx httpx.Context) error {
+					user, _ := ctx.Get("user")
+					return ctx.JSON(http.StatusOK, httpx.H{"user": user})
+				})
+			},
+			Request: get("/state"),
+		},
+	}
+}
@@ -0,0 +1,158 @@
+package conformance
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/go-sphere/httpx"
+	"github.com/go-sphere/httpx/lambdax"
+	"github.com/go-sphere/httpx/middleware"
+)
+
+func chainAuth(ctx httpx.Context) error {
+	return ctx.Next()
+}
+
+func chainDeny(ctx httpx.Context) error {
+	return httpx.NewWithStatus(http.StatusForbidden, "denied")
+}
+
+func TestChainInfoConformance(t *testing.T) {
+	engines := map[string]func(t *testing.T) httpx.Engine{
+		"lambdax": func(*testing.T) httpx.Engine { return lambdax.New() },
+	}
+	for _, name := range conformanceFrameworks {
+		engines[name] = func(t *testing.T) httpx.Engine { return newEphemeralEngine(t, name) }
+	}
+	for name, newEngine := range engines {
+		t.Run(name, func(t *testing.T) {
+			engine := newEngine(t)
+			var events []string
+			engine.Use(func(ctx httpx.Context) error {
+				if ctx.Header("X-Trace") == "" {
+					return ctx.Next()
+				}
+				httpx.TraceChain(ctx, func(ev httpx.ChainEvent) {
+					kind := "enter"
+					if ev.Exit {
+						kind = "exit"
+					}
+					events = append(events, kind+" "+shortName(ev.Name))
+				})
+				return ctx.Next()
+			})
+			var seen []httpx.Chain
+			record := func(ctx httpx.Context) error {
+				chain, ok := httpx.ChainInfo(ctx)
+				if ok {
+					seen = append(seen, chain)
+				}
+				return ctx.Next()
+			}
+			r := engine.Group("/api", record, chainAuth)
+			r.GET("/items", func(ctx httpx.Context) error {
+				chain, ok := httpx.ChainInfo(ctx)
+				if ok {
+					seen = append(seen, chain)
+				}
+				return ctx.Text(http.StatusOK, "ok")
+			})
+			r.Group("/admin", chainDeny).GET("/items", func(ctx httpx.Context) error {
+				return ctx.Text(http.StatusOK, "ok")
+			})
+
+			do := doEngineTest(engine)
+			got := do(t, httptest.NewRequest(http.MethodGet, "http://example.com/api/items", nil))
+			if got.Status != http.StatusOK || len(seen) != 0 {
+				t.Fatalf("untraced request: status %d, chains %v", got.Status, seen)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "http://example.com/api/items", nil)
+			req.Header.Set("X-Trace", "1")
+			if got := do(t, req); got.Status != http.StatusOK {
+				t.Fatalf("traced request: status %d", got.Status)
+			}
+			if len(seen) != 2 {
+				t.Fatalf("chains = %v", seen)
+			}
+			names := make([]string, len(seen[0].Names))
+			for i, n := range seen[0].Names {
+				names[i] = shortName(n)
+			}
+			if want := []string{"func", "chainAuth", "func"}; !slices.Equal(names, want) {
+				t.Fatalf("names = %v, want %v", names, want)
+			}
+			if seen[0].Index != 0 || seen[1].Index != 2 {
+				t.Fatalf("indexes = %d, %d, want 0, 2", seen[0].Index, seen[1].Index)
+			}
+			want := []string{"enter func", "enter chainAuth", "enter func", "exit func", "exit chainAuth", "exit func"}
+			if !slices.Equal(events, want) {
+				t.Fatalf("events = %v, want %v", events, want)
+			}
+
+			events = nil
+			req = httptest.NewRequest(http.MethodGet, "http://example.com/api/admin/items", nil)
+			req.Header.Set("X-Trace", "1")
+			if got := do(t, req); got.Status == http.StatusOK {
+				t.Fatalf("denied request: status %d", got.Status)
+			}
+			want = []string{"enter func", "enter chainAuth", "enter chainDeny", "exit chainDeny", "exit chainAuth", "exit func"}
+			if !slices.Equal(events, want) {
+				t.Fatalf("events = %v, want %v", events, want)
+			}
+		})
+	}
+}
+
+func TestDebugChainConformance(t *testing.T) {
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+			engine := newEphemeralEngine(t, name)
+			engine.Use(middleware.DebugChain(logger))
+			engine.Group("/api", chainDeny).GET("/items/:id", func(ctx httpx.Context) error {
+				return ctx.NoContent(http.StatusNoContent)
+			})
+			doEngineTest(engine)(t, httptest.NewRequest(http.MethodGet, "http://example.com/api/items/1", nil))
+
+			var records []map[string]any
+			dec := json.NewDecoder(&buf)
+			for dec.More() {
+				var record map[string]any
+				if err := dec.Decode(&record); err != nil {
+					t.Fatalf("decode log record: %v", err)
+				}
+				records = append(records, record)
+			}
+			if len(records) != 2 {
+				t.Fatalf("records = %v", records)
+			}
+			for i, msg := range []string{"chain enter", "chain exit"} {
+				rec := records[i]
+				if rec["msg"] != msg || rec["route"] != "/api/items/:id" || shortName(rec["layer"].(string)) != "chainDeny" || rec["index"] != float64(0) {
+					t.Fatalf("record %d = %v", i, rec)
+				}
+			}
+			if records[1]["error"] != "denied" {
+				t.Fatalf("exit record error = %v", records[1]["error"])
+			}
+		})
+	}
+}
+
+// shortName trims the package of a layer name and the suffix of anonymous
+// functions, so "conformance.TestX.func1.2" reads "func".
+func shortName(name string) string {
+	name = name[strings.LastIndexByte(name, '.')+1:]
+	if strings.HasPrefix(name, "func") || strings.Trim(name, "0123456789") == "" {
+		return "func"
+	}
+	return name
+}
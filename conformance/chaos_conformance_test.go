@@ -0,0 +1,99 @@
+package conformance
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-sphere/httpx"
+	"github.com/go-sphere/httpx/middleware"
+)
+
+func chaosMiddleware() httpx.Middleware {
+	return middleware.Chaos(middleware.ChaosConfig{
+		Faults: map[string]middleware.ChaosFault{
+			"slow":  {LatencyRate: 1, Latency: 50 * time.Millisecond},
+			"flaky": {ErrorRate: 0.5, ErrorStatus: http.StatusBadGateway},
+			"drop":  {AbortRate: 1},
+		},
+		Rand: func() float64 { return 0.25 },
+	})
+}
+
+func TestChaosConformance(t *testing.T) {
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			engine := newEphemeralEngine(t, name)
+			var handlerErr error
+			engine.Use(func(ctx httpx.Context) error {
+				handlerErr = ctx.Next()
+				return handlerErr
+			})
+			r := engine.Group("", chaosMiddleware())
+			ok := func(ctx httpx.Context) error {
+				return ctx.Text(http.StatusOK, "ok")
+			}
+			r.GET("/plain", ok)
+			r.GET("/slow", ok).Meta("chaos", "slow")
+			r.GET("/flaky", ok).Meta("chaos", "flaky")
+			r.GET("/unknown", ok).Meta("chaos", "unknown")
+
+			do := doEngineTest(engine)
+			for _, path := range []string{"/plain", "/unknown"} {
+				if got := do(t, httptest.NewRequest(http.MethodGet, path, nil)); got.Status != http.StatusOK || handlerErr != nil {
+					t.Fatalf("%s: status %d, error %v", path, got.Status, handlerErr)
+				}
+			}
+			start := time.Now()
+			if got := do(t, httptest.NewRequest(http.MethodGet, "/slow", nil)); got.Status != http.StatusOK {
+				t.Fatalf("/slow: status %d", got.Status)
+			}
+			if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+				t.Fatalf("/slow took %v, want injected latency", elapsed)
+			}
+			if got := do(t, httptest.NewRequest(http.MethodGet, "/flaky", nil)); got.Status == http.StatusOK || !errors.Is(handlerErr, middleware.ErrChaosFault) {
+				t.Fatalf("/flaky: status %d, error %v", got.Status, handlerErr)
+			}
+			var status httpx.StatusError
+			if !errors.As(handlerErr, &status) || status.GetStatus() != http.StatusBadGateway {
+				t.Fatalf("/flaky: error %v, want status %d", handlerErr, http.StatusBadGateway)
+			}
+		})
+	}
+}
+
+func TestChaosAbortConformance(t *testing.T) {
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			engine := newEphemeralEngine(t, name)
+			engine.Group("", chaosMiddleware()).GET("/drop", func(ctx httpx.Context) error {
+				return ctx.Text(http.StatusOK, "ok")
+			}).Meta("chaos", "drop")
+			startErrCh := make(chan error, 1)
+			go func() {
+				startErrCh <- engine.Start()
+			}()
+			addr := waitBoundAddr(t, engine, startErrCh).String()
+			t.Cleanup(func() { _ = engine.Stop(t.Context()) })
+
+			resp, err := http.Get("http://" + addr + "/drop")
+			if name == "ginx" || name == "echox" {
+				if err == nil {
+					_ = resp.Body.Close()
+					t.Fatalf("got status %d, want the connection closed", resp.StatusCode)
+				}
+				return
+			}
+			// Adapters without httpx.Hijacker fail the request instead.
+			if err != nil {
+				t.Fatal(err)
+			}
+			_ = resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				t.Fatal("aborted request succeeded")
+			}
+		})
+	}
+}
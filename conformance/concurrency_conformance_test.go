@@ -0,0 +1,202 @@
+package conformance
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-sphere/httpx"
+)
+
+// Run with -race to have the detector check the adapters under load.
+var concurrentRequests = flag.Int("concurrency", 32, "number of simultaneous requests sent to each adapter by the concurrency conformance tests")
+
+type concurrentResult struct {
+	ID     string `json:"id"`
+	Param  string `json:"param"`
+	Header string `json:"header"`
+	Body   string `json:"body"`
+	State  string `json:"state"`
+	Typed  string `json:"typed"`
+	Query  string `json:"query"`
+}
+
+// barrier holds requests until n of them have arrived, so that their
+// handlers run at the same time, and reports whether they all did.
+type barrier struct {
+	mu      sync.Mutex
+	n       int
+	arrived int
+	all     chan struct{}
+}
+
+func newBarrier(n int) *barrier {
+	return &barrier{n: n, all: make(chan struct{})}
+}
+
+func (b *barrier) wait(timeout time.Duration) bool {
+	b.mu.Lock()
+	b.arrived++
+	if b.arrived == b.n {
+		close(b.all)
+	}
+	b.mu.Unlock()
+
+	select {
+	case <-b.all:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// registerConcurrent registers a route that stores request data in the
+// context state, waits for the other requests at the barrier, and then
+// echoes what the request observes of itself.
+func registerConcurrent(r httpx.Router, b *barrier) {
+	idKey := httpx.NewKey[string]("concurrent.id")
+	r.Use(func(ctx httpx.Context) error {
+		id := ctx.Header("X-Request")
+		ctx.Set("id", id)
+		httpx.SetTyped(ctx, idKey, id)
+		ctx.SetHeader("X-Trace", id)
+		return ctx.Next()
+	})
+	r.POST("/concurrent/:id", func(ctx httpx.Context) error {
+		if !b.wait(5 * time.Second) {
+			return errors.New("requests were not served concurrently")
+		}
+		body, err := ctx.BodyRaw()
+		if err != nil {
+			return err
+		}
+		state, _ := httpx.GetAs[string](ctx, "id")
+		typed, _ := idKey.Get(ctx)
+		return ctx.JSON(http.StatusOK, concurrentResult{
+			ID:     ctx.Header("X-Request"),
+			Param:  ctx.Param("id"),
+			Header: ctx.Header("X-Request"),
+			Body:   string(body),
+			State:  state,
+			Typed:  typed,
+			Query:  ctx.Query("n"),
+		})
+	})
+}
+
+func newConcurrentRequest(baseURL string, i int) (*http.Request, error) {
+	id := fmt.Sprintf("req-%d", i)
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/concurrent/%s?n=%d", baseURL, id, i), strings.NewReader(strings.Repeat(id+";", 64)))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Request", id)
+	req.Header.Set("Content-Type", "text/plain")
+	return req, nil
+}
+
+func checkConcurrentResponse(resp *http.Response, i int) error {
+	got, err := ReadResponse(resp)
+	if err != nil {
+		return err
+	}
+	id := fmt.Sprintf("req-%d", i)
+	if got.Status != http.StatusOK {
+		return fmt.Errorf("%s: status = %d, body = %s", id, got.Status, got.Body)
+	}
+	if trace := got.Headers.Get("X-Trace"); trace != id {
+		return fmt.Errorf("%s: X-Trace = %q", id, trace)
+	}
+	var result concurrentResult
+	if err := json.Unmarshal([]byte(got.Body), &result); err != nil {
+		return fmt.Errorf("%s: decode body %q: %v", id, got.Body, err)
+	}
+	want := concurrentResult{
+		ID:     id,
+		Param:  id,
+		Header: id,
+		Body:   strings.Repeat(id+";", 64),
+		State:  id,
+		Typed:  id,
+		Query:  fmt.Sprint(i),
+	}
+	if result != want {
+		return fmt.Errorf("%s: got %+v, want %+v", id, result, want)
+	}
+	return nil
+}
+
+// fireConcurrently sends n requests at once through do and checks that each
+// response belongs to its request.
+func fireConcurrently(t *testing.T, n int, do func(i int) (*http.Response, error)) {
+	t.Helper()
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := do(i)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			errs[i] = checkConcurrentResponse(resp, i)
+		}()
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConcurrentRequestsInProcessConformance(t *testing.T) {
+	n := *concurrentRequests
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			h := newHarness(t, name)
+			registerConcurrent(h.Router, newBarrier(n))
+			fireConcurrently(t, n, func(i int) (*http.Response, error) {
+				req, err := newConcurrentRequest("http://example.com", i)
+				if err != nil {
+					return nil, err
+				}
+				return h.Engine.Test(req)
+			})
+		})
+	}
+}
+
+func TestConcurrentRequestsNetworkConformance(t *testing.T) {
+	n := *concurrentRequests
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			b := newFrameworkHarnessTB(t, name, harnessOptions{mode: harnessModeNetwork, errorMode: harnessErrorDefault, silenceHertzLog: true})
+			b.harness.Router.GET("/__ready", func(ctx httpx.Context) error {
+				return ctx.NoContent(http.StatusNoContent)
+			})
+			registerConcurrent(b.harness.Router, newBarrier(n))
+			startNetworkHarness(t, b)
+			t.Cleanup(func() { _ = b.harness.Engine.Stop(t.Context()) })
+
+			client := &http.Client{
+				Timeout:   10 * time.Second,
+				Transport: &http.Transport{MaxIdleConnsPerHost: n},
+			}
+			t.Cleanup(client.CloseIdleConnections)
+			fireConcurrently(t, n, func(i int) (*http.Response, error) {
+				req, err := newConcurrentRequest(b.baseURL, i)
+				if err != nil {
+					return nil, err
+				}
+				return client.Do(req)
+			})
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package conformance
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-sphere/httpx"
+)
+
+func TestConditionalUpdateConformance(t *testing.T) {
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			engine := newEphemeralEngine(t, name)
+			version := "v1"
+			engine.Group("").PUT("/docs/1", func(ctx httpx.Context) error {
+				if err := httpx.CheckIfMatch(ctx, version); err != nil {
+					if !errors.Is(err, httpx.ErrPreconditionFailed) {
+						t.Errorf("CheckIfMatch() = %v", err)
+					}
+					return ctx.Text(http.StatusPreconditionFailed, "stale")
+				}
+				version = "v2"
+				httpx.SetEntityTag(ctx, version)
+				return ctx.NoContent(http.StatusNoContent)
+			})
+
+			do := doEngineTest(engine)
+			put := func(ifMatch string) responseSnapshot {
+				req := httptest.NewRequest(http.MethodPut, "/docs/1", strings.NewReader("{}"))
+				req.Header.Set("If-Match", ifMatch)
+				return do(t, req)
+			}
+			if got := put(`"v1"`); got.Status != http.StatusNoContent || got.Headers.Get("ETag") != `"v2"` {
+				t.Fatalf("first update: status %d, ETag %q", got.Status, got.Headers.Get("ETag"))
+			}
+			if got := put(`"v1"`); got.Status != http.StatusPreconditionFailed {
+				t.Fatalf("stale update: status %d, want 412", got.Status)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package conformance
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-sphere/httpx"
+)
+
+func TestServeContentConformance(t *testing.T) {
+	const video = "0123456789abcdefghij"
+	modtime := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		method      string
+		header      map[string]string
+		wantStatus  int
+		wantBody    string
+		wantHeaders map[string]string
+	}{
+		{"full", http.MethodGet, nil, http.StatusOK, video, map[string]string{
+			"Content-Type": "video/mp4", "Accept-Ranges": "bytes", "Last-Modified": modtime.Format(http.TimeFormat)}},
+		{"range", http.MethodGet, map[string]string{"Range": "bytes=2-5"}, http.StatusPartialContent, "2345", map[string]string{
+			"Content-Range": "bytes 2-5/20", "Content-Length": "4"}},
+		{"suffix range", http.MethodGet, map[string]string{"Range": "bytes=-3"}, http.StatusPartialContent, "hij", nil},
+		{"if-range current", http.MethodGet, map[string]string{"Range": "bytes=0-0", "If-Range": modtime.Format(http.TimeFormat)}, http.StatusPartialContent, "0", nil},
+		{"if-range stale", http.MethodGet, map[string]string{"Range": "bytes=0-0", "If-Range": modtime.Add(-time.Hour).Format(http.TimeFormat)}, http.StatusOK, video, nil},
+		{"not modified", http.MethodGet, map[string]string{"If-Modified-Since": modtime.Format(http.TimeFormat)}, http.StatusNotModified, "", nil},
+		{"precondition", http.MethodGet, map[string]string{"If-Unmodified-Since": modtime.Add(-time.Hour).Format(http.TimeFormat)}, http.StatusPreconditionFailed, "", nil},
+		{"unsatisfiable", http.MethodGet, map[string]string{"Range": "bytes=50-"}, http.StatusRequestedRangeNotSatisfiable, "", map[string]string{
+			"Content-Range": "bytes */20"}},
+	}
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			engine := newEphemeralEngine(t, name)
+			engine.Group("").GET("/video.mp4", func(ctx httpx.Context) error {
+				return httpx.ServeContent(ctx, "video.mp4", modtime, strings.NewReader(video))
+			})
+			do := doEngineTest(engine)
+			for _, tc := range tests {
+				req := httptest.NewRequest(tc.method, "/video.mp4", nil)
+				for k, v := range tc.header {
+					req.Header.Set(k, v)
+				}
+				got := do(t, req)
+				if got.Status != tc.wantStatus || tc.wantBody != "" && got.Body != tc.wantBody {
+					t.Fatalf("%s: %d %q, want %d %q", tc.name, got.Status, got.Body, tc.wantStatus, tc.wantBody)
+				}
+				for k, v := range tc.wantHeaders {
+					if got.Headers.Get(k) != v {
+						t.Fatalf("%s: %s = %q, want %q", tc.name, k, got.Headers.Get(k), v)
+					}
+				}
+			}
+		})
+	}
+}
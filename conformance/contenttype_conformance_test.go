@@ -0,0 +1,74 @@
+package conformance
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-sphere/httpx"
+	"github.com/go-sphere/httpx/middleware"
+)
+
+func TestContentTypeConformance(t *testing.T) {
+	png := "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"
+	tests := []struct {
+		name, path, contentType, body string
+		want415                       bool
+		wantBody                      string
+	}{
+		{"json", "/enforced", "application/json; charset=utf-8", `{"name":"a"}`, false, "a"},
+		{"no body", "/enforced", "", "", false, ""},
+		{"not allowed", "/enforced", "text/plain", "name=a", true, ""},
+		{"missing type", "/enforced", "", `{"name":"a"}`, true, ""},
+		{"invalid type", "/enforced", "application/", `{"name":"a"}`, true, ""},
+		{"mislabeled unchecked", "/enforced", "application/json", png, false, ""},
+		{"sniffed json", "/sniffed", "application/json", ` {"name":"b"}`, false, "b"},
+		{"mislabeled json", "/sniffed", "application/json", png, true, ""},
+		{"image wildcard", "/sniffed", "image/png", png, false, ""},
+		{"mislabeled image", "/sniffed", "image/gif", png, true, ""},
+	}
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			engine := newEphemeralEngine(t, name)
+			var handlerErr error
+			engine.Use(func(ctx httpx.Context) error {
+				handlerErr = ctx.Next()
+				return handlerErr
+			})
+			handler := func(ctx httpx.Context) error {
+				var in struct {
+					Name string `json:"name"`
+				}
+				if strings.HasPrefix(ctx.Header("Content-Type"), "application/json") {
+					if err := ctx.BindJSON(&in); err != nil {
+						return err
+					}
+				}
+				return ctx.Text(http.StatusOK, in.Name)
+			}
+			engine.Group("", middleware.EnforceContentType("application/json")).POST("/enforced", handler)
+			engine.Group("", middleware.SniffContentType("application/json", "image/*")).POST("/sniffed", handler)
+
+			for _, tc := range tests {
+				req := httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.body))
+				if tc.contentType != "" {
+					req.Header.Set("Content-Type", tc.contentType)
+				}
+				got := doEngineTest(engine)(t, req)
+				if !tc.want415 {
+					// The binder, not the guard, rejects the unchecked body.
+					if errors.Is(handlerErr, middleware.ErrUnsupportedMediaType) || handlerErr == nil && got.Body != tc.wantBody {
+						t.Fatalf("%s: %d %q, error %v", tc.name, got.Status, got.Body, handlerErr)
+					}
+					continue
+				}
+				var status httpx.StatusError
+				if !errors.Is(handlerErr, middleware.ErrUnsupportedMediaType) || !errors.As(handlerErr, &status) || status.GetStatus() != http.StatusUnsupportedMediaType {
+					t.Fatalf("%s: error %v, want 415 wrapping ErrUnsupportedMediaType", tc.name, handlerErr)
+				}
+			}
+		})
+	}
+}
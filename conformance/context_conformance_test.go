@@ -5,16 +5,25 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"iter"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-sphere/httpx"
+	"github.com/go-sphere/httpx/echox"
+	"github.com/go-sphere/httpx/fiberx"
+	"github.com/go-sphere/httpx/ginx"
+	"github.com/go-sphere/httpx/hertzx"
 )
 
 func TestRequestInfoConformance(t *testing.T) {
@@ -48,6 +57,117 @@ func TestRequestInfoConformance(t *testing.T) {
 	assertMatchesGin(t, results)
 }
 
+func TestRequestIteratorsConformance(t *testing.T) {
+	collect := func(seq iter.Seq2[string, string]) map[string][]string {
+		out := make(map[string][]string)
+		for k, v := range seq {
+			out[k] = append(out[k], v)
+		}
+		return out
+	}
+	results := runAcrossFrameworks(t, func(r httpx.Router) {
+		r.GET("/iter", func(ctx httpx.Context) error {
+			queries := collect(ctx.AllQueries())
+			headers := collect(ctx.AllHeaders())
+			cookies := make(map[string]string)
+			for k, v := range ctx.AllCookies() {
+				cookies[k] = v
+			}
+			yielded := 0
+			for range ctx.AllHeaders() {
+				yielded++
+				break
+			}
+			return ctx.JSON(http.StatusOK, map[string]any{
+				"queries":        queries,
+				"headers":        headers["X-Multi"],
+				"cookies":        cookies,
+				"queriesMatch":   reflect.DeepEqual(queries, ctx.Queries()),
+				"headersMatch":   reflect.DeepEqual(headers, ctx.Headers()),
+				"cookiesMatch":   reflect.DeepEqual(cookies, ctx.Cookies()),
+				"yieldedOnBreak": yielded,
+			})
+		})
+	}, func() *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "http://example.com/iter?name=alice&name=bob&q=a+b", nil)
+		req.Header.Add("X-Multi", "1")
+		req.Header.Add("X-Multi", "2")
+		req.AddCookie(&http.Cookie{Name: "session", Value: "abc"})
+		req.AddCookie(&http.Cookie{Name: "theme", Value: "dark"})
+		return req
+	})
+
+	assertMatchesGin(t, results)
+	want := `{"cookies":{"session":"abc","theme":"dark"},"cookiesMatch":true,"headers":["1","2"],"headersMatch":true,"queries":{"name":["alice","bob"],"q":["a b"]},"queriesMatch":true,"yieldedOnBreak":1}`
+	if results["ginx"].Body != want {
+		t.Fatalf("body = %s, want %s", results["ginx"].Body, want)
+	}
+}
+
+func TestRequestURLConformance(t *testing.T) {
+	register := func(r httpx.Router) {
+		r.POST("/url/:id", func(ctx httpx.Context) error {
+			return ctx.JSON(200, map[string]any{
+				"scheme":        ctx.Scheme(),
+				"host":          ctx.Host(),
+				"proto":         ctx.Proto(),
+				"contentLength": ctx.ContentLength(),
+				"userAgent":     ctx.UserAgent(),
+				"referer":       ctx.Referer(),
+				"url":           ctx.URL().String(),
+				"abs":           httpx.AbsURL(ctx, "../users/7?tab=posts"),
+			})
+		})
+	}
+
+	t.Run("Direct", func(t *testing.T) {
+		results := runAcrossFrameworks(t, register, func() *http.Request {
+			req := httptest.NewRequest(http.MethodPost, "http://example.com/url/a%20b?q=1&q=2", strings.NewReader("hello"))
+			req.Header.Set("User-Agent", "conformance/1.0")
+			req.Header.Set("Referer", "http://example.com/from")
+			return req
+		})
+		assertMatchesGin(t, results)
+		assertJSONField(t, results, "url", "http://example.com/url/a%20b?q=1&q=2")
+	})
+
+	t.Run("XForwarded", func(t *testing.T) {
+		results := runAcrossFrameworks(t, register, func() *http.Request {
+			req := httptest.NewRequest(http.MethodPost, "http://internal:8080/url/1", nil)
+			req.Header.Set("X-Forwarded-Proto", "https, http")
+			req.Header.Set("X-Forwarded-Host", "api.example.com, internal")
+			return req
+		})
+		assertMatchesGin(t, results)
+		assertJSONField(t, results, "url", "https://api.example.com/url/1")
+		assertJSONField(t, results, "abs", "https://api.example.com/users/7?tab=posts")
+	})
+
+	t.Run("Forwarded", func(t *testing.T) {
+		results := runAcrossFrameworks(t, register, func() *http.Request {
+			req := httptest.NewRequest(http.MethodPost, "http://internal/url/1", nil)
+			req.Header.Set("Forwarded", `for=192.0.2.60;proto=https;host="example.org:8443", for=10.0.0.1`)
+			req.Header.Set("X-Forwarded-Proto", "http")
+			return req
+		})
+		assertMatchesGin(t, results)
+		assertJSONField(t, results, "url", "https://example.org:8443/url/1")
+	})
+}
+
+// assertJSONField checks a string field of the gin baseline JSON body, which
+// assertMatchesGin has already compared across frameworks.
+func assertJSONField(t *testing.T, results map[string]responseSnapshot, key, want string) {
+	t.Helper()
+	var body map[string]any
+	if err := json.Unmarshal([]byte(results["ginx"].Body), &body); err != nil {
+		t.Fatalf("decode gin body: %v", err)
+	}
+	if got := body[key]; got != want {
+		t.Fatalf("%s = %v, want %q", key, got, want)
+	}
+}
+
 func TestBodyFormAndBinderConformance(t *testing.T) {
 	t.Run("BodyRaw", func(t *testing.T) {
 		results := runAcrossFrameworks(t, func(r httpx.Router) {
@@ -156,6 +276,196 @@ func TestBodyFormAndBinderConformance(t *testing.T) {
 		assertMatchesGin(t, results)
 	})
 
+	t.Run("BindExtendedTypes", func(t *testing.T) {
+		type input struct {
+			Tags  []string          `query:"tag" form:"tag"`
+			Meta  map[string]string `query:"meta" form:"meta"`
+			At    time.Time         `query:"at" form:"at"`
+			Day   time.Time         `query:"day" form:"day" time_format:"2006-01-02"`
+			Since *time.Time        `query:"since" form:"since" time_format:"unix"`
+			Level level             `query:"level" form:"level"`
+		}
+		const encoded = "tag=a&tag=b&meta[x]=1&meta[y]=2&at=2024-05-01T10:00:00Z&day=2024-05-02&since=1700000000&level=WARN"
+		register := func(r httpx.Router) {
+			r.POST("/bind", func(ctx httpx.Context) error {
+				var q, f input
+				if err := ctx.BindQuery(&q); err != nil {
+					return err
+				}
+				if err := ctx.BindForm(&f); err != nil {
+					return err
+				}
+				return ctx.JSON(200, map[string]any{"query": q, "form": f})
+			})
+		}
+
+		for _, tc := range []struct {
+			name    string
+			request func() *http.Request
+		}{
+			{name: "URLEncoded", request: func() *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "http://example.com/bind?"+encoded, strings.NewReader(encoded))
+				req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+				return req
+			}},
+			{name: "Multipart", request: func() *http.Request {
+				var body bytes.Buffer
+				writer := multipart.NewWriter(&body)
+				for pair := range strings.SplitSeq(encoded, "&") {
+					key, value, _ := strings.Cut(pair, "=")
+					_ = writer.WriteField(key, value)
+				}
+				_ = writer.Close()
+				req := httptest.NewRequest(http.MethodPost, "http://example.com/bind?"+encoded, &body)
+				req.Header.Set("Content-Type", writer.FormDataContentType())
+				return req
+			}},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				results := runAcrossFrameworks(t, register, tc.request)
+				assertMatchesGin(t, results)
+				want := `{"Tags":["a","b"],"Meta":{"x":"1","y":"2"},"At":"2024-05-01T10:00:00Z","Day":"2024-05-02T00:00:00Z","Since":"` +
+					time.Unix(1700000000, 0).Format(time.RFC3339Nano) + `","Level":2}`
+				if body := results["ginx"].Body; !strings.Contains(body, `"form":`+want) || !strings.Contains(body, `"query":`+want) {
+					t.Fatalf("bound %s, want %s for query and form", body, want)
+				}
+			})
+		}
+	})
+
+	t.Run("BindBrackets", func(t *testing.T) {
+		// plain has only slices, which gin's binder would bind without the
+		// brackets; filtered has a named struct field.
+		type plain struct {
+			IDs []int `query:"id" form:"id"`
+		}
+		type filtered struct {
+			Filter struct {
+				Status string   `query:"status" form:"status"`
+				Tags   []string `query:"tags" form:"tags"`
+			} `query:"filter" form:"filter"`
+		}
+		const encoded = "id[]=1&id[]=2&filter[status]=open&filter[tags][]=bug&filter[tags][]=ui"
+		results := runAcrossFrameworks(t, func(r httpx.Router) {
+			r.POST("/bind", func(ctx httpx.Context) error {
+				var qp, fp plain
+				var qf, ff filtered
+				for _, err := range []error{ctx.BindQuery(&qp), ctx.BindQuery(&qf), ctx.BindForm(&fp), ctx.BindForm(&ff)} {
+					if err != nil {
+						return err
+					}
+				}
+				return ctx.JSON(200, map[string]any{"query": []any{qp, qf}, "form": []any{fp, ff}})
+			})
+		}, func() *http.Request {
+			req := httptest.NewRequest(http.MethodPost, "http://example.com/bind?"+encoded, strings.NewReader(encoded))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			return req
+		})
+		assertMatchesGin(t, results)
+		want := `[{"IDs":[1,2]},{"Filter":{"Status":"open","Tags":["bug","ui"]}}]`
+		if body := results["ginx"].Body; !strings.Contains(body, `"form":`+want) || !strings.Contains(body, `"query":`+want) {
+			t.Fatalf("bound %s, want %s for query and form", body, want)
+		}
+	})
+
+	t.Run("BindRegisteredTypes", func(t *testing.T) {
+		type input struct {
+			ID     conformanceID   `uri:"id" query:"id" form:"id" header:"X-ID"`
+			Tiers  []conformanceID `query:"tier" form:"tier" header:"X-Tier"`
+			Parent *conformanceID  `query:"parent" form:"parent" header:"X-Parent"`
+		}
+		results := runAcrossFrameworks(t, func(r httpx.Router) {
+			r.POST("/items/:id", func(ctx httpx.Context) error {
+				var u, q, f, h input
+				for _, err := range []error{ctx.BindURI(&u), ctx.BindQuery(&q), ctx.BindForm(&f), ctx.BindHeader(&h)} {
+					if err != nil {
+						return err
+					}
+				}
+				return ctx.JSON(200, []input{u, q, f, h})
+			})
+		}, func() *http.Request {
+			const encoded = "id=id-2&tier=id-3&tier=id-4&parent=id-5"
+			req := httptest.NewRequest(http.MethodPost, "http://example.com/items/id-1?"+encoded, strings.NewReader(encoded))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			req.Header.Set("X-ID", "id-2")
+			req.Header.Add("X-Tier", "id-3")
+			req.Header.Add("X-Tier", "id-4")
+			req.Header.Set("X-Parent", "id-5")
+			return req
+		})
+		assertMatchesGin(t, results)
+		bound := `{"ID":2,"Tiers":[3,4],"Parent":5}`
+		want := `[{"ID":1,"Tiers":null,"Parent":null},` + bound + "," + bound + "," + bound + "]"
+		if body := results["ginx"].Body; body != want {
+			t.Fatalf("bound %s, want %s", body, want)
+		}
+	})
+
+	t.Run("BindDefaultsAndRequired", func(t *testing.T) {
+		type input struct {
+			Page  int      `query:"page" form:"page" header:"X-Page" default:"1"`
+			Sort  []string `query:"sort" form:"sort" header:"X-Sort" default:"name,id"`
+			Name  string   `query:"name" form:"name" header:"X-Name" binding:"required"`
+			Token string   `query:"token" form:"token" header:"X-Token" binding:"required"`
+		}
+		register := func(r httpx.Router) {
+			r.POST("/bind", func(ctx httpx.Context) error {
+				var q, f, h input
+				out := map[string]any{}
+				for source, err := range map[string]error{
+					"query":  ctx.BindQuery(&q),
+					"form":   ctx.BindForm(&f),
+					"header": ctx.BindHeader(&h),
+				} {
+					var missing *httpx.MissingFieldsError
+					if errors.As(err, &missing) {
+						_, status, _ := httpx.ParseError(err)
+						out[source] = map[string]any{"status": status, "fields": missing.Fields}
+					} else if err != nil {
+						return err
+					}
+				}
+				out["bound"] = []input{q, f, h}
+				return ctx.JSON(200, out)
+			})
+		}
+
+		t.Run("Defaults", func(t *testing.T) {
+			results := runAcrossFrameworks(t, register, func() *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "http://example.com/bind?name=q&token=t", strings.NewReader("name=f&token=t&page="))
+				req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+				req.Header.Set("X-Name", "h")
+				req.Header.Set("X-Token", "t")
+				return req
+			})
+			assertMatchesGin(t, results)
+			want := `{"bound":[{"Page":1,"Sort":["name","id"],"Name":"q","Token":"t"},{"Page":1,"Sort":["name","id"],"Name":"f","Token":"t"},{"Page":1,"Sort":["name","id"],"Name":"h","Token":"t"}]}`
+			if body := results["ginx"].Body; body != want {
+				t.Fatalf("bound %s, want %s", body, want)
+			}
+		})
+
+		t.Run("Required", func(t *testing.T) {
+			results := runAcrossFrameworks(t, register, func() *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "http://example.com/bind?name=q", strings.NewReader("token=t"))
+				req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+				return req
+			})
+			assertMatchesGin(t, results)
+			for _, want := range []string{
+				`"query":{"fields":["token"],"status":422}`,
+				`"form":{"fields":["name"],"status":422}`,
+				`"header":{"fields":["X-Name","X-Token"],"status":422}`,
+			} {
+				if body := results["ginx"].Body; !strings.Contains(body, want) {
+					t.Fatalf("body %s does not contain %s", body, want)
+				}
+			}
+		})
+	})
+
 	t.Run("MultipartAndFormFile", func(t *testing.T) {
 		results := runAcrossFrameworks(t, func(r httpx.Router) {
 			r.POST("/upload", func(ctx httpx.Context) error {
@@ -453,6 +763,56 @@ func TestOptionalContextCapabilitiesConformance(t *testing.T) {
 			}
 		}
 	})
+
+	t.Run("Unwrap", func(t *testing.T) {
+		unwrappers := map[string]func(httpx.Context) (string, bool){
+			"ginx": func(ctx httpx.Context) (string, bool) {
+				native, ok := ginx.Unwrap(ctx)
+				if !ok {
+					return "", false
+				}
+				return native.Request.URL.Path, true
+			},
+			"fiberx": func(ctx httpx.Context) (string, bool) {
+				native, ok := fiberx.Unwrap(ctx)
+				if !ok {
+					return "", false
+				}
+				return native.Path(), true
+			},
+			"echox": func(ctx httpx.Context) (string, bool) {
+				native, ok := echox.Unwrap(ctx)
+				if !ok {
+					return "", false
+				}
+				return native.Request().URL.Path, true
+			},
+			"hertzx": func(ctx httpx.Context) (string, bool) {
+				native, ok := hertzx.Unwrap(ctx)
+				if !ok {
+					return "", false
+				}
+				return string(native.Path()), true
+			},
+		}
+		for _, name := range conformanceFrameworks {
+			h := newHarness(t, name)
+			h.Router.GET("/ctx/unwrap", func(ctx httpx.Context) error {
+				matched := make(map[string]string)
+				for adapter, unwrap := range unwrappers {
+					if path, ok := unwrap(ctx); ok {
+						matched[adapter] = path
+					}
+				}
+				return ctx.JSON(http.StatusOK, matched)
+			})
+			got := h.Do(t, httptest.NewRequest(http.MethodGet, "http://example.com/ctx/unwrap", nil))
+			want := fmt.Sprintf(`{"%s":"/ctx/unwrap"}`, name)
+			if strings.TrimSpace(got.Body) != want {
+				t.Fatalf("%s unwrap = %s, want %s", name, got.Body, want)
+			}
+		}
+	})
 }
 
 func TestWithJSONConformance(t *testing.T) {
@@ -479,6 +839,63 @@ func TestWithJSONConformance(t *testing.T) {
 	})
 }
 
+func TestMocksConformance(t *testing.T) {
+	mocks := httpx.NewMocks(true)
+	mocks.Set("users.list", httpx.MockResponse{
+		Status:  http.StatusOK,
+		Headers: map[string]string{"X-Trace": "mock"},
+		Body:    json.RawMessage(`[{"id":1,"name":"mock"}]`),
+		Latency: 5 * time.Millisecond,
+	})
+	mocks.Set("ping", httpx.MockResponse{
+		Status:      http.StatusAccepted,
+		ContentType: "text/plain; charset=utf-8",
+		Body:        json.RawMessage(`"pong"`),
+	})
+
+	tests := []struct {
+		name string
+		path string
+	}{
+		{name: "JSON", path: "/mock/users"},
+		{name: "Text", path: "/mock/ping"},
+		{name: "Unmocked", path: "/mock/real"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			results := runAcrossFrameworks(t, func(r httpx.Router) {
+				handler := func(ctx httpx.Context) error {
+					return ctx.Text(http.StatusOK, "real-handler")
+				}
+				r.GET("/mock/users", mocks.Wrap("users.list", handler))
+				r.GET("/mock/ping", mocks.Wrap("ping", handler))
+				r.GET("/mock/real", mocks.Wrap("unknown", handler))
+			}, func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "http://example.com"+tc.path, nil)
+			})
+			assertMatchesGin(t, results)
+			if mocked := results["ginx"].Body != "real-handler"; mocked == (tc.name == "Unmocked") {
+				t.Fatalf("unexpected handler selection: body=%q", results["ginx"].Body)
+			}
+		})
+	}
+}
+
+// level is a custom field type decoded through encoding.TextUnmarshaler.
+type level int
+
+func (l *level) UnmarshalText(text []byte) error {
+	switch strings.ToUpper(string(text)) {
+	case "INFO":
+		*l = 1
+	case "WARN":
+		*l = 2
+	default:
+		return fmt.Errorf("unknown level %q", text)
+	}
+	return nil
+}
+
 func mustCookie(ctx httpx.Context, key string) string {
 	v, err := ctx.Cookie(key)
 	if err != nil {
@@ -486,3 +903,18 @@ func mustCookie(ctx httpx.Context, key string) string {
 	}
 	return v
 }
+
+// conformanceID is a domain type bound through httpx.RegisterBindType from
+// values such as "id-7".
+type conformanceID int
+
+func init() {
+	httpx.RegisterBindType(reflect.TypeFor[conformanceID](), func(value string) (any, error) {
+		n, ok := strings.CutPrefix(value, "id-")
+		if !ok {
+			return nil, fmt.Errorf("invalid id %q", value)
+		}
+		id, err := strconv.Atoi(n)
+		return conformanceID(id), err
+	})
+}
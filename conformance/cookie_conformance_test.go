@@ -0,0 +1,69 @@
+package conformance
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-sphere/httpx"
+)
+
+func TestCookieConformance(t *testing.T) {
+	httpx.SetCookieSecret([]byte("conformance"))
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			engine := newEphemeralEngine(t, name)
+			router := engine.Group("")
+			router.GET("/login", func(ctx httpx.Context) error {
+				httpx.SetCookieValue(ctx, "theme", "dark", httpx.WithCookieMaxAge(time.Hour))
+				if err := httpx.SetSignedCookie(ctx, "user", "42"); err != nil {
+					return err
+				}
+				return ctx.NoContent(http.StatusNoContent)
+			})
+			router.GET("/me", func(ctx httpx.Context) error {
+				user, err := httpx.SignedCookie(ctx, "user")
+				if errors.Is(err, httpx.ErrInvalidCookieSignature) {
+					return ctx.NoContent(http.StatusUnauthorized)
+				}
+				if err != nil {
+					return err
+				}
+				return ctx.Text(http.StatusOK, user)
+			})
+
+			resp, err := engine.Test(httptest.NewRequest(http.MethodGet, "/login", nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			_ = resp.Body.Close()
+			cookies := map[string]*http.Cookie{}
+			for _, cookie := range resp.Cookies() {
+				cookies[cookie.Name] = cookie
+			}
+			theme, user := cookies["theme"], cookies["user"]
+			if theme == nil || user == nil {
+				t.Fatalf("cookies = %v", resp.Cookies())
+			}
+			if theme.Value != "dark" || theme.MaxAge != 3600 || theme.Path != "/" ||
+				!theme.Secure || !theme.HttpOnly || theme.SameSite != http.SameSiteLaxMode {
+				t.Fatalf("theme cookie = %+v, want secure defaults", theme)
+			}
+
+			me := func(cookie *http.Cookie) responseSnapshot {
+				req := httptest.NewRequest(http.MethodGet, "/me", nil)
+				req.AddCookie(cookie)
+				return doEngineTest(engine)(t, req)
+			}
+			if resp := me(user); resp.Status != http.StatusOK || resp.Body != "42" {
+				t.Fatalf("GET /me = %d %q", resp.Status, resp.Body)
+			}
+			forged := &http.Cookie{Name: "user", Value: "NDM." + user.Value[len("NDI."):]}
+			if resp := me(forged); resp.Status != http.StatusUnauthorized {
+				t.Fatalf("GET /me with a forged cookie = %d %q, want 401", resp.Status, resp.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package conformance
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/go-sphere/httpx"
+)
+
+func TestCSVConformance(t *testing.T) {
+	type contact struct {
+		Name  string `csv:"name" binding:"required"`
+		Score int    `csv:"score"`
+	}
+	register := func(r httpx.Router) {
+		r.POST("/import", func(ctx httpx.Context) error {
+			var contacts []contact
+			if err := httpx.BindCSV(ctx, &contacts); err != nil {
+				return err
+			}
+			return ctx.Text(http.StatusOK, fmt.Sprint(contacts))
+		})
+		r.GET("/export", func(ctx httpx.Context) error {
+			return httpx.CSVStream(ctx, http.StatusOK, func(yield func([]string) bool) {
+				_ = yield([]string{"name", "score"}) && yield([]string{"Ada, L.", "9"})
+				for i := range 1000 {
+					if !yield([]string{"n" + strconv.Itoa(i), strconv.Itoa(i)}) {
+						return
+					}
+				}
+			}, httpx.CSVOptions{Filename: "contacts.csv"})
+		})
+	}
+
+	results := runAcrossFrameworks(t, register, func() *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/import", strings.NewReader("name,score\nAda,9\n\"Grace, H.\",\n"))
+		req.Header.Set("Content-Type", httpx.CSVContentType)
+		return req
+	})
+	if got := results["ginx"]; got.Status != http.StatusOK || got.Body != "[{Ada 9} {Grace, H. 0}]" {
+		t.Fatalf("import = %d %q", got.Status, got.Body)
+	}
+
+	results = runAcrossFrameworks(t, register, func() *http.Request {
+		return httptest.NewRequest(http.MethodGet, "/export", nil)
+	})
+	got := results["ginx"]
+	if got.Status != http.StatusOK || got.Headers.Get("Content-Type") != httpx.CSVContentType || got.Headers.Get("Content-Disposition") != "attachment; filename=contacts.csv" {
+		t.Fatalf("export = %d, headers %v", got.Status, got.Headers)
+	}
+	if lines := strings.Split(got.Body, "\n"); len(lines) != 1003 || lines[1] != `"Ada, L.",9` || lines[1001] != "n999,999" {
+		t.Fatalf("export has %d lines, starting %q", len(lines), lines[:2])
+	}
+}
@@ -0,0 +1,93 @@
+package conformance
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/go-sphere/httpx"
+	"github.com/go-sphere/httpx/middleware"
+)
+
+func compress(t *testing.T, encoding string, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch encoding {
+	case "gzip":
+		w = gzip.NewWriter(&buf)
+	case "deflate":
+		w = zlib.NewWriter(&buf)
+	case "br":
+		w = brotli.NewWriter(&buf)
+	}
+	if _, err := w.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDecompressConformance(t *testing.T) {
+	const payload = `{"name":"gopher","tags":["a","b"]}`
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			h := newHarness(t, name)
+			h.Router.Use(middleware.Decompress(middleware.WithMaxDecompressedSize(1 << 10)))
+			h.Router.POST("/items", func(ctx httpx.Context) error {
+				raw, err := ctx.BodyRaw()
+				if err != nil {
+					return err
+				}
+				var item struct {
+					Name string   `json:"name"`
+					Tags []string `json:"tags"`
+				}
+				if err := ctx.BindJSON(&item); err != nil {
+					return err
+				}
+				return ctx.Text(http.StatusOK, item.Name+" "+strings.Join(item.Tags, ",")+" "+
+					ctx.Header("Content-Encoding")+"|"+string(raw))
+			})
+			post := func(encoding string, body []byte) responseSnapshot {
+				req := httptest.NewRequest(http.MethodPost, "http://example.com/items", bytes.NewReader(body))
+				req.Header.Set("Content-Type", "application/json")
+				if encoding != "" {
+					req.Header.Set("Content-Encoding", encoding)
+				}
+				return h.Do(t, req)
+			}
+
+			want := "gopher a,b |" + payload
+			for _, encoding := range []string{"", "gzip", "deflate", "br"} {
+				body := []byte(payload)
+				if encoding != "" {
+					body = compress(t, encoding, body)
+				}
+				if resp := post(encoding, body); resp.Status != http.StatusOK || resp.Body != want {
+					t.Fatalf("POST with Content-Encoding %q = %d %q, want %q", encoding, resp.Status, resp.Body, want)
+				}
+			}
+			stacked := compress(t, "br", compress(t, "gzip", []byte(payload)))
+			if resp := post("gzip, br", stacked); resp.Status != http.StatusOK || resp.Body != want {
+				t.Fatalf("POST with stacked codings = %d %q", resp.Status, resp.Body)
+			}
+
+			bomb := compress(t, "gzip", make([]byte, 1<<20))
+			if resp := post("gzip", bomb); resp.Status == http.StatusOK || !strings.Contains(resp.Body, "too large") {
+				t.Fatalf("POST of a compression bomb = %d %q", resp.Status, resp.Body)
+			}
+			if resp := post("compress", []byte(payload)); resp.Status == http.StatusOK || !strings.Contains(resp.Body, "unsupported content encoding") {
+				t.Fatalf("POST with an unsupported coding = %d %q", resp.Status, resp.Body)
+			}
+		})
+	}
+}
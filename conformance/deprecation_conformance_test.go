@@ -0,0 +1,55 @@
+package conformance
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-sphere/httpx"
+	"github.com/go-sphere/httpx/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestDeprecatedConformance(t *testing.T) {
+	sunset := time.Date(2027, time.January, 31, 0, 0, 0, 0, time.UTC)
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			registry := prometheus.NewRegistry()
+			h := newHarness(t, name)
+			h.Router.Use(middleware.Metrics(registry))
+			list := func(ctx httpx.Context) error {
+				return ctx.Text(http.StatusOK, "orders")
+			}
+			h.Router.GET("/v1/orders", httpx.Deprecated(list, sunset, "https://example.com/migrate"))
+			h.Router.GET("/v2/orders", list)
+			middleware.MountMetrics(h.Router, "/metrics", registry)
+
+			got := h.Do(t, httptest.NewRequest(http.MethodGet, "http://example.com/v1/orders", nil))
+			if got.Status != http.StatusOK || got.Body != "orders" {
+				t.Fatalf("deprecated route: status %d, body %q", got.Status, got.Body)
+			}
+			for key, want := range map[string]string{
+				"Deprecation": "true",
+				"Sunset":      "Sun, 31 Jan 2027 00:00:00 GMT",
+				"Link":        `<https://example.com/migrate>; rel="deprecation"`,
+			} {
+				if v := got.Headers.Get(key); v != want {
+					t.Fatalf("%s = %q, want %q", key, v, want)
+				}
+			}
+			if got := h.Do(t, httptest.NewRequest(http.MethodGet, "http://example.com/v2/orders", nil)); got.Headers.Get("Deprecation") != "" {
+				t.Fatalf("current route has Deprecation %q", got.Headers.Get("Deprecation"))
+			}
+
+			metrics := h.Do(t, httptest.NewRequest(http.MethodGet, "http://example.com/metrics", nil))
+			if want := `http_deprecated_requests_total{method="GET",route="/v1/orders"} 1`; !strings.Contains(metrics.Body, want) {
+				t.Fatalf("/metrics body missing %q:\n%s", want, metrics.Body)
+			}
+			if strings.Contains(metrics.Body, `http_deprecated_requests_total{method="GET",route="/v2/orders"}`) {
+				t.Fatal("current route counted as deprecated")
+			}
+		})
+	}
+}
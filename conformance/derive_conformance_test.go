@@ -0,0 +1,73 @@
+package conformance
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-sphere/httpx"
+	"github.com/go-sphere/httpx/lambdax"
+)
+
+type deriveKey string
+
+func TestDeriveConformance(t *testing.T) {
+	engines := map[string]func(t *testing.T) httpx.Engine{
+		"lambdax": func(*testing.T) httpx.Engine { return lambdax.New() },
+	}
+	for _, name := range conformanceFrameworks {
+		engines[name] = func(t *testing.T) httpx.Engine { return newEphemeralEngine(t, name) }
+	}
+	for name, newEngine := range engines {
+		t.Run(name, func(t *testing.T) {
+			engine := newEngine(t)
+			engine.Use(func(ctx httpx.Context) error {
+				httpx.WithValue(ctx, deriveKey("engine"), "engine")
+				c, cancel := context.WithTimeout(ctx.Context(), time.Minute)
+				defer cancel()
+				ctx.SetContext(c)
+				return ctx.Next()
+			})
+			seen := map[string]any{}
+			report := func(prefix string) httpx.Handler {
+				return func(ctx httpx.Context) error {
+					c := ctx.Context()
+					_, hasDeadline := c.Deadline()
+					seen[prefix+"engine"] = c.Value(deriveKey("engine"))
+					seen[prefix+"router"] = c.Value(deriveKey("router"))
+					seen[prefix+"base"] = c.Value(deriveKey("base"))
+					seen[prefix+"deadline"] = hasDeadline
+					return ctx.NoContent(http.StatusNoContent)
+				}
+			}
+			r := engine.Group("", func(ctx httpx.Context) error {
+				httpx.WithValue(ctx, deriveKey("router"), "router")
+				return ctx.Next()
+			})
+			r.GET("/augment", report("augment."))
+			r.Group("/derived", func(ctx httpx.Context) error {
+				base := context.WithValue(context.Background(), deriveKey("base"), "base")
+				defer httpx.Derive(ctx, base)()
+				return ctx.Next()
+			}).GET("", report("derive."))
+
+			do := doEngineTest(engine)
+			for _, path := range []string{"/augment", "/derived"} {
+				if got := do(t, httptest.NewRequest(http.MethodGet, "http://example.com"+path, nil)); got.Status != http.StatusNoContent {
+					t.Fatalf("%s: status %d, body %s", path, got.Status, got.Body)
+				}
+			}
+			want := map[string]any{
+				"augment.engine": "engine", "augment.router": "router", "augment.base": nil, "augment.deadline": true,
+				"derive.engine": nil, "derive.router": nil, "derive.base": "base", "derive.deadline": true,
+			}
+			for key, value := range want {
+				if seen[key] != value {
+					t.Errorf("%s = %v, want %v", key, seen[key], value)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package conformance
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-sphere/httpx"
+	"github.com/go-sphere/httpx/middleware"
+)
+
+func TestDetachConformance(t *testing.T) {
+	logger := slog.New(slog.DiscardHandler)
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			release := make(chan struct{})
+			seen := make(chan map[string]any, 1)
+
+			h := newHarness(t, name)
+			h.Router.Use(middleware.RequestID(), func(ctx httpx.Context) error {
+				httpx.SetLogger(ctx, logger)
+				ctx.Set("tenant", "acme")
+				ctx.Set("secret", "not-copied")
+				return ctx.Next()
+			})
+			h.Router.GET("/detach", func(ctx httpx.Context) error {
+				detached := httpx.Detach(ctx, "tenant")
+				go func() {
+					<-release
+					// The request has completed and its state may be reused.
+					seen <- map[string]any{
+						"requestID": middleware.RequestIDFromContext(detached),
+						"logger":    httpx.LoggerFromContext(detached) == logger,
+						"tenant":    detached.Value(httpx.StateKey("tenant")),
+						"secret":    detached.Value(httpx.StateKey("secret")),
+						"err":       detached.Err(),
+						"done":      detached.Done() == nil,
+					}
+				}()
+				return ctx.NoContent(http.StatusAccepted)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "http://example.com/detach", nil)
+			req.Header.Set("X-Request-ID", "req-1")
+			if got := h.Do(t, req); got.Status != http.StatusAccepted {
+				t.Fatalf("status = %d, want %d", got.Status, http.StatusAccepted)
+			}
+			close(release)
+
+			var got map[string]any
+			select {
+			case got = <-seen:
+			case <-time.After(2 * time.Second):
+				t.Fatal("background work did not finish")
+			}
+			want := map[string]any{
+				"requestID": "req-1",
+				"logger":    true,
+				"tenant":    "acme",
+				"secret":    nil,
+				"err":       nil,
+				"done":      true,
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("detached context = %v, want %v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package conformance
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-sphere/httpx"
+	"github.com/go-sphere/httpx/echox"
+	"github.com/go-sphere/httpx/fiberx"
+	"github.com/go-sphere/httpx/ginx"
+	"github.com/go-sphere/httpx/hertzx"
+)
+
+func TestClientDisconnectConformance(t *testing.T) {
+	// A concurrency limit wraps connections with httpx.LimitListener, and
+	// makes hertz use its standard transport instead of netpoll.
+	limited := []any{
+		ginx.WithConcurrencyLimit(8),
+		fiberx.WithConcurrencyLimit(8),
+		echox.WithConcurrencyLimit(8),
+		hertzx.WithConcurrencyLimit(8),
+	}
+	for _, name := range conformanceFrameworks {
+		for _, variant := range []string{"default", "limited"} {
+			t.Run(name+"/"+variant, func(t *testing.T) {
+				var opts []any
+				if variant == "limited" {
+					opts = limited
+				}
+				testClientDisconnect(t, name, opts...)
+			})
+		}
+	}
+}
+
+// testClientDisconnect checks that an engine built with opts leaves watched
+// connections intact and cancels the request context of a client that
+// disconnects.
+func testClientDisconnect(t *testing.T, name string, opts ...any) {
+	engine := newEphemeralEngine(t, name, opts...)
+	r := engine.Group("")
+	started := make(chan struct{}, 1)
+	gone := make(chan bool, 1)
+	r.GET("/wait", func(ctx httpx.Context) error {
+		started <- struct{}{}
+		select {
+		case <-ctx.Context().Done():
+			gone <- httpx.IsClientGone(ctx)
+		case <-time.After(5 * time.Second):
+			gone <- false
+		}
+		return nil
+	})
+	r.GET("/watched", func(ctx httpx.Context) error {
+		// Waiting on Done watches the connection, which must be left
+		// intact for the next request.
+		_ = ctx.Context().Done()
+		if httpx.IsClientGone(ctx) {
+			return ctx.Text(http.StatusInternalServerError, "gone")
+		}
+		return ctx.Text(http.StatusOK, "ok")
+	})
+	startErrCh := make(chan error, 1)
+	go func() {
+		startErrCh <- engine.Start()
+	}()
+	addr := waitBoundAddr(t, engine, startErrCh).String()
+	t.Cleanup(func() { _ = engine.Stop(t.Context()) })
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+	reader := bufio.NewReader(conn)
+	for i := range 2 {
+		if _, err := io.WriteString(conn, "GET /watched HTTP/1.1\r\nHost: example.com\r\n\r\n"); err != nil {
+			t.Fatal(err)
+		}
+		resp, err := http.ReadResponse(reader, nil)
+		if err != nil {
+			t.Fatalf("request %d on a watched connection: %v", i, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("request %d on a watched connection: status %d, body %s", i, resp.StatusCode, body)
+		}
+	}
+
+	conn, err = net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.WriteString(conn, "GET /wait HTTP/1.1\r\nHost: example.com\r\n\r\n"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-started:
+	case <-time.After(3 * time.Second):
+		t.Fatal("handler did not start")
+	}
+	_ = conn.Close()
+	if !<-gone {
+		t.Fatal("request context was not canceled when the client disconnected")
+	}
+}
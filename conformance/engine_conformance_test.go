@@ -1,20 +1,19 @@
 package conformance
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"io"
 	"net"
 	"net/http"
-	"net/http/httptest"
-	"net/textproto"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/cloudwego/hertz/pkg/app"
 	"github.com/cloudwego/hertz/pkg/app/server"
+	"github.com/cloudwego/hertz/pkg/common/config"
 	"github.com/cloudwego/hertz/pkg/common/hlog"
 	"github.com/gin-gonic/gin"
 	"github.com/go-sphere/httpx"
@@ -26,11 +25,7 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
-type responseSnapshot struct {
-	Status  int
-	Body    string
-	Headers http.Header
-}
+type responseSnapshot = Response
 
 type frameworkHarness struct {
 	Name   string
@@ -52,12 +47,14 @@ type harnessErrorMode int
 const (
 	harnessErrorDefault harnessErrorMode = iota
 	harnessErrorTeapot
+	harnessErrorProblem
 )
 
 type harnessOptions struct {
 	mode            harnessMode
 	errorMode       harnessErrorMode
 	silenceHertzLog bool
+	runtimeRoutes   bool
 }
 
 type harnessBundle struct {
@@ -117,6 +114,347 @@ func TestEngineStartConformance(t *testing.T) {
 	}
 }
 
+func TestEngineStopGracefulConformance(t *testing.T) {
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			b := newFrameworkHarnessTB(t, name, harnessOptions{mode: harnessModeNetwork, errorMode: harnessErrorDefault, silenceHertzLog: true})
+
+			inFlight := make(chan struct{})
+			b.harness.Router.GET("/__ready", func(ctx httpx.Context) error {
+				return ctx.NoContent(http.StatusNoContent)
+			})
+			b.harness.Router.GET("/slow", func(ctx httpx.Context) error {
+				close(inFlight)
+				time.Sleep(200 * time.Millisecond)
+				return ctx.Text(http.StatusOK, "done")
+			})
+
+			startErrCh := startNetworkHarness(t, b)
+
+			type result struct {
+				status int
+				body   string
+				err    error
+			}
+			resultCh := make(chan result, 1)
+			go func() {
+				resp, err := b.client.Get(b.baseURL + "/slow")
+				if err != nil {
+					resultCh <- result{err: err}
+					return
+				}
+				defer func() {
+					_ = resp.Body.Close()
+				}()
+				body, err := io.ReadAll(resp.Body)
+				resultCh <- result{status: resp.StatusCode, body: string(body), err: err}
+			}()
+
+			select {
+			case <-inFlight:
+			case <-time.After(2 * time.Second):
+				t.Fatalf("%s slow request never reached the handler", name)
+			}
+
+			drained := false
+			stopCtx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			defer cancel()
+			if err := b.harness.Engine.StopGraceful(stopCtx, func() { drained = true }); err != nil {
+				t.Fatalf("%s StopGraceful returned error: %v", name, err)
+			}
+			if !drained {
+				t.Fatalf("%s drain callback was not called", name)
+			}
+
+			got := <-resultCh
+			if got.err != nil {
+				t.Fatalf("%s in-flight request failed: %v", name, got.err)
+			}
+			if got.status != http.StatusOK || got.body != "done" {
+				t.Fatalf("%s in-flight request mismatch: status=%d body=%q", name, got.status, got.body)
+			}
+
+			select {
+			case err := <-startErrCh:
+				if !isExpectedStartExit(err) {
+					t.Fatalf("%s start returned unexpected error: %v", name, err)
+				}
+			case <-time.After(3 * time.Second):
+				t.Fatalf("%s start did not exit after graceful stop", name)
+			}
+		})
+	}
+}
+
+func TestEngineLifecycleHooksConformance(t *testing.T) {
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			b := newFrameworkHarnessTB(t, name, harnessOptions{mode: harnessModeNetwork, errorMode: harnessErrorDefault, silenceHertzLog: true})
+			engine := b.harness.Engine
+
+			var events []string
+			engine.OnStart(func() error {
+				events = append(events, "start")
+				return nil
+			})
+			engine.OnStopping(func() {
+				events = append(events, "stopping")
+			})
+			engine.OnStop(func(context.Context) error {
+				events = append(events, "stop:first")
+				return nil
+			})
+			engine.OnStop(func(context.Context) error {
+				events = append(events, "stop:second")
+				return nil
+			})
+
+			b.harness.Router.GET("/__ready", func(ctx httpx.Context) error {
+				return ctx.NoContent(http.StatusNoContent)
+			})
+			var routes []httpx.RouteInfo
+			engine.OnRouteRegistered(func(info httpx.RouteInfo) {
+				routes = append(routes, info)
+			})
+			api := engine.Group("/api")
+			api.POST("/users", func(ctx httpx.Context) error {
+				return ctx.NoContent(http.StatusCreated)
+			})
+			api.Group("/v1").Any("/items", func(ctx httpx.Context) error {
+				return ctx.NoContent(http.StatusOK)
+			})
+
+			wantRoutes := []httpx.RouteInfo{
+				{Method: http.MethodGet, Path: "/__ready"},
+				{Method: http.MethodPost, Path: "/api/users"},
+				{Method: httpx.MethodAny, Path: "/api/v1/items"},
+			}
+			if !slices.Equal(routes, wantRoutes) {
+				t.Fatalf("%s routes mismatch: got=%v want=%v", name, routes, wantRoutes)
+			}
+
+			startErrCh := startNetworkHarness(t, b)
+			stopCtx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			defer cancel()
+			if err := engine.Stop(stopCtx); err != nil {
+				t.Fatalf("%s stop returned error: %v", name, err)
+			}
+			select {
+			case err := <-startErrCh:
+				if !isExpectedStartExit(err) {
+					t.Fatalf("%s start returned unexpected error: %v", name, err)
+				}
+			case <-time.After(3 * time.Second):
+				t.Fatalf("%s start did not exit after stop", name)
+			}
+
+			wantEvents := []string{"start", "stopping", "stop:second", "stop:first"}
+			if !slices.Equal(events, wantEvents) {
+				t.Fatalf("%s lifecycle events mismatch: got=%v want=%v", name, events, wantEvents)
+			}
+		})
+	}
+}
+
+func TestEngineOnStartErrorConformance(t *testing.T) {
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			engine := newStartEngine(t, name)
+			wantErr := errors.New("warmup failed")
+			engine.OnStart(func() error {
+				return wantErr
+			})
+			if err := engine.Start(); !errors.Is(err, wantErr) {
+				t.Fatalf("%s start error = %v, want %v", name, err, wantErr)
+			}
+			if engine.IsRunning() {
+				t.Fatalf("%s engine should not be running after failed start hook", name)
+			}
+		})
+	}
+}
+
+func TestEngineWithListenerConformance(t *testing.T) {
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			ln := listenTB(t)
+			var engine httpx.Engine
+			switch name {
+			case "ginx":
+				gin.SetMode(gin.ReleaseMode)
+				engine = ginx.New(ginx.WithEngine(gin.New()), ginx.WithListener(ln))
+			case "fiberx":
+				engine = fiberx.New(fiberx.WithListener(ln, fiber.ListenConfig{DisableStartupMessage: true}))
+			case "echox":
+				engine = echox.New(echox.WithListener(ln))
+			case "hertzx":
+				engine = hertzx.New(hertzx.WithServerOptions(server.WithDisablePrintRoute(true)), hertzx.WithListener(ln))
+			}
+			b := harnessBundle{
+				harness: frameworkHarness{Name: name, Engine: engine, Router: engine.Group("")},
+				baseURL: "http://" + ln.Addr().String(),
+				client:  &http.Client{Timeout: 2 * time.Second},
+			}
+			b.harness.Router.GET("/__ready", func(ctx httpx.Context) error {
+				return ctx.NoContent(http.StatusNoContent)
+			})
+
+			startErrCh := startNetworkHarness(t, b)
+			stopCtx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			defer cancel()
+			_ = engine.Stop(stopCtx)
+			select {
+			case err := <-startErrCh:
+				if !isExpectedStartExit(err) {
+					t.Fatalf("%s start returned unexpected error: %v", name, err)
+				}
+			case <-time.After(3 * time.Second):
+				t.Fatalf("%s start did not exit after stop", name)
+			}
+		})
+	}
+}
+
+func TestEngineBoundAddrConformance(t *testing.T) {
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			engine := newEphemeralEngine(t, name)
+			if addr := engine.BoundAddr(); addr != nil {
+				t.Fatalf("%s BoundAddr before start = %v, want nil", name, addr)
+			}
+			engine.Group("").GET("/__ready", func(ctx httpx.Context) error {
+				return ctx.NoContent(http.StatusNoContent)
+			})
+
+			startErrCh := make(chan error, 1)
+			go func() {
+				startErrCh <- engine.Start()
+			}()
+			addr := waitBoundAddr(t, engine, startErrCh)
+			tcpAddr, ok := addr.(*net.TCPAddr)
+			if !ok || tcpAddr.Port == 0 {
+				t.Fatalf("%s BoundAddr = %v, want a TCP address with a concrete port", name, addr)
+			}
+
+			b := harnessBundle{
+				harness: frameworkHarness{Name: name, Engine: engine},
+				baseURL: "http://" + addr.String(),
+				client:  &http.Client{Timeout: 2 * time.Second},
+			}
+			req, err := http.NewRequest(http.MethodGet, b.baseURL+"/__ready", nil)
+			if err != nil {
+				t.Fatalf("build request failed: %v", err)
+			}
+			deadline := time.Now().Add(3 * time.Second)
+			for {
+				status, err := doRequest(b.client, req)
+				if err == nil && status == http.StatusNoContent {
+					break
+				}
+				if time.Now().After(deadline) {
+					t.Fatalf("%s request to BoundAddr failed: status=%d err=%v", name, status, err)
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+
+			stopCtx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			defer cancel()
+			_ = engine.Stop(stopCtx)
+			select {
+			case err := <-startErrCh:
+				if !isExpectedStartExit(err) {
+					t.Fatalf("%s start returned unexpected error: %v", name, err)
+				}
+			case <-time.After(3 * time.Second):
+				t.Fatalf("%s start did not exit after stop", name)
+			}
+			if addr := engine.BoundAddr(); addr != nil {
+				t.Fatalf("%s BoundAddr after stop = %v, want nil", name, addr)
+			}
+		})
+	}
+}
+
+// newEphemeralEngine builds an engine listening on 127.0.0.1:0 through each
+// adapter's regular address option.
+func newEphemeralEngine(tb testing.TB, name string, opts ...any) httpx.Engine {
+	tb.Helper()
+	const addr = "127.0.0.1:0"
+	switch name {
+	case "ginx":
+		gin.SetMode(gin.ReleaseMode)
+		return ginx.New(append([]ginx.Option{ginx.WithEngine(gin.New()), ginx.WithServerAddr(addr)}, optionsOf[ginx.Option](opts)...)...)
+	case "fiberx":
+		return fiberx.New(append([]fiberx.Option{fiberx.WithListen(addr, fiber.ListenConfig{DisableStartupMessage: true})}, optionsOf[fiberx.Option](opts)...)...)
+	case "echox":
+		return echox.New(append([]echox.Option{echox.WithServerAddr(addr)}, optionsOf[echox.Option](opts)...)...)
+	case "hertzx":
+		return hertzx.New(append([]hertzx.Option{hertzx.WithServerOptions(server.WithHostPorts(addr), server.WithDisablePrintRoute(true))}, optionsOf[hertzx.Option](opts)...)...)
+	default:
+		tb.Fatalf("unknown framework: %s", name)
+		return nil
+	}
+}
+
+func optionsOf[T any](opts []any) []T {
+	out := make([]T, 0, len(opts))
+	for _, opt := range opts {
+		if o, ok := opt.(T); ok {
+			out = append(out, o)
+		}
+	}
+	return out
+}
+
+// waitBoundAddr waits until a starting engine reports its bound address.
+func waitBoundAddr(tb testing.TB, engine httpx.Engine, startErrCh <-chan error) net.Addr {
+	tb.Helper()
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		select {
+		case err := <-startErrCh:
+			tb.Fatalf("engine exited before binding: %v", err)
+		default:
+		}
+		if addr := engine.BoundAddr(); addr != nil {
+			return addr
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	tb.Fatalf("engine did not report a bound address")
+	return nil
+}
+
+// startNetworkHarness starts a network-mode engine and waits until its
+// /__ready route answers. The returned channel receives the Start result.
+func startNetworkHarness(tb testing.TB, b harnessBundle) <-chan error {
+	tb.Helper()
+
+	startErrCh := make(chan error, 1)
+	go func() {
+		startErrCh <- b.harness.Engine.Start()
+	}()
+
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		select {
+		case err := <-startErrCh:
+			tb.Fatalf("%s engine exited before ready: %v", b.harness.Name, err)
+		default:
+		}
+		req, err := http.NewRequest(http.MethodGet, b.baseURL+"/__ready", nil)
+		if err != nil {
+			tb.Fatalf("build ready request failed: %v", err)
+		}
+		if status, err := doRequest(b.client, req); err == nil && status == http.StatusNoContent {
+			return startErrCh
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	tb.Fatalf("%s engine did not become ready", b.harness.Name)
+	return startErrCh
+}
+
 func isExpectedStartExit(err error) bool {
 	if err == nil {
 		return true
@@ -171,77 +509,65 @@ func newFrameworkHarnessTB(tb testing.TB, name string, opts harnessOptions) harn
 		gin.SetMode(gin.ReleaseMode)
 		g := gin.New()
 		g.Use(gin.Recovery())
-		addr := ginLikeAddrForMode(tb, opts.mode)
+		addr, ln := ginLikeAddrForMode(tb, opts.mode)
 
-		var engine httpx.Engine
+		ginOpts := []ginx.Option{ginx.WithEngine(g), ginx.WithServerAddr(addr), ginx.WithAllowRuntimeRoutes(opts.runtimeRoutes)}
+		if ln != nil {
+			ginOpts = append(ginOpts, ginx.WithListener(ln))
+		}
 		if opts.errorMode == harnessErrorTeapot {
-			engine = ginx.New(
-				ginx.WithEngine(g),
-				ginx.WithServerAddr(addr),
-				ginx.WithErrorHandler(func(ctx *gin.Context, err error) {
-					ctx.JSON(http.StatusTeapot, gin.H{"error": err.Error()})
-				}),
-			)
-		} else {
-			engine = ginx.New(ginx.WithEngine(g), ginx.WithServerAddr(addr))
+			ginOpts = append(ginOpts, ginx.WithErrorHandler(func(ctx *gin.Context, err error) {
+				ctx.JSON(http.StatusTeapot, gin.H{"error": err.Error()})
+			}))
+		}
+		if opts.errorMode == harnessErrorProblem {
+			ginOpts = append(ginOpts, ginx.WithErrorHandler(ginx.AdaptErrorHandler(httpx.ProblemErrorHandler)))
 		}
+		engine := ginx.New(ginOpts...)
 
 		h := frameworkHarness{
 			Name:   name,
 			Engine: engine,
 			Router: engine.Group(""),
-			Do: func(t *testing.T, req *http.Request) responseSnapshot {
-				t.Helper()
-				rr := httptest.NewRecorder()
-				g.ServeHTTP(rr, req)
-				return snapshotFromHTTPResponse(t, rr.Result())
-			},
+			Do:     doEngineTest(engine),
 		}
 		if opts.mode == harnessModeNetwork {
 			return harnessBundle{harness: h, baseURL: "http://" + addr, client: &http.Client{Timeout: 2 * time.Second}}
 		}
 		return harnessBundle{harness: h}
 	case "fiberx":
-		f := fiber.New(fiber.Config{
-			ErrorHandler: func(ctx fiber.Ctx, err error) error {
-				if opts.errorMode == harnessErrorTeapot {
-					return ctx.Status(http.StatusTeapot).JSON(fiber.Map{"error": err.Error()})
-				}
-				return ctx.Status(500).JSON(fiber.Map{"error": err.Error()})
-			},
-		})
+		errHandler := func(ctx fiber.Ctx, err error) error {
+			if opts.errorMode == harnessErrorTeapot {
+				return ctx.Status(http.StatusTeapot).JSON(fiber.Map{"error": err.Error()})
+			}
+			return ctx.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+		if opts.errorMode == harnessErrorProblem {
+			errHandler = fiberx.AdaptErrorHandler(httpx.ProblemErrorHandler)
+		}
+		f := fiber.New(fiber.Config{ErrorHandler: errHandler})
 
-		var engine httpx.Engine
+		fiberOpts := []fiberx.Option{fiberx.WithEngine(f), fiberx.WithAllowRuntimeRoutes(opts.runtimeRoutes)}
 		baseURL := ""
 		client := (*http.Client)(nil)
 		switch opts.mode {
 		case harnessModeNetwork:
-			ln, err := net.Listen("tcp", "127.0.0.1:0")
-			if err != nil {
-				tb.Fatalf("listen failed: %v", err)
-				return harnessBundle{}
-			}
-			engine = fiberx.New(fiberx.WithEngine(f), fiberx.WithListener(ln, fiber.ListenConfig{DisableStartupMessage: true}))
+			ln := listenTB(tb)
+			fiberOpts = append(fiberOpts, fiberx.WithListener(ln, fiber.ListenConfig{DisableStartupMessage: true}))
 			baseURL = "http://" + ln.Addr().String()
 			client = &http.Client{Timeout: 2 * time.Second}
 		case harnessModeStartOnly:
-			engine = fiberx.New(fiberx.WithEngine(f), fiberx.WithListen("127.0.0.1:0", fiber.ListenConfig{DisableStartupMessage: true}))
+			fiberOpts = append(fiberOpts, fiberx.WithListen("127.0.0.1:0", fiber.ListenConfig{DisableStartupMessage: true}))
 		default:
-			engine = fiberx.New(fiberx.WithEngine(f), fiberx.WithListen(":0"))
+			fiberOpts = append(fiberOpts, fiberx.WithListen(":0"))
 		}
+		engine := fiberx.New(fiberOpts...)
 
 		h := frameworkHarness{
 			Name:   name,
 			Engine: engine,
 			Router: engine.Group(""),
-			Do: func(t *testing.T, req *http.Request) responseSnapshot {
-				t.Helper()
-				resp, err := f.Test(req)
-				if err != nil {
-					t.Fatalf("fiberx test request failed: %v", err)
-				}
-				return snapshotFromHTTPResponse(t, resp)
-			},
+			Do:     doEngineTest(engine),
 		}
 		return harnessBundle{harness: h, baseURL: baseURL, client: client}
 	case "echox":
@@ -253,51 +579,50 @@ func newFrameworkHarnessTB(tb testing.TB, name string, opts harnessOptions) harn
 			}
 			_ = c.JSON(status, echo.Map{"error": err.Error()})
 		}
+		if opts.errorMode == harnessErrorProblem {
+			e.HTTPErrorHandler = echox.AdaptErrorHandler(httpx.ProblemErrorHandler)
+		}
 
-		addr := ginLikeAddrForMode(tb, opts.mode)
-		engine := echox.New(echox.WithEngine(e), echox.WithServerAddr(addr))
+		addr, ln := ginLikeAddrForMode(tb, opts.mode)
+		echoOpts := []echox.Option{echox.WithEngine(e), echox.WithServerAddr(addr), echox.WithAllowRuntimeRoutes(opts.runtimeRoutes)}
+		if ln != nil {
+			echoOpts = append(echoOpts, echox.WithListener(ln))
+		}
+		engine := echox.New(echoOpts...)
 		h := frameworkHarness{
 			Name:   name,
 			Engine: engine,
 			Router: engine.Group(""),
-			Do: func(t *testing.T, req *http.Request) responseSnapshot {
-				t.Helper()
-				rr := httptest.NewRecorder()
-				e.ServeHTTP(rr, req)
-				return snapshotFromHTTPResponse(t, rr.Result())
-			},
+			Do:     doEngineTest(engine),
 		}
 		if opts.mode == harnessModeNetwork {
 			return harnessBundle{harness: h, baseURL: "http://" + addr, client: &http.Client{Timeout: 2 * time.Second}}
 		}
 		return harnessBundle{harness: h}
 	case "hertzx":
-		addr := hertzAddrForMode(tb, opts.mode)
-		h := server.Default(
-			server.WithHostPorts(addr),
-			server.WithDisablePrintRoute(true),
-		)
-
-		var engine httpx.Engine
-		if opts.errorMode == harnessErrorTeapot {
-			engine = hertzx.New(
-				hertzx.WithEngine(h),
-				hertzx.WithErrorHandler(func(ctx context.Context, rc *app.RequestContext, err error) {
-					rc.JSON(http.StatusTeapot, map[string]string{"error": err.Error()})
-				}),
-			)
-		} else {
-			engine = hertzx.New(hertzx.WithEngine(h))
+		addr, ln := hertzAddrForMode(tb, opts.mode)
+		hertzOpts := []config.Option{server.WithHostPorts(addr), server.WithDisablePrintRoute(true)}
+		if ln != nil {
+			hertzOpts = append(hertzOpts, server.WithListener(ln))
+		}
+		h := server.Default(hertzOpts...)
+
+		engineOpts := []hertzx.Option{hertzx.WithEngine(h), hertzx.WithAllowRuntimeRoutes(opts.runtimeRoutes)}
+		switch opts.errorMode {
+		case harnessErrorTeapot:
+			engineOpts = append(engineOpts, hertzx.WithErrorHandler(func(ctx context.Context, rc *app.RequestContext, err error) {
+				rc.JSON(http.StatusTeapot, map[string]string{"error": err.Error()})
+			}))
+		case harnessErrorProblem:
+			engineOpts = append(engineOpts, hertzx.WithErrorHandler(hertzx.AdaptErrorHandler(httpx.ProblemErrorHandler)))
 		}
+		engine := hertzx.New(engineOpts...)
 
 		fh := frameworkHarness{
 			Name:   name,
 			Engine: engine,
 			Router: engine.Group(""),
-			Do: func(t *testing.T, req *http.Request) responseSnapshot {
-				t.Helper()
-				return doHertzRequest(t, h, req)
-			},
+			Do:     doEngineTest(engine),
 		}
 		if opts.mode == harnessModeNetwork {
 			return harnessBundle{harness: fh, baseURL: "http://" + addr, client: &http.Client{Timeout: 2 * time.Second}}
@@ -309,86 +634,59 @@ func newFrameworkHarnessTB(tb testing.TB, name string, opts harnessOptions) harn
 	}
 }
 
-func ginLikeAddrForMode(tb testing.TB, mode harnessMode) string {
+// ginLikeAddrForMode returns the server address for mode. Network mode binds
+// a listener up front and injects it through WithListener, so no port has to
+// be reserved and released.
+func ginLikeAddrForMode(tb testing.TB, mode harnessMode) (string, net.Listener) {
 	tb.Helper()
 	switch mode {
 	case harnessModeStartOnly:
-		return "127.0.0.1:0"
+		return "127.0.0.1:0", nil
 	case harnessModeNetwork:
-		return reserveAddrTB(tb)
+		ln := listenTB(tb)
+		return ln.Addr().String(), ln
 	default:
-		return ":0"
+		return ":0", nil
 	}
 }
 
-func hertzAddrForMode(tb testing.TB, mode harnessMode) string {
+func hertzAddrForMode(tb testing.TB, mode harnessMode) (string, net.Listener) {
 	tb.Helper()
 	switch mode {
-	case harnessModeInProcess:
-		return "127.0.0.1:0"
-	case harnessModeStartOnly:
-		return "127.0.0.1:0"
 	case harnessModeNetwork:
-		return reserveAddrTB(tb)
+		ln := listenTB(tb)
+		return ln.Addr().String(), ln
 	default:
-		return "127.0.0.1:0"
+		return "127.0.0.1:0", nil
 	}
 }
 
-func doHertzRequest(t *testing.T, h *server.Hertz, req *http.Request) responseSnapshot {
-	t.Helper()
-
-	urlStr := req.URL.String()
-	if !req.URL.IsAbs() {
-		urlStr = "http://example.com" + req.URL.RequestURI()
+func listenTB(tb testing.TB) net.Listener {
+	tb.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		tb.Fatalf("listen failed: %v", err)
 	}
+	return ln
+}
 
-	var bodyBytes []byte
-	if req.Body != nil {
-		var err error
-		bodyBytes, err = io.ReadAll(req.Body)
+// doEngineTest serves requests in-process with Engine.Test.
+func doEngineTest(engine httpx.Engine) func(*testing.T, *http.Request) responseSnapshot {
+	return func(t *testing.T, req *http.Request) responseSnapshot {
+		t.Helper()
+		resp, err := engine.Test(req)
 		if err != nil {
-			t.Fatalf("read request body: %v", err)
-		}
-	}
-
-	hctx := h.NewContext()
-	hctx.Request.Header.SetMethod(req.Method)
-	hctx.Request.SetRequestURI(urlStr)
-	if len(bodyBytes) > 0 {
-		hctx.Request.SetBodyStream(bytes.NewReader(bodyBytes), len(bodyBytes))
-	}
-	for key, values := range req.Header {
-		for _, value := range values {
-			hctx.Request.Header.Add(key, value)
+			t.Fatalf("%T test request failed: %v", engine, err)
 		}
+		return snapshotFromHTTPResponse(t, resp)
 	}
-
-	h.ServeHTTP(context.Background(), hctx)
-
-	hdr := make(http.Header)
-	hctx.Response.Header.VisitAll(func(k, v []byte) {
-		hdr.Add(textproto.CanonicalMIMEHeaderKey(string(k)), string(v))
-	})
-	for _, setCookie := range hctx.Response.Header.GetAll("Set-Cookie") {
-		hdr.Add("Set-Cookie", setCookie)
-	}
-
-	return responseSnapshot{Status: hctx.Response.StatusCode(), Body: string(hctx.Response.Body()), Headers: hdr}
 }
 
 func snapshotFromHTTPResponse(t *testing.T, resp *http.Response) responseSnapshot {
 	t.Helper()
-	defer func() {
-		_ = resp.Body.Close
-	}()
-	body, err := io.ReadAll(resp.Body)
+	snapshot, err := ReadResponse(resp)
 	if err != nil {
-		t.Fatalf("read response body: %v", err)
-	}
-	h := make(http.Header, len(resp.Header))
-	for key, values := range resp.Header {
-		h[key] = append([]string(nil), values...)
+		t.Fatal(err)
 	}
-	return responseSnapshot{Status: resp.StatusCode, Body: string(body), Headers: h}
+	return snapshot
 }
@@ -0,0 +1,53 @@
+package conformance
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-sphere/httpx"
+	"github.com/go-sphere/httpx/lambdax"
+)
+
+func TestEngineTestConformance(t *testing.T) {
+	engines := map[string]httpx.Engine{"lambdax": lambdax.New()}
+	for _, name := range conformanceFrameworks {
+		engines[name] = newHarness(t, name).Engine
+	}
+	for name, engine := range engines {
+		t.Run(name, func(t *testing.T) {
+			engine.Group("").POST("/echo/:id", func(ctx httpx.Context) error {
+				body, err := ctx.BodyRaw()
+				if err != nil {
+					return err
+				}
+				ctx.SetCookie(&http.Cookie{Name: "seen", Value: ctx.Param("id")})
+				return ctx.Text(http.StatusCreated, ctx.Header("X-Prefix")+" "+string(body))
+			})
+			if engine.IsRunning() {
+				t.Fatal("engine is running before Test")
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/echo/7", strings.NewReader("gopher"))
+			req.Header.Set("X-Prefix", "hello")
+			resp, err := engine.Test(req)
+			if err != nil {
+				t.Fatalf("Test: %v", err)
+			}
+			defer func() { _ = resp.Body.Close() }()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("read body: %v", err)
+			}
+			if resp.StatusCode != http.StatusCreated || string(body) != "hello gopher" {
+				t.Fatalf("response = %d %q", resp.StatusCode, body)
+			}
+			if cookies := resp.Cookies(); len(cookies) != 1 || cookies[0].Name != "seen" || cookies[0].Value != "7" {
+				t.Fatalf("cookies = %v", cookies)
+			}
+
+		})
+	}
+}
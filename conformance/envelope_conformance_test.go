@@ -0,0 +1,92 @@
+package conformance
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-sphere/httpx"
+	"github.com/go-sphere/httpx/echox"
+	"github.com/go-sphere/httpx/fiberx"
+	"github.com/go-sphere/httpx/ginx"
+	"github.com/go-sphere/httpx/hertzx"
+	"github.com/go-sphere/httpx/lambdax"
+)
+
+func TestEnvelopeConformance(t *testing.T) {
+	register := func(r httpx.Router) {
+		r.GET("/items", func(ctx httpx.Context) error {
+			return httpx.OK(ctx, []string{"a", "b"}, httpx.Meta{"total": 2}, httpx.Meta{"page": 1})
+		})
+		r.POST("/items", func(ctx httpx.Context) error {
+			return httpx.Created(ctx, "/items/7", map[string]int{"id": 7})
+		})
+		r.GET("/conflict", func(ctx httpx.Context) error {
+			httpx.EnvelopeErrorHandler(ctx, httpx.NewError(http.StatusConflict, 4091, "version mismatch", nil))
+			return nil
+		})
+	}
+
+	t.Run("default", func(t *testing.T) {
+		tests := []struct {
+			method, path string
+			wantStatus   int
+			wantBody     string
+		}{
+			{http.MethodGet, "/items", http.StatusOK, `{"data":["a","b"],"meta":{"page":1,"total":2}}`},
+			{http.MethodPost, "/items", http.StatusCreated, `{"data":{"id":7}}`},
+			{http.MethodGet, "/conflict", http.StatusConflict, `{"data":null,"error":{"code":4091,"message":"version mismatch"}}`},
+		}
+		for _, tc := range tests {
+			results := runAcrossFrameworks(t, register, func() *http.Request {
+				return httptest.NewRequest(tc.method, tc.path, nil)
+			})
+			got := results["ginx"]
+			if got.Status != tc.wantStatus || got.Body != tc.wantBody {
+				t.Fatalf("%s %s = %d %s, want %d %s", tc.method, tc.path, got.Status, got.Body, tc.wantStatus, tc.wantBody)
+			}
+			if tc.method == http.MethodPost && got.Headers.Get("Location") != "/items/7" {
+				t.Fatalf("Location = %q", got.Headers.Get("Location"))
+			}
+		}
+	})
+
+	t.Run("engine format", func(t *testing.T) {
+		format := func(ctx httpx.Context, env httpx.Envelope) any {
+			if env.Error != nil {
+				return map[string]any{"ok": false, "message": env.Error.Message}
+			}
+			return map[string]any{"ok": true, "result": env.Data}
+		}
+		engines := map[string]httpx.Engine{
+			"lambdax": lambdax.New(lambdax.WithEnvelope(format)),
+		}
+		for _, name := range conformanceFrameworks {
+			engines[name] = newEphemeralEngine(t, name,
+				ginx.WithEnvelope(format), fiberx.WithEnvelope(format), echox.WithEnvelope(format), hertzx.WithEnvelope(format))
+		}
+		for name, engine := range engines {
+			register(engine.Group(""))
+			do := doEngineTest(engine)
+			if got := do(t, httptest.NewRequest(http.MethodGet, "/items", nil)); strings.TrimSpace(got.Body) != `{"ok":true,"result":["a","b"]}` {
+				t.Fatalf("%s: GET /items = %d %s", name, got.Status, got.Body)
+			}
+			if got := do(t, httptest.NewRequest(http.MethodGet, "/conflict", nil)); got.Status != http.StatusConflict || strings.TrimSpace(got.Body) != `{"message":"version mismatch","ok":false}` {
+				t.Fatalf("%s: GET /conflict = %d %s", name, got.Status, got.Body)
+			}
+		}
+	})
+
+	t.Run("error handler", func(t *testing.T) {
+		engine := newEphemeralEngine(t, "ginx", ginx.WithErrorHandler(ginx.AdaptErrorHandler(httpx.EnvelopeErrorHandler)))
+		engine.Group("").POST("/users", func(ctx httpx.Context) error {
+			return &httpx.ValidationError{Errors: []httpx.FieldError{{Field: "Name", Rule: "required", Message: "Name failed the required rule"}}}
+		})
+		got := doEngineTest(engine)(t, httptest.NewRequest(http.MethodPost, "/users", nil))
+		want := `{"data":null,"error":{"code":422,"message":"invalid request: Name failed the required rule","errors":[{"field":"Name","rule":"required","message":"Name failed the required rule"}]}}`
+		if got.Status != http.StatusUnprocessableEntity || got.Body != want {
+			t.Fatalf("response = %d %s\nwant %s", got.Status, got.Body, want)
+		}
+	})
+}
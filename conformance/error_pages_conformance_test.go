@@ -0,0 +1,82 @@
+package conformance
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-sphere/httpx"
+	"github.com/go-sphere/httpx/echox"
+	"github.com/go-sphere/httpx/fiberx"
+	"github.com/go-sphere/httpx/ginx"
+	"github.com/go-sphere/httpx/hertzx"
+	"github.com/go-sphere/httpx/lambdax"
+)
+
+func TestHTMLErrorPagesConformance(t *testing.T) {
+	pages := fstest.MapFS{
+		"404.html": {Data: []byte("<h1>Not here</h1>")},
+		"5xx.html": {Data: []byte("<h1>Broken</h1>")},
+	}
+	engines := map[string]func(t *testing.T) httpx.Engine{
+		"lambdax": func(*testing.T) httpx.Engine { return lambdax.New(lambdax.WithHTMLErrorPages(pages)) },
+	}
+	for _, name := range conformanceFrameworks {
+		engines[name] = func(t *testing.T) httpx.Engine {
+			return newEphemeralEngine(t, name,
+				ginx.WithHTMLErrorPages(pages),
+				fiberx.WithHTMLErrorPages(pages),
+				echox.WithHTMLErrorPages(pages),
+				hertzx.WithHTMLErrorPages(pages),
+			)
+		}
+	}
+	const browser = "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"
+	tests := []struct {
+		name, path, accept string
+		wantStatus         int
+		wantPage           string
+	}{
+		{name: "unmatched browser", path: "/missing", accept: browser, wantStatus: http.StatusNotFound, wantPage: "<h1>Not here</h1>"},
+		{name: "error browser", path: "/fail", accept: browser, wantStatus: http.StatusInternalServerError, wantPage: "<h1>Broken</h1>"},
+		{name: "error api", path: "/fail", accept: "application/json"},
+		{name: "error curl", path: "/fail", accept: "*/*"},
+		{name: "no page", path: "/forbidden", accept: browser},
+		{name: "ok browser", path: "/ok", accept: browser, wantStatus: http.StatusOK},
+	}
+	for name, newEngine := range engines {
+		t.Run(name, func(t *testing.T) {
+			engine := newEngine(t)
+			r := engine.Group("")
+			r.GET("/ok", func(ctx httpx.Context) error {
+				return ctx.Text(http.StatusOK, "ok")
+			})
+			r.GET("/fail", func(ctx httpx.Context) error {
+				return errors.New("boom")
+			})
+			r.GET("/forbidden", func(ctx httpx.Context) error {
+				return httpx.NewForbiddenError("no")
+			})
+			do := doEngineTest(engine)
+			for _, tc := range tests {
+				req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+				req.Header.Set("Accept", tc.accept)
+				got := do(t, req)
+				if tc.wantPage == "" {
+					if got.Headers.Get("Content-Type") == "text/html; charset=utf-8" {
+						t.Fatalf("%s: got an HTML page %q", tc.name, got.Body)
+					}
+					if tc.wantStatus != 0 && got.Status != tc.wantStatus {
+						t.Fatalf("%s: status %d, want %d", tc.name, got.Status, tc.wantStatus)
+					}
+					continue
+				}
+				if got.Status != tc.wantStatus || got.Body != tc.wantPage || got.Headers.Get("Content-Type") != "text/html; charset=utf-8" {
+					t.Fatalf("%s: status %d, type %q, body %q", tc.name, got.Status, got.Headers.Get("Content-Type"), got.Body)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package conformance
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/go-sphere/httpx"
+)
+
+// registerErr returns the error register panics with, or nil.
+func registerErr(register func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			var ok bool
+			if err, ok = r.(error); !ok {
+				err = fmt.Errorf("panic: %v", r)
+			}
+		}
+	}()
+	register()
+	return nil
+}
+
+func TestFreezeConformance(t *testing.T) {
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			testFreeze(t, newHarness(t, name))
+		})
+	}
+	t.Run("lambdax", func(t *testing.T) {
+		testFreeze(t, newLambdaHarness(t))
+	})
+}
+
+func testFreeze(t *testing.T, h frameworkHarness) {
+	t.Helper()
+	h.Router.GET("/before", func(ctx httpx.Context) error {
+		return ctx.Text(http.StatusOK, "before")
+	})
+	h.Engine.Freeze()
+
+	api := h.Router.Group("/api")
+	for _, tc := range []struct {
+		name     string
+		register func()
+	}{
+		{"GET", func() { h.Router.GET("/after", func(ctx httpx.Context) error { return nil }) }},
+		{"Any", func() { api.Any("/after", func(ctx httpx.Context) error { return nil }) }},
+		{"StaticFS", func() { h.Router.StaticFS("/assets", os.DirFS(t.TempDir())) }},
+	} {
+		if err := registerErr(tc.register); !errors.Is(err, httpx.ErrRoutesFrozen) {
+			t.Fatalf("%s after Freeze: got %v, want a panic with ErrRoutesFrozen", tc.name, err)
+		}
+	}
+
+	resp := h.Do(t, httptest.NewRequest(http.MethodGet, "http://example.com/before", nil))
+	if resp.Status != http.StatusOK || resp.Body != "before" {
+		t.Fatalf("route registered before Freeze: status %d, body %q", resp.Status, resp.Body)
+	}
+}
+
+func TestStartFreezesRoutesConformance(t *testing.T) {
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			b := newFrameworkHarnessTB(t, name, harnessOptions{mode: harnessModeNetwork, silenceHertzLog: true})
+			b.harness.Router.GET("/__ready", func(ctx httpx.Context) error {
+				return ctx.NoContent(http.StatusNoContent)
+			})
+			startNetworkHarness(t, b)
+			t.Cleanup(func() { _ = b.harness.Engine.Stop(t.Context()) })
+
+			err := registerErr(func() {
+				b.harness.Router.GET("/late", func(ctx httpx.Context) error { return nil })
+			})
+			if !errors.Is(err, httpx.ErrRoutesFrozen) {
+				t.Fatalf("route registered after Start: got %v, want a panic with ErrRoutesFrozen", err)
+			}
+		})
+	}
+}
+
+func TestRuntimeRoutesConformance(t *testing.T) {
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			b := newFrameworkHarnessTB(t, name, harnessOptions{mode: harnessModeNetwork, silenceHertzLog: true, runtimeRoutes: true})
+			b.harness.Router.GET("/__ready", func(ctx httpx.Context) error {
+				return ctx.NoContent(http.StatusNoContent)
+			})
+			startNetworkHarness(t, b)
+			t.Cleanup(func() { _ = b.harness.Engine.Stop(t.Context()) })
+
+			b.harness.Router.GET("/late", func(ctx httpx.Context) error {
+				return ctx.Text(http.StatusOK, "late")
+			})
+			req, err := http.NewRequest(http.MethodGet, b.baseURL+"/late", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			status, err := doRequest(b.client, req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if status != http.StatusOK {
+				t.Fatalf("route registered after Start: status %d, want 200", status)
+			}
+		})
+	}
+}
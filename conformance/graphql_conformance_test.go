@@ -0,0 +1,152 @@
+package conformance
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-sphere/httpx"
+	"github.com/go-sphere/httpx/graphqlx"
+)
+
+// graphQLEcho stands in for a GraphQL server: it answers a query with the
+// query itself, an upload with the name of the file, and upgrades websocket
+// requests.
+var graphQLEcho = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Upgrade") == "websocket" {
+		conn, rw, err := http.NewResponseController(w).Hijack()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer conn.Close()
+		_, _ = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n\r\nnext")
+		_ = rw.Flush()
+		return
+	}
+	var echo string
+	switch {
+	case r.Method == http.MethodGet:
+		echo = r.URL.Query().Get("query")
+	case strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data"):
+		_, header, err := r.FormFile("0")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		echo = header.Filename
+	default:
+		var params struct {
+			Query string `json:"query"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		echo = params.Query
+	}
+	w.Header().Set("Content-Type", "application/graphql-response+json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]string{"echo": echo}})
+})
+
+func TestGraphQLMountConformance(t *testing.T) {
+	var upload bytes.Buffer
+	mw := multipart.NewWriter(&upload)
+	_ = mw.WriteField("operations", `{"query":"mutation($file: Upload!) { upload(file: $file) }","variables":{"file":null}}`)
+	_ = mw.WriteField("map", `{"0":["variables.file"]}`)
+	part, _ := mw.CreateFormFile("0", "avatar.png")
+	_, _ = part.Write([]byte("png"))
+	_ = mw.Close()
+
+	requests := map[string]func() *http.Request{
+		"get": func() *http.Request {
+			return httptest.NewRequest(http.MethodGet, "/graphql?query=%7Bme%7D", nil)
+		},
+		"post": func() *http.Request {
+			req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{"query":"{ me }"}`))
+			req.Header.Set("Content-Type", "application/json")
+			return req
+		},
+		"upload": func() *http.Request {
+			req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(upload.Bytes()))
+			req.Header.Set("Content-Type", mw.FormDataContentType())
+			return req
+		},
+	}
+	want := map[string]string{"get": "{me}", "post": "{ me }", "upload": "avatar.png"}
+	for name, request := range requests {
+		t.Run(name, func(t *testing.T) {
+			results := runAcrossFrameworks(t, func(r httpx.Router) {
+				graphqlx.Mount(r, "/graphql", graphQLEcho)
+			}, request)
+			assertMatchesGin(t, results)
+			if got := results["ginx"]; got.Status != http.StatusOK || !strings.Contains(got.Body, `"echo":"`+want[name]+`"`) {
+				t.Fatalf("status %d, body %q", got.Status, got.Body)
+			}
+		})
+	}
+}
+
+func TestGraphQLWebSocketConformance(t *testing.T) {
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			engine := newEphemeralEngine(t, name)
+			errs := make(chan error, 1)
+			engine.Use(func(ctx httpx.Context) error {
+				err := ctx.Next()
+				select {
+				case errs <- err:
+				default:
+				}
+				return err
+			})
+			graphqlx.Mount(engine.Group(""), "/graphql", graphQLEcho)
+			startErrCh := make(chan error, 1)
+			go func() {
+				startErrCh <- engine.Start()
+			}()
+			addr := waitBoundAddr(t, engine, startErrCh).String()
+			t.Cleanup(func() { _ = engine.Stop(t.Context()) })
+
+			conn, err := net.Dial("tcp", addr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer conn.Close()
+			_, _ = io.WriteString(conn, "GET /graphql HTTP/1.1\r\nHost: example.com\r\nConnection: Upgrade\r\nUpgrade: websocket\r\n\r\n")
+			reader := bufio.NewReader(conn)
+			resp, err := http.ReadResponse(reader, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if name == "fiberx" || name == "hertzx" {
+				_ = resp.Body.Close()
+				var handlerErr error
+				select {
+				case handlerErr = <-errs:
+				case <-time.After(5 * time.Second):
+					t.Fatal("handler did not return")
+				}
+				if resp.StatusCode == http.StatusSwitchingProtocols || !errors.Is(handlerErr, graphqlx.ErrWebSocketUnsupported) {
+					t.Fatalf("status %d, error %v", resp.StatusCode, handlerErr)
+				}
+				return
+			}
+			if resp.StatusCode != http.StatusSwitchingProtocols {
+				t.Fatalf("status %d, want 101", resp.StatusCode)
+			}
+			if rest, _ := io.ReadAll(reader); string(rest) != "next" {
+				t.Fatalf("upgraded connection read %q", rest)
+			}
+		})
+	}
+}
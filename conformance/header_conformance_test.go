@@ -0,0 +1,109 @@
+package conformance
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/go-sphere/httpx"
+)
+
+// headerRequest sends header lines a net/http client would not produce:
+// repeated keys in different cases and several Cookie lines.
+const headerRequest = "POST /headers HTTP/1.1\r\n" +
+	"Host: example.com\r\n" +
+	"x-multi: a\r\n" +
+	"X-MULTI: b\r\n" +
+	"X-Comma: a, b\r\n" +
+	"Cookie: a=1\r\n" +
+	"Cookie: b=2\r\n" +
+	"Content-Type: text/plain\r\n" +
+	"Content-Length: 3\r\n" +
+	"Connection: close\r\n" +
+	"\r\n" +
+	"abc"
+
+func TestHeaderConformance(t *testing.T) {
+	const want = `{"all":["Connection=close","Content-Length=3","Content-Type=text/plain","Cookie=a=1; b=2","X-Comma=a, b","X-Multi=a","X-Multi=b"],` +
+		`"comma":["a, b"],"cookie":"a=1; b=2","cookies":["a=1; b=2"],` +
+		`"headers":{"Connection":["close"],"Content-Length":["3"],"Content-Type":["text/plain"],"Cookie":["a=1; b=2"],"X-Comma":["a, b"],"X-Multi":["a","b"]},` +
+		`"host":"example.com","hostValues":["example.com"],"missing":null,"multi":"a","multiValues":["a","b"]}`
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			b := newFrameworkHarnessTB(t, name, harnessOptions{mode: harnessModeNetwork, errorMode: harnessErrorDefault, silenceHertzLog: true})
+			b.harness.Router.GET("/__ready", func(ctx httpx.Context) error {
+				return ctx.NoContent(http.StatusNoContent)
+			})
+			b.harness.Router.POST("/headers", func(ctx httpx.Context) error {
+				var all []string
+				for key, value := range ctx.AllHeaders() {
+					all = append(all, key+"="+value)
+				}
+				slices.Sort(all)
+				ctx.SetCookie(&http.Cookie{Name: "a", Value: "1"})
+				ctx.SetCookie(&http.Cookie{Name: "b", Value: "2"})
+				ctx.SetHeader("X-Comma", "a, b")
+				return ctx.JSON(http.StatusOK, map[string]any{
+					"multi":       ctx.Header("X-Multi"),
+					"multiValues": ctx.HeaderValues("x-MULTI"),
+					"comma":       ctx.HeaderValues("x-comma"),
+					"cookie":      ctx.Header("cookie"),
+					"cookies":     ctx.HeaderValues("Cookie"),
+					"host":        ctx.Header("host"),
+					"hostValues":  ctx.HeaderValues("Host"),
+					"missing":     ctx.HeaderValues("X-Missing"),
+					"headers":     ctx.Headers(),
+					"all":         all,
+				})
+			})
+			startErrCh := startNetworkHarness(t, b)
+			t.Cleanup(func() {
+				_ = b.harness.Engine.Stop(t.Context())
+				<-startErrCh
+			})
+
+			conn, err := net.Dial("tcp", strings.TrimPrefix(b.baseURL, "http://"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() {
+				_ = conn.Close()
+			}()
+			if _, err := io.WriteString(conn, headerRequest); err != nil {
+				t.Fatal(err)
+			}
+			resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() {
+				_ = resp.Body.Close()
+			}()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got, expected any
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("decode %s: %v", body, err)
+			}
+			_ = json.Unmarshal([]byte(want), &expected)
+			gotJSON, _ := json.Marshal(got)
+			wantJSON, _ := json.Marshal(expected)
+			if string(gotJSON) != string(wantJSON) {
+				t.Fatalf("request headers:\n got %s\nwant %s", gotJSON, wantJSON)
+			}
+			if cookies := resp.Cookies(); len(cookies) != 2 || cookies[0].Name != "a" || cookies[1].Name != "b" {
+				t.Fatalf("Set-Cookie = %q, want one line per cookie", resp.Header.Values("Set-Cookie"))
+			}
+			if comma := resp.Header.Values("X-Comma"); !slices.Equal(comma, []string{"a, b"}) {
+				t.Fatalf("X-Comma = %q, want the comma-joined value kept", comma)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package conformance
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-sphere/httpx"
+)
+
+func TestHealthConformance(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		failing    bool
+		wantStatus int
+	}{
+		{name: "Liveness", path: "/healthz", wantStatus: http.StatusOK},
+		{name: "ReadinessOK", path: "/readyz", wantStatus: http.StatusOK},
+		{name: "ReadinessFailing", path: "/readyz", failing: true, wantStatus: http.StatusServiceUnavailable},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			results := runAcrossFrameworks(t, func(r httpx.Router) {
+				health := httpx.Health(r, httpx.HealthOptions{})
+				health.AddCheck("db", func(context.Context) error {
+					if tc.failing {
+						return errors.New("db unreachable")
+					}
+					return nil
+				})
+			}, func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "http://example.com"+tc.path, nil)
+			})
+			assertMatchesGin(t, results)
+			if got := results["ginx"].Status; got != tc.wantStatus {
+				t.Fatalf("status = %d, want %d", got, tc.wantStatus)
+			}
+		})
+	}
+}
+
+func TestHealthShutdownConformance(t *testing.T) {
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			h := newHarness(t, name)
+			health := httpx.Health(h.Router, httpx.HealthOptions{Engine: h.Engine})
+
+			req := func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "http://example.com/readyz", nil)
+			}
+			if got := h.Do(t, req()); got.Status != http.StatusOK {
+				t.Fatalf("%s readyz before stop = %d, want %d", name, got.Status, http.StatusOK)
+			}
+
+			_ = h.Engine.Stop(context.Background())
+
+			if got := health.Readiness(context.Background()).Status; got != httpx.HealthStatusShuttingDown {
+				t.Fatalf("%s readiness after stop = %q, want %q", name, got, httpx.HealthStatusShuttingDown)
+			}
+			got := h.Do(t, req())
+			if got.Status != http.StatusServiceUnavailable {
+				t.Fatalf("%s readyz after stop = %d, want %d", name, got.Status, http.StatusServiceUnavailable)
+			}
+			assertJSONBodyEqual(t, name, `{"status":"shutting_down"}`, got.Body)
+		})
+	}
+}
+
+func TestGRPCHealthConformance(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		body       string
+		query      string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "Server", method: http.MethodPost, body: `{}`, wantStatus: http.StatusOK, wantBody: `{"status":"NOT_SERVING"}`},
+		{name: "Serving", method: http.MethodPost, body: `{"service":"db"}`, wantStatus: http.StatusOK, wantBody: `{"status":"SERVING"}`},
+		{name: "NotServing", method: http.MethodPost, body: `{"service":"cache"}`, wantStatus: http.StatusOK, wantBody: `{"status":"NOT_SERVING"}`},
+		{name: "Unknown", method: http.MethodPost, body: `{"service":"queue"}`, wantStatus: http.StatusNotFound, wantBody: `{"status":"SERVICE_UNKNOWN"}`},
+		{name: "Query", method: http.MethodGet, query: "?service=db", wantStatus: http.StatusOK, wantBody: `{"status":"SERVING"}`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			results := runAcrossFrameworks(t, func(r httpx.Router) {
+				health := httpx.Health(r, httpx.HealthOptions{})
+				health.AddCheck("db", func(context.Context) error { return nil })
+				health.AddCheck("cache", func(context.Context) error { return errors.New("cache unreachable") })
+				health.MountGRPC(r)
+			}, func() *http.Request {
+				req := httptest.NewRequest(tc.method, "http://example.com"+httpx.GRPCHealthCheckPath+tc.query, strings.NewReader(tc.body))
+				req.Header.Set("Content-Type", "application/json")
+				return req
+			})
+			assertMatchesGin(t, results)
+			if got := results["ginx"]; got.Status != tc.wantStatus || strings.TrimSpace(got.Body) != tc.wantBody {
+				t.Fatalf("status %d, body %q, want %d %q", got.Status, got.Body, tc.wantStatus, tc.wantBody)
+			}
+		})
+	}
+}
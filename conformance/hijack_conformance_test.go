@@ -0,0 +1,118 @@
+package conformance
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-sphere/httpx"
+)
+
+var netHTTPFrameworks = map[string]bool{"ginx": true, "echox": true}
+
+func TestFlusherHijackerSupportConformance(t *testing.T) {
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			h := newHarness(t, name)
+			var flusher, hijacker, informational bool
+			h.Router.GET("/caps", func(ctx httpx.Context) error {
+				_, flusher = httpx.AsFlusher(ctx)
+				_, hijacker = httpx.AsHijacker(ctx)
+				_, informational = httpx.AsInformationalWriter(ctx)
+				return ctx.NoContent(http.StatusNoContent)
+			})
+			h.Do(t, httptest.NewRequest(http.MethodGet, "http://example.com/caps", nil))
+			want := netHTTPFrameworks[name]
+			if flusher != want || hijacker != want || informational != want {
+				t.Fatalf("flusher=%v hijacker=%v informational=%v, want %v", flusher, hijacker, informational, want)
+			}
+		})
+	}
+}
+
+func TestFlushConformance(t *testing.T) {
+	results := make(map[string]responseSnapshot)
+	for name := range netHTTPFrameworks {
+		h := newHarness(t, name)
+		h.Router.GET("/stream", func(ctx httpx.Context) error {
+			interop, _ := httpx.AsHTTPInterop(ctx)
+			flusher, _ := httpx.AsFlusher(ctx)
+			ctx.SetHeader("Content-Type", "text/event-stream")
+			ctx.Status(http.StatusAccepted)
+			for _, event := range []string{"one", "two"} {
+				if _, err := io.WriteString(interop.HTTPResponseWriter(), "data: "+event+"\n\n"); err != nil {
+					return err
+				}
+				if err := flusher.Flush(); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		results[name] = h.Do(t, httptest.NewRequest(http.MethodGet, "http://example.com/stream", nil))
+	}
+	assertResponseLikeGin(t, "echox", results["ginx"], results["echox"])
+	if got := results["ginx"]; got.Status != http.StatusAccepted || got.Body != "data: one\n\ndata: two\n\n" {
+		t.Fatalf("response = %d %q", got.Status, got.Body)
+	}
+}
+
+func TestHijackConformance(t *testing.T) {
+	for name := range netHTTPFrameworks {
+		t.Run(name, func(t *testing.T) {
+			b := newFrameworkHarnessTB(t, name, harnessOptions{mode: harnessModeNetwork, errorMode: harnessErrorDefault})
+			b.harness.Router.GET("/__ready", func(ctx httpx.Context) error {
+				return ctx.NoContent(http.StatusNoContent)
+			})
+			b.harness.Router.GET("/tunnel", func(ctx httpx.Context) error {
+				hijacker, _ := httpx.AsHijacker(ctx)
+				conn, rw, err := hijacker.Hijack()
+				if err != nil {
+					return err
+				}
+				defer conn.Close()
+				_, _ = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: echo\r\nConnection: Upgrade\r\n\r\n")
+				_ = rw.Flush()
+				line, err := rw.ReadString('\n')
+				if err != nil {
+					return err
+				}
+				_, _ = rw.WriteString("echo: " + line)
+				return rw.Flush()
+			})
+			startNetworkHarness(t, b)
+			t.Cleanup(func() { _ = b.harness.Engine.Stop(t.Context()) })
+
+			conn, err := net.DialTimeout("tcp", strings.TrimPrefix(b.baseURL, "http://"), time.Second)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer conn.Close()
+			_ = conn.SetDeadline(time.Now().Add(2 * time.Second))
+			_, _ = io.WriteString(conn, "GET /tunnel HTTP/1.1\r\nHost: example.com\r\nConnection: Upgrade\r\nUpgrade: echo\r\n\r\n")
+
+			reader := bufio.NewReader(conn)
+			resp, err := http.ReadResponse(reader, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != http.StatusSwitchingProtocols {
+				t.Fatalf("status = %d, want 101", resp.StatusCode)
+			}
+			_, _ = io.WriteString(conn, "ping\n")
+			line, err := reader.ReadString('\n')
+			if err != nil && !errors.Is(err, io.EOF) {
+				t.Fatal(err)
+			}
+			if line != "echo: ping\n" {
+				t.Fatalf("tunnel read %q", line)
+			}
+		})
+	}
+}
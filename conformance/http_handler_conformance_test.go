@@ -0,0 +1,156 @@
+package conformance
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-sphere/httpx"
+)
+
+// runHTTPHandlerLikeGin serves handler through the ginx baseline at ginPath and
+// through httpx.ToHTTPHandler on an http.ServeMux at muxPattern, and asserts
+// both responses match.
+func runHTTPHandlerLikeGin(t *testing.T, method, ginPath, muxPattern string, handler httpx.Handler, request func() *http.Request) responseSnapshot {
+	t.Helper()
+	h := newHarness(t, "ginx")
+	h.Router.Handle(method, ginPath, handler)
+	want := h.Do(t, request())
+
+	mux := http.NewServeMux()
+	mux.Handle(method+" "+muxPattern, httpx.ToHTTPHandler(handler))
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, request())
+	got := snapshotFromHTTPResponse(t, rr.Result())
+	assertResponseLikeGin(t, "net/http", want, got)
+	return got
+}
+
+func TestHTTPHandlerConformance(t *testing.T) {
+	t.Run("RequestInfo", func(t *testing.T) {
+		runHTTPHandlerLikeGin(t, http.MethodGet, "/users/:id", "/users/{id}", func(ctx httpx.Context) error {
+			name, _ := ctx.Cookie("session")
+			return ctx.JSON(http.StatusOK, map[string]any{
+				"method": ctx.Method(),
+				"path":   ctx.Path(),
+				"id":     ctx.Param("id"),
+				"params": ctx.Params(),
+				"q":      ctx.Query("q"),
+				"tags":   ctx.Queries()["tag"],
+				"raw":    ctx.RawQuery(),
+				"header": ctx.Header("X-Test"),
+				"cookie": name,
+			})
+		}, func() *http.Request {
+			req := httptest.NewRequest(http.MethodGet, "http://example.com/users/42?q=go&tag=a&tag=b", nil)
+			req.Header.Set("X-Test", "yes")
+			req.AddCookie(&http.Cookie{Name: "session", Value: "abc"})
+			return req
+		})
+	})
+
+	t.Run("Binders", func(t *testing.T) {
+		type input struct {
+			ID     int      `uri:"id"`
+			Page   int      `query:"page" form:"page"`
+			Tags   []string `query:"tag" form:"tag"`
+			Token  string   `header:"x-token"`
+			Active *bool    `query:"active" form:"active"`
+		}
+		runHTTPHandlerLikeGin(t, http.MethodPost, "/items/:id", "/items/{id}", func(ctx httpx.Context) error {
+			var uri, query, form, header input
+			if err := errors.Join(ctx.BindURI(&uri), ctx.BindQuery(&query), ctx.BindForm(&form), ctx.BindHeader(&header)); err != nil {
+				return err
+			}
+			return ctx.JSON(http.StatusOK, map[string]any{"uri": uri.ID, "query": query, "form": form, "header": header.Token})
+		}, func() *http.Request {
+			req := httptest.NewRequest(http.MethodPost, "http://example.com/items/7?page=2&tag=x&tag=y&active=true", strings.NewReader("page=3&tag=z"))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			req.Header.Set("X-Token", "secret")
+			return req
+		})
+	})
+
+	t.Run("Responders", func(t *testing.T) {
+		runHTTPHandlerLikeGin(t, http.MethodGet, "/text", "/text", func(ctx httpx.Context) error {
+			ctx.SetHeader("X-Trace", "t-1")
+			ctx.SetCookie(&http.Cookie{Name: "seen", Value: "1", Path: "/"})
+			return ctx.Text(http.StatusAccepted, "accepted")
+		}, func() *http.Request {
+			return httptest.NewRequest(http.MethodGet, "http://example.com/text", nil)
+		})
+		runHTTPHandlerLikeGin(t, http.MethodGet, "/status", "/status", func(ctx httpx.Context) error {
+			ctx.Status(http.StatusCreated)
+			return nil
+		}, func() *http.Request {
+			return httptest.NewRequest(http.MethodGet, "http://example.com/status", nil)
+		})
+		runHTTPHandlerLikeGin(t, http.MethodGet, "/redirect", "/redirect", func(ctx httpx.Context) error {
+			return ctx.Redirect(http.StatusFound, "/target")
+		}, func() *http.Request {
+			return httptest.NewRequest(http.MethodGet, "http://example.com/redirect", nil)
+		})
+	})
+
+	t.Run("DefaultErrorHandler", func(t *testing.T) {
+		runHTTPHandlerLikeGin(t, http.MethodGet, "/fail", "/fail", func(ctx httpx.Context) error {
+			return errors.New("boom")
+		}, func() *http.Request {
+			return httptest.NewRequest(http.MethodGet, "http://example.com/fail", nil)
+		})
+	})
+
+	t.Run("MiddlewareAndState", func(t *testing.T) {
+		var order []string
+		handler := httpx.ToHTTPHandler(func(ctx httpx.Context) error {
+			order = append(order, "handler")
+			v, _ := ctx.Get("user")
+			return ctx.Text(http.StatusOK, v.(string)+" "+ctx.FullPath())
+		}, httpx.WithHTTPMiddleware(func(ctx httpx.Context) error {
+			order = append(order, "before")
+			ctx.Set("user", "alice")
+			err := ctx.Next()
+			ri, ok := httpx.AsResponseInfo(ctx)
+			if !ok || ri.StatusCode() != http.StatusOK {
+				t.Errorf("response info after Next = %v", ok)
+			}
+			if rs, ok := httpx.AsResponseSizer(ctx); !ok || rs.BodySize() == 0 {
+				t.Errorf("response size after Next = %v", ok)
+			}
+			order = append(order, "after")
+			return err
+		}), httpx.WithHTTPErrorHandler(func(ctx httpx.Context, err error) {
+			t.Errorf("unexpected error: %v", err)
+		}))
+
+		mux := http.NewServeMux()
+		mux.Handle("GET example.com/users/{id}", handler)
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://example.com/users/1", nil))
+		if rr.Body.String() != "alice /users/{id}" {
+			t.Fatalf("body = %q", rr.Body.String())
+		}
+		if got := strings.Join(order, ","); got != "before,handler,after" {
+			t.Fatalf("order = %s", got)
+		}
+	})
+
+	t.Run("MiddlewareShortCircuit", func(t *testing.T) {
+		handler := httpx.ToHTTPHandler(func(ctx httpx.Context) error {
+			t.Errorf("handler should not run")
+			return nil
+		}, httpx.WithHTTPMiddleware(func(ctx httpx.Context) error {
+			return httpx.NewUnauthorizedError("denied")
+		}), httpx.WithHTTPErrorHandler(func(ctx httpx.Context, err error) {
+			_, status, _ := httpx.ParseError(err)
+			_ = ctx.Text(int(status), err.Error())
+		}))
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://example.com/", nil))
+		if rr.Code != http.StatusUnauthorized || rr.Body.String() != "denied" {
+			t.Fatalf("got %d %q", rr.Code, rr.Body.String())
+		}
+	})
+}
@@ -0,0 +1,129 @@
+package conformance
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-sphere/httpx"
+)
+
+type httpMiddlewareKey struct{}
+
+func TestWrapHTTPMiddlewareConformance(t *testing.T) {
+	tagRequest := httpx.WrapHTTPMiddleware(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Trace", "from-net-http")
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), httpMiddlewareKey{}, "tagged")))
+		})
+	})
+	requireToken := httpx.WrapHTTPMiddleware(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("X-Token") == "" {
+				http.Error(w, "missing token", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	})
+	handler := func(ctx httpx.Context) error {
+		v, _ := ctx.Context().Value(httpMiddlewareKey{}).(string)
+		return ctx.Text(http.StatusOK, v)
+	}
+
+	t.Run("ContextAndHeaders", func(t *testing.T) {
+		results := runAcrossFrameworks(t, func(r httpx.Router) {
+			r.Use(tagRequest)
+			r.GET("/wrap/tag", handler)
+		}, func() *http.Request {
+			return httptest.NewRequest(http.MethodGet, "http://example.com/wrap/tag", nil)
+		})
+		assertMatchesGin(t, results)
+		if got := results["ginx"]; got.Body != "tagged" || got.Headers.Get("X-Trace") != "from-net-http" {
+			t.Fatalf("ginx baseline = %d %q %v", got.Status, got.Body, got.Headers)
+		}
+	})
+
+	t.Run("ShortCircuit", func(t *testing.T) {
+		results := runAcrossFrameworks(t, func(r httpx.Router) {
+			r.Use(requireToken)
+			r.GET("/wrap/secure", handler)
+		}, func() *http.Request {
+			return httptest.NewRequest(http.MethodGet, "http://example.com/wrap/secure", nil)
+		})
+		assertMatchesGin(t, results)
+		if got := results["ginx"]; got.Status != http.StatusForbidden {
+			t.Fatalf("ginx baseline status = %d", got.Status)
+		}
+	})
+
+	t.Run("MultiValueHeaders", func(t *testing.T) {
+		addHeaders := httpx.WrapHTTPMiddleware(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Add("Vary", "Origin")
+				w.Header().Add("Vary", "Accept-Encoding")
+				w.Header().Add("Set-Cookie", "a=1")
+				w.Header().Add("Set-Cookie", "b=2")
+				next.ServeHTTP(w, r)
+			})
+		})
+		for _, name := range conformanceFrameworks {
+			h := newHarness(t, name)
+			h.Router.Use(addHeaders)
+			h.Router.GET("/wrap/vary", handler)
+			got := h.Do(t, httptest.NewRequest(http.MethodGet, "http://example.com/wrap/vary", nil))
+			if vary := strings.Join(got.Headers.Values("Vary"), ", "); vary != "Origin, Accept-Encoding" {
+				t.Fatalf("%s Vary = %q, want both values", name, vary)
+			}
+			if cookies := got.Headers.Values("Set-Cookie"); len(cookies) != 2 {
+				t.Fatalf("%s Set-Cookie = %q, want both cookies", name, cookies)
+			}
+		}
+	})
+
+	t.Run("WriterWrapping", func(t *testing.T) {
+		upper := httpx.WrapHTTPMiddleware(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				next.ServeHTTP(upperWriter{w}, r)
+			})
+		})
+		for _, name := range conformanceFrameworks {
+			h := newHarness(t, name)
+			interop := false
+			h.Router.Use(upper)
+			h.Router.GET("/wrap/upper", func(ctx httpx.Context) error {
+				_, interop = httpx.AsHTTPInterop(ctx)
+				return ctx.Text(http.StatusOK, "hello")
+			})
+			got := h.Do(t, httptest.NewRequest(http.MethodGet, "http://example.com/wrap/upper", nil))
+			if !interop {
+				if name == "ginx" || name == "echox" {
+					t.Fatalf("%s should support net/http interop", name)
+				}
+				continue
+			}
+			if got.Body != "HELLO" {
+				t.Fatalf("%s wrapped body = %q, want HELLO", name, got.Body)
+			}
+		}
+
+		rr := httptest.NewRecorder()
+		httpx.ToHTTPHandler(func(ctx httpx.Context) error {
+			return ctx.Text(http.StatusOK, "hello")
+		}, httpx.WithHTTPMiddleware(upper)).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://example.com/", nil))
+		if rr.Body.String() != "HELLO" {
+			t.Fatalf("net/http wrapped body = %q, want HELLO", rr.Body.String())
+		}
+	})
+}
+
+type upperWriter struct {
+	http.ResponseWriter
+}
+
+func (w upperWriter) Write(b []byte) (int, error) {
+	return w.ResponseWriter.Write(bytes.ToUpper(b))
+}
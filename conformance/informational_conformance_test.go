@@ -0,0 +1,81 @@
+package conformance
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"net/textproto"
+	"reflect"
+	"testing"
+
+	"github.com/go-sphere/httpx"
+)
+
+var earlyHintLinks = []string{"</app.css>; rel=preload; as=style", "</app.js>; rel=preload; as=script"}
+
+func registerEarlyHints(r httpx.Router) {
+	r.GET("/__ready", func(ctx httpx.Context) error {
+		return ctx.NoContent(http.StatusNoContent)
+	})
+	r.GET("/hints", func(ctx httpx.Context) error {
+		if err := httpx.EarlyHints(ctx, earlyHintLinks); err != nil {
+			return err
+		}
+		return ctx.Text(http.StatusOK, "page")
+	})
+}
+
+func TestEarlyHintsConformance(t *testing.T) {
+	t.Run("NoOpWithoutCapability", func(t *testing.T) {
+		results := runAcrossFrameworks(t, registerEarlyHints, func() *http.Request {
+			return httptest.NewRequest(http.MethodGet, "http://example.com/hints", nil)
+		})
+		for name := range results {
+			if name == "ginx" || name == "echox" {
+				// httptest recorders take the 103 for the final status.
+				continue
+			}
+			if got := results[name]; got.Status != http.StatusOK || got.Body != "page" {
+				t.Fatalf("%s response = %d %q", name, got.Status, got.Body)
+			}
+		}
+	})
+
+	for name := range netHTTPFrameworks {
+		t.Run(name, func(t *testing.T) {
+			b := newFrameworkHarnessTB(t, name, harnessOptions{mode: harnessModeNetwork, errorMode: harnessErrorDefault})
+			registerEarlyHints(b.harness.Router)
+			startNetworkHarness(t, b)
+			t.Cleanup(func() { _ = b.harness.Engine.Stop(t.Context()) })
+
+			var hints []string
+			trace := &httptrace.ClientTrace{
+				Got1xxResponse: func(code int, header textproto.MIMEHeader) error {
+					if code == http.StatusEarlyHints {
+						hints = append(hints, header.Values("Link")...)
+					}
+					return nil
+				},
+			}
+			req, err := http.NewRequestWithContext(httptrace.WithClientTrace(t.Context(), trace), http.MethodGet, b.baseURL+"/hints", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := b.client.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("status = %d, want 200", resp.StatusCode)
+			}
+			if !reflect.DeepEqual(hints, earlyHintLinks) {
+				t.Fatalf("early hints = %q, want %q", hints, earlyHintLinks)
+			}
+			if link := resp.Header.Get("Link"); link != "" {
+				t.Fatalf("final response carries Link %q", link)
+			}
+		})
+	}
+}
@@ -0,0 +1,240 @@
+package conformance
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/cloudwego/hertz/pkg/app/server"
+	"github.com/gin-gonic/gin"
+	"github.com/go-sphere/httpx"
+	"github.com/go-sphere/httpx/echox"
+	"github.com/go-sphere/httpx/fiberx"
+	"github.com/go-sphere/httpx/ginx"
+	"github.com/go-sphere/httpx/hertzx"
+	"github.com/go-sphere/httpx/middleware"
+	"github.com/gofiber/fiber/v3"
+	"github.com/labstack/echo/v4"
+)
+
+// syncBuffer is a bytes.Buffer safe for the server goroutines logging to it.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) take() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s := b.buf.String()
+	b.buf.Reset()
+	return s
+}
+
+func TestIPFilterConformance(t *testing.T) {
+	office, err := middleware.ParseCIDRs("10.0.0.0/8", "192.0.2.7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	loopback, err := middleware.ParseCIDRs("127.0.0.0/8", "::1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			var logs syncBuffer
+			logger := middleware.WithIPFilterLogger(slog.New(slog.NewTextHandler(&logs, nil)))
+			engine := newEphemeralEngine(t, name)
+			errs := make(chan error, 1)
+			engine.Use(func(ctx httpx.Context) error {
+				err := ctx.Next()
+				errs <- err
+				return err
+			})
+			ok := func(ctx httpx.Context) error {
+				return ctx.Text(http.StatusOK, "ok")
+			}
+			admin := engine.Group("/admin", middleware.IPFilter(office, nil, middleware.IPFilterEnforce, logger))
+			admin.GET("/enforced", ok)
+			admin.GET("/report", ok).Meta("ipfilter", "report")
+			admin.GET("/off", ok).Meta("ipfilter", "off")
+			engine.Group("/local", middleware.IPFilter(loopback, nil, middleware.IPFilterEnforce, logger)).GET("/", ok)
+			engine.Group("/blocked", middleware.IPFilter(nil, loopback, middleware.IPFilterEnforce, logger)).GET("/", ok)
+
+			startErrCh := make(chan error, 1)
+			go func() {
+				startErrCh <- engine.Start()
+			}()
+			addr := waitBoundAddr(t, engine, startErrCh).String()
+			t.Cleanup(func() { _ = engine.Stop(t.Context()) })
+			get := func(path string) (int, error) {
+				t.Helper()
+				resp, err := http.Get("http://" + addr + path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				_ = resp.Body.Close()
+				return resp.StatusCode, <-errs
+			}
+
+			if status, err := get("/local/"); status != http.StatusOK || err != nil {
+				t.Fatalf("allowed client: status %d, error %v", status, err)
+			}
+			if line := logs.take(); line != "" {
+				t.Fatalf("allowed client logged %q", line)
+			}
+			for _, tc := range []struct{ path, reason string }{
+				{"/admin/enforced", "not allowed"},
+				{"/blocked/", "denied"},
+			} {
+				status, err := get(tc.path)
+				var se httpx.StatusError
+				if status == http.StatusOK || !errors.Is(err, middleware.ErrIPRejected) || !errors.As(err, &se) || se.GetStatus() != http.StatusForbidden {
+					t.Fatalf("%s: status %d, error %v", tc.path, status, err)
+				}
+				line := logs.take()
+				if !strings.Contains(line, "ip=127.0.0.1") || !strings.Contains(line, "route="+tc.path) ||
+					!strings.Contains(line, tc.reason) || !strings.Contains(line, "mode=enforce") {
+					t.Fatalf("%s: log %q", tc.path, line)
+				}
+			}
+
+			if status, err := get("/admin/report"); status != http.StatusOK || err != nil {
+				t.Fatalf("report route: status %d, error %v", status, err)
+			}
+			if line := logs.take(); !strings.Contains(line, "mode=report") {
+				t.Fatalf("report route: log %q", line)
+			}
+			if status, err := get("/admin/off"); status != http.StatusOK || err != nil {
+				t.Fatalf("off route: status %d, error %v", status, err)
+			}
+			if line := logs.take(); line != "" {
+				t.Fatalf("off route logged %q", line)
+			}
+		})
+	}
+}
+
+func TestIPFilterForwardedConformance(t *testing.T) {
+	office, err := middleware.ParseCIDRs("10.0.0.0/8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	loopback, err := middleware.ParseCIDRs("127.0.0.0/8", "::1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	trustLoopback := []any{
+		ginx.WithTrustedProxies(loopback...),
+		echox.WithTrustedProxies(loopback...),
+		fiberx.WithTrustedProxies(loopback...),
+		hertzx.WithTrustedProxies(loopback...),
+	}
+	for _, tc := range []struct {
+		name      string
+		newEngine func(t *testing.T, name string) httpx.Engine
+		xff       string
+		// rejected is the client IP logged by IPFilter, empty when allowed.
+		rejected string
+	}{
+		{"spoofed by an untrusted peer", func(t *testing.T, name string) httpx.Engine {
+			return newBuiltEngine(t, name)
+		}, "10.1.2.3", "127.0.0.1"},
+		{"forwarded by a trusted proxy", func(t *testing.T, name string) httpx.Engine {
+			return newBuiltEngine(t, name, trustLoopback...)
+		}, "10.1.2.3", ""},
+		{"spoofed through a trusted proxy", func(t *testing.T, name string) httpx.Engine {
+			return newBuiltEngine(t, name, trustLoopback...)
+		}, "10.1.2.3, 203.0.113.9", "203.0.113.9"},
+		// Supplied engines keep the trusted proxies of their framework,
+		// here loopback peers.
+		{"forwarded to a supplied engine", newSuppliedEngine, "10.1.2.3", ""},
+	} {
+		for _, name := range conformanceFrameworks {
+			t.Run(tc.name+"/"+name, func(t *testing.T) {
+				var logs syncBuffer
+				logger := middleware.WithIPFilterLogger(slog.New(slog.NewTextHandler(&logs, nil)))
+				engine := tc.newEngine(t, name)
+				engine.Group("/admin", middleware.IPFilter(office, nil, middleware.IPFilterEnforce, logger)).GET("/", func(ctx httpx.Context) error {
+					return ctx.Text(http.StatusOK, ctx.ClientIP())
+				})
+
+				startErrCh := make(chan error, 1)
+				go func() {
+					startErrCh <- engine.Start()
+				}()
+				addr := waitBoundAddr(t, engine, startErrCh).String()
+				t.Cleanup(func() { _ = engine.Stop(t.Context()) })
+				req, err := http.NewRequest(http.MethodGet, "http://"+addr+"/admin/", nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				req.Header.Set("X-Forwarded-For", tc.xff)
+				resp, err := http.DefaultClient.Do(req)
+				if err != nil {
+					t.Fatal(err)
+				}
+				body, _ := io.ReadAll(resp.Body)
+				_ = resp.Body.Close()
+				line := logs.take()
+				if tc.rejected == "" {
+					if resp.StatusCode != http.StatusOK || string(body) != "10.1.2.3" || line != "" {
+						t.Fatalf("response = %d %q, log %q, want ClientIP 10.1.2.3", resp.StatusCode, body, line)
+					}
+					return
+				}
+				if resp.StatusCode == http.StatusOK || !strings.Contains(line, "ip="+tc.rejected+" ") {
+					t.Fatalf("response = %d %q, log %q, want %s rejected", resp.StatusCode, body, line, tc.rejected)
+				}
+			})
+		}
+	}
+}
+
+// newBuiltEngine is newEphemeralEngine with the gin engine built by ginx,
+// which newEphemeralEngine supplies instead.
+func newBuiltEngine(t *testing.T, name string, opts ...any) httpx.Engine {
+	if name != "ginx" {
+		return newEphemeralEngine(t, name, opts...)
+	}
+	gin.SetMode(gin.ReleaseMode)
+	return ginx.New(append([]ginx.Option{ginx.WithServerAddr("127.0.0.1:0")}, optionsOf[ginx.Option](opts)...)...)
+}
+
+// newSuppliedEngine returns an engine over a framework engine of its own,
+// trusting the forwarding headers of loopback peers.
+func newSuppliedEngine(t *testing.T, name string) httpx.Engine {
+	const addr = "127.0.0.1:0"
+	switch name {
+	case "ginx":
+		return newEphemeralEngine(t, name)
+	case "fiberx":
+		app := fiber.New(fiber.Config{
+			TrustProxy:       true,
+			TrustProxyConfig: fiber.TrustProxyConfig{Loopback: true},
+			ProxyHeader:      fiber.HeaderXForwardedFor,
+		})
+		return fiberx.New(fiberx.WithEngine(app), fiberx.WithListen(addr, fiber.ListenConfig{DisableStartupMessage: true}))
+	case "echox":
+		return echox.New(echox.WithEngine(echo.New()), echox.WithServerAddr(addr))
+	case "hertzx":
+		// Custom hertz engines on port 0 report their address only with a
+		// listener of their own.
+		h := server.Default(server.WithListener(listenTB(t)), server.WithDisablePrintRoute(true))
+		return hertzx.New(hertzx.WithEngine(h))
+	default:
+		t.Fatalf("unknown framework: %s", name)
+		return nil
+	}
+}
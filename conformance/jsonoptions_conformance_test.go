@@ -0,0 +1,45 @@
+package conformance
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-sphere/httpx"
+	"github.com/go-sphere/httpx/echox"
+	"github.com/go-sphere/httpx/fiberx"
+	"github.com/go-sphere/httpx/ginx"
+	"github.com/go-sphere/httpx/hertzx"
+	"github.com/go-sphere/httpx/lambdax"
+)
+
+func TestJSONOptionsConformance(t *testing.T) {
+	opts := httpx.JSONOptions{Omit: httpx.JSONOmitNull, FieldName: httpx.SnakeCase}
+	engines := map[string]httpx.Engine{
+		"lambdax": lambdax.New(lambdax.WithJSONOptions(opts)),
+	}
+	for _, name := range conformanceFrameworks {
+		engines[name] = newEphemeralEngine(t, name,
+			ginx.WithJSONOptions(opts), fiberx.WithJSONOptions(opts), echox.WithJSONOptions(opts), hertzx.WithJSONOptions(opts))
+	}
+	const want = `{"user_id":7,"display_name":"<b>","next_page":"","data":{"item_count":1}}`
+	for name, engine := range engines {
+		engine.Group("").GET("/user", func(ctx httpx.Context) error {
+			return httpx.OK(ctx, struct {
+				UserID      int
+				DisplayName string
+				NextPage    string
+				Deleted     *string
+				Items       map[string]int `json:"data"`
+			}{UserID: 7, DisplayName: "<b>", Items: map[string]int{"itemCount": 1}})
+		})
+		got := doEngineTest(engine)(t, httptest.NewRequest(http.MethodGet, "/user", nil))
+		if got.Status != http.StatusOK || got.Body != `{"data":`+want+`}` {
+			t.Fatalf("%s: GET /user = %d %s", name, got.Status, got.Body)
+		}
+		if ct := got.Headers.Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+			t.Fatalf("%s: Content-Type = %q", name, ct)
+		}
+	}
+}
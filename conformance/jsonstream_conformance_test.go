@@ -0,0 +1,82 @@
+package conformance
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-sphere/httpx"
+)
+
+type streamedRow struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestJSONArrayStreamConformance(t *testing.T) {
+	const rows = 5000
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			engine := newEphemeralEngine(t, name)
+			r := engine.Group("")
+			release := make(chan struct{})
+			r.GET("/rows", func(ctx httpx.Context) error {
+				wait := ctx.Query("wait") != ""
+				return httpx.JSONArrayStream(ctx, http.StatusOK, func(yield func(streamedRow) bool) {
+					for i := range rows {
+						if i == rows/2 && wait {
+							<-release
+						}
+						if !yield(streamedRow{ID: i, Name: "row"}) {
+							return
+						}
+					}
+				})
+			})
+			r.GET("/empty", func(ctx httpx.Context) error {
+				return httpx.JSONArrayStream(ctx, http.StatusAccepted, func(yield func(int) bool) {})
+			})
+
+			do := doEngineTest(engine)
+			got := do(t, httptest.NewRequest(http.MethodGet, "/rows", nil))
+			var decoded []streamedRow
+			if err := json.Unmarshal([]byte(got.Body), &decoded); err != nil {
+				t.Fatalf("status %d, body not an array: %v", got.Status, err)
+			}
+			if got.Status != http.StatusOK || !strings.HasPrefix(got.Headers.Get("Content-Type"), "application/json") || len(decoded) != rows || decoded[rows-1].ID != rows-1 {
+				t.Fatalf("status %d, %d rows, headers %v", got.Status, len(decoded), got.Headers)
+			}
+			if got := do(t, httptest.NewRequest(http.MethodGet, "/empty", nil)); got.Status != http.StatusAccepted || got.Body != "[]" {
+				t.Fatalf("empty stream = %d %q", got.Status, got.Body)
+			}
+
+			// The first rows reach the client while the sequence is still
+			// running.
+			startErrCh := make(chan error, 1)
+			go func() {
+				startErrCh <- engine.Start()
+			}()
+			addr := waitBoundAddr(t, engine, startErrCh).String()
+			t.Cleanup(func() { _ = engine.Stop(t.Context()) })
+			resp, err := http.Get("http://" + addr + "/rows?wait=1")
+			if err != nil {
+				close(release)
+				t.Fatal(err)
+			}
+			defer func() { _ = resp.Body.Close() }()
+			first := make([]byte, 1024)
+			_, err = io.ReadFull(resp.Body, first)
+			close(release)
+			if err != nil || !strings.HasPrefix(string(first), `[{"id":0,"name":"row"}`) {
+				t.Fatalf("first bytes %q, error %v", first, err)
+			}
+			rest, err := io.ReadAll(resp.Body)
+			if err != nil || !json.Valid(append(first, rest...)) {
+				t.Fatalf("streamed body invalid, error %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package conformance
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/go-sphere/httpx"
+	"github.com/go-sphere/httpx/lambdax"
+)
+
+// newLambdaHarness serves requests through lambdax.Engine.HandleRequest by
+// translating them into API Gateway v2 HTTP events, base64 encoding bodies.
+func newLambdaHarness(t *testing.T) frameworkHarness {
+	t.Helper()
+	engine := lambdax.New().(*lambdax.Engine)
+	return frameworkHarness{
+		Name:   "lambdax",
+		Engine: engine,
+		Router: engine.Group(""),
+		Do: func(t *testing.T, req *http.Request) responseSnapshot {
+			t.Helper()
+			resp, err := engine.HandleRequest(context.Background(), eventFromRequest(t, req))
+			if err != nil {
+				t.Fatalf("lambdax HandleRequest failed: %v", err)
+			}
+			return snapshotFromLambdaResponse(t, resp)
+		},
+	}
+}
+
+func eventFromRequest(t *testing.T, req *http.Request) events.APIGatewayV2HTTPRequest {
+	t.Helper()
+	event := events.APIGatewayV2HTTPRequest{
+		RawPath:        req.URL.EscapedPath(),
+		RawQueryString: req.URL.RawQuery,
+		Headers:        map[string]string{"host": req.Host},
+		Cookies:        nil,
+	}
+	event.RequestContext.HTTP.Method = req.Method
+	event.RequestContext.HTTP.Path = req.URL.Path
+	event.RequestContext.HTTP.SourceIP = "203.0.113.7"
+	for key, values := range req.Header {
+		if key == "Cookie" {
+			for _, value := range values {
+				event.Cookies = append(event.Cookies, strings.Split(value, "; ")...)
+			}
+			continue
+		}
+		event.Headers[strings.ToLower(key)] = strings.Join(values, ",")
+	}
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			t.Fatalf("read request body: %v", err)
+		}
+		if len(body) > 0 {
+			event.Body = base64.StdEncoding.EncodeToString(body)
+			event.IsBase64Encoded = true
+		}
+	}
+	return event
+}
+
+func snapshotFromLambdaResponse(t *testing.T, resp events.APIGatewayV2HTTPResponse) responseSnapshot {
+	t.Helper()
+	body := resp.Body
+	if resp.IsBase64Encoded {
+		decoded, err := base64.StdEncoding.DecodeString(resp.Body)
+		if err != nil {
+			t.Fatalf("decode response body: %v", err)
+		}
+		body = string(decoded)
+	}
+	headers := make(http.Header)
+	for key, value := range resp.Headers {
+		headers.Set(key, value)
+	}
+	for _, cookie := range resp.Cookies {
+		headers.Add("Set-Cookie", cookie)
+	}
+	return responseSnapshot{Status: resp.StatusCode, Body: body, Headers: headers}
+}
+
+// runLambdaLikeGin registers routes on the ginx baseline and on lambdax and
+// asserts both serve request identically.
+func runLambdaLikeGin(t *testing.T, register func(httpx.Router), request func() *http.Request) responseSnapshot {
+	t.Helper()
+	base := newHarness(t, "ginx")
+	register(base.Router)
+	want := base.Do(t, request())
+
+	h := newLambdaHarness(t)
+	register(h.Router)
+	got := h.Do(t, request())
+	assertResponseLikeGin(t, "lambdax", want, got)
+	return got
+}
+
+func TestLambdaConformance(t *testing.T) {
+	t.Run("RequestInfo", func(t *testing.T) {
+		runLambdaLikeGin(t, func(r httpx.Router) {
+			r.Group("/api").GET("/users/:id/*rest", func(ctx httpx.Context) error {
+				session, _ := ctx.Cookie("session")
+				return ctx.JSON(http.StatusOK, map[string]any{
+					"path":     ctx.Path(),
+					"fullPath": ctx.FullPath(),
+					"params":   ctx.Params(),
+					"tags":     ctx.Queries()["tag"],
+					"header":   ctx.Header("X-Test"),
+					"session":  session,
+					"cookies":  ctx.Cookies(),
+				})
+			})
+		}, func() *http.Request {
+			req := httptest.NewRequest(http.MethodGet, "http://example.com/api/users/42/a/b?tag=x&tag=y", nil)
+			req.Header.Set("X-Test", "yes")
+			req.AddCookie(&http.Cookie{Name: "session", Value: "abc"})
+			req.AddCookie(&http.Cookie{Name: "theme", Value: "dark"})
+			return req
+		})
+	})
+
+	t.Run("Body", func(t *testing.T) {
+		runLambdaLikeGin(t, func(r httpx.Router) {
+			r.POST("/echo", func(ctx httpx.Context) error {
+				var payload map[string]any
+				if err := ctx.BindJSON(&payload); err != nil {
+					return err
+				}
+				return ctx.JSON(http.StatusCreated, payload)
+			})
+		}, func() *http.Request {
+			req := httptest.NewRequest(http.MethodPost, "http://example.com/echo", strings.NewReader(`{"name":"gopher"}`))
+			req.Header.Set("Content-Type", "application/json")
+			return req
+		})
+	})
+
+	t.Run("BinaryResponseAndCookies", func(t *testing.T) {
+		got := runLambdaLikeGin(t, func(r httpx.Router) {
+			r.GET("/bin", func(ctx httpx.Context) error {
+				ctx.SetCookie(&http.Cookie{Name: "a", Value: "1"})
+				ctx.SetCookie(&http.Cookie{Name: "b", Value: "2"})
+				return ctx.Bytes(http.StatusOK, []byte{0xff, 0x00, 0xfe}, "application/octet-stream")
+			})
+		}, func() *http.Request {
+			return httptest.NewRequest(http.MethodGet, "http://example.com/bin", nil)
+		})
+		if len(got.Headers.Values("Set-Cookie")) != 2 {
+			t.Fatalf("lambdax cookies = %v", got.Headers.Values("Set-Cookie"))
+		}
+	})
+
+	t.Run("ErrorHandler", func(t *testing.T) {
+		runLambdaLikeGin(t, func(r httpx.Router) {
+			r.GET("/fail", func(ctx httpx.Context) error {
+				return httpx.NewBadRequestError("boom")
+			})
+		}, func() *http.Request {
+			return httptest.NewRequest(http.MethodGet, "http://example.com/fail", nil)
+		})
+	})
+
+	t.Run("StaticFS", func(t *testing.T) {
+		files := fstest.MapFS{"hello.txt": {Data: []byte("hello")}}
+		runLambdaLikeGin(t, func(r httpx.Router) {
+			r.StaticFS("/assets", files)
+		}, func() *http.Request {
+			return httptest.NewRequest(http.MethodGet, "http://example.com/assets/hello.txt", nil)
+		})
+	})
+
+	t.Run("MiddlewareAndRoutes", func(t *testing.T) {
+		h := newLambdaHarness(t)
+		var routes []httpx.RouteInfo
+		h.Engine.OnRouteRegistered(func(info httpx.RouteInfo) {
+			routes = append(routes, info)
+		})
+		h.Engine.Use(func(ctx httpx.Context) error {
+			ctx.SetHeader("X-Trace", "engine")
+			return ctx.Next()
+		})
+		api := h.Engine.Group("/api", func(ctx httpx.Context) error {
+			event, ok := lambdax.EventFromContext(ctx.Context())
+			if !ok || event.RequestContext.HTTP.SourceIP != ctx.ClientIP() {
+				return httpx.NewForbiddenError("no event")
+			}
+			return ctx.Next()
+		})
+		api.GET("/ping", func(ctx httpx.Context) error {
+			return ctx.Text(http.StatusOK, "pong")
+		})
+
+		got := h.Do(t, httptest.NewRequest(http.MethodGet, "http://example.com/api/ping", nil))
+		if got.Status != http.StatusOK || got.Body != "pong" || got.Headers.Get("X-Trace") != "engine" {
+			t.Fatalf("lambdax response = %d %q %v", got.Status, got.Body, got.Headers)
+		}
+		if len(routes) != 1 || routes[0] != (httpx.RouteInfo{Method: http.MethodGet, Path: "/api/ping"}) {
+			t.Fatalf("routes = %v", routes)
+		}
+		if err := h.Engine.Start(); err != lambdax.ErrNotInLambda {
+			t.Fatalf("Start outside Lambda = %v, want ErrNotInLambda", err)
+		}
+	})
+}
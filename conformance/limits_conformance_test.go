@@ -0,0 +1,122 @@
+package conformance
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-sphere/httpx"
+	"github.com/go-sphere/httpx/echox"
+	"github.com/go-sphere/httpx/fiberx"
+	"github.com/go-sphere/httpx/ginx"
+	"github.com/go-sphere/httpx/hertzx"
+)
+
+// startLimitedEngine starts an engine built with opts that serves /ok, and
+// returns its address.
+func startLimitedEngine(t *testing.T, name string, opts ...any) string {
+	t.Helper()
+	engine := newEphemeralEngine(t, name, opts...)
+	engine.Group("").GET("/ok", func(ctx httpx.Context) error {
+		return ctx.Text(http.StatusOK, "ok")
+	})
+	startErrCh := make(chan error, 1)
+	go func() {
+		startErrCh <- engine.Start()
+	}()
+	addr := waitBoundAddr(t, engine, startErrCh).String()
+	t.Cleanup(func() { _ = engine.Stop(t.Context()) })
+	return addr
+}
+
+// getOK sends GET /ok on conn and returns the response status, or 0 when
+// the connection fails or times out.
+func getOK(conn net.Conn, header string) int {
+	req := "GET /ok HTTP/1.1\r\nHost: example.com\r\n" + header + "\r\n"
+	if _, err := io.WriteString(conn, req); err != nil {
+		return 0
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	if err != nil {
+		return 0
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	return resp.StatusCode
+}
+
+func dialTB(t *testing.T, addr string, timeout time.Duration) net.Conn {
+	t.Helper()
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		t.Fatal(err)
+	}
+	return conn
+}
+
+func TestMaxHeaderBytesConformance(t *testing.T) {
+	const limit = 4 << 10
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			addr := startLimitedEngine(t, name,
+				ginx.WithMaxHeaderBytes(limit),
+				fiberx.WithMaxHeaderBytes(limit),
+				echox.WithMaxHeaderBytes(limit),
+				hertzx.WithMaxHeaderBytes(limit),
+			)
+			if status := getOK(dialTB(t, addr, 3*time.Second), "X-Small: 1\r\n"); status != http.StatusOK {
+				t.Fatalf("small headers: status %d, want 200", status)
+			}
+			big := "X-Big: " + strings.Repeat("a", 32<<10) + "\r\n"
+			if status := getOK(dialTB(t, addr, 3*time.Second), big); status == http.StatusOK {
+				t.Fatalf("headers over the limit were served")
+			}
+		})
+	}
+}
+
+func TestConcurrencyLimitConformance(t *testing.T) {
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			addr := startLimitedEngine(t, name,
+				ginx.WithConcurrencyLimit(1),
+				fiberx.WithConcurrencyLimit(1),
+				echox.WithConcurrencyLimit(1),
+				hertzx.WithConcurrencyLimit(1),
+			)
+			first := dialTB(t, addr, 3*time.Second)
+			if status := getOK(first, ""); status != http.StatusOK {
+				t.Fatalf("first connection: status %d, want 200", status)
+			}
+			// The first connection is kept alive, so a second one is not
+			// served until it is closed.
+			// Probes are closed right away, or the server would keep them.
+			probe := func(timeout time.Duration) int {
+				conn := dialTB(t, addr, timeout)
+				defer func() {
+					_ = conn.Close()
+				}()
+				return getOK(conn, "")
+			}
+			if status := probe(300 * time.Millisecond); status == http.StatusOK {
+				t.Fatalf("second connection served while the first is open")
+			}
+			_ = first.Close()
+			deadline := time.Now().Add(3 * time.Second)
+			for probe(500*time.Millisecond) != http.StatusOK {
+				if time.Now().After(deadline) {
+					t.Fatalf("connection not served after the first was closed")
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+		})
+	}
+}
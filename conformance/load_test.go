@@ -0,0 +1,191 @@
+package conformance
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-sphere/httpx"
+)
+
+var (
+	loadDuration    = flag.Duration("load.duration", 0, "run the load test against each adapter for this long; 0 skips it")
+	loadConcurrency = flag.Int("load.concurrency", 16, "number of clients sending requests during the load test")
+	loadPayload     = flag.Int("load.payload", 1024, "size in bytes of the request body echoed by the load test route")
+	loadJSON        = flag.String("load.json", "", "write the load test reports to this file as JSON")
+)
+
+// loadReport is the result of the load test for one adapter. Latencies are
+// in milliseconds.
+type loadReport struct {
+	Framework         string  `json:"framework"`
+	Concurrency       int     `json:"concurrency"`
+	PayloadBytes      int     `json:"payload_bytes"`
+	DurationSeconds   float64 `json:"duration_seconds"`
+	Requests          int     `json:"requests"`
+	Errors            int     `json:"errors"`
+	RequestsPerSecond float64 `json:"requests_per_second"`
+	P50               float64 `json:"p50_ms"`
+	P95               float64 `json:"p95_ms"`
+	P99               float64 `json:"p99_ms"`
+	Max               float64 `json:"max_ms"`
+}
+
+// TestLoad measures throughput and latency of every adapter end to end, over
+// the network. It only runs when -load.duration is set:
+//
+//	go test -run TestLoad -v -load.duration 10s -load.concurrency 64 -load.json load.json
+func TestLoad(t *testing.T) {
+	if *loadDuration <= 0 {
+		t.Skip("set -load.duration to run the load test")
+	}
+	payload := bytes.Repeat([]byte("x"), *loadPayload)
+
+	var reports []loadReport
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			b := newFrameworkHarnessTB(t, name, harnessOptions{mode: harnessModeNetwork, errorMode: harnessErrorDefault, silenceHertzLog: true})
+			b.harness.Router.GET("/__ready", func(ctx httpx.Context) error {
+				return ctx.NoContent(http.StatusNoContent)
+			})
+			b.harness.Router.POST("/load", func(ctx httpx.Context) error {
+				body, err := ctx.BodyRaw()
+				if err != nil {
+					return err
+				}
+				return ctx.Bytes(http.StatusOK, body, "application/octet-stream")
+			})
+			startNetworkHarness(t, b)
+			t.Cleanup(func() { _ = b.harness.Engine.Stop(t.Context()) })
+
+			report := runLoad(b.baseURL+"/load", payload, *loadConcurrency, *loadDuration)
+			report.Framework = name
+			t.Logf("%s: %d requests, %d errors, %.0f req/s, p50 %.3fms, p95 %.3fms, p99 %.3fms, max %.3fms",
+				name, report.Requests, report.Errors, report.RequestsPerSecond, report.P50, report.P95, report.P99, report.Max)
+			if report.Requests == 0 {
+				t.Fatalf("no request succeeded")
+			}
+			reports = append(reports, report)
+		})
+	}
+
+	if *loadJSON != "" {
+		data, err := json.MarshalIndent(reports, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(*loadJSON, append(data, '\n'), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// runLoad sends payload to url from concurrency clients until duration has
+// elapsed. Requests that fail or do not echo the payload count as errors.
+func runLoad(url string, payload []byte, concurrency int, duration time.Duration) loadReport {
+	client := &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: &http.Transport{MaxIdleConnsPerHost: concurrency},
+	}
+	defer client.CloseIdleConnections()
+
+	var (
+		mu        sync.Mutex
+		latencies []time.Duration
+		errs      int
+		wg        sync.WaitGroup
+	)
+	start := time.Now()
+	deadline := start.Add(duration)
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var local []time.Duration
+			failed := 0
+			for time.Now().Before(deadline) {
+				began := time.Now()
+				if err := echoRequest(client, url, payload); err != nil {
+					failed++
+					continue
+				}
+				local = append(local, time.Since(began))
+			}
+			mu.Lock()
+			latencies = append(latencies, local...)
+			errs += failed
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	slices.Sort(latencies)
+	return loadReport{
+		Concurrency:       concurrency,
+		PayloadBytes:      len(payload),
+		DurationSeconds:   elapsed.Seconds(),
+		Requests:          len(latencies),
+		Errors:            errs,
+		RequestsPerSecond: float64(len(latencies)) / elapsed.Seconds(),
+		P50:               milliseconds(percentile(latencies, 50)),
+		P95:               milliseconds(percentile(latencies, 95)),
+		P99:               milliseconds(percentile(latencies, 99)),
+		Max:               milliseconds(percentile(latencies, 100)),
+	}
+}
+
+func echoRequest(client *http.Client, url string, payload []byte) error {
+	resp, err := client.Post(url, "application/octet-stream", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK || !bytes.Equal(body, payload) {
+		return fmt.Errorf("unexpected response: status %d, %d bytes", resp.StatusCode, len(body))
+	}
+	return nil
+}
+
+// percentile returns the nearest-rank percentile p of the sorted durations.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[max(rank, 1)-1]
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func TestPercentile(t *testing.T) {
+	var sorted []time.Duration
+	for i := 1; i <= 100; i++ {
+		sorted = append(sorted, time.Duration(i)*time.Millisecond)
+	}
+	for p, want := range map[float64]time.Duration{50: 50 * time.Millisecond, 95: 95 * time.Millisecond, 99: 99 * time.Millisecond, 100: 100 * time.Millisecond, 0: time.Millisecond} {
+		if got := percentile(sorted, p); got != want {
+			t.Errorf("percentile(%v) = %v, want %v", p, got, want)
+		}
+	}
+	if got := percentile(nil, 50); got != 0 {
+		t.Errorf("percentile of no durations = %v, want 0", got)
+	}
+}
@@ -0,0 +1,90 @@
+package conformance
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-sphere/httpx"
+	"github.com/go-sphere/httpx/lambdax"
+	"github.com/go-sphere/httpx/middleware"
+)
+
+func TestLocaleConformance(t *testing.T) {
+	messages := httpx.Messages{
+		"en": {"greeting": "Hello, %s"},
+		"de": {"greeting": "Hallo, %s"},
+		"fr": {"greeting": "Bonjour, %s"},
+	}
+	tests := []struct {
+		target         string
+		acceptLanguage string
+		want           string
+		wantLanguage   string
+	}{
+		{target: "/hello", acceptLanguage: "de-AT, en;q=0.8", want: "Hallo, Ada", wantLanguage: "de"},
+		{target: "/hello", acceptLanguage: "ja", want: "Hello, Ada", wantLanguage: "en"},
+		{target: "/hello?lang=fr", acceptLanguage: "de", want: "Bonjour, Ada", wantLanguage: "fr"},
+	}
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			h := newHarness(t, name)
+			h.Router.Use(middleware.Locale(messages, middleware.WithDefaultLocale("en"), middleware.WithLocaleQuery("lang")))
+			h.Router.GET("/hello", func(ctx httpx.Context) error {
+				return ctx.Text(http.StatusOK, httpx.T(ctx, "greeting", "Ada"))
+			})
+			for _, tt := range tests {
+				req := httptest.NewRequest(http.MethodGet, "http://example.com"+tt.target, nil)
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+				resp := h.Do(t, req)
+				if resp.Body != tt.want || resp.Headers.Get("Content-Language") != tt.wantLanguage {
+					t.Fatalf("GET %s with Accept-Language %q = %q, Content-Language %q, want %q, %q",
+						tt.target, tt.acceptLanguage, resp.Body, resp.Headers.Get("Content-Language"), tt.want, tt.wantLanguage)
+				}
+			}
+		})
+	}
+}
+
+func TestErrorLocalizationConformance(t *testing.T) {
+	httpx.RegisterErrorTranslator("de", httpx.ErrorMessages{
+		Errors: map[string]string{"access denied": "Zugriff verweigert"},
+		Rules:  map[string]string{"required": "%s ist erforderlich"},
+	})
+	messages := httpx.Messages{"en": {}, "de": {}}
+	engines := map[string]func(t *testing.T) httpx.Engine{
+		"lambdax": func(*testing.T) httpx.Engine { return lambdax.New() },
+	}
+	for _, name := range conformanceFrameworks {
+		engines[name] = func(t *testing.T) httpx.Engine { return newEphemeralEngine(t, name) }
+	}
+	for name, newEngine := range engines {
+		t.Run(name, func(t *testing.T) {
+			// The adapters' default error handlers localize errors.
+			engine := newEngine(t)
+			r := engine.Group("", middleware.Locale(messages))
+			r.GET("/denied", func(ctx httpx.Context) error {
+				return httpx.NewForbiddenError("access denied")
+			})
+			r.GET("/invalid", func(ctx httpx.Context) error {
+				return &httpx.ValidationError{Errors: []httpx.FieldError{{Field: "Name", Rule: "required", Message: "Name failed the required rule"}}}
+			})
+			for _, tt := range []struct {
+				target, language, want string
+			}{
+				{"/denied", "de-DE", `{"error":"Zugriff verweigert"}`},
+				{"/denied", "en", `{"error":"access denied"}`},
+				{"/invalid", "de", `"message":"Name ist erforderlich"`},
+				{"/invalid", "en", `"message":"Name failed the required rule"`},
+			} {
+				req := httptest.NewRequest(http.MethodGet, "http://example.com"+tt.target, nil)
+				req.Header.Set("Accept-Language", tt.language)
+				resp := doEngineTest(engine)(t, req)
+				if !strings.Contains(resp.Body, tt.want) {
+					t.Fatalf("GET %s in %s = %d %s, want %s", tt.target, tt.language, resp.Status, resp.Body, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package conformance
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-sphere/httpx"
+	"github.com/go-sphere/httpx/middleware"
+)
+
+func TestLoggerConformance(t *testing.T) {
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			base := slog.New(slog.NewJSONHandler(&buf, nil))
+			h := newHarness(t, name)
+			h.Router.Use(middleware.WithLogger(base))
+			h.Router.GET("/users/:id", func(ctx httpx.Context) error {
+				httpx.LoggerFrom(ctx).Info("handled")
+				if httpx.LoggerFromContext(ctx.Context()) == slog.Default() {
+					t.Errorf("%s logger not propagated through context.Context", name)
+				}
+				return ctx.NoContent(http.StatusNoContent)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "http://example.com/users/1", nil)
+			req.Header.Set("X-Request-ID", "req-1")
+			req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+			h.Do(t, req)
+
+			var record map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+				t.Fatalf("%s decode log record %q: %v", name, buf.String(), err)
+			}
+			want := map[string]string{
+				"msg":        "handled",
+				"request_id": "req-1",
+				"route":      "/users/:id",
+				"trace_id":   "4bf92f3577b34da6a3ce929d0e0e4736",
+			}
+			for key, value := range want {
+				if record[key] != value {
+					t.Fatalf("%s log %s = %v, want %q (record: %v)", name, key, record[key], value, record)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package conformance
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-sphere/httpx"
+	"github.com/go-sphere/httpx/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetricsConformance(t *testing.T) {
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			registry := prometheus.NewRegistry()
+			h := newHarness(t, name)
+			h.Router.Use(middleware.Metrics(registry))
+			h.Router.GET("/users/:id", func(ctx httpx.Context) error {
+				return ctx.Text(http.StatusOK, "user")
+			})
+			h.Router.GET("/invalid", func(ctx httpx.Context) error {
+				return ctx.JSON(http.StatusBadRequest, httpx.H{"error": "bad input"})
+			})
+			h.Router.GET("/fail", func(ctx httpx.Context) error {
+				return errors.New("boom")
+			})
+			middleware.MountMetrics(h.Router, "/metrics", registry)
+
+			for _, path := range []string{"/users/1", "/users/2", "/invalid", "/fail"} {
+				h.Do(t, httptest.NewRequest(http.MethodGet, "http://example.com"+path, nil))
+			}
+
+			if got := testutil.ToFloat64(counter(t, registry, "GET", "/users/:id", "2xx")); got != 2 {
+				t.Fatalf("%s 2xx counter = %v, want 2", name, got)
+			}
+			if got := testutil.ToFloat64(counter(t, registry, "GET", "/invalid", "4xx")); got != 1 {
+				t.Fatalf("%s 4xx counter = %v, want 1", name, got)
+			}
+			if got := testutil.ToFloat64(counter(t, registry, "GET", "/fail", "5xx")); got != 1 {
+				t.Fatalf("%s 5xx counter = %v, want 1", name, got)
+			}
+
+			resp := h.Do(t, httptest.NewRequest(http.MethodGet, "http://example.com/metrics", nil))
+			if resp.Status != http.StatusOK {
+				t.Fatalf("%s /metrics status = %d", name, resp.Status)
+			}
+			for _, want := range []string{
+				`http_requests_total{method="GET",route="/users/:id",status="2xx"} 2`,
+				`http_response_size_bytes_sum{method="GET",route="/users/:id",status="2xx"} 8`,
+				"http_request_duration_seconds_bucket",
+				"http_requests_in_flight 1",
+			} {
+				if !strings.Contains(resp.Body, want) {
+					t.Fatalf("%s /metrics body missing %q:\n%s", name, want, resp.Body)
+				}
+			}
+		})
+	}
+}
+
+func counter(t *testing.T, registry *prometheus.Registry, method, route, status string) prometheus.Collector {
+	t.Helper()
+	c := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Total number of HTTP requests handled.",
+	}, []string{"method", "route", "status"})
+	if err := registry.Register(c); err != nil {
+		var are prometheus.AlreadyRegisteredError
+		if !errors.As(err, &are) {
+			t.Fatalf("lookup counter failed: %v", err)
+		}
+		c = are.ExistingCollector.(*prometheus.CounterVec)
+	}
+	return c.WithLabelValues(method, route, status)
+}
@@ -0,0 +1,68 @@
+package conformance
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-sphere/httpx"
+	"github.com/go-sphere/httpx/middleware"
+)
+
+type mirroredRequest struct {
+	method, uri, header, body string
+}
+
+func TestMirrorConformance(t *testing.T) {
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			mirrored := make(chan mirroredRequest, 4)
+			shadow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				mirrored <- mirroredRequest{r.Method, r.RequestURI, r.Header.Get("X-Tenant"), string(body)}
+				w.WriteHeader(http.StatusTeapot)
+			}))
+			defer shadow.Close()
+			target, _ := url.Parse(shadow.URL + "/shadow")
+
+			engine := newEphemeralEngine(t, name)
+			engine.Use(middleware.Mirror(target, 1, middleware.WithMirrorFilter(func(ctx httpx.Context) bool {
+				return ctx.Method() != http.MethodGet
+			})))
+			r := engine.Group("")
+			handler := func(ctx httpx.Context) error {
+				body, err := ctx.BodyRaw()
+				if err != nil {
+					return err
+				}
+				return ctx.Text(http.StatusCreated, string(body))
+			}
+			r.GET("/orders", handler)
+			r.POST("/orders/:id", handler)
+
+			do := doEngineTest(engine)
+			if got := do(t, httptest.NewRequest(http.MethodGet, "http://example.com/orders", nil)); got.Status != http.StatusCreated {
+				t.Fatalf("filtered request: status %d", got.Status)
+			}
+			req := httptest.NewRequest(http.MethodPost, "http://example.com/orders/7?dry=1", strings.NewReader(`{"qty":2}`))
+			req.Header.Set("X-Tenant", "acme")
+			if got := do(t, req); got.Status != http.StatusCreated || got.Body != `{"qty":2}` {
+				t.Fatalf("mirrored request: status %d, body %q", got.Status, got.Body)
+			}
+
+			select {
+			case got := <-mirrored:
+				want := mirroredRequest{http.MethodPost, "/shadow/orders/7?dry=1", "acme", `{"qty":2}`}
+				if got != want {
+					t.Fatalf("shadow received %+v, want %+v", got, want)
+				}
+			case <-time.After(3 * time.Second):
+				t.Fatal("request was not mirrored")
+			}
+		})
+	}
+}
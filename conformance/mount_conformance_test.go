@@ -0,0 +1,70 @@
+package conformance
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-sphere/httpx"
+	"github.com/go-sphere/httpx/lambdax"
+)
+
+func TestMountEngineConformance(t *testing.T) {
+	children := map[string]func(t *testing.T) httpx.Engine{
+		"lambdax": func(*testing.T) httpx.Engine { return lambdax.New() },
+	}
+	for _, name := range conformanceFrameworks {
+		children[name] = func(t *testing.T) httpx.Engine { return newEphemeralEngine(t, name) }
+	}
+	for _, parent := range conformanceFrameworks {
+		for child, newChild := range children {
+			t.Run(parent+"/"+child, func(t *testing.T) {
+				engine := newChild(t)
+				engine.Use(func(ctx httpx.Context) error {
+					ctx.SetHeader("X-Child", "1")
+					return ctx.Next()
+				})
+				routes := engine.Group("")
+				routes.GET("/", func(ctx httpx.Context) error {
+					return ctx.Text(http.StatusOK, "root")
+				})
+				routes.GET("/users/:id", func(ctx httpx.Context) error {
+					return ctx.Text(http.StatusOK, ctx.Param("id")+" "+ctx.Path()+" "+ctx.Query("q"))
+				})
+				routes.POST("/echo", func(ctx httpx.Context) error {
+					body, err := ctx.BodyRaw()
+					if err != nil {
+						return err
+					}
+					return ctx.Text(http.StatusCreated, string(body))
+				})
+
+				h := newHarness(t, parent)
+				h.Router.Use(func(ctx httpx.Context) error {
+					ctx.SetHeader("X-Parent", "1")
+					return ctx.Next()
+				})
+				httpx.MountEngine(h.Router, "/admin", engine)
+
+				got := h.Do(t, httptest.NewRequest(http.MethodGet, "http://example.com/admin/users/7?q=x", nil))
+				if got.Status != http.StatusOK || got.Body != "7 /users/7 x" {
+					t.Fatalf("GET /admin/users/7: status %d, body %q", got.Status, got.Body)
+				}
+				if got.Headers.Get("X-Parent") != "1" || got.Headers.Get("X-Child") != "1" {
+					t.Fatalf("middleware headers = %v, want both engines' middleware to run", got.Headers)
+				}
+				got = h.Do(t, httptest.NewRequest(http.MethodPost, "http://example.com/admin/echo", strings.NewReader("hello")))
+				if got.Status != http.StatusCreated || got.Body != "hello" {
+					t.Fatalf("POST /admin/echo: status %d, body %q", got.Status, got.Body)
+				}
+				for _, path := range []string{"/admin", "/admin/"} {
+					got = h.Do(t, httptest.NewRequest(http.MethodGet, "http://example.com"+path, nil))
+					if got.Status != http.StatusOK || got.Body != "root" {
+						t.Fatalf("GET %s: status %d, body %q", path, got.Status, got.Body)
+					}
+				}
+			})
+		}
+	}
+}
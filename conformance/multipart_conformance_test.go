@@ -0,0 +1,119 @@
+package conformance
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/go-sphere/httpx"
+	"github.com/go-sphere/httpx/echox"
+	"github.com/go-sphere/httpx/fiberx"
+	"github.com/go-sphere/httpx/ginx"
+	"github.com/go-sphere/httpx/hertzx"
+	"github.com/go-sphere/httpx/lambdax"
+)
+
+func newUploadRequest(t *testing.T, size int) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	if err := w.WriteField("title", "report"); err != nil {
+		t.Fatal(err)
+	}
+	part, err := w.CreateFormFile("file", "report.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := part.Write(bytes.Repeat([]byte("x"), size)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "http://example.com/upload", &body)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	return req
+}
+
+func TestMultipartMemoryLimitConformance(t *testing.T) {
+	const limit = 1 << 10
+	engines := map[string]func() httpx.Engine{
+		"lambdax": func() httpx.Engine { return lambdax.New(lambdax.WithMultipartMemoryLimit(limit)) },
+	}
+	for _, name := range conformanceFrameworks {
+		engines[name] = func() httpx.Engine {
+			return newEphemeralEngine(t, name,
+				ginx.WithMultipartMemoryLimit(limit),
+				fiberx.WithMultipartMemoryLimit(limit),
+				echox.WithMultipartMemoryLimit(limit),
+				hertzx.WithMultipartMemoryLimit(limit),
+			)
+		}
+	}
+	for name, newEngine := range engines {
+		t.Run(name, func(t *testing.T) {
+			engine := newEngine()
+			var tempFile string
+			engine.Group("").POST("/upload", func(ctx httpx.Context) error {
+				form, err := ctx.MultipartForm()
+				if err != nil {
+					return err
+				}
+				if got := strings.Join(form.Value["title"], ","); got != "report" {
+					return errors.New("title = " + got)
+				}
+				fh, err := ctx.FormFile("file")
+				if err != nil {
+					return err
+				}
+				f, err := fh.Open()
+				if err != nil {
+					return err
+				}
+				defer func() {
+					_ = f.Close()
+				}()
+				// Files over the limit are written to temporary files.
+				osFile, ok := f.(*os.File)
+				if !ok {
+					return errors.New("file over the memory limit was kept in memory")
+				}
+				tempFile = osFile.Name()
+				n, err := io.Copy(io.Discard, f)
+				if err != nil {
+					return err
+				}
+				if n != 64<<10 {
+					return errors.New("file size mismatch")
+				}
+				return ctx.NoContent(http.StatusNoContent)
+			})
+
+			resp, err := engine.Test(newUploadRequest(t, 64<<10))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() {
+				_ = resp.Body.Close()
+			}()
+			if resp.StatusCode != http.StatusNoContent {
+				body, _ := io.ReadAll(resp.Body)
+				t.Fatalf("status %d, body %s", resp.StatusCode, body)
+			}
+			// net/http removes the temporary files once a server has served
+			// the request, which Test does not do; fiberx and hertzx remove
+			// those of the forms they parse.
+			if name == "fiberx" || name == "hertzx" {
+				if _, err := os.Stat(tempFile); !errors.Is(err, os.ErrNotExist) {
+					t.Fatalf("temporary file %s not removed: %v", tempFile, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package conformance
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/app/server"
+	"github.com/gin-gonic/gin"
+	"github.com/go-sphere/httpx"
+	"github.com/go-sphere/httpx/echox"
+	"github.com/go-sphere/httpx/fiberx"
+	"github.com/go-sphere/httpx/ginx"
+	"github.com/go-sphere/httpx/hertzx"
+	"github.com/go-sphere/httpx/lambdax"
+	"github.com/gofiber/fiber/v3"
+	"github.com/labstack/echo/v4"
+)
+
+func TestNativeEngineConformance(t *testing.T) {
+	natives := map[string]func(httpx.Engine) bool{
+		"ginx":   func(e httpx.Engine) bool { return ginx.Server(e) != nil },
+		"fiberx": func(e httpx.Engine) bool { return fiberx.App(e) != nil },
+		"echox":  func(e httpx.Engine) bool { return echox.Server(e) != nil },
+		"hertzx": func(e httpx.Engine) bool { return hertzx.Server(e) != nil },
+	}
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			engine := newEphemeralEngine(t, name)
+			for other, native := range natives {
+				if got, want := native(engine), other == name; got != want {
+					t.Fatalf("%s helper on a %s engine: got native %v, want %v", other, name, got, want)
+				}
+			}
+			var ok bool
+			switch name {
+			case "ginx", "echox":
+				_, ok = httpx.AsNativeEngine[*http.Server](engine)
+			case "fiberx":
+				_, ok = httpx.AsNativeEngine[*fiber.App](engine)
+			case "hertzx":
+				_, ok = httpx.AsNativeEngine[*server.Hertz](engine)
+			}
+			if !ok {
+				t.Fatalf("AsNativeEngine did not return the native server")
+			}
+		})
+	}
+	if _, ok := httpx.AsNativeEngine[any](lambdax.New()); ok {
+		t.Fatal("lambdax engine reports a native server")
+	}
+}
+
+func TestNativeServerTuningConformance(t *testing.T) {
+	engine := newEphemeralEngine(t, "ginx")
+	var connections atomic.Int32
+	ginx.Server(engine).ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			connections.Add(1)
+		}
+	}
+	engine.Group("").GET("/__ready", func(ctx httpx.Context) error {
+		return ctx.NoContent(http.StatusNoContent)
+	})
+	startErrCh := make(chan error, 1)
+	go func() {
+		startErrCh <- engine.Start()
+	}()
+	b := harnessBundle{
+		harness: frameworkHarness{Name: "ginx", Engine: engine},
+		baseURL: "http://" + waitBoundAddr(t, engine, startErrCh).String(),
+		client:  &http.Client{Timeout: 2 * time.Second},
+	}
+	startNetworkHarnessReady(t, b, startErrCh)
+	t.Cleanup(func() { _ = engine.Stop(t.Context()) })
+	if connections.Load() == 0 {
+		t.Fatal("ConnState set through ginx.Server was not called")
+	}
+}
+
+// TestNativeMiddlewareConformance checks that framework middleware adapted for
+// a Router wraps the rest of the chain, so it sees the response the way
+// gin.Logger does.
+func TestNativeMiddlewareConformance(t *testing.T) {
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			var order []string
+			after := func(status int) {
+				order = append(order, fmt.Sprintf("native after %d", status))
+			}
+			var native httpx.Middleware
+			switch name {
+			case "ginx":
+				native = ginx.AdaptGinMiddleware(func(c *gin.Context) {
+					order = append(order, "native before")
+					c.Next()
+					after(c.Writer.Status())
+				})
+			case "fiberx":
+				native = fiberx.AdaptFiberMiddleware(func(c fiber.Ctx) error {
+					order = append(order, "native before")
+					err := c.Next()
+					after(c.Response().StatusCode())
+					return err
+				})
+			case "echox":
+				native = echox.AdaptEchoMiddleware(func(next echo.HandlerFunc) echo.HandlerFunc {
+					return func(c echo.Context) error {
+						order = append(order, "native before")
+						err := next(c)
+						after(c.Response().Status)
+						return err
+					}
+				})
+			case "hertzx":
+				native = hertzx.AdaptHertzMiddleware(func(ctx context.Context, c *app.RequestContext) {
+					order = append(order, "native before")
+					c.Next(ctx)
+					after(c.Response.StatusCode())
+				})
+			}
+			h := newHarness(t, name)
+			r := h.Router.Group("/native", func(ctx httpx.Context) error {
+				order = append(order, "before")
+				err := ctx.Next()
+				order = append(order, "after")
+				return err
+			})
+			r.Use(native)
+			r.GET("/created", func(ctx httpx.Context) error {
+				order = append(order, "handler")
+				return ctx.Text(http.StatusCreated, "created")
+			})
+			resp := h.Do(t, httptest.NewRequest(http.MethodGet, "http://example.com/native/created", nil))
+			if resp.Status != http.StatusCreated {
+				t.Fatalf("status = %d, want %d", resp.Status, http.StatusCreated)
+			}
+			want := []string{"before", "native before", "handler", "native after 201", "after"}
+			if !slices.Equal(order, want) {
+				t.Fatalf("order = %q, want %q", order, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package conformance
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-sphere/httpx"
+	"github.com/go-sphere/httpx/openapix"
+)
+
+type openAPIUser struct {
+	ID   string `uri:"id"`
+	Name string `json:"name" binding:"required"`
+}
+
+func TestOpenAPIConformance(t *testing.T) {
+	register := func(r httpx.Router) {
+		api := openapix.New(openapix.Info{Title: "Users", Version: "1.0.0"})
+		v1 := r.Group("/v1")
+		api.Route(v1, openapix.Spec{
+			Method:    http.MethodPut,
+			Path:      "/users/:id",
+			Request:   openAPIUser{},
+			Responses: map[int]any{http.StatusNoContent: nil},
+		}, func(ctx httpx.Context) error {
+			return ctx.NoContent(http.StatusNoContent)
+		})
+		api.Mount(r, openapix.WithSwaggerUI("/docs"))
+	}
+
+	results := runAcrossFrameworks(t, register, func() *http.Request {
+		return httptest.NewRequest(http.MethodGet, "http://example.com/openapi.json", nil)
+	})
+	assertMatchesGin(t, results)
+	want := `{"openapi":"3.1.0","info":{"title":"Users","version":"1.0.0"},"paths":{"/v1/users/{id}":{"put":{` +
+		`"parameters":[{"name":"id","in":"path","required":true,"schema":{"type":"string"}}],` +
+		`"requestBody":{"required":true,"content":{"application/json":{"schema":{"type":"object","properties":{"name":{"type":"string"}},"required":["name"]}}}},` +
+		`"responses":{"204":{"description":"No Content"}}}}}}`
+	if got := results["ginx"]; got.Status != http.StatusOK || got.Body != want {
+		t.Fatalf("document = %d %s\nwant %s", got.Status, got.Body, want)
+	}
+
+	results = runAcrossFrameworks(t, register, func() *http.Request {
+		return httptest.NewRequest(http.MethodPut, "http://example.com/v1/users/7", strings.NewReader(`{"name":"gopher"}`))
+	})
+	assertMatchesGin(t, results)
+	if got := results["ginx"]; got.Status != http.StatusNoContent {
+		t.Fatalf("route status = %d, want %d", got.Status, http.StatusNoContent)
+	}
+
+	results = runAcrossFrameworks(t, register, func() *http.Request {
+		return httptest.NewRequest(http.MethodGet, "http://example.com/docs", nil)
+	})
+	assertMatchesGin(t, results)
+	if got := results["ginx"]; !strings.Contains(got.Body, `url: "/openapi.json"`) {
+		t.Fatalf("swagger ui page = %s", got.Body)
+	}
+}
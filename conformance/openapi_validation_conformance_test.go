@@ -0,0 +1,130 @@
+package conformance
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-sphere/httpx"
+	"github.com/go-sphere/httpx/openapix"
+)
+
+type validatedPet struct {
+	ID    int    `uri:"id"`
+	Limit int    `query:"limit"`
+	Name  string `json:"name" binding:"required"`
+}
+
+func TestOpenAPIValidationConformance(t *testing.T) {
+	register := func(r httpx.Router) {
+		api := openapix.New(openapix.Info{Title: "Pets", Version: "1.0.0"})
+		api.Add("/pets/:id", openapix.Spec{
+			Method:    http.MethodPut,
+			Request:   validatedPet{},
+			Responses: map[int]any{http.StatusOK: validatedPet{}},
+		})
+		doc := api.Document()
+		v, err := openapix.NewValidator(&doc, openapix.WithResponseValidation(true))
+		if err != nil {
+			t.Fatalf("NewValidator: %v", err)
+		}
+		r.Use(v.Middleware())
+		r.PUT("/pets/:id", v.Wrap(func(ctx httpx.Context) error {
+			if ctx.Query("broken") != "" {
+				return ctx.JSON(http.StatusOK, map[string]any{"id": "seven"})
+			}
+			var pet validatedPet
+			if err := ctx.BindJSON(&pet); err != nil {
+				return err
+			}
+			return ctx.JSON(http.StatusOK, map[string]any{"name": pet.Name})
+		}))
+		r.PUT("/undocumented", func(ctx httpx.Context) error {
+			return ctx.NoContent(http.StatusNoContent)
+		})
+	}
+
+	// Validation errors are rendered as problem details, whose status is
+	// consistent across frameworks.
+	run := func(request func() *http.Request) map[string]responseSnapshot {
+		t.Helper()
+		results := make(map[string]responseSnapshot, len(conformanceFrameworks))
+		for _, name := range conformanceFrameworks {
+			h := newFrameworkHarnessTB(t, name, harnessOptions{mode: harnessModeInProcess, errorMode: harnessErrorProblem}).harness
+			register(h.Router)
+			results[name] = h.Do(t, request())
+		}
+		assertMatchesGin(t, results)
+		return results
+	}
+
+	tests := []struct {
+		name        string
+		target      string
+		contentType string
+		body        string
+		wantStatus  int
+		wantBody    string
+		wantDetail  string
+	}{
+		{name: "Valid", target: "/pets/7?limit=5", contentType: "application/json", body: `{"name":"rex"}`, wantStatus: http.StatusOK, wantBody: `{"name":"rex"}`},
+		{
+			name: "InvalidParamsAndBody", target: "/pets/seven?limit=x", contentType: "application/json", body: `{"name":1}`,
+			wantStatus: http.StatusBadRequest,
+			wantDetail: "request does not match the API contract: path id: must be of type integer; query limit: must be of type integer; body name: must be of type string",
+		},
+		{
+			name: "MissingBody", target: "/pets/7", wantStatus: http.StatusBadRequest,
+			wantDetail: "request does not match the API contract: body: is required",
+		},
+		{
+			name: "UnsupportedMediaType", target: "/pets/7", contentType: "text/plain", body: "rex", wantStatus: http.StatusUnsupportedMediaType,
+			wantDetail: `request does not match the API contract: body: media type "text/plain" is not accepted`,
+		},
+		{name: "Undocumented", target: "/undocumented", contentType: "text/plain", body: "x", wantStatus: http.StatusNoContent},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			results := run(func() *http.Request {
+				req := httptest.NewRequest(http.MethodPut, "http://example.com"+tc.target, strings.NewReader(tc.body))
+				if tc.contentType != "" {
+					req.Header.Set("Content-Type", tc.contentType)
+				}
+				return req
+			})
+			got := results["ginx"]
+			if got.Status != tc.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", got.Status, tc.wantStatus, got.Body)
+			}
+			if tc.wantBody != "" && got.Body != tc.wantBody {
+				t.Fatalf("body = %s, want %s", got.Body, tc.wantBody)
+			}
+			if tc.wantDetail != "" && !strings.Contains(got.Body, jsonString(t, tc.wantDetail)) {
+				t.Fatalf("body = %s, want it to contain %s", got.Body, tc.wantDetail)
+			}
+		})
+	}
+
+	t.Run("InvalidResponse", func(t *testing.T) {
+		results := run(func() *http.Request {
+			req := httptest.NewRequest(http.MethodPut, "http://example.com/pets/7?broken=1", strings.NewReader(`{"name":"rex"}`))
+			req.Header.Set("Content-Type", "application/json")
+			return req
+		})
+		got := results["ginx"]
+		if got.Status != http.StatusInternalServerError || !strings.Contains(got.Body, "response does not match the API contract") {
+			t.Fatalf("response = %d %s", got.Status, got.Body)
+		}
+	})
+}
+
+func jsonString(t *testing.T, s string) string {
+	t.Helper()
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
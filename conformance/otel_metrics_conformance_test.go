@@ -0,0 +1,78 @@
+package conformance
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-sphere/httpx"
+	"github.com/go-sphere/httpx/otelhttpx"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestOTelMetricsConformance(t *testing.T) {
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			reader := sdkmetric.NewManualReader()
+			provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+			h := newHarness(t, name)
+			h.Router.Use(otelhttpx.Metrics(otelhttpx.WithMeterProvider(provider)))
+			h.Router.GET("/users/:id", func(ctx httpx.Context) error {
+				return ctx.Text(http.StatusOK, "user")
+			})
+			h.Router.GET("/fail", func(ctx httpx.Context) error {
+				return errors.New("boom")
+			})
+
+			for _, path := range []string{"/users/1", "/users/2", "/fail"} {
+				h.Do(t, httptest.NewRequest(http.MethodGet, "http://example.com"+path, nil))
+			}
+
+			var rm metricdata.ResourceMetrics
+			if err := reader.Collect(context.Background(), &rm); err != nil {
+				t.Fatalf("collect failed: %v", err)
+			}
+			duration := findMetric(t, rm, "http.server.request.duration").Data.(metricdata.Histogram[float64])
+			counts := map[string]uint64{}
+			for _, dp := range duration.DataPoints {
+				route, _ := dp.Attributes.Value("http.route")
+				status, _ := dp.Attributes.Value("http.response.status_code")
+				errorType, _ := dp.Attributes.Value("error.type")
+				counts[route.AsString()+" "+status.Emit()+" "+errorType.AsString()] += dp.Count
+			}
+			if got := counts["/users/:id 200 "]; got != 2 {
+				t.Fatalf("%s /users/:id 200 count = %d, want 2 (all: %v)", name, got, counts)
+			}
+			if got := counts["/fail 500 500"]; got != 1 {
+				t.Fatalf("%s /fail 500 count = %d, want 1 (all: %v)", name, got, counts)
+			}
+
+			active := findMetric(t, rm, "http.server.active_requests").Data.(metricdata.Sum[int64])
+			for _, dp := range active.DataPoints {
+				if dp.Value != 0 {
+					t.Fatalf("%s active requests = %d, want 0", name, dp.Value)
+				}
+				if method, _ := dp.Attributes.Value(attribute.Key("http.request.method")); method.AsString() != http.MethodGet {
+					t.Fatalf("%s active requests method = %q", name, method.AsString())
+				}
+			}
+		})
+	}
+}
+
+func findMetric(t *testing.T, rm metricdata.ResourceMetrics, name string) metricdata.Metrics {
+	t.Helper()
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == name {
+				return m
+			}
+		}
+	}
+	t.Fatalf("metric %q not recorded", name)
+	return metricdata.Metrics{}
+}
@@ -0,0 +1,79 @@
+package conformance
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-sphere/httpx"
+)
+
+func TestPaginationConformance(t *testing.T) {
+	register := func(r httpx.Router) {
+		r.GET("/items", func(ctx httpx.Context) error {
+			page, err := httpx.BindPagination(ctx, httpx.WithPageLimits(10, 50), httpx.WithSortFields("name", "created"))
+			if err != nil {
+				_, status, message := httpx.ParseError(err)
+				return ctx.JSON(int(status), map[string]any{"error": message})
+			}
+			httpx.SetPaginationHeaders(ctx, page, 95)
+			return ctx.JSON(http.StatusOK, page)
+		})
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantBody   string
+		wantLink   string
+	}{
+		{
+			name:       "Page",
+			query:      "page=3&limit=20&sort=-created,name",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"page":3,"limit":20,"offset":40,"sort":[{"field":"created","desc":true},{"field":"name"}]}`,
+			wantLink: `<http://example.com/items?limit=20&page=1&sort=-created%2Cname>; rel="first", ` +
+				`<http://example.com/items?limit=20&page=2&sort=-created%2Cname>; rel="prev", ` +
+				`<http://example.com/items?limit=20&page=4&sort=-created%2Cname>; rel="next", ` +
+				`<http://example.com/items?limit=20&page=5&sort=-created%2Cname>; rel="last"`,
+		},
+		{
+			name:       "OffsetAndClampedLimit",
+			query:      "offset=60&limit=500",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"page":2,"limit":50,"offset":60}`,
+			wantLink: `<http://example.com/items?limit=50&offset=0>; rel="first", ` +
+				`<http://example.com/items?limit=50&offset=10>; rel="prev", ` +
+				`<http://example.com/items?limit=50&offset=50>; rel="last"`,
+		},
+		{name: "Defaults", wantStatus: http.StatusOK, wantBody: `{"page":1,"limit":10,"offset":0}`},
+		{name: "InvalidPage", query: "page=0", wantStatus: http.StatusBadRequest},
+		{name: "InvalidLimit", query: "limit=abc", wantStatus: http.StatusBadRequest},
+		{name: "SortFieldNotAllowed", query: "sort=password", wantStatus: http.StatusBadRequest},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			results := runAcrossFrameworks(t, register, func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "http://example.com/items?"+tc.query, nil)
+			})
+			assertMatchesGin(t, results)
+			got := results["ginx"]
+			if got.Status != tc.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", got.Status, tc.wantStatus, got.Body)
+			}
+			if tc.wantStatus != http.StatusOK {
+				return
+			}
+			if got.Body != tc.wantBody {
+				t.Fatalf("body = %s, want %s", got.Body, tc.wantBody)
+			}
+			if got.Headers.Get("X-Total-Count") != "95" {
+				t.Fatalf("X-Total-Count = %q, want 95", got.Headers.Get("X-Total-Count"))
+			}
+			if tc.wantLink != "" && got.Headers.Get("Link") != tc.wantLink {
+				t.Fatalf("Link = %s\nwant %s", got.Headers.Get("Link"), tc.wantLink)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package conformance
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-sphere/httpx"
+	"github.com/go-sphere/httpx/echox"
+	"github.com/go-sphere/httpx/fiberx"
+	"github.com/go-sphere/httpx/ginx"
+	"github.com/go-sphere/httpx/hertzx"
+	"github.com/go-sphere/httpx/lambdax"
+)
+
+func TestPanicPolicyConformance(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   httpx.PanicPolicy
+		wantBody string
+	}{
+		{name: "ConvertToError", policy: httpx.PanicConvertToError, wantBody: `{"error":"httpx: panic: boom"}`},
+		{name: "Abort500", policy: httpx.PanicAbort500, wantBody: "Internal Server Error"},
+	}
+	for _, tt := range tests {
+		engines := map[string]func() httpx.Engine{
+			"lambdax": func() httpx.Engine { return lambdax.New(lambdax.WithPanicPolicy(tt.policy)) },
+		}
+		for _, name := range conformanceFrameworks {
+			engines[name] = func() httpx.Engine {
+				return newEphemeralEngine(t, name,
+					ginx.WithPanicPolicy(tt.policy),
+					fiberx.WithPanicPolicy(tt.policy),
+					echox.WithPanicPolicy(tt.policy),
+					hertzx.WithPanicPolicy(tt.policy),
+				)
+			}
+		}
+		for name, newEngine := range engines {
+			t.Run(tt.name+"/"+name, func(t *testing.T) {
+				engine := newEngine()
+				r := engine.Group("")
+				r.GET("/handler", func(ctx httpx.Context) error {
+					panic("boom")
+				})
+				r.Group("", func(ctx httpx.Context) error {
+					panic("boom")
+				}).GET("/middleware", func(ctx httpx.Context) error {
+					return ctx.NoContent(http.StatusNoContent)
+				})
+				r.GET("/ok", func(ctx httpx.Context) error {
+					return ctx.Text(http.StatusOK, "ok")
+				})
+
+				do := doEngineTest(engine)
+				for _, path := range []string{"/handler", "/middleware"} {
+					got := do(t, httptest.NewRequest(http.MethodGet, "http://example.com"+path, nil))
+					if got.Status != http.StatusInternalServerError || strings.TrimSpace(got.Body) != tt.wantBody {
+						t.Fatalf("GET %s: status %d, body %q, want 500 %q", path, got.Status, got.Body, tt.wantBody)
+					}
+				}
+				if got := do(t, httptest.NewRequest(http.MethodGet, "http://example.com/ok", nil)); got.Status != http.StatusOK || got.Body != "ok" {
+					t.Fatalf("GET /ok after a panic: status %d, body %q", got.Status, got.Body)
+				}
+			})
+		}
+	}
+}
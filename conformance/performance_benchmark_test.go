@@ -7,8 +7,8 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"net"
 	"net/http"
+	"net/http/httptest"
 	"time"
 
 	"testing"
@@ -302,13 +302,37 @@ func doRequest(client *http.Client, req *http.Request) (int, error) {
 	return resp.StatusCode, nil
 }
 
-func reserveAddrTB(tb testing.TB) string {
-	tb.Helper()
-	ln, err := net.Listen("tcp", "127.0.0.1:0")
-	if err != nil {
-		tb.Fatalf("reserve addr failed: %v", err)
+// BenchmarkMiddlewareChain serves a route behind increasing numbers of router
+// middleware in-process, so the cost per middleware shows in how the results
+// grow with depth.
+func BenchmarkMiddlewareChain(b *testing.B) {
+	for _, depth := range []int{0, 8, 32} {
+		b.Run(fmt.Sprintf("depth=%d", depth), func(b *testing.B) {
+			for _, name := range conformanceFrameworks {
+				b.Run(name, func(b *testing.B) {
+					h := newHarnessTB(b, name)
+					for range depth {
+						h.Router.Use(func(ctx httpx.Context) error {
+							return ctx.Next()
+						})
+					}
+					h.Router.GET("/chain", func(ctx httpx.Context) error {
+						return ctx.NoContent(http.StatusNoContent)
+					})
+
+					b.ReportAllocs()
+					for b.Loop() {
+						resp, err := h.Engine.Test(httptest.NewRequest(http.MethodGet, "http://example.com/chain", nil))
+						if err != nil {
+							b.Fatal(err)
+						}
+						_ = resp.Body.Close()
+						if resp.StatusCode != http.StatusNoContent {
+							b.Fatalf("unexpected status: %d", resp.StatusCode)
+						}
+					}
+				})
+			}
+		})
 	}
-	addr := ln.Addr().String()
-	_ = ln.Close()
-	return addr
 }
@@ -0,0 +1,124 @@
+package conformance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-sphere/httpx"
+)
+
+// Adapters pool their contexts and release them when the handler or
+// middleware they were passed to returns. These tests check that a context
+// is not released while it is still in use, and that a released context
+// does not keep request state, nor see that of the request reusing it.
+
+func TestPooledContextAfterNextConformance(t *testing.T) {
+	type observed struct {
+		param, header, state, inner string
+	}
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			h := newFrameworkHarnessTB(t, name, harnessOptions{mode: harnessModeInProcess, errorMode: harnessErrorProblem}).harness
+			var got observed
+			h.Router.Use(func(ctx httpx.Context) error {
+				ctx.Set("outer", "outer-value")
+				err := ctx.Next()
+				// The inner middleware and the handler have released their
+				// contexts; this one must still serve its own request.
+				state, _ := httpx.GetAs[string](ctx, "outer")
+				inner, _ := httpx.GetAs[string](ctx, "inner")
+				got = observed{
+					param:  ctx.Param("id"),
+					header: ctx.Header("X-Request"),
+					state:  state,
+					inner:  inner,
+				}
+				return err
+			})
+			h.Router.Use(func(ctx httpx.Context) error {
+				return ctx.Next()
+			})
+			h.Router.GET("/pool/:id", func(ctx httpx.Context) error {
+				ctx.Set("inner", "inner-value")
+				return httpx.WithStatus(http.StatusConflict, errors.New("conflict"))
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "http://example.com/pool/7", nil)
+			req.Header.Set("X-Request", "r-7")
+			resp := h.Do(t, req)
+			if resp.Status != http.StatusConflict {
+				t.Fatalf("status = %d, want 409; body = %s", resp.Status, resp.Body)
+			}
+			want := observed{param: "7", header: "r-7", state: "outer-value", inner: "inner-value"}
+			if got != want {
+				t.Fatalf("after Next got %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestReleasedContextConformance(t *testing.T) {
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			h := newHarness(t, name)
+			var kept context.Context
+			h.Router.GET("/keep/:id", func(ctx httpx.Context) error {
+				if _, leaked := ctx.Get("user"); leaked {
+					return errors.New("state of a previous request is visible")
+				}
+				ctx.Set("user", ctx.Param("id"))
+				kept = ctx.Context()
+				if got := kept.Value(httpx.StateKey("user")); got != ctx.Param("id") {
+					return errors.New("state not visible through Context")
+				}
+				return ctx.NoContent(http.StatusNoContent)
+			})
+
+			for _, id := range []string{"1", "2"} {
+				resp := h.Do(t, httptest.NewRequest(http.MethodGet, "http://example.com/keep/"+id, nil))
+				if resp.Status != http.StatusNoContent {
+					t.Fatalf("request %s: status = %d, body = %s", id, resp.Status, resp.Body)
+				}
+				// The context.Context outlived the request: its state is
+				// gone instead of panicking or showing another request.
+				if got := kept.Value(httpx.StateKey("user")); got != nil {
+					t.Fatalf("request %s: kept context resolves user = %v after the response", id, got)
+				}
+			}
+		})
+	}
+}
+
+func TestKeptContextInterleavedConformance(t *testing.T) {
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			h := newHarness(t, name)
+			var kept context.Context
+			h.Router.GET("/a", func(ctx httpx.Context) error {
+				ctx.Set("user", "alice")
+				kept = ctx.Context()
+				return ctx.NoContent(http.StatusNoContent)
+			})
+			h.Router.GET("/b", func(ctx httpx.Context) error {
+				// Request A is over and its pooled context is likely the
+				// one serving this request.
+				ctx.Set("user", "bob")
+				_ = ctx.Context()
+				if got := kept.Value(httpx.StateKey("user")); got != nil {
+					return fmt.Errorf("context kept from request A resolves user = %v", got)
+				}
+				return ctx.NoContent(http.StatusNoContent)
+			})
+
+			for _, path := range []string{"/a", "/b"} {
+				if resp := h.Do(t, httptest.NewRequest(http.MethodGet, "http://example.com"+path, nil)); resp.Status != http.StatusNoContent {
+					t.Fatalf("GET %s: status = %d, body = %s", path, resp.Status, resp.Body)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package conformance
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-sphere/httpx"
+)
+
+func TestProblemDetailsConformance(t *testing.T) {
+	register := func(r httpx.Router) {
+		r.GET("/problem", func(ctx httpx.Context) error {
+			return httpx.Problem(ctx, httpx.ProblemDetails{
+				Type:       "https://example.com/probs/out-of-credit",
+				Status:     http.StatusForbidden,
+				Detail:     "Your balance is 30, but that costs 50.",
+				Instance:   "/account/12345/msgs/abc",
+				Extensions: map[string]any{"balance": 30, "status": "ignored"},
+			})
+		})
+		r.GET("/returned", func(ctx httpx.Context) error {
+			return &httpx.ProblemDetails{Type: "https://example.com/probs/gone", Title: "Gone for good", Status: http.StatusGone}
+		})
+		r.GET("/status", func(ctx httpx.Context) error {
+			return httpx.NewError(http.StatusConflict, 4091, "version mismatch", nil)
+		})
+		r.GET("/plain", func(ctx httpx.Context) error {
+			return errors.New("boom")
+		})
+	}
+
+	tests := []struct {
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			path:       "/problem",
+			wantStatus: http.StatusForbidden,
+			wantBody:   `{"balance":30,"detail":"Your balance is 30, but that costs 50.","instance":"/account/12345/msgs/abc","status":403,"title":"Forbidden","type":"https://example.com/probs/out-of-credit"}`,
+		},
+		{
+			path:       "/returned",
+			wantStatus: http.StatusGone,
+			wantBody:   `{"instance":"/returned","status":410,"title":"Gone for good","type":"https://example.com/probs/gone"}`,
+		},
+		{
+			path:       "/status",
+			wantStatus: http.StatusConflict,
+			wantBody:   `{"code":4091,"detail":"version mismatch","instance":"/status","status":409,"title":"Conflict","type":"about:blank"}`,
+		},
+		{
+			path:       "/plain",
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   `{"detail":"boom","instance":"/plain","status":500,"title":"Internal Server Error","type":"about:blank"}`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.path, func(t *testing.T) {
+			results := make(map[string]responseSnapshot, len(conformanceFrameworks))
+			for _, name := range conformanceFrameworks {
+				h := newFrameworkHarnessTB(t, name, harnessOptions{mode: harnessModeInProcess, errorMode: harnessErrorProblem}).harness
+				register(h.Router)
+				results[name] = h.Do(t, httptest.NewRequest(http.MethodGet, "http://example.com"+tc.path, nil))
+			}
+			assertMatchesGin(t, results)
+			got := results["ginx"]
+			if got.Status != tc.wantStatus || got.Body != tc.wantBody {
+				t.Fatalf("response = %d %s\nwant %d %s", got.Status, got.Body, tc.wantStatus, tc.wantBody)
+			}
+			if ct := got.Headers.Get("Content-Type"); ct != httpx.ProblemContentType {
+				t.Fatalf("Content-Type = %q, want %q", ct, httpx.ProblemContentType)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package conformance
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-sphere/httpx"
+	"github.com/go-sphere/httpx/middleware"
+)
+
+func TestOutgoingHeadersConformance(t *testing.T) {
+	downstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, r.Header.Get("X-Request-ID")+"|"+r.Header.Get("traceparent")+"|"+r.Header.Get("baggage"))
+	}))
+	defer downstream.Close()
+	client := &http.Client{Transport: httpx.OutgoingTransport(nil)}
+
+	register := func(r httpx.Router) {
+		r.Use(middleware.RequestID(), middleware.Propagate())
+		r.GET("/call", func(ctx httpx.Context) error {
+			req, err := http.NewRequestWithContext(ctx.Context(), http.MethodGet, downstream.URL, nil)
+			if err != nil {
+				return err
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				return err
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return err
+			}
+			return ctx.Text(http.StatusOK, string(body))
+		})
+	}
+
+	t.Run("Propagated", func(t *testing.T) {
+		results := runAcrossFrameworks(t, register, func() *http.Request {
+			req := httptest.NewRequest(http.MethodGet, "http://example.com/call", nil)
+			req.Header.Set("X-Request-ID", "req-1")
+			req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+			req.Header.Set("baggage", "tenant=acme")
+			return req
+		})
+		assertMatchesGin(t, results)
+		want := "req-1|00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01|tenant=acme"
+		if got := results["ginx"].Body; got != want {
+			t.Fatalf("downstream saw %q, want %q", got, want)
+		}
+	})
+
+	t.Run("GeneratedRequestID", func(t *testing.T) {
+		for _, name := range conformanceFrameworks {
+			h := newHarness(t, name)
+			register(h.Router)
+			got := h.Do(t, httptest.NewRequest(http.MethodGet, "http://example.com/call", nil))
+			id := got.Headers.Get("X-Request-ID")
+			if len(id) != 32 || got.Body != id+"||" {
+				t.Fatalf("%s response id %q, downstream saw %q", name, id, got.Body)
+			}
+		}
+	})
+}
@@ -0,0 +1,120 @@
+package conformance
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"testing"
+	"time"
+
+	"github.com/go-sphere/httpx"
+	"github.com/go-sphere/httpx/echox"
+	"github.com/go-sphere/httpx/fiberx"
+	"github.com/go-sphere/httpx/ginx"
+	"github.com/go-sphere/httpx/hertzx"
+	"github.com/go-sphere/httpx/middleware"
+)
+
+func TestTarpitConformance(t *testing.T) {
+	const delay = 50 * time.Millisecond
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			engine := newEphemeralEngine(t, name)
+			engine.Use(middleware.Tarpit(middleware.DefaultTarpitPaths, delay))
+			engine.Group("").GET("/ok", func(ctx httpx.Context) error {
+				return ctx.Text(http.StatusOK, "ok")
+			})
+			do := doEngineTest(engine)
+			for _, path := range []string{"/.env", "/wp-admin/setup.php"} {
+				start := time.Now()
+				got := do(t, httptest.NewRequest(http.MethodGet, path, nil))
+				if elapsed := time.Since(start); elapsed < delay || got.Status == http.StatusOK {
+					t.Fatalf("%s: status %d after %v, want an error after %v", path, got.Status, elapsed, delay)
+				}
+			}
+			start := time.Now()
+			if got := do(t, httptest.NewRequest(http.MethodGet, "/ok", nil)); got.Status != http.StatusOK || time.Since(start) >= delay {
+				t.Fatalf("/ok: status %d after %v", got.Status, time.Since(start))
+			}
+		})
+	}
+}
+
+// startProtected starts an engine with the connection protection options
+// and returns its address.
+func startProtected(t *testing.T, name string, opts ...any) string {
+	t.Helper()
+	engine := newEphemeralEngine(t, name, opts...)
+	engine.Group("").GET("/", func(ctx httpx.Context) error {
+		return ctx.Text(http.StatusOK, "ok")
+	})
+	startErrCh := make(chan error, 1)
+	go func() {
+		startErrCh <- engine.Start()
+	}()
+	addr := waitBoundAddr(t, engine, startErrCh).String()
+	t.Cleanup(func() { _ = engine.Stop(t.Context()) })
+	return addr
+}
+
+func TestMaxRequestsPerConnConformance(t *testing.T) {
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			addr := startProtected(t, name,
+				ginx.WithMaxRequestsPerConn(2), fiberx.WithMaxRequestsPerConn(2),
+				echox.WithMaxRequestsPerConn(2), hertzx.WithMaxRequestsPerConn(2))
+			client := &http.Client{Transport: &http.Transport{}}
+			t.Cleanup(client.CloseIdleConnections)
+			var reused []bool
+			for range 3 {
+				req, err := http.NewRequest(http.MethodGet, "http://"+addr+"/", nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var info httptrace.GotConnInfo
+				req = req.WithContext(httptrace.WithClientTrace(req.Context(), &httptrace.ClientTrace{
+					GotConn: func(i httptrace.GotConnInfo) { info = i },
+				}))
+				resp, err := client.Do(req)
+				if err != nil {
+					t.Fatal(err)
+				}
+				_, _ = io.Copy(io.Discard, resp.Body)
+				_ = resp.Body.Close()
+				reused = append(reused, info.Reused)
+			}
+			if reused[0] || !reused[1] || reused[2] {
+				t.Fatalf("connection reused = %v, want a new connection after 2 requests", reused)
+			}
+		})
+	}
+}
+
+func TestReadHeaderTimeoutConformance(t *testing.T) {
+	const timeout = 100 * time.Millisecond
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			addr := startProtected(t, name,
+				ginx.WithReadHeaderTimeout(timeout), fiberx.WithReadHeaderTimeout(timeout),
+				echox.WithReadHeaderTimeout(timeout), hertzx.WithReadHeaderTimeout(timeout))
+			conn, err := net.Dial("tcp", addr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() { _ = conn.Close() }()
+			if _, err := io.WriteString(conn, "GET / HTTP/1.1\r\nHost: example.com\r\n"); err != nil {
+				t.Fatal(err)
+			}
+			// The server closes the connection, possibly after an error
+			// response, instead of waiting for the rest of the headers.
+			_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+			start := time.Now()
+			if _, err := io.Copy(io.Discard, bufio.NewReader(conn)); err != nil {
+				t.Fatalf("connection still open after %v: %v", time.Since(start), err)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package conformance
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/go-sphere/httpx"
+)
+
+func TestProxyConformance(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Upstream", "yes")
+		http.SetCookie(w, &http.Cookie{Name: "upstream", Value: "1"})
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"method":         r.Method,
+			"path":           r.URL.Path,
+			"query":          r.URL.RawQuery,
+			"body":           string(body),
+			"host":           r.Host,
+			"forwardedHost":  r.Header.Get("X-Forwarded-Host"),
+			"forwardedProto": r.Header.Get("X-Forwarded-Proto"),
+			"hasForwardedIP": r.Header.Get("X-Forwarded-For") != "",
+			"custom":         r.Header.Get("X-Custom"),
+		})
+	}))
+	defer upstream.Close()
+	target, err := url.Parse(upstream.URL + "/base?fixed=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Forward", func(t *testing.T) {
+		results := runAcrossFrameworks(t, func(r httpx.Router) {
+			path, _ := httpx.FixWildcardPathIfNeed(r, "/gw/*rest")
+			r.Any(path, httpx.Proxy(target, httpx.WithProxyRewrite(func(path string) string {
+				return strings.TrimPrefix(path, "/gw")
+			})))
+		}, func() *http.Request {
+			req := httptest.NewRequest(http.MethodPost, "http://example.com/gw/users/1?q=2", strings.NewReader(`{"name":"gopher"}`))
+			req.Header.Set("X-Custom", "value")
+			req.Header.Set("X-Forwarded-Proto", "https")
+			return req
+		})
+		assertMatchesGin(t, results)
+
+		got := results["ginx"]
+		var body map[string]any
+		if err := json.Unmarshal([]byte(got.Body), &body); err != nil {
+			t.Fatalf("decode body %q: %v", got.Body, err)
+		}
+		want := map[string]any{
+			"method":         http.MethodPost,
+			"path":           "/base/users/1",
+			"query":          "fixed=1&q=2",
+			"body":           `{"name":"gopher"}`,
+			"host":           target.Host,
+			"forwardedHost":  "example.com",
+			"forwardedProto": "https",
+			"hasForwardedIP": true,
+			"custom":         "value",
+		}
+		for key, value := range want {
+			if body[key] != value {
+				t.Fatalf("upstream %s = %v, want %v", key, body[key], value)
+			}
+		}
+		if got.Status != http.StatusAccepted || got.Headers.Get("X-Upstream") != "yes" || !strings.HasPrefix(got.Headers.Get("Set-Cookie"), "upstream=1") {
+			t.Fatalf("response = %d %v", got.Status, got.Headers)
+		}
+	})
+
+	t.Run("PreserveHost", func(t *testing.T) {
+		results := runAcrossFrameworks(t, func(r httpx.Router) {
+			r.GET("/host", httpx.Proxy(target, httpx.WithProxyPreserveHost()))
+		}, func() *http.Request {
+			return httptest.NewRequest(http.MethodGet, "http://example.com/host", nil)
+		})
+		assertMatchesGin(t, results)
+		if !strings.Contains(results["ginx"].Body, `"host":"example.com"`) {
+			t.Fatalf("upstream body = %s", results["ginx"].Body)
+		}
+	})
+
+	t.Run("Unreachable", func(t *testing.T) {
+		closed := httptest.NewServer(http.NotFoundHandler())
+		unreachable, _ := url.Parse(closed.URL)
+		closed.Close()
+
+		for _, name := range conformanceFrameworks {
+			h := newHarness(t, name)
+			var status int32
+			h.Router.Use(func(ctx httpx.Context) error {
+				err := ctx.Next()
+				_, status, _ = httpx.ParseError(err)
+				return err
+			})
+			h.Router.GET("/down", httpx.Proxy(unreachable))
+			got := h.Do(t, httptest.NewRequest(http.MethodGet, "http://example.com/down", nil))
+			if status != http.StatusBadGateway || got.Status < 500 {
+				t.Fatalf("%s proxy error status %d, response %d %q", name, status, got.Status, got.Body)
+			}
+		}
+	})
+}
@@ -0,0 +1,7 @@
+//go:build race
+
+package conformance
+
+// raceEnabled reports whether the tests run with the race detector, which
+// changes allocation counts.
+const raceEnabled = true
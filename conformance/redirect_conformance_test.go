@@ -0,0 +1,43 @@
+package conformance
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/go-sphere/httpx"
+)
+
+func TestRedirectConformance(t *testing.T) {
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			engine := newEphemeralEngine(t, name)
+			api := engine.Group("/api")
+			route := "conformance.user." + name
+			api.GET("/users/:id", func(ctx httpx.Context) error {
+				return ctx.Text(http.StatusOK, ctx.Param("id"))
+			}).Name(route)
+			api.GET("/me", func(ctx httpx.Context) error {
+				return httpx.RedirectToRoute(ctx, route, map[string]string{"id": "7"}, url.Values{"tab": {"posts"}})
+			})
+			api.Any("/v1/users", func(ctx httpx.Context) error {
+				return httpx.PermanentRedirect(ctx, "/api/v2/users")
+			})
+
+			do := doEngineTest(engine)
+			for _, tt := range []struct {
+				method, target, wantLocation string
+				wantStatus                   int
+			}{
+				{http.MethodGet, "/api/me", "/api/users/7?tab=posts", http.StatusFound},
+				{http.MethodPost, "/api/v1/users?page=2", "/api/v2/users?page=2", http.StatusPermanentRedirect},
+			} {
+				got := do(t, httptest.NewRequest(tt.method, tt.target, nil))
+				if got.Status != tt.wantStatus || got.Headers.Get("Location") != tt.wantLocation {
+					t.Fatalf("%s %s: status %d, Location %q, want %d %q", tt.method, tt.target, got.Status, got.Headers.Get("Location"), tt.wantStatus, tt.wantLocation)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package conformance
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-sphere/httpx"
+	"github.com/go-sphere/httpx/ginx"
+)
+
+// replaceableFrameworks swap their routes in ReplaceRoutes; the others
+// return httpx.ErrRoutesNotReplaceable.
+var replaceableFrameworks = []string{"ginx", "echox"}
+
+func TestReplaceRoutesConformance(t *testing.T) {
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			engine := newEphemeralEngine(t, name, ginx.WithEngineFactory(func() *gin.Engine { return gin.New() }))
+			engine.Use(func(ctx httpx.Context) error {
+				ctx.SetHeader("X-Engine-Middleware", "kept")
+				return ctx.Next()
+			})
+			ready := func(ctx httpx.Context) error {
+				return ctx.NoContent(http.StatusNoContent)
+			}
+			text := func(body string) httpx.Handler {
+				return func(ctx httpx.Context) error {
+					return ctx.Text(http.StatusOK, body)
+				}
+			}
+			router := engine.Group("")
+			router.GET("/__ready", ready)
+			router.GET("/old", text("old"))
+
+			startErrCh := make(chan error, 1)
+			go func() {
+				startErrCh <- engine.Start()
+			}()
+			b := harnessBundle{
+				harness: frameworkHarness{Name: name, Engine: engine},
+				baseURL: "http://" + waitBoundAddr(t, engine, startErrCh).String(),
+				client:  &http.Client{Timeout: 2 * time.Second},
+			}
+			startNetworkHarnessReady(t, b, startErrCh)
+			t.Cleanup(func() { _ = engine.Stop(t.Context()) })
+
+			err := httpx.ReplaceRoutes(engine, func(r httpx.Router) {
+				r.GET("/__ready", ready)
+				r.Group("/v2").GET("/new", text("new"))
+			})
+			if !slices.Contains(replaceableFrameworks, name) {
+				if !errors.Is(err, httpx.ErrRoutesNotReplaceable) {
+					t.Fatalf("ReplaceRoutes = %v, want ErrRoutesNotReplaceable", err)
+				}
+				if status, body, _ := getText(t, b, "/old"); status != http.StatusOK || body != "old" {
+					t.Fatalf("GET /old after refused replacement: status %d, body %q", status, body)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReplaceRoutes: %v", err)
+			}
+			// echox's default error handler answers unknown routes with 500.
+			if status, body, _ := getText(t, b, "/old"); status == http.StatusOK || body == "old" {
+				t.Fatalf("GET /old after replacement: status %d, body %q, want it unrouted", status, body)
+			}
+			status, body, header := getText(t, b, "/v2/new")
+			if status != http.StatusOK || body != "new" {
+				t.Fatalf("GET /v2/new after replacement: status %d, body %q", status, body)
+			}
+			if got := header.Get("X-Engine-Middleware"); got != "kept" {
+				t.Fatalf("engine middleware after replacement: X-Engine-Middleware = %q", got)
+			}
+			if err := registerErr(func() { router.GET("/late", ready) }); !errors.Is(err, httpx.ErrRoutesFrozen) {
+				t.Fatalf("route registered after replacement: got %v, want a panic with ErrRoutesFrozen", err)
+			}
+		})
+	}
+}
+
+func TestReplaceRoutesWithEngineConformance(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	engine := ginx.New(ginx.WithEngine(gin.New()))
+	if err := httpx.ReplaceRoutes(engine, func(httpx.Router) {}); !errors.Is(err, httpx.ErrRoutesNotReplaceable) {
+		t.Fatalf("ReplaceRoutes with WithEngine = %v, want ErrRoutesNotReplaceable", err)
+	}
+}
+
+func TestReplaceRoutesLambdaConformance(t *testing.T) {
+	h := newLambdaHarness(t)
+	h.Router.GET("/old", func(ctx httpx.Context) error {
+		return ctx.Text(http.StatusOK, "old")
+	})
+	do := func(path string) responseSnapshot {
+		return h.Do(t, httptest.NewRequest(http.MethodGet, "http://example.com"+path, nil))
+	}
+
+	// A conflicting route panics in http.ServeMux and keeps the old routes.
+	err := httpx.ReplaceRoutes(h.Engine, func(r httpx.Router) {
+		r.GET("/new", func(ctx httpx.Context) error { return nil })
+		r.GET("/new", func(ctx httpx.Context) error { return nil })
+	})
+	if err == nil {
+		t.Fatal("ReplaceRoutes with conflicting routes returned nil")
+	}
+	if resp := do("/old"); resp.Status != http.StatusOK {
+		t.Fatalf("GET /old after failed replacement: status %d, want 200", resp.Status)
+	}
+
+	err = httpx.ReplaceRoutes(h.Engine, func(r httpx.Router) {
+		r.GET("/new", func(ctx httpx.Context) error {
+			return ctx.Text(http.StatusOK, "new")
+		})
+	})
+	if err != nil {
+		t.Fatalf("ReplaceRoutes: %v", err)
+	}
+	if resp := do("/old"); resp.Status != http.StatusNotFound {
+		t.Fatalf("GET /old after replacement: status %d, want 404", resp.Status)
+	}
+	if resp := do("/new"); resp.Status != http.StatusOK || resp.Body != "new" {
+		t.Fatalf("GET /new after replacement: status %d, body %q", resp.Status, resp.Body)
+	}
+}
+
+// startNetworkHarnessReady waits until an engine started by the caller
+// answers /__ready.
+func startNetworkHarnessReady(tb testing.TB, b harnessBundle, startErrCh <-chan error) {
+	tb.Helper()
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		select {
+		case err := <-startErrCh:
+			tb.Fatalf("%s engine exited before ready: %v", b.harness.Name, err)
+		default:
+		}
+		if status, _, _ := getText(tb, b, "/__ready"); status == http.StatusNoContent {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	tb.Fatalf("%s engine did not become ready", b.harness.Name)
+}
+
+// getText requests path from a running engine and returns the response,
+// with a zero status when the request fails.
+func getText(tb testing.TB, b harnessBundle, path string) (int, string, http.Header) {
+	tb.Helper()
+	resp, err := b.client.Get(b.baseURL + path)
+	if err != nil {
+		return 0, "", nil
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		tb.Fatalf("read %s: %v", path, err)
+	}
+	return resp.StatusCode, string(body), resp.Header
+}
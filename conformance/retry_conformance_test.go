@@ -0,0 +1,33 @@
+package conformance
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-sphere/httpx"
+)
+
+func TestRetryAfterConformance(t *testing.T) {
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			engine := newEphemeralEngine(t, name)
+			r := engine.Group("")
+			r.GET("/throttled", func(ctx httpx.Context) error {
+				return httpx.TooManyRequests(ctx, 30*time.Second)
+			})
+			r.GET("/maintenance", func(ctx httpx.Context) error {
+				return httpx.ServiceUnavailable(ctx, 2*time.Minute)
+			})
+
+			do := doEngineTest(engine)
+			for path, want := range map[string]string{"/throttled": "30", "/maintenance": "120"} {
+				got := do(t, httptest.NewRequest(http.MethodGet, path, nil))
+				if got.Status == http.StatusOK || got.Headers.Get("Retry-After") != want {
+					t.Fatalf("%s: status %d, Retry-After %q, want %q", path, got.Status, got.Headers.Get("Retry-After"), want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package conformance
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-sphere/httpx"
+	"github.com/go-sphere/httpx/echox"
+	"github.com/go-sphere/httpx/fiberx"
+	"github.com/go-sphere/httpx/ginx"
+	"github.com/go-sphere/httpx/hertzx"
+	"github.com/go-sphere/httpx/lambdax"
+)
+
+type routeLimitsValueKey struct{}
+
+func TestRouteLimitsConformance(t *testing.T) {
+	const timeout = time.Second
+	engines := map[string]func(t *testing.T) httpx.Engine{
+		"lambdax": func(*testing.T) httpx.Engine { return lambdax.New(lambdax.WithRequestTimeout(timeout)) },
+	}
+	for _, name := range conformanceFrameworks {
+		engines[name] = func(t *testing.T) httpx.Engine {
+			return newEphemeralEngine(t, name,
+				ginx.WithRequestTimeout(timeout),
+				fiberx.WithRequestTimeout(timeout),
+				echox.WithRequestTimeout(timeout),
+				hertzx.WithRequestTimeout(timeout),
+			)
+		}
+	}
+	for name, newEngine := range engines {
+		t.Run(name, func(t *testing.T) {
+			engine := newEngine(t)
+			var handlerErr error
+			engine.Use(func(ctx httpx.Context) error {
+				handlerErr = ctx.Next()
+				return handlerErr
+			})
+			engine.Use(httpx.BodyLimit(8))
+			engine.Use(func(ctx httpx.Context) error {
+				ctx.SetContext(context.WithValue(ctx.Context(), routeLimitsValueKey{}, "kept"))
+				return ctx.Next()
+			})
+			var left time.Duration
+			read := func(ctx httpx.Context) error {
+				if _, err := io.ReadAll(ctx.BodyReader()); err != nil {
+					return err
+				}
+				deadline, ok := ctx.Context().Deadline()
+				if !ok {
+					return errors.New("request context has no deadline")
+				}
+				if ctx.Context().Value(routeLimitsValueKey{}) != "kept" {
+					return errors.New("request context lost its values")
+				}
+				left = time.Until(deadline)
+				return ctx.NoContent(http.StatusNoContent)
+			}
+			r := engine.Group("")
+			r.POST("/small", read)
+			r.POST("/upload", read).With(httpx.WithRouteTimeout(time.Hour), httpx.WithRouteBodyLimit(64))
+			r.Group("/strict", httpx.BodyLimit(4)).POST("/tiny", read)
+
+			do := doEngineTest(engine)
+			send := func(path, body string, chunked bool) responseSnapshot {
+				var reader io.Reader = strings.NewReader(body)
+				if chunked {
+					reader = io.MultiReader(reader)
+				}
+				handlerErr, left = nil, 0
+				return do(t, httptest.NewRequest(http.MethodPost, "http://example.com"+path, reader))
+			}
+
+			if got := send("/small", "1234", false); got.Status != http.StatusNoContent || left <= 0 || left > timeout {
+				t.Fatalf("small body: status %d, body %s, deadline in %v", got.Status, got.Body, left)
+			}
+			for _, chunked := range []bool{false, true} {
+				if chunked && name == "fiberx" {
+					// fiber's App.Test cannot send bodies of unknown length.
+					continue
+				}
+				if got := send("/small", strings.Repeat("x", 16), chunked); got.Status == http.StatusNoContent || !errors.Is(handlerErr, httpx.ErrBodyTooLarge) {
+					t.Fatalf("body over engine limit (chunked %v): status %d, error %v", chunked, got.Status, handlerErr)
+				}
+			}
+			if got := send("/upload", strings.Repeat("x", 32), false); got.Status != http.StatusNoContent || left <= time.Minute {
+				t.Fatalf("route override: status %d, body %s, deadline in %v", got.Status, got.Body, left)
+			}
+			if got := send("/upload", strings.Repeat("x", 100), name != "fiberx"); got.Status == http.StatusNoContent || !errors.Is(handlerErr, httpx.ErrBodyTooLarge) {
+				t.Fatalf("body over route limit: status %d, error %v", got.Status, handlerErr)
+			}
+			if got := send("/strict/tiny", "123456", false); got.Status == http.StatusNoContent || !errors.Is(handlerErr, httpx.ErrBodyTooLarge) {
+				t.Fatalf("body over router limit: status %d, error %v", got.Status, handlerErr)
+			}
+		})
+	}
+}
+
+func TestLimitedBodyReaderConformance(t *testing.T) {
+	engines := map[string]func(t *testing.T) httpx.Engine{
+		"lambdax": func(*testing.T) httpx.Engine { return lambdax.New() },
+	}
+	for _, name := range conformanceFrameworks {
+		engines[name] = func(t *testing.T) httpx.Engine { return newEphemeralEngine(t, name) }
+	}
+	for name, newEngine := range engines {
+		t.Run(name, func(t *testing.T) {
+			engine := newEngine(t)
+			var handlerErr error
+			var progress int64
+			engine.Group("").POST("/upload", func(ctx httpx.Context) error {
+				body := httpx.LimitedBodyReader(ctx, 8, func(read int64) {
+					progress = read
+				})
+				defer func() {
+					_ = body.Close()
+				}()
+				if _, err := io.Copy(io.Discard, body); err != nil {
+					handlerErr = err
+					return err
+				}
+				return ctx.NoContent(http.StatusNoContent)
+			})
+			do := doEngineTest(engine)
+			send := func(body string) responseSnapshot {
+				handlerErr, progress = nil, 0
+				return do(t, httptest.NewRequest(http.MethodPost, "http://example.com/upload", strings.NewReader(body)))
+			}
+			if got := send("12345678"); got.Status != http.StatusNoContent || progress != 8 {
+				t.Fatalf("body at limit: status %d, progress %d", got.Status, progress)
+			}
+			if got := send(strings.Repeat("x", 16)); got.Status == http.StatusNoContent || !errors.Is(handlerErr, httpx.ErrBodyTooLarge) {
+				t.Fatalf("body over limit: status %d, error %v", got.Status, handlerErr)
+			}
+		})
+	}
+}
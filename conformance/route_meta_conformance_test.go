@@ -0,0 +1,60 @@
+package conformance
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-sphere/httpx"
+	"github.com/go-sphere/httpx/lambdax"
+)
+
+func TestRouteMetaConformance(t *testing.T) {
+	engines := map[string]func(t *testing.T) httpx.Engine{
+		"lambdax": func(*testing.T) httpx.Engine { return lambdax.New() },
+	}
+	for _, name := range conformanceFrameworks {
+		engines[name] = func(t *testing.T) httpx.Engine { return newEphemeralEngine(t, name) }
+	}
+	for name, newEngine := range engines {
+		t.Run(name, func(t *testing.T) {
+			engine := newEngine(t)
+			// Engine middleware runs ahead of the route's handler, so it
+			// reads the metadata once Next returns.
+			var rate string
+			engine.Use(func(ctx httpx.Context) error {
+				err := ctx.Next()
+				rate = httpx.RouteMeta(ctx)["rate"]
+				return err
+			})
+			r := engine.Group("/api", func(ctx httpx.Context) error {
+				if httpx.RouteMeta(ctx)["auth"] != "public" && ctx.Header("Authorization") == "" {
+					return ctx.NoContent(http.StatusUnauthorized)
+				}
+				return ctx.Next()
+			})
+			ok := func(ctx httpx.Context) error {
+				return ctx.Text(http.StatusOK, "ok")
+			}
+			route := r.GET("/health", ok).Meta("auth", "public").Meta("rate", "high")
+			r.GET("/private", ok)
+			r.Any("/any", ok).Meta("auth", "public")
+
+			if got := route.Metadata(); len(got) != 2 || got["rate"] != "high" {
+				t.Fatalf("Metadata = %v", got)
+			}
+			do := doEngineTest(engine)
+			got := do(t, httptest.NewRequest(http.MethodGet, "http://example.com/api/health", nil))
+			if got.Status != http.StatusOK || rate != "high" {
+				t.Fatalf("public route: status %d, engine middleware saw rate %q", got.Status, rate)
+			}
+			if got := do(t, httptest.NewRequest(http.MethodPost, "http://example.com/api/any", nil)); got.Status != http.StatusOK {
+				t.Fatalf("public Any route: status %d", got.Status)
+			}
+			got = do(t, httptest.NewRequest(http.MethodGet, "http://example.com/api/private", nil))
+			if got.Status != http.StatusUnauthorized || rate != "" {
+				t.Fatalf("route without metadata: status %d, engine middleware saw rate %q", got.Status, rate)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package conformance
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"net/http/httptest"
@@ -262,6 +263,101 @@ func TestRouterConformance(t *testing.T) {
 	})
 }
 
+// TestComposedChainConformance covers router middleware, which adapters
+// compose with each handler when the route is registered.
+func TestComposedChainConformance(t *testing.T) {
+	t.Run("GroupOrder", func(t *testing.T) {
+		results := runAcrossFrameworks(t, func(r httpx.Router) {
+			trace := func(name string) httpx.Middleware {
+				return func(ctx httpx.Context) error {
+					v, _ := ctx.Get("order")
+					order, _ := v.([]string)
+					ctx.Set("order", append(order, name))
+					return ctx.Next()
+				}
+			}
+			r.Use(trace("router"))
+			api := r.Group("/api", trace("group"))
+			api.Use(trace("group-use"))
+			// Middleware added after Group only applies to later routes of
+			// the router itself.
+			r.Use(trace("late"))
+			api.GET("/order", func(ctx httpx.Context) error {
+				v, _ := ctx.Get("order")
+				return ctx.JSON(http.StatusOK, map[string]any{"order": v})
+			})
+		}, func() *http.Request {
+			return httptest.NewRequest(http.MethodGet, "http://example.com/api/order", nil)
+		})
+		assertMatchesGin(t, results)
+		assertJSONBodyEqual(t, "ginx", `{"order":["router","group","group-use"]}`, results["ginx"].Body)
+	})
+
+	t.Run("MiddlewareHandlesHandlerError", func(t *testing.T) {
+		results := runAcrossFrameworks(t, func(r httpx.Router) {
+			r.Use(func(ctx httpx.Context) error {
+				if err := ctx.Next(); err != nil {
+					return ctx.Text(http.StatusServiceUnavailable, "handled: "+err.Error())
+				}
+				return nil
+			})
+			r.GET("/mw/handled", func(ctx httpx.Context) error {
+				return errors.New("boom")
+			})
+		}, func() *http.Request {
+			return httptest.NewRequest(http.MethodGet, "http://example.com/mw/handled", nil)
+		})
+		assertMatchesGin(t, results)
+		for _, name := range conformanceFrameworks {
+			if got := results[name]; got.Status != http.StatusServiceUnavailable || got.Body != "handled: boom" {
+				t.Fatalf("%s: status %d, body %q; want the middleware's response", name, got.Status, got.Body)
+			}
+		}
+	})
+
+	t.Run("SetContextReachesHandler", func(t *testing.T) {
+		type ctxKey struct{}
+		results := runAcrossFrameworks(t, func(r httpx.Router) {
+			r.Use(func(ctx httpx.Context) error {
+				ctx.SetContext(context.WithValue(ctx.Context(), ctxKey{}, "from-middleware"))
+				return ctx.Next()
+			})
+			r.GET("/mw/context", func(ctx httpx.Context) error {
+				v, _ := ctx.Context().Value(ctxKey{}).(string)
+				return ctx.Text(http.StatusOK, v)
+			})
+		}, func() *http.Request {
+			return httptest.NewRequest(http.MethodGet, "http://example.com/mw/context", nil)
+		})
+		assertMatchesGin(t, results)
+		if got := results["ginx"].Body; got != "from-middleware" {
+			t.Fatalf("body = %q, want the value set by the middleware", got)
+		}
+	})
+
+	t.Run("StaticRunsMiddleware", func(t *testing.T) {
+		tmp := t.TempDir()
+		if err := os.WriteFile(filepath.Join(tmp, "hello.txt"), []byte("static-content"), 0o600); err != nil {
+			t.Fatalf("write static file: %v", err)
+		}
+		results := runAcrossFrameworks(t, func(r httpx.Router) {
+			r.Use(func(ctx httpx.Context) error {
+				ctx.SetHeader("X-Middleware", "static")
+				return ctx.Next()
+			})
+			r.Static("/assets", tmp)
+		}, func() *http.Request {
+			return httptest.NewRequest(http.MethodGet, "http://example.com/assets/hello.txt", nil)
+		})
+		assertMatchesGin(t, results)
+		for _, name := range conformanceFrameworks {
+			if got := results[name].Headers.Get("X-Middleware"); got != "static" {
+				t.Fatalf("%s: X-Middleware = %q, want the middleware to run for static files", name, got)
+			}
+		}
+	})
+}
+
 func TestStaticConformance(t *testing.T) {
 	tmp := t.TempDir()
 	if err := os.WriteFile(filepath.Join(tmp, "hello.txt"), []byte("static-content"), 0o600); err != nil {
@@ -0,0 +1,78 @@
+package conformance
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-sphere/httpx"
+)
+
+type stateUser struct {
+	Name string `json:"name"`
+}
+
+func TestTypedStateConformance(t *testing.T) {
+	userKey := httpx.NewKey[*stateUser]("user")
+	results := runAcrossFrameworks(t, func(r httpx.Router) {
+		r.Use(func(ctx httpx.Context) error {
+			httpx.SetTyped(ctx, userKey, &stateUser{Name: "gopher"})
+			ctx.Set("attempt", 2)
+			return ctx.Next()
+		})
+		r.GET("/state", func(ctx httpx.Context) error {
+			attempt, _ := httpx.GetAs[int](ctx, "attempt")
+			_, wrongType := httpx.GetAs[string](ctx, "attempt")
+			return ctx.JSON(http.StatusOK, map[string]any{
+				"user":      userKey.MustGet(ctx),
+				"attempt":   attempt,
+				"wrongType": wrongType,
+			})
+		})
+	}, func() *http.Request {
+		return httptest.NewRequest(http.MethodGet, "http://example.com/state", nil)
+	})
+	assertMatchesGin(t, results)
+	if want := `{"attempt":2,"user":{"name":"gopher"},"wrongType":false}`; results["ginx"].Body != want {
+		t.Fatalf("body = %s, want %s", results["ginx"].Body, want)
+	}
+}
+
+func TestStateThroughContextConformance(t *testing.T) {
+	type traceKey struct{}
+	userKey := httpx.NewKey[*stateUser]("user")
+	// lookup stands in for a downstream library that only sees a derived
+	// context.Context.
+	lookup := func(ctx context.Context) map[string]any {
+		user, _ := userKey.Value(ctx)
+		return map[string]any{
+			"tenant": ctx.Value(httpx.StateKey("tenant")),
+			"late":   ctx.Value(httpx.StateKey("late")),
+			"user":   user,
+			"trace":  ctx.Value(traceKey{}),
+			"plain":  ctx.Value("tenant"),
+		}
+	}
+	results := runAcrossFrameworks(t, func(r httpx.Router) {
+		r.Use(func(ctx httpx.Context) error {
+			ctx.Set("tenant", "acme")
+			httpx.SetTyped(ctx, userKey, &stateUser{Name: "gopher"})
+			ctx.SetContext(context.WithValue(ctx.Context(), traceKey{}, "trace-1"))
+			return ctx.Next()
+		})
+		r.GET("/state/context", func(ctx httpx.Context) error {
+			derived, cancel := context.WithTimeout(ctx.Context(), time.Minute)
+			defer cancel()
+			ctx.Set("late", true)
+			return ctx.JSON(http.StatusOK, lookup(derived))
+		})
+	}, func() *http.Request {
+		return httptest.NewRequest(http.MethodGet, "http://example.com/state/context", nil)
+	})
+	assertMatchesGin(t, results)
+	if want := `{"late":true,"plain":null,"tenant":"acme","trace":"trace-1","user":{"name":"gopher"}}`; results["ginx"].Body != want {
+		t.Fatalf("body = %s, want %s", results["ginx"].Body, want)
+	}
+}
@@ -0,0 +1,74 @@
+package conformance
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/go-sphere/httpx"
+)
+
+func TestStreamKnownSizeConformance(t *testing.T) {
+	// The body is larger than the buffer net/http sizes bodies of unknown
+	// length with.
+	body := "%PDF-1.7\n" + strings.Repeat("stream ", 2000)
+	size := int64(len(body))
+	tests := []struct {
+		path          string
+		reader        func() io.Reader
+		size          int64
+		wantLength    int64
+		wantTruncated bool
+	}{
+		{"/given", func() io.Reader { return iotest.HalfReader(strings.NewReader(body)) }, size, size, false},
+		{"/len", func() io.Reader { return strings.NewReader(body) }, -1, size, false},
+		{"/unknown", func() io.Reader { return io.MultiReader(strings.NewReader(body)) }, -1, -1, false},
+		{"/short", func() io.Reader { return io.MultiReader(strings.NewReader(body)) }, size + 10, size + 10, true},
+	}
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			engine := newEphemeralEngine(t, name)
+			r := engine.Group("")
+			for _, tc := range tests {
+				r.GET(tc.path, func(ctx httpx.Context) error {
+					return httpx.StreamKnownSize(ctx, http.StatusOK, "application/pdf", tc.size, tc.reader())
+				})
+			}
+			startErrCh := make(chan error, 1)
+			go func() {
+				startErrCh <- engine.Start()
+			}()
+			addr := waitBoundAddr(t, engine, startErrCh).String()
+			t.Cleanup(func() { _ = engine.Stop(t.Context()) })
+
+			client := &http.Client{Transport: &http.Transport{}}
+			t.Cleanup(client.CloseIdleConnections)
+			for _, tc := range tests {
+				resp, err := client.Get("http://" + addr + tc.path)
+				if err != nil && tc.wantTruncated {
+					// fasthttp fails the response before sending its headers.
+					continue
+				}
+				if err != nil {
+					t.Fatalf("%s: %v", tc.path, err)
+				}
+				got, err := io.ReadAll(resp.Body)
+				_ = resp.Body.Close()
+				if resp.ContentLength != tc.wantLength || resp.Header.Get("Content-Type") != "application/pdf" {
+					t.Fatalf("%s: Content-Length %d, Content-Type %q, want %d", tc.path, resp.ContentLength, resp.Header.Get("Content-Type"), tc.wantLength)
+				}
+				if tc.wantTruncated {
+					if err == nil {
+						t.Fatalf("%s: read %d bytes without error, want a truncated body", tc.path, len(got))
+					}
+					continue
+				}
+				if err != nil || string(got) != body {
+					t.Fatalf("%s: %d bytes, error %v", tc.path, len(got), err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,285 @@
+// Package conformance certifies httpx.Engine implementations against the
+// reference behaviour of the ginx adapter.
+//
+// The tests of this module check the adapters of this repository. Authors of
+// adapters for other frameworks can run the same checks with a Suite:
+//
+//	func TestConformance(t *testing.T) {
+//		suite := conformance.Suite{GoldenDir: "testdata/golden"}
+//		suite.Run(t, func(tb testing.TB) httpx.Engine {
+//			return myadapter.New()
+//		})
+//	}
+package conformance
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-sphere/httpx"
+	"github.com/go-sphere/httpx/ginx"
+)
+
+// Response is the part of a response that conformance compares.
+type Response struct {
+	Status  int         `json:"status"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    string      `json:"body"`
+}
+
+// comparedHeaders are the headers Compare checks, and that golden files
+// keep.
+var comparedHeaders = []string{"Content-Type", "Location", "X-Trace", "Set-Cookie"}
+
+// ReadResponse reads resp into a Response and closes its body.
+func ReadResponse(resp *http.Response) (Response, error) {
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Response{}, fmt.Errorf("read response body: %w", err)
+	}
+	return Response{Status: resp.StatusCode, Headers: resp.Header.Clone(), Body: string(body)}, nil
+}
+
+// Compare reports how got differs from the reference response want, or nil
+// when they match. It checks the status, the body, comparing JSON bodies
+// semantically, the media type of Content-Type, the Location and X-Trace
+// headers set by want and the names and values of its cookies.
+func Compare(want, got Response) error {
+	if want.Status != got.Status {
+		return fmt.Errorf("status mismatch: want %d, got %d", want.Status, got.Status)
+	}
+
+	if isJSON(want.Headers.Get("Content-Type")) {
+		if err := compareJSONBody(want.Body, got.Body); err != nil {
+			return err
+		}
+	} else if (want.Status < 300 || want.Status >= 400 || want.Headers.Get("Location") == "") && want.Body != got.Body {
+		return fmt.Errorf("body mismatch: want %q, got %q", want.Body, got.Body)
+	}
+
+	if want.Status < 300 || want.Status >= 400 {
+		if err := compareContentType(want.Headers.Get("Content-Type"), got.Headers.Get("Content-Type")); err != nil {
+			return err
+		}
+	}
+	for _, key := range []string{"Location", "X-Trace"} {
+		if err := compareHeaderIfPresent(key, want.Headers, got.Headers); err != nil {
+			return err
+		}
+	}
+	return compareSetCookie(want.Headers.Values("Set-Cookie"), got.Headers.Values("Set-Cookie"))
+}
+
+func compareHeaderIfPresent(key string, want, got http.Header) error {
+	w := want.Values(key)
+	if len(w) == 0 {
+		return nil
+	}
+	if g := got.Values(key); !reflect.DeepEqual(w, g) {
+		return fmt.Errorf("header %s mismatch: want %v, got %v", key, w, g)
+	}
+	return nil
+}
+
+func compareContentType(want, got string) error {
+	if want == "" {
+		return nil
+	}
+	wantMain := strings.TrimSpace(strings.Split(want, ";")[0])
+	gotMain := strings.TrimSpace(strings.Split(got, ";")[0])
+	if wantMain != gotMain {
+		return fmt.Errorf("content-type mismatch: want %q, got %q", wantMain, gotMain)
+	}
+	return nil
+}
+
+func compareSetCookie(want, got []string) error {
+	if len(want) == 0 {
+		return nil
+	}
+	if len(got) < len(want) {
+		return fmt.Errorf("set-cookie mismatch: want at least %d, got %d", len(want), len(got))
+	}
+	for _, expected := range want {
+		pair := cookiePair(expected)
+		found := false
+		for _, actual := range got {
+			if cookiePair(actual) == pair {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("missing cookie %q in %v", pair, got)
+		}
+	}
+	return nil
+}
+
+func cookiePair(v string) string {
+	parts := strings.Split(v, ";")
+	return strings.TrimSpace(parts[0])
+}
+
+func isJSON(contentType string) bool {
+	return strings.Contains(strings.ToLower(contentType), "application/json")
+}
+
+func compareJSONBody(want, got string) error {
+	var wantObj any
+	if err := json.Unmarshal([]byte(want), &wantObj); err != nil {
+		return fmt.Errorf("invalid reference json body: %v; body=%q", err, want)
+	}
+	var gotObj any
+	if err := json.Unmarshal([]byte(got), &gotObj); err != nil {
+		return fmt.Errorf("invalid json body: %v; body=%q", err, got)
+	}
+	if !reflect.DeepEqual(wantObj, gotObj) {
+		return fmt.Errorf("json body mismatch: want %s, got %s", want, got)
+	}
+	return nil
+}
+
+// Case is a single conformance check: routes registered on a fresh engine
+// and a request served by it.
+type Case struct {
+	Name     string
+	Register func(r httpx.Router)
+	Request  func() *http.Request
+}
+
+// Factory returns a new engine with the default error handler, which renders
+// errors as status 500 with a JSON {"error": ...} body. Suites serve requests
+// with Engine.Test and never start the engine.
+type Factory func(tb testing.TB) httpx.Engine
+
+// Suite checks an engine against reference responses.
+type Suite struct {
+	// Cases are the checks to run; nil runs Cases().
+	Cases []Case
+	// Reference creates the engine whose responses are expected; nil uses
+	// ginx.
+	Reference Factory
+	// GoldenDir, when set, holds the reference responses as one JSON file
+	// per case, so that the reference engine is not run. Files are written
+	// from the reference engine when Update is set.
+	GoldenDir string
+	Update    bool
+}
+
+// Run runs every case as a subtest of t against engines created by factory.
+func (s *Suite) Run(t *testing.T, factory Factory) {
+	t.Helper()
+	cases := s.Cases
+	if cases == nil {
+		cases = Cases()
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			want := s.want(t, c)
+			got, err := Serve(t, factory, c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := Compare(want, got); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func (s *Suite) want(t *testing.T, c Case) Response {
+	t.Helper()
+	reference := s.Reference
+	if reference == nil {
+		reference = ReferenceEngine
+	}
+	if s.GoldenDir == "" {
+		want, err := Serve(t, reference, c)
+		if err != nil {
+			t.Fatalf("reference: %v", err)
+		}
+		return want
+	}
+
+	path := filepath.Join(s.GoldenDir, goldenName(c.Name)+".json")
+	if s.Update {
+		want, err := Serve(t, reference, c)
+		if err != nil {
+			t.Fatalf("reference: %v", err)
+		}
+		if err := writeGolden(path, want); err != nil {
+			t.Fatal(err)
+		}
+		return want
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("missing golden file %s; run the suite with Update set to record it", path)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want Response
+	if err := json.Unmarshal(data, &want); err != nil {
+		t.Fatalf("decode golden file %s: %v", path, err)
+	}
+	return want
+}
+
+func writeGolden(path string, resp Response) error {
+	kept := make(http.Header)
+	for _, key := range comparedHeaders {
+		if values := resp.Headers.Values(key); len(values) > 0 {
+			kept[key] = values
+		}
+	}
+	resp.Headers = kept
+	data, err := json.MarshalIndent(resp, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+var goldenNameChars = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+func goldenName(name string) string {
+	return goldenNameChars.ReplaceAllString(name, "_")
+}
+
+// Serve registers c on an engine created by factory and serves its request.
+func Serve(tb testing.TB, factory Factory, c Case) (Response, error) {
+	tb.Helper()
+	engine := factory(tb)
+	c.Register(engine.Group(""))
+	resp, err := engine.Test(c.Request())
+	if err != nil {
+		return Response{}, err
+	}
+	return ReadResponse(resp)
+}
+
+// ReferenceEngine returns a ginx engine, the reference of the suite.
+func ReferenceEngine(testing.TB) httpx.Engine {
+	gin.SetMode(gin.ReleaseMode)
+	g := gin.New()
+	g.Use(gin.Recovery())
+	return ginx.New(ginx.WithEngine(g))
+}
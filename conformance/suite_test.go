@@ -0,0 +1,23 @@
+package conformance
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/go-sphere/httpx"
+)
+
+var updateGolden = flag.Bool("update", false, "record the golden files of the suite from the reference engine")
+
+func TestSuite(t *testing.T) {
+	suite := Suite{GoldenDir: "testdata/golden", Update: *updateGolden}
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			suite.Run(t, func(tb testing.TB) httpx.Engine {
+				return newHarnessTB(tb, name).Engine
+			})
+		})
+		// Record the golden files once, from the reference engine.
+		suite.Update = false
+	}
+}
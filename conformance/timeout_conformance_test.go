@@ -0,0 +1,103 @@
+package conformance
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-sphere/httpx"
+	"github.com/go-sphere/httpx/echox"
+	"github.com/go-sphere/httpx/fiberx"
+	"github.com/go-sphere/httpx/ginx"
+	"github.com/go-sphere/httpx/hertzx"
+	"github.com/go-sphere/httpx/lambdax"
+)
+
+func TestRequestTimeoutConformance(t *testing.T) {
+	const timeout = time.Minute
+	engines := map[string]func() httpx.Engine{
+		"lambdax": func() httpx.Engine { return lambdax.New(lambdax.WithRequestTimeout(timeout)) },
+	}
+	for _, name := range conformanceFrameworks {
+		engines[name] = func() httpx.Engine {
+			return newEphemeralEngine(t, name,
+				ginx.WithRequestTimeout(timeout),
+				fiberx.WithRequestTimeout(timeout),
+				echox.WithRequestTimeout(timeout),
+				hertzx.WithRequestTimeout(timeout),
+			)
+		}
+	}
+	for name, newEngine := range engines {
+		t.Run(name, func(t *testing.T) {
+			engine := newEngine()
+			engine.Group("").GET("/deadline", func(ctx httpx.Context) error {
+				deadline, ok := ctx.Context().Deadline()
+				if !ok {
+					return errors.New("request context has no deadline")
+				}
+				if left := time.Until(deadline); left <= 0 || left > timeout {
+					return errors.New("request context deadline is not within the timeout")
+				}
+				return ctx.NoContent(http.StatusNoContent)
+			})
+			resp, err := engine.Test(httptest.NewRequest(http.MethodGet, "http://example.com/deadline", nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() {
+				_ = resp.Body.Close()
+			}()
+			if resp.StatusCode != http.StatusNoContent {
+				body, _ := io.ReadAll(resp.Body)
+				t.Fatalf("status %d, body %s", resp.StatusCode, body)
+			}
+		})
+	}
+}
+
+func TestReadTimeoutConformance(t *testing.T) {
+	const timeout = 100 * time.Millisecond
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			engine := newEphemeralEngine(t, name,
+				ginx.WithReadTimeout(timeout),
+				fiberx.WithReadTimeout(timeout),
+				echox.WithReadTimeout(timeout),
+				hertzx.WithReadTimeout(timeout),
+			)
+			startErrCh := make(chan error, 1)
+			go func() {
+				startErrCh <- engine.Start()
+			}()
+			addr := waitBoundAddr(t, engine, startErrCh)
+			t.Cleanup(func() { _ = engine.Stop(t.Context()) })
+
+			conn, err := net.Dial("tcp", addr.String())
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() {
+				_ = conn.Close()
+			}()
+			// A client that never finishes its request headers is
+			// disconnected once the read timeout expires.
+			if _, err := io.WriteString(conn, "GET / HTTP/1.1\r\nHost: example.com\r\n"); err != nil {
+				t.Fatal(err)
+			}
+			if err := conn.SetReadDeadline(time.Now().Add(3 * time.Second)); err != nil {
+				t.Fatal(err)
+			}
+			start := time.Now()
+			_, err = io.Copy(io.Discard, conn)
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				t.Fatalf("connection still open after %v, want it closed after the read timeout", time.Since(start))
+			}
+		})
+	}
+}
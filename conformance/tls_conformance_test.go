@@ -0,0 +1,141 @@
+package conformance
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-sphere/httpx"
+	"github.com/go-sphere/httpx/echox"
+	"github.com/go-sphere/httpx/fiberx"
+	"github.com/go-sphere/httpx/ginx"
+	"github.com/go-sphere/httpx/hertzx"
+)
+
+func TestEngineStartTLSConformance(t *testing.T) {
+	certFile, keyFile := writeSelfSignedCert(t)
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			engine := newEphemeralEngine(t, name,
+				ginx.WithTLS(certFile, keyFile),
+				fiberx.WithTLS(certFile, keyFile),
+				echox.WithTLS(certFile, keyFile),
+				hertzx.WithTLS(certFile, keyFile),
+			)
+			engine.Group("").GET("/tls", func(ctx httpx.Context) error {
+				return ctx.Text(http.StatusOK, "secure")
+			})
+
+			startErrCh := make(chan error, 1)
+			go func() {
+				startErrCh <- engine.StartTLS()
+			}()
+			addr := waitBoundAddr(t, engine, startErrCh).String()
+
+			client := &http.Client{
+				Timeout: 2 * time.Second,
+				Transport: &http.Transport{
+					TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, //nolint:gosec // self-signed test certificate
+				},
+			}
+			var (
+				status int
+				body   string
+			)
+			deadline := time.Now().Add(3 * time.Second)
+			for time.Now().Before(deadline) {
+				select {
+				case err := <-startErrCh:
+					t.Fatalf("%s StartTLS exited early: %v", name, err)
+				default:
+				}
+				resp, err := client.Get("https://" + addr + "/tls")
+				if err == nil {
+					data, _ := io.ReadAll(resp.Body)
+					_ = resp.Body.Close()
+					status, body = resp.StatusCode, string(data)
+					break
+				}
+				time.Sleep(20 * time.Millisecond)
+			}
+			if status != http.StatusOK || body != "secure" {
+				t.Fatalf("%s HTTPS response mismatch: status=%d body=%q", name, status, body)
+			}
+
+			stopCtx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			defer cancel()
+			_ = engine.Stop(stopCtx)
+			select {
+			case err := <-startErrCh:
+				if !isExpectedStartExit(err) {
+					t.Fatalf("%s StartTLS returned unexpected error: %v", name, err)
+				}
+			case <-time.After(3 * time.Second):
+				t.Fatalf("%s StartTLS did not exit after stop", name)
+			}
+		})
+	}
+}
+
+func TestEngineStartTLSNotConfiguredConformance(t *testing.T) {
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			engine := newStartEngine(t, name)
+			if err := engine.StartTLS(); !errors.Is(err, httpx.ErrTLSNotConfigured) {
+				t.Fatalf("%s StartTLS error = %v, want %v", name, err, httpx.ErrTLSNotConfigured)
+			}
+		})
+	}
+}
+
+// writeSelfSignedCert writes a short-lived self-signed certificate for
+// 127.0.0.1 and returns the certificate and key file paths.
+func writeSelfSignedCert(tb testing.TB) (certFile, keyFile string) {
+	tb.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		tb.Fatalf("generate key failed: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "httpx-conformance"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		tb.Fatalf("create certificate failed: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		tb.Fatalf("marshal key failed: %v", err)
+	}
+
+	dir := tb.TempDir()
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		tb.Fatalf("write certificate failed: %v", err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		tb.Fatalf("write key failed: %v", err)
+	}
+	return certFile, keyFile
+}
@@ -0,0 +1,91 @@
+package conformance
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-sphere/httpx"
+	"github.com/go-sphere/httpx/echox"
+	"github.com/go-sphere/httpx/fiberx"
+	"github.com/go-sphere/httpx/ginx"
+	"github.com/go-sphere/httpx/hertzx"
+	"github.com/go-sphere/httpx/middleware"
+)
+
+type recordingTx struct {
+	log *[]string
+}
+
+func (tx recordingTx) Commit() error {
+	*tx.log = append(*tx.log, "commit")
+	return nil
+}
+
+func (tx recordingTx) Rollback() error {
+	*tx.log = append(*tx.log, "rollback")
+	return nil
+}
+
+func TestTxConformance(t *testing.T) {
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			var log []string
+			begin := func(ctx httpx.Context) (middleware.Transaction, error) {
+				if ctx.Query("fail") != "" {
+					return nil, errors.New("database unavailable")
+				}
+				return recordingTx{log: &log}, nil
+			}
+			engine := newEphemeralEngine(t, name,
+				ginx.WithPanicPolicy(httpx.PanicConvertToError),
+				fiberx.WithPanicPolicy(httpx.PanicConvertToError),
+				echox.WithPanicPolicy(httpx.PanicConvertToError),
+				hertzx.WithPanicPolicy(httpx.PanicConvertToError))
+			r := engine.Group("", middleware.Tx(begin))
+			r.POST("/orders", func(ctx httpx.Context) error {
+				if _, ok := middleware.TxKey.Get(ctx); !ok {
+					return errors.New("no transaction")
+				}
+				if _, ok := middleware.TxKey.Value(ctx.Context()); !ok {
+					return errors.New("no transaction in context")
+				}
+				return ctx.Text(http.StatusCreated, "created")
+			})
+			r.POST("/invalid", func(ctx httpx.Context) error {
+				return ctx.Text(http.StatusConflict, "conflict")
+			})
+			r.POST("/error", func(ctx httpx.Context) error {
+				return errors.New("insert failed")
+			})
+			r.POST("/panic", func(ctx httpx.Context) error {
+				panic("boom")
+			})
+
+			do := doEngineTest(engine)
+			tests := []struct {
+				path       string
+				wantStatus int
+				wantLog    string
+			}{
+				{"/orders", http.StatusCreated, "commit"},
+				{"/invalid", http.StatusConflict, "rollback"},
+				{"/error", http.StatusInternalServerError, "rollback"},
+				{"/panic", http.StatusInternalServerError, "rollback"},
+				{"/orders?fail=1", http.StatusInternalServerError, ""},
+			}
+			for _, tc := range tests {
+				log = nil
+				got := do(t, httptest.NewRequest(http.MethodPost, tc.path, nil))
+				gotLog := ""
+				if len(log) > 0 {
+					gotLog = log[0]
+				}
+				if got.Status != tc.wantStatus || len(log) > 1 || gotLog != tc.wantLog {
+					t.Fatalf("%s: status %d, log %v; want %d, %q", tc.path, got.Status, log, tc.wantStatus, tc.wantLog)
+				}
+			}
+		})
+	}
+}
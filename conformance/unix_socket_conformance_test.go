@@ -0,0 +1,127 @@
+package conformance
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/hertz/pkg/app/server"
+	"github.com/gin-gonic/gin"
+	"github.com/go-sphere/httpx"
+	"github.com/go-sphere/httpx/echox"
+	"github.com/go-sphere/httpx/fiberx"
+	"github.com/go-sphere/httpx/ginx"
+	"github.com/go-sphere/httpx/hertzx"
+	"github.com/gofiber/fiber/v3"
+)
+
+func TestEngineUnixSocketConformance(t *testing.T) {
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			dir, err := os.MkdirTemp("", "httpx")
+			if err != nil {
+				t.Fatalf("MkdirTemp failed: %v", err)
+			}
+			t.Cleanup(func() {
+				_ = os.RemoveAll(dir)
+			})
+			path := filepath.Join(dir, "app.sock")
+
+			// Leave a stale socket file behind, as a crashed process would.
+			stale, err := net.Listen("unix", path)
+			if err != nil {
+				t.Fatalf("listen stale socket failed: %v", err)
+			}
+			stale.(*net.UnixListener).SetUnlinkOnClose(false)
+			_ = stale.Close()
+
+			engine := newUnixSocketEngine(t, name, path)
+			engine.Group("").GET("/__ready", func(ctx httpx.Context) error {
+				return ctx.NoContent(http.StatusNoContent)
+			})
+			engine.Group("").GET("/unix", func(ctx httpx.Context) error {
+				return ctx.Text(http.StatusOK, "unix")
+			})
+
+			b := harnessBundle{
+				harness: frameworkHarness{Name: name, Engine: engine},
+				baseURL: "http://unix",
+				client: &http.Client{
+					Timeout: 2 * time.Second,
+					Transport: &http.Transport{
+						DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+							var d net.Dialer
+							return d.DialContext(ctx, "unix", path)
+						},
+					},
+				},
+			}
+			startErrCh := startNetworkHarness(t, b)
+
+			if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o660 {
+				t.Fatalf("%s socket file mode mismatch: info=%v err=%v", name, info, err)
+			}
+			resp, err := b.client.Get(b.baseURL + "/unix")
+			if err != nil {
+				t.Fatalf("%s unix request failed: %v", name, err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			_ = resp.Body.Close()
+			if resp.StatusCode != http.StatusOK || string(body) != "unix" {
+				t.Fatalf("%s unix response mismatch: status=%d body=%q", name, resp.StatusCode, body)
+			}
+
+			stopCtx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			defer cancel()
+			_ = engine.Stop(stopCtx)
+			select {
+			case err := <-startErrCh:
+				if !isExpectedStartExit(err) {
+					t.Fatalf("%s start returned unexpected error: %v", name, err)
+				}
+			case <-time.After(3 * time.Second):
+				t.Fatalf("%s start did not exit after stop", name)
+			}
+			if _, err := os.Stat(path); !os.IsNotExist(err) {
+				t.Fatalf("%s socket file should be removed after stop, stat err = %v", name, err)
+			}
+		})
+	}
+}
+
+// TestEngineUnixSocketUnstartedConformance checks that engines bind their
+// socket when they start, so engines that never start leave no socket file.
+func TestEngineUnixSocketUnstartedConformance(t *testing.T) {
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "app.sock")
+			newUnixSocketEngine(t, name, path)
+			if _, err := os.Stat(path); !os.IsNotExist(err) {
+				t.Fatalf("%s socket file created before start, stat err = %v", name, err)
+			}
+		})
+	}
+}
+
+func newUnixSocketEngine(tb testing.TB, name, path string) httpx.Engine {
+	tb.Helper()
+	switch name {
+	case "ginx":
+		gin.SetMode(gin.ReleaseMode)
+		return ginx.New(ginx.WithEngine(gin.New()), ginx.WithUnixSocket(path, 0o660))
+	case "fiberx":
+		return fiberx.New(fiberx.WithListen("", fiber.ListenConfig{DisableStartupMessage: true}), fiberx.WithUnixSocket(path, 0o660))
+	case "echox":
+		return echox.New(echox.WithUnixSocket(path, 0o660))
+	case "hertzx":
+		return hertzx.New(hertzx.WithServerOptions(server.WithDisablePrintRoute(true)), hertzx.WithUnixSocket(path, 0o660))
+	default:
+		tb.Fatalf("unknown framework: %s", name)
+		return nil
+	}
+}
@@ -0,0 +1,146 @@
+package conformance
+
+import (
+	"bytes"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-sphere/httpx"
+)
+
+func TestUploadConformance(t *testing.T) {
+	png := append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 64)...)
+	policy := httpx.UploadPolicy{MaxSize: 1 << 10, AllowedMIME: []string{"image/*"}, AllowedExt: []string{".png"}}
+
+	register := func(dir string) func(httpx.Router) {
+		return func(r httpx.Router) {
+			r.POST("/upload", func(ctx httpx.Context) error {
+				fh, err := ctx.FormFile("file")
+				if err != nil {
+					return err
+				}
+				if err := policy.Validate(fh); err != nil {
+					_, status, _ := httpx.ParseError(err)
+					return ctx.JSON(int(status), map[string]any{
+						"tooLarge":   errors.Is(err, httpx.ErrUploadTooLarge),
+						"notAllowed": errors.Is(err, httpx.ErrUploadTypeNotAllowed),
+					})
+				}
+				dst := filepath.Join(dir, "uploads", filepath.Base(fh.Filename))
+				if err := httpx.SaveUploadedFile(fh, dst); err != nil {
+					return err
+				}
+				saved, err := os.ReadFile(dst)
+				if err != nil {
+					return err
+				}
+				return ctx.JSON(http.StatusCreated, map[string]any{"size": len(saved)})
+			})
+		}
+	}
+	upload := func(filename string, content []byte) func() *http.Request {
+		return func() *http.Request {
+			var body bytes.Buffer
+			writer := multipart.NewWriter(&body)
+			part, _ := writer.CreateFormFile("file", filename)
+			_, _ = part.Write(content)
+			_ = writer.Close()
+
+			req := httptest.NewRequest(http.MethodPost, "http://example.com/upload", &body)
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+			return req
+		}
+	}
+
+	tests := []struct {
+		name       string
+		filename   string
+		content    []byte
+		wantStatus int
+	}{
+		{name: "Saved", filename: "../avatar.PNG", content: png, wantStatus: http.StatusCreated},
+		{name: "TooLarge", filename: "big.png", content: append(png, make([]byte, 2<<10)...), wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "Extension", filename: "avatar.gif", content: png, wantStatus: http.StatusUnsupportedMediaType},
+		{name: "SniffedType", filename: "fake.png", content: []byte("<html><script></script></html>"), wantStatus: http.StatusUnsupportedMediaType},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			results := runAcrossFrameworks(t, register(t.TempDir()), upload(tc.filename, tc.content))
+			assertMatchesGin(t, results)
+			if got := results["ginx"].Status; got != tc.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", got, tc.wantStatus, results["ginx"].Body)
+			}
+		})
+	}
+}
+
+func TestStreamUploadConformance(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 10<<10)
+	register := func(r httpx.Router) {
+		r.POST("/stream", func(ctx httpx.Context) error {
+			var sink bytes.Buffer
+			info, err := httpx.StreamUpload(ctx, "file", &sink)
+			if err != nil {
+				_, status, _ := httpx.ParseError(err)
+				return ctx.JSON(int(status), map[string]any{"missing": errors.Is(err, httpx.ErrUploadMissing)})
+			}
+			return ctx.JSON(http.StatusOK, map[string]any{
+				"filename":    info.Filename,
+				"contentType": info.ContentType,
+				"size":        info.Size,
+				"intact":      bytes.Equal(sink.Bytes(), content),
+			})
+		})
+	}
+	upload := func(field string) func() *http.Request {
+		return func() *http.Request {
+			var body bytes.Buffer
+			writer := multipart.NewWriter(&body)
+			_ = writer.WriteField("title", "report")
+			part, _ := writer.CreateFormFile(field, "../report.txt")
+			_, _ = part.Write(content)
+			_ = writer.WriteField("after", "ignored")
+			_ = writer.Close()
+
+			req := httptest.NewRequest(http.MethodPost, "http://example.com/stream", &body)
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+			return req
+		}
+	}
+	tests := []struct {
+		name     string
+		request  func() *http.Request
+		wantBody string
+	}{
+		{
+			name:     "Streamed",
+			request:  upload("file"),
+			wantBody: `{"contentType":"application/octet-stream","filename":"report.txt","intact":true,"size":102400}`,
+		},
+		{name: "Missing", request: upload("other"), wantBody: `{"missing":true}`},
+		{
+			name: "NotMultipart",
+			request: func() *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "http://example.com/stream", bytes.NewReader(content))
+				req.Header.Set("Content-Type", "application/octet-stream")
+				return req
+			},
+			wantBody: `{"missing":false}`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			results := runAcrossFrameworks(t, register, tc.request)
+			assertMatchesGin(t, results)
+			if got := strings.TrimSpace(results["ginx"].Body); got != tc.wantBody {
+				t.Fatalf("body = %s, want %s", got, tc.wantBody)
+			}
+		})
+	}
+}
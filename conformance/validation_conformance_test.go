@@ -0,0 +1,185 @@
+package conformance
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-sphere/httpx"
+	"github.com/go-sphere/httpx/ginx"
+	"github.com/go-sphere/httpx/lambdax"
+)
+
+func TestValidationErrorConformance(t *testing.T) {
+	engines := map[string]func(t *testing.T) httpx.Engine{
+		"lambdax": func(*testing.T) httpx.Engine { return lambdax.New() },
+	}
+	for _, name := range conformanceFrameworks {
+		engines[name] = func(t *testing.T) httpx.Engine { return newEphemeralEngine(t, name) }
+	}
+	for name, newEngine := range engines {
+		t.Run(name, func(t *testing.T) {
+			engine := newEngine(t)
+			engine.Group("").POST("/users", func(ctx httpx.Context) error {
+				return &httpx.ValidationError{Errors: []httpx.FieldError{
+					{Field: "Name", Rule: "required", Message: "Name failed the required rule"},
+				}}
+			})
+			resp := doEngineTest(engine)(t, httptest.NewRequest(http.MethodPost, "/users", nil))
+			if resp.Status != http.StatusUnprocessableEntity {
+				t.Fatalf("status = %d, want 422 (%s)", resp.Status, resp.Body)
+			}
+			var body struct {
+				Error  string             `json:"error"`
+				Errors []httpx.FieldError `json:"errors"`
+			}
+			if err := json.Unmarshal([]byte(resp.Body), &body); err != nil {
+				t.Fatalf("body %q: %v", resp.Body, err)
+			}
+			if body.Error != "invalid request: Name failed the required rule" || len(body.Errors) != 1 || body.Errors[0].Rule != "required" {
+				t.Fatalf("body = %s", resp.Body)
+			}
+		})
+	}
+
+	t.Run("ginx binding tags", func(t *testing.T) {
+		engine := newEphemeralEngine(t, "ginx")
+		type address struct {
+			City string `json:"city" binding:"required"`
+		}
+		type input struct {
+			Name    string  `json:"name" binding:"required,min=3"`
+			Address address `json:"address"`
+		}
+		var bindErr error
+		engine.Group("").POST("/users", func(ctx httpx.Context) error {
+			var in input
+			bindErr = ctx.BindJSON(&in)
+			return bindErr
+		})
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":"go"}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp := doEngineTest(engine)(t, req)
+		var ve *httpx.ValidationError
+		if !errors.As(bindErr, &ve) {
+			t.Fatalf("BindJSON() error = %v, want *httpx.ValidationError", bindErr)
+		}
+		want := []httpx.FieldError{
+			{Field: "Name", Rule: "min", Message: "Name failed the min=3 rule"},
+			{Field: "Address.City", Rule: "required", Message: "Address.City failed the required rule"},
+		}
+		if len(ve.Errors) != len(want) || ve.Errors[0] != want[0] || ve.Errors[1] != want[1] {
+			t.Fatalf("errors = %+v, want %+v", ve.Errors, want)
+		}
+		if resp.Status != http.StatusUnprocessableEntity || !strings.Contains(resp.Body, `"field":"Address.City"`) {
+			t.Fatalf("response = %d %s", resp.Status, resp.Body)
+		}
+	})
+
+	t.Run("required fields", func(t *testing.T) {
+		type address struct {
+			City string `json:"city" query:"city" binding:"required"`
+		}
+		type input struct {
+			Name    string  `json:"name" query:"name" binding:"required"`
+			Age     int     `json:"age" query:"age"`
+			Address address `json:"address" query:"address"`
+		}
+		want := `{"error":"invalid request: Name failed the required rule; Address.City failed the required rule",` +
+			`"errors":[{"field":"Name","rule":"required","message":"Name failed the required rule"},` +
+			`{"field":"Address.City","rule":"required","message":"Address.City failed the required rule"}]}`
+		for source, request := range map[string]func() *http.Request{
+			"json": func() *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/json", strings.NewReader(`{"age":1,"address":{}}`))
+				req.Header.Set("Content-Type", "application/json")
+				return req
+			},
+			"query": func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/query?age=1", nil)
+			},
+		} {
+			for name, newEngine := range engines {
+				t.Run(source+"/"+name, func(t *testing.T) {
+					engine := newEngine(t)
+					r := engine.Group("")
+					r.POST("/json", func(ctx httpx.Context) error {
+						var in input
+						return ctx.BindJSON(&in)
+					})
+					r.GET("/query", func(ctx httpx.Context) error {
+						var in input
+						return ctx.BindQuery(&in)
+					})
+					resp := doEngineTest(engine)(t, request())
+					if resp.Status != http.StatusUnprocessableEntity || strings.TrimSpace(resp.Body) != want {
+						t.Fatalf("response = %d %s, want 422 %s", resp.Status, resp.Body, want)
+					}
+				})
+			}
+		}
+	})
+
+	t.Run("type errors", func(t *testing.T) {
+		type input struct {
+			Page  int `query:"page" form:"page"`
+			Retry int `header:"X-Retry"`
+		}
+		for source, tc := range map[string]struct {
+			request func() *http.Request
+			field   string
+		}{
+			"query": {func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/query?page=abc", nil)
+			}, "Page"},
+			"form": {func() *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/form", strings.NewReader("page=abc"))
+				req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+				return req
+			}, "Page"},
+			"header": {func() *http.Request {
+				req := httptest.NewRequest(http.MethodGet, "/header", nil)
+				req.Header.Set("X-Retry", "soon")
+				return req
+			}, "Retry"},
+		} {
+			want := `{"error":"invalid request: ` + tc.field + ` failed the type rule",` +
+				`"errors":[{"field":"` + tc.field + `","rule":"type","message":"` + tc.field + ` failed the type rule"}]}`
+			for name, newEngine := range engines {
+				t.Run(source+"/"+name, func(t *testing.T) {
+					engine := newEngine(t)
+					r := engine.Group("")
+					r.GET("/query", func(ctx httpx.Context) error {
+						var in input
+						return ctx.BindQuery(&in)
+					})
+					r.POST("/form", func(ctx httpx.Context) error {
+						var in input
+						return ctx.BindForm(&in)
+					})
+					r.GET("/header", func(ctx httpx.Context) error {
+						var in input
+						return ctx.BindHeader(&in)
+					})
+					resp := doEngineTest(engine)(t, tc.request())
+					if resp.Status != http.StatusUnprocessableEntity || strings.TrimSpace(resp.Body) != want {
+						t.Fatalf("response = %d %s, want 422 %s", resp.Status, resp.Body, want)
+					}
+				})
+			}
+		}
+	})
+
+	t.Run("problem details", func(t *testing.T) {
+		engine := newEphemeralEngine(t, "ginx", ginx.WithErrorHandler(ginx.AdaptErrorHandler(httpx.ProblemErrorHandler)))
+		engine.Group("").POST("/users", func(ctx httpx.Context) error {
+			return &httpx.ValidationError{Errors: []httpx.FieldError{{Field: "Name", Rule: "required", Message: "Name failed the required rule"}}}
+		})
+		resp := doEngineTest(engine)(t, httptest.NewRequest(http.MethodPost, "/users", nil))
+		if resp.Status != http.StatusUnprocessableEntity || !strings.Contains(resp.Body, `"errors":[{"field":"Name","rule":"required"`) {
+			t.Fatalf("response = %d %s", resp.Status, resp.Body)
+		}
+	})
+}
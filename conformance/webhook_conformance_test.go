@@ -0,0 +1,64 @@
+package conformance
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-sphere/httpx"
+	"github.com/go-sphere/httpx/webhookx"
+)
+
+type pushEvent struct {
+	Ref string `json:"ref"`
+}
+
+func TestWebhookReceiverConformance(t *testing.T) {
+	scheme := webhookx.GitHub("s3cret")
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			engine := newEphemeralEngine(t, name)
+			var handlerErr error
+			engine.Use(func(ctx httpx.Context) error {
+				handlerErr = ctx.Next()
+				return handlerErr
+			})
+			r := engine.Group("")
+			hooks := webhookx.New(scheme).On("push", webhookx.Typed(func(ctx httpx.Context, push pushEvent) error {
+				return ctx.Text(http.StatusOK, push.Ref)
+			}))
+			r.POST("/hooks", hooks.Handle)
+			r.Group("", webhookx.Verify(scheme)).POST("/raw", func(ctx httpx.Context) error {
+				body, err := ctx.BodyRaw()
+				if err != nil {
+					return err
+				}
+				return ctx.Text(http.StatusOK, string(body))
+			})
+
+			do := doEngineTest(engine)
+			send := func(path, event, body string, sign bool) responseSnapshot {
+				req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+				req.Header.Set("X-GitHub-Event", event)
+				if sign {
+					scheme.Sign(req.Header, []byte(body))
+				}
+				return do(t, req)
+			}
+			if got := send("/hooks", "push", `{"ref":"refs/heads/main"}`, true); got.Status != http.StatusOK || got.Body != "refs/heads/main" {
+				t.Fatalf("push: status %d, body %q", got.Status, got.Body)
+			}
+			if got := send("/hooks", "star", `{}`, true); got.Status != http.StatusNoContent {
+				t.Fatalf("unhandled event: status %d", got.Status)
+			}
+			if got := send("/hooks", "push", `{"ref":"x"}`, false); got.Status == http.StatusOK || !errors.Is(handlerErr, webhookx.ErrInvalidSignature) {
+				t.Fatalf("unsigned: status %d, error %v", got.Status, handlerErr)
+			}
+			if got := send("/raw", "push", `{"ref":"raw"}`, true); got.Status != http.StatusOK || got.Body != `{"ref":"raw"}` {
+				t.Fatalf("verified route: status %d, body %q", got.Status, got.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package conformance
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-sphere/httpx"
+	"github.com/go-sphere/httpx/webhookx"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestWebhookDispatcherConformance(t *testing.T) {
+	scheme := webhookx.GitHub("s3cret")
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			engine := newEphemeralEngine(t, name)
+			r := engine.Group("")
+			var calls atomic.Int32
+			r.POST("/hooks", webhookx.New(scheme).On("push", func(ctx httpx.Context, event webhookx.Event) error {
+				if calls.Add(1) == 1 {
+					return ctx.NoContent(http.StatusServiceUnavailable)
+				}
+				if ctx.Header(webhookx.DeliveryHeader) == "" {
+					return ctx.NoContent(http.StatusBadRequest)
+				}
+				return ctx.NoContent(http.StatusNoContent)
+			}).Handle)
+			r.POST("/gone", func(ctx httpx.Context) error {
+				return ctx.NoContent(http.StatusGone)
+			})
+
+			dispatcher := webhookx.NewDispatcher(scheme,
+				webhookx.WithEventHeader("X-GitHub-Event"),
+				webhookx.WithBackoff(func(int) time.Duration { return time.Millisecond }),
+				webhookx.WithHTTPClient(&http.Client{Transport: roundTripFunc(engine.Test)}),
+			)
+			dispatcher.Mount(engine.Group("/admin"))
+
+			ok, err := dispatcher.Dispatch(t.Context(), "http://example.com/hooks", "push", []byte(`{"ref":"main"}`))
+			if err != nil {
+				t.Fatal(err)
+			}
+			gone, err := dispatcher.Dispatch(t.Context(), "http://example.com/gone", "push", []byte(`{}`))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := dispatcher.Close(t.Context()); err != nil {
+				t.Fatal(err)
+			}
+
+			do := doEngineTest(engine)
+			var delivery webhookx.Delivery
+			got := do(t, httptest.NewRequest(http.MethodGet, "/admin/deliveries/"+ok, nil))
+			if err := json.Unmarshal([]byte(got.Body), &delivery); err != nil {
+				t.Fatalf("status %d, body %q: %v", got.Status, got.Body, err)
+			}
+			if delivery.State != webhookx.DeliverySucceeded || delivery.Attempts != 2 || delivery.StatusCode != http.StatusNoContent {
+				t.Fatalf("retried delivery: %+v", delivery)
+			}
+
+			var failed []webhookx.Delivery
+			got = do(t, httptest.NewRequest(http.MethodGet, "/admin/deliveries?state=failed", nil))
+			if err := json.Unmarshal([]byte(got.Body), &failed); err != nil {
+				t.Fatalf("status %d, body %q: %v", got.Status, got.Body, err)
+			}
+			if len(failed) != 1 || failed[0].ID != gone || failed[0].Attempts != 1 || failed[0].StatusCode != http.StatusGone {
+				t.Fatalf("failed deliveries: %+v", failed)
+			}
+			if got := do(t, httptest.NewRequest(http.MethodGet, "/admin/deliveries/unknown", nil)); got.Status == http.StatusOK {
+				t.Fatalf("unknown delivery: status %d", got.Status)
+			}
+		})
+	}
+}
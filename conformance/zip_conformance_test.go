@@ -0,0 +1,69 @@
+package conformance
+
+import (
+	"archive/zip"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-sphere/httpx"
+)
+
+func TestZipStreamConformance(t *testing.T) {
+	errMissing := errors.New("missing file")
+	for _, name := range conformanceFrameworks {
+		t.Run(name, func(t *testing.T) {
+			engine := newEphemeralEngine(t, name)
+			var handlerErr error
+			engine.Use(func(ctx httpx.Context) error {
+				handlerErr = ctx.Next()
+				return handlerErr
+			})
+			r := engine.Group("")
+			r.GET("/all", func(ctx httpx.Context) error {
+				ctx.SetHeader("Content-Disposition", `attachment; filename="all.zip"`)
+				return httpx.ZipStream(ctx, func(yield func(httpx.ZipEntry, error) bool) {
+					_ = yield(httpx.ZipEntry{Name: "a.txt", Body: strings.NewReader(strings.Repeat("a", 4096))}, nil) &&
+						yield(httpx.ZipEntry{Name: "dir/b.bin", Body: io.NopCloser(strings.NewReader("bbb")), Store: true}, nil)
+				})
+			})
+			r.GET("/missing", func(ctx httpx.Context) error {
+				return httpx.ZipStream(ctx, func(yield func(httpx.ZipEntry, error) bool) {
+					yield(httpx.ZipEntry{}, errMissing)
+				})
+			})
+
+			do := doEngineTest(engine)
+			got := do(t, httptest.NewRequest(http.MethodGet, "/all", nil))
+			if got.Status != http.StatusOK || got.Headers.Get("Content-Type") != "application/zip" || got.Headers.Get("Content-Disposition") == "" {
+				t.Fatalf("status %d, headers %v", got.Status, got.Headers)
+			}
+			zr, err := zip.NewReader(strings.NewReader(got.Body), int64(len(got.Body)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := map[string]string{"a.txt": strings.Repeat("a", 4096), "dir/b.bin": "bbb"}
+			if len(zr.File) != len(want) {
+				t.Fatalf("archive has %d files, want %d", len(zr.File), len(want))
+			}
+			for _, f := range zr.File {
+				rc, err := f.Open()
+				if err != nil {
+					t.Fatal(err)
+				}
+				content, err := io.ReadAll(rc)
+				_ = rc.Close()
+				if err != nil || string(content) != want[f.Name] {
+					t.Fatalf("%s: %d bytes, error %v", f.Name, len(content), err)
+				}
+			}
+
+			if got := do(t, httptest.NewRequest(http.MethodGet, "/missing", nil)); got.Status == http.StatusOK || !errors.Is(handlerErr, errMissing) {
+				t.Fatalf("failing first entry: status %d, error %v", got.Status, handlerErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package httpx
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// serveContentHeaders are the request headers http.ServeContent reads.
+var serveContentHeaders = []string{"Range", "If-Range", "If-Match", "If-None-Match", "If-Modified-Since", "If-Unmodified-Since"}
+
+// ServeContent responds with content the way http.ServeContent does, for
+// media endpoints on any adapter: it answers Range requests, including
+// If-Range, with status 206 and the requested bytes, and conditional
+// requests on modtime with status 304 or 412. The Content-Type is taken
+// from the extension of name, or else sniffed from content:
+//
+//	f, err := os.Open(video.Path)
+//	if err != nil {
+//		return err
+//	}
+//	return httpx.ServeContent(ctx, video.Name, video.UpdatedAt, f)
+//
+// Response headers are not readable through Context, so an entity tag set
+// with SetEntityTag does not take part in the conditions; If-Match and
+// If-None-Match only match "*". The body is sent through DataFromReader,
+// which fiber and hertz read after the handler returns, so the handler must
+// not close content: ServeContent closes it once sent when it implements
+// io.Closer.
+func ServeContent(ctx Context, name string, modtime time.Time, content io.ReadSeeker) error {
+	req := &http.Request{Method: ctx.Method(), URL: &url.URL{Path: ctx.Path()}, Header: make(http.Header)}
+	for _, key := range serveContentHeaders {
+		if v := ctx.Header(key); v != "" {
+			req.Header.Set(key, v)
+		}
+	}
+	pr, pw := io.Pipe()
+	w := &contentWriter{header: make(http.Header), body: pw, status: make(chan int, 1)}
+	go func() {
+		http.ServeContent(w, req, name, modtime, content)
+		w.WriteHeader(http.StatusOK)
+		if c, ok := content.(io.Closer); ok {
+			_ = c.Close()
+		}
+		_ = pw.Close()
+	}()
+	status := <-w.status
+	for key, values := range w.header {
+		if key != "Content-Type" && key != "Content-Length" && len(values) > 0 {
+			ctx.SetHeader(key, values[0])
+		}
+	}
+	if status == http.StatusNotModified {
+		_ = pr.Close()
+		return ctx.NoContent(status)
+	}
+	size := -1
+	if n, err := strconv.Atoi(w.header.Get("Content-Length")); err == nil {
+		size = n
+	}
+	return ctx.DataFromReader(status, w.header.Get("Content-Type"), pr, size)
+}
+
+// contentWriter is the http.ResponseWriter ServeContent runs
+// http.ServeContent with. It reports the status once the headers are
+// complete, and pipes the body to DataFromReader.
+type contentWriter struct {
+	header      http.Header
+	body        io.Writer
+	status      chan int
+	wroteHeader bool
+}
+
+func (w *contentWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *contentWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	w.status <- code
+}
+
+func (w *contentWriter) Write(p []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	return w.body.Write(p)
+}
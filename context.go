@@ -1,10 +1,14 @@
 package httpx
 
 import (
+	"bufio"
 	"context"
 	"io"
+	"iter"
 	"mime/multipart"
+	"net"
 	"net/http"
+	"net/url"
 )
 
 // RequestInfo exposes a stable, read-only view of an incoming HTTP request.
@@ -22,7 +26,12 @@ type RequestInfo interface {
 	Method() string
 	Path() string     // Always returns a request path
 	FullPath() string // Returns a route pattern when available, empty otherwise
-	ClientIP() string // Best-effort client IP detection
+	// ClientIP returns the peer address of the request, or behind the
+	// proxies given to the WithTrustedProxies option of the adapter, the
+	// client address they forwarded. Engines the adapter does not build
+	// itself, such as those of WithEngine, keep the trusted proxies of their
+	// framework unless that option is given.
+	ClientIP() string
 
 	Param(key string) string
 	Params() map[string]string // nil if no params
@@ -31,11 +40,35 @@ type RequestInfo interface {
 	Queries() map[string][]string // nil if no queries
 	RawQuery() string
 
+	// Header and HeaderValues match keys case-insensitively. HeaderValues
+	// returns one value per header line, in order, without splitting values
+	// on commas, and nil if the header was not sent. Several Cookie lines
+	// are joined into one value with "; ". Host is read with Header and
+	// HeaderValues, but is not listed by Headers and AllHeaders.
 	Header(key string) string
+	HeaderValues(key string) []string
 	Headers() map[string][]string // nil if no headers
 
 	Cookie(name string) (string, error) // Returns error if cookie not found
 	Cookies() map[string]string         // nil if no cookies
+
+	// AllQueries, AllHeaders and AllCookies iterate over the values of
+	// Queries, Headers and Cookies without building maps, which makes them
+	// cheaper for walking all values. A key with several values is yielded
+	// once per value, and header keys are canonical.
+	AllQueries() iter.Seq2[string, string]
+	AllHeaders() iter.Seq2[string, string]
+	AllCookies() iter.Seq2[string, string]
+
+	// Scheme and Host honour the Forwarded and X-Forwarded-* headers, see
+	// ForwardedScheme and ForwardedHost.
+	Scheme() string       // "http" or "https"
+	Host() string         // Host requested by the client, may include a port
+	Proto() string        // Protocol version, e.g. "HTTP/1.1"
+	ContentLength() int64 // -1 if unknown
+	UserAgent() string    // Empty if not sent
+	Referer() string      // Empty if not sent
+	URL() *url.URL        // Absolute request URL; a new copy on every call
 }
 
 // BodyAccess provides access to the raw request body.
@@ -51,6 +84,8 @@ type BodyAccess interface {
 	//
 	// Calling this method may consume the underlying request body.
 	// Implementations should make best-effort to allow subsequent reads.
+	// The slice may be kept after the request completes but must not be
+	// modified. See UnsafeBody for a variant without a copy.
 	BodyRaw() ([]byte, error)
 
 	// BodyReader returns a reader for the request body.
@@ -249,6 +284,17 @@ type ResponseInfo interface {
 	StatusCode() int
 }
 
+// ResponseSizer reports the size of the response body.
+//
+// This optional capability is kept apart from ResponseInfo so that existing
+// implementations of ResponseInfo keep satisfying it.
+type ResponseSizer interface {
+	// BodySize returns the number of response body bytes written so far.
+	// For streamed bodies it reports the declared length when known, and 0
+	// otherwise; it never consumes the stream.
+	BodySize() int
+}
+
 // NativeContextProvider exposes the underlying framework context.
 //
 // This optional capability is an escape hatch for framework-specific features
@@ -257,6 +303,84 @@ type NativeContextProvider interface {
 	NativeContext() any
 }
 
+// Flusher sends buffered response data to the client, for streaming
+// responses written through the HTTPInterop response writer.
+//
+// This optional capability is provided by contexts backed by net/http, such
+// as those of ginx, echox and ToHTTPHandler. Fiber and hertz build the whole
+// response before sending it, so their contexts do not provide it.
+type Flusher interface {
+	// Flush commits the status and headers if needed and sends the body
+	// written so far. It returns an error wrapping http.ErrNotSupported when
+	// the underlying writer cannot flush.
+	Flush() error
+}
+
+// UnsafeBody gives access to the request body buffer of the framework,
+// without the copy BodyRaw makes to keep the body valid after the request.
+//
+// This optional capability is provided by fiberx and hertzx, whose
+// frameworks reuse request buffers across requests. Contexts backed by
+// net/http cache the body instead and do not copy it in BodyRaw.
+type UnsafeBody interface {
+	// BodyRawUnsafe returns the request body like BodyRaw, without copying
+	// it. The slice is owned by the framework: it must not be modified, nor
+	// used after the handler returns.
+	BodyRawUnsafe() ([]byte, error)
+}
+
+// RequestBodySetter lets middleware replace the request body before
+// handlers read it, for example with its decoded form.
+//
+// This optional capability is provided by every built-in adapter.
+type RequestBodySetter interface {
+	// SetRequestBody replaces the request body with body, which is sent
+	// without content coding: Content-Length is updated and
+	// Content-Encoding removed. BodyRaw, BodyReader and the binders read
+	// the new body afterwards.
+	SetRequestBody(body []byte)
+}
+
+// AbortedKey is the StateStore key under which adapters record that the
+// chain was aborted, see Aborter.
+const AbortedKey = "httpx.aborted"
+
+// Aborter lets middleware stop the handlers that follow it from running,
+// optionally writing the response, like gin's Abort.
+//
+// Once the chain is aborted, Next returns nil without running the rest of
+// the chain. Abort does not interrupt the running handler, nor affect
+// middleware that already called Next; those see IsAborted once Next
+// returns. Returning without calling Next also ends the chain, so Abort
+// mostly makes it explicit. Errors are handled as usual: an error returned
+// after Abort still reaches the error handler.
+//
+// This optional capability is provided by every built-in adapter.
+type Aborter interface {
+	// Abort stops the rest of the chain from running.
+	Abort()
+	// AbortWithStatus aborts and responds with status code and no body.
+	AbortWithStatus(code int) error
+	// AbortWithStatusJSON aborts and responds with status code and obj
+	// encoded as JSON.
+	AbortWithStatusJSON(code int, obj any) error
+	// IsAborted reports whether the chain of the request was aborted.
+	IsAborted() bool
+}
+
+// Hijacker lets a handler take over the client connection, for long-polling,
+// tunnels or protocols such as websockets.
+//
+// This optional capability is provided by the same contexts as Flusher.
+type Hijacker interface {
+	// Hijack returns the connection and its buffered reader and writer. It
+	// returns an error wrapping http.ErrNotSupported when the connection
+	// cannot be hijacked, e.g. over HTTP/2, or after the body was written.
+	// After a successful call the handler owns the connection and must not
+	// write the response through the context.
+	Hijack() (net.Conn, *bufio.ReadWriter, error)
+}
+
 // StateStore carries request-scoped values shared across the handler chain.
 //
 // StateStore provides a simple key-value storage that is scoped to the
@@ -264,12 +388,11 @@ type NativeContextProvider interface {
 // to be shared between middleware and handlers handling the same request,
 // within the same handler chain execution.
 //
-// IMPORTANT: Values stored via Set are NOT propagated through the standard
-// context.Context returned by Context.Context(). They are visible only to
-// middleware and handlers that share the same httpx.Context instance for
-// the current request. To propagate values through context.Context (e.g.
-// into downstream business logic, goroutines, or RPC calls), use
-// SetContext with context.WithValue instead.
+// Values stored via Set are also visible through the standard
+// context.Context returned by Context.Context(), and contexts derived from
+// it, under StateKey keys. Lookups read the store of the current request,
+// so such contexts must not be used after the request completes; use Detach
+// for background goroutines.
 //
 // Stored values MUST NOT be accessed concurrently without external
 // synchronization unless the implementation explicitly guarantees
@@ -278,9 +401,9 @@ type StateStore interface {
 	// Set associates the given value with the provided key for the
 	// lifetime of the current request.
 	//
-	// The value is accessible only within the current handler chain
-	// (i.e., by subsequent middleware and the final handler). It is
-	// NOT propagated through context.Context.
+	// The value is accessible within the current handler chain (i.e., by
+	// subsequent middleware and the final handler), and through the
+	// context.Context returned by Context under StateKey(key).
 	//
 	// Setting a value with an existing key replaces the previous value.
 	Set(key string, val any)
@@ -329,9 +452,9 @@ type Context interface {
 	// and respects request cancellation and deadlines. It is safe to pass
 	// this value to downstream business logic, database calls, or RPC clients.
 	//
-	// Values stored via StateStore.Set are NOT visible through the returned
-	// context.Context. Use SetContext with context.WithValue to propagate
-	// values through the standard context chain.
+	// Values stored via StateStore.Set are visible through the returned
+	// context.Context, and contexts derived from it, under StateKey keys.
+	// Other values can be propagated with SetContext and context.WithValue.
 	Context() context.Context
 
 	// SetContext replaces the standard Go context.Context for the current request.
@@ -362,7 +485,55 @@ func AsResponseInfo(ctx Context) (ResponseInfo, bool) {
 	return ri, ok
 }
 
+// AsResponseSizer returns response body size capability when supported.
+func AsResponseSizer(ctx Context) (ResponseSizer, bool) {
+	rs, ok := ctx.(ResponseSizer)
+	return rs, ok
+}
+
+// AsFlusher returns response flushing capability when supported.
+func AsFlusher(ctx Context) (Flusher, bool) {
+	f, ok := ctx.(Flusher)
+	return f, ok
+}
+
+// AsHijacker returns connection hijacking capability when supported.
+func AsHijacker(ctx Context) (Hijacker, bool) {
+	h, ok := ctx.(Hijacker)
+	return h, ok
+}
+
+// AsUnsafeBody returns zero-copy body access when supported.
+func AsUnsafeBody(ctx Context) (UnsafeBody, bool) {
+	u, ok := ctx.(UnsafeBody)
+	return u, ok
+}
+
+// AsRequestBodySetter returns request body replacement when supported.
+func AsRequestBodySetter(ctx Context) (RequestBodySetter, bool) {
+	s, ok := ctx.(RequestBodySetter)
+	return s, ok
+}
+
+// AsAborter returns chain abortion when supported.
+func AsAborter(ctx Context) (Aborter, bool) {
+	a, ok := ctx.(Aborter)
+	return a, ok
+}
+
+// BodyRawUnsafe returns the request body without a copy when ctx supports
+// UnsafeBody, and BodyRaw otherwise. The slice must not be modified, nor
+// used after the handler returns.
+func BodyRawUnsafe(ctx Context) ([]byte, error) {
+	if u, ok := AsUnsafeBody(ctx); ok {
+		return u.BodyRawUnsafe()
+	}
+	return ctx.BodyRaw()
+}
+
 // AsNativeContext returns the underlying native context when supported.
+// Adapters provide typed wrappers such as ginx.Unwrap and fiberx.Unwrap, which
+// also document how long the native context remains valid.
 func AsNativeContext[T any](ctx Context) (T, bool) {
 	var zero T
 	nativeProvider, ok := ctx.(NativeContextProvider)
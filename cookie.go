@@ -0,0 +1,197 @@
+package httpx
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// ErrCookieSecretNotSet reports a signed cookie used before
+	// SetCookieSecret configured a secret.
+	ErrCookieSecretNotSet = errors.New("httpx: cookie secret not set")
+	// ErrInvalidCookieSignature reports a signed cookie whose value was
+	// not signed with a configured secret or was modified.
+	ErrInvalidCookieSignature = errors.New("httpx: invalid cookie signature")
+)
+
+// cookieDefaults are the attributes NewCookie starts from.
+type cookieDefaults struct {
+	secure   bool
+	httpOnly bool
+	sameSite http.SameSite
+}
+
+var (
+	defaultCookie atomic.Pointer[cookieDefaults]
+	cookieSecrets atomic.Pointer[[][]byte]
+)
+
+func init() {
+	SecureCookieDefaults(true, true, http.SameSiteLaxMode)
+}
+
+// SecureCookieDefaults sets the Secure, HttpOnly and SameSite attributes of
+// the cookies created by NewCookie and SetCookieValue, package-wide. The
+// defaults are Secure, HttpOnly and SameSite=Lax; development servers on
+// plain HTTP can turn Secure off, and options override the defaults per
+// cookie.
+func SecureCookieDefaults(secure, httpOnly bool, sameSite http.SameSite) {
+	defaultCookie.Store(&cookieDefaults{secure: secure, httpOnly: httpOnly, sameSite: sameSite})
+}
+
+// CookieOption sets an attribute of a cookie created by NewCookie.
+type CookieOption func(*http.Cookie)
+
+// WithCookiePath sets the cookie's Path, "/" by default.
+func WithCookiePath(path string) CookieOption {
+	return func(c *http.Cookie) {
+		c.Path = path
+	}
+}
+
+// WithCookieDomain sets the cookie's Domain.
+func WithCookieDomain(domain string) CookieOption {
+	return func(c *http.Cookie) {
+		c.Domain = domain
+	}
+}
+
+// WithCookieMaxAge sets the cookie's lifetime. A negative duration deletes
+// the cookie; without it the cookie lasts for the browser session.
+func WithCookieMaxAge(d time.Duration) CookieOption {
+	return func(c *http.Cookie) {
+		if d < 0 {
+			c.MaxAge = -1
+			return
+		}
+		c.MaxAge = int(d / time.Second)
+	}
+}
+
+// WithCookieExpires sets the cookie's Expires attribute.
+func WithCookieExpires(t time.Time) CookieOption {
+	return func(c *http.Cookie) {
+		c.Expires = t
+	}
+}
+
+// WithCookieSecure overrides the package-wide Secure default.
+func WithCookieSecure(secure bool) CookieOption {
+	return func(c *http.Cookie) {
+		c.Secure = secure
+	}
+}
+
+// WithCookieHTTPOnly overrides the package-wide HttpOnly default.
+func WithCookieHTTPOnly(httpOnly bool) CookieOption {
+	return func(c *http.Cookie) {
+		c.HttpOnly = httpOnly
+	}
+}
+
+// WithCookieSameSite overrides the package-wide SameSite default.
+func WithCookieSameSite(sameSite http.SameSite) CookieOption {
+	return func(c *http.Cookie) {
+		c.SameSite = sameSite
+	}
+}
+
+// NewCookie returns a cookie with the package-wide defaults, see
+// SecureCookieDefaults, and Path "/", changed by opts.
+func NewCookie(name, value string, opts ...CookieOption) *http.Cookie {
+	defaults := defaultCookie.Load()
+	cookie := &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		Secure:   defaults.secure,
+		HttpOnly: defaults.httpOnly,
+		SameSite: defaults.sameSite,
+	}
+	for _, opt := range opts {
+		opt(cookie)
+	}
+	return cookie
+}
+
+// SetCookieValue sets a cookie created by NewCookie on the response.
+func SetCookieValue(ctx Context, name, value string, opts ...CookieOption) {
+	ctx.SetCookie(NewCookie(name, value, opts...))
+}
+
+// DeleteCookie tells the client to remove a cookie. opts must repeat the
+// Path and Domain the cookie was set with.
+func DeleteCookie(ctx Context, name string, opts ...CookieOption) {
+	opts = append(opts, WithCookieMaxAge(-1))
+	ctx.SetCookie(NewCookie(name, "", opts...))
+}
+
+// SetCookieSecret sets the secret that signs the cookies of
+// SetSignedCookie. SignedCookie also accepts values signed with the previous
+// secrets, so secrets can be rotated without invalidating existing cookies.
+func SetCookieSecret(secret []byte, previous ...[]byte) {
+	secrets := make([][]byte, 0, len(previous)+1)
+	secrets = append(secrets, secret)
+	secrets = append(secrets, previous...)
+	cookieSecrets.Store(&secrets)
+}
+
+// SetSignedCookie sets a cookie whose value is signed with the cookie
+// secret, so SignedCookie can detect values modified by the client. The
+// value is encoded but not encrypted: clients can still read it.
+func SetSignedCookie(ctx Context, name, value string, opts ...CookieOption) error {
+	secrets := cookieSecrets.Load()
+	if secrets == nil {
+		return ErrCookieSecretNotSet
+	}
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(value))
+	signature := signCookie((*secrets)[0], name, encoded)
+	SetCookieValue(ctx, name, encoded+"."+signature, opts...)
+	return nil
+}
+
+// SignedCookie returns the value of a cookie set with SetSignedCookie. It
+// returns http.ErrNoCookie when the cookie is missing, and an error wrapping
+// ErrInvalidCookieSignature with status 400 when its signature does not
+// match any cookie secret.
+func SignedCookie(ctx Context, name string) (string, error) {
+	secrets := cookieSecrets.Load()
+	if secrets == nil {
+		return "", ErrCookieSecretNotSet
+	}
+	raw, err := ctx.Cookie(name)
+	if err != nil {
+		return "", err
+	}
+	invalid := BadRequestError(ErrInvalidCookieSignature)
+	encoded, signature, ok := strings.Cut(raw, ".")
+	if !ok {
+		return "", invalid
+	}
+	for _, secret := range *secrets {
+		if hmac.Equal([]byte(signature), []byte(signCookie(secret, name, encoded))) {
+			value, err := base64.RawURLEncoding.DecodeString(encoded)
+			if err != nil {
+				return "", invalid
+			}
+			return string(value), nil
+		}
+	}
+	return "", invalid
+}
+
+// signCookie signs the cookie's name with its value, so a signed value
+// cannot be replayed under another cookie name.
+func signCookie(secret []byte, name, value string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(name))
+	mac.Write([]byte{'='})
+	mac.Write([]byte(value))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
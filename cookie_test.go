@@ -0,0 +1,86 @@
+package httpx
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNewCookieDefaults(t *testing.T) {
+	cookie := NewCookie("session", "abc")
+	if cookie.Path != "/" || !cookie.Secure || !cookie.HttpOnly || cookie.SameSite != http.SameSiteLaxMode {
+		t.Fatalf("NewCookie = %+v, want secure defaults", cookie)
+	}
+	cookie = NewCookie("theme", "dark", WithCookieHTTPOnly(false), WithCookieMaxAge(time.Hour), WithCookiePath("/app"))
+	if cookie.HttpOnly || cookie.MaxAge != 3600 || cookie.Path != "/app" {
+		t.Fatalf("NewCookie with options = %+v", cookie)
+	}
+
+	SecureCookieDefaults(false, true, http.SameSiteStrictMode)
+	defer SecureCookieDefaults(true, true, http.SameSiteLaxMode)
+	if cookie := NewCookie("session", "abc"); cookie.Secure || cookie.SameSite != http.SameSiteStrictMode {
+		t.Fatalf("NewCookie after SecureCookieDefaults = %+v", cookie)
+	}
+}
+
+func TestSignedCookie(t *testing.T) {
+	defer cookieSecrets.Store(nil)
+
+	roundTrip := func(set func(Context) error, read func(Context) (string, error), tamper func(*http.Cookie)) (string, error) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		if err := set(NewContext(rec, httptest.NewRequest(http.MethodGet, "/", nil))); err != nil {
+			t.Fatal(err)
+		}
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		for _, cookie := range rec.Result().Cookies() {
+			if tamper != nil {
+				tamper(cookie)
+			}
+			req.AddCookie(cookie)
+		}
+		return read(NewContext(httptest.NewRecorder(), req))
+	}
+	set := func(ctx Context) error { return SetSignedCookie(ctx, "user", "42; admin=1") }
+	read := func(ctx Context) (string, error) { return SignedCookie(ctx, "user") }
+
+	if err := set(NewContext(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))); !errors.Is(err, ErrCookieSecretNotSet) {
+		t.Fatalf("SetSignedCookie without secret: %v, want ErrCookieSecretNotSet", err)
+	}
+
+	SetCookieSecret([]byte("old"))
+	if got, err := roundTrip(set, read, nil); err != nil || got != "42; admin=1" {
+		t.Fatalf("SignedCookie = %q, %v", got, err)
+	}
+	_, err := roundTrip(set, read, func(c *http.Cookie) { c.Value = "NDM" + c.Value[2:] })
+	if !errors.Is(err, ErrInvalidCookieSignature) {
+		t.Fatalf("tampered cookie: %v, want ErrInvalidCookieSignature", err)
+	}
+	_, err = roundTrip(set, func(ctx Context) (string, error) { return SignedCookie(ctx, "other") },
+		func(c *http.Cookie) { c.Name = "other" })
+	if !errors.Is(err, ErrInvalidCookieSignature) {
+		t.Fatalf("cookie renamed: %v, want ErrInvalidCookieSignature", err)
+	}
+
+	// Cookies signed with the previous secret stay valid after rotation.
+	rotate := func(ctx Context) error {
+		err := set(ctx)
+		SetCookieSecret([]byte("new"), []byte("old"))
+		return err
+	}
+	SetCookieSecret([]byte("old"))
+	if got, err := roundTrip(rotate, read, nil); err != nil || got != "42; admin=1" {
+		t.Fatalf("SignedCookie after rotation = %q, %v", got, err)
+	}
+	SetCookieSecret([]byte("old"))
+	rotateOut := func(ctx Context) error {
+		err := set(ctx)
+		SetCookieSecret([]byte("new"))
+		return err
+	}
+	if _, err := roundTrip(rotateOut, read, nil); !errors.Is(err, ErrInvalidCookieSignature) {
+		t.Fatalf("cookie signed with a retired secret: %v, want ErrInvalidCookieSignature", err)
+	}
+}
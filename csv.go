@@ -0,0 +1,153 @@
+package httpx
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// CSVContentType is the Content-Type of the bodies written by CSV and
+// CSVStream.
+const CSVContentType = "text/csv; charset=utf-8"
+
+// CSVOptions control the bodies written by CSV and CSVStream.
+type CSVOptions struct {
+	// Filename, if set, makes clients download the body as an attachment of
+	// that name.
+	Filename string
+	// Comma is the field delimiter, ',' when zero.
+	Comma rune
+	// UseCRLF ends records with \r\n, as RFC 4180 does.
+	UseCRLF bool
+	// BOM starts the body with a UTF-8 byte order mark, without which Excel
+	// misreads non-ASCII text.
+	BOM bool
+}
+
+// setFilename makes the response an attachment named Filename, if set.
+func (o CSVOptions) setFilename(ctx Context) {
+	if o.Filename != "" {
+		ctx.SetHeader("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": o.Filename}))
+	}
+}
+
+// newWriter writes the byte order mark to w and returns a csv.Writer for
+// the records.
+func (o CSVOptions) newWriter(w io.Writer) (*csv.Writer, error) {
+	if o.BOM {
+		if _, err := io.WriteString(w, utf8BOM); err != nil {
+			return nil, err
+		}
+	}
+	cw := csv.NewWriter(w)
+	if o.Comma != 0 {
+		cw.Comma = o.Comma
+	}
+	cw.UseCRLF = o.UseCRLF
+	return cw, nil
+}
+
+const utf8BOM = "\ufeff"
+
+// CSV writes rows as a CSV body with status code:
+//
+//	return httpx.CSV(ctx, http.StatusOK, [][]string{
+//		{"id", "name"},
+//		{"1", "Ada"},
+//	}, httpx.CSVOptions{Filename: "users.csv"})
+func CSV(ctx Context, code int, rows [][]string, opts CSVOptions) error {
+	var buf bytes.Buffer
+	cw, err := opts.newWriter(&buf)
+	if err != nil {
+		return err
+	}
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+	opts.setFilename(ctx)
+	return ctx.Bytes(code, buf.Bytes(), CSVContentType)
+}
+
+// CSVStream writes the rows of seq as a CSV body with status code, as seq
+// yields them rather than buffered, for exports too large to hold in
+// memory. Like JSONArrayStream, it sends the body through DataFromReader,
+// so seq must not use ctx.
+func CSVStream(ctx Context, code int, seq iter.Seq[[]string], opts CSVOptions) error {
+	opts.setFilename(ctx)
+	pr, pw := io.Pipe()
+	go func() {
+		cw, err := opts.newWriter(pw)
+		if err != nil {
+			_ = pw.CloseWithError(err)
+			return
+		}
+		for row := range seq {
+			if err := cw.Write(row); err != nil {
+				_ = pw.CloseWithError(err)
+				return
+			}
+		}
+		cw.Flush()
+		_ = pw.CloseWithError(cw.Error())
+	}()
+	return ctx.DataFromReader(code, CSVContentType, pr, -1)
+}
+
+// BindCSV decodes a CSV request body into dst, one T per record after the
+// header record. The fields of T are bound like BindValues binds them, using
+// the "csv" tag to name their columns, so that a column absent from the
+// header, or empty in a record, takes the value of the default tag:
+//
+//	type Row struct {
+//		Email  string    `csv:"email" binding:"required"`
+//		Joined time.Time `csv:"joined" time_format:"2006-01-02"`
+//	}
+//
+//	var rows []Row
+//	if err := httpx.BindCSV(ctx, &rows); err != nil {
+//		return err
+//	}
+//
+// Records are appended to *dst. Malformed bodies and values fail with
+// status 400, naming the line of the record.
+func BindCSV[T any](ctx Context, dst *[]T) error {
+	body := ctx.BodyReader()
+	defer func() { _ = body.Close() }()
+	r := csv.NewReader(body)
+	header, err := r.Read()
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	if err != nil {
+		return WithStatus(http.StatusBadRequest, err)
+	}
+	header[0] = strings.TrimPrefix(header[0], utf8BOM)
+	for i, name := range header {
+		header[i] = strings.TrimSpace(name)
+	}
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return WithStatus(http.StatusBadRequest, err)
+		}
+		values := make(map[string][]string, len(header))
+		for i, name := range header {
+			values[name] = []string{record[i]}
+		}
+		var v T
+		if err := BindValues(&v, "csv", values); err != nil {
+			line, _ := r.FieldPos(0)
+			return WithStatus(http.StatusBadRequest, fmt.Errorf("csv line %d: %w", line, err))
+		}
+		*dst = append(*dst, v)
+	}
+}
@@ -0,0 +1,78 @@
+package httpx
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCSV(t *testing.T) {
+	rec := httptest.NewRecorder()
+	ctx := newHTTPContext(rec, httptest.NewRequest(http.MethodGet, "/export", nil), nil)
+	rows := [][]string{{"id", "name"}, {"1", "Ada; \"the first\""}}
+	if err := CSV(ctx, http.StatusOK, rows, CSVOptions{Filename: "users €.csv", Comma: ';', UseCRLF: true, BOM: true}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := rec.Body.String(), "\ufeffid;name\r\n1;\"Ada; \"\"the first\"\"\"\r\n"; got != want {
+		t.Fatalf("body = %q, want %q", got, want)
+	}
+	if got := rec.Header().Get("Content-Type"); got != CSVContentType {
+		t.Fatalf("Content-Type = %q", got)
+	}
+	if got := rec.Header().Get("Content-Disposition"); got != "attachment; filename*=utf-8''users%20%E2%82%AC.csv" {
+		t.Fatalf("Content-Disposition = %q", got)
+	}
+}
+
+func TestBindCSV(t *testing.T) {
+	type row struct {
+		Email  string    `csv:"email" binding:"required"`
+		Age    int       `csv:"age"`
+		Role   string    `csv:"role" default:"member"`
+		Joined time.Time `csv:"joined" time_format:"2006-01-02"`
+	}
+	bind := func(body string) ([]row, error) {
+		ctx := newHTTPContext(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/import", strings.NewReader(body)), nil)
+		var rows []row
+		err := BindCSV(ctx, &rows)
+		return rows, err
+	}
+
+	rows, err := bind("\ufeff email ,age,role,joined\na@example.com,30,,2024-02-01\nb@example.com,41,admin,2023-12-24\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []row{
+		{"a@example.com", 30, "member", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"b@example.com", 41, "admin", time.Date(2023, 12, 24, 0, 0, 0, 0, time.UTC)},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("rows = %+v", rows)
+	}
+	for i := range want {
+		if rows[i].Email != want[i].Email || rows[i].Age != want[i].Age || rows[i].Role != want[i].Role || !rows[i].Joined.Equal(want[i].Joined) {
+			t.Fatalf("row %d = %+v, want %+v", i, rows[i], want[i])
+		}
+	}
+
+	if rows, err := bind(""); err != nil || len(rows) != 0 {
+		t.Fatalf("empty body = %v, %v", rows, err)
+	}
+	for _, body := range []string{
+		"email,age\na@example.com,old\n",
+		"email,age\n,3\n",
+		"email,age\na@example.com\n",
+	} {
+		_, err := bind(body)
+		var se StatusError
+		if !errors.As(err, &se) || se.GetStatus() != http.StatusBadRequest {
+			t.Fatalf("%q: error %v, want status 400", body, err)
+		}
+	}
+	if _, err := bind("email,age\na@example.com,1\n,2\n"); err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Fatalf("error %v, want the line of the record", err)
+	}
+}
@@ -0,0 +1,49 @@
+package httpx
+
+import (
+	"net/http"
+	"time"
+)
+
+var deprecatedKey = NewKey[bool]("deprecated")
+
+// Deprecated wraps the handler of a deprecated route so its responses
+// announce the deprecation, letting clients migrate before the route is
+// removed:
+//
+//	r.GET("/v1/orders", httpx.Deprecated(listOrders, sunset, "https://example.com/migrate"))
+//
+// Responses carry "Deprecation: true", a Sunset header with sunset unless it
+// is zero, and a Link header to link with relation "deprecation" unless it is
+// empty. The request is marked for IsDeprecated, which Metrics of the
+// middleware package counts as http_deprecated_requests_total.
+//
+// The headers are set before handler runs; a handler setting Link itself
+// replaces the deprecation link.
+func Deprecated(handler Handler, sunset time.Time, link string) Handler {
+	var sunsetValue, linkValue string
+	if !sunset.IsZero() {
+		sunsetValue = sunset.UTC().Format(http.TimeFormat)
+	}
+	if link != "" {
+		linkValue = "<" + link + `>; rel="deprecation"`
+	}
+	return func(ctx Context) error {
+		SetTyped(ctx, deprecatedKey, true)
+		ctx.SetHeader("Deprecation", "true")
+		if sunsetValue != "" {
+			ctx.SetHeader("Sunset", sunsetValue)
+		}
+		if linkValue != "" {
+			ctx.SetHeader("Link", linkValue)
+		}
+		return handler(ctx)
+	}
+}
+
+// IsDeprecated reports whether the request was handled by a handler wrapped
+// with Deprecated. Middleware reads it after Next returns.
+func IsDeprecated(ctx StateStore) bool {
+	deprecated, _ := deprecatedKey.Get(ctx)
+	return deprecated
+}
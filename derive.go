@@ -0,0 +1,54 @@
+package httpx
+
+import (
+	"context"
+	"time"
+)
+
+// WithValue makes val visible under key through ctx.Context() for the rest
+// of the chain, on every adapter:
+//
+//	httpx.WithValue(ctx, tenantKey{}, tenant)
+//	return ctx.Next()
+//
+// It is a shorthand for SetContext with context.WithValue.
+func WithValue(ctx Context, key, val any) {
+	ctx.SetContext(context.WithValue(ctx.Context(), key, val))
+}
+
+// Derive replaces the context.Context of ctx with base for the rest of the
+// chain, so middleware can hand handlers a context carrying other values,
+// such as one prepared by a job runner or dependency container. The
+// replacement keeps the request's lifetime: it is canceled when the request
+// context is, and gets its deadline when that is earlier than the one of
+// base. StateStore values stay visible through it.
+//
+// The returned func releases the replacement and must be called once the
+// chain returns:
+//
+//	defer httpx.Derive(ctx, base)()
+//	return ctx.Next()
+func Derive(ctx Context, base context.Context) context.CancelFunc {
+	parent := ctx.Context()
+	derived, cancel := context.WithCancelCause(base)
+	stop := context.AfterFunc(parent, func() {
+		cancel(context.Cause(parent))
+	})
+	c := derived
+	cancelDeadline := context.CancelFunc(func() {})
+	if d, ok := parent.Deadline(); ok && !deadlineBefore(base, d) {
+		c, cancelDeadline = context.WithDeadline(derived, d)
+	}
+	ctx.SetContext(c)
+	return func() {
+		stop()
+		cancelDeadline()
+		cancel(context.Canceled)
+	}
+}
+
+// deadlineBefore reports whether ctx has a deadline before d.
+func deadlineBefore(ctx context.Context, d time.Time) bool {
+	own, ok := ctx.Deadline()
+	return ok && own.Before(d)
+}
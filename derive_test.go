@@ -0,0 +1,69 @@
+package httpx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type deriveTestKey struct{}
+
+func TestDerive(t *testing.T) {
+	reqCtx, cancelReq := context.WithTimeout(context.Background(), time.Minute)
+	defer cancelReq()
+	reqDeadline, _ := reqCtx.Deadline()
+	stateKey := NewKey[string]("derive_test")
+
+	var handlerErr error
+	h := ToHTTPHandler(func(ctx Context) error {
+		c := ctx.Context()
+		if c.Value(deriveTestKey{}) != "base" {
+			return errors.New("base value not visible")
+		}
+		if v, _ := stateKey.Value(c); v != "state" {
+			return errors.New("state not visible")
+		}
+		if d, ok := c.Deadline(); !ok || !d.Equal(reqDeadline) {
+			return errors.New("request deadline not kept")
+		}
+		cancelReq()
+		select {
+		case <-c.Done():
+		case <-time.After(time.Second):
+			return errors.New("request cancellation not propagated")
+		}
+		if !errors.Is(context.Cause(c), context.Canceled) {
+			return errors.New("unexpected cause")
+		}
+		return ctx.NoContent(http.StatusNoContent)
+	}, WithHTTPMiddleware(func(ctx Context) error {
+		SetTyped(ctx, stateKey, "state")
+		defer Derive(ctx, context.WithValue(context.Background(), deriveTestKey{}, "base"))()
+		return ctx.Next()
+	}), WithHTTPErrorHandler(func(ctx Context, err error) {
+		handlerErr = err
+	}))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil).WithContext(reqCtx))
+	if handlerErr != nil || rec.Code != http.StatusNoContent {
+		t.Fatalf("status %d, error %v", rec.Code, handlerErr)
+	}
+}
+
+func TestWithValue(t *testing.T) {
+	var got any
+	h := ToHTTPHandler(func(ctx Context) error {
+		got = ctx.Context().Value(deriveTestKey{})
+		return nil
+	}, WithHTTPMiddleware(func(ctx Context) error {
+		WithValue(ctx, deriveTestKey{}, "value")
+		return ctx.Next()
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if got != "value" {
+		t.Fatalf("value = %v", got)
+	}
+}
@@ -0,0 +1,39 @@
+package httpx
+
+import "context"
+
+// Detach returns a context for work that outlives the request, such as a
+// goroutine started by a handler. It carries the values of ctx.Context(),
+// like the logger set by SetLogger, outgoing headers and trace spans, but not
+// its cancellation or deadline.
+//
+// StateStore values are not reachable through the returned context, since
+// fiber and hertz reuse their request state once the response is sent; the
+// values of keys are copied instead and remain visible under StateKey.
+func Detach(ctx Context, keys ...string) context.Context {
+	var state map[string]any
+	for _, key := range keys {
+		if val, ok := ctx.Get(key); ok {
+			if state == nil {
+				state = make(map[string]any, len(keys))
+			}
+			state[key] = val
+		}
+	}
+	return detachedContext{Context: context.WithoutCancel(ctx.Context()), state: state}
+}
+
+type detachedContext struct {
+	context.Context
+	state map[string]any
+}
+
+func (c detachedContext) Value(key any) any {
+	switch k := key.(type) {
+	case StateKey:
+		return c.state[string(k)]
+	case stateStoreKey:
+		return nil
+	}
+	return c.Context.Value(key)
+}
@@ -1,32 +1,59 @@
 package echox
 
 import (
-	"bytes"
+	"bufio"
 	"context"
+	"errors"
 	"io"
+	"iter"
 	"mime/multipart"
+	"net"
 	"net/http"
-	"net/textproto"
+	"net/url"
 	"strconv"
+	"sync"
 
 	"github.com/go-sphere/httpx"
 	"github.com/labstack/echo/v4"
 )
 
 var (
-	_ httpx.Context = (*echoContext)(nil)
+	_ httpx.Context           = (*echoContext)(nil)
+	_ httpx.ChainContext      = (*echoContext)(nil)
+	_ httpx.RequestBodySetter = (*echoContext)(nil)
+	_ httpx.Aborter           = (*echoContext)(nil)
+	_ httpx.ResponseSizer     = (*echoContext)(nil)
 )
 
 type echoContext struct {
-	ctx    echo.Context
-	next   echo.HandlerFunc
-	binder echo.DefaultBinder
-}
-
-func newEchoContext(ctx echo.Context) *echoContext {
-	return &echoContext{
-		ctx: ctx,
+	ctx       echo.Context
+	next      echo.HandlerFunc
+	chainNext httpx.Handler
+	aborted   bool
+	state     *httpx.RequestState
+}
+
+// echoContexts pools the contexts passed to handlers and middleware. A
+// context is released when the handler or middleware it was passed to
+// returns, so it must not be kept, nor used from other goroutines, after
+// that; httpx.Detach keeps request values for work that outlives it.
+var echoContexts = sync.Pool{New: func() any { return new(echoContext) }}
+
+func acquireEchoContext(ec echo.Context) *echoContext {
+	c := echoContexts.Get().(*echoContext)
+	c.ctx = ec
+	return c
+}
+
+// releaseEchoContext closes the request state of c, so that a context.Context
+// kept from it no longer resolves request state, clears c and returns it to
+// the pool.
+func releaseEchoContext(c *echoContext) {
+	if c.state != nil {
+		c.state.Close()
 	}
+	*c = echoContext{}
+	echoContexts.Put(c)
 }
 
 // Request (httpx.Request)
@@ -89,20 +116,15 @@ func (c *echoContext) RawQuery() string {
 }
 
 func (c *echoContext) Header(key string) string {
-	return c.ctx.Request().Header.Get(key)
+	return httpx.RequestHeader(c.ctx.Request(), key)
+}
+
+func (c *echoContext) HeaderValues(key string) []string {
+	return httpx.RequestHeaderValues(c.ctx.Request(), key)
 }
 
 func (c *echoContext) Headers() map[string][]string {
-	src := c.ctx.Request().Header
-	if len(src) == 0 {
-		return nil
-	}
-	out := make(map[string][]string, len(src))
-	for k, v := range src {
-		ck := textproto.CanonicalMIMEHeaderKey(k)
-		out[ck] = append([]string(nil), v...)
-	}
-	return out
+	return httpx.RequestHeaders(c.ctx.Request().Header)
 }
 
 func (c *echoContext) Cookie(name string) (string, error) {
@@ -125,37 +147,90 @@ func (c *echoContext) Cookies() map[string]string {
 	return out
 }
 
+func (c *echoContext) AllQueries() iter.Seq2[string, string] {
+	return httpx.QueryPairs(c.ctx.Request().URL.RawQuery)
+}
+
+func (c *echoContext) AllHeaders() iter.Seq2[string, string] {
+	return httpx.HeaderPairs(c.ctx.Request().Header)
+}
+
+func (c *echoContext) AllCookies() iter.Seq2[string, string] {
+	return httpx.CookiePairs(c.ctx.Request())
+}
+
+func (c *echoContext) Scheme() string {
+	return httpx.ForwardedScheme(c.ctx.Request().Header.Get, c.ctx.Request().TLS != nil)
+}
+
+func (c *echoContext) Host() string {
+	return httpx.ForwardedHost(c.ctx.Request().Header.Get, c.ctx.Request().Host)
+}
+
+func (c *echoContext) Proto() string {
+	return c.ctx.Request().Proto
+}
+
+func (c *echoContext) ContentLength() int64 {
+	return c.ctx.Request().ContentLength
+}
+
+func (c *echoContext) UserAgent() string {
+	return c.ctx.Request().UserAgent()
+}
+
+func (c *echoContext) Referer() string {
+	return c.ctx.Request().Referer()
+}
+
+func (c *echoContext) URL() *url.URL {
+	u := *c.ctx.Request().URL
+	u.Scheme, u.Host = c.Scheme(), c.Host()
+	return &u
+}
+
 func (c *echoContext) FormValue(key string) string {
+	_ = httpx.CacheFormBody(c.ctx.Request())
 	return c.ctx.FormValue(key)
 }
 
 func (c *echoContext) MultipartForm() (*multipart.Form, error) {
+	if err := c.parseMultipartForm(); err != nil {
+		return nil, err
+	}
 	return c.ctx.MultipartForm()
 }
 
 func (c *echoContext) FormFile(name string) (*multipart.FileHeader, error) {
+	if err := c.parseMultipartForm(); err != nil {
+		return nil, err
+	}
 	return c.ctx.FormFile(name)
 }
 
-func (c *echoContext) BodyRaw() ([]byte, error) {
-	req := c.ctx.Request()
-	if req.Body == nil {
-		return nil, nil
+// parseMultipartForm parses a multipart form with the limit set by
+// WithMultipartMemoryLimit, before echo parses it with its fixed one.
+func (c *echoContext) parseMultipartForm() error {
+	maxMemory, ok := c.ctx.Get(multipartMemoryKey).(int64)
+	if !ok {
+		return nil
 	}
-	body, err := io.ReadAll(req.Body)
-	if err != nil {
-		return nil, err
+	if err := c.ctx.Request().ParseMultipartForm(maxMemory); err != nil && !errors.Is(err, http.ErrNotMultipart) {
+		return err
 	}
-	_ = req.Body.Close()
-	req.Body = io.NopCloser(bytes.NewReader(body))
-	return body, nil
+	return nil
+}
+
+func (c *echoContext) BodyRaw() ([]byte, error) {
+	return httpx.ReusableBody(c.ctx.Request())
 }
 
 func (c *echoContext) BodyReader() io.ReadCloser {
-	if body := c.ctx.Request().Body; body != nil {
-		return body
-	}
-	return http.NoBody
+	return httpx.ReusableBodyReader(c.ctx.Request())
+}
+
+func (c *echoContext) SetRequestBody(body []byte) {
+	httpx.SetRequestBody(c.ctx.Request(), body)
 }
 
 // Request helpers not defined on httpx.Request but kept for compatibility.
@@ -163,23 +238,45 @@ func (c *echoContext) BodyReader() io.ReadCloser {
 // Binder (httpx.Binder)
 
 func (c *echoContext) BindJSON(dst any) error {
-	return c.binder.BindBody(c.ctx, dst)
+	if _, err := httpx.ReusableBody(c.ctx.Request()); err != nil {
+		return err
+	}
+	// BindBody picks the decoder from Content-Type and accepts empty bodies;
+	// decode JSON unconditionally like the other adapters.
+	if err := c.ctx.Echo().JSONSerializer.Deserialize(c.ctx, dst); err != nil {
+		return err
+	}
+	// echo does not validate bound structs; check binding tags like gin.
+	return httpx.ValidateRequired(dst)
 }
 
 func (c *echoContext) BindQuery(dst any) error {
-	return c.binder.BindQueryParams(c.ctx, dst)
+	return httpx.BindValues(dst, "query", c.ctx.QueryParams())
 }
 
 func (c *echoContext) BindForm(dst any) error {
-	return c.binder.BindBody(c.ctx, dst)
+	if err := httpx.CacheFormBody(c.ctx.Request()); err != nil {
+		return err
+	}
+	if err := c.parseMultipartForm(); err != nil {
+		return err
+	}
+	// FormParams parses the body; PostForm excludes the query string.
+	if _, err := c.ctx.FormParams(); err != nil {
+		return err
+	}
+	return httpx.BindValues(dst, "form", c.ctx.Request().PostForm)
 }
 
 func (c *echoContext) BindURI(dst any) error {
+	if httpx.UsesBindTypes(dst) {
+		return httpx.BindParams(dst, c.Params())
+	}
 	return bindURIWithForm(dst, c.ctx)
 }
 
 func (c *echoContext) BindHeader(dst any) error {
-	return c.binder.BindHeaders(c.ctx, dst)
+	return httpx.BindHeaders(dst, c.ctx.Request().Header)
 }
 
 // Responder (httpx.Responder)
@@ -189,6 +286,13 @@ func (c *echoContext) Status(code int) {
 }
 
 func (c *echoContext) JSON(code int, v any) error {
+	if opts, ok := httpx.JSONOptionsKey.Get(c); ok {
+		body, err := opts.Marshal(v)
+		if err != nil {
+			return err
+		}
+		return c.Bytes(code, body, "application/json; charset=utf-8")
+	}
 	return c.ctx.JSON(code, v)
 }
 
@@ -257,14 +361,51 @@ func (c *echoContext) Get(key string) (any, bool) {
 // Context (context.Context accessor + Next)
 
 func (c *echoContext) Context() context.Context {
-	return c.ctx.Request().Context()
+	if c.state == nil {
+		c.state = httpx.NewRequestState(c)
+	}
+	return httpx.WithState(c.ctx.Request().Context(), c.state)
 }
 
 func (c *echoContext) SetContext(ctx context.Context) {
 	c.ctx.SetRequest(c.ctx.Request().WithContext(ctx))
 }
 
+// Aborter (httpx.Aborter)
+
+func (c *echoContext) Abort() {
+	c.aborted = true
+	c.Set(httpx.AbortedKey, true)
+}
+
+func (c *echoContext) AbortWithStatus(code int) error {
+	c.Abort()
+	return c.NoContent(code)
+}
+
+func (c *echoContext) AbortWithStatusJSON(code int, obj any) error {
+	c.Abort()
+	return c.JSON(code, obj)
+}
+
+func (c *echoContext) IsAborted() bool {
+	_, ok := c.Get(httpx.AbortedKey)
+	return ok
+}
+
+// SetNext sets the rest of a chain composed by httpx.Compose.
+func (c *echoContext) SetNext(next httpx.Handler) {
+	c.chainNext = next
+}
+
 func (c *echoContext) Next() error {
+	if c.aborted {
+		return nil
+	}
+	if next := c.chainNext; next != nil {
+		c.chainNext = nil
+		return next(c)
+	}
 	if c.next == nil {
 		return nil
 	}
@@ -277,6 +418,72 @@ func (c *echoContext) StatusCode() int {
 	return c.ctx.Response().Status
 }
 
+func (c *echoContext) BodySize() int {
+	return int(c.ctx.Response().Size)
+}
+
 func (c *echoContext) NativeContext() any {
 	return c.ctx
 }
+
+// Unwrap returns the echo.Context behind ctx, reporting false for contexts of
+// other adapters. Echo pools its contexts, so the returned value is only
+// valid until the handler chain returns.
+func Unwrap(ctx httpx.Context) (echo.Context, bool) {
+	if c, ok := ctx.(*echoContext); ok {
+		return c.ctx, true
+	}
+	return httpx.AsNativeContext[echo.Context](ctx)
+}
+
+// Flusher and Hijacker (httpx.Flusher, httpx.Hijacker)
+
+func (c *echoContext) Flush() error {
+	resp := c.ctx.Response()
+	if !resp.Committed {
+		resp.WriteHeader(resp.Status)
+	}
+	// echo.Response.Flush panics when the writer cannot flush.
+	return http.NewResponseController(resp.Writer).Flush()
+}
+
+func (c *echoContext) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	resp := c.ctx.Response()
+	conn, rw, err := resp.Hijack()
+	if err == nil {
+		resp.Committed = true
+	}
+	return conn, rw, err
+}
+
+// InformationalWriter (httpx.InformationalWriter)
+
+func (c *echoContext) WriteInformational(code int, header http.Header) error {
+	resp := c.ctx.Response()
+	if resp.Committed {
+		return httpx.ErrResponseCommitted
+	}
+	return httpx.WriteHTTPInformational(resp.Writer, code, header)
+}
+
+// HTTPInterop (httpx.HTTPInterop)
+
+func (c *echoContext) HTTPRequest() *http.Request {
+	return c.ctx.Request()
+}
+
+func (c *echoContext) SetHTTPRequest(r *http.Request) {
+	c.ctx.SetRequest(r)
+}
+
+func (c *echoContext) HTTPResponseWriter() http.ResponseWriter {
+	return c.ctx.Response()
+}
+
+func (c *echoContext) SetHTTPResponseWriter(w http.ResponseWriter) {
+	if resp, ok := w.(*echo.Response); ok {
+		c.ctx.SetResponse(resp)
+		return
+	}
+	c.ctx.SetResponse(echo.NewResponse(w, c.ctx.Echo()))
+}
@@ -2,6 +2,7 @@ package echox
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/go-sphere/httpx"
 	"github.com/labstack/echo/v4"
@@ -10,7 +11,8 @@ import (
 func adaptMiddleware(middleware httpx.Middleware) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(ec echo.Context) error {
-			ctx := newEchoContext(ec)
+			ctx := acquireEchoContext(ec)
+			defer releaseEchoContext(ctx)
 			ctx.next = next
 			return middleware(ctx)
 		}
@@ -28,6 +30,32 @@ func adaptMiddlewares(middlewares []httpx.Middleware) []echo.MiddlewareFunc {
 	return out
 }
 
+func cloneMiddlewares(middlewares []httpx.Middleware, extra ...httpx.Middleware) []httpx.Middleware {
+	out := make([]httpx.Middleware, len(middlewares)+len(extra))
+	copy(out, middlewares)
+	copy(out[len(middlewares):], extra)
+	return out
+}
+
+// AdaptErrorHandler adapts a framework-independent error handler, such as
+// httpx.ProblemErrorHandler, for echo.Echo.HTTPErrorHandler. Errors raised by
+// echo itself, such as unmatched routes, keep the status of their
+// *echo.HTTPError. Errors after the response is committed are ignored.
+func AdaptErrorHandler(errHandler httpx.ErrorHandler) echo.HTTPErrorHandler {
+	return func(err error, c echo.Context) {
+		if c.Response().Committed {
+			return
+		}
+		var he *echo.HTTPError
+		if errors.As(err, &he) {
+			err = httpx.WithStatus(int32(he.Code), err, fmt.Sprint(he.Message))
+		}
+		ctx := acquireEchoContext(c)
+		defer releaseEchoContext(ctx)
+		errHandler(ctx, err)
+	}
+}
+
 func AdaptEchoMiddleware(middleware echo.MiddlewareFunc) httpx.Middleware {
 	if middleware == nil {
 		return func(ctx httpx.Context) error {
@@ -48,3 +76,16 @@ func AdaptEchoMiddleware(middleware echo.MiddlewareFunc) httpx.Middleware {
 		return nextHandler(ec.ctx)
 	}
 }
+
+// multipartMemoryKey keeps the limit of WithMultipartMemoryLimit in the
+// request state.
+const multipartMemoryKey = "echox.multipart_memory"
+
+// multipartMemoryLimit keeps bytes as the multipart memory limit of each
+// request, as echo does not let engines configure it.
+func multipartMemoryLimit(bytes int64) httpx.Middleware {
+	return func(ctx httpx.Context) error {
+		ctx.Set(multipartMemoryKey, bytes)
+		return ctx.Next()
+	}
+}
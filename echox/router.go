@@ -2,6 +2,7 @@ package echox
 
 import (
 	"io/fs"
+	"net/http"
 	"path"
 	"strings"
 
@@ -11,13 +12,18 @@ import (
 
 var _ httpx.Router = (*Router)(nil)
 
+// Router composes its middleware with each handler when the route is
+// registered, see httpx.Compose, so echo runs a single handler per route
+// besides the engine's middleware.
 type Router struct {
-	group    *echo.Group
-	basePath string
+	group       *echo.Group
+	basePath    string
+	middlewares []httpx.Middleware
+	hooks       *httpx.Hooks
 }
 
 func (r *Router) Use(m ...httpx.Middleware) {
-	r.group.Use(adaptMiddlewares(m)...)
+	r.middlewares = append(r.middlewares, m...)
 }
 
 func (r *Router) BasePath() string {
@@ -35,65 +41,107 @@ func (r *Router) SupportsRouterFeature(feature httpx.RouterFeature) bool {
 
 func (r *Router) Group(prefix string, m ...httpx.Middleware) httpx.Router {
 	return &Router{
-		group:    r.group.Group(prefix, adaptMiddlewares(m)...),
-		basePath: joinPaths(r.basePath, prefix),
+		group:       r.group.Group(prefix),
+		basePath:    joinPaths(r.basePath, prefix),
+		middlewares: cloneMiddlewares(r.middlewares, m...),
+		hooks:       r.hooks,
 	}
 }
 
-func (r *Router) Handle(method, path string, h httpx.Handler) {
-	r.group.Add(strings.ToUpper(method), path, r.toEchoHandler(h))
+func (r *Router) Handle(method, path string, h httpx.Handler) *httpx.Route {
+	method = strings.ToUpper(method)
+	r.checkRoute(method, path)
+	route := httpx.NewRouteFor(r.routeInfo(method, path))
+	r.group.Add(method, path, r.toEchoHandler(route, h))
+	r.notifyRoute(method, path)
+	return route
 }
 
-func (r *Router) Any(path string, h httpx.Handler) {
-	r.group.Any(path, r.toEchoHandler(h))
+func (r *Router) Any(path string, h httpx.Handler) *httpx.Route {
+	r.checkRoute(httpx.MethodAny, path)
+	route := httpx.NewRouteFor(r.routeInfo(httpx.MethodAny, path))
+	r.group.Any(path, r.toEchoHandler(route, h))
+	r.notifyRoute(httpx.MethodAny, path)
+	return route
 }
 
 func (r *Router) Static(prefix, root string) {
-	r.group.Static(prefix, root)
+	r.checkRoute(http.MethodGet, prefix)
+	r.staticGroup().Static(prefix, root)
 }
 
 func (r *Router) StaticFS(prefix string, filesystem fs.FS) {
-	r.group.StaticFS(prefix, filesystem)
+	r.checkRoute(http.MethodGet, prefix)
+	r.staticGroup().StaticFS(prefix, filesystem)
 }
 
 // GET registers a new GET route for a path with matching handler.
-func (r *Router) GET(path string, h httpx.Handler) {
-	r.group.GET(path, r.toEchoHandler(h))
+func (r *Router) GET(path string, h httpx.Handler) *httpx.Route {
+	return r.Handle(http.MethodGet, path, h)
 }
 
 // POST registers a new POST route for a path with matching handler.
-func (r *Router) POST(path string, h httpx.Handler) {
-	r.group.POST(path, r.toEchoHandler(h))
+func (r *Router) POST(path string, h httpx.Handler) *httpx.Route {
+	return r.Handle(http.MethodPost, path, h)
 }
 
 // PUT registers a new PUT route for a path with matching handler.
-func (r *Router) PUT(path string, h httpx.Handler) {
-	r.group.PUT(path, r.toEchoHandler(h))
+func (r *Router) PUT(path string, h httpx.Handler) *httpx.Route {
+	return r.Handle(http.MethodPut, path, h)
 }
 
 // DELETE registers a new DELETE route for a path with matching handler.
-func (r *Router) DELETE(path string, h httpx.Handler) {
-	r.group.DELETE(path, r.toEchoHandler(h))
+func (r *Router) DELETE(path string, h httpx.Handler) *httpx.Route {
+	return r.Handle(http.MethodDelete, path, h)
 }
 
 // PATCH registers a new PATCH route for a path with matching handler.
-func (r *Router) PATCH(path string, h httpx.Handler) {
-	r.group.PATCH(path, r.toEchoHandler(h))
+func (r *Router) PATCH(path string, h httpx.Handler) *httpx.Route {
+	return r.Handle(http.MethodPatch, path, h)
 }
 
 // HEAD registers a new HEAD route for a path with matching handler.
-func (r *Router) HEAD(path string, h httpx.Handler) {
-	r.group.HEAD(path, r.toEchoHandler(h))
+func (r *Router) HEAD(path string, h httpx.Handler) *httpx.Route {
+	return r.Handle(http.MethodHead, path, h)
 }
 
 // OPTIONS registers a new OPTIONS route for a path with matching handler.
-func (r *Router) OPTIONS(path string, h httpx.Handler) {
-	r.group.OPTIONS(path, r.toEchoHandler(h))
+func (r *Router) OPTIONS(path string, h httpx.Handler) *httpx.Route {
+	return r.Handle(http.MethodOptions, path, h)
 }
 
-func (r *Router) toEchoHandler(h httpx.Handler) echo.HandlerFunc {
+func (r *Router) notifyRoute(method, path string) {
+	r.hooks.NotifyRoute(r.routeInfo(method, path))
+}
+
+// checkRoute panics when the engine is frozen, see httpx.Engine.Freeze.
+func (r *Router) checkRoute(method, path string) {
+	if err := r.hooks.CheckRoute(r.routeInfo(method, path)); err != nil {
+		panic(err)
+	}
+}
+
+func (r *Router) routeInfo(method, path string) httpx.RouteInfo {
+	return httpx.RouteInfo{
+		Method: method,
+		Path:   joinPaths(r.basePath, path),
+	}
+}
+
+// staticGroup returns a group running the router's middleware as echo
+// middleware, for file handlers that cannot be composed.
+func (r *Router) staticGroup() *echo.Group {
+	if len(r.middlewares) == 0 {
+		return r.group
+	}
+	return r.group.Group("", adaptMiddlewares(r.middlewares)...)
+}
+
+func (r *Router) toEchoHandler(route *httpx.Route, h httpx.Handler) echo.HandlerFunc {
+	h = route.Compose(h, r.middlewares...)
 	return func(ec echo.Context) error {
-		ctx := newEchoContext(ec)
+		ctx := acquireEchoContext(ec)
+		defer releaseEchoContext(ctx)
 		return h(ctx)
 	}
 }
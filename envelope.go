@@ -0,0 +1,103 @@
+package httpx
+
+import (
+	"errors"
+	"maps"
+	"net/http"
+)
+
+// Meta is the metadata of an enveloped response, such as the totals of a
+// page.
+type Meta map[string]any
+
+// Envelope is the standard body of OK, Created and EnvelopeErrorHandler:
+//
+//	{"data": {...}, "meta": {"total": 42}}
+//	{"data": null, "error": {"code": 1001, "message": "..."}}
+type Envelope struct {
+	Data  any            `json:"data"`
+	Meta  Meta           `json:"meta,omitempty"`
+	Error *EnvelopeError `json:"error,omitempty"`
+}
+
+// EnvelopeError is the error member of an Envelope.
+type EnvelopeError struct {
+	Code    int32        `json:"code,omitempty"`
+	Message string       `json:"message"`
+	Errors  []FieldError `json:"errors,omitempty"`
+}
+
+// EnvelopeFunc returns the body written for env, letting an engine use an
+// envelope of its own shape, see WithEnvelope.
+type EnvelopeFunc func(ctx Context, env Envelope) any
+
+var envelopeKey = NewKey[EnvelopeFunc]("envelope")
+
+// WithEnvelope makes OK, Created and EnvelopeErrorHandler write the body
+// returned by format for the requests it runs for. The adapters install it
+// with their WithEnvelope options; without it the Envelope is written as
+// is.
+func WithEnvelope(format EnvelopeFunc) Middleware {
+	return func(ctx Context) error {
+		SetTyped(ctx, envelopeKey, format)
+		return ctx.Next()
+	}
+}
+
+// writeEnvelope writes env as JSON with status code, formatted by the
+// EnvelopeFunc of the engine.
+func writeEnvelope(ctx Context, code int, env Envelope) error {
+	if format, ok := envelopeKey.Get(ctx); ok && format != nil {
+		return ctx.JSON(code, format(ctx, env))
+	}
+	return ctx.JSON(code, env)
+}
+
+// OK writes data in an Envelope with status 200, along with the entries of
+// meta merged in order:
+//
+//	return httpx.OK(ctx, items, httpx.Meta{"total": total})
+func OK(ctx Context, data any, meta ...Meta) error {
+	var merged Meta
+	for _, m := range meta {
+		if len(m) == 0 {
+			continue
+		}
+		if merged == nil {
+			merged = make(Meta, len(m))
+		}
+		maps.Copy(merged, m)
+	}
+	return writeEnvelope(ctx, http.StatusOK, Envelope{Data: data, Meta: merged})
+}
+
+// Created writes data in an Envelope with status 201, and sets the Location
+// header to location unless it is empty.
+func Created(ctx Context, location string, data any) error {
+	if location != "" {
+		ctx.SetHeader("Location", location)
+	}
+	return writeEnvelope(ctx, http.StatusCreated, Envelope{Data: data})
+}
+
+// EnvelopeErrorHandler is an ErrorHandler rendering every error as the
+// error member of an Envelope, with the status, code and message of
+// ParseError after translating the error with LocalizeError. The fields of
+// a *ValidationError are listed in its errors. Adapters accept it through
+// their AdaptErrorHandler helpers.
+func EnvelopeErrorHandler(ctx Context, err error) {
+	err = LocalizeError(ctx, err)
+	code, status, message := ParseError(err)
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+	if message == "" {
+		message = http.StatusText(int(status))
+	}
+	envErr := &EnvelopeError{Code: code, Message: message}
+	var ve *ValidationError
+	if errors.As(err, &ve) {
+		envErr.Errors = ve.Errors
+	}
+	_ = writeEnvelope(ctx, int(status), Envelope{Error: envErr})
+}
@@ -0,0 +1,28 @@
+package httpx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOK(t *testing.T) {
+	rec := httptest.NewRecorder()
+	ctx := newHTTPContext(rec, httptest.NewRequest(http.MethodGet, "/items", nil), nil)
+	if err := OK(ctx, []int{1}, Meta{"total": 1, "page": 1}, nil, Meta{"page": 2}); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(rec.Body.String()); rec.Code != http.StatusOK || got != `{"data":[1],"meta":{"page":2,"total":1}}` {
+		t.Fatalf("OK() = %d %s", rec.Code, got)
+	}
+
+	rec = httptest.NewRecorder()
+	ctx = newHTTPContext(rec, httptest.NewRequest(http.MethodGet, "/items", nil), nil)
+	if err := OK(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(rec.Body.String()); got != `{"data":null}` {
+		t.Fatalf("OK(nil) = %s", got)
+	}
+}
@@ -0,0 +1,138 @@
+package httpx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ErrorTranslator translates error messages into one locale for the error
+// handlers, see RegisterErrorTranslator.
+type ErrorTranslator interface {
+	// TranslateError returns the message of err in the locale, or false to
+	// keep its message.
+	TranslateError(err error) (string, bool)
+	// TranslateField returns the message of a field failing validation in
+	// the locale, or false to keep its message.
+	TranslateField(field FieldError) (string, bool)
+}
+
+// ErrorMessages is an ErrorTranslator of message formats.
+type ErrorMessages struct {
+	// Errors maps the message of an error, see ParseError, or else its
+	// text, to its translation.
+	Errors map[string]string
+	// Rules maps a validation rule, such as "required", to the message
+	// format of the fields failing it, formatted with the field path as
+	// fmt.Sprintf does: "%s ist erforderlich".
+	Rules map[string]string
+}
+
+// TranslateError returns the translation of the message of err.
+func (m ErrorMessages) TranslateError(err error) (string, bool) {
+	_, _, message := ParseError(err)
+	if message == "" {
+		message = err.Error()
+	}
+	msg, ok := m.Errors[message]
+	return msg, ok
+}
+
+// TranslateField returns the message format of the rule of field, formatted
+// with its path.
+func (m ErrorMessages) TranslateField(field FieldError) (string, bool) {
+	format, ok := m.Rules[field.Rule]
+	if !ok {
+		return "", false
+	}
+	return fmt.Sprintf(format, field.Field), true
+}
+
+var errorTranslators sync.Map // lowercase locale -> ErrorTranslator
+
+// RegisterErrorTranslator registers the translator of error messages into
+// locale, such as "de" or "pt-BR". The error handlers translate the errors
+// of requests whose locale, set by middleware.Locale, is locale or one of
+// its regional variants, see LocalizeError.
+func RegisterErrorTranslator(locale string, t ErrorTranslator) {
+	errorTranslators.Store(strings.ToLower(locale), t)
+}
+
+// errorTranslator returns the translator registered for locale, or else for
+// its language.
+func errorTranslator(locale string) (ErrorTranslator, bool) {
+	locale = strings.ToLower(locale)
+	for locale != "" {
+		if t, ok := errorTranslators.Load(locale); ok {
+			return t.(ErrorTranslator), true
+		}
+		i := strings.LastIndexByte(locale, '-')
+		if i < 0 {
+			break
+		}
+		locale = locale[:i]
+	}
+	return nil, false
+}
+
+// LocalizeError translates err into the locale of the request, see Locale,
+// with the translator registered for it. A *ValidationError is returned as
+// a copy with translated field messages; other errors keep their status and
+// code, and take the translated message as both their message and error
+// text. err is returned unchanged when the request has no locale, the
+// locale no translator, or the translator no translation. The default error
+// handlers and ProblemErrorHandler localize the errors they render.
+func LocalizeError(ctx StateStore, err error) error {
+	if err == nil {
+		return nil
+	}
+	t, ok := errorTranslator(Locale(ctx))
+	if !ok {
+		return err
+	}
+	var ve *ValidationError
+	if errors.As(err, &ve) {
+		localized := &ValidationError{Errors: make([]FieldError, len(ve.Errors)), err: ve.err}
+		for i, field := range ve.Errors {
+			if msg, ok := t.TranslateField(field); ok {
+				field.Message = msg
+			}
+			localized.Errors[i] = field
+		}
+		return localized
+	}
+	msg, ok := t.TranslateError(err)
+	if !ok {
+		return err
+	}
+	return &localizedError{error: err, message: msg}
+}
+
+// localizedError is an error with a translated message.
+type localizedError struct {
+	error
+	message string
+}
+
+func (e *localizedError) Error() string {
+	return e.message
+}
+
+func (e *localizedError) Unwrap() error {
+	return e.error
+}
+
+func (e *localizedError) GetStatus() int32 {
+	_, status, _ := ParseError(e.error)
+	return status
+}
+
+func (e *localizedError) GetCode() int32 {
+	code, _, _ := ParseError(e.error)
+	return code
+}
+
+func (e *localizedError) GetMessage() string {
+	return e.message
+}
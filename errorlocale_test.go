@@ -0,0 +1,56 @@
+package httpx
+
+import (
+	"errors"
+	"testing"
+)
+
+type testState map[string]any
+
+func (s testState) Set(key string, val any) { s[key] = val }
+
+func (s testState) Get(key string) (any, bool) {
+	val, ok := s[key]
+	return val, ok
+}
+
+func TestLocalizeError(t *testing.T) {
+	RegisterErrorTranslator("x-test", ErrorMessages{
+		Errors: map[string]string{"not allowed": "nicht erlaubt"},
+		Rules:  map[string]string{"required": "%s ist erforderlich"},
+	})
+	state := testState{}
+	SetLocale(state, "X-Test-AT", nil)
+
+	err := LocalizeError(state, NewForbiddenError("not allowed"))
+	if _, status, message := ParseError(err); err.Error() != "nicht erlaubt" || status != 403 || message != "nicht erlaubt" {
+		t.Fatalf("LocalizeError() = %v (%d %q)", err, status, message)
+	}
+	var se StatusError
+	if !errors.As(err, &se) {
+		t.Fatalf("localized error lost its cause: %v", err)
+	}
+
+	ve := &ValidationError{Errors: []FieldError{
+		{Field: "Name", Rule: "required", Message: "Name failed the required rule"},
+		{Field: "Age", Rule: "min", Message: "Age failed the min=18 rule"},
+	}}
+	var localized *ValidationError
+	if !errors.As(LocalizeError(state, ve), &localized) {
+		t.Fatal("LocalizeError() did not keep the *ValidationError")
+	}
+	if localized.Errors[0].Message != "Name ist erforderlich" || localized.Errors[1].Message != "Age failed the min=18 rule" {
+		t.Fatalf("fields = %+v", localized.Errors)
+	}
+	if ve.Errors[0].Message != "Name failed the required rule" {
+		t.Fatalf("LocalizeError() modified its argument: %+v", ve.Errors)
+	}
+
+	untranslated := errors.New("boom")
+	if got := LocalizeError(state, untranslated); got != untranslated {
+		t.Fatalf("LocalizeError(untranslated) = %v", got)
+	}
+	if got := LocalizeError(testState{}, ve); got != error(ve) {
+		t.Fatalf("LocalizeError() without locale = %v", got)
+	}
+}
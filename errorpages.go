@@ -0,0 +1,103 @@
+package httpx
+
+import (
+	"io/fs"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// HTMLErrorPages returns middleware rendering error responses as the static
+// HTML pages of pages for browsers, while other clients, such as API
+// clients, still get the response of the error handler. Adapters install it
+// ahead of the other engine middleware for their WithHTMLErrorPages option.
+//
+// A page is served when the request's Accept header prefers text/html over
+// application/json, and pages has a page for the status: "404.html" for
+// status 404, or "4xx.html" for any status of its class. Errors returned by
+// the chain use their status, see ParseError, and requests matching no route
+// use 404. The error is not passed on once its page is served.
+func HTMLErrorPages(pages fs.FS) Middleware {
+	return func(ctx Context) error {
+		err := ctx.Next()
+		status := 0
+		switch {
+		case ctx.FullPath() == "" && (err != nil || !responseWritten(ctx)):
+			status = http.StatusNotFound
+		case err != nil && !responseWritten(ctx):
+			_, s, _ := ParseError(err)
+			status = int(s)
+		}
+		if status != 0 && ServeErrorPage(ctx, pages, status) {
+			return nil
+		}
+		return err
+	}
+}
+
+// ServeErrorPage responds with the page of pages for status, see
+// HTMLErrorPages, and reports whether it did. It serves nothing for statuses
+// below 400 and requests preferring another type. Adapters whose routes
+// handle errors ahead of the engine middleware call it from their error
+// handler.
+func ServeErrorPage(ctx Context, pages fs.FS, status int) bool {
+	if status < 400 || !prefersHTML(ctx.Header("Accept")) {
+		return false
+	}
+	page, ok := errorPage(pages, status)
+	if !ok {
+		return false
+	}
+	return ctx.Bytes(status, page, "text/html; charset=utf-8") == nil
+}
+
+// responseWritten reports whether a response was set, such as a CORS
+// preflight answered by middleware for an unmatched request, or an error
+// response written by a route. gin and hertz set status 404 ahead of the
+// middleware of unmatched requests.
+func responseWritten(ctx Context) bool {
+	ri, ok := AsResponseInfo(ctx)
+	if !ok {
+		return false
+	}
+	if rs, ok := AsResponseSizer(ctx); ok && rs.BodySize() > 0 {
+		return true
+	}
+	status := ri.StatusCode()
+	return status != http.StatusOK && status != http.StatusNotFound
+}
+
+func errorPage(pages fs.FS, status int) ([]byte, bool) {
+	code := strconv.Itoa(status)
+	for _, name := range []string{code + ".html", code[:1] + "xx.html"} {
+		if page, err := fs.ReadFile(pages, name); err == nil {
+			return page, true
+		}
+	}
+	return nil, false
+}
+
+// prefersHTML reports whether the Accept header accept ranks text/html
+// above zero and at least as high as application/json. Wildcards count for
+// JSON only, so clients sending "*/*", such as curl, get JSON.
+func prefersHTML(accept string) bool {
+	var html, json float64
+	for part := range strings.SplitSeq(accept, ",") {
+		mediaType, params, _ := strings.Cut(part, ";")
+		q := 1.0
+		for param := range strings.SplitSeq(params, ";") {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		switch strings.ToLower(strings.TrimSpace(mediaType)) {
+		case "text/html":
+			html = max(html, q)
+		case "application/json", "application/*", "*/*":
+			json = max(json, q)
+		}
+	}
+	return html > 0 && html >= json
+}
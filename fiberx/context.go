@@ -3,25 +3,57 @@ package fiberx
 import (
 	"bytes"
 	"context"
+	"errors"
 	"io"
+	"iter"
 	"mime/multipart"
 	"net/http"
+	"net/netip"
 	"net/textproto"
+	"net/url"
+	"strings"
+	"sync"
 
 	"github.com/go-sphere/httpx"
 	"github.com/gofiber/fiber/v3"
 )
 
-var _ httpx.Context = (*fiberContext)(nil)
+var (
+	_ httpx.Context           = (*fiberContext)(nil)
+	_ httpx.ChainContext      = (*fiberContext)(nil)
+	_ httpx.RequestBodySetter = (*fiberContext)(nil)
+	_ httpx.Aborter           = (*fiberContext)(nil)
+	_ httpx.ResponseSizer     = (*fiberContext)(nil)
+)
 
 type fiberContext struct {
-	ctx fiber.Ctx
-}
-
-func newFiberContext(ctx fiber.Ctx) *fiberContext {
-	return &fiberContext{
-		ctx: ctx,
+	ctx       fiber.Ctx
+	chainNext httpx.Handler
+	aborted   bool
+	state     *httpx.RequestState
+}
+
+// fiberContexts pools the contexts passed to handlers and middleware. A
+// context is released when the handler or middleware it was passed to
+// returns, so it must not be kept, nor used from other goroutines, after
+// that; httpx.Detach keeps request values for work that outlives it.
+var fiberContexts = sync.Pool{New: func() any { return new(fiberContext) }}
+
+func acquireFiberContext(ctx fiber.Ctx) *fiberContext {
+	c := fiberContexts.Get().(*fiberContext)
+	c.ctx = ctx
+	return c
+}
+
+// releaseFiberContext closes the request state of c, so that a context.Context
+// kept from it no longer resolves request state, clears c and returns it to
+// the pool.
+func releaseFiberContext(c *fiberContext) {
+	if c.state != nil {
+		c.state.Close()
 	}
+	*c = fiberContext{}
+	fiberContexts.Put(c)
 }
 
 // Request (httpx.Request)
@@ -39,7 +71,39 @@ func (c *fiberContext) FullPath() string {
 }
 
 func (c *fiberContext) ClientIP() string {
-	return c.ctx.IP()
+	proxies, ok := c.ctx.App().State().Get(trustedProxiesKey)
+	if !ok {
+		return c.ctx.IP()
+	}
+	peer, ok := netip.AddrFromSlice(c.ctx.RequestCtx().RemoteIP())
+	if !ok {
+		return c.ctx.IP()
+	}
+	return forwardedIP(peer.Unmap(), c.ctx.Get(fiber.HeaderXForwardedFor), proxies.([]netip.Prefix))
+}
+
+// forwardedIP walks X-Forwarded-For back from the peer to the first address
+// that is not a trusted proxy, unlike fiber, which takes the first address of
+// the header whatever the client put there. An unparseable hop ends the walk.
+func forwardedIP(ip netip.Addr, xff string, proxies []netip.Prefix) string {
+	for trusted(ip, proxies) {
+		i := strings.LastIndexByte(xff, ',')
+		hop, err := netip.ParseAddr(strings.TrimSpace(xff[i+1:]))
+		if err != nil {
+			break
+		}
+		ip, xff = hop.Unmap(), xff[:max(i, 0)]
+	}
+	return ip.String()
+}
+
+func trusted(ip netip.Addr, proxies []netip.Prefix) bool {
+	for _, p := range proxies {
+		if p.Contains(ip) {
+			return true
+		}
+	}
+	return false
 }
 
 func (c *fiberContext) Param(key string) string {
@@ -80,18 +144,28 @@ func (c *fiberContext) RawQuery() string {
 }
 
 func (c *fiberContext) Header(key string) string {
+	if strings.EqualFold(key, fiber.HeaderCookie) {
+		// fasthttp returns the first Cookie line until cookies are parsed.
+		values := c.HeaderValues(key)
+		if len(values) == 0 {
+			return ""
+		}
+		return values[0]
+	}
 	return c.ctx.Get(key)
 }
 
+func (c *fiberContext) HeaderValues(key string) []string {
+	return headerValues(key, c.ctx.Request().Header.PeekAll(key))
+}
+
 func (c *fiberContext) Headers() map[string][]string {
-	src := c.ctx.GetReqHeaders()
-	if len(src) == 0 {
-		return nil
-	}
-	out := make(map[string][]string, len(src))
-	for k, v := range src {
-		ck := textproto.CanonicalMIMEHeaderKey(k)
-		out[ck] = append([]string(nil), v...)
+	var out map[string][]string
+	for key, value := range c.AllHeaders() {
+		if out == nil {
+			out = make(map[string][]string)
+		}
+		out[key] = append(out[key], value)
 	}
 	return out
 }
@@ -115,26 +189,145 @@ func (c *fiberContext) Cookies() map[string]string {
 	return out
 }
 
+func (c *fiberContext) AllQueries() iter.Seq2[string, string] {
+	return func(yield func(string, string) bool) {
+		for key, value := range c.ctx.Request().URI().QueryArgs().All() {
+			if !yield(string(key), string(value)) {
+				return
+			}
+		}
+	}
+}
+
+// AllHeaders yields the headers like net/http-based contexts: without Host,
+// and with the Cookie lines joined into one value.
+func (c *fiberContext) AllHeaders() iter.Seq2[string, string] {
+	return func(yield func(string, string) bool) {
+		var cookies []string
+		for key, value := range c.ctx.Request().Header.All() {
+			switch k := textproto.CanonicalMIMEHeaderKey(string(key)); k {
+			case fiber.HeaderHost:
+			case fiber.HeaderCookie:
+				cookies = append(cookies, string(value))
+			default:
+				if !yield(k, string(value)) {
+					return
+				}
+			}
+		}
+		if len(cookies) > 0 {
+			yield(fiber.HeaderCookie, strings.Join(cookies, "; "))
+		}
+	}
+}
+
+func (c *fiberContext) AllCookies() iter.Seq2[string, string] {
+	return func(yield func(string, string) bool) {
+		for key, value := range c.ctx.Request().Header.Cookies() {
+			if !yield(string(key), string(value)) {
+				return
+			}
+		}
+	}
+}
+
+func (c *fiberContext) Scheme() string {
+	return httpx.ForwardedScheme(c.Header, c.ctx.RequestCtx().IsTLS())
+}
+
+func (c *fiberContext) Host() string {
+	return httpx.ForwardedHost(c.Header, string(c.ctx.Request().Host()))
+}
+
+func (c *fiberContext) Proto() string {
+	return string(c.ctx.Request().Header.Protocol())
+}
+
+// ContentLength returns -1 for chunked requests and requests without a
+// declared length, like net/http.
+func (c *fiberContext) ContentLength() int64 {
+	if n := c.ctx.Request().Header.ContentLength(); n >= 0 {
+		return int64(n)
+	}
+	return -1
+}
+
+func (c *fiberContext) UserAgent() string {
+	return string(c.ctx.Request().Header.UserAgent())
+}
+
+func (c *fiberContext) Referer() string {
+	return c.Header("Referer")
+}
+
+func (c *fiberContext) URL() *url.URL {
+	return httpx.RequestURL(c.Scheme(), c.Host(), string(c.ctx.Request().Header.RequestURI()))
+}
+
 func (c *fiberContext) FormValue(key string) string {
 	return c.ctx.FormValue(key)
 }
 
 func (c *fiberContext) MultipartForm() (*multipart.Form, error) {
-	return c.ctx.MultipartForm()
+	maxMemory, ok := c.ctx.Locals(multipartMemoryKey).(int64)
+	if !ok {
+		return c.ctx.MultipartForm()
+	}
+	if form, ok := c.ctx.Locals(multipartFormKey).(*multipart.Form); ok {
+		return form, nil
+	}
+	form, err := c.readMultipartForm(maxMemory)
+	if err != nil {
+		return nil, err
+	}
+	c.ctx.Locals(multipartFormKey, form)
+	return form, nil
+}
+
+// readMultipartForm parses the form with the limit set by
+// WithMultipartMemoryLimit, which fasthttp has no setting for.
+func (c *fiberContext) readMultipartForm(maxMemory int64) (*multipart.Form, error) {
+	req := c.ctx.Request()
+	boundary := string(req.Header.MultipartFormBoundary())
+	if boundary == "" {
+		return nil, http.ErrNotMultipart
+	}
+	var body io.Reader
+	if req.IsBodyStream() {
+		body = req.BodyStream()
+	} else {
+		body = bytes.NewReader(req.Body())
+	}
+	return multipart.NewReader(body, boundary).ReadForm(maxMemory)
 }
 
 func (c *fiberContext) FormFile(name string) (*multipart.FileHeader, error) {
-	return c.ctx.FormFile(name)
+	if _, ok := c.ctx.Locals(multipartMemoryKey).(int64); !ok {
+		return c.ctx.FormFile(name)
+	}
+	form, err := c.MultipartForm()
+	if err != nil {
+		return nil, err
+	}
+	if files := form.File[name]; len(files) > 0 {
+		return files[0], nil
+	}
+	return nil, http.ErrMissingFile
 }
 
+// BodyRaw copies the body, as fasthttp reuses the request buffer after the
+// handler returns.
 func (c *fiberContext) BodyRaw() ([]byte, error) {
-	return c.ctx.BodyRaw(), nil
+	return bytes.Clone(c.ctx.Request().Body()), nil
 }
 
+func (c *fiberContext) BodyRawUnsafe() ([]byte, error) {
+	return c.ctx.Request().Body(), nil
+}
+
+// BodyReader reads from the buffered body. A streamed request body is
+// drained into the buffer first, so the body can be read again.
 func (c *fiberContext) BodyReader() io.ReadCloser {
-	if stream := c.ctx.Request().BodyStream(); stream != nil {
-		return httpx.NewReadCloser(stream, c.ctx.Request().CloseBodyStream)
-	}
 	body := c.ctx.Body()
 	if len(body) == 0 {
 		return http.NoBody
@@ -142,26 +335,78 @@ func (c *fiberContext) BodyReader() io.ReadCloser {
 	return httpx.NewReadCloser(bytes.NewReader(body), nil)
 }
 
+func (c *fiberContext) SetRequestBody(body []byte) {
+	req := c.ctx.Request()
+	req.SetBody(body)
+	req.Header.Del(fiber.HeaderContentEncoding)
+	req.Header.SetContentLength(len(body))
+}
+
 // Binder (httpx.Binder)
 
 func (c *fiberContext) BindJSON(dst any) error {
-	return c.ctx.Bind().JSON(dst)
+	if err := c.ctx.Bind().JSON(dst); err != nil {
+		return httpx.ToValidationError(err)
+	}
+	if c.ctx.App().Config().StructValidator == nil {
+		// Without a struct validator, check binding tags like gin.
+		return httpx.ValidateRequired(dst)
+	}
+	return nil
 }
 
 func (c *fiberContext) BindQuery(dst any) error {
-	return c.ctx.Bind().Query(dst)
+	return c.validated(httpx.BindValues(dst, "query", c.Queries()), dst)
 }
 
 func (c *fiberContext) BindForm(dst any) error {
-	return c.ctx.Bind().Form(dst)
+	form, err := c.formValues()
+	if err != nil {
+		return err
+	}
+	return c.validated(httpx.BindValues(dst, "form", form), dst)
+}
+
+// validated returns the error of a bind by the httpx binder, or else runs
+// the app's struct validator like fiber's own binders.
+func (c *fiberContext) validated(err error, dst any) error {
+	if err != nil {
+		return err
+	}
+	if validator := c.ctx.App().Config().StructValidator; validator != nil {
+		return httpx.ToValidationError(validator.Validate(dst))
+	}
+	return nil
+}
+
+// formValues collects urlencoded and multipart form fields.
+func (c *fiberContext) formValues() (map[string][]string, error) {
+	values := make(map[string][]string)
+	if strings.HasPrefix(strings.ToLower(c.ctx.Get(fiber.HeaderContentType)), fiber.MIMEMultipartForm) {
+		form, err := c.MultipartForm()
+		if err != nil {
+			return nil, err
+		}
+		for key, vals := range form.Value {
+			values[key] = append(values[key], vals...)
+		}
+		return values, nil
+	}
+	for key, value := range c.ctx.Request().PostArgs().All() {
+		values[string(key)] = append(values[string(key)], string(value))
+	}
+	return values, nil
 }
 
 func (c *fiberContext) BindURI(dst any) error {
-	return c.ctx.Bind().URI(dst)
+	if httpx.UsesBindTypes(dst) {
+		return c.validated(httpx.BindParams(dst, c.Params()), dst)
+	}
+	return httpx.ToValidationError(c.ctx.Bind().URI(dst))
 }
 
 func (c *fiberContext) BindHeader(dst any) error {
-	return c.ctx.Bind().Header(dst)
+	return c.validated(httpx.BindHeaders(dst, c.Headers()), dst)
 }
 
 // Responder (httpx.Responder)
@@ -170,7 +415,18 @@ func (c *fiberContext) Status(code int) {
 	c.ctx.Status(code)
 }
 
+// jsonOptionsLocal is the key of httpx.JSONOptionsKey, converted to an
+// interface once rather than on every JSON call.
+var jsonOptionsLocal any = httpx.JSONOptionsKey.String()
+
 func (c *fiberContext) JSON(code int, v any) error {
+	if opts, ok := c.ctx.Locals(jsonOptionsLocal).(httpx.JSONOptions); ok {
+		body, err := opts.Marshal(v)
+		if err != nil {
+			return err
+		}
+		return c.Bytes(code, body, "application/json; charset=utf-8")
+	}
 	return c.ctx.Status(code).JSON(v)
 }
 
@@ -239,21 +495,113 @@ func (c *fiberContext) Get(key string) (any, bool) {
 // Context (context.Context accessor + Next)
 
 func (c *fiberContext) Context() context.Context {
-	return c.ctx.Context()
+	if c.state == nil {
+		c.state = httpx.NewRequestState(c)
+	}
+	return httpx.WithState(c.ctx.Context(), c.state)
 }
 
 func (c *fiberContext) SetContext(ctx context.Context) {
 	c.ctx.SetContext(ctx)
 }
 
+// Aborter (httpx.Aborter)
+
+func (c *fiberContext) Abort() {
+	c.aborted = true
+	c.Set(httpx.AbortedKey, true)
+}
+
+func (c *fiberContext) AbortWithStatus(code int) error {
+	c.Abort()
+	return c.NoContent(code)
+}
+
+func (c *fiberContext) AbortWithStatusJSON(code int, obj any) error {
+	c.Abort()
+	return c.JSON(code, obj)
+}
+
+func (c *fiberContext) IsAborted() bool {
+	_, ok := c.Get(httpx.AbortedKey)
+	return ok
+}
+
+// SetNext sets the rest of a chain composed by httpx.Compose.
+func (c *fiberContext) SetNext(next httpx.Handler) {
+	c.chainNext = next
+}
+
 func (c *fiberContext) Next() error {
-	return c.ctx.Next()
+	if c.aborted {
+		return nil
+	}
+	if next := c.chainNext; next != nil {
+		c.chainNext = nil
+		return next(c)
+	}
+	return withFiberStatus(c.ctx.Next())
+}
+
+// withFiberStatus gives the *fiber.Error of err, raised by fiber for
+// unmatched routes and the like, its status for httpx middleware, see
+// httpx.ParseError. fiber's error handling still finds the *fiber.Error.
+func withFiberStatus(err error) error {
+	var fe *fiber.Error
+	var se httpx.StatusError
+	if errors.As(err, &fe) && !errors.As(err, &se) {
+		return httpx.WithStatus(int32(fe.Code), err, fe.Message)
+	}
+	return err
 }
 
 func (c *fiberContext) StatusCode() int {
 	return c.ctx.Response().StatusCode()
 }
 
+func (c *fiberContext) BodySize() int {
+	resp := c.ctx.Response()
+	if resp.IsBodyStream() {
+		return max(resp.Header.ContentLength(), 0)
+	}
+	return len(resp.Body())
+}
+
 func (c *fiberContext) NativeContext() any {
 	return c.ctx
 }
+
+// Unwrap returns the fiber.Ctx behind ctx, reporting false for contexts of
+// other adapters. Fiber pools its contexts, so the returned value and any
+// strings or byte slices obtained from it are only valid until the handler
+// chain returns and must be copied before being retained.
+func Unwrap(ctx httpx.Context) (fiber.Ctx, bool) {
+	if c, ok := ctx.(*fiberContext); ok {
+		return c.ctx, true
+	}
+	return httpx.AsNativeContext[fiber.Ctx](ctx)
+}
+
+// headerValues converts the values fasthttp's PeekAll returns for key to
+// those of net/http-based contexts, see httpx.RequestHeaderValues.
+func headerValues(key string, raw [][]byte) []string {
+	if len(raw) == 0 {
+		return nil
+	}
+	values := make([]string, 0, len(raw))
+	for _, value := range raw {
+		values = append(values, string(value))
+	}
+	switch textproto.CanonicalMIMEHeaderKey(key) {
+	case fiber.HeaderContentLength:
+		// PeekAll reports an empty length for requests without one.
+		if values[0] == "" {
+			return nil
+		}
+	case fiber.HeaderCookie:
+		if len(values) > 1 {
+			return []string{strings.Join(values, "; ")}
+		}
+	}
+	return values
+}
@@ -2,18 +2,55 @@ package fiberx
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
+	"io/fs"
 	"net"
+	"net/http"
+	"net/netip"
+	"os"
+	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/go-sphere/httpx"
 	"github.com/gofiber/fiber/v3"
+	"github.com/valyala/fasthttp"
 )
 
-var _ httpx.Engine = (*Engine)(nil)
+var (
+	_ httpx.Engine               = (*Engine)(nil)
+	_ httpx.NativeEngineProvider = (*Engine)(nil)
+)
 
 type Config struct {
-	engine *fiber.App
-	listen func(*fiber.App) error
+	engine          *fiber.App
+	addr            string
+	listener        net.Listener
+	unixSocket      string
+	unixPerm        os.FileMode
+	listenConfig    fiber.ListenConfig
+	shutdownTimeout time.Duration
+	readTimeout     time.Duration
+	writeTimeout    time.Duration
+	idleTimeout     time.Duration
+	headerTimeout   time.Duration
+	connRequests    int
+	requestTimeout  time.Duration
+	maxHeaderBytes  int
+	concurrency     int
+	multipartMemory int64
+	trustedProxies  []netip.Prefix
+	certFile        string
+	keyFile         string
+	tlsConfig       *tls.Config
+	h2c             bool
+	runtimeRoutes   bool
+	basePath        string
+	panicPolicy     httpx.PanicPolicy
+	errorPages      fs.FS
+	jsonOptions     *httpx.JSONOptions
+	envelope        httpx.EnvelopeFunc
 }
 
 type Option func(*Config)
@@ -27,16 +64,51 @@ func NewConfig(opts ...Option) *Config {
 		conf.engine = fiber.New(
 			fiber.Config{
 				ErrorHandler: func(ctx fiber.Ctx, err error) error {
+					fc := acquireFiberContext(ctx)
+					err = httpx.LocalizeError(fc, err)
+					releaseFiberContext(fc)
+					var ve *httpx.ValidationError
+					if errors.As(err, &ve) {
+						return ctx.Status(fiber.StatusUnprocessableEntity).JSON(ve)
+					}
 					return ctx.Status(500).JSON(fiber.Map{"error": err.Error()})
 				},
 			},
 		)
 	}
-	if conf.listen == nil {
-		conf.listen = func(app *fiber.App) error {
-			return app.Listen(":8080")
+	if len(conf.trustedProxies) > 0 {
+		conf.engine.State().Set(trustedProxiesKey, conf.trustedProxies)
+	}
+	server := conf.engine.Server()
+	if conf.readTimeout > 0 {
+		server.ReadTimeout = conf.readTimeout
+	}
+	if conf.writeTimeout > 0 {
+		server.WriteTimeout = conf.writeTimeout
+	}
+	if conf.idleTimeout > 0 {
+		server.IdleTimeout = conf.idleTimeout
+	}
+	if conf.headerTimeout > 0 {
+		// fasthttp times the headers and the body together: the read timeout
+		// bounds the headers, and the body gets its own once they arrive.
+		server.ReadTimeout = conf.headerTimeout
+		if conf.readTimeout > 0 {
+			server.HeaderReceived = bodyReadTimeout(server.HeaderReceived, conf.readTimeout)
 		}
 	}
+	if conf.connRequests > 0 {
+		server.MaxRequestsPerConn = conf.connRequests
+	}
+	if conf.maxHeaderBytes > 0 {
+		server.ReadBufferSize = conf.maxHeaderBytes
+	}
+	if conf.concurrency > 0 {
+		server.Concurrency = conf.concurrency
+	}
+	if conf.addr == "" && conf.listener == nil {
+		conf.addr = ":8080"
+	}
 	return &conf
 }
 
@@ -48,35 +120,285 @@ func WithEngine(engine *fiber.App) Option {
 
 func WithListen(addr string, config ...fiber.ListenConfig) Option {
 	return func(conf *Config) {
-		conf.listen = func(app *fiber.App) error {
-			return app.Listen(addr, config...)
+		conf.addr = addr
+		conf.listener = nil
+		if len(config) > 0 {
+			conf.listenConfig = config[0]
 		}
 	}
 }
 
 func WithListener(ln net.Listener, config ...fiber.ListenConfig) Option {
 	return func(conf *Config) {
-		conf.listen = func(app *fiber.App) error {
-			return app.Listener(ln, config...)
+		conf.addr = ""
+		conf.listener = ln
+		if len(config) > 0 {
+			conf.listenConfig = config[0]
+		}
+	}
+}
+
+// WithUnixSocket serves on a unix domain socket at path instead of TCP. perm
+// is applied to the socket file when non-zero. Stale socket files are removed
+// before listening and the socket file is removed on shutdown.
+func WithUnixSocket(path string, perm os.FileMode) Option {
+	return func(conf *Config) {
+		conf.unixSocket = path
+		conf.unixPerm = perm
+	}
+}
+
+// WithShutdownTimeout bounds how long Stop waits for in-flight requests.
+// A zero or negative timeout relies solely on the context passed to Stop.
+func WithShutdownTimeout(timeout time.Duration) Option {
+	return func(conf *Config) {
+		conf.shutdownTimeout = timeout
+	}
+}
+
+// WithReadTimeout bounds reading a request, including its body. It sets the
+// ReadTimeout of fiber's fasthttp server.
+func WithReadTimeout(timeout time.Duration) Option {
+	return func(conf *Config) {
+		conf.readTimeout = timeout
+	}
+}
+
+// WithWriteTimeout bounds writing the response. It sets the WriteTimeout of
+// fiber's fasthttp server.
+func WithWriteTimeout(timeout time.Duration) Option {
+	return func(conf *Config) {
+		conf.writeTimeout = timeout
+	}
+}
+
+// WithIdleTimeout bounds how long a keep-alive connection waits for the next
+// request. It sets the IdleTimeout of fiber's fasthttp server.
+func WithIdleTimeout(timeout time.Duration) Option {
+	return func(conf *Config) {
+		conf.idleTimeout = timeout
+	}
+}
+
+// WithReadHeaderTimeout bounds reading the request headers, so clients
+// sending them slowly cannot hold connections open. fasthttp has no
+// separate header timeout, so it becomes the ReadTimeout of fiber's fasthttp
+// server, and the body is then read within the timeout of WithReadTimeout,
+// or else within what is left of this one.
+func WithReadHeaderTimeout(timeout time.Duration) Option {
+	return func(conf *Config) {
+		conf.headerTimeout = timeout
+	}
+}
+
+// WithMaxRequestsPerConn closes keep-alive connections once they have served
+// n requests. It sets the MaxRequestsPerConn of fiber's fasthttp server.
+func WithMaxRequestsPerConn(n int) Option {
+	return func(conf *Config) {
+		conf.connRequests = n
+	}
+}
+
+// bodyReadTimeout gives requests timeout to read their body once their
+// headers are received, unless headerReceived sets one.
+func bodyReadTimeout(headerReceived func(*fasthttp.RequestHeader) fasthttp.RequestConfig, timeout time.Duration) func(*fasthttp.RequestHeader) fasthttp.RequestConfig {
+	return func(header *fasthttp.RequestHeader) fasthttp.RequestConfig {
+		var conf fasthttp.RequestConfig
+		if headerReceived != nil {
+			conf = headerReceived(header)
+		}
+		if conf.ReadTimeout == 0 {
+			conf.ReadTimeout = timeout
 		}
+		return conf
+	}
+}
+
+// WithRequestTimeout gives the context of each request a deadline timeout
+// after it starts, see httpx.RequestTimeout.
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(conf *Config) {
+		conf.requestTimeout = timeout
+	}
+}
+
+// WithMaxHeaderBytes bounds the size of request headers. It sets the
+// ReadBufferSize of fiber's fasthttp server, which bounds the headers.
+func WithMaxHeaderBytes(n int) Option {
+	return func(conf *Config) {
+		conf.maxHeaderBytes = n
+	}
+}
+
+// WithConcurrencyLimit bounds the number of connections served at once.
+// It sets the Concurrency of fiber's fasthttp server, which answers
+// further connections with an error and closes them.
+func WithConcurrencyLimit(n int) Option {
+	return func(conf *Config) {
+		conf.concurrency = n
+	}
+}
+
+// WithMultipartMemoryLimit sets how many bytes of a multipart form
+// MultipartForm, FormFile and BindForm keep in memory; larger file parts are
+// written to temporary files. fasthttp
+// otherwise keeps the forms of buffered bodies in memory. New installs a
+// middleware keeping the limit for the request, and removing the temporary
+// files once it has been served.
+func WithMultipartMemoryLimit(bytes int64) Option {
+	return func(conf *Config) {
+		conf.multipartMemory = bytes
+	}
+}
+
+// WithTrustedProxies sets the proxies whose X-Forwarded-For headers
+// Context.ClientIP believes, taking the nearest address that is not one of
+// them. Without it ClientIP is fiber's Ctx.IP, which an app fiberx creates
+// leaves to the peer address.
+func WithTrustedProxies(proxies ...netip.Prefix) Option {
+	return func(conf *Config) {
+		conf.trustedProxies = proxies
+	}
+}
+
+// WithTLS sets the certificate and key files used by StartTLS.
+func WithTLS(certFile, keyFile string) Option {
+	return func(conf *Config) {
+		conf.certFile = certFile
+		conf.keyFile = keyFile
+	}
+}
+
+// WithTLSConfig sets the TLS config used by StartTLS. When combined with
+// WithTLS, the certificate pair is appended to the config's certificates.
+func WithTLSConfig(tlsConfig *tls.Config) Option {
+	return func(conf *Config) {
+		conf.tlsConfig = tlsConfig
+	}
+}
+
+// WithH2C requests cleartext HTTP/2. fasthttp only speaks HTTP/1.x, so Start
+// and StartTLS return httpx.ErrH2CUnsupported when it is enabled.
+func WithH2C(enabled bool) Option {
+	return func(conf *Config) {
+		conf.h2c = enabled
+	}
+}
+
+// WithAllowRuntimeRoutes keeps the routes open to registration after Start,
+// which otherwise freezes them, see httpx.Engine.Freeze. fiberx rebuilds
+// fiber's route tree for each route registered while running, which fiber
+// does not synchronize with the requests being served.
+func WithAllowRuntimeRoutes(allowed bool) Option {
+	return func(conf *Config) {
+		conf.runtimeRoutes = allowed
+	}
+}
+
+// WithBasePath prefixes the routes of every group created by the engine with
+// path, for services mounted under a path by a gateway. Router.BasePath,
+// FullPath and the routes reported to OnRouteRegistered include it.
+func WithBasePath(path string) Option {
+	return func(conf *Config) {
+		conf.basePath = path
+	}
+}
+
+// WithPanicPolicy sets what happens when a handler or middleware panics,
+// see httpx.PanicPolicy. The default, httpx.PanicPropagate, leaves panics to
+// the framework.
+func WithPanicPolicy(policy httpx.PanicPolicy) Option {
+	return func(conf *Config) {
+		conf.panicPolicy = policy
+	}
+}
+
+// WithHTMLErrorPages renders error responses as the static HTML pages of
+// pages for browsers, such as "404.html" and "500.html", see
+// httpx.HTMLErrorPages. Other clients get the response of the error handler.
+func WithHTMLErrorPages(pages fs.FS) Option {
+	return func(conf *Config) {
+		conf.errorPages = pages
+	}
+}
+
+// WithEnvelope makes httpx.OK, httpx.Created and httpx.EnvelopeErrorHandler
+// write the body returned by format instead of the httpx.Envelope, see
+// httpx.WithEnvelope.
+func WithEnvelope(format httpx.EnvelopeFunc) Option {
+	return func(conf *Config) {
+		conf.envelope = format
+	}
+}
+
+// WithJSONOptions makes the engine encode the bodies written with JSON
+// using opts instead of the framework's encoder, see httpx.JSONOptions.
+func WithJSONOptions(opts httpx.JSONOptions) Option {
+	return func(conf *Config) {
+		conf.jsonOptions = &opts
 	}
 }
 
 type Engine struct {
-	engine      *fiber.App
-	middlewares []httpx.Middleware
-	listen      func(*fiber.App) error
-	running     atomic.Bool
+	engine          *fiber.App
+	middlewares     []httpx.Middleware
+	addr            string
+	listener        net.Listener
+	unixSocket      string
+	unixPerm        os.FileMode
+	listenConfig    fiber.ListenConfig
+	shutdownTimeout time.Duration
+	certFile        string
+	keyFile         string
+	tlsConfig       *tls.Config
+	h2c             bool
+	hooks           *httpx.Hooks
+	running         atomic.Bool
+	mu              sync.Mutex
+	boundAddr       net.Addr
+	runtimeRoutes   bool
+	basePath        string
 }
 
 func New(opts ...Option) httpx.Engine {
 	conf := NewConfig(opts...)
 	engine := &Engine{
-		engine:      conf.engine,
-		middlewares: []httpx.Middleware{},
-		listen:      conf.listen,
+		engine:          conf.engine,
+		middlewares:     []httpx.Middleware{},
+		addr:            conf.addr,
+		listener:        conf.listener,
+		unixSocket:      conf.unixSocket,
+		unixPerm:        conf.unixPerm,
+		listenConfig:    conf.listenConfig,
+		shutdownTimeout: conf.shutdownTimeout,
+		certFile:        conf.certFile,
+		keyFile:         conf.keyFile,
+		tlsConfig:       conf.tlsConfig,
+		h2c:             conf.h2c,
+		runtimeRoutes:   conf.runtimeRoutes,
+		basePath:        conf.basePath,
+		hooks:           &httpx.Hooks{},
 	}
 	engine.running.Store(false)
+	engine.engine.Use(watchClient)
+	if conf.jsonOptions != nil {
+		engine.Use(httpx.WithJSONOptions(*conf.jsonOptions))
+	}
+	if conf.envelope != nil {
+		engine.Use(httpx.WithEnvelope(conf.envelope))
+	}
+	if conf.errorPages != nil {
+		engine.Use(httpx.HTMLErrorPages(conf.errorPages))
+	}
+	if conf.panicPolicy != httpx.PanicPropagate {
+		engine.Use(httpx.RecoverPanics(conf.panicPolicy))
+	}
+	if conf.requestTimeout > 0 {
+		engine.Use(httpx.RequestTimeout(conf.requestTimeout))
+	}
+	if conf.multipartMemory > 0 {
+		engine.Use(multipartMemoryLimit(conf.multipartMemory))
+	}
 	return engine
 }
 
@@ -89,24 +411,100 @@ func (e *Engine) Use(middlewares ...httpx.Middleware) {
 }
 
 func (e *Engine) Group(prefix string, m ...httpx.Middleware) httpx.Router {
+	prefix = joinPaths(e.basePath, prefix)
 	return &Router{
 		basePath:    joinPaths("/", prefix),
 		group:       e.engine.Group(prefix),
 		middlewares: cloneMiddlewares([]httpx.Middleware{}, m...), // Don't include global middlewares here since they're already registered
+		hooks:       e.hooks,
+		engine:      e,
 	}
 }
 
 func (e *Engine) Start() error {
+	return e.serve(nil)
+}
+
+// StartTLS serves HTTPS using the certificates configured by WithTLS and
+// WithTLSConfig, falling back to the listen config's TLSConfig.
+func (e *Engine) StartTLS() error {
+	base := e.tlsConfig
+	if base == nil {
+		base = e.listenConfig.TLSConfig
+	}
+	tlsConfig, err := httpx.NewTLSConfig(base, e.certFile, e.keyFile)
+	if err != nil {
+		return err
+	}
+	return e.serve(tlsConfig)
+}
+
+func (e *Engine) serve(tlsConfig *tls.Config) error {
+	if e.h2c {
+		return httpx.ErrH2CUnsupported
+	}
+	if err := e.hooks.RunStart(); err != nil {
+		return err
+	}
+	if !e.runtimeRoutes {
+		e.Freeze()
+	}
+	ln := e.listener
+	if e.unixSocket != "" {
+		var err error
+		if ln, err = httpx.ListenUnix(e.unixSocket, e.unixPerm); err != nil {
+			return err
+		}
+	}
 	e.running.Store(true)
 	defer e.running.Store(false)
-	return e.listen(e.engine)
+	defer e.setBoundAddr(nil)
+
+	config := e.listenConfig
+	if ln != nil {
+		e.setBoundAddr(ln.Addr())
+		if tlsConfig != nil {
+			ln = tls.NewListener(ln, tlsConfig)
+		}
+		return e.engine.Listener(ln, config)
+	}
+	if tlsConfig != nil {
+		config.TLSConfig = tlsConfig
+	}
+	addrFunc := config.ListenerAddrFunc
+	config.ListenerAddrFunc = func(addr net.Addr) {
+		e.setBoundAddr(addr)
+		if addrFunc != nil {
+			addrFunc(addr)
+		}
+	}
+	return e.engine.Listen(e.addr, config)
 }
 
 func (e *Engine) Stop(ctx context.Context) error {
+	e.hooks.RunStopping()
+	if e.shutdownTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.shutdownTimeout)
+		defer cancel()
+	}
 	err := e.engine.ShutdownWithContext(ctx)
 	if err == nil {
 		e.running.Store(false)
 	}
+	if e.unixSocket != "" {
+		err = errors.Join(err, httpx.RemoveUnixSocket(e.unixSocket))
+	}
+	return errors.Join(err, e.hooks.RunStop(ctx))
+}
+
+// StopGraceful stops accepting connections, waits for in-flight requests,
+// and then runs drain.
+func (e *Engine) StopGraceful(ctx context.Context, drain func()) error {
+	err := e.Stop(ctx)
+	if drain != nil {
+		drain()
+	}
 	return err
 }
 
@@ -114,3 +512,66 @@ func (e *Engine) Stop(ctx context.Context) error {
 func (e *Engine) IsRunning() bool {
 	return e.running.Load()
 }
+
+// BoundAddr returns the address the server is listening on while running.
+func (e *Engine) BoundAddr() net.Addr {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.boundAddr
+}
+
+// Test serves req in-process with fiber's App.Test. A deadline on the
+// context of req bounds the request; fiber's default of one second does not
+// apply.
+func (e *Engine) Test(req *http.Request) (*http.Response, error) {
+	conf := fiber.TestConfig{FailOnTimeout: true}
+	if deadline, ok := req.Context().Deadline(); ok {
+		conf.Timeout = time.Until(deadline)
+	}
+	return e.engine.Test(req, conf)
+}
+
+func (e *Engine) setBoundAddr(addr net.Addr) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.boundAddr = addr
+}
+
+// OnStart registers a hook that runs before the server starts listening.
+func (e *Engine) OnStart(fn func() error) {
+	e.hooks.OnStart(fn)
+}
+
+// OnStopping registers a hook that runs when Stop is called, before shutdown.
+func (e *Engine) OnStopping(fn func()) {
+	e.hooks.OnStopping(fn)
+}
+
+// OnStop registers a hook that runs after the server has shut down.
+func (e *Engine) OnStop(fn func(ctx context.Context) error) {
+	e.hooks.OnStop(fn)
+}
+
+// OnRouteRegistered registers a hook invoked for every handler route.
+func (e *Engine) OnRouteRegistered(fn func(httpx.RouteInfo)) {
+	e.hooks.OnRouteRegistered(fn)
+}
+
+func (e *Engine) Freeze() {
+	e.hooks.Freeze()
+}
+
+// Native returns the *fiber.App of the engine, see App.
+func (e *Engine) Native() any {
+	return e.engine
+}
+
+// App returns the *fiber.App of a fiberx engine, or nil for engines of other
+// adapters. Its Server method returns the fasthttp server, whose settings
+// can be changed before Start.
+func App(engine httpx.Engine) *fiber.App {
+	if e, ok := engine.(*Engine); ok {
+		return e.engine
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package fiberx
 
 import (
 	"errors"
+	"mime/multipart"
 
 	"github.com/go-sphere/httpx"
 	"github.com/gofiber/fiber/v3"
@@ -9,7 +10,8 @@ import (
 
 func adaptMiddleware(middleware httpx.Middleware) fiber.Handler {
 	return func(ctx fiber.Ctx) error {
-		fc := newFiberContext(ctx)
+		fc := acquireFiberContext(ctx)
+		defer releaseFiberContext(fc)
 		// Return error directly to fiber's error handling system
 		return middleware(fc)
 	}
@@ -22,12 +24,84 @@ func cloneMiddlewares(middlewares []httpx.Middleware, extra ...httpx.Middleware)
 	return out
 }
 
+// AdaptErrorHandler adapts a framework-independent error handler, such as
+// httpx.ProblemErrorHandler, for fiber.Config.ErrorHandler. Errors raised by
+// fiber itself, such as unmatched routes, keep the status of their
+// *fiber.Error.
+func AdaptErrorHandler(errHandler httpx.ErrorHandler) fiber.ErrorHandler {
+	return func(ctx fiber.Ctx, err error) error {
+		var fe *fiber.Error
+		if errors.As(err, &fe) {
+			err = httpx.WithStatus(int32(fe.Code), err, fe.Message)
+		}
+		fc := acquireFiberContext(ctx)
+		defer releaseFiberContext(fc)
+		errHandler(fc, err)
+		return nil
+	}
+}
+
+// AdaptFiberMiddleware adapts a fiber middleware. Passed to a Router, which
+// composes its middleware into a single fiber handler, the middleware gets a
+// fiber.Ctx whose Next runs the rest of the composed chain.
 func AdaptFiberMiddleware(middleware fiber.Handler) httpx.Middleware {
 	return func(ctx httpx.Context) error {
 		fc, ok := ctx.(*fiberContext)
 		if !ok {
 			return errors.New("AdaptGinMiddleware: fiber context type error")
 		}
-		return middleware(fc.ctx)
+		if fc.chainNext == nil {
+			return middleware(fc.ctx)
+		}
+		return middleware(chainCtx{Ctx: fc.ctx, fc: fc})
 	}
 }
+
+// chainCtx is the fiber.Ctx of a fiber middleware in a composed chain.
+type chainCtx struct {
+	fiber.Ctx
+	fc *fiberContext
+}
+
+func (c chainCtx) Next() error {
+	return c.fc.Next()
+}
+
+const (
+	// multipartMemoryKey keeps the limit of WithMultipartMemoryLimit in the
+	// request state.
+	multipartMemoryKey = "fiberx.multipart_memory"
+	// multipartFormKey keeps the form parsed with that limit.
+	multipartFormKey = "fiberx.multipart_form"
+	// trustedProxiesKey keeps the proxies of WithTrustedProxies in the app
+	// state.
+	trustedProxiesKey = "fiberx.trusted_proxies"
+)
+
+// multipartMemoryLimit keeps bytes as the multipart memory limit of each
+// request, and removes the temporary files of the form parsed with it once
+// the request has been served.
+func multipartMemoryLimit(bytes int64) httpx.Middleware {
+	return func(ctx httpx.Context) error {
+		fc, ok := Unwrap(ctx)
+		if !ok {
+			return ctx.Next()
+		}
+		fc.Locals(multipartMemoryKey, bytes)
+		defer func() {
+			if form, ok := fc.Locals(multipartFormKey).(*multipart.Form); ok {
+				_ = form.RemoveAll()
+			}
+		}()
+		return ctx.Next()
+	}
+}
+
+// watchClient cancels the request context when the client disconnects,
+// which fasthttp does not do, see httpx.WatchClient.
+func watchClient(c fiber.Ctx) error {
+	ctx, release := httpx.WatchClient(c.Context(), c.RequestCtx().Conn())
+	defer release()
+	c.SetContext(ctx)
+	return c.Next()
+}
@@ -12,10 +12,15 @@ import (
 
 var _ httpx.Router = (*Router)(nil)
 
+// Router composes its middleware with each handler when the route is
+// registered, see httpx.Compose, so fiber runs a single handler per route
+// besides the engine's middleware.
 type Router struct {
 	basePath    string
 	group       fiber.Router
 	middlewares []httpx.Middleware
+	hooks       *httpx.Hooks
+	engine      *Engine
 }
 
 func (r *Router) Use(m ...httpx.Middleware) {
@@ -43,63 +48,105 @@ func (r *Router) Group(prefix string, m ...httpx.Middleware) httpx.Router {
 		basePath:    joinPaths(r.basePath, prefix),
 		group:       r.group.Group(prefix),
 		middlewares: cloneMiddlewares(r.middlewares, m...),
+		hooks:       r.hooks,
+		engine:      r.engine,
 	}
 }
 
-func (r *Router) Handle(method, path string, h httpx.Handler) {
-	methods := []string{strings.ToUpper(method)}
-	handler, handlers := splitHandlers(r.adaptHandler(h))
-	r.group.Add(methods, path, handler, handlers...)
+func (r *Router) Handle(method, path string, h httpx.Handler) *httpx.Route {
+	method = strings.ToUpper(method)
+	r.checkRoute(method, path)
+	route := httpx.NewRouteFor(r.routeInfo(method, path))
+	r.group.Add([]string{method}, path, r.adaptHandler(route, h))
+	r.rebuildTree()
+	r.notifyRoute(method, path)
+	return route
 }
 
-func (r *Router) Any(path string, h httpx.Handler) {
-	handler, handlers := splitHandlers(r.adaptHandler(h))
-	r.group.All(path, handler, handlers...)
+func (r *Router) Any(path string, h httpx.Handler) *httpx.Route {
+	r.checkRoute(httpx.MethodAny, path)
+	route := httpx.NewRouteFor(r.routeInfo(httpx.MethodAny, path))
+	r.group.All(path, r.adaptHandler(route, h))
+	r.rebuildTree()
+	r.notifyRoute(httpx.MethodAny, path)
+	return route
 }
 
 func (r *Router) Static(prefix, root string) {
+	r.checkRoute(fiber.MethodGet, prefix)
 	r.group.Use(append([]any{prefix}, r.combineHandlers(static.New(root))...)...)
+	r.rebuildTree()
 }
 
 func (r *Router) StaticFS(prefix string, fs fs.FS) {
+	r.checkRoute(fiber.MethodGet, prefix)
 	r.group.Use(append([]any{prefix}, r.combineHandlers(static.New("", static.Config{FS: fs}))...)...)
+	r.rebuildTree()
 }
 
 // GET registers a new GET route for a path with matching handler.
-func (r *Router) GET(path string, h httpx.Handler) {
-	r.Handle("GET", path, h)
+func (r *Router) GET(path string, h httpx.Handler) *httpx.Route {
+	return r.Handle("GET", path, h)
 }
 
 // POST registers a new POST route for a path with matching handler.
-func (r *Router) POST(path string, h httpx.Handler) {
-	r.Handle("POST", path, h)
+func (r *Router) POST(path string, h httpx.Handler) *httpx.Route {
+	return r.Handle("POST", path, h)
 }
 
 // PUT registers a new PUT route for a path with matching handler.
-func (r *Router) PUT(path string, h httpx.Handler) {
-	r.Handle("PUT", path, h)
+func (r *Router) PUT(path string, h httpx.Handler) *httpx.Route {
+	return r.Handle("PUT", path, h)
 }
 
 // DELETE registers a new DELETE route for a path with matching handler.
-func (r *Router) DELETE(path string, h httpx.Handler) {
-	r.Handle("DELETE", path, h)
+func (r *Router) DELETE(path string, h httpx.Handler) *httpx.Route {
+	return r.Handle("DELETE", path, h)
 }
 
 // PATCH registers a new PATCH route for a path with matching handler.
-func (r *Router) PATCH(path string, h httpx.Handler) {
-	r.Handle("PATCH", path, h)
+func (r *Router) PATCH(path string, h httpx.Handler) *httpx.Route {
+	return r.Handle("PATCH", path, h)
 }
 
 // HEAD registers a new HEAD route for a path with matching handler.
-func (r *Router) HEAD(path string, h httpx.Handler) {
-	r.Handle("HEAD", path, h)
+func (r *Router) HEAD(path string, h httpx.Handler) *httpx.Route {
+	return r.Handle("HEAD", path, h)
 }
 
 // OPTIONS registers a new OPTIONS route for a path with matching handler.
-func (r *Router) OPTIONS(path string, h httpx.Handler) {
-	r.Handle("OPTIONS", path, h)
+func (r *Router) OPTIONS(path string, h httpx.Handler) *httpx.Route {
+	return r.Handle("OPTIONS", path, h)
 }
 
+func (r *Router) notifyRoute(method, path string) {
+	r.hooks.NotifyRoute(r.routeInfo(method, path))
+}
+
+// checkRoute panics when the engine is frozen, see httpx.Engine.Freeze.
+func (r *Router) checkRoute(method, path string) {
+	if err := r.hooks.CheckRoute(r.routeInfo(method, path)); err != nil {
+		panic(err)
+	}
+}
+
+// rebuildTree makes routes registered while the engine is running visible,
+// as fiber builds its route tree when it starts, see WithAllowRuntimeRoutes.
+func (r *Router) rebuildTree() {
+	if r.engine.IsRunning() {
+		r.engine.engine.RebuildTree()
+	}
+}
+
+func (r *Router) routeInfo(method, path string) httpx.RouteInfo {
+	return httpx.RouteInfo{
+		Method: method,
+		Path:   joinPaths(r.BasePath(), path),
+	}
+}
+
+// combineHandlers returns the router's middleware as fiber handlers followed
+// by h, for file handlers that cannot be composed.
 func (r *Router) combineHandlers(h fiber.Handler) []any {
 	mid := make([]any, 0, len(r.middlewares)+1)
 	for _, m := range r.middlewares {
@@ -109,22 +156,14 @@ func (r *Router) combineHandlers(h fiber.Handler) []any {
 	return mid
 }
 
-func (r *Router) adaptHandler(h httpx.Handler) []any {
-	return r.combineHandlers(func(ctx fiber.Ctx) error {
-		fc := newFiberContext(ctx)
+func (r *Router) adaptHandler(route *httpx.Route, h httpx.Handler) fiber.Handler {
+	h = route.Compose(h, r.middlewares...)
+	return func(ctx fiber.Ctx) error {
+		fc := acquireFiberContext(ctx)
+		defer releaseFiberContext(fc)
 		// Return error directly to fiber's error handling system
 		return h(fc)
-	})
-}
-
-func splitHandlers(handlers []any) (any, []any) {
-	if len(handlers) == 0 {
-		return nil, nil
-	}
-	if len(handlers) == 1 {
-		return handlers[0], nil
 	}
-	return handlers[0], handlers[1:]
 }
 
 func joinPaths(absolutePath, relativePath string) string {
@@ -0,0 +1,115 @@
+package httpx
+
+import (
+	"encoding/base64"
+	"encoding/json"
+)
+
+// FlashCookieName is the name of the cookie that carries flash messages
+// from one request to the next.
+var FlashCookieName = "flash"
+
+// FlashMessage is a one-shot message, such as a confirmation shown once
+// after a form is submitted. Kind is free-form, for example "success" or
+// "error".
+type FlashMessage struct {
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+// Flashes holds the flash messages of a request, see Flash.
+type Flashes struct {
+	ctx      Context
+	incoming []FlashMessage
+	pending  []FlashMessage
+	consumed bool
+}
+
+var flashKey = NewKey[*Flashes]("httpx.flash")
+
+// Flash returns the flash messages of the request. Messages added with Add
+// are stored in a cookie and returned by Consume on a later request, usually
+// the one following a redirect:
+//
+//	httpx.Flash(ctx).Add("success", "Profile saved")
+//	return ctx.Redirect(http.StatusSeeOther, "/profile")
+//
+// The cookie is signed when a secret is set with SetCookieSecret, and
+// messages that fail verification are dropped. As it travels with every
+// request, it suits a few short messages only.
+func Flash(ctx Context) *Flashes {
+	if f, ok := flashKey.Get(ctx); ok {
+		return f
+	}
+	f := &Flashes{ctx: ctx, incoming: readFlashes(ctx)}
+	SetTyped(ctx, flashKey, f)
+	return f
+}
+
+// Add stores a message for the next request that consumes the flashes.
+// Messages of the current request that were not consumed are kept too.
+func (f *Flashes) Add(kind, message string) {
+	f.pending = append(f.pending, FlashMessage{Kind: kind, Message: message})
+	f.write()
+}
+
+// Consume returns the messages sent with the request and removes them, so
+// they are shown once. Messages added during the request are kept for the
+// next one.
+func (f *Flashes) Consume() []FlashMessage {
+	if f.consumed {
+		return nil
+	}
+	f.consumed = true
+	if len(f.incoming) > 0 {
+		f.write()
+	}
+	return f.incoming
+}
+
+// write replaces the flash cookie with the messages still to be shown.
+func (f *Flashes) write() {
+	var messages []FlashMessage
+	if !f.consumed {
+		messages = append(messages, f.incoming...)
+	}
+	messages = append(messages, f.pending...)
+	if len(messages) == 0 {
+		DeleteCookie(f.ctx, FlashCookieName)
+		return
+	}
+	data, err := json.Marshal(messages)
+	if err != nil {
+		return
+	}
+	if err := SetSignedCookie(f.ctx, FlashCookieName, string(data)); err == nil {
+		return
+	}
+	SetCookieValue(f.ctx, FlashCookieName, base64.RawURLEncoding.EncodeToString(data))
+}
+
+// readFlashes decodes the flash cookie of the request, returning nil when it
+// is missing or invalid.
+func readFlashes(ctx Context) []FlashMessage {
+	var data []byte
+	if cookieSecrets.Load() != nil {
+		value, err := SignedCookie(ctx, FlashCookieName)
+		if err != nil {
+			return nil
+		}
+		data = []byte(value)
+	} else {
+		value, err := ctx.Cookie(FlashCookieName)
+		if err != nil {
+			return nil
+		}
+		if data, err = base64.RawURLEncoding.DecodeString(value); err != nil {
+			return nil
+		}
+	}
+	var messages []FlashMessage
+	if err := json.Unmarshal(data, &messages); err != nil {
+		return nil
+	}
+	return messages
+}
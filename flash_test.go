@@ -0,0 +1,76 @@
+package httpx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestFlash(t *testing.T) {
+	for _, secret := range []string{"", "flash-secret"} {
+		t.Run("secret="+secret, func(t *testing.T) {
+			if secret != "" {
+				SetCookieSecret([]byte(secret))
+				defer cookieSecrets.Store(nil)
+			}
+			// serve runs handle with the cookies of the previous response.
+			var cookies []*http.Cookie
+			serve := func(handle func(Context)) {
+				t.Helper()
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				for _, cookie := range cookies {
+					if cookie.MaxAge >= 0 {
+						req.AddCookie(cookie)
+					}
+				}
+				rec := httptest.NewRecorder()
+				handle(NewContext(rec, req))
+				// Like browsers, keep the last cookie set under each name.
+				latest := map[string]*http.Cookie{}
+				for _, cookie := range cookies {
+					latest[cookie.Name] = cookie
+				}
+				for _, cookie := range rec.Result().Cookies() {
+					latest[cookie.Name] = cookie
+				}
+				cookies = cookies[:0]
+				for _, cookie := range latest {
+					cookies = append(cookies, cookie)
+				}
+			}
+
+			serve(func(ctx Context) {
+				Flash(ctx).Add("success", "Profile saved")
+				Flash(ctx).Add("info", "Check your email")
+			})
+			var got []FlashMessage
+			serve(func(ctx Context) {
+				got = Flash(ctx).Consume()
+				if again := Flash(ctx).Consume(); again != nil {
+					t.Fatalf("second Consume = %v, want nil", again)
+				}
+			})
+			want := []FlashMessage{{Kind: "success", Message: "Profile saved"}, {Kind: "info", Message: "Check your email"}}
+			if !slices.Equal(got, want) {
+				t.Fatalf("Consume = %v, want %v", got, want)
+			}
+			serve(func(ctx Context) {
+				if got := Flash(ctx).Consume(); got != nil {
+					t.Fatalf("Consume after the messages were shown = %v, want nil", got)
+				}
+			})
+
+			// Messages added while consuming are kept for the next request.
+			serve(func(ctx Context) { Flash(ctx).Add("error", "first") })
+			serve(func(ctx Context) {
+				Flash(ctx).Consume()
+				Flash(ctx).Add("error", "second")
+			})
+			serve(func(ctx Context) { got = Flash(ctx).Consume() })
+			if want := []FlashMessage{{Kind: "error", Message: "second"}}; !slices.Equal(got, want) {
+				t.Fatalf("Consume = %v, want %v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package httpx
+
+import (
+	"net/url"
+	"strings"
+)
+
+// ForwardedScheme returns the scheme the client used to reach the server:
+// the proto of the first Forwarded element, else the first X-Forwarded-Proto
+// value, else "https" when X-Forwarded-Ssl is "on". Only "http" and "https"
+// are accepted from headers. Without them, the scheme is "https" when tls is
+// set and "http" otherwise.
+//
+// The headers can be set by any client, so servers that are not behind a
+// proxy that overwrites them should not rely on the result for security
+// decisions.
+func ForwardedScheme(header func(key string) string, tls bool) string {
+	for _, scheme := range []string{
+		forwardedParam(header("Forwarded"), "proto"),
+		firstListValue(header("X-Forwarded-Proto")),
+	} {
+		if scheme = strings.ToLower(scheme); scheme == "http" || scheme == "https" {
+			return scheme
+		}
+	}
+	if strings.EqualFold(header("X-Forwarded-Ssl"), "on") || tls {
+		return "https"
+	}
+	return "http"
+}
+
+// ForwardedHost returns the host the client requested: the host of the first
+// Forwarded element, else the first X-Forwarded-Host value, else host. The
+// same caveat as ForwardedScheme applies.
+func ForwardedHost(header func(key string) string, host string) string {
+	if forwarded := forwardedParam(header("Forwarded"), "host"); forwarded != "" {
+		return forwarded
+	}
+	if forwarded := firstListValue(header("X-Forwarded-Host")); forwarded != "" {
+		return forwarded
+	}
+	return host
+}
+
+// RequestURL builds the absolute URL of a request from its scheme, host and
+// request URI as sent on the request line.
+func RequestURL(scheme, host, requestURI string) *url.URL {
+	u, err := url.ParseRequestURI(requestURI)
+	if err != nil {
+		u = &url.URL{Path: requestURI}
+	}
+	u.Scheme, u.Host = scheme, host
+	return u
+}
+
+// AbsURL returns the absolute URL of path on the server the client
+// requested, for Location headers, links in emails and hypermedia links:
+//
+//	httpx.AbsURL(ctx, "/users/42") // "https://api.example.com/users/42"
+//
+// The scheme and host honour the Forwarded and X-Forwarded-* headers, see
+// ForwardedScheme and ForwardedHost. path is resolved against the URL of the
+// request, so relative paths are relative to the request path, and absolute
+// URLs are returned as is. A path that does not parse is returned unchanged.
+func AbsURL(ctx RequestInfo, path string) string {
+	ref, err := url.Parse(path)
+	if err != nil {
+		return path
+	}
+	return ctx.URL().ResolveReference(ref).String()
+}
+
+// forwardedParam returns a parameter of the first element of an RFC 7239
+// Forwarded header value, unquoting it if needed.
+func forwardedParam(value, name string) string {
+	element, _, _ := strings.Cut(value, ",")
+	for pair := range strings.SplitSeq(element, ";") {
+		key, val, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || !strings.EqualFold(key, name) {
+			continue
+		}
+		if len(val) >= 2 && val[0] == '"' && val[len(val)-1] == '"' {
+			val = val[1 : len(val)-1]
+		}
+		return val
+	}
+	return ""
+}
+
+func firstListValue(value string) string {
+	first, _, _ := strings.Cut(value, ",")
+	return strings.TrimSpace(first)
+}
@@ -0,0 +1,99 @@
+package httpx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestForwardedSchemeAndHost(t *testing.T) {
+	tests := []struct {
+		name       string
+		headers    map[string]string
+		tls        bool
+		wantScheme string
+		wantHost   string
+	}{
+		{
+			name:       "no headers",
+			wantScheme: "http",
+			wantHost:   "origin",
+		},
+		{
+			name:       "tls without headers",
+			tls:        true,
+			wantScheme: "https",
+			wantHost:   "origin",
+		},
+		{
+			name: "forwarded takes precedence",
+			headers: map[string]string{
+				"Forwarded":         `for=192.0.2.60;Proto=HTTPS;host="example.org:8443", proto=http`,
+				"X-Forwarded-Proto": "http",
+				"X-Forwarded-Host":  "other",
+			},
+			wantScheme: "https",
+			wantHost:   "example.org:8443",
+		},
+		{
+			name: "x-forwarded lists use the first value",
+			headers: map[string]string{
+				"X-Forwarded-Proto": " https , http",
+				"X-Forwarded-Host":  "api.example.com, internal",
+			},
+			wantScheme: "https",
+			wantHost:   "api.example.com",
+		},
+		{
+			name:       "x-forwarded-ssl",
+			headers:    map[string]string{"X-Forwarded-Ssl": "on"},
+			wantScheme: "https",
+			wantHost:   "origin",
+		},
+		{
+			name:       "unknown proto is ignored",
+			headers:    map[string]string{"X-Forwarded-Proto": "javascript"},
+			tls:        true,
+			wantScheme: "https",
+			wantHost:   "origin",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := make(http.Header)
+			for k, v := range tt.headers {
+				header.Set(k, v)
+			}
+			if got := ForwardedScheme(header.Get, tt.tls); got != tt.wantScheme {
+				t.Fatalf("ForwardedScheme() = %q, want %q", got, tt.wantScheme)
+			}
+			if got := ForwardedHost(header.Get, "origin"); got != tt.wantHost {
+				t.Fatalf("ForwardedHost() = %q, want %q", got, tt.wantHost)
+			}
+		})
+	}
+}
+
+func TestRequestURL(t *testing.T) {
+	if got := RequestURL("https", "example.com", "/a%20b?x=1").String(); got != "https://example.com/a%20b?x=1" {
+		t.Fatalf("RequestURL() = %q", got)
+	}
+}
+
+func TestAbsURL(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "http://internal/api/orders/7?page=2", nil)
+	req.Header.Set("X-Forwarded-Proto", "https")
+	req.Header.Set("X-Forwarded-Host", "shop.example.com")
+	ctx := newHTTPContext(httptest.NewRecorder(), req, nil)
+	for path, want := range map[string]string{
+		"/users/42":                 "https://shop.example.com/users/42",
+		"items?sort=asc":            "https://shop.example.com/api/orders/items?sort=asc",
+		"":                          "https://shop.example.com/api/orders/7?page=2",
+		"https://cdn.example/a.png": "https://cdn.example/a.png",
+	} {
+		if got := AbsURL(ctx, path); got != want {
+			t.Fatalf("AbsURL(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
@@ -1,11 +1,47 @@
 package ginx
 
 import (
+	"errors"
 	"net/http"
+	"reflect"
 
 	"github.com/gin-gonic/gin/binding"
+	"github.com/go-sphere/httpx"
 )
 
+// validated returns the error of a bind by the httpx binder, or else
+// validates dst like gin's own bindings.
+func validated(err error, dst any) error {
+	if err != nil || binding.Validator == nil {
+		return err
+	}
+	return httpx.ToValidationError(binding.Validator.ValidateStruct(dst))
+}
+
+// bindError returns the error of a gin binding of dst as a
+// *httpx.ValidationError when it reports failed fields. gin's mapping errors,
+// such as those of strconv for "abc" bound to an int, do not name the field,
+// so rebind, an httpx binder of the same values, binds them again into a new
+// value to find the field failing the "type" rule.
+func bindError(err error, dst any, rebind func(dst any) error) error {
+	if err == nil {
+		return nil
+	}
+	var ve *httpx.ValidationError
+	if converted := httpx.ToValidationError(err); errors.As(converted, &ve) {
+		return converted
+	}
+	rt := reflect.TypeOf(dst)
+	if rt.Kind() != reflect.Pointer || rt.Elem().Kind() != reflect.Struct {
+		return err
+	}
+	// Missing fields are left to gin's validator.
+	if errors.As(rebind(reflect.New(rt.Elem()).Interface()), &ve) && len(ve.Errors) == 1 && ve.Errors[0].Rule == "type" {
+		return ve
+	}
+	return err
+}
+
 type QueryBinding struct{}
 
 func (QueryBinding) Name() string {
@@ -1,32 +1,63 @@
 package ginx
 
 import (
+	"bufio"
 	"context"
 	"errors"
+	"fmt"
 	"io"
+	"iter"
 	"mime/multipart"
+	"net"
 	"net/http"
-	"net/textproto"
+	"net/url"
 	"strings"
+	"sync"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 	"github.com/go-sphere/httpx"
 )
 
-var _ httpx.Context = (*ginContext)(nil)
+var (
+	_ httpx.Context           = (*ginContext)(nil)
+	_ httpx.ChainContext      = (*ginContext)(nil)
+	_ httpx.RequestBodySetter = (*ginContext)(nil)
+	_ httpx.Aborter           = (*ginContext)(nil)
+	_ httpx.ResponseSizer     = (*ginContext)(nil)
+)
 
 var queryBinding = QueryBinding{}
 
 type ginContext struct {
 	ctx        *gin.Context
 	nextCalled bool
+	chainNext  httpx.Handler
+	aborted    bool
+	state      *httpx.RequestState
+}
+
+// ginContexts pools the contexts passed to handlers and middleware. A
+// context is released when the handler or middleware it was passed to
+// returns, so it must not be kept, nor used from other goroutines, after
+// that; httpx.Detach keeps request values for work that outlives it.
+var ginContexts = sync.Pool{New: func() any { return new(ginContext) }}
+
+func acquireGinContext(gc *gin.Context) *ginContext {
+	c := ginContexts.Get().(*ginContext)
+	c.ctx = gc
+	return c
 }
 
-func newGinContext(gc *gin.Context) *ginContext {
-	return &ginContext{
-		ctx: gc,
+// releaseGinContext closes the request state of c, so that a context.Context
+// kept from it no longer resolves request state, clears c and returns it to
+// the pool.
+func releaseGinContext(c *ginContext) {
+	if c.state != nil {
+		c.state.Close()
 	}
+	*c = ginContext{}
+	ginContexts.Put(c)
 }
 
 // Request (httpx.Request)
@@ -83,20 +114,15 @@ func (c *ginContext) RawQuery() string {
 }
 
 func (c *ginContext) Header(key string) string {
-	return c.ctx.GetHeader(key)
+	return httpx.RequestHeader(c.ctx.Request, key)
+}
+
+func (c *ginContext) HeaderValues(key string) []string {
+	return httpx.RequestHeaderValues(c.ctx.Request, key)
 }
 
 func (c *ginContext) Headers() map[string][]string {
-	src := c.ctx.Request.Header
-	if len(src) == 0 {
-		return nil
-	}
-	out := make(map[string][]string, len(src))
-	for k, v := range src {
-		ck := textproto.CanonicalMIMEHeaderKey(k)
-		out[ck] = append([]string(nil), v...)
-	}
-	return out
+	return httpx.RequestHeaders(c.ctx.Request.Header)
 }
 
 func (c *ginContext) Cookie(name string) (string, error) {
@@ -119,15 +145,55 @@ func (c *ginContext) Cookies() map[string]string {
 	return out
 }
 
+func (c *ginContext) AllQueries() iter.Seq2[string, string] {
+	return httpx.QueryPairs(c.ctx.Request.URL.RawQuery)
+}
+
+func (c *ginContext) AllHeaders() iter.Seq2[string, string] {
+	return httpx.HeaderPairs(c.ctx.Request.Header)
+}
+
+func (c *ginContext) AllCookies() iter.Seq2[string, string] {
+	return httpx.CookiePairs(c.ctx.Request)
+}
+
+func (c *ginContext) Scheme() string {
+	return httpx.ForwardedScheme(c.ctx.GetHeader, c.ctx.Request.TLS != nil)
+}
+
+func (c *ginContext) Host() string {
+	return httpx.ForwardedHost(c.ctx.GetHeader, c.ctx.Request.Host)
+}
+
+func (c *ginContext) Proto() string {
+	return c.ctx.Request.Proto
+}
+
+func (c *ginContext) ContentLength() int64 {
+	return c.ctx.Request.ContentLength
+}
+
+func (c *ginContext) UserAgent() string {
+	return c.ctx.Request.UserAgent()
+}
+
+func (c *ginContext) Referer() string {
+	return c.ctx.Request.Referer()
+}
+
+func (c *ginContext) URL() *url.URL {
+	u := *c.ctx.Request.URL
+	u.Scheme, u.Host = c.Scheme(), c.Host()
+	return &u
+}
+
 func (c *ginContext) FormValue(key string) string {
+	_ = httpx.CacheFormBody(c.ctx.Request)
 	return c.ctx.Request.FormValue(key)
 }
 
 func (c *ginContext) MultipartForm() (*multipart.Form, error) {
-	if err := c.ctx.Request.ParseMultipartForm(32 << 20); err != nil {
-		return nil, err
-	}
-	return c.ctx.Request.MultipartForm, nil
+	return c.ctx.MultipartForm()
 }
 
 func (c *ginContext) FormFile(name string) (*multipart.FileHeader, error) {
@@ -135,40 +201,72 @@ func (c *ginContext) FormFile(name string) (*multipart.FileHeader, error) {
 }
 
 func (c *ginContext) BodyRaw() ([]byte, error) {
-	return c.ctx.GetRawData()
+	return httpx.ReusableBody(c.ctx.Request)
 }
 
 func (c *ginContext) BodyReader() io.ReadCloser {
-	if c.ctx.Request.Body != nil {
-		return c.ctx.Request.Body
-	}
-	return http.NoBody
+	return httpx.ReusableBodyReader(c.ctx.Request)
+}
+
+func (c *ginContext) SetRequestBody(body []byte) {
+	httpx.SetRequestBody(c.ctx.Request, body)
 }
 
 // Binder (httpx.Binder)
 
 func (c *ginContext) BindJSON(dst any) error {
-	return c.ctx.ShouldBindJSON(dst)
+	if _, err := httpx.ReusableBody(c.ctx.Request); err != nil {
+		return err
+	}
+	return httpx.ToValidationError(c.ctx.ShouldBindJSON(dst))
 }
 
 func (c *ginContext) BindQuery(dst any) error {
-	return queryBinding.Bind(c.ctx.Request, dst)
+	query := c.ctx.Request.URL.Query()
+	if httpx.NeedsValueBinder(dst) || httpx.HasBracketKeys(query) {
+		return validated(httpx.BindValues(dst, "query", query), dst)
+	}
+	return bindError(queryBinding.Bind(c.ctx.Request, dst), dst, func(dst any) error {
+		return httpx.BindValues(dst, "query", query)
+	})
 }
 
 func (c *ginContext) BindForm(dst any) error {
+	if err := httpx.CacheFormBody(c.ctx.Request); err != nil {
+		return err
+	}
+	// gin's MultipartForm parses with the engine's MaxMultipartMemory, which
+	// gin's form binding does not use.
+	if _, err := c.ctx.MultipartForm(); err != nil && !errors.Is(err, http.ErrNotMultipart) {
+		return err
+	}
+	if httpx.NeedsValueBinder(dst) || httpx.HasBracketKeys(c.ctx.Request.PostForm) {
+		return validated(httpx.BindValues(dst, "form", c.ctx.Request.PostForm), dst)
+	}
+	rebind := func(dst any) error {
+		return httpx.BindValues(dst, "form", c.ctx.Request.PostForm)
+	}
 	contentType := c.ctx.GetHeader("Content-Type")
 	if strings.HasPrefix(strings.ToLower(contentType), "multipart/") {
-		return c.ctx.ShouldBindWith(dst, binding.FormMultipart)
+		return bindError(c.ctx.ShouldBindWith(dst, binding.FormMultipart), dst, rebind)
 	}
-	return c.ctx.ShouldBindWith(dst, binding.Form)
+	return bindError(c.ctx.ShouldBindWith(dst, binding.Form), dst, rebind)
 }
 
 func (c *ginContext) BindURI(dst any) error {
-	return c.ctx.ShouldBindUri(dst)
+	if httpx.UsesBindTypes(dst) {
+		return validated(httpx.BindParams(dst, c.Params()), dst)
+	}
+	return httpx.ToValidationError(c.ctx.ShouldBindUri(dst))
 }
 
 func (c *ginContext) BindHeader(dst any) error {
-	return c.ctx.ShouldBindHeader(dst)
+	if httpx.NeedsValueBinder(dst) {
+		return validated(httpx.BindHeaders(dst, c.ctx.Request.Header), dst)
+	}
+	return bindError(c.ctx.ShouldBindHeader(dst), dst, func(dst any) error {
+		return httpx.BindHeaders(dst, c.ctx.Request.Header)
+	})
 }
 
 // Responder (httpx.Responder)
@@ -178,6 +276,13 @@ func (c *ginContext) Status(code int) {
 }
 
 func (c *ginContext) JSON(code int, v any) error {
+	if opts, ok := httpx.JSONOptionsKey.Get(c); ok {
+		body, err := opts.Marshal(v)
+		if err != nil {
+			return err
+		}
+		return c.Bytes(code, body, "application/json; charset=utf-8")
+	}
 	c.ctx.JSON(code, v)
 	return nil
 }
@@ -240,15 +345,52 @@ func (c *ginContext) Get(key string) (any, bool) {
 // Context (context.Context accessor + Next)
 
 func (c *ginContext) Context() context.Context {
-	return c.ctx.Request.Context()
+	if c.state == nil {
+		c.state = httpx.NewRequestState(c)
+	}
+	return httpx.WithState(c.ctx.Request.Context(), c.state)
 }
 
 func (c *ginContext) SetContext(ctx context.Context) {
 	c.ctx.Request = c.ctx.Request.WithContext(ctx)
 }
 
+// Aborter (httpx.Aborter)
+
+func (c *ginContext) Abort() {
+	c.aborted = true
+	c.Set(httpx.AbortedKey, true)
+}
+
+func (c *ginContext) AbortWithStatus(code int) error {
+	c.Abort()
+	return c.NoContent(code)
+}
+
+func (c *ginContext) AbortWithStatusJSON(code int, obj any) error {
+	c.Abort()
+	return c.JSON(code, obj)
+}
+
+func (c *ginContext) IsAborted() bool {
+	_, ok := c.Get(httpx.AbortedKey)
+	return ok
+}
+
+// SetNext sets the rest of a chain composed by httpx.Compose.
+func (c *ginContext) SetNext(next httpx.Handler) {
+	c.chainNext = next
+}
+
 func (c *ginContext) Next() error {
+	if c.aborted {
+		return nil
+	}
 	c.nextCalled = true
+	if next := c.chainNext; next != nil {
+		c.chainNext = nil
+		return next(c)
+	}
 	before := len(c.ctx.Errors)
 	c.ctx.Next()
 
@@ -281,6 +423,121 @@ func (c *ginContext) StatusCode() int {
 	return c.ctx.Writer.Status()
 }
 
+func (c *ginContext) BodySize() int {
+	return max(c.ctx.Writer.Size(), 0)
+}
+
 func (c *ginContext) NativeContext() any {
 	return c.ctx
 }
+
+// Unwrap returns the *gin.Context behind ctx, reporting false for contexts of
+// other adapters. Gin pools its contexts, so the returned value is only valid
+// until the handler chain returns; use gin.Context.Copy before handing it to
+// background goroutines.
+func Unwrap(ctx httpx.Context) (*gin.Context, bool) {
+	if c, ok := ctx.(*ginContext); ok {
+		return c.ctx, true
+	}
+	return httpx.AsNativeContext[*gin.Context](ctx)
+}
+
+// Flusher and Hijacker (httpx.Flusher, httpx.Hijacker)
+
+func (c *ginContext) Flush() error {
+	return http.NewResponseController(c.ctx.Writer).Flush()
+}
+
+func (c *ginContext) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	// gin's writer panics when the connection cannot be hijacked.
+	if !canHijack(c.ctx.Writer) {
+		return nil, nil, fmt.Errorf("ginx: hijack: %w", http.ErrNotSupported)
+	}
+	return c.ctx.Writer.Hijack()
+}
+
+// canHijack reports whether the innermost writer behind w, reached through
+// Unwrap, implements http.Hijacker.
+func canHijack(w http.ResponseWriter) bool {
+	for {
+		u, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			_, ok := w.(http.Hijacker)
+			return ok
+		}
+		w = u.Unwrap()
+	}
+}
+
+// InformationalWriter (httpx.InformationalWriter)
+
+func (c *ginContext) WriteInformational(code int, header http.Header) error {
+	if c.ctx.Writer.Written() {
+		return httpx.ErrResponseCommitted
+	}
+	// gin's writer holds back WriteHeader until the body is written, so the
+	// informational response goes to the writer it wraps.
+	u, ok := c.ctx.Writer.(interface{ Unwrap() http.ResponseWriter })
+	if !ok {
+		return fmt.Errorf("ginx: informational response: %w", http.ErrNotSupported)
+	}
+	return httpx.WriteHTTPInformational(u.Unwrap(), code, header)
+}
+
+// HTTPInterop (httpx.HTTPInterop)
+
+func (c *ginContext) HTTPRequest() *http.Request {
+	return c.ctx.Request
+}
+
+func (c *ginContext) SetHTTPRequest(r *http.Request) {
+	c.ctx.Request = r
+}
+
+func (c *ginContext) HTTPResponseWriter() http.ResponseWriter {
+	return c.ctx.Writer
+}
+
+func (c *ginContext) SetHTTPResponseWriter(w http.ResponseWriter) {
+	if gw, ok := w.(gin.ResponseWriter); ok {
+		c.ctx.Writer = gw
+		return
+	}
+	c.ctx.Writer = &wrappedWriter{ResponseWriter: c.ctx.Writer, w: w}
+}
+
+// wrappedWriter routes the writes of a gin.ResponseWriter through a net/http
+// writer installed by net/http middleware, while keeping gin's own status and
+// size bookkeeping, which is updated when w writes to the original writer.
+type wrappedWriter struct {
+	gin.ResponseWriter
+	w http.ResponseWriter
+}
+
+func (w *wrappedWriter) Header() http.Header {
+	return w.w.Header()
+}
+
+func (w *wrappedWriter) WriteHeader(code int) {
+	w.w.WriteHeader(code)
+}
+
+func (w *wrappedWriter) Write(b []byte) (int, error) {
+	return w.w.Write(b)
+}
+
+func (w *wrappedWriter) WriteString(s string) (int, error) {
+	return io.WriteString(w.w, s)
+}
+
+func (w *wrappedWriter) Flush() {
+	if f, ok := w.w.(http.Flusher); ok {
+		f.Flush()
+		return
+	}
+	w.ResponseWriter.Flush()
+}
+
+func (w *wrappedWriter) Unwrap() http.ResponseWriter {
+	return w.w
+}
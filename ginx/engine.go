@@ -2,21 +2,61 @@ package ginx
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
 	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"os"
+	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-sphere/httpx"
 )
 
-var _ httpx.Engine = (*Engine)(nil)
+var (
+	_ httpx.Engine               = (*Engine)(nil)
+	_ httpx.NativeEngineProvider = (*Engine)(nil)
+	_ http.Handler               = (*Engine)(nil)
+)
 
 type ErrorHandler func(ctx *gin.Context, err error)
 
 type Config struct {
-	engine     *gin.Engine
-	server     *http.Server
-	errHandler ErrorHandler
+	engine          *gin.Engine
+	newEngine       func() *gin.Engine
+	server          *http.Server
+	listener        net.Listener
+	unixSocket      string
+	unixPerm        os.FileMode
+	errHandler      ErrorHandler
+	shutdownTimeout time.Duration
+	readTimeout     time.Duration
+	writeTimeout    time.Duration
+	idleTimeout     time.Duration
+	headerTimeout   time.Duration
+	connRequests    int
+	requestTimeout  time.Duration
+	maxHeaderBytes  int
+	concurrency     int
+	multipartMemory int64
+	trustedProxies  []netip.Prefix
+	trustProxies    bool
+	certFile        string
+	keyFile         string
+	tlsConfig       *tls.Config
+	h2c             bool
+	runtimeRoutes   bool
+	basePath        string
+	panicPolicy     httpx.PanicPolicy
+	errorPages      fs.FS
+	jsonOptions     *httpx.JSONOptions
+	envelope        httpx.EnvelopeFunc
 }
 
 type Option func(*Config)
@@ -27,15 +67,49 @@ func NewConfig(opts ...Option) *Config {
 		opt(&conf)
 	}
 	if conf.engine == nil {
-		conf.engine = gin.Default()
+		if conf.newEngine == nil {
+			conf.newEngine = func() *gin.Engine { return gin.Default() }
+			conf.trustProxies = true
+		}
+		conf.engine = conf.newEngine()
+	}
+	if conf.multipartMemory > 0 {
+		conf.engine.MaxMultipartMemory = conf.multipartMemory
+	}
+	if conf.trustProxies {
+		trustProxies(conf.engine, conf.trustedProxies)
 	}
 	if conf.server == nil {
 		conf.server = &http.Server{
 			Addr: ":8080",
 		}
 	}
+	if conf.readTimeout > 0 {
+		conf.server.ReadTimeout = conf.readTimeout
+	}
+	if conf.writeTimeout > 0 {
+		conf.server.WriteTimeout = conf.writeTimeout
+	}
+	if conf.idleTimeout > 0 {
+		conf.server.IdleTimeout = conf.idleTimeout
+	}
+	if conf.headerTimeout > 0 {
+		conf.server.ReadHeaderTimeout = conf.headerTimeout
+	}
+	if conf.maxHeaderBytes > 0 {
+		conf.server.MaxHeaderBytes = conf.maxHeaderBytes
+	}
 	if conf.errHandler == nil {
 		conf.errHandler = func(ctx *gin.Context, err error) {
+			fc := acquireGinContext(ctx)
+			err = httpx.LocalizeError(fc, err)
+			releaseGinContext(fc)
+			var ve *httpx.ValidationError
+			if errors.As(err, &ve) {
+				ctx.JSON(http.StatusUnprocessableEntity, ve)
+				ctx.Abort()
+				return
+			}
 			ctx.JSON(500, gin.H{
 				"error": err.Error(),
 			})
@@ -48,6 +122,17 @@ func NewConfig(opts ...Option) *Config {
 func WithEngine(engine *gin.Engine) Option {
 	return func(conf *Config) {
 		conf.engine = engine
+		conf.newEngine = nil
+	}
+}
+
+// WithEngineFactory creates the gin engine with newEngine instead of
+// gin.Default. ReplaceRoutes calls it again for each new route table, which
+// it cannot do for an engine set with WithEngine.
+func WithEngineFactory(newEngine func() *gin.Engine) Option {
+	return func(conf *Config) {
+		conf.engine = nil
+		conf.newEngine = newEngine
 	}
 }
 
@@ -75,46 +160,402 @@ func WithErrorHandler(errHandler ErrorHandler) Option {
 	}
 }
 
+// WithListener serves on a pre-bound listener instead of the server address,
+// for example one inherited through systemd socket activation.
+func WithListener(ln net.Listener) Option {
+	return func(conf *Config) {
+		conf.listener = ln
+	}
+}
+
+// WithUnixSocket serves on a unix domain socket at path instead of TCP. perm
+// is applied to the socket file when non-zero. Stale socket files are removed
+// before listening and the socket file is removed on shutdown.
+func WithUnixSocket(path string, perm os.FileMode) Option {
+	return func(conf *Config) {
+		conf.unixSocket = path
+		conf.unixPerm = perm
+	}
+}
+
+// WithShutdownTimeout bounds how long Stop waits for in-flight requests.
+// A zero or negative timeout relies solely on the context passed to Stop.
+func WithShutdownTimeout(timeout time.Duration) Option {
+	return func(conf *Config) {
+		conf.shutdownTimeout = timeout
+	}
+}
+
+// WithReadTimeout bounds reading a request, including its body. It sets
+// http.Server.ReadTimeout.
+func WithReadTimeout(timeout time.Duration) Option {
+	return func(conf *Config) {
+		conf.readTimeout = timeout
+	}
+}
+
+// WithWriteTimeout bounds writing the response. It sets http.Server.WriteTimeout,
+// which also bounds the handler.
+func WithWriteTimeout(timeout time.Duration) Option {
+	return func(conf *Config) {
+		conf.writeTimeout = timeout
+	}
+}
+
+// WithIdleTimeout bounds how long a keep-alive connection waits for the next
+// request. It sets http.Server.IdleTimeout.
+func WithIdleTimeout(timeout time.Duration) Option {
+	return func(conf *Config) {
+		conf.idleTimeout = timeout
+	}
+}
+
+// WithReadHeaderTimeout bounds reading the request headers, so clients
+// sending them slowly cannot hold connections open. It sets
+// http.Server.ReadHeaderTimeout.
+func WithReadHeaderTimeout(timeout time.Duration) Option {
+	return func(conf *Config) {
+		conf.headerTimeout = timeout
+	}
+}
+
+// WithMaxRequestsPerConn closes keep-alive connections once they have served
+// n requests, see httpx.LimitConnRequests.
+func WithMaxRequestsPerConn(n int) Option {
+	return func(conf *Config) {
+		conf.connRequests = n
+	}
+}
+
+// WithRequestTimeout gives the context of each request a deadline timeout
+// after it starts, see httpx.RequestTimeout.
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(conf *Config) {
+		conf.requestTimeout = timeout
+	}
+}
+
+// WithMaxHeaderBytes bounds the size of request headers. It sets
+// http.Server.MaxHeaderBytes.
+func WithMaxHeaderBytes(n int) Option {
+	return func(conf *Config) {
+		conf.maxHeaderBytes = n
+	}
+}
+
+// WithConcurrencyLimit bounds the number of connections served at once.
+// Further connections wait
+// in the listen backlog until one of them is closed, see httpx.LimitListener.
+func WithConcurrencyLimit(n int) Option {
+	return func(conf *Config) {
+		conf.concurrency = n
+	}
+}
+
+// WithMultipartMemoryLimit sets how many bytes of a multipart form
+// MultipartForm, FormFile and BindForm keep in memory; larger file parts are
+// written to temporary files. It sets
+// the MaxMultipartMemory of the gin engine, and of those created by
+// ReplaceRoutes.
+func WithMultipartMemoryLimit(bytes int64) Option {
+	return func(conf *Config) {
+		conf.multipartMemory = bytes
+	}
+}
+
+// WithTrustedProxies sets the proxies whose X-Forwarded-For and X-Real-IP
+// headers Context.ClientIP believes, replacing the trusted proxies of the gin
+// engine, and of those created by ReplaceRoutes.
+func WithTrustedProxies(proxies ...netip.Prefix) Option {
+	return func(conf *Config) {
+		conf.trustedProxies = proxies
+		conf.trustProxies = true
+	}
+}
+
+// trustProxies sets the trusted proxies of engine; gin trusts every peer
+// unless told otherwise.
+func trustProxies(engine *gin.Engine, proxies []netip.Prefix) {
+	cidrs := make([]string, len(proxies))
+	for i, p := range proxies {
+		cidrs[i] = p.String()
+	}
+	// Formatted prefixes always parse.
+	_ = engine.SetTrustedProxies(cidrs)
+}
+
+// WithTLS sets the certificate and key files used by StartTLS.
+func WithTLS(certFile, keyFile string) Option {
+	return func(conf *Config) {
+		conf.certFile = certFile
+		conf.keyFile = keyFile
+	}
+}
+
+// WithTLSConfig sets the TLS config used by StartTLS. When combined with
+// WithTLS, the certificate pair is appended to the config's certificates.
+func WithTLSConfig(tlsConfig *tls.Config) Option {
+	return func(conf *Config) {
+		conf.tlsConfig = tlsConfig
+	}
+}
+
+// WithH2C enables cleartext HTTP/2 on the plain listener used by Start.
+func WithH2C(enabled bool) Option {
+	return func(conf *Config) {
+		conf.h2c = enabled
+	}
+}
+
+// WithAllowRuntimeRoutes keeps the routes open to registration after Start,
+// which otherwise freezes them, see httpx.Engine.Freeze. gin does not synchronize
+// its route tree, so routes registered while requests are served race with
+// them.
+func WithAllowRuntimeRoutes(allowed bool) Option {
+	return func(conf *Config) {
+		conf.runtimeRoutes = allowed
+	}
+}
+
+// WithBasePath prefixes the routes of every group created by the engine with
+// path, for services mounted under a path by a gateway. Router.BasePath,
+// FullPath and the routes reported to OnRouteRegistered include it.
+func WithBasePath(path string) Option {
+	return func(conf *Config) {
+		conf.basePath = path
+	}
+}
+
+// WithPanicPolicy sets what happens when a handler or middleware panics,
+// see httpx.PanicPolicy. The default, httpx.PanicPropagate, leaves panics to
+// the framework.
+func WithPanicPolicy(policy httpx.PanicPolicy) Option {
+	return func(conf *Config) {
+		conf.panicPolicy = policy
+	}
+}
+
+// WithHTMLErrorPages renders error responses as the static HTML pages of
+// pages for browsers, such as "404.html" and "500.html", see
+// httpx.HTMLErrorPages. Other clients get the response of the error handler.
+func WithHTMLErrorPages(pages fs.FS) Option {
+	return func(conf *Config) {
+		conf.errorPages = pages
+	}
+}
+
+// WithEnvelope makes httpx.OK, httpx.Created and httpx.EnvelopeErrorHandler
+// write the body returned by format instead of the httpx.Envelope, see
+// httpx.WithEnvelope.
+func WithEnvelope(format httpx.EnvelopeFunc) Option {
+	return func(conf *Config) {
+		conf.envelope = format
+	}
+}
+
+// WithJSONOptions makes the engine encode the bodies written with JSON
+// using opts instead of the framework's encoder, see httpx.JSONOptions.
+func WithJSONOptions(opts httpx.JSONOptions) Option {
+	return func(conf *Config) {
+		conf.jsonOptions = &opts
+	}
+}
+
 type Engine struct {
-	engine     *gin.Engine
-	server     *http.Server
-	errHandler ErrorHandler
-	running    atomic.Bool
+	engine          atomic.Pointer[gin.Engine]
+	newEngine       func() *gin.Engine
+	middlewares     []httpx.Middleware
+	server          *http.Server
+	listener        net.Listener
+	unixSocket      string
+	unixPerm        os.FileMode
+	errHandler      ErrorHandler
+	shutdownTimeout time.Duration
+	concurrency     int
+	multipartMemory int64
+	trustedProxies  []netip.Prefix
+	trustProxies    bool
+	certFile        string
+	keyFile         string
+	tlsConfig       *tls.Config
+	hooks           *httpx.Hooks
+	running         atomic.Bool
+	mu              sync.Mutex
+	boundAddr       net.Addr
+	runtimeRoutes   bool
+	basePath        string
 }
 
 // New constructs a gin-backed Engine using core options.
 func New(opts ...Option) httpx.Engine {
 	conf := NewConfig(opts...)
-	conf.server.Handler = conf.engine
-	return &Engine{
-		engine:     conf.engine,
-		server:     conf.server,
-		errHandler: conf.errHandler,
+	if conf.errorPages != nil {
+		conf.errHandler = errorPagesHandler(conf.errorPages, conf.errHandler)
+	}
+	engine := &Engine{
+		newEngine:       conf.newEngine,
+		server:          conf.server,
+		listener:        conf.listener,
+		unixSocket:      conf.unixSocket,
+		unixPerm:        conf.unixPerm,
+		errHandler:      conf.errHandler,
+		shutdownTimeout: conf.shutdownTimeout,
+		concurrency:     conf.concurrency,
+		multipartMemory: conf.multipartMemory,
+		trustedProxies:  conf.trustedProxies,
+		trustProxies:    conf.trustProxies,
+		certFile:        conf.certFile,
+		keyFile:         conf.keyFile,
+		tlsConfig:       conf.tlsConfig,
+		runtimeRoutes:   conf.runtimeRoutes,
+		basePath:        conf.basePath,
+		hooks:           &httpx.Hooks{},
+	}
+	engine.engine.Store(conf.engine)
+	// The server serves the current gin engine, which ReplaceRoutes swaps.
+	conf.server.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		engine.engine.Load().ServeHTTP(w, r)
+	})
+	if conf.h2c {
+		httpx.EnableH2C(conf.server)
+	}
+	if conf.connRequests > 0 {
+		httpx.LimitConnRequests(conf.server, conf.connRequests)
+	}
+	if conf.jsonOptions != nil {
+		engine.Use(httpx.WithJSONOptions(*conf.jsonOptions))
 	}
+	if conf.envelope != nil {
+		engine.Use(httpx.WithEnvelope(conf.envelope))
+	}
+	if conf.errorPages != nil {
+		engine.Use(httpx.HTMLErrorPages(conf.errorPages))
+	}
+	if conf.panicPolicy != httpx.PanicPropagate {
+		engine.Use(httpx.RecoverPanics(conf.panicPolicy))
+	}
+	if conf.requestTimeout > 0 {
+		engine.Use(httpx.RequestTimeout(conf.requestTimeout))
+	}
+	return engine
 }
 
 func (e *Engine) Use(middleware ...httpx.Middleware) {
-	e.engine.Use(adaptMiddlewares(middleware, e.errHandler)...)
+	e.middlewares = append(e.middlewares, middleware...)
+	e.engine.Load().Use(adaptMiddlewares(middleware, e.errHandler)...)
 }
 
 func (e *Engine) Group(prefix string, m ...httpx.Middleware) httpx.Router {
+	return e.group(e.engine.Load(), prefix, m...)
+}
+
+func (e *Engine) group(engine *gin.Engine, prefix string, m ...httpx.Middleware) *Router {
+	prefix = httpx.JoinPaths(e.basePath, prefix)
 	return &Router{
-		group:      e.engine.Group(prefix, adaptMiddlewares(m, e.errHandler)...),
-		errHandler: e.errHandler,
+		group:       engine.Group(prefix),
+		middlewares: cloneMiddlewares(nil, m...),
+		errHandler:  e.errHandler,
+		hooks:       e.hooks,
 	}
 }
 
 func (e *Engine) Start() error {
+	if err := e.hooks.RunStart(); err != nil {
+		return err
+	}
+	if !e.runtimeRoutes {
+		e.Freeze()
+	}
+	ln, err := e.netListener(":http")
+	if err != nil {
+		return err
+	}
+	e.setBoundAddr(ln.Addr())
+	defer e.setBoundAddr(nil)
+	e.running.Store(true)
+	defer e.running.Store(false)
+	return e.server.Serve(ln)
+}
+
+// StartTLS serves HTTPS using the certificates configured by WithTLS and
+// WithTLSConfig, falling back to the server's own TLSConfig.
+func (e *Engine) StartTLS() error {
+	base := e.tlsConfig
+	if base == nil {
+		base = e.server.TLSConfig
+	}
+	tlsConfig, err := httpx.NewTLSConfig(base, e.certFile, e.keyFile)
+	if err != nil {
+		return err
+	}
+	if err := e.hooks.RunStart(); err != nil {
+		return err
+	}
+	if !e.runtimeRoutes {
+		e.Freeze()
+	}
+	ln, err := e.netListener(":https")
+	if err != nil {
+		return err
+	}
+	e.server.TLSConfig = tlsConfig
+	e.setBoundAddr(ln.Addr())
+	defer e.setBoundAddr(nil)
 	e.running.Store(true)
 	defer e.running.Store(false)
-	return e.server.ListenAndServe()
+	return e.server.ServeTLS(ln, "", "")
+}
+
+// netListener returns the listener to serve on, limited to the connections
+// allowed by WithConcurrencyLimit.
+func (e *Engine) netListener(defaultAddr string) (net.Listener, error) {
+	ln, err := e.listen(defaultAddr)
+	if err != nil || e.concurrency <= 0 {
+		return ln, err
+	}
+	return httpx.LimitListener(ln, e.concurrency), nil
+}
+
+// listen returns the unix socket, the injected listener, or a TCP listener
+// on the server address, falling back to defaultAddr like http.Server does.
+func (e *Engine) listen(defaultAddr string) (net.Listener, error) {
+	if e.unixSocket != "" {
+		return httpx.ListenUnix(e.unixSocket, e.unixPerm)
+	}
+	if e.listener != nil {
+		return e.listener, nil
+	}
+	addr := e.server.Addr
+	if addr == "" {
+		addr = defaultAddr
+	}
+	return net.Listen("tcp", addr)
 }
 
 func (e *Engine) Stop(ctx context.Context) error {
+	e.hooks.RunStopping()
+	if e.shutdownTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.shutdownTimeout)
+		defer cancel()
+	}
 	err := httpx.Close(ctx, e.server)
 	if err == nil {
 		e.running.Store(false)
 	}
+	if e.unixSocket != "" {
+		err = errors.Join(err, httpx.RemoveUnixSocket(e.unixSocket))
+	}
+	return errors.Join(err, e.hooks.RunStop(ctx))
+}
+
+// StopGraceful stops accepting connections, waits for in-flight requests,
+// and then runs drain.
+func (e *Engine) StopGraceful(ctx context.Context, drain func()) error {
+	err := e.Stop(ctx)
+	if drain != nil {
+		drain()
+	}
 	return err
 }
 
@@ -122,3 +563,113 @@ func (e *Engine) Stop(ctx context.Context) error {
 func (e *Engine) IsRunning() bool {
 	return e.running.Load()
 }
+
+// BoundAddr returns the address the server is listening on while running.
+func (e *Engine) BoundAddr() net.Addr {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.boundAddr
+}
+
+// ServeHTTP serves a net/http request through the current gin engine, so the
+// engine can be mounted with httpx.MountEngine.
+func (e *Engine) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.engine.Load().ServeHTTP(w, r)
+}
+
+// Test serves req in-process through the gin engine and returns the recorded
+// response.
+func (e *Engine) Test(req *http.Request) (*http.Response, error) {
+	rec := httptest.NewRecorder()
+	e.engine.Load().ServeHTTP(rec, req)
+	return rec.Result(), nil
+}
+
+func (e *Engine) setBoundAddr(addr net.Addr) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.boundAddr = addr
+}
+
+// OnStart registers a hook that runs before the server starts listening.
+func (e *Engine) OnStart(fn func() error) {
+	e.hooks.OnStart(fn)
+}
+
+// OnStopping registers a hook that runs when Stop is called, before shutdown.
+func (e *Engine) OnStopping(fn func()) {
+	e.hooks.OnStopping(fn)
+}
+
+// OnStop registers a hook that runs after the server has shut down.
+func (e *Engine) OnStop(fn func(ctx context.Context) error) {
+	e.hooks.OnStop(fn)
+}
+
+// OnRouteRegistered registers a hook invoked for every handler route.
+func (e *Engine) OnRouteRegistered(fn func(httpx.RouteInfo)) {
+	e.hooks.OnRouteRegistered(fn)
+}
+
+func (e *Engine) Freeze() {
+	e.hooks.Freeze()
+}
+
+// Native returns the *http.Server of the engine, see Server.
+func (e *Engine) Native() any {
+	return e.server
+}
+
+// Server returns the *http.Server of a ginx engine, or nil for engines of
+// other adapters. Change it before Start, as the server reads most settings
+// when it starts serving. Its Handler serves the gin engine and must be kept.
+func Server(engine httpx.Engine) *http.Server {
+	if e, ok := engine.(*Engine); ok {
+		return e.server
+	}
+	return nil
+}
+
+// ReplaceRoutes implements httpx.RouteReplacer: register adds routes to a
+// new gin engine from the engine factory, see WithEngineFactory, which then
+// serves the requests that follow. Routers obtained before keep registering
+// on the previous engine. It returns httpx.ErrRoutesNotReplaceable for an
+// engine set with WithEngine.
+func (e *Engine) ReplaceRoutes(register func(httpx.Router)) (err error) {
+	if e.newEngine == nil {
+		return httpx.ErrRoutesNotReplaceable
+	}
+	next := e.newEngine()
+	if e.multipartMemory > 0 {
+		next.MaxMultipartMemory = e.multipartMemory
+	}
+	if e.trustProxies {
+		trustProxies(next, e.trustedProxies)
+	}
+	next.Use(adaptMiddlewares(e.middlewares, e.errHandler)...)
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("ginx: replace routes: %v", r)
+		}
+	}()
+	e.hooks.ReplaceRoutes(func() {
+		register(e.group(next, ""))
+	})
+	e.engine.Store(next)
+	return nil
+}
+
+// errorPagesHandler serves the error pages of route errors, which reach the
+// engine middleware only after errHandler responded, see
+// httpx.ServeErrorPage.
+func errorPagesHandler(pages fs.FS, errHandler ErrorHandler) ErrorHandler {
+	return func(ctx *gin.Context, err error) {
+		fc := acquireGinContext(ctx)
+		defer releaseGinContext(fc)
+		if _, status, _ := httpx.ParseError(err); httpx.ServeErrorPage(fc, pages, int(status)) {
+			ctx.Abort()
+			return
+		}
+		errHandler(ctx, err)
+	}
+}
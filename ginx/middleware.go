@@ -2,6 +2,7 @@ package ginx
 
 import (
 	"errors"
+	"reflect"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-sphere/httpx"
@@ -9,7 +10,8 @@ import (
 
 func adaptMiddleware(middleware httpx.Middleware, errHandler ErrorHandler) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		fc := newGinContext(ctx)
+		fc := acquireGinContext(ctx)
+		defer releaseGinContext(fc)
 		if err := middleware(fc); err != nil {
 			_ = ctx.Error(err)
 			if !ctx.IsAborted() {
@@ -27,6 +29,21 @@ func adaptMiddleware(middleware httpx.Middleware, errHandler ErrorHandler) gin.H
 	}
 }
 
+// ginMiddlewarePC identifies the middleware returned by AdaptGinMiddleware.
+var ginMiddlewarePC = reflect.ValueOf(AdaptGinMiddleware(nil)).Pointer()
+
+// splitMiddlewares splits middlewares after the last one returned by
+// AdaptGinMiddleware, into those run as gin handlers and those composed with
+// the route's handler.
+func splitMiddlewares(middlewares []httpx.Middleware) (native, composed []httpx.Middleware) {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		if reflect.ValueOf(middlewares[i]).Pointer() == ginMiddlewarePC {
+			return middlewares[:i+1], middlewares[i+1:]
+		}
+	}
+	return nil, middlewares
+}
+
 func adaptMiddlewares(middlewares []httpx.Middleware, errHandler ErrorHandler) []gin.HandlerFunc {
 	if len(middlewares) == 0 {
 		return nil
@@ -38,6 +55,34 @@ func adaptMiddlewares(middlewares []httpx.Middleware, errHandler ErrorHandler) [
 	return gMid
 }
 
+func cloneMiddlewares(middlewares []httpx.Middleware, extra ...httpx.Middleware) []httpx.Middleware {
+	out := make([]httpx.Middleware, len(middlewares)+len(extra))
+	copy(out, middlewares)
+	copy(out[len(middlewares):], extra)
+	return out
+}
+
+// AdaptErrorHandler adapts a framework-independent error handler, such as
+// httpx.ProblemErrorHandler, for WithErrorHandler.
+func AdaptErrorHandler(errHandler httpx.ErrorHandler) ErrorHandler {
+	return func(ctx *gin.Context, err error) {
+		fc := acquireGinContext(ctx)
+		defer releaseGinContext(fc)
+		errHandler(fc, err)
+		ctx.Abort()
+	}
+}
+
+// AdaptGinMiddleware adapts a gin middleware. A Router runs it, and the
+// router middleware before it, as gin handlers of the route rather than in the
+// chain composed with the handler, so that calling c.Next() there runs the
+// rest of the chain, as gin.Logger expects. Like engine middleware, those
+// handlers see the metadata of the route once Next returns.
+//
+// AdaptGinMiddleware is not inlined, so that its middleware keeps the code
+// pointer of ginMiddlewarePC.
+//
+//go:noinline
 func AdaptGinMiddleware(middleware gin.HandlerFunc) httpx.Middleware {
 	return func(ctx httpx.Context) error {
 		fc, ok := ctx.(*ginContext)
@@ -45,6 +90,9 @@ func AdaptGinMiddleware(middleware gin.HandlerFunc) httpx.Middleware {
 			return errors.New("AdaptGinMiddleware: gin context type error")
 		}
 		middleware(fc.ctx)
-		return nil
+		if fc.chainNext == nil || fc.ctx.IsAborted() {
+			return nil
+		}
+		return fc.Next()
 	}
 }
@@ -3,6 +3,7 @@ package ginx
 import (
 	"io/fs"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-sphere/httpx"
@@ -10,13 +11,18 @@ import (
 
 var _ httpx.Router = (*Router)(nil)
 
+// Router composes its middleware with each handler when the route is
+// registered, see httpx.Compose, so gin runs a single handler per route
+// besides the engine's middleware.
 type Router struct {
-	group      *gin.RouterGroup
-	errHandler ErrorHandler
+	group       *gin.RouterGroup
+	middlewares []httpx.Middleware
+	errHandler  ErrorHandler
+	hooks       *httpx.Hooks
 }
 
 func (r *Router) Use(m ...httpx.Middleware) {
-	r.group.Use(adaptMiddlewares(m, r.errHandler)...)
+	r.middlewares = append(r.middlewares, m...)
 }
 
 func (r *Router) BasePath() string {
@@ -34,65 +40,121 @@ func (r *Router) SupportsRouterFeature(feature httpx.RouterFeature) bool {
 
 func (r *Router) Group(prefix string, m ...httpx.Middleware) httpx.Router {
 	return &Router{
-		group:      r.group.Group(prefix, adaptMiddlewares(m, r.errHandler)...),
-		errHandler: r.errHandler,
+		group:       r.group.Group(prefix),
+		middlewares: cloneMiddlewares(r.middlewares, m...),
+		errHandler:  r.errHandler,
+		hooks:       r.hooks,
 	}
 }
 
-func (r *Router) Handle(method, path string, h httpx.Handler) {
-	r.group.Handle(method, path, r.toGinHandler(h))
+func (r *Router) Handle(method, path string, h httpx.Handler) *httpx.Route {
+	method = strings.ToUpper(method)
+	r.checkRoute(method, path)
+	route := httpx.NewRouteFor(r.routeInfo(method, path))
+	group, handler := r.routeGroup(route, h)
+	group.Handle(method, path, handler)
+	r.notifyRoute(method, path)
+	return route
 }
 
-func (r *Router) Any(path string, h httpx.Handler) {
-	r.group.Any(path, r.toGinHandler(h))
+func (r *Router) Any(path string, h httpx.Handler) *httpx.Route {
+	r.checkRoute(httpx.MethodAny, path)
+	route := httpx.NewRouteFor(r.routeInfo(httpx.MethodAny, path))
+	group, handler := r.routeGroup(route, h)
+	group.Any(path, handler)
+	r.notifyRoute(httpx.MethodAny, path)
+	return route
 }
 
 func (r *Router) Static(prefix, root string) {
-	r.group.Static(prefix, root)
+	r.checkRoute(http.MethodGet, prefix)
+	r.staticGroup().Static(prefix, root)
 }
 
 func (r *Router) StaticFS(prefix string, fs fs.FS) {
-	r.group.StaticFS(prefix, http.FS(fs))
+	r.checkRoute(http.MethodGet, prefix)
+	r.staticGroup().StaticFS(prefix, http.FS(fs))
 }
 
 // GET registers a new GET route for a path with matching handler.
-func (r *Router) GET(path string, h httpx.Handler) {
-	r.group.GET(path, r.toGinHandler(h))
+func (r *Router) GET(path string, h httpx.Handler) *httpx.Route {
+	return r.Handle(http.MethodGet, path, h)
 }
 
 // POST registers a new POST route for a path with matching handler.
-func (r *Router) POST(path string, h httpx.Handler) {
-	r.group.POST(path, r.toGinHandler(h))
+func (r *Router) POST(path string, h httpx.Handler) *httpx.Route {
+	return r.Handle(http.MethodPost, path, h)
 }
 
 // PUT registers a new PUT route for a path with matching handler.
-func (r *Router) PUT(path string, h httpx.Handler) {
-	r.group.PUT(path, r.toGinHandler(h))
+func (r *Router) PUT(path string, h httpx.Handler) *httpx.Route {
+	return r.Handle(http.MethodPut, path, h)
 }
 
 // DELETE registers a new DELETE route for a path with matching handler.
-func (r *Router) DELETE(path string, h httpx.Handler) {
-	r.group.DELETE(path, r.toGinHandler(h))
+func (r *Router) DELETE(path string, h httpx.Handler) *httpx.Route {
+	return r.Handle(http.MethodDelete, path, h)
 }
 
 // PATCH registers a new PATCH route for a path with matching handler.
-func (r *Router) PATCH(path string, h httpx.Handler) {
-	r.group.PATCH(path, r.toGinHandler(h))
+func (r *Router) PATCH(path string, h httpx.Handler) *httpx.Route {
+	return r.Handle(http.MethodPatch, path, h)
 }
 
 // HEAD registers a new HEAD route for a path with matching handler.
-func (r *Router) HEAD(path string, h httpx.Handler) {
-	r.group.HEAD(path, r.toGinHandler(h))
+func (r *Router) HEAD(path string, h httpx.Handler) *httpx.Route {
+	return r.Handle(http.MethodHead, path, h)
 }
 
 // OPTIONS registers a new OPTIONS route for a path with matching handler.
-func (r *Router) OPTIONS(path string, h httpx.Handler) {
-	r.group.OPTIONS(path, r.toGinHandler(h))
+func (r *Router) OPTIONS(path string, h httpx.Handler) *httpx.Route {
+	return r.Handle(http.MethodOptions, path, h)
 }
 
-func (r *Router) toGinHandler(h httpx.Handler) gin.HandlerFunc {
+func (r *Router) notifyRoute(method, path string) {
+	r.hooks.NotifyRoute(r.routeInfo(method, path))
+}
+
+// checkRoute panics when the engine is frozen, see httpx.Engine.Freeze.
+func (r *Router) checkRoute(method, path string) {
+	if err := r.hooks.CheckRoute(r.routeInfo(method, path)); err != nil {
+		panic(err)
+	}
+}
+
+func (r *Router) routeInfo(method, path string) httpx.RouteInfo {
+	return httpx.RouteInfo{
+		Method: method,
+		Path:   httpx.JoinPaths(r.group.BasePath(), path),
+	}
+}
+
+// staticGroup returns a group running the router's middleware as gin
+// handlers, for file handlers that cannot be composed.
+func (r *Router) staticGroup() *gin.RouterGroup {
+	if len(r.middlewares) == 0 {
+		return r.group
+	}
+	return r.group.Group("", adaptMiddlewares(r.middlewares, r.errHandler)...)
+}
+
+// routeGroup returns the group to register the gin handler of route on. The
+// router middleware up to the last one of AdaptGinMiddleware runs as gin
+// handlers of the group, so that gin middleware wraps the rest of the chain.
+func (r *Router) routeGroup(route *httpx.Route, h httpx.Handler) (*gin.RouterGroup, gin.HandlerFunc) {
+	native, composed := splitMiddlewares(r.middlewares)
+	group := r.group
+	if len(native) > 0 {
+		group = group.Group("", adaptMiddlewares(native, r.errHandler)...)
+	}
+	return group, r.toGinHandler(route, h, composed)
+}
+
+func (r *Router) toGinHandler(route *httpx.Route, h httpx.Handler, middlewares []httpx.Middleware) gin.HandlerFunc {
+	h = route.Compose(h, middlewares...)
 	return func(gc *gin.Context) {
-		ctx := newGinContext(gc)
+		ctx := acquireGinContext(gc)
+		defer releaseGinContext(ctx)
 		if err := h(ctx); err != nil {
 			_ = gc.Error(err)
 			r.errHandler(gc, err)
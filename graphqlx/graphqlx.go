@@ -0,0 +1,53 @@
+// Package graphqlx serves GraphQL handlers, such as the handler.Server of
+// gqlgen, on httpx routes, so GraphQL services run on any adapter:
+//
+//	srv := handler.New(generated.NewExecutableSchema(resolvers))
+//	srv.AddTransport(transport.POST{})
+//	srv.AddTransport(transport.GET{})
+//	srv.AddTransport(transport.MultipartForm{})
+//	srv.AddTransport(transport.Websocket{})
+//	graphqlx.Mount(r, "/graphql", srv)
+//
+// The handler parses the requests itself, so every transport it supports is
+// served: queries over GET, JSON and multipart upload requests over POST, and
+// subscriptions over websockets where the context can hijack the connection.
+package graphqlx
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-sphere/httpx"
+)
+
+// ErrWebSocketUnsupported reports a websocket subscription request on a
+// context without httpx.Hijacker, such as those of fiberx and hertzx.
+var ErrWebSocketUnsupported = errors.New("graphqlx: websocket subscriptions not supported by this adapter")
+
+// Mount registers schemaHandler on r for GET and POST requests to path.
+func Mount(r httpx.Router, path string, schemaHandler http.Handler) {
+	h := Handler(schemaHandler)
+	r.GET(path, h)
+	r.POST(path, h)
+}
+
+// Handler adapts schemaHandler into an httpx.Handler with
+// httpx.FromHTTPHandler. Websocket upgrade requests fail with status 501
+// wrapping ErrWebSocketUnsupported on contexts that cannot hijack the
+// connection, instead of reaching a handler that would fail to upgrade.
+func Handler(schemaHandler http.Handler) httpx.Handler {
+	h := httpx.FromHTTPHandler(schemaHandler)
+	return func(ctx httpx.Context) error {
+		if isWebSocket(ctx) {
+			if _, ok := httpx.AsHijacker(ctx); !ok {
+				return httpx.WithStatus(http.StatusNotImplemented, ErrWebSocketUnsupported)
+			}
+		}
+		return h(ctx)
+	}
+}
+
+func isWebSocket(ctx httpx.Context) bool {
+	return strings.EqualFold(ctx.Header("Upgrade"), "websocket")
+}
@@ -0,0 +1,81 @@
+package httpx
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// GRPCHealthCheckPath is the path of the Check method of the gRPC health
+// service, grpc.health.v1.Health.
+const GRPCHealthCheckPath = "/grpc.health.v1.Health/Check"
+
+// Serving statuses of the gRPC health protocol.
+const (
+	GRPCServing        = "SERVING"
+	GRPCNotServing     = "NOT_SERVING"
+	GRPCServiceUnknown = "SERVICE_UNKNOWN"
+)
+
+// GRPCHealthResponse is the JSON form of grpc.health.v1.HealthCheckResponse.
+type GRPCHealthResponse struct {
+	Status string `json:"status"`
+}
+
+// ServingStatus returns the gRPC serving status of service: the readiness of
+// the whole server for the empty service, and the result of the readiness
+// check registered under the name service otherwise. It reports
+// GRPCServiceUnknown for services without a check, and GRPCNotServing once
+// shutdown has begun.
+func (h *HealthChecker) ServingStatus(ctx context.Context, service string) string {
+	checks := h.readiness
+	if service != "" {
+		h.mu.RLock()
+		check, ok := h.readiness[service]
+		h.mu.RUnlock()
+		if !ok {
+			return GRPCServiceUnknown
+		}
+		checks = map[string]HealthCheck{service: check}
+	}
+	if h.shuttingDown.Load() || h.run(ctx, checks).Status != HealthStatusOK {
+		return GRPCNotServing
+	}
+	return GRPCServing
+}
+
+// MountGRPC registers GRPCHealthCheckPath on r, serving the Check method of
+// the gRPC health protocol as transcoded to JSON, so fleets mixing gRPC and
+// HTTP services probe both the same way. The request names the service in a
+// JSON body, {"service": "db"}, or the service query parameter; the empty
+// service stands for the whole server. Responses are GRPCHealthResponse
+// bodies with status 200, or 404 for unknown services, as gRPC transcoding
+// maps the NOT_FOUND error of the protocol. The streaming Watch method is not
+// served.
+func (h *HealthChecker) MountGRPC(r Router) {
+	r.POST(GRPCHealthCheckPath, h.serveGRPC)
+	r.GET(GRPCHealthCheckPath, h.serveGRPC)
+}
+
+func (h *HealthChecker) serveGRPC(ctx Context) error {
+	var req struct {
+		Service string `json:"service"`
+	}
+	req.Service = ctx.Query("service")
+	body, err := ctx.BodyRaw()
+	if err != nil {
+		return err
+	}
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &req); err != nil {
+			return BadRequestError(err)
+		}
+	}
+	status := h.ServingStatus(ctx.Context(), req.Service)
+	code := http.StatusOK
+	if status == GRPCServiceUnknown {
+		code = http.StatusNotFound
+	}
+	ctx.SetHeader("Cache-Control", "no-store")
+	return ctx.JSON(code, GRPCHealthResponse{Status: status})
+}
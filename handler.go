@@ -0,0 +1,168 @@
+package httpx
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// ErrorHandler renders an error returned by the handler chain.
+type ErrorHandler func(ctx Context, err error)
+
+// HTTPHandlerOption configures ToHTTPHandler.
+type HTTPHandlerOption func(*httpHandlerConfig)
+
+type httpHandlerConfig struct {
+	middlewares     []Middleware
+	errHandler      ErrorHandler
+	route           string
+	multipartMemory int64
+}
+
+// WithHTTPMiddleware runs middleware before the handler, in order.
+func WithHTTPMiddleware(middleware ...Middleware) HTTPHandlerOption {
+	return func(conf *httpHandlerConfig) {
+		conf.middlewares = append(conf.middlewares, middleware...)
+	}
+}
+
+// WithHTTPErrorHandler sets how errors returned by the chain are rendered.
+// The default responds with status 500 and a JSON {"error": ...} body, like
+// the framework adapters.
+func WithHTTPErrorHandler(errHandler ErrorHandler) HTTPHandlerOption {
+	return func(conf *httpHandlerConfig) {
+		conf.errHandler = errHandler
+	}
+}
+
+// WithHTTPMultipartMemory sets how many bytes of a multipart form
+// MultipartForm and FormFile keep in memory; larger file parts are written
+// to temporary files. The default is 32 MiB, like gin and echo.
+func WithHTTPMultipartMemory(bytes int64) HTTPHandlerOption {
+	return func(conf *httpHandlerConfig) {
+		conf.multipartMemory = bytes
+	}
+}
+
+// WithHTTPRoute declares that the handler is mounted on http.ServeMux at
+// ServeMuxPattern(path), where path uses httpx route syntax such as
+// "/users/:id" or "/files/*filepath". FullPath then reports path, and Param
+// resolves its parameters; wildcard values keep their leading slash as with
+// the framework adapters.
+func WithHTTPRoute(path string) HTTPHandlerOption {
+	return func(conf *httpHandlerConfig) {
+		conf.route = path
+	}
+}
+
+// ServeMuxPattern converts a path in httpx route syntax into an
+// http.ServeMux pattern: ":name" segments become "{name}", "*name" segments
+// become "{name...}", and a trailing slash matches only itself.
+func ServeMuxPattern(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		switch {
+		case strings.HasPrefix(segment, ":"):
+			segments[i] = "{" + segment[1:] + "}"
+		case strings.HasPrefix(segment, "*"):
+			segments[i] = "{" + segment[1:] + "...}"
+		}
+	}
+	pattern := strings.Join(segments, "/")
+	if strings.HasSuffix(pattern, "/") {
+		pattern += "{$}"
+	}
+	return pattern
+}
+
+// routeParams resolves the parameters of an httpx route from the wildcards
+// matched by http.ServeMux.
+func routeParams(path string, r *http.Request) map[string]string {
+	var params map[string]string
+	for segment := range strings.SplitSeq(path, "/") {
+		if len(segment) < 2 || (segment[0] != ':' && segment[0] != '*') {
+			continue
+		}
+		if params == nil {
+			params = make(map[string]string)
+		}
+		name := segment[1:]
+		if segment[0] == '*' {
+			params[name] = "/" + r.PathValue(name)
+		} else {
+			params[name] = r.PathValue(name)
+		}
+	}
+	return params
+}
+
+func defaultErrorHandler(ctx Context, err error) {
+	err = LocalizeError(ctx, err)
+	var ve *ValidationError
+	if errors.As(err, &ve) {
+		_ = ctx.JSON(http.StatusUnprocessableEntity, ve)
+		return
+	}
+	_ = ctx.JSON(http.StatusInternalServerError, H{"error": err.Error()})
+}
+
+// ToHTTPHandler adapts h into an http.Handler backed by a built-in Context,
+// so a handler can be served by http.ServeMux, httptest or any net/http
+// compatible server without picking a framework.
+//
+// When served by http.ServeMux, FullPath reports the matched pattern without
+// its method and host, and Param reads the pattern's wildcards, e.g. "id" in
+// "GET /users/{id}". ClientIP reports the remote address of the connection.
+func ToHTTPHandler(h Handler, opts ...HTTPHandlerOption) http.Handler {
+	conf := httpHandlerConfig{errHandler: defaultErrorHandler}
+	for _, opt := range opts {
+		opt(&conf)
+	}
+	handlers := make([]Handler, 0, len(conf.middlewares)+1)
+	for _, m := range conf.middlewares {
+		handlers = append(handlers, Handler(m))
+	}
+	handlers = append(handlers, h)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := newHTTPContext(w, r, handlers)
+		if conf.route != "" {
+			ctx.pattern, ctx.params = conf.route, routeParams(conf.route, r)
+		}
+		if conf.multipartMemory > 0 {
+			ctx.multipartMemory = conf.multipartMemory
+		}
+		if err := ctx.Next(); err != nil {
+			conf.errHandler(ctx, err)
+		}
+		ctx.writer.writeHeaderNow()
+	})
+}
+
+// FromHTTPHandler adapts h into a Handler, the reverse of ToHTTPHandler, so
+// net/http handlers such as GraphQL servers or file servers can be routed on
+// any adapter.
+//
+// On contexts implementing HTTPInterop h serves the real request and writer,
+// so flushing and hijacking, e.g. for websockets, work through
+// http.ResponseController. Other contexts, such as those of fiberx and
+// hertzx, serve h an emulated request and stream its response through the
+// context, as Proxy does; h cannot hijack the connection there.
+func FromHTTPHandler(h http.Handler) Handler {
+	return func(ctx Context) error {
+		if hi, ok := AsHTTPInterop(ctx); ok {
+			h.ServeHTTP(proxyWriter{hi.HTTPResponseWriter()}, hi.HTTPRequest())
+			return nil
+		}
+		var err error
+		return serveHandlerEmulated(ctx, h, nil, &err)
+	}
+}
+
+// NewContext returns the built-in Context of ToHTTPHandler for a request
+// handled outside of a handler chain, such as in tests; Next is a no-op. The
+// status set by Status is sent with the first body write or on Flush, see
+// AsFlusher.
+func NewContext(w http.ResponseWriter, r *http.Request) Context {
+	return newHTTPContext(w, r, nil)
+}
@@ -0,0 +1,47 @@
+package httpx
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestServeMuxPattern(t *testing.T) {
+	tests := map[string]string{
+		"/":                   "/{$}",
+		"/users":              "/users",
+		"/users/:id":          "/users/{id}",
+		"/users/:id/posts/":   "/users/{id}/posts/{$}",
+		"/files/*filepath":    "/files/{filepath...}",
+		"/api/:version/*rest": "/api/{version}/{rest...}",
+	}
+	for path, want := range tests {
+		if got := ServeMuxPattern(path); got != want {
+			t.Errorf("ServeMuxPattern(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestToHTTPHandlerFlushAndHijack(t *testing.T) {
+	var flushErr, hijackErr error
+	h := ToHTTPHandler(func(ctx Context) error {
+		interop, _ := AsHTTPInterop(ctx)
+		_, _ = io.WriteString(interop.HTTPResponseWriter(), "chunk")
+		flusher, _ := AsFlusher(ctx)
+		flushErr = flusher.Flush()
+		hijacker, _ := AsHijacker(ctx)
+		_, _, hijackErr = hijacker.Hijack()
+		return nil
+	})
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if flushErr != nil || !rec.Flushed || rec.Body.String() != "chunk" {
+		t.Fatalf("Flush() = %v, flushed %v, body %q", flushErr, rec.Flushed, rec.Body)
+	}
+	if !errors.Is(hijackErr, http.ErrNotSupported) {
+		t.Fatalf("Hijack() error = %v, want ErrNotSupported", hijackErr)
+	}
+}
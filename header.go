@@ -0,0 +1,77 @@
+package httpx
+
+import (
+	"net/http"
+	"net/textproto"
+	"strings"
+)
+
+// The helpers below implement the header methods of RequestInfo for contexts
+// backed by net/http, with the semantics fasthttp gives fiberx and hertzx:
+//
+//   - keys are case-insensitive, and Headers returns canonical keys
+//   - a header sent on several lines has one value per line, in order; values
+//     are never split on commas
+//   - several Cookie lines are joined into one value with "; ", as HTTP/2
+//     requires and fasthttp does
+//   - Host is read with Header("Host") and HeaderValues("Host"), but is not
+//     listed by Headers and AllHeaders, as net/http keeps it apart
+
+// RequestHeader returns the first value of the header key of r.
+func RequestHeader(r *http.Request, key string) string {
+	key = textproto.CanonicalMIMEHeaderKey(key)
+	switch key {
+	case "Host":
+		return r.Host
+	case "Cookie":
+		if values := r.Header[key]; len(values) > 1 {
+			return strings.Join(values, "; ")
+		}
+	}
+	return r.Header.Get(key)
+}
+
+// RequestHeaderValues returns a copy of the values of the header key of r,
+// or nil when it was not sent.
+func RequestHeaderValues(r *http.Request, key string) []string {
+	key = textproto.CanonicalMIMEHeaderKey(key)
+	if key == "Host" {
+		if r.Host == "" {
+			return nil
+		}
+		return []string{r.Host}
+	}
+	values := r.Header[key]
+	if len(values) == 0 {
+		return nil
+	}
+	if key == "Cookie" {
+		return joinCookieValues(values)
+	}
+	return append([]string(nil), values...)
+}
+
+// RequestHeaders returns a copy of header with canonical keys, or nil when
+// it is empty.
+func RequestHeaders(header http.Header) map[string][]string {
+	if len(header) == 0 {
+		return nil
+	}
+	out := make(map[string][]string, len(header))
+	for k, v := range header {
+		ck := textproto.CanonicalMIMEHeaderKey(k)
+		if ck == "Cookie" {
+			out[ck] = joinCookieValues(v)
+			continue
+		}
+		out[ck] = append(out[ck], v...)
+	}
+	return out
+}
+
+func joinCookieValues(values []string) []string {
+	if len(values) <= 1 {
+		return append([]string(nil), values...)
+	}
+	return []string{strings.Join(values, "; ")}
+}
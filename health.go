@@ -0,0 +1,172 @@
+package httpx
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// HealthCheck reports the health of a single dependency. A nil error means healthy.
+type HealthCheck func(ctx context.Context) error
+
+// HealthOptions configures the endpoints registered by Health.
+type HealthOptions struct {
+	// LivenessPath is the liveness endpoint path. Defaults to "/healthz".
+	LivenessPath string
+	// ReadinessPath is the readiness endpoint path. Defaults to "/readyz".
+	ReadinessPath string
+	// Timeout bounds each check. Defaults to 5 seconds.
+	Timeout time.Duration
+	// Engine, when set, marks the service not ready as soon as the engine
+	// starts stopping, so load balancers drain traffic during shutdown.
+	Engine Engine
+}
+
+// HealthStatus is the aggregated JSON body served by the health endpoints.
+type HealthStatus struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks,omitempty"`
+}
+
+// CheckResult is the outcome of a single named check.
+type CheckResult struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+const (
+	HealthStatusOK           = "ok"
+	HealthStatusError        = "error"
+	HealthStatusShuttingDown = "shutting_down"
+)
+
+// HealthChecker aggregates liveness and readiness checks.
+//
+// Liveness checks should only fail when the process itself is broken, since
+// orchestrators restart unhealthy processes. Dependency checks such as
+// databases belong in readiness checks registered with AddCheck.
+type HealthChecker struct {
+	timeout      time.Duration
+	mu           sync.RWMutex
+	liveness     map[string]HealthCheck
+	readiness    map[string]HealthCheck
+	shuttingDown atomic.Bool
+}
+
+// Health registers liveness and readiness endpoints on r and returns the
+// checker used to add checks. Both endpoints respond with a HealthStatus
+// body, using 200 when every check passes and 503 otherwise.
+func Health(r Router, opts HealthOptions) *HealthChecker {
+	if opts.LivenessPath == "" {
+		opts.LivenessPath = "/healthz"
+	}
+	if opts.ReadinessPath == "" {
+		opts.ReadinessPath = "/readyz"
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 5 * time.Second
+	}
+	h := &HealthChecker{
+		timeout:   opts.Timeout,
+		liveness:  make(map[string]HealthCheck),
+		readiness: make(map[string]HealthCheck),
+	}
+	if opts.Engine != nil {
+		opts.Engine.OnStopping(h.SetShuttingDown)
+	}
+	r.GET(opts.LivenessPath, h.serveLiveness)
+	r.GET(opts.ReadinessPath, h.serveReadiness)
+	return h
+}
+
+// AddCheck registers a readiness check under name, replacing any previous one.
+func (h *HealthChecker) AddCheck(name string, check HealthCheck) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.readiness[name] = check
+}
+
+// AddLivenessCheck registers a liveness check under name, replacing any previous one.
+func (h *HealthChecker) AddLivenessCheck(name string, check HealthCheck) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.liveness[name] = check
+}
+
+// SetShuttingDown marks the service as not ready. It is called automatically
+// when HealthOptions.Engine starts stopping.
+func (h *HealthChecker) SetShuttingDown() {
+	h.shuttingDown.Store(true)
+}
+
+// Liveness runs the liveness checks.
+func (h *HealthChecker) Liveness(ctx context.Context) HealthStatus {
+	return h.run(ctx, h.liveness)
+}
+
+// Readiness runs the readiness checks, reporting HealthStatusShuttingDown
+// without running them once shutdown has begun.
+func (h *HealthChecker) Readiness(ctx context.Context) HealthStatus {
+	if h.shuttingDown.Load() {
+		return HealthStatus{Status: HealthStatusShuttingDown}
+	}
+	return h.run(ctx, h.readiness)
+}
+
+func (h *HealthChecker) serveLiveness(ctx Context) error {
+	return writeHealth(ctx, h.Liveness(ctx.Context()))
+}
+
+func (h *HealthChecker) serveReadiness(ctx Context) error {
+	return writeHealth(ctx, h.Readiness(ctx.Context()))
+}
+
+// run executes checks concurrently, each bounded by the checker timeout.
+func (h *HealthChecker) run(ctx context.Context, checks map[string]HealthCheck) HealthStatus {
+	h.mu.RLock()
+	snapshot := make(map[string]HealthCheck, len(checks))
+	for name, check := range checks {
+		snapshot[name] = check
+	}
+	h.mu.RUnlock()
+
+	status := HealthStatus{Status: HealthStatusOK}
+	if len(snapshot) == 0 {
+		return status
+	}
+	status.Checks = make(map[string]CheckResult, len(snapshot))
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for name, check := range snapshot {
+		wg.Go(func() {
+			checkCtx, cancel := context.WithTimeout(ctx, h.timeout)
+			defer cancel()
+			result := CheckResult{Status: HealthStatusOK}
+			if err := check(checkCtx); err != nil {
+				result = CheckResult{Status: HealthStatusError, Error: err.Error()}
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			status.Checks[name] = result
+			if result.Status != HealthStatusOK {
+				status.Status = HealthStatusError
+			}
+		})
+	}
+	wg.Wait()
+	return status
+}
+
+func writeHealth(ctx Context, status HealthStatus) error {
+	code := http.StatusOK
+	if status.Status != HealthStatusOK {
+		code = http.StatusServiceUnavailable
+	}
+	ctx.SetHeader("Cache-Control", "no-store")
+	return ctx.JSON(code, status)
+}
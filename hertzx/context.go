@@ -5,28 +5,59 @@ import (
 	"context"
 	"errors"
 	"io"
+	"iter"
 	"mime/multipart"
 	"net/http"
 	"net/textproto"
+	"net/url"
+	"strings"
+	"sync"
 
 	"github.com/cloudwego/hertz/pkg/app"
 	"github.com/cloudwego/hertz/pkg/protocol"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
 	"github.com/go-sphere/httpx"
 )
 
-var _ httpx.Context = (*hertzContext)(nil)
+var (
+	_ httpx.Context           = (*hertzContext)(nil)
+	_ httpx.ChainContext      = (*hertzContext)(nil)
+	_ httpx.RequestBodySetter = (*hertzContext)(nil)
+	_ httpx.Aborter           = (*hertzContext)(nil)
+	_ httpx.ResponseSizer     = (*hertzContext)(nil)
+)
 
 type hertzContext struct {
 	ctx        *app.RequestContext
 	baseCtx    context.Context
 	nextCalled bool
+	chainNext  httpx.Handler
+	aborted    bool
+	state      *httpx.RequestState
 }
 
-func newHertzContext(ctx context.Context, rc *app.RequestContext) *hertzContext {
-	return &hertzContext{
-		ctx:     rc,
-		baseCtx: ctx,
+// hertzContexts pools the contexts passed to handlers and middleware. A
+// context is released when the handler or middleware it was passed to
+// returns, so it must not be kept, nor used from other goroutines, after
+// that; httpx.Detach keeps request values for work that outlives it.
+var hertzContexts = sync.Pool{New: func() any { return new(hertzContext) }}
+
+func acquireHertzContext(ctx context.Context, rc *app.RequestContext) *hertzContext {
+	c := hertzContexts.Get().(*hertzContext)
+	c.ctx = rc
+	c.baseCtx = ctx
+	return c
+}
+
+// releaseHertzContext closes the request state of c, so that a context.Context
+// kept from it no longer resolves request state, clears c and returns it to
+// the pool.
+func releaseHertzContext(c *hertzContext) {
+	if c.state != nil {
+		c.state.Close()
 	}
+	*c = hertzContext{}
+	hertzContexts.Put(c)
 }
 
 // Request (httpx.Request)
@@ -84,19 +115,29 @@ func (c *hertzContext) RawQuery() string {
 }
 
 func (c *hertzContext) Header(key string) string {
+	if strings.EqualFold(key, consts.HeaderCookie) {
+		// hertz returns the first Cookie line until cookies are parsed.
+		values := c.HeaderValues(key)
+		if len(values) == 0 {
+			return ""
+		}
+		return values[0]
+	}
 	return string(c.ctx.GetHeader(key))
 }
 
+func (c *hertzContext) HeaderValues(key string) []string {
+	return headerValues(key, c.ctx.Request.Header.PeekAll(key))
+}
+
 func (c *hertzContext) Headers() map[string][]string {
-	header := &c.ctx.Request.Header
-	if header.Len() == 0 {
-		return nil
+	var out map[string][]string
+	for key, value := range c.AllHeaders() {
+		if out == nil {
+			out = make(map[string][]string)
+		}
+		out[key] = append(out[key], value)
 	}
-	out := make(map[string][]string, header.Len())
-	header.VisitAll(func(k, v []byte) {
-		key := textproto.CanonicalMIMEHeaderKey(string(k))
-		out[key] = append(out[key], string(v))
-	})
 	return out
 }
 
@@ -123,26 +164,157 @@ func (c *hertzContext) Cookies() map[string]string {
 	return out
 }
 
+// hertz only offers VisitAll callbacks, which cannot be stopped, so the
+// iterators below ignore the remaining entries once yield returns false.
+
+func (c *hertzContext) AllQueries() iter.Seq2[string, string] {
+	return func(yield func(string, string) bool) {
+		done := false
+		c.ctx.QueryArgs().VisitAll(func(key, value []byte) {
+			done = done || !yield(string(key), string(value))
+		})
+	}
+}
+
+// AllHeaders yields the headers like net/http-based contexts: without Host,
+// and with the Cookie lines joined into one value.
+func (c *hertzContext) AllHeaders() iter.Seq2[string, string] {
+	return func(yield func(string, string) bool) {
+		done := false
+		var cookies []string
+		c.ctx.Request.Header.VisitAll(func(key, value []byte) {
+			if done {
+				return
+			}
+			switch k := textproto.CanonicalMIMEHeaderKey(string(key)); k {
+			case consts.HeaderHost:
+			case consts.HeaderCookie:
+				cookies = append(cookies, string(value))
+			default:
+				done = !yield(k, string(value))
+			}
+		})
+		if !done && len(cookies) > 0 {
+			yield(consts.HeaderCookie, strings.Join(cookies, "; "))
+		}
+	}
+}
+
+func (c *hertzContext) AllCookies() iter.Seq2[string, string] {
+	return func(yield func(string, string) bool) {
+		done := false
+		c.ctx.Request.Header.VisitAllCookie(func(key, value []byte) {
+			done = done || !yield(string(key), string(value))
+		})
+	}
+}
+
+func (c *hertzContext) Scheme() string {
+	return httpx.ForwardedScheme(c.Header, string(c.ctx.Request.URI().Scheme()) == "https")
+}
+
+func (c *hertzContext) Host() string {
+	return httpx.ForwardedHost(c.Header, string(c.ctx.Request.Host()))
+}
+
+// Proto reports "HTTP/1.1" for requests built in-process, such as by hertz's
+// ut package, which carry no protocol.
+func (c *hertzContext) Proto() string {
+	if proto := c.ctx.Request.Header.GetProtocol(); proto != "" {
+		return proto
+	}
+	return consts.HTTP11
+}
+
+// ContentLength returns -1 for chunked requests and requests without a
+// declared length, like net/http.
+func (c *hertzContext) ContentLength() int64 {
+	if n := c.ctx.Request.Header.ContentLength(); n >= 0 {
+		return int64(n)
+	}
+	return -1
+}
+
+func (c *hertzContext) UserAgent() string {
+	return string(c.ctx.Request.Header.UserAgent())
+}
+
+func (c *hertzContext) Referer() string {
+	return c.Header("Referer")
+}
+
+func (c *hertzContext) URL() *url.URL {
+	return httpx.RequestURL(c.Scheme(), c.Host(), string(c.ctx.Request.Header.RequestURI()))
+}
+
 func (c *hertzContext) FormValue(key string) string {
 	return string(c.ctx.FormValue(key))
 }
 
 func (c *hertzContext) MultipartForm() (*multipart.Form, error) {
-	return c.ctx.MultipartForm()
+	maxMemory, ok := c.ctx.Value(multipartMemoryKey).(int64)
+	if !ok {
+		return c.ctx.MultipartForm()
+	}
+	if form, ok := c.ctx.Value(multipartFormKey).(*multipart.Form); ok {
+		return form, nil
+	}
+	form, err := c.readMultipartForm(maxMemory)
+	if err != nil {
+		return nil, err
+	}
+	c.ctx.Set(multipartFormKey, form)
+	return form, nil
+}
+
+// readMultipartForm parses the form with the limit set by
+// WithMultipartMemoryLimit, which hertz has no setting for.
+func (c *hertzContext) readMultipartForm(maxMemory int64) (*multipart.Form, error) {
+	req := &c.ctx.Request
+	boundary := string(req.Header.MultipartFormBoundary())
+	if boundary == "" {
+		return nil, http.ErrNotMultipart
+	}
+	var body io.Reader
+	if req.IsBodyStream() {
+		body = req.BodyStream()
+	} else {
+		body = bytes.NewReader(req.Body())
+	}
+	return multipart.NewReader(body, boundary).ReadForm(maxMemory)
 }
 
 func (c *hertzContext) FormFile(name string) (*multipart.FileHeader, error) {
-	return c.ctx.FormFile(name)
+	if _, ok := c.ctx.Value(multipartMemoryKey).(int64); !ok {
+		return c.ctx.FormFile(name)
+	}
+	form, err := c.MultipartForm()
+	if err != nil {
+		return nil, err
+	}
+	if files := form.File[name]; len(files) > 0 {
+		return files[0], nil
+	}
+	return nil, http.ErrMissingFile
 }
 
+// BodyRaw copies the body, as hertz reuses the request buffer after the
+// handler returns.
 func (c *hertzContext) BodyRaw() ([]byte, error) {
+	body, err := c.ctx.Request.BodyE()
+	if err != nil {
+		return nil, err
+	}
+	return bytes.Clone(body), nil
+}
+
+func (c *hertzContext) BodyRawUnsafe() ([]byte, error) {
 	return c.ctx.Request.BodyE()
 }
 
+// BodyReader reads from the buffered body. A streamed request body is
+// drained into the buffer first, so the body can be read again.
 func (c *hertzContext) BodyReader() io.ReadCloser {
-	if stream := c.ctx.Request.BodyStream(); stream != nil {
-		return httpx.NewReadCloser(stream, c.ctx.Request.CloseBodyStream)
-	}
 	body := c.ctx.Request.Body()
 	if len(body) == 0 {
 		return http.NoBody
@@ -150,26 +322,63 @@ func (c *hertzContext) BodyReader() io.ReadCloser {
 	return httpx.NewReadCloser(bytes.NewReader(body), nil)
 }
 
+func (c *hertzContext) SetRequestBody(body []byte) {
+	req := &c.ctx.Request
+	req.SetBody(body)
+	req.Header.Del("Content-Encoding")
+	req.Header.SetContentLength(len(body))
+}
+
 // Binder (httpx.Binder)
 
 func (c *hertzContext) BindJSON(dst any) error {
-	return c.ctx.BindJSON(dst)
+	if err := c.ctx.BindJSON(dst); err != nil {
+		return httpx.ToValidationError(err)
+	}
+	// hertz validates vd tags only; check binding tags like gin.
+	return httpx.ValidateRequired(dst)
 }
 
 func (c *hertzContext) BindQuery(dst any) error {
-	return c.ctx.BindQuery(dst)
+	return httpx.BindValues(dst, "query", c.Queries())
 }
 
 func (c *hertzContext) BindForm(dst any) error {
-	return c.ctx.BindForm(dst)
+	form, err := c.formValues()
+	if err != nil {
+		return err
+	}
+	return httpx.BindValues(dst, "form", form)
+}
+
+// formValues collects urlencoded and multipart form fields.
+func (c *hertzContext) formValues() (map[string][]string, error) {
+	values := make(map[string][]string)
+	if bytes.HasPrefix(c.ctx.Request.Header.ContentType(), []byte(consts.MIMEMultipartPOSTForm)) {
+		form, err := c.MultipartForm()
+		if err != nil {
+			return nil, err
+		}
+		for key, vals := range form.Value {
+			values[key] = append(values[key], vals...)
+		}
+		return values, nil
+	}
+	c.ctx.PostArgs().VisitAll(func(key, value []byte) {
+		values[string(key)] = append(values[string(key)], string(value))
+	})
+	return values, nil
 }
 
 func (c *hertzContext) BindURI(dst any) error {
+	if httpx.UsesBindTypes(dst) {
+		return httpx.BindParams(dst, c.Params())
+	}
 	return bindURIWithForm(dst, c.ctx)
 }
 
 func (c *hertzContext) BindHeader(dst any) error {
-	return c.ctx.BindHeader(dst)
+	return httpx.BindHeaders(dst, c.Headers())
 }
 
 // Responder (httpx.Responder)
@@ -179,6 +388,13 @@ func (c *hertzContext) Status(code int) {
 }
 
 func (c *hertzContext) JSON(code int, v any) error {
+	if opts, ok := httpx.JSONOptionsKey.Get(c); ok {
+		body, err := opts.Marshal(v)
+		if err != nil {
+			return err
+		}
+		return c.Bytes(code, body, "application/json; charset=utf-8")
+	}
 	c.ctx.JSON(code, v)
 	return nil
 }
@@ -250,15 +466,52 @@ func (c *hertzContext) Get(key string) (any, bool) {
 // Context (context.Context accessor + Next)
 
 func (c *hertzContext) Context() context.Context {
-	return c.baseCtx
+	if c.state == nil {
+		c.state = httpx.NewRequestState(c)
+	}
+	return httpx.WithState(c.baseCtx, c.state)
 }
 
 func (c *hertzContext) SetContext(ctx context.Context) {
 	c.baseCtx = ctx
 }
 
+// Aborter (httpx.Aborter)
+
+func (c *hertzContext) Abort() {
+	c.aborted = true
+	c.Set(httpx.AbortedKey, true)
+}
+
+func (c *hertzContext) AbortWithStatus(code int) error {
+	c.Abort()
+	return c.NoContent(code)
+}
+
+func (c *hertzContext) AbortWithStatusJSON(code int, obj any) error {
+	c.Abort()
+	return c.JSON(code, obj)
+}
+
+func (c *hertzContext) IsAborted() bool {
+	_, ok := c.Get(httpx.AbortedKey)
+	return ok
+}
+
+// SetNext sets the rest of a chain composed by httpx.Compose.
+func (c *hertzContext) SetNext(next httpx.Handler) {
+	c.chainNext = next
+}
+
 func (c *hertzContext) Next() error {
+	if c.aborted {
+		return nil
+	}
 	c.nextCalled = true
+	if next := c.chainNext; next != nil {
+		c.chainNext = nil
+		return next(c)
+	}
 	before := len(c.ctx.Errors)
 	c.ctx.Next(c.baseCtx)
 
@@ -291,10 +544,28 @@ func (c *hertzContext) StatusCode() int {
 	return c.ctx.Response.StatusCode()
 }
 
+func (c *hertzContext) BodySize() int {
+	if c.ctx.Response.IsBodyStream() {
+		return max(c.ctx.Response.Header.ContentLength(), 0)
+	}
+	return len(c.ctx.Response.BodyBytes())
+}
+
 func (c *hertzContext) NativeContext() any {
 	return c.ctx
 }
 
+// Unwrap returns the *app.RequestContext behind ctx, reporting false for
+// contexts of other adapters. Hertz pools its request contexts, so the
+// returned value is only valid until the handler chain returns; use
+// RequestContext.Copy before handing it to background goroutines.
+func Unwrap(ctx httpx.Context) (*app.RequestContext, bool) {
+	if c, ok := ctx.(*hertzContext); ok {
+		return c.ctx, true
+	}
+	return httpx.AsNativeContext[*app.RequestContext](ctx)
+}
+
 func mapSameSite(mode http.SameSite) protocol.CookieSameSite {
 	switch mode {
 	case http.SameSiteStrictMode:
@@ -307,3 +578,27 @@ func mapSameSite(mode http.SameSite) protocol.CookieSameSite {
 		return protocol.CookieSameSiteLaxMode
 	}
 }
+
+// headerValues converts the values hertz's PeekAll returns for key to
+// those of net/http-based contexts, see httpx.RequestHeaderValues.
+func headerValues(key string, raw [][]byte) []string {
+	if len(raw) == 0 {
+		return nil
+	}
+	values := make([]string, 0, len(raw))
+	for _, value := range raw {
+		values = append(values, string(value))
+	}
+	switch textproto.CanonicalMIMEHeaderKey(key) {
+	case consts.HeaderContentLength:
+		// PeekAll reports an empty length for requests without one.
+		if values[0] == "" {
+			return nil
+		}
+	case consts.HeaderCookie:
+		if len(values) > 1 {
+			return []string{strings.Join(values, "; ")}
+		}
+	}
+	return values
+}
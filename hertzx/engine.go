@@ -1,21 +1,68 @@
 package hertzx
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/netip"
+	"os"
+	"strings"
+	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/cloudwego/hertz/pkg/app"
 	"github.com/cloudwego/hertz/pkg/app/server"
+	"github.com/cloudwego/hertz/pkg/common/config"
+	"github.com/cloudwego/hertz/pkg/network"
+	"github.com/cloudwego/hertz/pkg/network/standard"
+	"github.com/cloudwego/hertz/pkg/protocol/suite"
 	"github.com/go-sphere/httpx"
 )
 
-var _ httpx.Engine = (*Engine)(nil)
+var (
+	_ httpx.Engine               = (*Engine)(nil)
+	_ httpx.NativeEngineProvider = (*Engine)(nil)
+)
 
 type ErrorHandler func(ctx context.Context, rc *app.RequestContext, err error)
 
 type Config struct {
-	engine     *server.Hertz
-	errHandler ErrorHandler
+	engine          *server.Hertz
+	errHandler      ErrorHandler
+	shutdownTimeout time.Duration
+	readTimeout     time.Duration
+	writeTimeout    time.Duration
+	idleTimeout     time.Duration
+	headerTimeout   time.Duration
+	connRequests    int
+	requestTimeout  time.Duration
+	maxHeaderBytes  int
+	concurrency     int
+	multipartMemory int64
+	trustedProxies  []netip.Prefix
+	trustProxies    bool
+	certFile        string
+	keyFile         string
+	tlsConfig       *tls.Config
+	h2c             bool
+	configErr       error
+	unixSocket      string
+	unixPerm        os.FileMode
+	listener        *startListener
+	serverOpts      []config.Option
+	runtimeRoutes   bool
+	basePath        string
+	panicPolicy     httpx.PanicPolicy
+	errorPages      fs.FS
+	jsonOptions     *httpx.JSONOptions
+	envelope        httpx.EnvelopeFunc
 }
 
 type Option func(*Config)
@@ -26,10 +73,23 @@ func NewConfig(opts ...Option) *Config {
 		opt(&conf)
 	}
 	if conf.engine == nil {
-		conf.engine = server.Default()
+		conf.engine = server.Default(conf.serverOptions()...)
+		conf.trustProxies = true
+	}
+	if conf.trustProxies {
+		conf.engine.SetClientIPFunc(clientIP(conf.trustedProxies))
 	}
 	if conf.errHandler == nil {
 		conf.errHandler = func(ctx context.Context, rc *app.RequestContext, err error) {
+			fc := acquireHertzContext(ctx, rc)
+			err = httpx.LocalizeError(fc, err)
+			releaseHertzContext(fc)
+			var ve *httpx.ValidationError
+			if errors.As(err, &ve) {
+				rc.JSON(http.StatusUnprocessableEntity, ve)
+				rc.Abort()
+				return
+			}
 			rc.JSON(500, map[string]string{
 				"error": err.Error(),
 			})
@@ -38,31 +98,513 @@ func NewConfig(opts ...Option) *Config {
 	}
 	return &conf
 }
+
+// serverOptions maps TLS, h2c, limit, timeout and listener options onto hertz server options.
+// Hertz binds its transport at construction, so these only apply to the
+// engine created by NewConfig; custom engines configure server.WithTLS
+// directly.
+func (conf *Config) serverOptions() []config.Option {
+	// Cancel request contexts when clients disconnect, as net/http does.
+	opts := append([]config.Option{server.WithSenseClientDisconnection(true)}, conf.serverOpts...)
+	if conf.tlsConfig != nil || conf.certFile != "" || conf.keyFile != "" {
+		tlsConfig, err := httpx.NewTLSConfig(conf.tlsConfig, conf.certFile, conf.keyFile)
+		if err != nil {
+			conf.configErr = err
+		} else {
+			// The netpoll transport does not support TLS.
+			opts = append(opts, server.WithTLS(tlsConfig), server.WithTransport(standard.NewTransporter))
+		}
+	}
+	if conf.h2c {
+		opts = append(opts, server.WithH2C(true))
+	}
+	if conf.readTimeout > 0 {
+		opts = append(opts, server.WithReadTimeout(conf.readTimeout))
+	} else if conf.headerTimeout > 0 {
+		opts = append(opts, server.WithReadTimeout(conf.headerTimeout))
+	}
+	if conf.writeTimeout > 0 {
+		opts = append(opts, server.WithWriteTimeout(conf.writeTimeout))
+	}
+	if conf.idleTimeout > 0 {
+		opts = append(opts, server.WithIdleTimeout(conf.idleTimeout))
+	}
+	if conf.connRequests > 0 {
+		opts = append(opts, countConnRequests())
+	}
+	if conf.maxHeaderBytes > 0 {
+		opts = append(opts, server.WithMaxHeaderBytes(conf.maxHeaderBytes))
+	}
+	if listen := conf.listenFunc(opts); listen != nil {
+		newTransport := config.NewOptions(opts).TransporterNewer
+		if newTransport == nil {
+			newTransport = defaultTransporter
+		}
+		if conf.concurrency > 0 {
+			// netpoll cannot serve the listener of httpx.LimitListener.
+			newTransport = standard.NewTransporter
+		}
+		conf.listener = &startListener{listen: listen, newTransport: newTransport}
+		opts = append(opts, server.WithTransport(conf.listener.transporter))
+		if conf.unixSocket != "" {
+			opts = append(opts, config.Option{F: func(o *config.Options) {
+				// Hertz unlinks unix socket paths before serving, which would
+				// remove the file the listener is bound to; Stop removes it
+				// instead.
+				o.Network = ""
+			}})
+		}
+	}
+	return opts
+}
+
+// listenFunc returns how the engine created by NewConfig binds its listener
+// when it starts, or nil when hertz binds it itself: unix sockets,
+// WithConcurrencyLimit and TCP port 0 need a listener of their own, the last
+// so that BoundAddr can report the port chosen by the kernel.
+func (conf *Config) listenFunc(opts []config.Option) func() (net.Listener, error) {
+	options := config.NewOptions(opts)
+	var listen func() (net.Listener, error)
+	switch {
+	case conf.unixSocket != "":
+		path, perm := conf.unixSocket, conf.unixPerm
+		listen = func() (net.Listener, error) {
+			return httpx.ListenUnix(path, perm)
+		}
+	case options.Listener != nil:
+		if conf.concurrency <= 0 {
+			return nil
+		}
+		ln := options.Listener
+		listen = func() (net.Listener, error) {
+			return ln, nil
+		}
+	case conf.concurrency > 0 || isEphemeral(options):
+		network, addr := options.Network, options.Addr
+		listen = func() (net.Listener, error) {
+			return net.Listen(network, addr)
+		}
+	default:
+		return nil
+	}
+	if n := conf.concurrency; n > 0 {
+		bind := listen
+		listen = func() (net.Listener, error) {
+			ln, err := bind()
+			if err != nil {
+				return nil, err
+			}
+			return httpx.LimitListener(ln, n), nil
+		}
+	}
+	return listen
+}
+
+// isEphemeral reports whether options listen on TCP port 0.
+func isEphemeral(options *config.Options) bool {
+	if !strings.HasPrefix(options.Network, "tcp") {
+		return false
+	}
+	_, port, err := net.SplitHostPort(options.Addr)
+	return err == nil && port == "0"
+}
+
+// startListener binds the listener of the engine created by NewConfig when
+// the engine starts rather than when it is created, so engines that never
+// start hold no port or socket file. Hertz hands its transport the listener
+// when the engine is created, so startListener is the transport of the engine
+// and creates the one serving the listener once it is bound.
+type startListener struct {
+	listen       func() (net.Listener, error)
+	newTransport func(*config.Options) network.Transporter
+
+	mu        sync.Mutex
+	options   *config.Options
+	transport network.Transporter
+}
+
+// transporter is the server.WithTransport constructor of l.
+func (l *startListener) transporter(options *config.Options) network.Transporter {
+	l.options = options
+	return l
+}
+
+// bind binds the listener, once, and sets it on the engine options, where
+// BoundAddr reads it.
+func (l *startListener) bind() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.transport != nil {
+		return nil
+	}
+	ln, err := l.listen()
+	if err != nil {
+		return err
+	}
+	l.options.Listener = ln
+	l.transport = l.newTransport(l.options)
+	return nil
+}
+
+func (l *startListener) bound() network.Transporter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.transport
+}
+
+func (l *startListener) ListenAndServe(onData network.OnData) error {
+	if err := l.bind(); err != nil {
+		return err
+	}
+	return l.bound().ListenAndServe(onData)
+}
+
+func (l *startListener) Close() error {
+	if t := l.bound(); t != nil {
+		return t.Close()
+	}
+	return nil
+}
+
+func (l *startListener) Shutdown(ctx context.Context) error {
+	if t := l.bound(); t != nil {
+		return t.Shutdown(ctx)
+	}
+	return nil
+}
+
+// Listener returns the bound listener, or nil before the engine starts; hertz
+// checks it to report whether the engine is running.
+func (l *startListener) Listener() net.Listener {
+	if l.bound() == nil {
+		return nil
+	}
+	return l.options.Listener
+}
+
+// connRequestsKey is the context key of the number of requests served on
+// a connection, see WithMaxRequestsPerConn.
+type connRequestsKey struct{}
+
+// countConnRequests gives the context of each connection a request counter,
+// after the OnConnect hook of other options.
+func countConnRequests() config.Option {
+	return config.Option{F: func(o *config.Options) {
+		onConnect := o.OnConnect
+		o.OnConnect = func(ctx context.Context, conn network.Conn) context.Context {
+			if onConnect != nil {
+				ctx = onConnect(ctx, conn)
+			}
+			return context.WithValue(ctx, connRequestsKey{}, new(atomic.Int64))
+		}
+	}}
+}
+
+// closeAfterRequests closes connections after their n-th request, counted
+// by countConnRequests.
+func closeAfterRequests(n int) app.HandlerFunc {
+	return func(ctx context.Context, rc *app.RequestContext) {
+		if count, ok := ctx.Value(connRequestsKey{}).(*atomic.Int64); ok && count.Add(1) >= int64(n) {
+			rc.SetConnectionClose()
+		}
+		rc.Next(ctx)
+	}
+}
+
 func WithEngine(engine *server.Hertz) Option {
 	return func(conf *Config) {
 		conf.engine = engine
 	}
 }
 
+// WithServerOptions appends hertz server options used when New creates the
+// engine. They are ignored when WithEngine supplies one.
+func WithServerOptions(opts ...config.Option) Option {
+	return func(conf *Config) {
+		conf.serverOpts = append(conf.serverOpts, opts...)
+	}
+}
+
+// WithListener serves the engine created by New on a pre-bound listener,
+// for example one inherited through systemd socket activation. Custom engines
+// configure server.WithListener directly.
+func WithListener(ln net.Listener) Option {
+	return WithServerOptions(server.WithListener(ln))
+}
+
+// WithUnixSocket serves the engine created by New on a unix domain socket at
+// path. perm is applied to the socket file when non-zero. The socket is
+// created by Start; stale socket files are removed first and the socket file
+// is removed on shutdown.
+func WithUnixSocket(path string, perm os.FileMode) Option {
+	return func(conf *Config) {
+		conf.unixSocket = path
+		conf.unixPerm = perm
+	}
+}
+
 func WithErrorHandler(errHandler ErrorHandler) Option {
 	return func(conf *Config) {
 		conf.errHandler = errHandler
 	}
 }
 
+// WithShutdownTimeout bounds how long Stop waits for in-flight requests.
+// A zero or negative timeout relies solely on the context passed to Stop.
+func WithShutdownTimeout(timeout time.Duration) Option {
+	return func(conf *Config) {
+		conf.shutdownTimeout = timeout
+	}
+}
+
+// WithReadTimeout bounds reading a request, including its body. It maps
+// onto server.WithReadTimeout, which only applies to the engine created by
+// NewConfig.
+func WithReadTimeout(timeout time.Duration) Option {
+	return func(conf *Config) {
+		conf.readTimeout = timeout
+	}
+}
+
+// WithWriteTimeout bounds writing the response. It maps onto
+// server.WithWriteTimeout, which only applies to the engine created by
+// NewConfig.
+func WithWriteTimeout(timeout time.Duration) Option {
+	return func(conf *Config) {
+		conf.writeTimeout = timeout
+	}
+}
+
+// WithIdleTimeout bounds how long a keep-alive connection waits for the next
+// request. It maps onto server.WithIdleTimeout, which only applies to the engine
+// created by NewConfig.
+func WithIdleTimeout(timeout time.Duration) Option {
+	return func(conf *Config) {
+		conf.idleTimeout = timeout
+	}
+}
+
+// WithReadHeaderTimeout bounds reading the request headers, so clients
+// sending them slowly cannot hold connections open. Hertz has no separate
+// header timeout, so it maps onto server.WithReadTimeout unless
+// WithReadTimeout is set, and only applies to the engine created by
+// NewConfig.
+func WithReadHeaderTimeout(timeout time.Duration) Option {
+	return func(conf *Config) {
+		conf.headerTimeout = timeout
+	}
+}
+
+// WithMaxRequestsPerConn closes keep-alive connections once they have served
+// n requests, by answering the last one with "Connection: close". It counts
+// the requests of the connections accepted by the engine created by
+// NewConfig.
+func WithMaxRequestsPerConn(n int) Option {
+	return func(conf *Config) {
+		conf.connRequests = n
+	}
+}
+
+// WithRequestTimeout gives the context of each request a deadline timeout
+// after it starts, see httpx.RequestTimeout.
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(conf *Config) {
+		conf.requestTimeout = timeout
+	}
+}
+
+// WithMaxHeaderBytes bounds the size of request headers. It maps
+// onto server.WithMaxHeaderBytes, which only applies to the engine created
+// by NewConfig.
+func WithMaxHeaderBytes(n int) Option {
+	return func(conf *Config) {
+		conf.maxHeaderBytes = n
+	}
+}
+
+// WithConcurrencyLimit bounds the number of connections served at once.
+// hertz has no such setting, so the engine created by NewConfig listens
+// through httpx.LimitListener, and uses the standard transport, as netpoll
+// cannot serve a wrapped listener. Further connections
+// wait in the listen backlog until one of them is closed.
+func WithConcurrencyLimit(n int) Option {
+	return func(conf *Config) {
+		conf.concurrency = n
+	}
+}
+
+// WithMultipartMemoryLimit sets how many bytes of a multipart form
+// MultipartForm, FormFile and BindForm keep in memory; larger file parts are
+// written to temporary files. hertz
+// otherwise keeps the forms of buffered bodies in memory. New installs a
+// middleware keeping the limit for the request, and removing the temporary
+// files once it has been served.
+func WithMultipartMemoryLimit(bytes int64) Option {
+	return func(conf *Config) {
+		conf.multipartMemory = bytes
+	}
+}
+
+// WithTrustedProxies sets the proxies whose X-Forwarded-For and X-Real-IP
+// headers Context.ClientIP believes, replacing the ClientIP function of the
+// hertz engine.
+func WithTrustedProxies(proxies ...netip.Prefix) Option {
+	return func(conf *Config) {
+		conf.trustedProxies = proxies
+		conf.trustProxies = true
+	}
+}
+
+// clientIP reads the client IP from the forwarding headers behind proxies
+// only; hertz trusts every peer by default.
+func clientIP(proxies []netip.Prefix) app.ClientIP {
+	opts := app.ClientIPOptions{TrustedCIDRs: make([]*net.IPNet, len(proxies))}
+	if len(proxies) > 0 {
+		opts.RemoteIPHeaders = []string{"X-Forwarded-For", "X-Real-IP"}
+	}
+	for i, p := range proxies {
+		opts.TrustedCIDRs[i] = &net.IPNet{
+			IP:   p.Addr().AsSlice(),
+			Mask: net.CIDRMask(p.Bits(), p.Addr().BitLen()),
+		}
+	}
+	return app.ClientIPWithOption(opts)
+}
+
+// WithTLS sets the certificate and key files of the engine created by New.
+// Because hertz binds TLS at construction, Start and StartTLS both serve
+// HTTPS once TLS is configured.
+func WithTLS(certFile, keyFile string) Option {
+	return func(conf *Config) {
+		conf.certFile = certFile
+		conf.keyFile = keyFile
+	}
+}
+
+// WithTLSConfig sets the TLS config of the engine created by New. When
+// combined with WithTLS, the certificate pair is appended to the config's
+// certificates.
+func WithTLSConfig(tlsConfig *tls.Config) Option {
+	return func(conf *Config) {
+		conf.tlsConfig = tlsConfig
+	}
+}
+
+// WithH2C enables cleartext HTTP/2 on the engine created by New. Hertz
+// serves HTTP/2 through a separately registered protocol server such as
+// github.com/hertz-contrib/http2; Start returns httpx.ErrH2CUnsupported
+// when none is registered.
+func WithH2C(enabled bool) Option {
+	return func(conf *Config) {
+		conf.h2c = enabled
+	}
+}
+
+// WithAllowRuntimeRoutes keeps the routes open to registration after Start,
+// which otherwise freezes them, see httpx.Engine.Freeze. hertz does not
+// synchronize its route trees, so routes registered while requests are served
+// race with them.
+func WithAllowRuntimeRoutes(allowed bool) Option {
+	return func(conf *Config) {
+		conf.runtimeRoutes = allowed
+	}
+}
+
+// WithBasePath prefixes the routes of every group created by the engine with
+// path, for services mounted under a path by a gateway. Router.BasePath,
+// FullPath and the routes reported to OnRouteRegistered include it.
+func WithBasePath(path string) Option {
+	return func(conf *Config) {
+		conf.basePath = path
+	}
+}
+
+// WithPanicPolicy sets what happens when a handler or middleware panics,
+// see httpx.PanicPolicy. The default, httpx.PanicPropagate, leaves panics to
+// the framework.
+func WithPanicPolicy(policy httpx.PanicPolicy) Option {
+	return func(conf *Config) {
+		conf.panicPolicy = policy
+	}
+}
+
+// WithHTMLErrorPages renders error responses as the static HTML pages of
+// pages for browsers, such as "404.html" and "500.html", see
+// httpx.HTMLErrorPages. Other clients get the response of the error handler.
+func WithHTMLErrorPages(pages fs.FS) Option {
+	return func(conf *Config) {
+		conf.errorPages = pages
+	}
+}
+
+// WithEnvelope makes httpx.OK, httpx.Created and httpx.EnvelopeErrorHandler
+// write the body returned by format instead of the httpx.Envelope, see
+// httpx.WithEnvelope.
+func WithEnvelope(format httpx.EnvelopeFunc) Option {
+	return func(conf *Config) {
+		conf.envelope = format
+	}
+}
+
+// WithJSONOptions makes the engine encode the bodies written with JSON
+// using opts instead of the framework's encoder, see httpx.JSONOptions.
+func WithJSONOptions(opts httpx.JSONOptions) Option {
+	return func(conf *Config) {
+		conf.jsonOptions = &opts
+	}
+}
+
 type Engine struct {
-	engine     *server.Hertz
-	errHandler ErrorHandler
-	running    atomic.Bool
+	engine          *server.Hertz
+	errHandler      ErrorHandler
+	shutdownTimeout time.Duration
+	configErr       error
+	unixSocket      string
+	listener        *startListener
+	hooks           *httpx.Hooks
+	running         atomic.Bool
+	runtimeRoutes   bool
+	basePath        string
 }
 
 func New(opts ...Option) httpx.Engine {
 	conf := NewConfig(opts...)
+	if conf.errorPages != nil {
+		conf.errHandler = errorPagesHandler(conf.errorPages, conf.errHandler)
+	}
 	engine := &Engine{
-		engine:     conf.engine,
-		errHandler: conf.errHandler,
+		engine:          conf.engine,
+		errHandler:      conf.errHandler,
+		shutdownTimeout: conf.shutdownTimeout,
+		configErr:       conf.configErr,
+		unixSocket:      conf.unixSocket,
+		listener:        conf.listener,
+		runtimeRoutes:   conf.runtimeRoutes,
+		basePath:        conf.basePath,
+		hooks:           &httpx.Hooks{},
 	}
 	engine.running.Store(false)
+	if conf.connRequests > 0 {
+		conf.engine.Use(closeAfterRequests(conf.connRequests))
+	}
+	if conf.jsonOptions != nil {
+		engine.Use(httpx.WithJSONOptions(*conf.jsonOptions))
+	}
+	if conf.envelope != nil {
+		engine.Use(httpx.WithEnvelope(conf.envelope))
+	}
+	if conf.errorPages != nil {
+		engine.Use(httpx.HTMLErrorPages(conf.errorPages))
+	}
+	if conf.panicPolicy != httpx.PanicPropagate {
+		engine.Use(httpx.RecoverPanics(conf.panicPolicy))
+	}
+	if conf.requestTimeout > 0 {
+		engine.Use(httpx.RequestTimeout(conf.requestTimeout))
+	}
+	if conf.multipartMemory > 0 {
+		engine.Use(multipartMemoryLimit(conf.multipartMemory))
+	}
 	return engine
 }
 
@@ -71,23 +613,74 @@ func (e *Engine) Use(middleware ...httpx.Middleware) {
 }
 
 func (e *Engine) Group(prefix string, m ...httpx.Middleware) httpx.Router {
+	prefix = httpx.JoinPaths(e.basePath, prefix)
 	return &Router{
-		group:      e.engine.Group(prefix, adaptMiddlewares(m, e.errHandler)...),
-		errHandler: e.errHandler,
+		group:       e.engine.Group(prefix),
+		middlewares: cloneMiddlewares(nil, m...),
+		errHandler:  e.errHandler,
+		hooks:       e.hooks,
 	}
 }
 
 func (e *Engine) Start() error {
+	if e.configErr != nil {
+		return e.configErr
+	}
+	options := e.engine.GetOptions()
+	if options.H2C && !e.engine.HasServer(suite.HTTP2) {
+		return fmt.Errorf("hertzx: no %s protocol server registered: %w", suite.HTTP2, httpx.ErrH2CUnsupported)
+	}
+	if err := e.hooks.RunStart(); err != nil {
+		return err
+	}
+	if e.listener != nil {
+		if err := e.listener.bind(); err != nil {
+			return err
+		}
+	}
+	if !e.runtimeRoutes {
+		e.Freeze()
+	}
 	e.running.Store(true)
 	defer e.running.Store(false)
 	return e.engine.Run()
 }
 
+// StartTLS serves HTTPS using the TLS config bound to the hertz engine.
+func (e *Engine) StartTLS() error {
+	if e.configErr != nil {
+		return e.configErr
+	}
+	if e.engine.GetOptions().TLS == nil {
+		return httpx.ErrTLSNotConfigured
+	}
+	return e.Start()
+}
+
 func (e *Engine) Stop(ctx context.Context) error {
+	e.hooks.RunStopping()
+	if e.shutdownTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.shutdownTimeout)
+		defer cancel()
+	}
 	err := e.engine.Shutdown(ctx)
 	if err == nil {
 		e.running.Store(false)
 	}
+	if e.unixSocket != "" && e.listener.bound() != nil {
+		err = errors.Join(err, httpx.RemoveUnixSocket(e.unixSocket))
+	}
+	return errors.Join(err, e.hooks.RunStop(ctx))
+}
+
+// StopGraceful stops accepting connections, waits for in-flight requests,
+// and then runs drain.
+func (e *Engine) StopGraceful(ctx context.Context, drain func()) error {
+	err := e.Stop(ctx)
+	if drain != nil {
+		drain()
+	}
 	return err
 }
 
@@ -95,3 +688,131 @@ func (e *Engine) Stop(ctx context.Context) error {
 func (e *Engine) IsRunning() bool {
 	return e.running.Load()
 }
+
+// BoundAddr returns the address the server is listening on while running.
+// For custom engines on port 0 without server.WithListener, the chosen port
+// cannot be observed and BoundAddr returns nil.
+func (e *Engine) BoundAddr() net.Addr {
+	if !e.IsRunning() {
+		return nil
+	}
+	options := e.engine.GetOptions()
+	if options.Listener != nil {
+		return options.Listener.Addr()
+	}
+	switch {
+	case options.Network == "unix":
+		return &net.UnixAddr{Name: options.Addr, Net: "unix"}
+	case strings.HasPrefix(options.Network, "tcp"):
+		addr, err := net.ResolveTCPAddr(options.Network, options.Addr)
+		if err != nil || addr.Port == 0 {
+			return nil
+		}
+		return addr
+	}
+	return nil
+}
+
+// Test serves req in-process through the hertz engine and returns its
+// response.
+func (e *Engine) Test(req *http.Request) (*http.Response, error) {
+	rc := e.engine.NewContext()
+	rc.Request.Header.SetMethod(req.Method)
+	uri := req.URL.String()
+	if !req.URL.IsAbs() {
+		host := req.Host
+		if host == "" {
+			host = "localhost"
+		}
+		uri = "http://" + host + req.URL.RequestURI()
+	}
+	rc.Request.SetRequestURI(uri)
+	for key, values := range req.Header {
+		for _, value := range values {
+			rc.Request.Header.Add(key, value)
+		}
+	}
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		rc.Request.SetBodyStream(bytes.NewReader(body), len(body))
+	}
+
+	e.engine.ServeHTTP(req.Context(), rc)
+
+	header := make(http.Header)
+	rc.Response.Header.VisitAll(func(k, v []byte) {
+		header.Add(string(k), string(v))
+	})
+	body := bytes.Clone(rc.Response.Body())
+	status := rc.Response.StatusCode()
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// OnStart registers a hook that runs before the server starts listening.
+func (e *Engine) OnStart(fn func() error) {
+	e.hooks.OnStart(fn)
+}
+
+// OnStopping registers a hook that runs when Stop is called, before shutdown.
+func (e *Engine) OnStopping(fn func()) {
+	e.hooks.OnStopping(fn)
+}
+
+// OnStop registers a hook that runs after the server has shut down.
+func (e *Engine) OnStop(fn func(ctx context.Context) error) {
+	e.hooks.OnStop(fn)
+}
+
+// OnRouteRegistered registers a hook invoked for every handler route.
+func (e *Engine) OnRouteRegistered(fn func(httpx.RouteInfo)) {
+	e.hooks.OnRouteRegistered(fn)
+}
+
+func (e *Engine) Freeze() {
+	e.hooks.Freeze()
+}
+
+// Native returns the *server.Hertz of the engine, see Server.
+func (e *Engine) Native() any {
+	return e.engine
+}
+
+// Server returns the *server.Hertz of a hertzx engine, or nil for engines of
+// other adapters. hertz binds its transport when the engine is created, so
+// transport settings are set with WithServerOptions instead; the returned
+// server can still register hooks such as OnRun and OnShutdown.
+func Server(engine httpx.Engine) *server.Hertz {
+	if e, ok := engine.(*Engine); ok {
+		return e.engine
+	}
+	return nil
+}
+
+// errorPagesHandler serves the error pages of route errors, which reach the
+// engine middleware only after errHandler responded, see
+// httpx.ServeErrorPage.
+func errorPagesHandler(pages fs.FS, errHandler ErrorHandler) ErrorHandler {
+	return func(c context.Context, ctx *app.RequestContext, err error) {
+		fc := acquireHertzContext(c, ctx)
+		defer releaseHertzContext(fc)
+		if _, status, _ := httpx.ParseError(err); httpx.ServeErrorPage(fc, pages, int(status)) {
+			ctx.Abort()
+			return
+		}
+		errHandler(c, ctx, err)
+	}
+}
@@ -3,6 +3,7 @@ package hertzx
 import (
 	"context"
 	"errors"
+	"mime/multipart"
 
 	"github.com/cloudwego/hertz/pkg/app"
 	"github.com/go-sphere/httpx"
@@ -10,7 +11,8 @@ import (
 
 func adaptMiddleware(middleware httpx.Middleware, errHandler ErrorHandler) app.HandlerFunc {
 	return func(c context.Context, ctx *app.RequestContext) {
-		fc := newHertzContext(c, ctx)
+		fc := acquireHertzContext(c, ctx)
+		defer releaseHertzContext(fc)
 		if err := middleware(fc); err != nil {
 			_ = ctx.Error(err)
 			if !ctx.IsAborted() {
@@ -39,13 +41,84 @@ func adaptMiddlewares(middlewares []httpx.Middleware, errHandler ErrorHandler) [
 	return gMid
 }
 
+func cloneMiddlewares(middlewares []httpx.Middleware, extra ...httpx.Middleware) []httpx.Middleware {
+	out := make([]httpx.Middleware, len(middlewares)+len(extra))
+	copy(out, middlewares)
+	copy(out[len(middlewares):], extra)
+	return out
+}
+
+// AdaptErrorHandler adapts a framework-independent error handler, such as
+// httpx.ProblemErrorHandler, for WithErrorHandler.
+func AdaptErrorHandler(errHandler httpx.ErrorHandler) ErrorHandler {
+	return func(c context.Context, ctx *app.RequestContext, err error) {
+		fc := acquireHertzContext(c, ctx)
+		defer releaseHertzContext(fc)
+		errHandler(fc, err)
+		ctx.Abort()
+	}
+}
+
+// AdaptHertzMiddleware adapts a hertz middleware. Passed to a Router, which
+// composes its middleware into a single hertz handler, the middleware runs in
+// a handler chain of its own, where calling c.Next(ctx) runs the rest of the
+// composed chain.
 func AdaptHertzMiddleware(middleware app.HandlerFunc) httpx.Middleware {
 	return func(ctx httpx.Context) error {
 		fc, ok := ctx.(*hertzContext)
 		if !ok {
 			return errors.New("AdaptHertzMiddleware: invalid context type")
 		}
-		middleware(fc.baseCtx, fc.ctx)
-		return nil
+		if fc.chainNext == nil {
+			middleware(fc.baseCtx, fc.ctx)
+			return nil
+		}
+		return runInChain(fc, middleware)
+	}
+}
+
+// runInChain runs middleware in a hertz chain of its own, followed by the rest
+// of the composed chain of fc, and restores the route's chain afterwards.
+func runInChain(fc *hertzContext, middleware app.HandlerFunc) error {
+	rc := fc.ctx
+	handlers, index := rc.Handlers(), rc.GetIndex()
+	var err error
+	rc.SetHandlers(app.HandlersChain{middleware, func(c context.Context, _ *app.RequestContext) {
+		fc.baseCtx = c
+		err = fc.Next()
+	}})
+	rc.SetIndex(-1)
+	rc.Next(fc.baseCtx)
+	rc.SetHandlers(handlers)
+	if !rc.IsAborted() {
+		rc.SetIndex(index)
+	}
+	return err
+}
+
+const (
+	// multipartMemoryKey keeps the limit of WithMultipartMemoryLimit in the
+	// request state.
+	multipartMemoryKey = "hertzx.multipart_memory"
+	// multipartFormKey keeps the form parsed with that limit.
+	multipartFormKey = "hertzx.multipart_form"
+)
+
+// multipartMemoryLimit keeps bytes as the multipart memory limit of each
+// request, and removes the temporary files of the form parsed with it once
+// the request has been served.
+func multipartMemoryLimit(bytes int64) httpx.Middleware {
+	return func(ctx httpx.Context) error {
+		rc, ok := Unwrap(ctx)
+		if !ok {
+			return ctx.Next()
+		}
+		rc.Set(multipartMemoryKey, bytes)
+		defer func() {
+			if form, ok := rc.Value(multipartFormKey).(*multipart.Form); ok {
+				_ = form.RemoveAll()
+			}
+		}()
+		return ctx.Next()
 	}
 }
@@ -18,13 +18,18 @@ import (
 
 var _ httpx.Router = (*Router)(nil)
 
+// Router composes its middleware with each handler when the route is
+// registered, see httpx.Compose, so hertz runs a single handler per route
+// besides the engine's middleware.
 type Router struct {
-	group      *route.RouterGroup
-	errHandler ErrorHandler
+	group       *route.RouterGroup
+	middlewares []httpx.Middleware
+	errHandler  ErrorHandler
+	hooks       *httpx.Hooks
 }
 
 func (r *Router) Use(m ...httpx.Middleware) {
-	r.group.Use(adaptMiddlewares(m, r.errHandler)...)
+	r.middlewares = append(r.middlewares, m...)
 }
 
 func (r *Router) BasePath() string {
@@ -42,69 +47,102 @@ func (r *Router) SupportsRouterFeature(feature httpx.RouterFeature) bool {
 
 func (r *Router) Group(prefix string, m ...httpx.Middleware) httpx.Router {
 	return &Router{
-		group:      r.group.Group(prefix, adaptMiddlewares(m, r.errHandler)...),
-		errHandler: r.errHandler,
+		group:       r.group.Group(prefix),
+		middlewares: cloneMiddlewares(r.middlewares, m...),
+		errHandler:  r.errHandler,
+		hooks:       r.hooks,
 	}
 }
 
-func (r *Router) Handle(method, path string, h httpx.Handler) {
+func (r *Router) Handle(method, path string, h httpx.Handler) *httpx.Route {
 	method = strings.ToUpper(method)
-	r.group.Handle(method, path, r.toHertzHandler(h))
+	r.checkRoute(method, path)
+	route := httpx.NewRouteFor(r.routeInfo(method, path))
+	r.group.Handle(method, path, r.toHertzHandler(route, h))
+	r.notifyRoute(method, path)
+	return route
 }
 
-func (r *Router) Any(path string, h httpx.Handler) {
-	r.group.Any(path, r.toHertzHandler(h))
+func (r *Router) Any(path string, h httpx.Handler) *httpx.Route {
+	r.checkRoute(httpx.MethodAny, path)
+	route := httpx.NewRouteFor(r.routeInfo(httpx.MethodAny, path))
+	r.group.Any(path, r.toHertzHandler(route, h))
+	r.notifyRoute(httpx.MethodAny, path)
+	return route
 }
 
 func (r *Router) Static(prefix, root string) {
+	r.checkRoute(http.MethodGet, prefix)
 	r.StaticFS(prefix, osDirFS(root))
 }
 
 func (r *Router) StaticFS(prefix string, fs fs.FS) {
+	r.checkRoute(http.MethodGet, prefix)
 	urlPattern := path.Join(prefix, "/*filepath")
-	handler := r.toStaticHandler(fs)
-	r.group.GET(urlPattern, handler)
-	r.group.HEAD(urlPattern, handler)
+	// File handlers cannot be composed: the middleware run as hertz handlers.
+	handlers := append(adaptMiddlewares(r.middlewares, r.errHandler), r.toStaticHandler(fs))
+	r.group.GET(urlPattern, handlers...)
+	r.group.HEAD(urlPattern, handlers...)
 }
 
 // GET registers a new GET route for a path with matching handler.
-func (r *Router) GET(path string, h httpx.Handler) {
-	r.group.GET(path, r.toHertzHandler(h))
+func (r *Router) GET(path string, h httpx.Handler) *httpx.Route {
+	return r.Handle(http.MethodGet, path, h)
 }
 
 // POST registers a new POST route for a path with matching handler.
-func (r *Router) POST(path string, h httpx.Handler) {
-	r.group.POST(path, r.toHertzHandler(h))
+func (r *Router) POST(path string, h httpx.Handler) *httpx.Route {
+	return r.Handle(http.MethodPost, path, h)
 }
 
 // PUT registers a new PUT route for a path with matching handler.
-func (r *Router) PUT(path string, h httpx.Handler) {
-	r.group.PUT(path, r.toHertzHandler(h))
+func (r *Router) PUT(path string, h httpx.Handler) *httpx.Route {
+	return r.Handle(http.MethodPut, path, h)
 }
 
 // DELETE registers a new DELETE route for a path with matching handler.
-func (r *Router) DELETE(path string, h httpx.Handler) {
-	r.group.DELETE(path, r.toHertzHandler(h))
+func (r *Router) DELETE(path string, h httpx.Handler) *httpx.Route {
+	return r.Handle(http.MethodDelete, path, h)
 }
 
 // PATCH registers a new PATCH route for a path with matching handler.
-func (r *Router) PATCH(path string, h httpx.Handler) {
-	r.group.PATCH(path, r.toHertzHandler(h))
+func (r *Router) PATCH(path string, h httpx.Handler) *httpx.Route {
+	return r.Handle(http.MethodPatch, path, h)
 }
 
 // HEAD registers a new HEAD route for a path with matching handler.
-func (r *Router) HEAD(path string, h httpx.Handler) {
-	r.group.HEAD(path, r.toHertzHandler(h))
+func (r *Router) HEAD(path string, h httpx.Handler) *httpx.Route {
+	return r.Handle(http.MethodHead, path, h)
 }
 
 // OPTIONS registers a new OPTIONS route for a path with matching handler.
-func (r *Router) OPTIONS(path string, h httpx.Handler) {
-	r.group.OPTIONS(path, r.toHertzHandler(h))
+func (r *Router) OPTIONS(path string, h httpx.Handler) *httpx.Route {
+	return r.Handle(http.MethodOptions, path, h)
 }
 
-func (r *Router) toHertzHandler(h httpx.Handler) app.HandlerFunc {
+func (r *Router) notifyRoute(method, path string) {
+	r.hooks.NotifyRoute(r.routeInfo(method, path))
+}
+
+// checkRoute panics when the engine is frozen, see httpx.Engine.Freeze.
+func (r *Router) checkRoute(method, path string) {
+	if err := r.hooks.CheckRoute(r.routeInfo(method, path)); err != nil {
+		panic(err)
+	}
+}
+
+func (r *Router) routeInfo(method, path string) httpx.RouteInfo {
+	return httpx.RouteInfo{
+		Method: method,
+		Path:   httpx.JoinPaths(r.group.BasePath(), path),
+	}
+}
+
+func (r *Router) toHertzHandler(route *httpx.Route, h httpx.Handler) app.HandlerFunc {
+	h = route.Compose(h, r.middlewares...)
 	return func(ctx context.Context, rc *app.RequestContext) {
-		hc := newHertzContext(ctx, rc)
+		hc := acquireHertzContext(ctx, rc)
+		defer releaseHertzContext(hc)
 		if err := h(hc); err != nil {
 			_ = rc.Error(err)
 			r.errHandler(ctx, rc, err)
@@ -0,0 +1,8 @@
+//go:build !((amd64 || arm64) && (linux || darwin))
+
+package hertzx
+
+import "github.com/cloudwego/hertz/pkg/network/standard"
+
+// defaultTransporter is the transport hertz serves with on this platform.
+var defaultTransporter = standard.NewTransporter
@@ -0,0 +1,558 @@
+package httpx
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// defaultMultipartMemory matches the in-memory limit used by gin and echo.
+const defaultMultipartMemory = 32 << 20
+
+var (
+	_ Context           = (*httpContext)(nil)
+	_ ChainContext      = (*httpContext)(nil)
+	_ RequestBodySetter = (*httpContext)(nil)
+	_ Aborter           = (*httpContext)(nil)
+)
+
+// httpContext is the built-in Context implementation over net/http, used
+// where no framework adapter is involved.
+type httpContext struct {
+	writer   *responseWriter
+	request  *http.Request
+	handlers []Handler
+	index    int
+	next     Handler
+	aborted  bool
+	query    url.Values
+	params   map[string]string
+	pattern  string
+	keys     map[string]any
+
+	multipartMemory int64
+}
+
+func newHTTPContext(w http.ResponseWriter, r *http.Request, handlers []Handler) *httpContext {
+	pattern, params := servePattern(r)
+	return &httpContext{
+		writer:   &responseWriter{ResponseWriter: w, status: http.StatusOK},
+		request:  r,
+		handlers: handlers,
+		index:    -1,
+		params:   params,
+		pattern:  pattern,
+
+		multipartMemory: defaultMultipartMemory,
+	}
+}
+
+// servePattern extracts the route pattern and wildcard values matched by
+// http.ServeMux, stripping the optional method and host of the pattern.
+func servePattern(r *http.Request) (string, map[string]string) {
+	pattern := r.Pattern
+	if i := strings.IndexByte(pattern, ' '); i >= 0 {
+		pattern = strings.TrimLeft(pattern[i+1:], " \t")
+	}
+	if i := strings.IndexByte(pattern, '/'); i > 0 {
+		pattern = pattern[i:]
+	}
+	var params map[string]string
+	for rest := pattern; ; {
+		start := strings.IndexByte(rest, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(rest[start:], '}')
+		if end < 0 {
+			break
+		}
+		name := strings.TrimSuffix(rest[start+1:start+end], "...")
+		rest = rest[start+end+1:]
+		if name == "" || name == "$" {
+			continue
+		}
+		if params == nil {
+			params = make(map[string]string)
+		}
+		params[name] = r.PathValue(name)
+	}
+	return pattern, params
+}
+
+// Request (httpx.Request)
+
+func (c *httpContext) Method() string {
+	return c.request.Method
+}
+
+func (c *httpContext) Path() string {
+	return c.request.URL.Path
+}
+
+func (c *httpContext) FullPath() string {
+	return c.pattern
+}
+
+// ClientIP returns the host of the connection's remote address. Proxy
+// headers are not trusted because they can be set by any client.
+func (c *httpContext) ClientIP() string {
+	host, _, err := net.SplitHostPort(strings.TrimSpace(c.request.RemoteAddr))
+	if err != nil {
+		return c.request.RemoteAddr
+	}
+	return host
+}
+
+func (c *httpContext) Param(key string) string {
+	return c.params[key]
+}
+
+func (c *httpContext) Params() map[string]string {
+	if len(c.params) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(c.params))
+	for k, v := range c.params {
+		out[k] = v
+	}
+	return out
+}
+
+func (c *httpContext) queryValues() url.Values {
+	if c.query == nil {
+		c.query = c.request.URL.Query()
+	}
+	return c.query
+}
+
+func (c *httpContext) Query(key string) string {
+	return c.queryValues().Get(key)
+}
+
+func (c *httpContext) Queries() map[string][]string {
+	values := c.queryValues()
+	if len(values) == 0 {
+		return nil
+	}
+	out := make(map[string][]string, len(values))
+	for k, v := range values {
+		out[k] = append([]string(nil), v...)
+	}
+	return out
+}
+
+func (c *httpContext) RawQuery() string {
+	return c.request.URL.RawQuery
+}
+
+func (c *httpContext) Header(key string) string {
+	return RequestHeader(c.request, key)
+}
+
+func (c *httpContext) HeaderValues(key string) []string {
+	return RequestHeaderValues(c.request, key)
+}
+
+func (c *httpContext) Headers() map[string][]string {
+	return RequestHeaders(c.request.Header)
+}
+
+func (c *httpContext) Cookie(name string) (string, error) {
+	cookie, err := c.request.Cookie(name)
+	if err != nil {
+		return "", http.ErrNoCookie
+	}
+	return cookie.Value, nil
+}
+
+func (c *httpContext) Cookies() map[string]string {
+	raw := c.request.Cookies()
+	if len(raw) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(raw))
+	for _, cookie := range raw {
+		out[cookie.Name] = cookie.Value
+	}
+	return out
+}
+
+func (c *httpContext) AllQueries() iter.Seq2[string, string] {
+	return QueryPairs(c.request.URL.RawQuery)
+}
+
+func (c *httpContext) AllHeaders() iter.Seq2[string, string] {
+	return HeaderPairs(c.request.Header)
+}
+
+func (c *httpContext) AllCookies() iter.Seq2[string, string] {
+	return CookiePairs(c.request)
+}
+
+func (c *httpContext) Scheme() string {
+	return ForwardedScheme(c.request.Header.Get, c.request.TLS != nil)
+}
+
+func (c *httpContext) Host() string {
+	return ForwardedHost(c.request.Header.Get, c.request.Host)
+}
+
+func (c *httpContext) Proto() string {
+	return c.request.Proto
+}
+
+func (c *httpContext) ContentLength() int64 {
+	return c.request.ContentLength
+}
+
+func (c *httpContext) UserAgent() string {
+	return c.request.UserAgent()
+}
+
+func (c *httpContext) Referer() string {
+	return c.request.Referer()
+}
+
+func (c *httpContext) URL() *url.URL {
+	u := *c.request.URL
+	u.Scheme, u.Host = c.Scheme(), c.Host()
+	return &u
+}
+
+func (c *httpContext) FormValue(key string) string {
+	_ = CacheFormBody(c.request)
+	return c.request.FormValue(key)
+}
+
+func (c *httpContext) MultipartForm() (*multipart.Form, error) {
+	if err := c.request.ParseMultipartForm(c.multipartMemory); err != nil {
+		return nil, err
+	}
+	return c.request.MultipartForm, nil
+}
+
+func (c *httpContext) FormFile(name string) (*multipart.FileHeader, error) {
+	form, err := c.MultipartForm()
+	if err != nil {
+		return nil, err
+	}
+	if files := form.File[name]; len(files) > 0 {
+		return files[0], nil
+	}
+	return nil, http.ErrMissingFile
+}
+
+func (c *httpContext) BodyRaw() ([]byte, error) {
+	return ReusableBody(c.request)
+}
+
+func (c *httpContext) BodyReader() io.ReadCloser {
+	return ReusableBodyReader(c.request)
+}
+
+func (c *httpContext) SetRequestBody(body []byte) {
+	SetRequestBody(c.request, body)
+}
+
+// Binder (httpx.Binder)
+
+func (c *httpContext) BindJSON(dst any) error {
+	body, err := c.BodyRaw()
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, dst); err != nil {
+		return err
+	}
+	return ValidateRequired(dst)
+}
+
+func (c *httpContext) BindQuery(dst any) error {
+	return BindValues(dst, "query", c.queryValues())
+}
+
+func (c *httpContext) BindForm(dst any) error {
+	req := c.request
+	if err := CacheFormBody(req); err != nil {
+		return err
+	}
+	if err := req.ParseMultipartForm(c.multipartMemory); err != nil && err != http.ErrNotMultipart {
+		return err
+	}
+	return BindValues(dst, "form", req.Form)
+}
+
+func (c *httpContext) BindURI(dst any) error {
+	return BindParams(dst, c.params)
+}
+
+func (c *httpContext) BindHeader(dst any) error {
+	return BindHeaders(dst, c.request.Header)
+}
+
+func lookupValues(values map[string][]string) valueLookup {
+	return func(key string) ([]string, bool) {
+		v, ok := values[key]
+		return v, ok
+	}
+}
+
+// Responder (httpx.Responder)
+
+func (c *httpContext) Status(code int) {
+	c.writer.setStatus(code)
+}
+
+func (c *httpContext) SetHeader(key, value string) {
+	c.writer.Header().Set(key, value)
+}
+
+func (c *httpContext) SetCookie(cookie *http.Cookie) {
+	if cookie != nil {
+		http.SetCookie(c.writer, cookie)
+	}
+}
+
+func (c *httpContext) JSON(code int, v any) error {
+	marshal := json.Marshal
+	if opts, ok := JSONOptionsKey.Get(c); ok {
+		marshal = opts.Marshal
+	}
+	body, err := marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.Bytes(code, body, "application/json; charset=utf-8")
+}
+
+func (c *httpContext) Text(code int, s string) error {
+	return c.Bytes(code, []byte(s), "text/plain; charset=utf-8")
+}
+
+func (c *httpContext) NoContent(code int) error {
+	c.writer.WriteHeader(code)
+	return nil
+}
+
+func (c *httpContext) Bytes(code int, b []byte, contentType string) error {
+	if contentType == "" {
+		contentType = http.DetectContentType(b)
+	}
+	c.writer.Header().Set("Content-Type", contentType)
+	c.writer.WriteHeader(code)
+	_, err := c.writer.Write(b)
+	return err
+}
+
+func (c *httpContext) DataFromReader(code int, contentType string, r io.Reader, size int) error {
+	if rc, ok := r.(io.Closer); ok {
+		defer func() {
+			_ = rc.Close()
+		}()
+	}
+	if contentType == "" {
+		contentType = http.DetectContentType(nil)
+	}
+	header := c.writer.Header()
+	header.Set("Content-Type", contentType)
+	if size >= 0 {
+		header.Set("Content-Length", strconv.Itoa(size))
+	}
+	c.writer.WriteHeader(code)
+	_, err := io.Copy(c.writer, r)
+	return err
+}
+
+func (c *httpContext) File(path string) error {
+	http.ServeFile(c.writer, c.request, path)
+	return nil
+}
+
+func (c *httpContext) Redirect(code int, location string) error {
+	if (code < http.StatusMultipleChoices || code > http.StatusPermanentRedirect) && code != http.StatusCreated {
+		return fmt.Errorf("httpx: cannot redirect with status code %d", code)
+	}
+	http.Redirect(c.writer, c.request, location, code)
+	return nil
+}
+
+// StateStore (httpx.StateStore)
+
+func (c *httpContext) Set(key string, val any) {
+	if c.keys == nil {
+		c.keys = make(map[string]any)
+	}
+	c.keys[key] = val
+}
+
+func (c *httpContext) Get(key string) (any, bool) {
+	val, ok := c.keys[key]
+	return val, ok
+}
+
+// Context (context.Context accessor + Next)
+
+func (c *httpContext) Context() context.Context {
+	return WithState(c.request.Context(), c)
+}
+
+func (c *httpContext) SetContext(ctx context.Context) {
+	c.request = c.request.WithContext(ctx)
+}
+
+// Aborter (httpx.Aborter)
+
+func (c *httpContext) Abort() {
+	c.aborted = true
+	c.Set(AbortedKey, true)
+}
+
+func (c *httpContext) AbortWithStatus(code int) error {
+	c.Abort()
+	return c.NoContent(code)
+}
+
+func (c *httpContext) AbortWithStatusJSON(code int, obj any) error {
+	c.Abort()
+	return c.JSON(code, obj)
+}
+
+func (c *httpContext) IsAborted() bool {
+	_, ok := c.Get(AbortedKey)
+	return ok
+}
+
+// SetNext sets the rest of a chain composed by Compose.
+func (c *httpContext) SetNext(next Handler) {
+	c.next = next
+}
+
+func (c *httpContext) Next() error {
+	if c.aborted {
+		return nil
+	}
+	if next := c.next; next != nil {
+		c.next = nil
+		return next(c)
+	}
+	c.index++
+	if c.index >= len(c.handlers) {
+		return nil
+	}
+	return c.handlers[c.index](c)
+}
+
+// ResponseInfo and ResponseSizer (httpx.ResponseInfo, httpx.ResponseSizer)
+
+func (c *httpContext) StatusCode() int {
+	return c.writer.status
+}
+
+func (c *httpContext) BodySize() int {
+	return c.writer.size
+}
+
+// Flusher and Hijacker (httpx.Flusher, httpx.Hijacker)
+
+func (c *httpContext) Flush() error {
+	c.writer.writeHeaderNow()
+	return http.NewResponseController(c.writer).Flush()
+}
+
+func (c *httpContext) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(c.writer).Hijack()
+	if err == nil {
+		// The connection is no longer served by net/http; keep
+		// writeHeaderNow from writing to it.
+		c.writer.wroteHeader = true
+	}
+	return conn, rw, err
+}
+
+// InformationalWriter (httpx.InformationalWriter)
+
+func (c *httpContext) WriteInformational(code int, header http.Header) error {
+	if c.writer.wroteHeader {
+		return ErrResponseCommitted
+	}
+	return WriteHTTPInformational(c.writer.ResponseWriter, code, header)
+}
+
+// responseWriter records the status and body size of a response. The status
+// is only sent once the header is written, so Status can be changed until
+// the response is committed.
+type responseWriter struct {
+	http.ResponseWriter
+	status      int
+	size        int
+	wroteHeader bool
+}
+
+func (w *responseWriter) setStatus(code int) {
+	if !w.wroteHeader {
+		w.status = code
+	}
+}
+
+func (w *responseWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+	w.status = code
+	w.wroteHeader = true
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(w.status)
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.size += n
+	return n, err
+}
+
+// writeHeaderNow commits a status set with Status when nothing was written.
+func (w *responseWriter) writeHeaderNow() {
+	if !w.wroteHeader {
+		w.WriteHeader(w.status)
+	}
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// HTTPInterop (httpx.HTTPInterop)
+
+func (c *httpContext) HTTPRequest() *http.Request {
+	return c.request
+}
+
+func (c *httpContext) SetHTTPRequest(r *http.Request) {
+	c.request = r
+	c.query = nil
+}
+
+func (c *httpContext) HTTPResponseWriter() http.ResponseWriter {
+	return c.writer
+}
+
+func (c *httpContext) SetHTTPResponseWriter(w http.ResponseWriter) {
+	if rw, ok := w.(*responseWriter); ok {
+		c.writer = rw
+		return
+	}
+	c.writer = &responseWriter{ResponseWriter: w, status: c.writer.status}
+}
@@ -0,0 +1,174 @@
+package httpx
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// HTTPInterop exposes the net/http request and response writer of contexts
+// backed by net/http, such as those of ginx, echox and ToHTTPHandler.
+//
+// This optional capability lets net/http middleware replace the request or
+// wrap the response writer for the rest of the chain.
+type HTTPInterop interface {
+	// HTTPRequest returns the current request.
+	HTTPRequest() *http.Request
+	// SetHTTPRequest replaces the request seen by downstream handlers.
+	SetHTTPRequest(r *http.Request)
+	// HTTPResponseWriter returns the writer downstream writes go through.
+	HTTPResponseWriter() http.ResponseWriter
+	// SetHTTPResponseWriter routes downstream writes through w. Status and
+	// body size tracking is preserved as long as w writes to the writer
+	// returned by HTTPResponseWriter.
+	SetHTTPResponseWriter(w http.ResponseWriter)
+}
+
+// AsHTTPInterop returns net/http interop capability when supported.
+func AsHTTPInterop(ctx Context) (HTTPInterop, bool) {
+	hi, ok := ctx.(HTTPInterop)
+	return hi, ok
+}
+
+// WrapHTTPMiddleware adapts net/http middleware into a Middleware. The rest
+// of the chain runs when the wrapped middleware calls its next handler; if it
+// responds without calling next, the chain stops there.
+//
+// On contexts implementing HTTPInterop the middleware sees the real request
+// and writer, so request replacement and writer wrapping (compression,
+// buffering) work as in plain net/http. Other contexts, such as those of
+// fiberx and hertzx, run the middleware against an emulated request: headers
+// it sets before calling next and responses it writes instead of calling
+// next are applied, and the request's context is propagated, but request
+// header changes and writer wrapping have no effect.
+func WrapHTTPMiddleware(middleware func(http.Handler) http.Handler) Middleware {
+	return func(ctx Context) error {
+		if hi, ok := AsHTTPInterop(ctx); ok {
+			return serveHTTPInterop(ctx, hi, middleware)
+		}
+		return serveHTTPEmulated(ctx, middleware)
+	}
+}
+
+func serveHTTPInterop(ctx Context, hi HTTPInterop, middleware func(http.Handler) http.Handler) error {
+	var err error
+	writer := hi.HTTPResponseWriter()
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hi.SetHTTPRequest(r)
+		hi.SetHTTPResponseWriter(w)
+		defer hi.SetHTTPResponseWriter(writer)
+		err = ctx.Next()
+	})
+	middleware(next).ServeHTTP(writer, hi.HTTPRequest())
+	return err
+}
+
+func serveHTTPEmulated(ctx Context, middleware func(http.Handler) http.Handler) error {
+	req, err := emulatedRequest(ctx)
+	if err != nil {
+		return err
+	}
+	rec := &recordingWriter{header: make(http.Header)}
+	called := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		applyHeaders(ctx, rec.header)
+		ctx.SetContext(r.Context())
+		err = ctx.Next()
+	})
+	middleware(next).ServeHTTP(rec, req)
+	if called {
+		return err
+	}
+	applyHeaders(ctx, rec.header)
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	if rec.body.Len() == 0 {
+		return ctx.NoContent(rec.status)
+	}
+	return ctx.Bytes(rec.status, rec.body.Bytes(), rec.header.Get("Content-Type"))
+}
+
+// emulatedRequest builds a net/http request mirroring ctx. The body is
+// buffered so it can still be read by downstream handlers.
+func emulatedRequest(ctx Context) (*http.Request, error) {
+	body, err := ctx.BodyRaw()
+	if err != nil {
+		return nil, err
+	}
+	req, err := newEmulatedRequest(ctx, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = int64(len(body))
+	return req, nil
+}
+
+// newEmulatedRequest builds a net/http request mirroring ctx that reads its
+// body from body. RemoteAddr carries port 0, since the port is unknown.
+func newEmulatedRequest(ctx Context, body io.Reader) (*http.Request, error) {
+	target := ctx.Path()
+	if raw := ctx.RawQuery(); raw != "" {
+		target += "?" + raw
+	}
+	req, err := http.NewRequestWithContext(ctx.Context(), ctx.Method(), target, body)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range ctx.Headers() {
+		req.Header[key] = values
+	}
+	req.Host = ctx.Header("Host")
+	if req.Host == "" {
+		// Requests in absolute form may carry the host only in the target.
+		req.Host = ctx.Host()
+	}
+	req.RemoteAddr = net.JoinHostPort(ctx.ClientIP(), "0")
+	return req, nil
+}
+
+func applyHeaders(ctx Context, header http.Header) {
+	for key, values := range header {
+		if key == "Set-Cookie" {
+			for _, value := range values {
+				if cookie, err := http.ParseSetCookie(value); err == nil {
+					ctx.SetCookie(cookie)
+				}
+			}
+			continue
+		}
+		if len(values) > 0 {
+			// Contexts set one value per header, so repeated values are
+			// combined into a list as RFC 9110 allows for all but
+			// Set-Cookie.
+			ctx.SetHeader(key, strings.Join(values, ", "))
+		}
+	}
+}
+
+// recordingWriter captures the response of emulated net/http middleware.
+type recordingWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (w *recordingWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *recordingWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.body.Write(b)
+}
@@ -0,0 +1,154 @@
+// Package httpxtest provides utilities for testing httpx handlers and
+// middleware without an engine.
+//
+//	req := httptest.NewRequest(http.MethodGet, "/users/7", nil)
+//	ctx, rec := httpxtest.NewContext(req)
+//	ctx.SetParam("id", "7")
+//	if err := getUser(ctx); err != nil {
+//		t.Fatal(err)
+//	}
+//	if rec.StatusCode() != http.StatusOK {
+//		t.Fatalf("status = %d", rec.StatusCode())
+//	}
+package httpxtest
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/go-sphere/httpx"
+)
+
+// handlerContext names the embedded context of MockContext, whose field
+// could not be called Context next to the Context method.
+type handlerContext = httpx.Context
+
+// MockContext is an httpx.Context for calling handlers and middleware
+// directly. Requests are read and responses written like with
+// httpx.ToHTTPHandler; route parameters and the rest of the chain are set by
+// the test.
+type MockContext struct {
+	handlerContext
+	fullPath   string
+	params     map[string]string
+	next       httpx.Handler
+	nextCalled bool
+	aborted    bool
+}
+
+var (
+	_ httpx.Context       = (*MockContext)(nil)
+	_ httpx.ResponseInfo  = (*MockContext)(nil)
+	_ httpx.ResponseSizer = (*MockContext)(nil)
+	_ httpx.ChainContext  = (*MockContext)(nil)
+	_ httpx.Aborter       = (*MockContext)(nil)
+)
+
+// NewContext returns a MockContext for req and the recorder of its response.
+func NewContext(req *http.Request) (*MockContext, *ResponseRecorder) {
+	rec := httptest.NewRecorder()
+	inner := httpx.NewContext(rec, req)
+	ctx := &MockContext{
+		handlerContext: inner,
+		fullPath:       inner.FullPath(),
+		params:         inner.Params(),
+	}
+	return ctx, &ResponseRecorder{rec: rec, ctx: inner}
+}
+
+// SetParam sets a route parameter.
+func (c *MockContext) SetParam(key, value string) {
+	if c.params == nil {
+		c.params = make(map[string]string)
+	}
+	c.params[key] = value
+}
+
+// SetFullPath sets the route pattern reported by FullPath, such as
+// "/users/:id".
+func (c *MockContext) SetFullPath(path string) {
+	c.fullPath = path
+}
+
+// SetNext sets the handler Next runs, standing in for the rest of the chain
+// when testing middleware. Without it, Next is a no-op.
+func (c *MockContext) SetNext(h httpx.Handler) {
+	c.next = h
+}
+
+// NextCalled reports whether Next was called.
+func (c *MockContext) NextCalled() bool {
+	return c.nextCalled
+}
+
+func (c *MockContext) FullPath() string {
+	return c.fullPath
+}
+
+func (c *MockContext) Param(key string) string {
+	return c.params[key]
+}
+
+func (c *MockContext) Params() map[string]string {
+	if len(c.params) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(c.params))
+	for k, v := range c.params {
+		out[k] = v
+	}
+	return out
+}
+
+func (c *MockContext) BindURI(dst any) error {
+	values := make(map[string][]string, len(c.params))
+	for k, v := range c.params {
+		values[k] = []string{v}
+	}
+	return httpx.BindValues(dst, "uri", values)
+}
+
+func (c *MockContext) Next() error {
+	if c.aborted {
+		return nil
+	}
+	c.nextCalled = true
+	if c.next == nil {
+		return nil
+	}
+	return c.next(c)
+}
+
+// Aborter (httpx.Aborter)
+
+func (c *MockContext) Abort() {
+	c.aborted = true
+	c.Set(httpx.AbortedKey, true)
+}
+
+func (c *MockContext) AbortWithStatus(code int) error {
+	c.Abort()
+	return c.NoContent(code)
+}
+
+func (c *MockContext) AbortWithStatusJSON(code int, obj any) error {
+	c.Abort()
+	return c.JSON(code, obj)
+}
+
+func (c *MockContext) IsAborted() bool {
+	_, ok := c.Get(httpx.AbortedKey)
+	return ok
+}
+
+// ResponseInfo and ResponseSizer (httpx.ResponseInfo, httpx.ResponseSizer)
+
+func (c *MockContext) StatusCode() int {
+	info, _ := httpx.AsResponseInfo(c.handlerContext)
+	return info.StatusCode()
+}
+
+func (c *MockContext) BodySize() int {
+	sizer, _ := httpx.AsResponseSizer(c.handlerContext)
+	return sizer.BodySize()
+}
@@ -0,0 +1,107 @@
+package httpxtest
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-sphere/httpx"
+)
+
+func TestNewContextHandler(t *testing.T) {
+	type updateUser struct {
+		ID   int    `uri:"id" binding:"required"`
+		Name string `json:"name"`
+	}
+	handler := func(ctx httpx.Context) error {
+		var in updateUser
+		if err := ctx.BindURI(&in); err != nil {
+			return err
+		}
+		if err := ctx.BindJSON(&in); err != nil {
+			return err
+		}
+		ctx.SetHeader("X-Route", ctx.FullPath())
+		return ctx.JSON(http.StatusAccepted, in)
+	}
+
+	req := httptest.NewRequest(http.MethodPut, "/users/7", strings.NewReader(`{"name":"gopher"}`))
+	req.Header.Set("Content-Type", "application/json")
+	ctx, rec := NewContext(req)
+	ctx.SetFullPath("/users/:id")
+	ctx.SetParam("id", "7")
+	if err := handler(ctx); err != nil {
+		t.Fatalf("handler: %v", err)
+	}
+	if rec.StatusCode() != http.StatusAccepted || rec.Header().Get("X-Route") != "/users/:id" {
+		t.Fatalf("response = %d %v", rec.StatusCode(), rec.Header())
+	}
+	var got updateUser
+	if err := rec.DecodeJSON(&got); err != nil || got != (updateUser{ID: 7, Name: "gopher"}) {
+		t.Fatalf("body = %s (%v)", rec.BodyString(), err)
+	}
+
+	ctx, _ = NewContext(httptest.NewRequest(http.MethodPut, "/users/", nil))
+	var missing *httpx.MissingFieldsError
+	if err := handler(ctx); !errors.As(err, &missing) {
+		t.Fatalf("handler without id = %v, want *httpx.MissingFieldsError", err)
+	}
+}
+
+func TestNewContextMiddleware(t *testing.T) {
+	middleware := func(ctx httpx.Context) error {
+		ctx.Set("user", "gopher")
+		err := ctx.Next()
+		if info, ok := httpx.AsResponseInfo(ctx); ok {
+			ctx.SetHeader("X-Status", http.StatusText(info.StatusCode()))
+		}
+		return err
+	}
+
+	ctx, rec := NewContext(httptest.NewRequest(http.MethodGet, "/", nil))
+	if err := middleware(ctx); err != nil || !ctx.NextCalled() {
+		t.Fatalf("middleware = %v, next called %v", err, ctx.NextCalled())
+	}
+
+	ctx, rec = NewContext(httptest.NewRequest(http.MethodGet, "/", nil))
+	ctx.SetNext(func(ctx httpx.Context) error {
+		user, _ := ctx.Get("user")
+		ctx.Status(http.StatusCreated)
+		ctx.SetHeader("X-User", user.(string))
+		return nil
+	})
+	if err := middleware(ctx); err != nil {
+		t.Fatalf("middleware: %v", err)
+	}
+	if rec.StatusCode() != http.StatusCreated || rec.Header().Get("X-User") != "gopher" || rec.Header().Get("X-Status") != "Created" {
+		t.Fatalf("response = %d %v", rec.StatusCode(), rec.Header())
+	}
+}
+
+func TestNewContextAbort(t *testing.T) {
+	auth := func(ctx httpx.Context) error {
+		if ctx.Header("Authorization") == "" {
+			a, _ := httpx.AsAborter(ctx)
+			if err := a.AbortWithStatusJSON(http.StatusUnauthorized, httpx.H{"error": "unauthorized"}); err != nil {
+				return err
+			}
+		}
+		return ctx.Next()
+	}
+	ctx, rec := NewContext(httptest.NewRequest(http.MethodGet, "/", nil))
+	ctx.SetNext(func(ctx httpx.Context) error {
+		t.Fatal("handler ran after Abort")
+		return nil
+	})
+	if err := auth(ctx); err != nil {
+		t.Fatalf("middleware: %v", err)
+	}
+	if ctx.NextCalled() || !ctx.IsAborted() {
+		t.Fatalf("NextCalled = %v, IsAborted = %v", ctx.NextCalled(), ctx.IsAborted())
+	}
+	if rec.StatusCode() != http.StatusUnauthorized || !strings.Contains(string(rec.Body()), "unauthorized") {
+		t.Fatalf("response = %d %q", rec.StatusCode(), rec.Body())
+	}
+}
@@ -0,0 +1,132 @@
+package httpxtest
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/go-sphere/httpx"
+)
+
+// ResponseRecorder captures a response: its status, headers, cookies and
+// body. It is returned by NewContext for the response written through the
+// MockContext, and by NewRecorder on its own.
+//
+// ResponseRecorder implements httpx.Responder, httpx.ResponseInfo and
+// httpx.ResponseSizer, so helpers and middleware written against those
+// abstractions can be tested without any framework.
+//
+// Accessors of the written response commit it first, so a status set with
+// Status but no body is reported as sent.
+type ResponseRecorder struct {
+	rec *httptest.ResponseRecorder
+	ctx httpx.Context
+}
+
+var (
+	_ httpx.Responder     = (*ResponseRecorder)(nil)
+	_ httpx.ResponseInfo  = (*ResponseRecorder)(nil)
+	_ httpx.ResponseSizer = (*ResponseRecorder)(nil)
+)
+
+// NewRecorder returns a ResponseRecorder for a GET request to "/".
+func NewRecorder() *ResponseRecorder {
+	_, rec := NewContext(httptest.NewRequest(http.MethodGet, "/", nil))
+	return rec
+}
+
+func (r *ResponseRecorder) commit() {
+	if f, ok := httpx.AsFlusher(r.ctx); ok {
+		_ = f.Flush()
+	}
+}
+
+// Header returns the headers of the response.
+func (r *ResponseRecorder) Header() http.Header {
+	return r.Result().Header
+}
+
+// Cookies returns the cookies set by the response.
+func (r *ResponseRecorder) Cookies() []*http.Cookie {
+	return r.Result().Cookies()
+}
+
+// Body returns the body of the response.
+func (r *ResponseRecorder) Body() []byte {
+	r.commit()
+	return r.rec.Body.Bytes()
+}
+
+// BodyString returns the body of the response as a string.
+func (r *ResponseRecorder) BodyString() string {
+	return string(r.Body())
+}
+
+// DecodeJSON decodes the body of the response into v.
+func (r *ResponseRecorder) DecodeJSON(v any) error {
+	return json.Unmarshal(r.Body(), v)
+}
+
+// Result returns the response, as received by a client.
+func (r *ResponseRecorder) Result() *http.Response {
+	r.commit()
+	return r.rec.Result()
+}
+
+// ResponseInfo and ResponseSizer (httpx.ResponseInfo, httpx.ResponseSizer)
+
+// StatusCode returns the status of the response, including one set with
+// Status that is not sent yet.
+func (r *ResponseRecorder) StatusCode() int {
+	info, _ := httpx.AsResponseInfo(r.ctx)
+	return info.StatusCode()
+}
+
+// BodySize returns the number of body bytes written.
+func (r *ResponseRecorder) BodySize() int {
+	sizer, _ := httpx.AsResponseSizer(r.ctx)
+	return sizer.BodySize()
+}
+
+// Responder (httpx.Responder)
+
+func (r *ResponseRecorder) Status(code int) {
+	r.ctx.Status(code)
+}
+
+func (r *ResponseRecorder) SetHeader(key, value string) {
+	r.ctx.SetHeader(key, value)
+}
+
+func (r *ResponseRecorder) SetCookie(cookie *http.Cookie) {
+	r.ctx.SetCookie(cookie)
+}
+
+func (r *ResponseRecorder) JSON(code int, v any) error {
+	return r.ctx.JSON(code, v)
+}
+
+func (r *ResponseRecorder) Text(code int, s string) error {
+	return r.ctx.Text(code, s)
+}
+
+func (r *ResponseRecorder) NoContent(code int) error {
+	return r.ctx.NoContent(code)
+}
+
+func (r *ResponseRecorder) Bytes(code int, b []byte, contentType string) error {
+	return r.ctx.Bytes(code, b, contentType)
+}
+
+func (r *ResponseRecorder) DataFromReader(code int, contentType string, reader io.Reader, size int) error {
+	return r.ctx.DataFromReader(code, contentType, reader, size)
+}
+
+func (r *ResponseRecorder) File(path string) error {
+	return r.ctx.File(path)
+}
+
+func (r *ResponseRecorder) Redirect(code int, location string) error {
+	return r.ctx.Redirect(code, location)
+}
@@ -0,0 +1,53 @@
+package httpxtest
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-sphere/httpx"
+)
+
+// writeCacheable is a helper written against the Responder abstraction.
+func writeCacheable(r httpx.Responder, etag string, body string) error {
+	r.SetHeader("ETag", etag)
+	r.SetHeader("Cache-Control", "max-age=60")
+	r.SetCookie(&http.Cookie{Name: "visited", Value: "1", HttpOnly: true})
+	return r.DataFromReader(http.StatusOK, "text/plain", strings.NewReader(body), len(body))
+}
+
+func TestResponseRecorder(t *testing.T) {
+	rec := NewRecorder()
+	if err := writeCacheable(rec, `"v1"`, "hello"); err != nil {
+		t.Fatalf("writeCacheable: %v", err)
+	}
+	if rec.StatusCode() != http.StatusOK || rec.BodyString() != "hello" || rec.BodySize() != 5 {
+		t.Fatalf("response = %d %q (%d bytes)", rec.StatusCode(), rec.BodyString(), rec.BodySize())
+	}
+	if h := rec.Header(); h.Get("ETag") != `"v1"` || h.Get("Cache-Control") != "max-age=60" || h.Get("Content-Type") != "text/plain" {
+		t.Fatalf("headers = %v", h)
+	}
+	cookies := rec.Cookies()
+	if len(cookies) != 1 || cookies[0].Name != "visited" || !cookies[0].HttpOnly {
+		t.Fatalf("cookies = %v", cookies)
+	}
+}
+
+func TestResponseRecorderPendingStatus(t *testing.T) {
+	rec := NewRecorder()
+	rec.Status(http.StatusAccepted)
+	if rec.StatusCode() != http.StatusAccepted || rec.BodySize() != 0 {
+		t.Fatalf("pending status = %d, size %d", rec.StatusCode(), rec.BodySize())
+	}
+	if resp := rec.Result(); resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("committed status = %d", resp.StatusCode)
+	}
+
+	rec = NewRecorder()
+	if err := rec.Redirect(http.StatusFound, "/login"); err != nil {
+		t.Fatalf("Redirect: %v", err)
+	}
+	if rec.StatusCode() != http.StatusFound || rec.Header().Get("Location") != "/login" {
+		t.Fatalf("redirect = %d %v", rec.StatusCode(), rec.Header())
+	}
+}
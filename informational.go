@@ -0,0 +1,73 @@
+package httpx
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrResponseCommitted reports an informational response attempted after the
+// final response header was written.
+var ErrResponseCommitted = errors.New("httpx: response already committed")
+
+// InformationalWriter sends 1xx informational responses ahead of the final
+// response.
+//
+// This optional capability is provided by contexts backed by net/http, such
+// as those of ginx, echox and ToHTTPHandler. fiber and hertz cannot send
+// informational responses.
+type InformationalWriter interface {
+	// WriteInformational sends an informational response with the given
+	// status, between 100 and 199 except 101, and headers. The headers are
+	// not added to the final response. It returns ErrResponseCommitted once
+	// the final response header was written.
+	WriteInformational(code int, header http.Header) error
+}
+
+// AsInformationalWriter returns informational response capability when
+// supported.
+func AsInformationalWriter(ctx Context) (InformationalWriter, bool) {
+	iw, ok := ctx.(InformationalWriter)
+	return iw, ok
+}
+
+// EarlyHints sends a 103 Early Hints response with one Link header value per
+// entry of links, such as `</app.css>; rel=preload; as=style`, so clients
+// can start fetching resources while the handler prepares the response. It
+// is a no-op on contexts without InformationalWriter.
+func EarlyHints(ctx Context, links []string) error {
+	iw, ok := AsInformationalWriter(ctx)
+	if !ok || len(links) == 0 {
+		return nil
+	}
+	return iw.WriteInformational(http.StatusEarlyHints, http.Header{"Link": links})
+}
+
+// WriteHTTPInformational sends an informational response on w, which must
+// not have written its final header, with header temporarily added to the
+// header map of w. Adapters backed by net/http use it to implement
+// InformationalWriter.
+func WriteHTTPInformational(w http.ResponseWriter, code int, header http.Header) error {
+	if code < 100 || code > 199 || code == http.StatusSwitchingProtocols {
+		return fmt.Errorf("httpx: invalid informational status %d", code)
+	}
+	dst := w.Header()
+	saved := make(http.Header, len(header))
+	for key, values := range header {
+		key = http.CanonicalHeaderKey(key)
+		if old, ok := dst[key]; ok {
+			saved[key] = old
+		}
+		dst[key] = values
+	}
+	w.WriteHeader(code)
+	for key := range header {
+		key = http.CanonicalHeaderKey(key)
+		if old, ok := saved[key]; ok {
+			dst[key] = old
+		} else {
+			delete(dst, key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package httpx
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+)
+
+// JSONOmitPolicy selects the object members JSONOptions drops from
+// responses.
+type JSONOmitPolicy int
+
+const (
+	// JSONKeepAll keeps every member, as encoding/json does.
+	JSONKeepAll JSONOmitPolicy = iota
+	// JSONOmitNull drops the members whose value is null.
+	JSONOmitNull
+	// JSONOmitEmpty drops the members whose value is null, false, 0, "",
+	// [] or {}, like an omitempty tag on every field and map entry.
+	JSONOmitEmpty
+)
+
+// JSONOptions shape the JSON bodies an engine writes with Context.JSON, so
+// that responses look the same whichever framework's encoder the adapter
+// would use. Unlike encoding/json, strings are not HTML-escaped unless
+// EscapeHTML is set.
+type JSONOptions struct {
+	// Omit selects the object members dropped from the body.
+	Omit JSONOmitPolicy
+	// FieldName, if set, maps the names of object members, those of map
+	// entries included, such as SnakeCase.
+	FieldName func(name string) string
+	// EscapeHTML escapes <, > and & in strings, for bodies embedded in
+	// HTML.
+	EscapeHTML bool
+}
+
+// Marshal returns the JSON encoding of v shaped by o.
+func (o JSONOptions) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(o.EscapeHTML)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	body := bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+	if o.Omit == JSONKeepAll && o.FieldName == nil {
+		return body, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var out bytes.Buffer
+	if _, err := o.reshape(dec, &out); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// reshape copies the next value of dec to out, renaming and dropping object
+// members, and reports whether the value counts as empty for JSONOmitEmpty.
+func (o JSONOptions) reshape(dec *json.Decoder, out *bytes.Buffer) (empty bool, err error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return false, err
+	}
+	switch tok := tok.(type) {
+	case json.Delim:
+		if tok == '[' {
+			out.WriteByte('[')
+			n := 0
+			for dec.More() {
+				if n > 0 {
+					out.WriteByte(',')
+				}
+				if _, err := o.reshape(dec, out); err != nil {
+					return false, err
+				}
+				n++
+			}
+			out.WriteByte(']')
+			_, err = dec.Token()
+			return n == 0, err
+		}
+		out.WriteByte('{')
+		n := 0
+		var member bytes.Buffer
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return false, err
+			}
+			name, _ := key.(string)
+			member.Reset()
+			isEmpty, err := o.reshape(dec, &member)
+			if err != nil {
+				return false, err
+			}
+			if o.omits(member.Bytes(), isEmpty) {
+				continue
+			}
+			if n > 0 {
+				out.WriteByte(',')
+			}
+			if o.FieldName != nil {
+				name = o.FieldName(name)
+			}
+			if err := o.writeString(out, name); err != nil {
+				return false, err
+			}
+			out.WriteByte(':')
+			out.Write(member.Bytes())
+			n++
+		}
+		out.WriteByte('}')
+		_, err = dec.Token()
+		return n == 0, err
+	case string:
+		return tok == "", o.writeString(out, tok)
+	case json.Number:
+		out.WriteString(tok.String())
+		f, err := strconv.ParseFloat(tok.String(), 64)
+		return err == nil && f == 0, nil
+	case bool:
+		out.WriteString(strconv.FormatBool(tok))
+		return !tok, nil
+	case nil:
+		out.WriteString("null")
+		return true, nil
+	}
+	return false, fmt.Errorf("httpx: unexpected JSON token %v", tok)
+}
+
+func (o JSONOptions) omits(value []byte, empty bool) bool {
+	switch o.Omit {
+	case JSONOmitNull:
+		return string(value) == "null"
+	case JSONOmitEmpty:
+		return empty
+	}
+	return false
+}
+
+func (o JSONOptions) writeString(out *bytes.Buffer, s string) error {
+	enc := json.NewEncoder(out)
+	enc.SetEscapeHTML(o.EscapeHTML)
+	if err := enc.Encode(s); err != nil {
+		return err
+	}
+	out.Truncate(out.Len() - 1)
+	return nil
+}
+
+// SnakeCase maps a field name such as "UserID" or "createdAt" to snake
+// case, "user_id" and "created_at", for JSONOptions.FieldName. Names in
+// snake case are returned unchanged.
+func SnakeCase(name string) string {
+	var b []byte
+	prev := rune(-1)
+	for i, r := range name {
+		if !unicode.IsUpper(r) {
+			b = utf8.AppendRune(b, r)
+			prev = r
+			continue
+		}
+		next, _ := utf8.DecodeRuneInString(name[i+utf8.RuneLen(r):])
+		if prev >= 0 && prev != '_' && (unicode.IsLower(prev) || unicode.IsDigit(prev) || unicode.IsLower(next)) {
+			b = append(b, '_')
+		}
+		b = utf8.AppendRune(b, unicode.ToLower(r))
+		prev = r
+	}
+	return string(b)
+}
+
+// JSONOptionsKey holds the JSONOptions set by WithJSONOptions, which the
+// JSON methods of adapter contexts read back.
+var JSONOptionsKey = NewKey[JSONOptions]("json options")
+
+// WithJSONOptions makes Context.JSON encode bodies with opts for the
+// requests it runs for. The adapters install it with their WithJSONOptions
+// options.
+func WithJSONOptions(opts JSONOptions) Middleware {
+	return func(ctx Context) error {
+		SetTyped(ctx, JSONOptionsKey, opts)
+		return ctx.Next()
+	}
+}
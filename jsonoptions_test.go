@@ -0,0 +1,74 @@
+package httpx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestJSONOptionsMarshal(t *testing.T) {
+	type profile struct {
+		AvatarURL string
+		Tags      []string
+		Extra     map[string]any
+	}
+	v := struct {
+		UserID    int
+		Name      string `json:"displayName"`
+		Bio       string
+		Admin     bool
+		Profile   *profile
+		Deleted   *profile
+		CreatedAt string
+	}{UserID: 7, Name: "<b>", Profile: &profile{AvatarURL: "a.png", Extra: map[string]any{"theme": nil}}}
+
+	tests := []struct {
+		name string
+		opts JSONOptions
+		want string
+	}{
+		{"keep all", JSONOptions{},
+			`{"UserID":7,"displayName":"<b>","Bio":"","Admin":false,"Profile":{"AvatarURL":"a.png","Tags":null,"Extra":{"theme":null}},"Deleted":null,"CreatedAt":""}`},
+		{"escape html", JSONOptions{EscapeHTML: true, Omit: JSONOmitEmpty},
+			`{"UserID":7,"displayName":"\u003cb\u003e","Profile":{"AvatarURL":"a.png"}}`},
+		{"omit null", JSONOptions{Omit: JSONOmitNull, FieldName: SnakeCase},
+			`{"user_id":7,"display_name":"<b>","bio":"","admin":false,"profile":{"avatar_url":"a.png","extra":{}},"created_at":""}`},
+	}
+	for _, tc := range tests {
+		got, err := tc.opts.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != tc.want {
+			t.Fatalf("%s: Marshal() = %s\nwant %s", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	for in, want := range map[string]string{
+		"UserID":     "user_id",
+		"createdAt":  "created_at",
+		"HTTPServer": "http_server",
+		"Page2Size":  "page2_size",
+		"user_id":    "user_id",
+		"ID":         "id",
+		"":           "",
+	} {
+		if got := SnakeCase(in); got != want {
+			t.Errorf("SnakeCase(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestWithJSONOptions(t *testing.T) {
+	rec := httptest.NewRecorder()
+	ctx := newHTTPContext(rec, httptest.NewRequest(http.MethodGet, "/", nil), nil)
+	SetTyped(ctx, JSONOptionsKey, JSONOptions{Omit: JSONOmitNull, FieldName: SnakeCase})
+	if err := ctx.JSON(http.StatusOK, map[string]any{"nextPage": nil, "pageSize": 10}); err != nil {
+		t.Fatal(err)
+	}
+	if got := rec.Body.String(); got != `{"page_size":10}` {
+		t.Fatalf("body = %s", got)
+	}
+}
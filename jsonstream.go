@@ -0,0 +1,60 @@
+package httpx
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"iter"
+)
+
+// JSONArrayStream responds with status code and a JSON array of the values
+// of seq, written as seq yields them rather than buffered, for exports too
+// large to hold in memory:
+//
+//	return httpx.JSONArrayStream(ctx, http.StatusOK, func(yield func(Row) bool) {
+//		for rows.Next() {
+//			var r Row
+//			if rows.Scan(&r.ID, &r.Name) != nil || !yield(r) {
+//				return
+//			}
+//		}
+//	})
+//
+// Values are encoded with the JSONOptions of the engine, if any, and sent in
+// chunks through DataFromReader, so seq must not use ctx: fiber and hertz
+// send the body after the handler returns. A value that cannot be encoded
+// ends the body early, leaving the client an unterminated array.
+func JSONArrayStream[T any](ctx Context, code int, seq iter.Seq[T]) error {
+	marshal := json.Marshal
+	if opts, ok := JSONOptionsKey.Get(ctx); ok {
+		marshal = opts.Marshal
+	}
+	pr, pw := io.Pipe()
+	go func() {
+		_ = pw.CloseWithError(writeJSONArray(pw, seq, marshal))
+	}()
+	return ctx.DataFromReader(code, "application/json; charset=utf-8", pr, -1)
+}
+
+func writeJSONArray[T any](w io.Writer, seq iter.Seq[T], marshal func(any) ([]byte, error)) error {
+	bw := bufio.NewWriter(w)
+	_ = bw.WriteByte('[')
+	first := true
+	for v := range seq {
+		body, err := marshal(v)
+		if err != nil {
+			return err
+		}
+		if !first {
+			_ = bw.WriteByte(',')
+		}
+		first = false
+		// bufio keeps the first write error, which ends the stream once the
+		// client is gone.
+		if _, err := bw.Write(body); err != nil {
+			return err
+		}
+	}
+	_ = bw.WriteByte(']')
+	return bw.Flush()
+}
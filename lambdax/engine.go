@@ -0,0 +1,355 @@
+// Package lambdax serves httpx routers on AWS Lambda behind API Gateway HTTP
+// APIs (payload format 2.0) and Lambda function URLs.
+package lambdax
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/go-sphere/httpx"
+)
+
+var _ httpx.Engine = (*Engine)(nil)
+
+// ErrNotInLambda is returned by Start outside the AWS Lambda runtime.
+var ErrNotInLambda = errors.New("lambdax: AWS_LAMBDA_RUNTIME_API is not set")
+
+// ErrTLSUnsupported is returned by StartTLS: API Gateway terminates TLS.
+var ErrTLSUnsupported = errors.New("lambdax: TLS is terminated by API Gateway")
+
+type Config struct {
+	errHandler      httpx.ErrorHandler
+	lambdaOptions   []lambda.Option
+	runtimeRoutes   bool
+	basePath        string
+	panicPolicy     httpx.PanicPolicy
+	errorPages      fs.FS
+	jsonOptions     *httpx.JSONOptions
+	envelope        httpx.EnvelopeFunc
+	requestTimeout  time.Duration
+	multipartMemory int64
+}
+
+type Option func(*Config)
+
+func NewConfig(opts ...Option) *Config {
+	conf := Config{}
+	for _, opt := range opts {
+		opt(&conf)
+	}
+	if conf.errHandler == nil {
+		conf.errHandler = func(ctx httpx.Context, err error) {
+			err = httpx.LocalizeError(ctx, err)
+			var ve *httpx.ValidationError
+			if errors.As(err, &ve) {
+				_ = ctx.JSON(http.StatusUnprocessableEntity, ve)
+				return
+			}
+			_ = ctx.JSON(http.StatusInternalServerError, httpx.H{
+				"error": err.Error(),
+			})
+		}
+	}
+	return &conf
+}
+
+func WithErrorHandler(errHandler httpx.ErrorHandler) Option {
+	return func(conf *Config) {
+		conf.errHandler = errHandler
+	}
+}
+
+// WithLambdaOptions appends options passed to lambda.StartWithOptions.
+func WithLambdaOptions(opts ...lambda.Option) Option {
+	return func(conf *Config) {
+		conf.lambdaOptions = append(conf.lambdaOptions, opts...)
+	}
+}
+
+// WithRequestTimeout gives the context of each request a deadline timeout
+// after it starts, see httpx.RequestTimeout. The deadline of the Lambda
+// invocation still applies.
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(conf *Config) {
+		conf.requestTimeout = timeout
+	}
+}
+
+// WithMultipartMemoryLimit sets how many bytes of a multipart form
+// MultipartForm and FormFile keep in memory; larger file parts are written
+// to temporary files, see httpx.WithHTTPMultipartMemory.
+func WithMultipartMemoryLimit(bytes int64) Option {
+	return func(conf *Config) {
+		conf.multipartMemory = bytes
+	}
+}
+
+// WithAllowRuntimeRoutes keeps the routes open to registration after Start,
+// which otherwise freezes them, see httpx.Engine.Freeze. The http.ServeMux
+// serving the routes is safe for concurrent registration.
+func WithAllowRuntimeRoutes(allowed bool) Option {
+	return func(conf *Config) {
+		conf.runtimeRoutes = allowed
+	}
+}
+
+// WithBasePath prefixes the routes of every group created by the engine with
+// path, for services mounted under a path by a gateway. Router.BasePath,
+// FullPath and the routes reported to OnRouteRegistered include it.
+func WithBasePath(path string) Option {
+	return func(conf *Config) {
+		conf.basePath = path
+	}
+}
+
+// WithPanicPolicy sets what happens when a handler or middleware panics,
+// see httpx.PanicPolicy. The default, httpx.PanicPropagate, leaves panics to
+// the framework.
+func WithPanicPolicy(policy httpx.PanicPolicy) Option {
+	return func(conf *Config) {
+		conf.panicPolicy = policy
+	}
+}
+
+// WithHTMLErrorPages renders error responses as the static HTML pages of
+// pages for browsers, such as "404.html" and "500.html", see
+// httpx.HTMLErrorPages. Other clients get the response of the error handler.
+func WithHTMLErrorPages(pages fs.FS) Option {
+	return func(conf *Config) {
+		conf.errorPages = pages
+	}
+}
+
+// WithEnvelope makes httpx.OK, httpx.Created and httpx.EnvelopeErrorHandler
+// write the body returned by format instead of the httpx.Envelope, see
+// httpx.WithEnvelope.
+func WithEnvelope(format httpx.EnvelopeFunc) Option {
+	return func(conf *Config) {
+		conf.envelope = format
+	}
+}
+
+// WithJSONOptions makes the engine encode the bodies written with JSON
+// using opts instead of the framework's encoder, see httpx.JSONOptions.
+func WithJSONOptions(opts httpx.JSONOptions) Option {
+	return func(conf *Config) {
+		conf.jsonOptions = &opts
+	}
+}
+
+// Engine routes API Gateway v2 HTTP events to httpx handlers. Routes are
+// served by an http.ServeMux using httpx.ServeMuxPattern, so Engine is also
+// an http.Handler that can be exercised locally.
+type Engine struct {
+	mux             atomic.Pointer[http.ServeMux]
+	middlewares     []httpx.Middleware
+	errHandler      httpx.ErrorHandler
+	lambdaOptions   []lambda.Option
+	hooks           *httpx.Hooks
+	running         atomic.Bool
+	runtimeRoutes   bool
+	basePath        string
+	multipartMemory int64
+	errorPages      fs.FS
+}
+
+func New(opts ...Option) httpx.Engine {
+	conf := NewConfig(opts...)
+	engine := &Engine{
+		errHandler:      conf.errHandler,
+		lambdaOptions:   conf.lambdaOptions,
+		runtimeRoutes:   conf.runtimeRoutes,
+		basePath:        conf.basePath,
+		multipartMemory: conf.multipartMemory,
+		errorPages:      conf.errorPages,
+		hooks:           &httpx.Hooks{},
+	}
+	engine.mux.Store(http.NewServeMux())
+	if conf.jsonOptions != nil {
+		engine.Use(httpx.WithJSONOptions(*conf.jsonOptions))
+	}
+	if conf.envelope != nil {
+		engine.Use(httpx.WithEnvelope(conf.envelope))
+	}
+	if conf.errorPages != nil {
+		engine.Use(httpx.HTMLErrorPages(conf.errorPages))
+	}
+	if conf.panicPolicy != httpx.PanicPropagate {
+		engine.Use(httpx.RecoverPanics(conf.panicPolicy))
+	}
+	if conf.requestTimeout > 0 {
+		engine.Use(httpx.RequestTimeout(conf.requestTimeout))
+	}
+	return engine
+}
+
+// Use registers global middleware. Like gin, it applies to routes registered
+// after the call.
+func (e *Engine) Use(middleware ...httpx.Middleware) {
+	e.middlewares = append(e.middlewares, middleware...)
+}
+
+func (e *Engine) Group(prefix string, m ...httpx.Middleware) httpx.Router {
+	prefix = httpx.JoinPaths(e.basePath, prefix)
+	return &Router{
+		engine:      e,
+		mux:         e.mux.Load(),
+		basePath:    httpx.JoinPaths("/", prefix),
+		middlewares: m,
+	}
+}
+
+// ServeHTTP serves a net/http request through the registered routes.
+func (e *Engine) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	mux := e.mux.Load()
+	if e.errorPages != nil {
+		// Engine middleware only runs for matched routes, so unmatched
+		// requests get their error page here.
+		if h, pattern := mux.Handler(r); pattern == "" {
+			e.serveUnmatched(w, r, h)
+			return
+		}
+	}
+	mux.ServeHTTP(w, r)
+}
+
+// serveUnmatched serves the error page of an unmatched request, or the
+// response of the ServeMux handler h for clients that prefer another type.
+func (e *Engine) serveUnmatched(w http.ResponseWriter, r *http.Request, h http.Handler) {
+	fallback := httpx.FromHTTPHandler(h)
+	httpx.ToHTTPHandler(func(httpx.Context) error {
+		return httpx.NewNotFoundError(http.StatusText(http.StatusNotFound))
+	}, httpx.WithHTTPMiddleware(httpx.HTMLErrorPages(e.errorPages)), httpx.WithHTTPErrorHandler(func(ctx httpx.Context, _ error) {
+		_ = fallback(ctx)
+	})).ServeHTTP(w, r)
+}
+
+// HandleRequest translates an API Gateway v2 HTTP event into a request,
+// serves it, and translates the response back.
+func (e *Engine) HandleRequest(ctx context.Context, event events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+	req, err := newRequest(ctx, event)
+	if err != nil {
+		return events.APIGatewayV2HTTPResponse{}, err
+	}
+	w := newResponseWriter()
+	e.ServeHTTP(w, req)
+	// Without a net/http server, remove the temporary files of multipart
+	// forms here, as the function's disk outlives the invocation.
+	if req.MultipartForm != nil {
+		_ = req.MultipartForm.RemoveAll()
+	}
+	return w.response(), nil
+}
+
+// Start runs the Lambda runtime loop. It does not return while the function
+// is invoked, and returns ErrNotInLambda outside the Lambda environment.
+// SIGTERM sent by the runtime before shutdown triggers Stop.
+func (e *Engine) Start() error {
+	if os.Getenv("AWS_LAMBDA_RUNTIME_API") == "" {
+		return ErrNotInLambda
+	}
+	if err := e.hooks.RunStart(); err != nil {
+		return err
+	}
+	if !e.runtimeRoutes {
+		e.Freeze()
+	}
+	e.running.Store(true)
+	defer e.running.Store(false)
+	opts := append([]lambda.Option{lambda.WithEnableSIGTERM(func() {
+		_ = e.Stop(context.Background())
+	})}, e.lambdaOptions...)
+	lambda.StartWithOptions(e.HandleRequest, opts...)
+	return nil
+}
+
+// StartTLS returns ErrTLSUnsupported.
+func (e *Engine) StartTLS() error {
+	return ErrTLSUnsupported
+}
+
+// Stop runs the stop hooks. The Lambda runtime owns the process, so there
+// are no connections to drain.
+func (e *Engine) Stop(ctx context.Context) error {
+	e.hooks.RunStopping()
+	e.running.Store(false)
+	return e.hooks.RunStop(ctx)
+}
+
+// StopGraceful runs Stop and then drain.
+func (e *Engine) StopGraceful(ctx context.Context, drain func()) error {
+	err := e.Stop(ctx)
+	if drain != nil {
+		drain()
+	}
+	return err
+}
+
+// IsRunning returns true while the Lambda runtime loop is running.
+func (e *Engine) IsRunning() bool {
+	return e.running.Load()
+}
+
+// BoundAddr returns nil: Lambda functions do not listen on an address.
+func (e *Engine) BoundAddr() net.Addr {
+	return nil
+}
+
+// Test serves req in-process through the routes of the engine and returns the recorded
+// response.
+func (e *Engine) Test(req *http.Request) (*http.Response, error) {
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec.Result(), nil
+}
+
+// OnStart registers a hook that runs before the runtime loop starts.
+func (e *Engine) OnStart(fn func() error) {
+	e.hooks.OnStart(fn)
+}
+
+// OnStopping registers a hook that runs when Stop is called.
+func (e *Engine) OnStopping(fn func()) {
+	e.hooks.OnStopping(fn)
+}
+
+// OnStop registers a hook that runs after the stopping hooks.
+func (e *Engine) OnStop(fn func(ctx context.Context) error) {
+	e.hooks.OnStop(fn)
+}
+
+// OnRouteRegistered registers a hook invoked for every handler route.
+func (e *Engine) OnRouteRegistered(fn func(httpx.RouteInfo)) {
+	e.hooks.OnRouteRegistered(fn)
+}
+
+func (e *Engine) Freeze() {
+	e.hooks.Freeze()
+}
+
+// ReplaceRoutes implements httpx.RouteReplacer: register adds routes to a
+// new http.ServeMux, which then serves the requests that follow. Routers
+// obtained before keep registering on the previous ServeMux.
+func (e *Engine) ReplaceRoutes(register func(httpx.Router)) (err error) {
+	next := http.NewServeMux()
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("lambdax: replace routes: %v", r)
+		}
+	}()
+	e.hooks.ReplaceRoutes(func() {
+		register(&Router{engine: e, mux: next, basePath: httpx.JoinPaths("/", e.basePath)})
+	})
+	e.mux.Store(next)
+	return nil
+}
@@ -0,0 +1,121 @@
+package lambdax
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+type eventKey struct{}
+
+// EventFromContext returns the API Gateway event of the request being served,
+// for example to read authorizer claims.
+func EventFromContext(ctx context.Context) (events.APIGatewayV2HTTPRequest, bool) {
+	event, ok := ctx.Value(eventKey{}).(events.APIGatewayV2HTTPRequest)
+	return event, ok
+}
+
+// newRequest translates an API Gateway v2 HTTP event into a net/http request.
+func newRequest(ctx context.Context, event events.APIGatewayV2HTTPRequest) (*http.Request, error) {
+	body := []byte(event.Body)
+	if event.IsBase64Encoded {
+		decoded, err := base64.StdEncoding.DecodeString(event.Body)
+		if err != nil {
+			return nil, err
+		}
+		body = decoded
+	}
+
+	path := event.RawPath
+	if path == "" {
+		path = event.RequestContext.HTTP.Path
+	}
+	u := &url.URL{Path: path, RawQuery: event.RawQueryString}
+	if unescaped, err := url.PathUnescape(path); err == nil {
+		u.Path, u.RawPath = unescaped, path
+	}
+
+	ctx = context.WithValue(ctx, eventKey{}, event)
+	req, err := http.NewRequestWithContext(ctx, event.RequestContext.HTTP.Method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.URL = u
+	req.RequestURI = u.RequestURI()
+	for key, value := range event.Headers {
+		// API Gateway joins repeated headers with commas.
+		req.Header.Set(key, value)
+	}
+	if len(event.Cookies) > 0 {
+		req.Header.Set("Cookie", strings.Join(event.Cookies, "; "))
+	}
+	req.Host = req.Header.Get("Host")
+	if req.Host == "" {
+		req.Host = event.RequestContext.DomainName
+	}
+	req.RemoteAddr = event.RequestContext.HTTP.SourceIP
+	return req, nil
+}
+
+// responseWriter buffers a response for translation into an API Gateway
+// response.
+type responseWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newResponseWriter() *responseWriter {
+	return &responseWriter{header: make(http.Header)}
+}
+
+func (w *responseWriter) Header() http.Header {
+	return w.header
+}
+
+// WriteHeader records the first final status. API Gateway cannot relay
+// informational responses, so 1xx codes, such as early hints, are dropped.
+func (w *responseWriter) WriteHeader(code int) {
+	if w.status == 0 && code >= 200 {
+		w.status = code
+	}
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	return w.body.Write(b)
+}
+
+// response builds the API Gateway response. Set-Cookie headers map to the
+// cookies field, and bodies that are not valid UTF-8 text or are
+// content-encoded are base64 encoded.
+func (w *responseWriter) response() events.APIGatewayV2HTTPResponse {
+	w.WriteHeader(http.StatusOK)
+	resp := events.APIGatewayV2HTTPResponse{
+		StatusCode: w.status,
+		Cookies:    w.header.Values("Set-Cookie"),
+	}
+	for key, values := range w.header {
+		if key == "Set-Cookie" {
+			continue
+		}
+		if resp.Headers == nil {
+			resp.Headers = make(map[string]string, len(w.header))
+		}
+		resp.Headers[key] = strings.Join(values, ",")
+	}
+	body := w.body.Bytes()
+	if w.header.Get("Content-Encoding") != "" || !utf8.Valid(body) {
+		resp.Body = base64.StdEncoding.EncodeToString(body)
+		resp.IsBase64Encoded = true
+	} else {
+		resp.Body = string(body)
+	}
+	return resp
+}
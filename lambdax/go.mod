@@ -0,0 +1,10 @@
+module github.com/go-sphere/httpx/lambdax
+
+go 1.25.5
+
+require (
+	github.com/aws/aws-lambda-go v1.49.0
+	github.com/go-sphere/httpx v0.0.3
+)
+
+replace github.com/go-sphere/httpx => ../
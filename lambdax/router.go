@@ -0,0 +1,149 @@
+package lambdax
+
+import (
+	"io/fs"
+	"net/http"
+	"strings"
+
+	"github.com/go-sphere/httpx"
+)
+
+var _ httpx.Router = (*Router)(nil)
+
+type Router struct {
+	engine      *Engine
+	mux         *http.ServeMux
+	basePath    string
+	middlewares []httpx.Middleware
+}
+
+func (r *Router) Use(m ...httpx.Middleware) {
+	r.middlewares = append(r.middlewares, m...)
+}
+
+func (r *Router) BasePath() string {
+	return r.basePath
+}
+
+func (r *Router) SupportsRouterFeature(feature httpx.RouterFeature) bool {
+	switch feature {
+	case httpx.RouterFeatureNamedWildcard:
+		return true
+	default:
+		return false
+	}
+}
+
+func (r *Router) Group(prefix string, m ...httpx.Middleware) httpx.Router {
+	middlewares := make([]httpx.Middleware, 0, len(r.middlewares)+len(m))
+	middlewares = append(middlewares, r.middlewares...)
+	return &Router{
+		engine:      r.engine,
+		mux:         r.mux,
+		basePath:    httpx.JoinPaths(r.basePath, prefix),
+		middlewares: append(middlewares, m...),
+	}
+}
+
+func (r *Router) Handle(method, path string, h httpx.Handler) *httpx.Route {
+	method = strings.ToUpper(method)
+	r.checkRoute(method, path)
+	route := httpx.NewRouteFor(r.routeInfo(method, path))
+	r.handle(method+" ", path, route, h)
+	r.notifyRoute(method, path)
+	return route
+}
+
+func (r *Router) Any(path string, h httpx.Handler) *httpx.Route {
+	r.checkRoute(httpx.MethodAny, path)
+	route := httpx.NewRouteFor(r.routeInfo(httpx.MethodAny, path))
+	r.handle("", path, route, h)
+	r.notifyRoute(httpx.MethodAny, path)
+	return route
+}
+
+func (r *Router) Static(prefix, root string) {
+	r.checkRoute(http.MethodGet, prefix)
+	r.serveFiles(prefix, http.Dir(root))
+}
+
+func (r *Router) StaticFS(prefix string, fs fs.FS) {
+	r.checkRoute(http.MethodGet, prefix)
+	r.serveFiles(prefix, http.FS(fs))
+}
+
+func (r *Router) serveFiles(prefix string, root http.FileSystem) {
+	stripped := http.StripPrefix(httpx.JoinPaths(r.basePath, prefix), http.FileServer(root))
+	h := func(ctx httpx.Context) error {
+		hi, _ := httpx.AsHTTPInterop(ctx)
+		stripped.ServeHTTP(hi.HTTPResponseWriter(), hi.HTTPRequest())
+		return nil
+	}
+	path := httpx.JoinPaths(prefix, "/*filepath")
+	r.handle(http.MethodGet+" ", path, httpx.NewRoute(), h)
+}
+
+// handle registers h on the router's ServeMux. methodPrefix is either empty
+// or a method followed by a space, as in ServeMux patterns.
+func (r *Router) handle(methodPrefix, path string, route *httpx.Route, h httpx.Handler) {
+	fullPath := httpx.JoinPaths(r.basePath, path)
+	r.mux.Handle(methodPrefix+httpx.ServeMuxPattern(fullPath), httpx.ToHTTPHandler(route.Compose(h, r.middlewares...),
+		httpx.WithHTTPRoute(fullPath),
+		httpx.WithHTTPMiddleware(r.engine.middlewares...),
+		httpx.WithHTTPErrorHandler(r.engine.errHandler),
+		httpx.WithHTTPMultipartMemory(r.engine.multipartMemory),
+	))
+}
+
+// GET registers a new GET route for a path with matching handler.
+func (r *Router) GET(path string, h httpx.Handler) *httpx.Route {
+	return r.Handle(http.MethodGet, path, h)
+}
+
+// POST registers a new POST route for a path with matching handler.
+func (r *Router) POST(path string, h httpx.Handler) *httpx.Route {
+	return r.Handle(http.MethodPost, path, h)
+}
+
+// PUT registers a new PUT route for a path with matching handler.
+func (r *Router) PUT(path string, h httpx.Handler) *httpx.Route {
+	return r.Handle(http.MethodPut, path, h)
+}
+
+// DELETE registers a new DELETE route for a path with matching handler.
+func (r *Router) DELETE(path string, h httpx.Handler) *httpx.Route {
+	return r.Handle(http.MethodDelete, path, h)
+}
+
+// PATCH registers a new PATCH route for a path with matching handler.
+func (r *Router) PATCH(path string, h httpx.Handler) *httpx.Route {
+	return r.Handle(http.MethodPatch, path, h)
+}
+
+// HEAD registers a new HEAD route for a path with matching handler.
+func (r *Router) HEAD(path string, h httpx.Handler) *httpx.Route {
+	return r.Handle(http.MethodHead, path, h)
+}
+
+// OPTIONS registers a new OPTIONS route for a path with matching handler.
+func (r *Router) OPTIONS(path string, h httpx.Handler) *httpx.Route {
+	return r.Handle(http.MethodOptions, path, h)
+}
+
+func (r *Router) notifyRoute(method, path string) {
+	r.engine.hooks.NotifyRoute(r.routeInfo(method, path))
+}
+
+// checkRoute panics when the engine is frozen, see httpx.Engine.Freeze.
+func (r *Router) checkRoute(method, path string) {
+	if err := r.engine.hooks.CheckRoute(r.routeInfo(method, path)); err != nil {
+		panic(err)
+	}
+}
+
+func (r *Router) routeInfo(method, path string) httpx.RouteInfo {
+	return httpx.RouteInfo{
+		Method: method,
+		Path:   httpx.JoinPaths(r.basePath, path),
+	}
+}
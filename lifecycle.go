@@ -0,0 +1,224 @@
+package httpx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+var (
+	// ErrRoutesFrozen is wrapped by the error routers panic with when a
+	// route is registered on a frozen engine, see Engine.Freeze.
+	ErrRoutesFrozen = errors.New("httpx: routes are frozen")
+	// ErrRoutesNotReplaceable is returned by ReplaceRoutes for engines that
+	// cannot swap their routes.
+	ErrRoutesNotReplaceable = errors.New("httpx: routes cannot be replaced")
+)
+
+// RouteReplacer is implemented by engines that can swap their routes while
+// serving, without restarting the listener, for live reload in development.
+// ginx, echox and lambdax implement it.
+type RouteReplacer interface {
+	// ReplaceRoutes registers routes on an empty route table with register,
+	// and swaps it for the current one once register returns. Requests in
+	// flight complete on the previous table. Middleware added with
+	// Engine.Use is kept, and the engine's freeze does not apply. A panic
+	// in register, such as a route conflict, is returned as an error and
+	// keeps the current table.
+	ReplaceRoutes(register func(Router)) error
+}
+
+// AsRouteReplacer returns the route replacement capability when supported.
+func AsRouteReplacer(e Engine) (RouteReplacer, bool) {
+	r, ok := e.(RouteReplacer)
+	return r, ok
+}
+
+// ReplaceRoutes swaps the routes of e for those registered by register when e
+// implements RouteReplacer. It returns ErrRoutesNotReplaceable otherwise, so
+// live-reload tools can fall back to restarting the engine.
+func ReplaceRoutes(e Engine, register func(Router)) error {
+	r, ok := AsRouteReplacer(e)
+	if !ok {
+		return ErrRoutesNotReplaceable
+	}
+	return r.ReplaceRoutes(register)
+}
+
+// MethodAny is reported as RouteInfo.Method for routes registered via Any.
+const MethodAny = "ANY"
+
+// RouteInfo describes a route registered on an Engine.
+type RouteInfo struct {
+	Method string `json:"method"` // HTTP method, or MethodAny
+	Path   string `json:"path"`   // Full route pattern including group prefixes
+}
+
+// Hooks stores engine lifecycle callbacks with framework-independent semantics.
+//
+// Adapters keep a Hooks value per Engine and forward OnStart, OnStopping,
+// OnStop and OnRouteRegistered to it, so applications can wire pools, warmups, and
+// metrics exporters once regardless of the underlying framework.
+//
+// The zero value is ready to use. Hooks is safe for concurrent use.
+type Hooks struct {
+	mu         sync.RWMutex
+	onStart    []func() error
+	onStopping []func()
+	onStop     []func(context.Context) error
+	onRoute    []func(RouteInfo)
+	routes     []RouteInfo
+	frozen     bool
+}
+
+// OnStart registers fn to run before the engine starts serving.
+func (h *Hooks) OnStart(fn func() error) {
+	if fn == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.onStart = append(h.onStart, fn)
+}
+
+// OnStopping registers fn to run when Stop is called, before the engine stops
+// accepting connections.
+func (h *Hooks) OnStopping(fn func()) {
+	if fn == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.onStopping = append(h.onStopping, fn)
+}
+
+// OnStop registers fn to run after the engine has stopped serving.
+func (h *Hooks) OnStop(fn func(ctx context.Context) error) {
+	if fn == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.onStop = append(h.onStop, fn)
+}
+
+// OnRouteRegistered registers fn to observe route registrations.
+//
+// Routes registered before fn was added are replayed to it immediately,
+// so late subscribers still see the full route table.
+func (h *Hooks) OnRouteRegistered(fn func(RouteInfo)) {
+	if fn == nil {
+		return
+	}
+	h.mu.Lock()
+	h.onRoute = append(h.onRoute, fn)
+	routes := slices.Clone(h.routes)
+	h.mu.Unlock()
+	for _, info := range routes {
+		fn(info)
+	}
+}
+
+// RunStart runs start hooks in registration order and stops at the first error.
+func (h *Hooks) RunStart() error {
+	h.mu.RLock()
+	hooks := slices.Clone(h.onStart)
+	h.mu.RUnlock()
+	for _, fn := range hooks {
+		if err := fn(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RunStopping runs stopping hooks in registration order.
+func (h *Hooks) RunStopping() {
+	h.mu.RLock()
+	hooks := slices.Clone(h.onStopping)
+	h.mu.RUnlock()
+	for _, fn := range hooks {
+		fn()
+	}
+}
+
+// RunStop runs stop hooks in reverse registration order, so resources are
+// released in the opposite order they were acquired. All hooks run; their
+// errors are joined.
+func (h *Hooks) RunStop(ctx context.Context) error {
+	h.mu.RLock()
+	hooks := slices.Clone(h.onStop)
+	h.mu.RUnlock()
+	var errs []error
+	for _, fn := range slices.Backward(hooks) {
+		if err := fn(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// NotifyRoute records a registered route and passes it to route hooks.
+func (h *Hooks) NotifyRoute(info RouteInfo) {
+	h.mu.Lock()
+	h.routes = append(h.routes, info)
+	hooks := slices.Clone(h.onRoute)
+	h.mu.Unlock()
+	for _, fn := range hooks {
+		fn(info)
+	}
+}
+
+// Freeze makes CheckRoute reject the routes registered from now on.
+func (h *Hooks) Freeze() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.frozen = true
+}
+
+// Frozen reports whether Freeze was called.
+func (h *Hooks) Frozen() bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.frozen
+}
+
+// CheckRoute returns an error wrapping ErrRoutesFrozen once h is frozen.
+// Routers call it before registering a route and panic with the error, like
+// routers do for conflicting routes.
+func (h *Hooks) CheckRoute(info RouteInfo) error {
+	if !h.Frozen() {
+		return nil
+	}
+	return fmt.Errorf("%w: cannot register %s %s", ErrRoutesFrozen, info.Method, info.Path)
+}
+
+// ReplaceRoutes forgets the recorded routes and runs register, which
+// registers a new route table, with CheckRoute accepting routes even when h
+// is frozen. Adapters call it from their ReplaceRoutes method.
+func (h *Hooks) ReplaceRoutes(register func()) {
+	h.mu.Lock()
+	routes, frozen := h.routes, h.frozen
+	h.routes, h.frozen = nil, false
+	h.mu.Unlock()
+	replaced := false
+	defer func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		h.frozen = frozen
+		if !replaced {
+			h.routes = routes
+		}
+	}()
+	register()
+	replaced = true
+}
+
+// Routes returns the routes recorded so far, in registration order.
+func (h *Hooks) Routes() []RouteInfo {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return slices.Clone(h.routes)
+}
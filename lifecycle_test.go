@@ -0,0 +1,153 @@
+package httpx
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestHooksRunOrder(t *testing.T) {
+	var h Hooks
+	var events []string
+	h.OnStart(func() error {
+		events = append(events, "start:1")
+		return nil
+	})
+	h.OnStart(func() error {
+		events = append(events, "start:2")
+		return nil
+	})
+	h.OnStopping(func() {
+		events = append(events, "stopping")
+	})
+	h.OnStop(func(context.Context) error {
+		events = append(events, "stop:1")
+		return nil
+	})
+	h.OnStop(func(context.Context) error {
+		events = append(events, "stop:2")
+		return nil
+	})
+
+	if err := h.RunStart(); err != nil {
+		t.Fatalf("RunStart() error = %v", err)
+	}
+	h.RunStopping()
+	if err := h.RunStop(context.Background()); err != nil {
+		t.Fatalf("RunStop() error = %v", err)
+	}
+	want := []string{"start:1", "start:2", "stopping", "stop:2", "stop:1"}
+	if !slices.Equal(events, want) {
+		t.Fatalf("events = %v, want %v", events, want)
+	}
+}
+
+func TestHooksErrors(t *testing.T) {
+	var h Hooks
+	errA := errors.New("a")
+	errB := errors.New("b")
+	called := false
+	h.OnStart(func() error { return errA })
+	h.OnStart(func() error {
+		called = true
+		return nil
+	})
+	if err := h.RunStart(); !errors.Is(err, errA) {
+		t.Fatalf("RunStart() error = %v, want %v", err, errA)
+	}
+	if called {
+		t.Fatalf("RunStart() should stop at the first error")
+	}
+
+	h.OnStop(func(context.Context) error { return errA })
+	h.OnStop(func(context.Context) error { return errB })
+	err := h.RunStop(context.Background())
+	if !errors.Is(err, errA) || !errors.Is(err, errB) {
+		t.Fatalf("RunStop() error = %v, want both errors joined", err)
+	}
+}
+
+func TestHooksRouteReplay(t *testing.T) {
+	var h Hooks
+	h.NotifyRoute(RouteInfo{Method: "GET", Path: "/a"})
+
+	var seen []RouteInfo
+	h.OnRouteRegistered(func(info RouteInfo) {
+		seen = append(seen, info)
+	})
+	h.NotifyRoute(RouteInfo{Method: MethodAny, Path: "/b"})
+
+	want := []RouteInfo{{Method: "GET", Path: "/a"}, {Method: MethodAny, Path: "/b"}}
+	if !slices.Equal(seen, want) {
+		t.Fatalf("seen = %v, want %v", seen, want)
+	}
+	if !slices.Equal(h.Routes(), want) {
+		t.Fatalf("Routes() = %v, want %v", h.Routes(), want)
+	}
+}
+
+func TestHooksFreeze(t *testing.T) {
+	var h Hooks
+	info := RouteInfo{Method: "GET", Path: "/a"}
+	if err := h.CheckRoute(info); err != nil {
+		t.Fatalf("CheckRoute before Freeze = %v", err)
+	}
+	h.Freeze()
+	if !h.Frozen() {
+		t.Fatal("Frozen() = false after Freeze")
+	}
+	err := h.CheckRoute(info)
+	if !errors.Is(err, ErrRoutesFrozen) {
+		t.Fatalf("CheckRoute after Freeze = %v, want ErrRoutesFrozen", err)
+	}
+	if want := "httpx: routes are frozen: cannot register GET /a"; err.Error() != want {
+		t.Fatalf("error = %q, want %q", err, want)
+	}
+}
+
+func TestHooksReplaceRoutes(t *testing.T) {
+	var h Hooks
+	h.NotifyRoute(RouteInfo{Method: "GET", Path: "/old"})
+	h.Freeze()
+
+	func() {
+		defer func() { _ = recover() }()
+		h.ReplaceRoutes(func() {
+			h.NotifyRoute(RouteInfo{Method: "GET", Path: "/broken"})
+			panic("conflict")
+		})
+	}()
+	if got := h.Routes(); !slices.Equal(got, []RouteInfo{{Method: "GET", Path: "/old"}}) {
+		t.Fatalf("routes after a failed replacement = %v", got)
+	}
+
+	h.ReplaceRoutes(func() {
+		info := RouteInfo{Method: "GET", Path: "/new"}
+		if err := h.CheckRoute(info); err != nil {
+			t.Fatalf("CheckRoute while replacing = %v", err)
+		}
+		h.NotifyRoute(info)
+	})
+	if got := h.Routes(); !slices.Equal(got, []RouteInfo{{Method: "GET", Path: "/new"}}) {
+		t.Fatalf("routes after replacement = %v", got)
+	}
+	if !h.Frozen() {
+		t.Fatal("Frozen() = false after replacement")
+	}
+}
+
+func TestJoinPaths(t *testing.T) {
+	tests := []struct{ base, rel, want string }{
+		{"/", "", "/"},
+		{"/api", "", "/api"},
+		{"/api", "/users", "/api/users"},
+		{"/api", "/users/", "/api/users/"},
+		{"/api/", "users", "/api/users"},
+	}
+	for _, tt := range tests {
+		if got := JoinPaths(tt.base, tt.rel); got != tt.want {
+			t.Errorf("JoinPaths(%q, %q) = %q, want %q", tt.base, tt.rel, got, tt.want)
+		}
+	}
+}
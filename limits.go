@@ -0,0 +1,288 @@
+package httpx
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// RouteMetaTimeout is the route metadata key of WithRouteTimeout. Its
+	// value is a duration as parsed by time.ParseDuration.
+	RouteMetaTimeout = "httpx.timeout"
+	// RouteMetaBodyLimit is the route metadata key of WithRouteBodyLimit.
+	// Its value is a size in bytes.
+	RouteMetaBodyLimit = "httpx.body_limit"
+)
+
+// ErrBodyTooLarge reports a request body larger than the limit of BodyLimit,
+// WithRouteBodyLimit or LimitedBodyReader.
+var ErrBodyTooLarge = errors.New("httpx: request body too large")
+
+// WithRouteTimeout overrides the timeout of RequestTimeout for a route, so a
+// route such as an upload endpoint can run longer, or shorter, than the rest
+// of the engine:
+//
+//	r.POST("/uploads", upload).With(httpx.WithRouteTimeout(10*time.Minute))
+//
+// The request context gets a deadline d after the request starts, replacing
+// the one of RequestTimeout, even where none is installed.
+func WithRouteTimeout(d time.Duration) RouteOption {
+	return func(r *Route) {
+		r.Meta(RouteMetaTimeout, d.String())
+	}
+}
+
+// WithRouteBodyLimit overrides the limit of BodyLimit for a route, or limits
+// the request body of the route to n bytes where none is installed.
+func WithRouteBodyLimit(n int64) RouteOption {
+	return func(r *Route) {
+		r.Meta(RouteMetaBodyLimit, strconv.FormatInt(n, 10))
+	}
+}
+
+// limitsInUse is set once a route limit or BodyLimit is configured, so
+// requests of applications without them skip the bookkeeping.
+var limitsInUse atomic.Bool
+
+// requestLimits is the per-request state connecting RequestTimeout and
+// BodyLimit run as engine middleware, before the route is matched, with the
+// overrides of the route.
+type requestLimits struct {
+	// base is the request context before RequestTimeout set its deadline.
+	base context.Context
+	// bodyLimit is the limit of the BodyLimit middleware run ahead of the
+	// route, or 0.
+	bodyLimit int64
+	// route reports that the route's limits have been applied.
+	route bool
+}
+
+var requestLimitsKey = NewKey[*requestLimits]("request_limits")
+
+func requestLimitsOf(ctx Context) *requestLimits {
+	l, ok := requestLimitsKey.Get(ctx)
+	if !ok || l == nil {
+		l = &requestLimits{}
+		SetTyped(ctx, requestLimitsKey, l)
+	}
+	return l
+}
+
+// BodyLimit returns middleware that limits request bodies to n bytes, or to
+// the limit set for the matched route with WithRouteBodyLimit. Requests
+// declaring a larger Content-Length fail with status 413 wrapping
+// ErrBodyTooLarge before the handler runs; streamed bodies fail the same way
+// once reading passes the limit.
+//
+// Added with Use on the engine, the limit is applied when the route is
+// matched, so the route's override can raise it. fiber and hertz buffer
+// request bodies up to their server's limit before any middleware runs, so
+// overrides cannot raise the body size those servers accept.
+func BodyLimit(n int64) Middleware {
+	limitsInUse.Store(true)
+	return func(ctx Context) error {
+		l := requestLimitsOf(ctx)
+		if !l.route {
+			if l.bodyLimit == 0 || n < l.bodyLimit {
+				l.bodyLimit = n
+			}
+			return ctx.Next()
+		}
+		if route, ok := routeKey.Get(ctx); ok && route != nil && route.bodyLimit > 0 {
+			// The route's own limit is already applied.
+			return ctx.Next()
+		}
+		if err := limitBody(ctx, n); err != nil {
+			return err
+		}
+		return ctx.Next()
+	}
+}
+
+// applyLimits applies the timeout and body limit of r, or those recorded by
+// the engine middleware. The returned cancel func is non-nil when the
+// request context was replaced.
+func (r *Route) applyLimits(ctx Context) (context.CancelFunc, error) {
+	l := requestLimitsOf(ctx)
+	l.route = true
+	limit := l.bodyLimit
+	if r.bodyLimit > 0 {
+		limit = r.bodyLimit
+	}
+	if limit > 0 {
+		if err := limitBody(ctx, limit); err != nil {
+			return nil, err
+		}
+	}
+	if r.timeout <= 0 {
+		return nil, nil
+	}
+	current := ctx.Context()
+	if l.base == nil {
+		c, cancel := context.WithTimeout(current, r.timeout)
+		ctx.SetContext(c)
+		return cancel, nil
+	}
+	// The deadline derives from the context RequestTimeout started from, so
+	// the override can extend it, while values added since are kept.
+	c, cancel := context.WithTimeout(l.base, r.timeout)
+	ctx.SetContext(deadlineContext{Context: c, values: current})
+	return cancel, nil
+}
+
+// deadlineContext carries the deadline and cancellation of its Context and
+// the values of values.
+type deadlineContext struct {
+	context.Context
+	values context.Context
+}
+
+func (c deadlineContext) Value(key any) any {
+	return c.values.Value(key)
+}
+
+// limitBody applies limit to the request body of ctx.
+func limitBody(ctx Context, limit int64) error {
+	if ctx.ContentLength() > limit {
+		return bodyTooLarge(limit)
+	}
+	if hi, ok := AsHTTPInterop(ctx); ok {
+		req := hi.HTTPRequest()
+		if req.Body != nil && req.Body != http.NoBody {
+			req.Body = &limitedBody{ReadCloser: req.Body, limit: limit}
+		}
+		return nil
+	}
+	if ctx.ContentLength() >= 0 {
+		return nil
+	}
+	// Adapters without HTTPInterop buffer the body, so its size is known.
+	body, err := BodyRawUnsafe(ctx)
+	if err != nil {
+		return err
+	}
+	if int64(len(body)) > limit {
+		return bodyTooLarge(limit)
+	}
+	return nil
+}
+
+func bodyTooLarge(limit int64) error {
+	return WithStatus(http.StatusRequestEntityTooLarge, fmt.Errorf("%w: limit %d bytes", ErrBodyTooLarge, limit))
+}
+
+// LimitedBodyReader returns the request body limited to limit bytes, for
+// upload endpoints reading large bodies as a stream. On adapters backed by
+// net/http the body is read from the connection as the reader is read, so
+// that no more than limit+1 bytes are consumed. Reads past the limit,
+// or of a body declaring a larger Content-Length, fail with status 413
+// wrapping ErrBodyTooLarge, which the error handler receives when the
+// handler returns the error. A limit of zero or less does not limit the
+// body.
+//
+// onProgress, when non-nil, is called after each read with the number of
+// bytes read so far, to report upload progress.
+func LimitedBodyReader(ctx Context, limit int64, onProgress func(read int64)) io.ReadCloser {
+	if limit > 0 && ctx.ContentLength() > limit {
+		return NewReadCloser(errReader{bodyTooLarge(limit)}, nil)
+	}
+	var body io.ReadCloser
+	if hi, ok := AsHTTPInterop(ctx); ok {
+		// BodyReader of net/http based adapters reads the whole body first,
+		// which the limit is meant to prevent.
+		body = hi.HTTPRequest().Body
+	} else {
+		body = ctx.BodyReader()
+	}
+	if body == nil {
+		body = http.NoBody
+	}
+	return &limitedBody{ReadCloser: body, limit: limit, onProgress: onProgress}
+}
+
+// BodyRawLimit returns the request body like BodyRawUnsafe, unless it is
+// longer than limit bytes, which fails with status 413 wrapping
+// ErrBodyTooLarge. A body declaring a larger Content-Length is not read, and
+// on adapters backed by net/http, a body of unknown length is read no
+// further than limit+1 bytes; the bytes read stay in the body for later
+// reads.
+func BodyRawLimit(ctx Context, limit int64) ([]byte, error) {
+	if ctx.ContentLength() > limit {
+		return nil, bodyTooLarge(limit)
+	}
+	if _, ok := AsUnsafeBody(ctx); !ok {
+		if hi, ok := AsHTTPInterop(ctx); ok {
+			return httpBodyLimit(hi.HTTPRequest(), limit)
+		}
+	}
+	data, err := BodyRawUnsafe(ctx)
+	if err == nil && int64(len(data)) > limit {
+		return nil, bodyTooLarge(limit)
+	}
+	return data, err
+}
+
+// httpBodyLimit is BodyRawLimit for r, caching the body like ReusableBody
+// when it is not too large.
+func httpBodyLimit(r *http.Request, limit int64) ([]byte, error) {
+	if _, ok := r.Body.(*reusableBody); ok || r.Body == nil || r.Body == http.NoBody {
+		data, err := ReusableBody(r)
+		if err == nil && int64(len(data)) > limit {
+			return nil, bodyTooLarge(limit)
+		}
+		return data, err
+	}
+	body := r.Body
+	data, err := io.ReadAll(io.LimitReader(body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		r.Body = NewReadCloser(io.MultiReader(bytes.NewReader(data), body), body.Close)
+		return nil, bodyTooLarge(limit)
+	}
+	_ = body.Close()
+	r.Body = &reusableBody{Reader: bytes.NewReader(data), data: data}
+	return data, nil
+}
+
+// limitedBody fails reads past limit bytes, when limit is positive, with
+// ErrBodyTooLarge.
+type limitedBody struct {
+	io.ReadCloser
+	read       int64
+	limit      int64
+	onProgress func(read int64)
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.limit > 0 {
+		if b.read > b.limit {
+			return 0, bodyTooLarge(b.limit)
+		}
+		// Read one byte past the limit to tell a body of exactly limit
+		// bytes from a larger one.
+		if rest := b.limit - b.read + 1; int64(len(p)) > rest {
+			p = p[:rest]
+		}
+	}
+	n, err := b.ReadCloser.Read(p)
+	b.read += int64(n)
+	delivered := b.read
+	if b.limit > 0 && b.read > b.limit {
+		n -= int(b.read - b.limit)
+		delivered = b.limit
+		err = bodyTooLarge(b.limit)
+	}
+	if b.onProgress != nil && n > 0 {
+		b.onProgress(delivered)
+	}
+	return n, err
+}
@@ -0,0 +1,138 @@
+package httpx
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLimitedBodyReader(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		length   int64
+		limit    int64
+		wantErr  bool
+		wantRead string
+	}{
+		{name: "within limit", body: "hello", length: 5, limit: 5, wantRead: "hello"},
+		{name: "over limit", body: "hello world", length: -1, limit: 5, wantErr: true, wantRead: "hello"},
+		{name: "declared over limit", body: "hello world", length: 11, limit: 5, wantErr: true},
+		{name: "unlimited", body: "hello world", length: -1, wantRead: "hello world"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var progress []int64
+			var read []byte
+			var readErr error
+			h := ToHTTPHandler(func(ctx Context) error {
+				body := LimitedBodyReader(ctx, tt.limit, func(n int64) {
+					progress = append(progress, n)
+				})
+				defer body.Close()
+				buf := make([]byte, 3)
+				for {
+					n, err := body.Read(buf)
+					read = append(read, buf[:n]...)
+					if err != nil {
+						if !errors.Is(err, io.EOF) {
+							readErr = err
+						}
+						return nil
+					}
+				}
+			})
+			req := httptest.NewRequest(http.MethodPost, "/", io.MultiReader(strings.NewReader(tt.body)))
+			req.ContentLength = tt.length
+			h.ServeHTTP(httptest.NewRecorder(), req)
+
+			if string(read) != tt.wantRead {
+				t.Fatalf("read %q, want %q", read, tt.wantRead)
+			}
+			if tt.wantErr != errors.Is(readErr, ErrBodyTooLarge) {
+				t.Fatalf("error = %v, want ErrBodyTooLarge: %v", readErr, tt.wantErr)
+			}
+			if readErr != nil {
+				if _, status, _ := ParseError(readErr); status != http.StatusRequestEntityTooLarge {
+					t.Fatalf("status = %d, want 413", status)
+				}
+			}
+			if len(read) > 0 && progress[len(progress)-1] != int64(len(read)) {
+				t.Fatalf("progress = %v, want it to end at %d", progress, len(read))
+			}
+		})
+	}
+}
+
+// countingReader counts the bytes read from a body of size bytes.
+type countingReader struct{ n, size int64 }
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	if r.n == r.size {
+		return 0, io.EOF
+	}
+	p = p[:min(int64(len(p)), r.size-r.n)]
+	for i := range p {
+		p[i] = 'x'
+	}
+	r.n += int64(len(p))
+	return len(p), nil
+}
+
+func TestLimitedBodyReaderStreams(t *testing.T) {
+	const limit = 1 << 10
+	src := &countingReader{size: 1 << 20}
+	var readErr error
+	h := ToHTTPHandler(func(ctx Context) error {
+		body := LimitedBodyReader(ctx, limit, nil)
+		defer body.Close()
+		_, readErr = io.Copy(io.Discard, body)
+		return nil
+	})
+	req := httptest.NewRequest(http.MethodPost, "/", src)
+	req.ContentLength = -1
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	if !errors.Is(readErr, ErrBodyTooLarge) {
+		t.Fatalf("error = %v, want ErrBodyTooLarge", readErr)
+	}
+	if src.n != limit+1 {
+		t.Fatalf("consumed %d bytes of the body, want %d", src.n, limit+1)
+	}
+}
+
+func TestBodyRawLimit(t *testing.T) {
+	const limit = 1 << 10
+	src := &countingReader{size: 1 << 20}
+	var rawErr error
+	var consumed, rest int64
+	h := ToHTTPHandler(func(ctx Context) error {
+		_, rawErr = BodyRawLimit(ctx, limit)
+		consumed = src.n
+		// The bytes read to check the limit stay in the body.
+		rest, _ = io.Copy(io.Discard, ctx.BodyReader())
+		return nil
+	})
+	req := httptest.NewRequest(http.MethodPost, "/", src)
+	req.ContentLength = -1
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	if !errors.Is(rawErr, ErrBodyTooLarge) || consumed != limit+1 || rest != src.size {
+		t.Fatalf("error = %v after reading %d bytes, body left %d bytes, want ErrBodyTooLarge after %d and %d",
+			rawErr, consumed, rest, limit+1, src.size)
+	}
+
+	var raw []byte
+	h = ToHTTPHandler(func(ctx Context) error {
+		raw, rawErr = BodyRawLimit(ctx, limit)
+		return rawErr
+	})
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader("small"))
+	req.ContentLength = -1
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	if rawErr != nil || string(raw) != "small" {
+		t.Fatalf("BodyRawLimit() = %q, %v", raw, rawErr)
+	}
+}
@@ -0,0 +1,56 @@
+package httpx
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Link is an RFC 8288 web link, sent in the Link header by SetLinkHeaders.
+type Link struct {
+	// URL is the target of the link, usually absolute, see AbsURL.
+	URL string
+	// Rel is the relation type, such as "self", "next" or "prev".
+	Rel string
+	// Title is the optional human-readable title of the link.
+	Title string
+	// Type is the optional media type of the target.
+	Type string
+}
+
+// String formats l as a Link header value, as in
+// `<https://example.com/items?page=2>; rel="next"`.
+func (l Link) String() string {
+	var b strings.Builder
+	b.WriteString("<" + l.URL + ">")
+	for _, param := range [...]struct{ name, value string }{
+		{"rel", l.Rel},
+		{"title", l.Title},
+		{"type", l.Type},
+	} {
+		if param.value != "" {
+			b.WriteString("; " + param.name + "=" + strconv.Quote(param.value))
+		}
+	}
+	return b.String()
+}
+
+// SetLinkHeaders sets the Link header of the response to links, for
+// hypermedia APIs:
+//
+//	httpx.SetLinkHeaders(ctx, []httpx.Link{
+//		{URL: httpx.AbsURL(ctx, "/orders/7"), Rel: "self"},
+//		{URL: httpx.AbsURL(ctx, "/orders/7/items"), Rel: "items"},
+//	})
+//
+// It replaces a Link header set before, and removes nothing when links is
+// empty. PaginationLinks returns the links of a page.
+func SetLinkHeaders(ctx Context, links []Link) {
+	if len(links) == 0 {
+		return
+	}
+	values := make([]string, len(links))
+	for i, link := range links {
+		values[i] = link.String()
+	}
+	ctx.SetHeader("Link", strings.Join(values, ", "))
+}
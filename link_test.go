@@ -0,0 +1,28 @@
+package httpx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSetLinkHeaders(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/orders?page=2&limit=10", nil)
+	rec := httptest.NewRecorder()
+	ctx := newHTTPContext(rec, req, nil)
+	links := append([]Link{{URL: AbsURL(ctx, ""), Rel: "self", Title: `Orders "2"`, Type: "application/json"}},
+		PaginationLinks(ctx, Page{Page: 2, Limit: 10, Offset: 10}, 15)...)
+	SetLinkHeaders(ctx, links)
+	want := `<http://example.com/orders?page=2&limit=10>; rel="self"; title="Orders \"2\""; type="application/json", ` +
+		`<http://example.com/orders?limit=10&page=1>; rel="first", ` +
+		`<http://example.com/orders?limit=10&page=1>; rel="prev", ` +
+		`<http://example.com/orders?limit=10&page=2>; rel="last"`
+	if got := rec.Header().Get("Link"); got != want {
+		t.Fatalf("Link = %s\nwant %s", got, want)
+	}
+
+	SetLinkHeaders(ctx, nil)
+	if got := rec.Header().Get("Link"); got != want {
+		t.Fatalf("SetLinkHeaders(nil) changed Link to %q", got)
+	}
+}
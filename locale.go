@@ -0,0 +1,153 @@
+package httpx
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// LocaleKey is the StateStore key under which SetLocale stores the locale.
+const LocaleKey = "httpx.locale"
+
+// LangQ is a language range of an Accept-Language header with its quality.
+type LangQ struct {
+	Tag string
+	Q   float64
+}
+
+// AcceptLanguages returns the languages of the request's Accept-Language
+// header, see ParseAcceptLanguage.
+func AcceptLanguages(ctx RequestInfo) []LangQ {
+	return ParseAcceptLanguage(ctx.Header("Accept-Language"))
+}
+
+// ParseAcceptLanguage parses an Accept-Language header value into its
+// language ranges sorted by decreasing quality, keeping the header's order
+// for equal qualities. Ranges with quality 0 and malformed ones are
+// dropped.
+func ParseAcceptLanguage(header string) []LangQ {
+	var langs []LangQ
+	for part := range strings.SplitSeq(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil || parsed < 0 || parsed > 1 {
+				continue
+			}
+			q = parsed
+		}
+		if q == 0 {
+			continue
+		}
+		langs = append(langs, LangQ{Tag: tag, Q: q})
+	}
+	slices.SortStableFunc(langs, func(a, b LangQ) int {
+		switch {
+		case a.Q > b.Q:
+			return -1
+		case a.Q < b.Q:
+			return 1
+		}
+		return 0
+	})
+	return langs
+}
+
+// NegotiateLocale returns the supported locale that best matches the
+// accepted languages. A range matches a locale equal to it or to one of its
+// prefixes, so "de-CH" matches "de"; a bare language also matches a locale
+// of that language, so "en" matches "en-US". "*" matches the first
+// supported locale. ok is false when nothing matches.
+func NegotiateLocale(accepted []LangQ, supported []string) (locale string, ok bool) {
+	for _, lang := range accepted {
+		if lang.Tag == "*" {
+			if len(supported) > 0 {
+				return supported[0], true
+			}
+			continue
+		}
+		for tag := lang.Tag; tag != ""; {
+			if i := slices.IndexFunc(supported, func(s string) bool { return strings.EqualFold(s, tag) }); i >= 0 {
+				return supported[i], true
+			}
+			i := strings.LastIndexByte(tag, '-')
+			if i < 0 {
+				break
+			}
+			tag = tag[:i]
+		}
+		base, _, _ := strings.Cut(lang.Tag, "-")
+		for _, s := range supported {
+			if sBase, _, _ := strings.Cut(s, "-"); strings.EqualFold(sBase, base) {
+				return s, true
+			}
+		}
+	}
+	return "", false
+}
+
+// MessageStore provides translated messages for T.
+type MessageStore interface {
+	// Locales returns the supported locales. Negotiation falls back to the
+	// first one unless another default is configured.
+	Locales() []string
+	// Message returns the message format of key in locale.
+	Message(locale, key string) (string, bool)
+}
+
+// Messages is an in-memory MessageStore of message formats by locale and
+// key.
+type Messages map[string]map[string]string
+
+// Locales returns the locales of m in sorted order.
+func (m Messages) Locales() []string {
+	return slices.Sorted(maps.Keys(m))
+}
+
+// Message returns the message format of key in locale.
+func (m Messages) Message(locale, key string) (string, bool) {
+	msg, ok := m[locale][key]
+	return msg, ok
+}
+
+// messageStoreKey is the StateStore key of the MessageStore set by
+// SetLocale.
+const messageStoreKey = "httpx.message_store"
+
+// SetLocale stores the locale of the request and the store T translates
+// with. It is usually called by a middleware such as middleware.Locale.
+func SetLocale(ctx StateStore, locale string, store MessageStore) {
+	ctx.Set(LocaleKey, locale)
+	ctx.Set(messageStoreKey, store)
+}
+
+// Locale returns the locale stored by SetLocale, or "" when none was set.
+func Locale(ctx StateStore) string {
+	locale, _ := GetAs[string](ctx, LocaleKey)
+	return locale
+}
+
+// T translates key into the request's locale, formatting the message with
+// args as fmt.Sprintf does. It returns key itself when no locale was set or
+// the store has no message for it, so missing translations stay visible.
+func T(ctx StateStore, key string, args ...any) string {
+	store, ok := GetAs[MessageStore](ctx, messageStoreKey)
+	if !ok || store == nil {
+		return key
+	}
+	msg, ok := store.Message(Locale(ctx), key)
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
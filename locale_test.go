@@ -0,0 +1,68 @@
+package httpx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	got := ParseAcceptLanguage("fr-CH, fr;q=0.9, en;q=0.8, de;q=0.7, *;q=0.5, es;q=0, it;q=x")
+	want := []LangQ{{"fr-CH", 1}, {"fr", 0.9}, {"en", 0.8}, {"de", 0.7}, {"*", 0.5}}
+	if !slices.Equal(got, want) {
+		t.Fatalf("ParseAcceptLanguage = %v, want %v", got, want)
+	}
+	got = ParseAcceptLanguage("en;q=0.5, de, ja;q=0.5")
+	want = []LangQ{{"de", 1}, {"en", 0.5}, {"ja", 0.5}}
+	if !slices.Equal(got, want) {
+		t.Fatalf("ParseAcceptLanguage = %v, want %v", got, want)
+	}
+	if got := ParseAcceptLanguage(""); got != nil {
+		t.Fatalf("ParseAcceptLanguage(\"\") = %v, want nil", got)
+	}
+}
+
+func TestNegotiateLocale(t *testing.T) {
+	supported := []string{"en-US", "de", "zh-Hant"}
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{header: "de-CH, en;q=0.5", want: "de"},
+		{header: "EN", want: "en-US"},
+		{header: "zh-Hant-TW", want: "zh-Hant"},
+		{header: "fr, de;q=0.1", want: "de"},
+		{header: "fr, *;q=0.1", want: "en-US"},
+		{header: "fr", want: ""},
+	}
+	for _, tt := range tests {
+		got, ok := NegotiateLocale(ParseAcceptLanguage(tt.header), supported)
+		if got != tt.want || ok != (tt.want != "") {
+			t.Errorf("NegotiateLocale(%q) = %q, %v, want %q", tt.header, got, ok, tt.want)
+		}
+	}
+}
+
+func TestT(t *testing.T) {
+	ctx := NewContext(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if got := T(ctx, "greeting", "Ada"); got != "greeting" {
+		t.Fatalf("T without locale = %q, want the key", got)
+	}
+	SetLocale(ctx, "de", Messages{
+		"en": {"greeting": "Hello, %s"},
+		"de": {"greeting": "Hallo, %s", "bye": "Tschüss"},
+	})
+	if got := T(ctx, "greeting", "Ada"); got != "Hallo, Ada" {
+		t.Fatalf("T = %q", got)
+	}
+	if got := T(ctx, "bye"); got != "Tschüss" {
+		t.Fatalf("T without args = %q", got)
+	}
+	if got := T(ctx, "missing"); got != "missing" {
+		t.Fatalf("T of a missing message = %q, want the key", got)
+	}
+	if got := Locale(ctx); got != "de" {
+		t.Fatalf("Locale = %q", got)
+	}
+}
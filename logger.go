@@ -0,0 +1,39 @@
+package httpx
+
+import (
+	"context"
+	"log/slog"
+)
+
+// LoggerKey is the StateStore key under which the request-scoped logger is stored.
+const LoggerKey = "httpx.logger"
+
+type loggerContextKey struct{}
+
+// SetLogger stores logger as the request-scoped logger of ctx. It is kept in
+// the StateStore and in the request's context.Context, so code that only
+// receives a context.Context can retrieve it with LoggerFromContext.
+func SetLogger(ctx Context, logger *slog.Logger) {
+	ctx.Set(LoggerKey, logger)
+	ctx.SetContext(context.WithValue(ctx.Context(), loggerContextKey{}, logger))
+}
+
+// LoggerFrom returns the request-scoped logger of ctx, or slog.Default when
+// none was set.
+func LoggerFrom(ctx Context) *slog.Logger {
+	if v, ok := ctx.Get(LoggerKey); ok {
+		if logger, ok := v.(*slog.Logger); ok && logger != nil {
+			return logger
+		}
+	}
+	return LoggerFromContext(ctx.Context())
+}
+
+// LoggerFromContext returns the logger stored by SetLogger in ctx, or
+// slog.Default when none was set.
+func LoggerFromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerContextKey{}).(*slog.Logger); ok && logger != nil {
+		return logger
+	}
+	return slog.Default()
+}
@@ -0,0 +1,304 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"math/rand/v2"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-sphere/httpx"
+)
+
+// Redacted replaces the values AccessLog redacts.
+const Redacted = "[REDACTED]"
+
+// DefaultRedactedHeaders are the headers AccessLog always redacts.
+var DefaultRedactedHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"X-Api-Key",
+	"X-Auth-Token",
+}
+
+// DefaultRedactedFields are the query, form and JSON body fields AccessLog
+// always redacts.
+var DefaultRedactedFields = []string{
+	"password",
+	"passwd",
+	"secret",
+	"token",
+	"access_token",
+	"refresh_token",
+	"api_key",
+	"client_secret",
+}
+
+// AccessLogOption configures AccessLog.
+type AccessLogOption func(*accessLogConfig)
+
+type accessLogConfig struct {
+	rate       float64
+	keepErrors bool
+	headers    []string
+	allHeaders bool
+	redacted   map[string]bool
+	fields     map[string]bool
+	maxBody    int
+}
+
+// WithAccessLogSampling logs only a fraction rate of the requests, between
+// 0 and 1, picked at random. With keepErrors, requests that fail, with an
+// error or a status of 400 or more, are logged whatever the rate, so that
+// sampling thins out the successes only. Every request is logged by
+// default.
+func WithAccessLogSampling(rate float64, keepErrors bool) AccessLogOption {
+	return func(c *accessLogConfig) {
+		c.rate = rate
+		c.keepErrors = keepErrors
+	}
+}
+
+// WithAccessLogHeaders logs the request headers named, or all of them when
+// none are named, in a "headers" group. The values of redacted headers are
+// replaced with Redacted.
+func WithAccessLogHeaders(names ...string) AccessLogOption {
+	return func(c *accessLogConfig) {
+		c.headers = names
+		c.allHeaders = len(names) == 0
+	}
+}
+
+// WithAccessLogRedactHeaders redacts the headers named, besides
+// DefaultRedactedHeaders. Names are matched case-insensitively.
+func WithAccessLogRedactHeaders(names ...string) AccessLogOption {
+	return func(c *accessLogConfig) {
+		for _, name := range names {
+			c.redacted[strings.ToLower(name)] = true
+		}
+	}
+}
+
+// WithAccessLogRedactFields redacts the query, form and JSON body fields
+// named, besides DefaultRedactedFields. Names are matched
+// case-insensitively, at any depth of a JSON body.
+func WithAccessLogRedactFields(names ...string) AccessLogOption {
+	return func(c *accessLogConfig) {
+		for _, name := range names {
+			c.fields[strings.ToLower(name)] = true
+		}
+	}
+}
+
+// WithAccessLogBody logs up to maxBytes of the request body, after
+// redaction, in a "body" attribute; "body_truncated" marks bodies cut
+// short. Only JSON and urlencoded form bodies, whose fields can be
+// redacted, are logged. Redaction needs the whole body, so bodies longer
+// than four times maxBytes are neither read nor decoded, and logged with
+// "body_truncated" alone.
+func WithAccessLogBody(maxBytes int) AccessLogOption {
+	return func(c *accessLogConfig) {
+		c.maxBody = maxBytes
+	}
+}
+
+// AccessLog logs a record for each request once it is handled, with these
+// attributes:
+//
+//   - method, path and route: the request and its route pattern
+//   - query: the query string, with redacted fields
+//   - status and size: the response status and body size
+//   - duration: the time taken to handle the request
+//   - client_ip: the client address (Context.ClientIP)
+//   - error: the error returned by the chain, if any
+//
+// plus the headers and body requested with WithAccessLogHeaders and
+// WithAccessLogBody. Records of requests failing with status 500 or more
+// are logged at error level, those of other failures at warn level, and
+// the rest at info level. Secrets are redacted by default, see
+// DefaultRedactedHeaders and DefaultRedactedFields. A nil logger uses
+// slog.Default:
+//
+//	engine.Use(middleware.AccessLog(nil,
+//		middleware.WithAccessLogSampling(0.1, true),
+//		middleware.WithAccessLogHeaders("User-Agent", "Authorization"),
+//		middleware.WithAccessLogBody(2048),
+//	))
+func AccessLog(logger *slog.Logger, opts ...AccessLogOption) httpx.Middleware {
+	conf := &accessLogConfig{
+		rate:     1,
+		redacted: make(map[string]bool),
+		fields:   make(map[string]bool),
+	}
+	for _, name := range DefaultRedactedHeaders {
+		conf.redacted[strings.ToLower(name)] = true
+	}
+	for _, name := range DefaultRedactedFields {
+		conf.fields[strings.ToLower(name)] = true
+	}
+	for _, opt := range opts {
+		opt(conf)
+	}
+	return func(ctx httpx.Context) error {
+		sampled := conf.rate >= 1 || conf.rate > 0 && rand.Float64() < conf.rate
+		if !sampled && !conf.keepErrors {
+			return ctx.Next()
+		}
+		start := time.Now()
+		err := ctx.Next()
+		status, size := responseStatus(ctx, err)
+		if !sampled && err == nil && status < 400 {
+			return err
+		}
+		log := logger
+		if log == nil {
+			log = slog.Default()
+		}
+		attrs := []slog.Attr{
+			slog.String("method", ctx.Method()),
+			slog.String("path", ctx.Path()),
+			slog.String("route", ctx.FullPath()),
+		}
+		if query := ctx.RawQuery(); query != "" {
+			attrs = append(attrs, slog.String("query", conf.redactQuery(query)))
+		}
+		attrs = append(attrs,
+			slog.Int("status", status),
+			slog.Int("size", size),
+			slog.Duration("duration", time.Since(start)),
+			slog.String("client_ip", ctx.ClientIP()),
+		)
+		if err != nil {
+			attrs = append(attrs, slog.Any("error", err))
+		}
+		if conf.allHeaders || len(conf.headers) > 0 {
+			attrs = append(attrs, conf.headerGroup(ctx))
+		}
+		if conf.maxBody > 0 {
+			attrs = append(attrs, conf.bodyAttrs(ctx)...)
+		}
+		level := slog.LevelInfo
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400 || err != nil:
+			level = slog.LevelWarn
+		}
+		log.LogAttrs(ctx.Context(), level, "request", attrs...)
+		return err
+	}
+}
+
+func (c *accessLogConfig) headerGroup(ctx httpx.Context) slog.Attr {
+	var attrs []any
+	add := func(name, value string) {
+		if c.redacted[strings.ToLower(name)] {
+			value = Redacted
+		}
+		attrs = append(attrs, slog.String(name, value))
+	}
+	if c.allHeaders {
+		for name, value := range ctx.AllHeaders() {
+			add(name, value)
+		}
+	}
+	for _, name := range c.headers {
+		if value := ctx.Header(name); value != "" {
+			add(http.CanonicalHeaderKey(name), value)
+		}
+	}
+	return slog.Group("headers", attrs...)
+}
+
+func (c *accessLogConfig) redactQuery(query string) string {
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return Redacted
+	}
+	return c.redactValues(values).Encode()
+}
+
+func (c *accessLogConfig) redactValues(values url.Values) url.Values {
+	for key, vals := range values {
+		if c.fields[strings.ToLower(key)] {
+			for i := range vals {
+				vals[i] = Redacted
+			}
+		}
+	}
+	return values
+}
+
+func (c *accessLogConfig) redactJSON(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, val := range v {
+			if c.fields[strings.ToLower(key)] {
+				v[key] = Redacted
+			} else {
+				v[key] = c.redactJSON(val)
+			}
+		}
+	case []any:
+		for i, val := range v {
+			v[i] = c.redactJSON(val)
+		}
+	}
+	return v
+}
+
+// accessLogBodyFactor bounds the bodies AccessLog reads to a multiple of
+// the bytes it logs, leaving room for redaction to shorten them.
+const accessLogBodyFactor = 4
+
+// bodyAttrs returns the redacted request body, cut at maxBody bytes, when
+// it can be redacted.
+func (c *accessLogConfig) bodyAttrs(ctx httpx.Context) []slog.Attr {
+	mediaType, _, _ := mime.ParseMediaType(ctx.Header("Content-Type"))
+	isJSON := mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+	isForm := mediaType == "application/x-www-form-urlencoded"
+	if !isJSON && !isForm {
+		return nil
+	}
+	raw, err := httpx.BodyRawLimit(ctx, int64(c.maxBody)*accessLogBodyFactor)
+	if errors.Is(err, httpx.ErrBodyTooLarge) {
+		return []slog.Attr{slog.Bool("body_truncated", true)}
+	}
+	if err != nil || len(raw) == 0 {
+		return nil
+	}
+	var body string
+	if isJSON {
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.UseNumber()
+		var v any
+		if dec.Decode(&v) != nil {
+			// A body that does not parse cannot be redacted.
+			return []slog.Attr{slog.String("body", Redacted)}
+		}
+		redacted, err := json.Marshal(c.redactJSON(v))
+		if err != nil {
+			return nil
+		}
+		body = string(redacted)
+	} else {
+		values, err := url.ParseQuery(string(raw))
+		if err != nil {
+			return []slog.Attr{slog.String("body", Redacted)}
+		}
+		body = c.redactValues(values).Encode()
+	}
+	if len(body) <= c.maxBody {
+		return []slog.Attr{slog.String("body", body)}
+	}
+	return []slog.Attr{
+		slog.String("body", strings.ToValidUTF8(body[:c.maxBody], "")),
+		slog.Bool("body_truncated", true),
+	}
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/go-sphere/httpx"
+)
+
+// ErrBotBlocked reports a request rejected by BlockBots.
+var ErrBotBlocked = errors.New("httpx: user agent blocked")
+
+// BlockBots rejects the requests whose User-Agent header matches one of
+// patterns, regular expressions matched case-insensitively anywhere in the
+// header, so a plain name such as "GPTBot" matches the crawler of that
+// name:
+//
+//	engine.Use(middleware.BlockBots([]string{"GPTBot", `^python-requests/`}))
+//
+// Rejected requests fail with status 403 and wrap ErrBotBlocked. Requests
+// without a User-Agent header are let through. BlockBots panics when a
+// pattern does not compile.
+func BlockBots(patterns []string) httpx.Middleware {
+	if len(patterns) == 0 {
+		return func(ctx httpx.Context) error {
+			return ctx.Next()
+		}
+	}
+	alternatives := make([]string, len(patterns))
+	for i, p := range patterns {
+		if _, err := regexp.Compile(p); err != nil {
+			panic("httpx: invalid bot pattern " + p + ": " + err.Error())
+		}
+		alternatives[i] = "(?:" + p + ")"
+	}
+	re := regexp.MustCompile("(?i)" + strings.Join(alternatives, "|"))
+	return func(ctx httpx.Context) error {
+		if ua := ctx.Header("User-Agent"); ua != "" && re.MatchString(ua) {
+			return httpx.WithStatus(http.StatusForbidden, ErrBotBlocked)
+		}
+		return ctx.Next()
+	}
+}
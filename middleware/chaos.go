@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"time"
+
+	"github.com/go-sphere/httpx"
+)
+
+// DefaultChaosTag is the route metadata key Chaos reads the fault of a route
+// from when ChaosConfig.Tag is empty.
+const DefaultChaosTag = "chaos"
+
+// ErrChaosFault reports an error response or connection abort injected by
+// Chaos.
+var ErrChaosFault = errors.New("httpx: injected fault")
+
+// ChaosFault is the set of faults injected into the requests of the routes
+// with a tag. Each rate is a probability between 0 and 1, drawn
+// independently for every request.
+type ChaosFault struct {
+	// LatencyRate is the probability of delaying a request by Latency
+	// before the handler runs.
+	LatencyRate float64
+	Latency     time.Duration
+	// ErrorRate is the probability of failing a request with ErrorStatus,
+	// http.StatusServiceUnavailable when zero, without running the handler.
+	ErrorRate   float64
+	ErrorStatus int
+	// AbortRate is the probability of closing the client connection without
+	// a response.
+	AbortRate float64
+}
+
+// ChaosConfig configures Chaos.
+type ChaosConfig struct {
+	// Tag is the route metadata key naming the fault of a route,
+	// DefaultChaosTag when empty.
+	Tag string
+	// Faults maps tag values to their faults. Routes without the tag, or
+	// with a value missing from Faults, are left alone.
+	Faults map[string]ChaosFault
+	// Default, when non-nil, is the fault of routes without the tag.
+	Default *ChaosFault
+	// Rand returns numbers in [0, 1) the rates are compared with,
+	// rand.Float64 when nil.
+	Rand func() float64
+}
+
+// Chaos injects latency, error responses and connection aborts into the
+// requests of tagged routes, for resilience testing without a fault
+// injecting proxy:
+//
+//	api.Use(middleware.Chaos(middleware.ChaosConfig{
+//		Faults: map[string]middleware.ChaosFault{
+//			"flaky": {ErrorRate: 0.1, LatencyRate: 0.5, Latency: time.Second},
+//		},
+//	}))
+//	api.GET("/orders", listOrders).Meta("chaos", "flaky")
+//
+// Route metadata is only known once the route is matched, so Chaos must be
+// added to a group or route rather than to the engine. Injected errors wrap
+// ErrChaosFault. Aborts hijack the connection on contexts with
+// httpx.Hijacker; elsewhere, as with fiber and hertz, they fail the request
+// like an injected error.
+func Chaos(config ChaosConfig) httpx.Middleware {
+	tag := config.Tag
+	if tag == "" {
+		tag = DefaultChaosTag
+	}
+	random := config.Rand
+	if random == nil {
+		random = rand.Float64
+	}
+	hit := func(rate float64) bool {
+		return rate > 0 && (rate >= 1 || random() < rate)
+	}
+	return func(ctx httpx.Context) error {
+		var fault ChaosFault
+		value, ok := httpx.RouteMeta(ctx)[tag]
+		if ok {
+			fault, ok = config.Faults[value]
+		} else if config.Default != nil {
+			fault, ok = *config.Default, true
+		}
+		if !ok {
+			return ctx.Next()
+		}
+		if hit(fault.LatencyRate) && fault.Latency > 0 {
+			timer := time.NewTimer(fault.Latency)
+			select {
+			case <-timer.C:
+			case <-ctx.Context().Done():
+				timer.Stop()
+				return ctx.Context().Err()
+			}
+		}
+		if hit(fault.AbortRate) {
+			if hj, ok := httpx.AsHijacker(ctx); ok {
+				if conn, _, err := hj.Hijack(); err == nil {
+					_ = conn.Close()
+					return nil
+				}
+			}
+			return chaosError(fault)
+		}
+		if hit(fault.ErrorRate) {
+			return chaosError(fault)
+		}
+		return ctx.Next()
+	}
+}
+
+func chaosError(fault ChaosFault) error {
+	status := fault.ErrorStatus
+	if status == 0 {
+		status = http.StatusServiceUnavailable
+	}
+	return httpx.WithStatus(int32(status), ErrChaosFault)
+}
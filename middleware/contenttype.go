@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/go-sphere/httpx"
+)
+
+// ErrUnsupportedMediaType reports a request body rejected by
+// EnforceContentType or SniffContentType.
+var ErrUnsupportedMediaType = errors.New("httpx: unsupported media type")
+
+// EnforceContentType rejects request bodies whose Content-Type is not one of
+// allowed, such as "application/json" or "image/*", with status 415 wrapping
+// ErrUnsupportedMediaType. Media type parameters such as charset are
+// ignored. Requests without a body pass; a body without a Content-Type is
+// rejected:
+//
+//	api.Use(middleware.EnforceContentType("application/json"))
+func EnforceContentType(allowed ...string) httpx.Middleware {
+	return contentTypeGuard(allowed, false)
+}
+
+// SniffContentType is EnforceContentType that also checks the first bytes
+// of the body against its declared type, to reject mislabeled bodies before
+// binding: JSON bodies must start with a JSON value, XML bodies with '<' and
+// multipart bodies with a boundary, and bodies that http.DetectContentType
+// recognizes must be of the declared type. The body is read into memory, so
+// add a body limit ahead of it.
+func SniffContentType(allowed ...string) httpx.Middleware {
+	return contentTypeGuard(allowed, true)
+}
+
+func contentTypeGuard(allowed []string, sniff bool) httpx.Middleware {
+	return func(ctx httpx.Context) error {
+		contentType := ctx.Header("Content-Type")
+		if contentType == "" {
+			body, err := httpx.BodyRawUnsafe(ctx)
+			if err != nil {
+				return err
+			}
+			if len(body) == 0 {
+				return ctx.Next()
+			}
+			return unsupportedMediaType("request body without Content-Type")
+		}
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil {
+			return unsupportedMediaType(fmt.Sprintf("invalid Content-Type %q", contentType))
+		}
+		if !matchesMediaType(allowed, mediaType) {
+			return unsupportedMediaType(fmt.Sprintf("Content-Type %q", mediaType))
+		}
+		if sniff {
+			body, err := httpx.BodyRawUnsafe(ctx)
+			if err != nil {
+				return err
+			}
+			if !sniffedAs(mediaType, body) {
+				return unsupportedMediaType(fmt.Sprintf("body is not %s", mediaType))
+			}
+		}
+		return ctx.Next()
+	}
+}
+
+func unsupportedMediaType(detail string) error {
+	return httpx.WithStatus(http.StatusUnsupportedMediaType,
+		fmt.Errorf("%w: %s", ErrUnsupportedMediaType, detail))
+}
+
+// matchesMediaType reports whether mediaType matches one of patterns, which
+// may be a whole type such as "image/*", or "*/*".
+func matchesMediaType(patterns []string, mediaType string) bool {
+	major, _, _ := strings.Cut(mediaType, "/")
+	for _, p := range patterns {
+		if p == "*/*" || strings.EqualFold(p, mediaType) {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(p, "/*"); ok && strings.EqualFold(prefix, major) {
+			return true
+		}
+	}
+	return false
+}
+
+// sniffedAs reports whether the first bytes of body may be of mediaType. An
+// empty body is left to the binders.
+func sniffedAs(mediaType string, body []byte) bool {
+	if len(body) == 0 {
+		return true
+	}
+	trimmed := bytes.TrimLeft(body, " \t\r\n")
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		return len(trimmed) > 0 && strings.IndexByte(`{["-0123456789tfn`, trimmed[0]) >= 0
+	case mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml"):
+		return bytes.HasPrefix(trimmed, []byte("<"))
+	case strings.HasPrefix(mediaType, "multipart/"):
+		return bytes.HasPrefix(trimmed, []byte("--"))
+	}
+	sniffed, _, _ := strings.Cut(http.DetectContentType(body), ";")
+	switch {
+	case mediaType == "application/octet-stream":
+		return true
+	case sniffed == "application/octet-stream" || sniffed == "text/plain":
+		// Unrecognized bytes and plain text may be of many types.
+		return true
+	}
+	return strings.EqualFold(sniffed, mediaType)
+}
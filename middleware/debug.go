@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"log/slog"
+
+	"github.com/go-sphere/httpx"
+)
+
+// DebugChain logs each layer of the matched route's chain as it starts and
+// returns, at debug level, to diagnose which middleware ran, in which order,
+// and which one ended the chain. Records carry these attributes:
+//
+//   - route: the matched route pattern (Context.FullPath)
+//   - layer: the function name of the middleware or handler
+//   - index: the position of the layer in the chain
+//   - error: the error the layer returned, on exit
+//
+// It traces the chain with httpx.TraceChain, so it must be installed as
+// engine middleware. A nil logger uses slog.Default.
+func DebugChain(logger *slog.Logger) httpx.Middleware {
+	return func(ctx httpx.Context) error {
+		log := logger
+		if log == nil {
+			log = slog.Default()
+		}
+		httpx.TraceChain(ctx, func(ev httpx.ChainEvent) {
+			attrs := []any{
+				slog.String("route", ctx.FullPath()),
+				slog.String("layer", ev.Name),
+				slog.Int("index", ev.Index),
+			}
+			if !ev.Exit {
+				log.Debug("chain enter", attrs...)
+				return
+			}
+			if ev.Err != nil {
+				attrs = append(attrs, slog.Any("error", ev.Err))
+			}
+			log.Debug("chain exit", attrs...)
+		})
+		return ctx.Next()
+	}
+}
@@ -0,0 +1,120 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/go-sphere/httpx"
+)
+
+// DefaultMaxDecompressedSize is the default limit of Decompress on the size
+// of a decoded request body.
+const DefaultMaxDecompressedSize = 10 << 20
+
+var (
+	// ErrDecompressedTooLarge reports a request body that decodes to more
+	// than the limit of Decompress.
+	ErrDecompressedTooLarge = errors.New("middleware: decompressed request body too large")
+	// ErrUnsupportedContentEncoding reports a request body in a content
+	// coding Decompress does not decode.
+	ErrUnsupportedContentEncoding = errors.New("middleware: unsupported content encoding")
+)
+
+// DecompressOption configures Decompress.
+type DecompressOption func(*decompressConfig)
+
+type decompressConfig struct {
+	maxSize int64
+}
+
+// WithMaxDecompressedSize sets the maximum size of a decoded request body,
+// DefaultMaxDecompressedSize by default.
+func WithMaxDecompressedSize(n int64) DecompressOption {
+	return func(c *decompressConfig) {
+		c.maxSize = n
+	}
+}
+
+// Decompress decodes request bodies sent with a gzip, deflate or br
+// Content-Encoding, so BodyRaw, BodyReader and the binders see the plain
+// body on every adapter. The decoded body replaces the request body through
+// httpx.RequestBodySetter, without the Content-Encoding header.
+//
+// The body is decoded in memory up to the maximum decompressed size, which
+// guards against compression bombs: larger bodies fail with status 413
+// wrapping ErrDecompressedTooLarge. Malformed bodies fail with status 400,
+// and other content codings with status 415 wrapping
+// ErrUnsupportedContentEncoding.
+func Decompress(opts ...DecompressOption) httpx.Middleware {
+	conf := decompressConfig{maxSize: DefaultMaxDecompressedSize}
+	for _, opt := range opts {
+		opt(&conf)
+	}
+	return func(ctx httpx.Context) error {
+		encoding := ctx.Header("Content-Encoding")
+		if encoding == "" {
+			return ctx.Next()
+		}
+		setter, ok := httpx.AsRequestBodySetter(ctx)
+		if !ok {
+			return fmt.Errorf("middleware: decompress: %T cannot replace the request body", ctx)
+		}
+		body, err := httpx.BodyRawUnsafe(ctx)
+		if err != nil {
+			return err
+		}
+		// Codings are listed in the order they were applied.
+		codings := strings.Split(encoding, ",")
+		for i := len(codings) - 1; i >= 0; i-- {
+			if body, err = decodeBody(strings.TrimSpace(codings[i]), body, conf.maxSize); err != nil {
+				return err
+			}
+		}
+		setter.SetRequestBody(body)
+		return ctx.Next()
+	}
+}
+
+func decodeBody(coding string, body []byte, maxSize int64) ([]byte, error) {
+	var r io.Reader
+	switch strings.ToLower(coding) {
+	case "", "identity":
+		return body, nil
+	case "gzip", "x-gzip":
+		zr, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, httpx.BadRequestError(fmt.Errorf("middleware: decompress gzip: %w", err))
+		}
+		r = zr
+	case "deflate":
+		// deflate is zlib-wrapped, though some clients send raw deflate.
+		zr, err := zlib.NewReader(bytes.NewReader(body))
+		if err != nil {
+			r = flate.NewReader(bytes.NewReader(body))
+			break
+		}
+		r = zr
+	case "br":
+		r = brotli.NewReader(bytes.NewReader(body))
+	default:
+		return nil, httpx.WithStatus(http.StatusUnsupportedMediaType,
+			fmt.Errorf("%w: %q", ErrUnsupportedContentEncoding, coding))
+	}
+	decoded, err := io.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return nil, httpx.BadRequestError(fmt.Errorf("middleware: decompress %s: %w", coding, err))
+	}
+	if int64(len(decoded)) > maxSize {
+		return nil, httpx.WithStatus(http.StatusRequestEntityTooLarge,
+			fmt.Errorf("%w: limit %d bytes", ErrDecompressedTooLarge, maxSize))
+	}
+	return decoded, nil
+}
@@ -0,0 +1,177 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/go-sphere/httpx"
+)
+
+// DefaultIPFilterTag is the route metadata key IPFilter reads the mode of a
+// route from, see WithIPFilterTag.
+const DefaultIPFilterTag = "ipfilter"
+
+// ErrIPRejected reports a request rejected by IPFilter.
+var ErrIPRejected = errors.New("httpx: client IP rejected")
+
+// IPFilterMode is what IPFilter does with the requests its lists reject.
+type IPFilterMode int
+
+const (
+	// IPFilterEnforce fails rejected requests with status 403.
+	IPFilterEnforce IPFilterMode = iota
+	// IPFilterReport only logs rejected requests, to try lists out before
+	// enforcing them.
+	IPFilterReport
+	// IPFilterOff lets every request through without checking it.
+	IPFilterOff
+)
+
+func (m IPFilterMode) String() string {
+	switch m {
+	case IPFilterEnforce:
+		return "enforce"
+	case IPFilterReport:
+		return "report"
+	case IPFilterOff:
+		return "off"
+	default:
+		return fmt.Sprintf("IPFilterMode(%d)", int(m))
+	}
+}
+
+// IPFilterOption configures IPFilter.
+type IPFilterOption func(*ipFilterConfig)
+
+type ipFilterConfig struct {
+	logger *slog.Logger
+	tag    string
+}
+
+// WithIPFilterLogger sets the logger of rejected requests, slog.Default
+// when unset.
+func WithIPFilterLogger(logger *slog.Logger) IPFilterOption {
+	return func(c *ipFilterConfig) {
+		c.logger = logger
+	}
+}
+
+// WithIPFilterTag sets the route metadata key naming the mode of a route,
+// DefaultIPFilterTag by default.
+func WithIPFilterTag(tag string) IPFilterOption {
+	return func(c *ipFilterConfig) {
+		c.tag = tag
+	}
+}
+
+// ParseCIDRs parses CIDR prefixes such as "10.0.0.0/8" for IPFilter. A bare
+// address stands for itself alone.
+func ParseCIDRs(cidrs ...string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(cidrs))
+	for _, s := range cidrs {
+		if !strings.Contains(s, "/") {
+			addr, err := netip.ParseAddr(s)
+			if err != nil {
+				return nil, err
+			}
+			addr = addr.Unmap()
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(s)
+		if err != nil {
+			return nil, err
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// IPFilter rejects requests by the client IP, as reported by
+// Context.ClientIP: the peer address, or behind the proxies set with the
+// WithTrustedProxies option of the adapter, the address they forwarded. A
+// client in deny is rejected; when allow is not empty, so is a client outside
+// it. An unparseable client IP is rejected only by a non-empty allow list.
+//
+// Rejections are logged at warning level with the client IP, the route and
+// the reason, and handled according to mode. Routes override the mode with
+// their metadata, naming one of "enforce", "report" or "off":
+//
+//	admin := engine.Group("/admin", middleware.IPFilter(office, nil, middleware.IPFilterEnforce))
+//	admin.GET("/status", status).Meta("ipfilter", "off")
+//
+// Route metadata is only known once the route is matched, so overrides take
+// effect when IPFilter is added to a group or route. Enforced rejections
+// fail with status 403 and wrap ErrIPRejected.
+func IPFilter(allow, deny []netip.Prefix, mode IPFilterMode, opts ...IPFilterOption) httpx.Middleware {
+	conf := ipFilterConfig{tag: DefaultIPFilterTag}
+	for _, opt := range opts {
+		opt(&conf)
+	}
+	return func(ctx httpx.Context) error {
+		routeMode := mode
+		if value, ok := httpx.RouteMeta(ctx)[conf.tag]; ok {
+			switch value {
+			case "enforce":
+				routeMode = IPFilterEnforce
+			case "report":
+				routeMode = IPFilterReport
+			case "off":
+				routeMode = IPFilterOff
+			}
+		}
+		if routeMode == IPFilterOff {
+			return ctx.Next()
+		}
+		ip := ctx.ClientIP()
+		reason := ipRejection(ip, allow, deny)
+		if reason == "" {
+			return ctx.Next()
+		}
+		logger := conf.logger
+		if logger == nil {
+			logger = slog.Default()
+		}
+		logger.Warn("ip filter rejection",
+			slog.String("ip", ip),
+			slog.String("method", ctx.Method()),
+			slog.String("route", ctx.FullPath()),
+			slog.String("reason", reason),
+			slog.String("mode", routeMode.String()),
+		)
+		if routeMode == IPFilterReport {
+			return ctx.Next()
+		}
+		return httpx.WithStatus(http.StatusForbidden, ErrIPRejected)
+	}
+}
+
+// ipRejection returns why the lists reject ip, or "" when they accept it.
+func ipRejection(ip string, allow, deny []netip.Prefix) string {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		if len(allow) > 0 {
+			return "invalid ip"
+		}
+		return ""
+	}
+	addr = addr.Unmap().WithZone("")
+	for _, p := range deny {
+		if p.Contains(addr) {
+			return "denied"
+		}
+	}
+	if len(allow) == 0 {
+		return ""
+	}
+	for _, p := range allow {
+		if p.Contains(addr) {
+			return ""
+		}
+	}
+	return "not allowed"
+}
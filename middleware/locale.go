@@ -0,0 +1,80 @@
+package middleware
+
+import "github.com/go-sphere/httpx"
+
+// LocaleOption configures Locale.
+type LocaleOption func(*localeConfig)
+
+type localeConfig struct {
+	defaultLocale string
+	queryParam    string
+	cookie        string
+}
+
+// WithDefaultLocale sets the locale used when the request accepts none of
+// the supported ones, instead of the first locale of the store.
+func WithDefaultLocale(locale string) LocaleOption {
+	return func(c *localeConfig) {
+		c.defaultLocale = locale
+	}
+}
+
+// WithLocaleQuery lets the query parameter name, such as "lang", choose the
+// locale ahead of the Accept-Language header.
+func WithLocaleQuery(name string) LocaleOption {
+	return func(c *localeConfig) {
+		c.queryParam = name
+	}
+}
+
+// WithLocaleCookie lets the cookie name choose the locale ahead of the
+// Accept-Language header, after the query parameter.
+func WithLocaleCookie(name string) LocaleOption {
+	return func(c *localeConfig) {
+		c.cookie = name
+	}
+}
+
+// Locale negotiates the request's locale among the locales of store from
+// the Accept-Language header, see httpx.NegotiateLocale, and stores it with
+// httpx.SetLocale, so handlers translate with httpx.T. The locale is sent
+// in the Content-Language response header.
+func Locale(store httpx.MessageStore, opts ...LocaleOption) httpx.Middleware {
+	supported := store.Locales()
+	conf := localeConfig{}
+	if len(supported) > 0 {
+		conf.defaultLocale = supported[0]
+	}
+	for _, opt := range opts {
+		opt(&conf)
+	}
+	return func(ctx httpx.Context) error {
+		locale := negotiateLocale(ctx, conf, supported)
+		httpx.SetLocale(ctx, locale, store)
+		if locale != "" {
+			ctx.SetHeader("Content-Language", locale)
+		}
+		return ctx.Next()
+	}
+}
+
+func negotiateLocale(ctx httpx.Context, conf localeConfig, supported []string) string {
+	var explicit []httpx.LangQ
+	if conf.queryParam != "" {
+		if lang := ctx.Query(conf.queryParam); lang != "" {
+			explicit = append(explicit, httpx.LangQ{Tag: lang, Q: 1})
+		}
+	}
+	if conf.cookie != "" {
+		if lang, err := ctx.Cookie(conf.cookie); err == nil && lang != "" {
+			explicit = append(explicit, httpx.LangQ{Tag: lang, Q: 1})
+		}
+	}
+	if locale, ok := httpx.NegotiateLocale(explicit, supported); ok {
+		return locale
+	}
+	if locale, ok := httpx.NegotiateLocale(httpx.AcceptLanguages(ctx), supported); ok {
+		return locale
+	}
+	return conf.defaultLocale
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"log/slog"
+	"strings"
+
+	"github.com/go-sphere/httpx"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestIDHeader is the header WithLogger reads the request ID from.
+const RequestIDHeader = "X-Request-ID"
+
+// WithLogger derives a request-scoped logger from base and stores it with
+// httpx.SetLogger, so handlers retrieve it with httpx.LoggerFrom. The logger
+// carries these attributes when available:
+//
+//   - request_id: the ID assigned by RequestID, or the X-Request-ID header
+//   - route: the matched route pattern (Context.FullPath)
+//   - trace_id: the active OpenTelemetry span, or the W3C traceparent header
+//
+// A nil base uses slog.Default.
+func WithLogger(base *slog.Logger) httpx.Middleware {
+	return func(ctx httpx.Context) error {
+		logger := base
+		if logger == nil {
+			logger = slog.Default()
+		}
+		var attrs []any
+		if id := RequestIDFrom(ctx); id != "" {
+			attrs = append(attrs, slog.String("request_id", id))
+		}
+		if route := ctx.FullPath(); route != "" {
+			attrs = append(attrs, slog.String("route", route))
+		}
+		if traceID := traceIDOf(ctx); traceID != "" {
+			attrs = append(attrs, slog.String("trace_id", traceID))
+		}
+		httpx.SetLogger(ctx, logger.With(attrs...))
+		return ctx.Next()
+	}
+}
+
+// traceIDOf returns the trace ID of the span in the request context, falling
+// back to the incoming traceparent header when no span was started.
+func traceIDOf(ctx httpx.Context) string {
+	if sc := trace.SpanContextFromContext(ctx.Context()); sc.HasTraceID() {
+		return sc.TraceID().String()
+	}
+	// traceparent: version-traceid-parentid-flags
+	parts := strings.Split(ctx.Header("traceparent"), "-")
+	if len(parts) != 4 || len(parts[1]) != 32 {
+		return ""
+	}
+	if id, err := trace.TraceIDFromHex(parts[1]); err == nil {
+		return id.String()
+	}
+	return ""
+}
@@ -0,0 +1,137 @@
+// Package middleware provides framework-independent httpx middleware.
+package middleware
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/go-sphere/httpx"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// UnmatchedRoute is the route label used for requests that matched no route pattern.
+const UnmatchedRoute = "unmatched"
+
+type metrics struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	size     *prometheus.HistogramVec
+	inFlight prometheus.Gauge
+	// deprecated counts requests to routes wrapped with httpx.Deprecated.
+	deprecated *prometheus.CounterVec
+}
+
+// Metrics records Prometheus request metrics on registerer:
+//
+//   - http_requests_total: counter of handled requests
+//   - http_request_duration_seconds: histogram of handling latency
+//   - http_response_size_bytes: histogram of response body sizes
+//   - http_requests_in_flight: gauge of requests currently being handled
+//   - http_deprecated_requests_total: counter of requests handled by
+//     handlers wrapped with httpx.Deprecated, labeled by method and route
+//
+// Requests are labeled by method, route pattern (Context.FullPath) and status
+// class such as "2xx". Using the route pattern rather than the raw path keeps
+// label cardinality bounded. Registering Metrics several times on the same
+// registerer reuses the existing collectors.
+func Metrics(registerer prometheus.Registerer) httpx.Middleware {
+	labels := []string{"method", "route", "status"}
+	m := metrics{
+		requests: register(registerer, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "Total number of HTTP requests handled.",
+		}, labels)),
+		duration: register(registerer, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "HTTP request handling latency in seconds.",
+			Buckets: prometheus.DefBuckets,
+		}, labels)),
+		size: register(registerer, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_response_size_bytes",
+			Help:    "HTTP response body size in bytes.",
+			Buckets: prometheus.ExponentialBuckets(100, 10, 6),
+		}, labels)),
+		inFlight: register(registerer, prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "http_requests_in_flight",
+			Help: "Number of HTTP requests currently being handled.",
+		})),
+		deprecated: register(registerer, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_deprecated_requests_total",
+			Help: "Total number of HTTP requests to deprecated routes.",
+		}, []string{"method", "route"})),
+	}
+
+	return func(ctx httpx.Context) error {
+		start := time.Now()
+		m.inFlight.Inc()
+		defer m.inFlight.Dec()
+
+		err := ctx.Next()
+
+		route := ctx.FullPath()
+		if route == "" {
+			route = UnmatchedRoute
+		}
+		status, size := responseStatus(ctx, err)
+		values := []string{ctx.Method(), route, statusClass(status)}
+		m.requests.WithLabelValues(values...).Inc()
+		m.duration.WithLabelValues(values...).Observe(time.Since(start).Seconds())
+		m.size.WithLabelValues(values...).Observe(float64(size))
+		if httpx.IsDeprecated(ctx) {
+			m.deprecated.WithLabelValues(ctx.Method(), route).Inc()
+		}
+		return err
+	}
+}
+
+// MetricsHandler serves the metrics gathered by gatherer with the
+// promhttp.HandlerFor handler of opts, which negotiates the exposition format
+// and compression from the request headers.
+func MetricsHandler(gatherer prometheus.Gatherer, opts promhttp.HandlerOpts) httpx.Handler {
+	return httpx.FromHTTPHandler(promhttp.HandlerFor(gatherer, opts))
+}
+
+// MountMetrics registers MetricsHandler for gatherer with default options as a
+// GET route at path, typically "/metrics".
+func MountMetrics(r httpx.Router, path string, gatherer prometheus.Gatherer) {
+	r.GET(path, MetricsHandler(gatherer, promhttp.HandlerOpts{}))
+}
+
+func register[C prometheus.Collector](registerer prometheus.Registerer, c C) C {
+	if err := registerer.Register(c); err != nil {
+		var are prometheus.AlreadyRegisteredError
+		if errors.As(err, &are) {
+			if existing, ok := are.ExistingCollector.(C); ok {
+				return existing
+			}
+		}
+		panic(err)
+	}
+	return c
+}
+
+// responseStatus resolves the final status and body size. When the chain
+// returned an error the response may not have been written yet, so the status
+// is derived from the error with httpx.ParseError.
+func responseStatus(ctx httpx.Context, err error) (status, size int) {
+	if ri, ok := httpx.AsResponseInfo(ctx); ok {
+		status = ri.StatusCode()
+	}
+	if rs, ok := httpx.AsResponseSizer(ctx); ok {
+		size = rs.BodySize()
+	}
+	if err != nil && status < 400 {
+		_, s, _ := httpx.ParseError(err)
+		status = int(s)
+	}
+	return status, size
+}
+
+func statusClass(status int) string {
+	if status < 100 || status > 599 {
+		return "unknown"
+	}
+	return strconv.Itoa(status/100) + "xx"
+}
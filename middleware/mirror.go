@@ -0,0 +1,153 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-sphere/httpx"
+)
+
+// DefaultMirrorTimeout is the default time limit of a mirrored request.
+const DefaultMirrorTimeout = 10 * time.Second
+
+// DefaultMirrorMaxInFlight is the default limit of Mirror on the number of
+// mirrored requests in flight.
+const DefaultMirrorMaxInFlight = 64
+
+// MirrorOption configures Mirror.
+type MirrorOption func(*mirrorConfig)
+
+type mirrorConfig struct {
+	transport   http.RoundTripper
+	timeout     time.Duration
+	maxInFlight int
+	filter      func(httpx.Context) bool
+}
+
+// WithMirrorTransport sets the transport mirrored requests are sent with.
+// The default is http.DefaultTransport.
+func WithMirrorTransport(transport http.RoundTripper) MirrorOption {
+	return func(c *mirrorConfig) {
+		c.transport = transport
+	}
+}
+
+// WithMirrorTimeout sets the time limit of a mirrored request,
+// DefaultMirrorTimeout by default.
+func WithMirrorTimeout(d time.Duration) MirrorOption {
+	return func(c *mirrorConfig) {
+		c.timeout = d
+	}
+}
+
+// WithMirrorMaxInFlight sets the number of mirrored requests in flight
+// beyond which requests are not mirrored, DefaultMirrorMaxInFlight by
+// default.
+func WithMirrorMaxInFlight(n int) MirrorOption {
+	return func(c *mirrorConfig) {
+		c.maxInFlight = n
+	}
+}
+
+// WithMirrorFilter selects the requests Mirror samples, such as those of a
+// route or method. By default every request is sampled.
+func WithMirrorFilter(filter func(httpx.Context) bool) MirrorOption {
+	return func(c *mirrorConfig) {
+		c.filter = filter
+	}
+}
+
+// Mirror replays a copy of a sampleRate fraction of requests to the shadow
+// backend at target, for testing a new backend with production traffic. A
+// copy has the method, path, query, headers and body of the request; the
+// path is joined with the path of target. Copies are sent in the background
+// and their responses discarded, so the shadow backend neither delays nor
+// changes the responses of the application.
+//
+// The body is read with BodyRaw, which keeps it readable by the handler.
+// Copies beyond the in-flight limit are dropped rather than queued.
+func Mirror(target *url.URL, sampleRate float64, opts ...MirrorOption) httpx.Middleware {
+	conf := mirrorConfig{
+		transport:   http.DefaultTransport,
+		timeout:     DefaultMirrorTimeout,
+		maxInFlight: DefaultMirrorMaxInFlight,
+	}
+	for _, opt := range opts {
+		opt(&conf)
+	}
+	inFlight := make(chan struct{}, max(conf.maxInFlight, 1))
+	client := &http.Client{
+		Transport: conf.transport,
+		Timeout:   conf.timeout,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	return func(ctx httpx.Context) error {
+		if sampleRate <= 0 || (sampleRate < 1 && rand.Float64() >= sampleRate) {
+			return ctx.Next()
+		}
+		if conf.filter != nil && !conf.filter(ctx) {
+			return ctx.Next()
+		}
+		select {
+		case inFlight <- struct{}{}:
+		default:
+			return ctx.Next()
+		}
+		req, err := mirrorRequest(ctx, target)
+		if err != nil {
+			<-inFlight
+			return ctx.Next()
+		}
+		go func() {
+			defer func() { <-inFlight }()
+			resp, err := client.Do(req)
+			if err != nil {
+				return
+			}
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}()
+		return ctx.Next()
+	}
+}
+
+// mirrorRequest copies the request of ctx for target. The copy does not
+// share anything with the request, so it can be sent after the handler
+// returns.
+func mirrorRequest(ctx httpx.Context, target *url.URL) (*http.Request, error) {
+	body, err := ctx.BodyRaw()
+	if err != nil {
+		return nil, err
+	}
+	u := *target
+	u.Path = strings.TrimSuffix(target.Path, "/") + ctx.Path()
+	u.RawPath = ""
+	u.RawQuery = ctx.RawQuery()
+	req, err := http.NewRequestWithContext(context.Background(), ctx.Method(), u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header = http.Header(ctx.Headers()).Clone()
+	if req.Header == nil {
+		req.Header = make(http.Header)
+	}
+	for _, key := range hopHeaders {
+		req.Header.Del(key)
+	}
+	return req, nil
+}
+
+// hopHeaders are the headers of a connection rather than of a request,
+// which a copy does not carry.
+var hopHeaders = []string{
+	"Connection", "Content-Length", "Keep-Alive", "Proxy-Authorization", "Proxy-Connection",
+	"Te", "Trailer", "Transfer-Encoding", "Upgrade",
+}
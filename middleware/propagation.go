@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/go-sphere/httpx"
+)
+
+// RequestIDKey is the StateStore key under which RequestID stores the ID.
+const RequestIDKey = "httpx.request_id"
+
+type requestIDContextKey struct{}
+
+// TraceContextHeaders are the W3C Trace Context and Baggage headers that
+// Propagate forwards by default.
+var TraceContextHeaders = []string{"traceparent", "tracestate", "baggage"}
+
+// RequestID assigns every request an ID: the incoming X-Request-ID header, or
+// a random 128-bit hex ID when it is absent. The ID is echoed in the response
+// header, kept in the request's context.Context for RequestIDFromContext and
+// recorded with httpx.SetOutgoingHeader, so downstream calls made with
+// httpx.OutgoingTransport carry it. WithLogger picks it up when it runs
+// after RequestID.
+func RequestID() httpx.Middleware {
+	return func(ctx httpx.Context) error {
+		id := ctx.Header(RequestIDHeader)
+		if id == "" {
+			id = newRequestID()
+		}
+		ctx.Set(RequestIDKey, id)
+		ctx.SetContext(context.WithValue(ctx.Context(), requestIDContextKey{}, id))
+		ctx.SetHeader(RequestIDHeader, id)
+		httpx.SetOutgoingHeader(ctx, RequestIDHeader, id)
+		return ctx.Next()
+	}
+}
+
+// RequestIDFrom returns the ID assigned by RequestID, falling back to the
+// incoming X-Request-ID header.
+func RequestIDFrom(ctx httpx.Context) string {
+	if v, ok := ctx.Get(RequestIDKey); ok {
+		if id, ok := v.(string); ok {
+			return id
+		}
+	}
+	return ctx.Header(RequestIDHeader)
+}
+
+// RequestIDFromContext returns the ID assigned by RequestID to the request
+// of ctx, which may also be a context returned by httpx.Detach, or "" when
+// there is none.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDContextKey{}).(string)
+	return id
+}
+
+// Propagate records the given incoming request headers with
+// httpx.SetOutgoingHeader so they are forwarded to downstream calls. Without
+// arguments it forwards TraceContextHeaders.
+func Propagate(headers ...string) httpx.Middleware {
+	if len(headers) == 0 {
+		headers = TraceContextHeaders
+	}
+	return func(ctx httpx.Context) error {
+		for _, key := range headers {
+			if value := ctx.Header(key); value != "" {
+				httpx.SetOutgoingHeader(ctx, key, value)
+			}
+		}
+		return ctx.Next()
+	}
+}
+
+func newRequestID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
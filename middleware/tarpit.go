@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"strings"
+	"time"
+
+	"github.com/go-sphere/httpx"
+)
+
+// DefaultTarpitPaths are paths vulnerability scanners commonly probe, for
+// services that serve none of them.
+var DefaultTarpitPaths = []string{
+	"/.env",
+	"/.git/*",
+	"/wp-admin/*",
+	"/wp-login.php",
+	"/xmlrpc.php",
+	"/phpmyadmin/*",
+	"/cgi-bin/*",
+	"/vendor/phpunit/*",
+}
+
+// Tarpit delays the requests for paths by delay before passing them on,
+// usually to a 404, slowing down scanners probing for well-known weak
+// spots while costing the server only an idle goroutine. A path ending in
+// "*" matches every path it is a prefix of; others match exactly:
+//
+//	engine.Use(middleware.Tarpit(middleware.DefaultTarpitPaths, 10*time.Second))
+//
+// Scanners probe paths without routes, so Tarpit must be added with Use on
+// the engine, which runs it for unmatched requests too. A request whose
+// client gives up is ended with the error of its context.
+func Tarpit(paths []string, delay time.Duration) httpx.Middleware {
+	exact := make(map[string]bool, len(paths))
+	var prefixes []string
+	for _, p := range paths {
+		if prefix, ok := strings.CutSuffix(p, "*"); ok {
+			prefixes = append(prefixes, prefix)
+		} else {
+			exact[p] = true
+		}
+	}
+	trapped := func(path string) bool {
+		if exact[path] {
+			return true
+		}
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(path, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+	return func(ctx httpx.Context) error {
+		if delay <= 0 || !trapped(ctx.Path()) {
+			return ctx.Next()
+		}
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			return ctx.Next()
+		case <-ctx.Context().Done():
+			return ctx.Context().Err()
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-sphere/httpx"
+)
+
+// Transaction is a transaction begun by Tx, such as a *sql.Tx.
+type Transaction interface {
+	Commit() error
+	Rollback() error
+}
+
+// TxKey is the key Tx stores the transaction of a request under. Handlers
+// get it with TxKey.Get, and code holding only the context.Context of the
+// request with TxKey.Value:
+//
+//	tx, _ := middleware.TxKey.MustGet(ctx).(*sql.Tx)
+var TxKey = httpx.NewKey[Transaction]("tx")
+
+// Tx runs the rest of the chain in a transaction begun by begin and stored
+// under TxKey. The transaction is committed when the chain returns nil with
+// a response status below 400, and rolled back when it returns an error,
+// responds with an error status or panics; the panic then continues. An
+// error of begin is returned without running the chain. A failed commit is
+// returned for the error handler, but a response the handler wrote may
+// already be sent, so handlers that must report it should respond after
+// their last write.
+func Tx(begin func(ctx httpx.Context) (Transaction, error)) httpx.Middleware {
+	return func(ctx httpx.Context) (err error) {
+		tx, err := begin(ctx)
+		if err != nil {
+			return err
+		}
+		httpx.SetTyped(ctx, TxKey, tx)
+		done := false
+		defer func() {
+			if !done {
+				_ = tx.Rollback()
+			}
+		}()
+
+		err = ctx.Next()
+		done = true
+		if err != nil || failedStatus(ctx) {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				return errors.Join(err, rbErr)
+			}
+			return err
+		}
+		return tx.Commit()
+	}
+}
+
+// failedStatus reports whether the response status of ctx is an error
+// status, when the adapter exposes it.
+func failedStatus(ctx httpx.Context) bool {
+	ri, ok := httpx.AsResponseInfo(ctx)
+	return ok && ri.StatusCode() >= http.StatusBadRequest
+}
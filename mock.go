@@ -0,0 +1,177 @@
+package httpx
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MockResponse describes a canned response served in place of a real handler.
+//
+// Body holds a JSON value. When ContentType is empty, a non-empty Body is
+// served as "application/json"; for non-JSON content types a JSON string body
+// is served as its raw text. Latency delays the response, and Jitter adds
+// a random extra delay in [0, Jitter) to simulate a slow dependency.
+type MockResponse struct {
+	Status      int               `json:"status"`
+	ContentType string            `json:"content_type,omitempty"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	Body        json.RawMessage   `json:"body,omitempty"`
+	Latency     time.Duration     `json:"-"`
+	Jitter      time.Duration     `json:"-"`
+}
+
+// UnmarshalJSON decodes a MockResponse, accepting latency and jitter as
+// duration strings such as "150ms".
+func (m *MockResponse) UnmarshalJSON(data []byte) error {
+	type plain MockResponse
+	aux := struct {
+		*plain
+		Latency string `json:"latency"`
+		Jitter  string `json:"jitter"`
+	}{plain: (*plain)(m)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	var err error
+	if aux.Latency != "" {
+		if m.Latency, err = time.ParseDuration(aux.Latency); err != nil {
+			return fmt.Errorf("httpx: invalid mock latency %q: %w", aux.Latency, err)
+		}
+	}
+	if aux.Jitter != "" {
+		if m.Jitter, err = time.ParseDuration(aux.Jitter); err != nil {
+			return fmt.Errorf("httpx: invalid mock jitter %q: %w", aux.Jitter, err)
+		}
+	}
+	return nil
+}
+
+// Mocks maps route tags to canned responses for dependency-free local development.
+//
+// Routes opt in by wrapping their handler with Wrap and a tag. When the mock set
+// is enabled and a response is configured for the tag, the real handler is
+// skipped and the configured response is served after the simulated latency.
+// When disabled, wrapped handlers run unchanged.
+type Mocks struct {
+	enabled   bool
+	mu        sync.RWMutex
+	responses map[string]MockResponse
+}
+
+// NewMocks creates an empty mock set. The enabled flag is typically driven by
+// a dev-mode setting and should be false in production.
+func NewMocks(enabled bool) *Mocks {
+	return &Mocks{
+		enabled:   enabled,
+		responses: make(map[string]MockResponse),
+	}
+}
+
+// LoadMocks creates a mock set from a JSON object keyed by route tag:
+//
+//	{
+//	  "users.list": {"status": 200, "latency": "150ms", "body": [{"id": 1}]}
+//	}
+func LoadMocks(r io.Reader, enabled bool) (*Mocks, error) {
+	responses := make(map[string]MockResponse)
+	if err := json.NewDecoder(r).Decode(&responses); err != nil {
+		return nil, fmt.Errorf("httpx: decode mocks: %w", err)
+	}
+	m := NewMocks(enabled)
+	for tag, resp := range responses {
+		m.Set(tag, resp)
+	}
+	return m, nil
+}
+
+// Enabled reports whether mock responses are served.
+func (m *Mocks) Enabled() bool {
+	return m.enabled
+}
+
+// Set configures the mock response for a tag, replacing any previous one.
+func (m *Mocks) Set(tag string, resp MockResponse) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.responses[tag] = resp
+}
+
+// Lookup returns the mock response configured for a tag.
+func (m *Mocks) Lookup(tag string) (MockResponse, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	resp, ok := m.responses[tag]
+	return resp, ok
+}
+
+// Wrap returns a handler that serves the mock configured for tag when the set
+// is enabled, and falls through to h otherwise.
+func (m *Mocks) Wrap(tag string, h Handler) Handler {
+	if !m.enabled {
+		return h
+	}
+	return func(ctx Context) error {
+		resp, ok := m.Lookup(tag)
+		if !ok {
+			return h(ctx)
+		}
+		if err := sleepContext(ctx, resp.delay()); err != nil {
+			return err
+		}
+		return writeMock(ctx, resp)
+	}
+}
+
+func (m MockResponse) delay() time.Duration {
+	d := m.Latency
+	if m.Jitter > 0 {
+		d += rand.N(m.Jitter)
+	}
+	return d
+}
+
+func sleepContext(ctx Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Context().Done():
+		return ctx.Context().Err()
+	}
+}
+
+func writeMock(ctx Context, resp MockResponse) error {
+	status := resp.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	for k, v := range resp.Headers {
+		ctx.SetHeader(k, v)
+	}
+	if len(resp.Body) == 0 {
+		return ctx.NoContent(status)
+	}
+	contentType := resp.ContentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	body := []byte(resp.Body)
+	if !strings.Contains(contentType, "json") {
+		// Non-JSON bodies are declared as JSON strings; serve the raw text.
+		var text string
+		if err := json.Unmarshal(body, &text); err == nil {
+			body = []byte(text)
+		}
+	}
+	return ctx.Bytes(status, body, contentType)
+}
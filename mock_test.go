@@ -0,0 +1,62 @@
+package httpx
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadMocks(t *testing.T) {
+	src := `{
+		"users.list": {"status": 200, "latency": "150ms", "jitter": "10ms", "body": [{"id": 1}]},
+		"health": {"status": 204}
+	}`
+	m, err := LoadMocks(strings.NewReader(src), true)
+	if err != nil {
+		t.Fatalf("LoadMocks() error = %v", err)
+	}
+	if !m.Enabled() {
+		t.Fatalf("Enabled() = false, want true")
+	}
+
+	resp, ok := m.Lookup("users.list")
+	if !ok {
+		t.Fatalf("Lookup(users.list) missing")
+	}
+	if resp.Status != 200 || resp.Latency != 150*time.Millisecond || resp.Jitter != 10*time.Millisecond {
+		t.Fatalf("unexpected mock response: %+v", resp)
+	}
+	if string(resp.Body) != `[{"id": 1}]` {
+		t.Fatalf("Body = %s", resp.Body)
+	}
+	if d := resp.delay(); d < resp.Latency || d >= resp.Latency+resp.Jitter {
+		t.Fatalf("delay() = %v, want in [%v, %v)", d, resp.Latency, resp.Latency+resp.Jitter)
+	}
+
+	if _, ok := m.Lookup("missing"); ok {
+		t.Fatalf("Lookup(missing) should not be found")
+	}
+}
+
+func TestLoadMocksInvalidLatency(t *testing.T) {
+	_, err := LoadMocks(strings.NewReader(`{"a": {"latency": "soon"}}`), true)
+	if err == nil {
+		t.Fatalf("LoadMocks() should fail on invalid latency")
+	}
+}
+
+func TestMocksWrapDisabled(t *testing.T) {
+	m := NewMocks(false)
+	m.Set("tag", MockResponse{Status: 200})
+	called := false
+	h := m.Wrap("tag", func(Context) error {
+		called = true
+		return nil
+	})
+	if err := h(nil); err != nil {
+		t.Fatalf("handler error = %v", err)
+	}
+	if !called {
+		t.Fatalf("disabled mocks should call the real handler")
+	}
+}
@@ -0,0 +1,72 @@
+package httpx
+
+import (
+	"io"
+	"net/http"
+	"strings"
+)
+
+// MountEngine serves the routes of engine under prefix of r, so applications
+// can be composed of engines built independently, on any adapter. Requests
+// to prefix and below are passed to engine with prefix stripped from their
+// path, like http.StripPrefix, so a route "/users" of engine is served at
+// prefix+"/users". The middleware of r runs before engine's own.
+//
+// engine serves in-process and need not be started; its lifecycle hooks do
+// not run. Engines implementing http.Handler, such as those of ginx, echox
+// and lambdax, stream their responses, and on contexts implementing
+// HTTPInterop write to the real response writer. Other engines, such as
+// those of fiberx and hertzx, serve through Engine.Test, which buffers the
+// request and response bodies.
+func MountEngine(r Router, prefix string, engine Engine) {
+	path, param := FixWildcardPathIfNeed(r, JoinPaths(prefix, "/*path"))
+	h := mountHandler(engineHandler(engine), param)
+	if strings.Trim(prefix, "/") != "" {
+		r.Any(strings.TrimSuffix(prefix, "/"), h)
+	}
+	r.Any(path, h)
+}
+
+// engineHandler returns engine as an http.Handler, serving through
+// Engine.Test when it does not implement one.
+func engineHandler(engine Engine) http.Handler {
+	if h, ok := engine.(http.Handler); ok {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp, err := engine.Test(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+		for key, values := range resp.Header {
+			w.Header()[key] = values
+		}
+		w.WriteHeader(resp.StatusCode)
+		_, _ = io.Copy(w, resp.Body)
+	})
+}
+
+// mountHandler serves requests through h with the path set to the value of
+// the wildcard param.
+func mountHandler(h http.Handler, param string) Handler {
+	return func(ctx Context) error {
+		path := "/" + strings.TrimPrefix(ctx.Param(param), "/")
+		rewrite := func(req *http.Request) {
+			req.URL.Path = path
+			req.URL.RawPath = ""
+			req.RequestURI = req.URL.RequestURI()
+		}
+		if hi, ok := AsHTTPInterop(ctx); ok {
+			req := hi.HTTPRequest().Clone(ctx.Context())
+			rewrite(req)
+			h.ServeHTTP(proxyWriter{hi.HTTPResponseWriter()}, req)
+			return nil
+		}
+		var err error
+		return serveHandlerEmulated(ctx, h, rewrite, &err)
+	}
+}
@@ -0,0 +1,235 @@
+// Package openapix generates an OpenAPI 3.1 document from httpx routes.
+//
+// Routes registered through API.Route carry a Spec with their request and
+// response models. Request models use the binding tags of httpx: fields
+// tagged uri, query or header become parameters, and the remaining fields
+// form a JSON body, or a form body when they carry form tags. binding:"required",
+// default and doc tags add required flags, defaults and descriptions.
+//
+//	api := openapix.New(openapix.Info{Title: "Users", Version: "1.0.0"})
+//	api.Route(r, openapix.Spec{
+//		Method:    http.MethodPost,
+//		Path:      "/users",
+//		Summary:   "Create a user",
+//		Request:   CreateUser{},
+//		Responses: map[int]any{http.StatusCreated: User{}},
+//	}, createUser)
+//	api.Mount(r, openapix.WithSwaggerUI("/docs"))
+package openapix
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-sphere/httpx"
+)
+
+// Spec describes a route for the OpenAPI document.
+type Spec struct {
+	Method      string
+	Path        string
+	OperationID string
+	Summary     string
+	Description string
+	Tags        []string
+	Deprecated  bool
+	// Request is a value of the request model, such as CreateUser{}.
+	Request any
+	// Responses maps status codes to a value of the response model, or nil
+	// for responses without a body. A route without responses documents a
+	// bodiless 200 response.
+	Responses map[int]any
+}
+
+// API collects the operations of the routes registered through it. It is
+// safe for concurrent use.
+type API struct {
+	mu      sync.Mutex
+	doc     Document
+	schemas *schemas
+}
+
+// New returns an API with no operations.
+func New(info Info) *API {
+	return &API{
+		doc:     Document{OpenAPI: Version, Info: info, Paths: make(map[string]PathItem)},
+		schemas: newSchemas(),
+	}
+}
+
+// Route registers h on r like r.Handle and adds its operation to the
+// document, under the full path of the route including group prefixes.
+func (a *API) Route(r httpx.Router, spec Spec, h httpx.Handler) {
+	r.Handle(spec.Method, spec.Path, h)
+	a.Add(httpx.JoinPaths(r.BasePath(), spec.Path), spec)
+}
+
+// Add adds an operation for a route at the full path fullPath, which uses the
+// httpx syntax for parameters, without registering a handler.
+func (a *API) Add(fullPath string, spec Spec) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	method := strings.ToUpper(spec.Method)
+	op := &Operation{
+		OperationID: spec.OperationID,
+		Summary:     spec.Summary,
+		Description: spec.Description,
+		Tags:        spec.Tags,
+		Deprecated:  spec.Deprecated,
+		Responses:   make(map[string]*Response),
+	}
+	withBody := method != http.MethodGet && method != http.MethodHead
+	op.Parameters, op.RequestBody = a.schemas.request(spec.Request, withBody)
+
+	path, names := openAPIPath(fullPath)
+	for _, name := range names {
+		if !hasParameter(op.Parameters, "path", name) {
+			op.Parameters = append(op.Parameters, &Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "string"}})
+		}
+	}
+
+	for code, model := range spec.Responses {
+		resp := &Response{Description: http.StatusText(code)}
+		if model != nil {
+			resp.Content = map[string]*MediaType{"application/json": {Schema: a.schemas.of(reflect.TypeOf(model))}}
+		}
+		op.Responses[strconv.Itoa(code)] = resp
+	}
+	if len(op.Responses) == 0 {
+		op.Responses["200"] = &Response{Description: http.StatusText(http.StatusOK)}
+	}
+
+	item := a.doc.Paths[path]
+	if item == nil {
+		item = make(PathItem)
+		a.doc.Paths[path] = item
+	}
+	item[strings.ToLower(method)] = op
+}
+
+// Document returns the current document. It shares its maps with a, so it
+// must not be modified, nor read while routes are still being added.
+func (a *API) Document() Document {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.document()
+}
+
+func (a *API) document() Document {
+	doc := a.doc
+	if len(a.schemas.components) > 0 {
+		doc.Components = &Components{Schemas: a.schemas.components}
+	}
+	return doc
+}
+
+// MarshalJSON encodes the current document.
+func (a *API) MarshalJSON() ([]byte, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return json.Marshal(a.document())
+}
+
+// Handler serves the document as JSON.
+func (a *API) Handler() httpx.Handler {
+	return func(ctx httpx.Context) error {
+		body, err := a.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		return ctx.Bytes(http.StatusOK, body, "application/json")
+	}
+}
+
+// MountOption configures Mount.
+type MountOption func(*mountConfig)
+
+type mountConfig struct {
+	specPath    string
+	swaggerPath string
+}
+
+// WithSpecPath sets the path the document is served at. The default is
+// "/openapi.json".
+func WithSpecPath(path string) MountOption {
+	return func(conf *mountConfig) {
+		conf.specPath = path
+	}
+}
+
+// WithSwaggerUI serves a Swagger UI page for the document at path. The page
+// loads the Swagger UI assets from the unpkg CDN.
+func WithSwaggerUI(path string) MountOption {
+	return func(conf *mountConfig) {
+		conf.swaggerPath = path
+	}
+}
+
+// Mount serves the document on r, at "/openapi.json" unless WithSpecPath is
+// given, and optionally a Swagger UI page. These routes are not documented.
+func (a *API) Mount(r httpx.Router, opts ...MountOption) {
+	conf := mountConfig{specPath: "/openapi.json"}
+	for _, opt := range opts {
+		opt(&conf)
+	}
+	r.GET(conf.specPath, a.Handler())
+	if conf.swaggerPath != "" {
+		page := swaggerUIPage(a.Document().Info.Title, httpx.JoinPaths(r.BasePath(), conf.specPath))
+		r.GET(conf.swaggerPath, func(ctx httpx.Context) error {
+			return ctx.Bytes(http.StatusOK, page, "text/html; charset=utf-8")
+		})
+	}
+}
+
+var swaggerUITemplate = template.Must(template.New("swagger").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+<script>window.ui = SwaggerUIBundle({url: {{.SpecURL}}, dom_id: "#swagger-ui"});</script>
+</body>
+</html>
+`))
+
+func swaggerUIPage(title, specURL string) []byte {
+	var buf strings.Builder
+	if err := swaggerUITemplate.Execute(&buf, map[string]string{"Title": title, "SpecURL": specURL}); err != nil {
+		panic(fmt.Sprintf("openapix: render swagger ui: %v", err))
+	}
+	return []byte(buf.String())
+}
+
+// openAPIPath converts an httpx route path, such as "/files/:id/*rest", into
+// an OpenAPI path template and its parameter names.
+func openAPIPath(path string) (string, []string) {
+	segments := strings.Split(path, "/")
+	var names []string
+	for i, segment := range segments {
+		if len(segment) > 1 && (segment[0] == ':' || segment[0] == '*') {
+			names = append(names, segment[1:])
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/"), names
+}
+
+func hasParameter(params []*Parameter, in, name string) bool {
+	for _, p := range params {
+		if p.In == in && p.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+package openapix
+
+import (
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type address struct {
+	City string `json:"city" binding:"required"`
+}
+
+type user struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name" binding:"required" doc:"Display name"`
+	Tags      []string  `json:"tags,omitempty"`
+	Address   *address  `json:"address"`
+	Manager   *user     `json:"manager,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	secret    string
+	Ignored   string `json:"-"`
+}
+
+type listUsers struct {
+	Org   string `uri:"org"`
+	Limit int    `query:"limit" default:"20" doc:"Page size"`
+	Trace string `header:"X-Trace" binding:"required"`
+}
+
+type updateUser struct {
+	ID   string `uri:"id"`
+	Name string `json:"name" binding:"required"`
+}
+
+type upload struct {
+	Title string                `form:"title"`
+	File  *multipart.FileHeader `form:"file" binding:"required"`
+}
+
+func marshal(t *testing.T, v any) string {
+	t.Helper()
+	body, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return string(body)
+}
+
+func TestSchemaOf(t *testing.T) {
+	s := newSchemas()
+	ref := s.ref(reflect.TypeFor[user]())
+	if got := marshal(t, ref); got != `{"$ref":"#/components/schemas/user"}` {
+		t.Fatalf("ref = %s", got)
+	}
+	want := `{"type":"object","properties":{` +
+		`"address":{"$ref":"#/components/schemas/address"},` +
+		`"created_at":{"type":"string","format":"date-time"},` +
+		`"id":{"type":"integer","format":"int64"},` +
+		`"manager":{"$ref":"#/components/schemas/user"},` +
+		`"name":{"type":"string","description":"Display name"},` +
+		`"tags":{"type":"array","items":{"type":"string"}}},` +
+		`"required":["name"]}`
+	if got := marshal(t, s.components["user"]); got != want {
+		t.Fatalf("user schema = %s\nwant %s", got, want)
+	}
+	if got := marshal(t, s.components["address"]); got != `{"type":"object","properties":{"city":{"type":"string"}},"required":["city"]}` {
+		t.Fatalf("address schema = %s", got)
+	}
+}
+
+func TestAddOperations(t *testing.T) {
+	api := New(Info{Title: "Users", Version: "1.0.0"})
+	api.Add("/orgs/:org/users", Spec{
+		Method:    http.MethodGet,
+		Summary:   "List users",
+		Request:   listUsers{},
+		Responses: map[int]any{http.StatusOK: []user{}},
+	})
+	api.Add("/users/:id", Spec{
+		Method:    http.MethodPut,
+		Request:   &updateUser{},
+		Responses: map[int]any{http.StatusOK: user{}, http.StatusNotFound: nil},
+	})
+	api.Add("/uploads", Spec{Method: http.MethodPost, Request: upload{}})
+	api.Add("/files/*path", Spec{Method: http.MethodGet})
+
+	doc := api.Document()
+	if doc.OpenAPI != "3.1.0" || doc.Components == nil || doc.Components.Schemas["user"] == nil {
+		t.Fatalf("document = %s", marshal(t, doc))
+	}
+
+	list := doc.Paths["/orgs/{org}/users"]["get"]
+	wantList := `{"summary":"List users","parameters":[` +
+		`{"name":"org","in":"path","required":true,"schema":{"type":"string"}},` +
+		`{"name":"limit","in":"query","description":"Page size","schema":{"type":"integer","format":"int64","default":20}},` +
+		`{"name":"X-Trace","in":"header","required":true,"schema":{"type":"string"}}],` +
+		`"responses":{"200":{"description":"OK","content":{"application/json":{"schema":{"type":"array","items":{"$ref":"#/components/schemas/user"}}}}}}}`
+	if got := marshal(t, list); got != wantList {
+		t.Fatalf("list operation = %s\nwant %s", got, wantList)
+	}
+
+	update := doc.Paths["/users/{id}"]["put"]
+	wantUpdate := `{"parameters":[{"name":"id","in":"path","required":true,"schema":{"type":"string"}}],` +
+		`"requestBody":{"required":true,"content":{"application/json":{"schema":{"type":"object","properties":{"name":{"type":"string"}},"required":["name"]}}}},` +
+		`"responses":{"200":{"description":"OK","content":{"application/json":{"schema":{"$ref":"#/components/schemas/user"}}}},"404":{"description":"Not Found"}}}`
+	if got := marshal(t, update); got != wantUpdate {
+		t.Fatalf("update operation = %s\nwant %s", got, wantUpdate)
+	}
+
+	uploadOp := doc.Paths["/uploads"]["post"]
+	wantUpload := `{"requestBody":{"required":true,"content":{"multipart/form-data":{"schema":{"type":"object","properties":{` +
+		`"file":{"type":"string","format":"binary"},"title":{"type":"string"}},"required":["file"]}}}},` +
+		`"responses":{"200":{"description":"OK"}}}`
+	if got := marshal(t, uploadOp); got != wantUpload {
+		t.Fatalf("upload operation = %s\nwant %s", got, wantUpload)
+	}
+
+	files := doc.Paths["/files/{path}"]["get"]
+	if len(files.Parameters) != 1 || files.Parameters[0].Name != "path" || files.Parameters[0].In != "path" {
+		t.Fatalf("files operation = %s", marshal(t, files))
+	}
+}
+
+func TestOpenAPIPath(t *testing.T) {
+	path, names := openAPIPath("/v1/:org/files/*rest")
+	if path != "/v1/{org}/files/{rest}" || len(names) != 2 || names[0] != "org" || names[1] != "rest" {
+		t.Fatalf("openAPIPath = %q, %v", path, names)
+	}
+}
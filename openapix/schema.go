@@ -0,0 +1,288 @@
+package openapix
+
+import (
+	"encoding"
+	"mime/multipart"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType          = reflect.TypeFor[time.Time]()
+	fileHeaderType    = reflect.TypeFor[multipart.FileHeader]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+	invalidNameChars  = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+)
+
+// parameterTags maps the binding tags of parameters to their location.
+var parameterTags = []struct{ tag, in string }{
+	{"uri", "path"},
+	{"query", "query"},
+	{"header", "header"},
+}
+
+// schemas generates schemas, collecting named struct types as components.
+type schemas struct {
+	components map[string]*Schema
+	names      map[reflect.Type]string
+}
+
+func newSchemas() *schemas {
+	return &schemas{components: make(map[string]*Schema), names: make(map[reflect.Type]string)}
+}
+
+// of returns the schema of t as encoded by encoding/json. Named structs are
+// added to the components and referenced.
+func (s *schemas) of(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == fileHeaderType:
+		return &Schema{Type: "string", Format: "binary"}
+	case t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType):
+		return &Schema{Type: "string"}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Schema{Type: "integer", Format: intFormat(t)}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		zero := 0.0
+		return &Schema{Type: "integer", Format: intFormat(t), Minimum: &zero}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: s.of(t.Elem())}
+	case reflect.Array:
+		return &Schema{Type: "array", Items: s.of(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: s.of(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.object(t, "json", nil)
+		}
+		return s.ref(t)
+	default:
+		return &Schema{}
+	}
+}
+
+func intFormat(t reflect.Type) string {
+	if t.Bits() == 64 {
+		return "int64"
+	}
+	return "int32"
+}
+
+// ref adds the named struct t to the components and returns a reference.
+func (s *schemas) ref(t reflect.Type) *Schema {
+	name, ok := s.names[t]
+	if !ok {
+		name = invalidNameChars.ReplaceAllString(t.Name(), "_")
+		for i := 2; s.components[name] != nil; i++ {
+			name = invalidNameChars.ReplaceAllString(t.Name(), "_") + strconv.Itoa(i)
+		}
+		s.names[t] = name
+		// Register before generating, so recursive types terminate.
+		s.components[name] = &Schema{}
+		*s.components[name] = *s.object(t, "json", nil)
+	}
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// object returns an inline object schema of the fields of t named by tag,
+// skipping fields for which skip reports true.
+func (s *schemas) object(t reflect.Type, tag string, skip func(reflect.StructField) bool) *Schema {
+	obj := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	for _, field := range fields(t) {
+		if skip != nil && skip(field) {
+			continue
+		}
+		name, ok := fieldName(field, tag)
+		if !ok {
+			continue
+		}
+		prop := s.of(field.Type)
+		if def, ok := field.Tag.Lookup("default"); ok && prop.Ref == "" {
+			prop.Default = defaultValue(prop, def)
+		}
+		if doc := field.Tag.Get("doc"); doc != "" {
+			prop.Description = doc
+		}
+		obj.Properties[name] = prop
+		if isRequired(field) {
+			obj.Required = append(obj.Required, name)
+		}
+	}
+	return obj
+}
+
+// fields returns the exported fields of t, flattening embedded structs the
+// way encoding/json does.
+func fields(t reflect.Type) []reflect.StructField {
+	var out []reflect.StructField
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if field.Anonymous {
+			ft := field.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct && field.Tag.Get("json") == "" {
+				out = append(out, fields(ft)...)
+				continue
+			}
+		}
+		if field.IsExported() {
+			out = append(out, field)
+		}
+	}
+	return out
+}
+
+// fieldName returns the name of field under tag, defaulting to the Go name
+// for json like encoding/json does.
+func fieldName(field reflect.StructField, tag string) (string, bool) {
+	name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+	switch {
+	case name == "-":
+		return "", false
+	case name != "":
+		return name, true
+	case tag == "json":
+		return field.Name, true
+	default:
+		return "", false
+	}
+}
+
+func isRequired(field reflect.StructField) bool {
+	for rule := range strings.SplitSeq(field.Tag.Get("binding"), ",") {
+		if strings.TrimSpace(rule) == "required" {
+			return true
+		}
+	}
+	return false
+}
+
+func defaultValue(schema *Schema, value string) any {
+	switch schema.Type {
+	case "integer":
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return n
+		}
+	case "number":
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return value
+}
+
+// parameterIn returns the location and name of a field bound from the path,
+// query or headers.
+func parameterIn(field reflect.StructField) (string, string, bool) {
+	for _, p := range parameterTags {
+		if name, ok := fieldName(field, p.tag); ok {
+			return p.in, name, true
+		}
+	}
+	return "", "", false
+}
+
+func isParameter(field reflect.StructField) bool {
+	_, _, ok := parameterIn(field)
+	return ok
+}
+
+// isFile reports whether t holds uploaded files.
+func isFile(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	return t == fileHeaderType
+}
+
+// request derives the parameters and body of a request model. Fields tagged
+// uri, query or header become parameters; the rest form the body, encoded as
+// a form when any field has a form tag and as JSON otherwise.
+func (s *schemas) request(model any, withBody bool) ([]*Parameter, *RequestBody) {
+	t := reflect.TypeOf(model)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil {
+		return nil, nil
+	}
+	if t.Kind() != reflect.Struct {
+		if !withBody {
+			return nil, nil
+		}
+		return nil, jsonBody(s.of(t))
+	}
+
+	var params []*Parameter
+	var form, file, body bool
+	for _, field := range fields(t) {
+		if in, name, ok := parameterIn(field); ok {
+			p := &Parameter{
+				Name:        name,
+				In:          in,
+				Description: field.Tag.Get("doc"),
+				Required:    in == "path" || isRequired(field),
+				Schema:      s.of(field.Type),
+			}
+			if def, ok := field.Tag.Lookup("default"); ok {
+				p.Schema.Default = defaultValue(p.Schema, def)
+			}
+			params = append(params, p)
+			continue
+		}
+		if _, ok := field.Tag.Lookup("form"); ok {
+			form = true
+			file = file || isFile(field.Type)
+		}
+		if _, ok := fieldName(field, "json"); ok {
+			body = true
+		}
+	}
+	if !withBody || !(body || form) {
+		return params, nil
+	}
+	switch {
+	case file:
+		return params, &RequestBody{Required: true, Content: map[string]*MediaType{
+			"multipart/form-data": {Schema: s.object(t, "form", isParameter)},
+		}}
+	case form:
+		return params, &RequestBody{Required: true, Content: map[string]*MediaType{
+			"application/x-www-form-urlencoded": {Schema: s.object(t, "form", isParameter)},
+		}}
+	case len(params) == 0 && t.Name() != "":
+		return params, jsonBody(s.ref(t))
+	default:
+		return params, jsonBody(s.object(t, "json", isParameter))
+	}
+}
+
+func jsonBody(schema *Schema) *RequestBody {
+	return &RequestBody{Required: true, Content: map[string]*MediaType{"application/json": {Schema: schema}}}
+}
@@ -0,0 +1,638 @@
+package openapix
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-sphere/httpx"
+)
+
+// Violation is a single mismatch between a request or response and the
+// document.
+type Violation struct {
+	// Location is "path", "query", "header", "cookie", "body" or "response".
+	Location string `json:"location"`
+	// Field names the parameter, or the member of the body such as
+	// "items[0].name". It is empty for the body as a whole.
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+func (v Violation) String() string {
+	if v.Field == "" {
+		return v.Location + ": " + v.Message
+	}
+	return v.Location + " " + v.Field + ": " + v.Message
+}
+
+// ValidationError reports the violations found by a Validator. Its status is
+// 400 for invalid requests, 415 for request bodies of an undocumented media
+// type and 500 for invalid responses.
+//
+// It differs from httpx.ValidationError, which the Bind methods return with
+// status 422 for the fields of a bound struct failing their rules: a
+// Validator checks requests against the document before anything is bound,
+// so violations are reported by location, such as a query parameter or a
+// member of the body, rather than by struct field.
+type ValidationError struct {
+	Status     int
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	return "openapix: " + e.GetMessage()
+}
+
+func (e *ValidationError) GetStatus() int32 {
+	return int32(e.Status)
+}
+
+func (e *ValidationError) GetMessage() string {
+	parts := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		parts[i] = v.String()
+	}
+	subject := "request"
+	if e.Status >= http.StatusInternalServerError {
+		subject = "response"
+	}
+	return fmt.Sprintf("%s does not match the API contract: %s", subject, strings.Join(parts, "; "))
+}
+
+// ValidatorOption configures a Validator.
+type ValidatorOption func(*Validator)
+
+// WithResponseValidation enables the validation of responses by Wrap. The
+// enabled flag is typically driven by configuration, so that responses are
+// checked in development and tests only.
+func WithResponseValidation(enabled bool) ValidatorOption {
+	return func(v *Validator) {
+		v.responses = enabled
+	}
+}
+
+// Validator checks requests, and optionally responses, against a document.
+// Requests to paths or methods the document does not describe are passed
+// through unchecked.
+//
+// Schemas are checked for the keywords modelled by Schema; other keywords
+// are ignored.
+type Validator struct {
+	doc       *Document
+	routes    []validatorRoute
+	patterns  map[string]*regexp.Regexp
+	responses bool
+}
+
+type validatorRoute struct {
+	segments []string
+	item     PathItem
+}
+
+// NewValidator returns a Validator for doc, which must not be modified
+// afterwards. It fails when a schema has an invalid pattern.
+func NewValidator(doc *Document, opts ...ValidatorOption) (*Validator, error) {
+	v := &Validator{doc: doc, patterns: make(map[string]*regexp.Regexp)}
+	for _, opt := range opts {
+		opt(v)
+	}
+	var err error
+	compile := func(s *Schema) {
+		if s.Pattern == "" || v.patterns[s.Pattern] != nil || err != nil {
+			return
+		}
+		var re *regexp.Regexp
+		if re, err = regexp.Compile(s.Pattern); err != nil {
+			err = fmt.Errorf("openapix: invalid schema pattern %q: %w", s.Pattern, err)
+			return
+		}
+		v.patterns[s.Pattern] = re
+	}
+	if doc.Components != nil {
+		for _, s := range doc.Components.Schemas {
+			walkSchema(s, compile)
+		}
+	}
+	for path, item := range doc.Paths {
+		v.routes = append(v.routes, validatorRoute{segments: strings.Split(path, "/"), item: item})
+		for _, op := range item {
+			for _, p := range op.Parameters {
+				walkSchema(p.Schema, compile)
+			}
+			if op.RequestBody != nil {
+				for _, media := range op.RequestBody.Content {
+					walkSchema(media.Schema, compile)
+				}
+			}
+			for _, resp := range op.Responses {
+				for _, media := range resp.Content {
+					walkSchema(media.Schema, compile)
+				}
+			}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Prefer literal segments over templated ones, like the routers do.
+	slices.SortFunc(v.routes, func(a, b validatorRoute) int {
+		if n := literalSegments(b.segments) - literalSegments(a.segments); n != 0 {
+			return n
+		}
+		return strings.Compare(strings.Join(a.segments, "/"), strings.Join(b.segments, "/"))
+	})
+	return v, nil
+}
+
+func walkSchema(s *Schema, fn func(*Schema)) {
+	if s == nil {
+		return
+	}
+	fn(s)
+	walkSchema(s.Items, fn)
+	walkSchema(s.AdditionalProperties, fn)
+	for _, prop := range s.Properties {
+		walkSchema(prop, fn)
+	}
+}
+
+func isTemplate(segment string) bool {
+	return len(segment) > 2 && segment[0] == '{' && segment[len(segment)-1] == '}'
+}
+
+func literalSegments(segments []string) int {
+	n := 0
+	for _, segment := range segments {
+		if !isTemplate(segment) {
+			n++
+		}
+	}
+	return n
+}
+
+// Middleware validates the parameters and body of requests before the next
+// handler runs, returning a *ValidationError for invalid ones.
+func (v *Validator) Middleware() httpx.Middleware {
+	return func(ctx httpx.Context) error {
+		if op, params := v.match(ctx.Method(), ctx.Path()); op != nil {
+			if err := v.validateRequest(ctx, op, params); err != nil {
+				return err
+			}
+		}
+		return ctx.Next()
+	}
+}
+
+// Wrap validates the responses h writes with JSON, Bytes, Text and
+// NoContent when WithResponseValidation is enabled, and returns h unchanged
+// otherwise. An invalid response is not written; a *ValidationError with
+// status 500 is returned instead. Middleware cannot observe response bodies,
+// hence the wrapper; requests are left to Middleware.
+//
+// h receives a wrapper of the context, so optional capabilities such as
+// AsFlusher are not available to it.
+func (v *Validator) Wrap(h httpx.Handler) httpx.Handler {
+	if !v.responses {
+		return h
+	}
+	return func(ctx httpx.Context) error {
+		op, _ := v.match(ctx.Method(), ctx.Path())
+		if op == nil {
+			return h(ctx)
+		}
+		return h(&responseValidator{handlerContext: ctx, v: v, op: op})
+	}
+}
+
+// match returns the operation for a request and the values of its path
+// parameters.
+func (v *Validator) match(method, path string) (*Operation, map[string]string) {
+	segments := strings.Split(path, "/")
+	for _, route := range v.routes {
+		if len(route.segments) != len(segments) {
+			continue
+		}
+		params := make(map[string]string)
+		matched := true
+		for i, segment := range route.segments {
+			switch {
+			case isTemplate(segment) && segments[i] != "":
+				params[segment[1:len(segment)-1]] = segments[i]
+			case segment != segments[i]:
+				matched = false
+			}
+			if !matched {
+				break
+			}
+		}
+		if !matched {
+			continue
+		}
+		op := route.item[strings.ToLower(method)]
+		if op == nil && method == http.MethodHead {
+			op = route.item["get"]
+		}
+		return op, params
+	}
+	return nil, nil
+}
+
+func (v *Validator) validateRequest(ctx httpx.Context, op *Operation, params map[string]string) error {
+	var errs []Violation
+	for _, p := range op.Parameters {
+		var values []string
+		switch p.In {
+		case "path":
+			if val, ok := params[p.Name]; ok {
+				values = []string{val}
+			}
+		case "query":
+			values = ctx.Queries()[p.Name]
+		case "header":
+			if val := ctx.Header(p.Name); val != "" {
+				values = []string{val}
+			}
+		case "cookie":
+			if val, err := ctx.Cookie(p.Name); err == nil {
+				values = []string{val}
+			}
+		default:
+			continue
+		}
+		if len(values) == 0 {
+			if p.Required {
+				errs = append(errs, Violation{Location: p.In, Field: p.Name, Message: "is required"})
+			}
+			continue
+		}
+		v.check(p.Schema, v.parameterValue(p.Schema, values), p.In, p.Name, &errs)
+	}
+	status := http.StatusBadRequest
+	if op.RequestBody != nil {
+		unsupported, err := v.validateBody(ctx, op.RequestBody, &errs)
+		if err != nil {
+			return err
+		}
+		if unsupported {
+			status = http.StatusUnsupportedMediaType
+		}
+	}
+	if len(errs) > 0 {
+		return &ValidationError{Status: status, Violations: errs}
+	}
+	return nil
+}
+
+// validateBody checks the request body, reporting whether its media type is
+// not accepted by rb.
+func (v *Validator) validateBody(ctx httpx.Context, rb *RequestBody, errs *[]Violation) (bool, error) {
+	mediaType, _, _ := mime.ParseMediaType(ctx.Header("Content-Type"))
+	if mediaType == "" || mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") ||
+		mediaType == "application/x-www-form-urlencoded" {
+		body, err := ctx.BodyRaw()
+		if err != nil {
+			return false, err
+		}
+		if len(bytes.TrimSpace(body)) == 0 {
+			if rb.Required {
+				*errs = append(*errs, Violation{Location: "body", Message: "is required"})
+			}
+			return false, nil
+		}
+		if mediaType == "" {
+			*errs = append(*errs, Violation{Location: "body", Message: "has no Content-Type"})
+			return true, nil
+		}
+	}
+	media, ok := lookupMedia(rb.Content, mediaType)
+	if !ok {
+		*errs = append(*errs, Violation{Location: "body", Message: fmt.Sprintf("media type %q is not accepted", mediaType)})
+		return true, nil
+	}
+	if media.Schema == nil {
+		return false, nil
+	}
+	switch {
+	case isJSON(mediaType):
+		body, _ := ctx.BodyRaw()
+		v.checkJSON(media.Schema, body, "body", errs)
+	case mediaType == "application/x-www-form-urlencoded":
+		body, _ := ctx.BodyRaw()
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			*errs = append(*errs, Violation{Location: "body", Message: "is not a valid form"})
+			return false, nil
+		}
+		schema := v.resolve(media.Schema)
+		if schema == nil {
+			return false, nil
+		}
+		values := make(map[string]any, len(form))
+		for key, vals := range form {
+			prop := schema.Properties[key]
+			if prop == nil {
+				prop = schema.AdditionalProperties
+			}
+			values[key] = v.parameterValue(prop, vals)
+		}
+		v.check(schema, values, "body", "", errs)
+	}
+	return false, nil
+}
+
+func isJSON(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// lookupMedia finds the media type in content, falling back to ranges such
+// as "application/*" and "*/*".
+func lookupMedia(content map[string]*MediaType, mediaType string) (*MediaType, bool) {
+	if media, ok := content[mediaType]; ok {
+		return media, true
+	}
+	major, _, _ := strings.Cut(mediaType, "/")
+	if media, ok := content[major+"/*"]; ok {
+		return media, true
+	}
+	media, ok := content["*/*"]
+	return media, ok
+}
+
+func (v *Validator) checkJSON(schema *Schema, body []byte, loc string, errs *[]Violation) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var val any
+	if err := dec.Decode(&val); err != nil {
+		*errs = append(*errs, Violation{Location: loc, Message: "is not valid JSON"})
+		return
+	}
+	v.check(schema, val, loc, "", errs)
+}
+
+// parameterValue converts the string values of a parameter or form field to
+// the JSON types of schema, leaving values that do not parse as strings so
+// that check reports them.
+func (v *Validator) parameterValue(schema *Schema, values []string) any {
+	schema = v.resolve(schema)
+	if schema != nil && schema.Type == "array" {
+		items := make([]any, len(values))
+		for i, val := range values {
+			items[i] = v.scalarValue(schema.Items, val)
+		}
+		return items
+	}
+	return v.scalarValue(schema, values[0])
+}
+
+func (v *Validator) scalarValue(schema *Schema, val string) any {
+	schema = v.resolve(schema)
+	if schema == nil {
+		return val
+	}
+	switch schema.Type {
+	case "integer", "number":
+		if _, err := strconv.ParseFloat(val, 64); err == nil {
+			return json.Number(val)
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(val); err == nil {
+			return b
+		}
+	}
+	return val
+}
+
+// resolve follows a reference to the components of the document.
+func (v *Validator) resolve(s *Schema) *Schema {
+	for seen := 0; s != nil && s.Ref != "" && seen < 32; seen++ {
+		name, ok := strings.CutPrefix(s.Ref, "#/components/schemas/")
+		if !ok || v.doc.Components == nil {
+			return nil
+		}
+		s = v.doc.Components.Schemas[name]
+	}
+	return s
+}
+
+// check validates a value decoded from JSON, with numbers as json.Number.
+func (v *Validator) check(s *Schema, val any, loc, field string, errs *[]Violation) {
+	s = v.resolve(s)
+	if s == nil {
+		return
+	}
+	fail := func(format string, args ...any) {
+		*errs = append(*errs, Violation{Location: loc, Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+	if s.Type != "" && !hasType(s.Type, val) {
+		fail("must be of type %s", s.Type)
+		return
+	}
+	if len(s.Enum) > 0 && !inEnum(s.Enum, val) {
+		fail("must be one of %s", enumList(s.Enum))
+	}
+	switch val := val.(type) {
+	case string:
+		n := utf8.RuneCountInString(val)
+		if s.MinLength != nil && n < *s.MinLength {
+			fail("must be at least %d characters", *s.MinLength)
+		}
+		if s.MaxLength != nil && n > *s.MaxLength {
+			fail("must be at most %d characters", *s.MaxLength)
+		}
+		if re := v.patterns[s.Pattern]; re != nil && !re.MatchString(val) {
+			fail("must match %s", s.Pattern)
+		}
+		if !validFormat(s.Format, val) {
+			fail("must be a valid %s", s.Format)
+		}
+	case json.Number:
+		f, _ := val.Float64()
+		if s.Minimum != nil && f < *s.Minimum {
+			fail("must be at least %v", *s.Minimum)
+		}
+		if s.Maximum != nil && f > *s.Maximum {
+			fail("must be at most %v", *s.Maximum)
+		}
+	case []any:
+		for i, item := range val {
+			v.check(s.Items, item, loc, field+"["+strconv.Itoa(i)+"]", errs)
+		}
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := val[name]; !ok {
+				*errs = append(*errs, Violation{Location: loc, Field: joinField(field, name), Message: "is required"})
+			}
+		}
+		keys := make([]string, 0, len(val))
+		for key := range val {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			prop := s.Properties[key]
+			if prop == nil {
+				prop = s.AdditionalProperties
+			}
+			v.check(prop, val[key], loc, joinField(field, key), errs)
+		}
+	}
+}
+
+func joinField(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
+
+func hasType(typ string, val any) bool {
+	switch typ {
+	case "object":
+		_, ok := val.(map[string]any)
+		return ok
+	case "array":
+		_, ok := val.([]any)
+		return ok
+	case "string":
+		_, ok := val.(string)
+		return ok
+	case "boolean":
+		_, ok := val.(bool)
+		return ok
+	case "number":
+		_, ok := val.(json.Number)
+		return ok
+	case "integer":
+		n, ok := val.(json.Number)
+		if !ok {
+			return false
+		}
+		f, err := n.Float64()
+		return err == nil && math.Trunc(f) == f
+	case "null":
+		return val == nil
+	}
+	return true
+}
+
+func inEnum(enum []any, val any) bool {
+	got, err := json.Marshal(val)
+	if err != nil {
+		return false
+	}
+	for _, want := range enum {
+		if b, err := json.Marshal(want); err == nil && bytes.Equal(b, got) {
+			return true
+		}
+	}
+	return false
+}
+
+func enumList(enum []any) string {
+	parts := make([]string, len(enum))
+	for i, val := range enum {
+		b, _ := json.Marshal(val)
+		parts[i] = string(b)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func validFormat(format, val string) bool {
+	var err error
+	switch format {
+	case "date-time":
+		_, err = time.Parse(time.RFC3339, val)
+	case "date":
+		_, err = time.Parse(time.DateOnly, val)
+	}
+	return err == nil
+}
+
+// handlerContext names the embedded context of responseValidator, whose
+// field could not be called Context next to the Context method.
+type handlerContext = httpx.Context
+
+// responseValidator checks the responses of a handler before writing them.
+type responseValidator struct {
+	handlerContext
+	v  *Validator
+	op *Operation
+}
+
+func (c *responseValidator) JSON(code int, val any) error {
+	body, err := json.Marshal(val)
+	if err != nil {
+		return c.handlerContext.JSON(code, val)
+	}
+	if err := c.v.validateResponse(c.op, code, "application/json", body); err != nil {
+		return err
+	}
+	return c.handlerContext.JSON(code, val)
+}
+
+func (c *responseValidator) Bytes(code int, b []byte, contentType string) error {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if err := c.v.validateResponse(c.op, code, mediaType, b); err != nil {
+		return err
+	}
+	return c.handlerContext.Bytes(code, b, contentType)
+}
+
+func (c *responseValidator) Text(code int, s string) error {
+	if err := c.v.validateResponse(c.op, code, "text/plain", []byte(s)); err != nil {
+		return err
+	}
+	return c.handlerContext.Text(code, s)
+}
+
+func (c *responseValidator) NoContent(code int) error {
+	if err := c.v.validateResponse(c.op, code, "", nil); err != nil {
+		return err
+	}
+	return c.handlerContext.NoContent(code)
+}
+
+func (v *Validator) validateResponse(op *Operation, code int, mediaType string, body []byte) error {
+	var errs []Violation
+	status := strconv.Itoa(code)
+	resp := op.Responses[status]
+	if resp == nil {
+		resp = op.Responses[status[:1]+"XX"]
+	}
+	if resp == nil {
+		resp = op.Responses["default"]
+	}
+	switch {
+	case resp == nil:
+		errs = append(errs, Violation{Location: "response", Message: fmt.Sprintf("status %d is not documented", code)})
+	case len(resp.Content) == 0:
+		if len(body) > 0 {
+			errs = append(errs, Violation{Location: "response", Message: fmt.Sprintf("status %d is documented without a body", code)})
+		}
+	case mediaType == "":
+		errs = append(errs, Violation{Location: "response", Message: fmt.Sprintf("status %d is documented with a body", code)})
+	default:
+		media, ok := lookupMedia(resp.Content, mediaType)
+		switch {
+		case !ok:
+			errs = append(errs, Violation{Location: "response", Message: fmt.Sprintf("media type %q is not documented for status %d", mediaType, code)})
+		case media.Schema != nil && isJSON(mediaType):
+			v.checkJSON(media.Schema, body, "response", &errs)
+		}
+	}
+	if len(errs) > 0 {
+		return &ValidationError{Status: http.StatusInternalServerError, Violations: errs}
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+package openapix
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/go-sphere/httpx"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func petDocument() *Document {
+	return &Document{
+		OpenAPI: Version,
+		Components: &Components{Schemas: map[string]*Schema{
+			"pet": {
+				Type:     "object",
+				Required: []string{"name"},
+				Properties: map[string]*Schema{
+					"name":  {Type: "string", MinLength: ptr(2), MaxLength: ptr(5), Pattern: "^[a-z]+$"},
+					"kind":  {Type: "string", Enum: []any{"cat", "dog"}},
+					"age":   {Type: "integer", Minimum: ptr(0.0), Maximum: ptr(30.0)},
+					"born":  {Type: "string", Format: "date"},
+					"seen":  {Type: "string", Format: "date-time"},
+					"tags":  {Type: "array", Items: &Schema{Type: "string"}},
+					"owner": {Ref: "#/components/schemas/owner"},
+				},
+				AdditionalProperties: &Schema{Type: "integer"},
+			},
+			"owner": {Type: "object", Required: []string{"id"}, Properties: map[string]*Schema{
+				"id": {Type: "integer"},
+			}},
+		}},
+	}
+}
+
+func checkBody(t *testing.T, v *Validator, schema *Schema, body string) []string {
+	t.Helper()
+	var errs []Violation
+	v.checkJSON(schema, []byte(body), "body", &errs)
+	out := make([]string, len(errs))
+	for i, e := range errs {
+		out[i] = e.String()
+	}
+	return out
+}
+
+func TestValidatorSchemaKeywords(t *testing.T) {
+	v, err := NewValidator(petDocument())
+	if err != nil {
+		t.Fatalf("NewValidator() error = %v", err)
+	}
+	pet := &Schema{Ref: "#/components/schemas/pet"}
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{"valid", `{"name":"tom","kind":"cat","age":3,"born":"2020-02-29","seen":"2024-05-01T10:00:00Z","tags":["a"],"owner":{"id":1},"extra":7}`, nil},
+		{"required", `{}`, []string{"body name: is required"}},
+		{"type", `[]`, []string{"body: must be of type object"}},
+		{"enum", `{"name":"tom","kind":"cow"}`, []string{`body kind: must be one of "cat", "dog"`}},
+		{"pattern", `{"name":"Tom"}`, []string{"body name: must match ^[a-z]+$"}},
+		{"minLength", `{"name":"t"}`, []string{"body name: must be at least 2 characters"}},
+		{"maxLength", `{"name":"tomcat"}`, []string{"body name: must be at most 5 characters"}},
+		{"minimum", `{"name":"tom","age":-1}`, []string{"body age: must be at least 0"}},
+		{"maximum", `{"name":"tom","age":31}`, []string{"body age: must be at most 30"}},
+		{"integer", `{"name":"tom","age":1.5}`, []string{"body age: must be of type integer"}},
+		{"large integer", `{"name":"tom","extra":18446744073709551616}`, nil},
+		{"date", `{"name":"tom","born":"2021-02-29"}`, []string{"body born: must be a valid date"}},
+		{"date-time", `{"name":"tom","seen":"2024-05-01 10:00"}`, []string{"body seen: must be a valid date-time"}},
+		{"ref", `{"name":"tom","owner":{"id":"x"}}`, []string{"body owner.id: must be of type integer"}},
+		{"ref required", `{"name":"tom","owner":{}}`, []string{"body owner.id: is required"}},
+		{"additionalProperties", `{"name":"tom","extra":"x"}`, []string{"body extra: must be of type integer"}},
+		{"items", `{"name":"tom","tags":["a",1]}`, []string{"body tags[1]: must be of type string"}},
+		{"invalid JSON", `{"name":`, []string{"body: is not valid JSON"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := checkBody(t, v, pet, tt.body); !slices.Equal(got, tt.want) {
+				t.Fatalf("violations = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewValidatorInvalidPattern(t *testing.T) {
+	doc := &Document{OpenAPI: Version, Components: &Components{Schemas: map[string]*Schema{
+		"code": {Type: "string", Pattern: "(["},
+	}}}
+	if _, err := NewValidator(doc); err == nil || !strings.Contains(err.Error(), "invalid schema pattern") {
+		t.Fatalf("NewValidator() error = %v, want invalid pattern", err)
+	}
+}
+
+func TestValidatorRequests(t *testing.T) {
+	doc := petDocument()
+	doc.Paths = map[string]PathItem{
+		"/pets/{id}": {"post": {
+			Parameters: []*Parameter{
+				{Name: "id", In: "path", Required: true, Schema: &Schema{Type: "integer"}},
+				{Name: "ids", In: "query", Schema: &Schema{Type: "array", Items: &Schema{Type: "integer"}}},
+				{Name: "X-Trace", In: "header", Required: true, Schema: &Schema{Type: "string"}},
+			},
+			RequestBody: &RequestBody{Required: true, Content: map[string]*MediaType{
+				"application/x-www-form-urlencoded": {Schema: &Schema{Ref: "#/components/schemas/pet"}},
+				"application/json":                  {Schema: &Schema{Ref: "#/components/schemas/pet"}},
+			}},
+		}},
+	}
+	v, err := NewValidator(doc)
+	if err != nil {
+		t.Fatalf("NewValidator() error = %v", err)
+	}
+	var got error
+	h := httpx.ToHTTPHandler(func(ctx httpx.Context) error { return ctx.NoContent(http.StatusNoContent) },
+		httpx.WithHTTPMiddleware(v.Middleware()),
+		httpx.WithHTTPErrorHandler(func(ctx httpx.Context, err error) { got = err }),
+	)
+	tests := []struct {
+		name, target, contentType, body string
+		status                          int
+		want                            []string
+	}{
+		{"form", "/pets/1", "application/x-www-form-urlencoded", "name=tom&age=3&tags=a&tags=b", 0, nil},
+		{"form violations", "/pets/1", "application/x-www-form-urlencoded", "name=Tom&age=x&extra=2", http.StatusBadRequest,
+			[]string{"body age: must be of type integer", "body name: must match ^[a-z]+$"}},
+		{"parameters", "/pets/x?ids=1&ids=y", "application/json", `{"name":"tom"}`, http.StatusBadRequest,
+			[]string{"path id: must be of type integer", "query ids[1]: must be of type integer", "header X-Trace: is required"}},
+		{"missing body", "/pets/1", "application/json", "", http.StatusBadRequest, []string{"body: is required"}},
+		{"unsupported media type", "/pets/1", "text/plain", "tom", http.StatusUnsupportedMediaType,
+			[]string{`body: media type "text/plain" is not accepted`}},
+		{"undocumented path", "/owners/1", "text/plain", "", 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = nil
+			req := httptest.NewRequest(http.MethodPost, tt.target, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			if tt.name != "parameters" {
+				req.Header.Set("X-Trace", "t")
+			}
+			h.ServeHTTP(httptest.NewRecorder(), req)
+			assertViolations(t, got, tt.status, tt.want)
+		})
+	}
+}
+
+func TestValidatorResponseStatus(t *testing.T) {
+	op := &Operation{Responses: map[string]*Response{
+		"200":     {Content: map[string]*MediaType{"application/json": {Schema: &Schema{Type: "object", Required: []string{"id"}}}}},
+		"4XX":     {},
+		"default": {Content: map[string]*MediaType{"text/*": {}}},
+	}}
+	v, err := NewValidator(&Document{OpenAPI: Version})
+	if err != nil {
+		t.Fatalf("NewValidator() error = %v", err)
+	}
+	tests := []struct {
+		name      string
+		code      int
+		mediaType string
+		body      string
+		want      []string
+	}{
+		{"exact", 200, "application/json", `{"id":1}`, nil},
+		{"exact schema", 200, "application/json", `{}`, []string{"response id: is required"}},
+		{"exact without body", 200, "", "", []string{"response: status 200 is documented with a body"}},
+		{"range", 404, "", "", nil},
+		{"range with body", 409, "application/json", `{}`, []string{"response: status 409 is documented without a body"}},
+		{"default", 503, "text/plain", "down", nil},
+		{"default media type", 201, "application/json", `{}`, []string{`response: media type "application/json" is not documented for status 201`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.validateResponse(op, tt.code, tt.mediaType, []byte(tt.body))
+			status := 0
+			if tt.want != nil {
+				status = http.StatusInternalServerError
+			}
+			assertViolations(t, err, status, tt.want)
+		})
+	}
+
+	noDefault := &Operation{Responses: map[string]*Response{"200": {}}}
+	err = v.validateResponse(noDefault, 500, "", nil)
+	assertViolations(t, err, http.StatusInternalServerError, []string{"response: status 500 is not documented"})
+}
+
+func assertViolations(t *testing.T, err error, status int, want []string) {
+	t.Helper()
+	if want == nil {
+		if err != nil {
+			t.Fatalf("error = %v, want none", err)
+		}
+		return
+	}
+	var ve *ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("error = %v, want *ValidationError", err)
+	}
+	got := make([]string, len(ve.Violations))
+	for i, v := range ve.Violations {
+		got[i] = v.String()
+	}
+	if ve.Status != status || !slices.Equal(got, want) {
+		t.Fatalf("error = %d %q, want %d %q", ve.Status, got, status, want)
+	}
+}
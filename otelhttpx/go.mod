@@ -0,0 +1,18 @@
+module github.com/go-sphere/httpx/otelhttpx
+
+go 1.25.5
+
+require (
+	github.com/go-sphere/httpx v0.0.3
+	go.opentelemetry.io/otel v1.38.0
+	go.opentelemetry.io/otel/metric v1.38.0
+)
+
+require (
+	github.com/go-logr/logr v1.4.3 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	go.opentelemetry.io/auto/sdk v1.1.0 // indirect
+	go.opentelemetry.io/otel/trace v1.38.0 // indirect
+)
+
+replace github.com/go-sphere/httpx => ../
@@ -0,0 +1,127 @@
+// Package otelhttpx provides OpenTelemetry instrumentation for httpx engines.
+package otelhttpx
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-sphere/httpx"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+// ScopeName is the instrumentation scope used for the meter.
+const ScopeName = "github.com/go-sphere/httpx/otelhttpx"
+
+// durationBuckets are the explicit bucket boundaries advised by the HTTP
+// semantic conventions for http.server.request.duration.
+var durationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.075, 0.1, 0.25, 0.5, 0.75, 1, 2.5, 5, 7.5, 10}
+
+type config struct {
+	meterProvider metric.MeterProvider
+}
+
+// Option configures the OpenTelemetry middleware.
+type Option func(*config)
+
+// WithMeterProvider sets the meter provider. Defaults to the global provider.
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(conf *config) {
+		conf.meterProvider = provider
+	}
+}
+
+func newConfig(opts ...Option) *config {
+	conf := config{}
+	for _, opt := range opts {
+		opt(&conf)
+	}
+	if conf.meterProvider == nil {
+		conf.meterProvider = otel.GetMeterProvider()
+	}
+	return &conf
+}
+
+// Metrics records the semantic-convention HTTP server metrics:
+//
+//   - http.server.request.duration: histogram of request durations in seconds
+//   - http.server.active_requests: number of requests currently being handled
+//
+// Durations carry http.request.method, http.route (Context.FullPath),
+// http.response.status_code and, for failed requests, error.type. The route
+// attribute is omitted when no route pattern matched.
+func Metrics(opts ...Option) httpx.Middleware {
+	conf := newConfig(opts...)
+	meter := conf.meterProvider.Meter(ScopeName)
+	duration, err := meter.Float64Histogram(
+		"http.server.request.duration",
+		metric.WithDescription("Duration of HTTP server requests."),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(durationBuckets...),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+	active, err := meter.Int64UpDownCounter(
+		"http.server.active_requests",
+		metric.WithDescription("Number of active HTTP server requests."),
+		metric.WithUnit("{request}"),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	return func(ctx httpx.Context) error {
+		start := time.Now()
+		method := requestMethod(ctx.Method())
+		activeAttrs := metric.WithAttributeSet(attribute.NewSet(method))
+		active.Add(ctx.Context(), 1, activeAttrs)
+		defer active.Add(ctx.Context(), -1, activeAttrs)
+
+		err := ctx.Next()
+
+		status := responseStatus(ctx, err)
+		attrs := []attribute.KeyValue{method, semconv.HTTPResponseStatusCode(status)}
+		if route := ctx.FullPath(); route != "" {
+			attrs = append(attrs, semconv.HTTPRoute(route))
+		}
+		switch {
+		case status >= http.StatusInternalServerError:
+			attrs = append(attrs, semconv.ErrorTypeKey.String(strconv.Itoa(status)))
+		case err != nil:
+			attrs = append(attrs, semconv.ErrorType(err))
+		}
+		duration.Record(ctx.Context(), time.Since(start).Seconds(), metric.WithAttributes(attrs...))
+		return err
+	}
+}
+
+// requestMethod normalizes unknown methods to "_OTHER" as required by the
+// semantic conventions, keeping attribute cardinality bounded.
+func requestMethod(method string) attribute.KeyValue {
+	switch method {
+	case http.MethodConnect, http.MethodDelete, http.MethodGet, http.MethodHead,
+		http.MethodOptions, http.MethodPatch, http.MethodPost, http.MethodPut,
+		http.MethodTrace:
+		return semconv.HTTPRequestMethodKey.String(method)
+	}
+	return semconv.HTTPRequestMethodOther
+}
+
+// responseStatus resolves the final status. When the chain returned an error
+// the response may not have been written yet, so the status is derived from
+// the error with httpx.ParseError.
+func responseStatus(ctx httpx.Context, err error) int {
+	status := http.StatusOK
+	if ri, ok := httpx.AsResponseInfo(ctx); ok {
+		status = ri.StatusCode()
+	}
+	if err != nil && status < http.StatusBadRequest {
+		_, s, _ := httpx.ParseError(err)
+		status = int(s)
+	}
+	return status
+}
@@ -0,0 +1,169 @@
+package httpx
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Page is a normalized pagination request parsed by BindPagination.
+type Page struct {
+	// Page is the 1-based page number. When the request uses offset, it is
+	// the page containing the first item.
+	Page int `json:"page"`
+	// Limit is the page size, between 1 and the configured maximum.
+	Limit int `json:"limit"`
+	// Offset is the number of items to skip.
+	Offset int `json:"offset"`
+	// Sort lists the requested sort keys in order of precedence.
+	Sort []SortField `json:"sort,omitempty"`
+}
+
+// SortField is one sort key, parsed from "name" (ascending) or "-name"
+// (descending).
+type SortField struct {
+	Field string `json:"field"`
+	Desc  bool   `json:"desc,omitempty"`
+}
+
+// PaginationOption configures BindPagination.
+type PaginationOption func(*paginationConfig)
+
+type paginationConfig struct {
+	defaultLimit int
+	maxLimit     int
+	sortFields   []string
+}
+
+// WithPageLimits sets the page size used when the request has no limit, and
+// the largest accepted page size. The defaults are 20 and 100.
+func WithPageLimits(defaultLimit, maxLimit int) PaginationOption {
+	return func(conf *paginationConfig) {
+		conf.defaultLimit, conf.maxLimit = defaultLimit, maxLimit
+	}
+}
+
+// WithSortFields restricts the fields accepted in the sort parameter. By
+// default any field is accepted, so handlers building queries from Page.Sort
+// should set it.
+func WithSortFields(fields ...string) PaginationOption {
+	return func(conf *paginationConfig) {
+		conf.sortFields = fields
+	}
+}
+
+// BindPagination parses the page, limit, offset and sort query parameters.
+//
+// A missing limit takes the default and a larger one is lowered to the
+// maximum. Offset takes precedence over page; otherwise the offset is derived
+// from the page. sort is a comma-separated list of fields, each optionally
+// prefixed by "-" for descending or "+" for ascending order, and may be
+// repeated. Malformed or negative numbers, a page below 1 and disallowed sort
+// fields are returned as errors with status 400.
+func BindPagination(ctx RequestInfo, opts ...PaginationOption) (Page, error) {
+	conf := paginationConfig{defaultLimit: 20, maxLimit: 100}
+	for _, opt := range opts {
+		opt(&conf)
+	}
+	if conf.defaultLimit < 1 {
+		conf.defaultLimit = 20
+	}
+
+	var p Page
+	var err error
+	if p.Limit, err = paginationInt(ctx, "limit", conf.defaultLimit, 1); err != nil {
+		return Page{}, err
+	}
+	if conf.maxLimit > 0 && p.Limit > conf.maxLimit {
+		p.Limit = conf.maxLimit
+	}
+	if ctx.Query("offset") != "" {
+		if p.Offset, err = paginationInt(ctx, "offset", 0, 0); err != nil {
+			return Page{}, err
+		}
+		p.Page = p.Offset/p.Limit + 1
+	} else {
+		if p.Page, err = paginationInt(ctx, "page", 1, 1); err != nil {
+			return Page{}, err
+		}
+		p.Offset = (p.Page - 1) * p.Limit
+	}
+
+	for _, value := range ctx.Queries()["sort"] {
+		for _, field := range strings.Split(value, ",") {
+			field = strings.TrimSpace(field)
+			if field == "" {
+				continue
+			}
+			sf := SortField{Field: field}
+			if name, ok := strings.CutPrefix(field, "-"); ok {
+				sf = SortField{Field: name, Desc: true}
+			} else if name, ok := strings.CutPrefix(field, "+"); ok {
+				sf.Field = name
+			}
+			if sf.Field == "" || (len(conf.sortFields) > 0 && !slices.Contains(conf.sortFields, sf.Field)) {
+				return Page{}, NewBadRequestError(fmt.Sprintf("invalid sort field %q", sf.Field))
+			}
+			p.Sort = append(p.Sort, sf)
+		}
+	}
+	return p, nil
+}
+
+func paginationInt(ctx RequestInfo, key string, def, minimum int) (int, error) {
+	value := ctx.Query(key)
+	if value == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < minimum {
+		return 0, NewBadRequestError(fmt.Sprintf("invalid %s %q", key, value))
+	}
+	return n, nil
+}
+
+// SetPaginationHeaders sets X-Total-Count to total and the Link header to
+// the PaginationLinks of p. Call it before writing the response body.
+func SetPaginationHeaders(ctx Context, p Page, total int) {
+	ctx.SetHeader("X-Total-Count", strconv.Itoa(total))
+	SetLinkHeaders(ctx, PaginationLinks(ctx, p, total))
+}
+
+// PaginationLinks returns the links to the first, prev, next and last pages
+// of p among total items, built from the request URL with its page or
+// offset parameter replaced. prev and next are left out on the first and
+// last pages. Handlers add links of their own, such as "self", before
+// passing them to SetLinkHeaders.
+func PaginationLinks(ctx RequestInfo, p Page, total int) []Link {
+	if p.Limit < 1 {
+		return nil
+	}
+
+	u := ctx.URL()
+	query := u.Query()
+	useOffset := query.Has("offset")
+	link := func(offset int, rel string) Link {
+		if useOffset {
+			query.Set("offset", strconv.Itoa(offset))
+		} else {
+			query.Set("page", strconv.Itoa(offset/p.Limit+1))
+		}
+		query.Set("limit", strconv.Itoa(p.Limit))
+		u.RawQuery = query.Encode()
+		return Link{URL: u.String(), Rel: rel}
+	}
+
+	last := 0
+	if total > 0 {
+		last = (total - 1) / p.Limit * p.Limit
+	}
+	links := []Link{link(0, "first")}
+	if p.Offset > 0 {
+		links = append(links, link(max(p.Offset-p.Limit, 0), "prev"))
+	}
+	if p.Offset+p.Limit < total {
+		links = append(links, link(p.Offset+p.Limit, "next"))
+	}
+	return append(links, link(last, "last"))
+}
@@ -0,0 +1,66 @@
+package httpx
+
+import (
+	"iter"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strings"
+)
+
+// QueryPairs iterates over the key-value pairs of a raw query string in
+// order, decoding them like url.ParseQuery and skipping the pairs it
+// rejects. Unlike url.ParseQuery, it builds no map.
+func QueryPairs(rawQuery string) iter.Seq2[string, string] {
+	return func(yield func(string, string) bool) {
+		for rawQuery != "" {
+			var pair string
+			pair, rawQuery, _ = strings.Cut(rawQuery, "&")
+			if pair == "" || strings.Contains(pair, ";") {
+				continue
+			}
+			key, value, _ := strings.Cut(pair, "=")
+			key, err := url.QueryUnescape(key)
+			if err != nil {
+				continue
+			}
+			value, err = url.QueryUnescape(value)
+			if err != nil {
+				continue
+			}
+			if !yield(key, value) {
+				return
+			}
+		}
+	}
+}
+
+// HeaderPairs iterates over the values of header with canonical keys, in no
+// particular order of keys. A key with several values is yielded once per
+// value, except Cookie, see RequestHeaderValues.
+func HeaderPairs(header http.Header) iter.Seq2[string, string] {
+	return func(yield func(string, string) bool) {
+		for key, values := range header {
+			key = textproto.CanonicalMIMEHeaderKey(key)
+			if key == "Cookie" {
+				values = joinCookieValues(values)
+			}
+			for _, value := range values {
+				if !yield(key, value) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// CookiePairs iterates over the names and values of the cookies sent with r.
+func CookiePairs(r *http.Request) iter.Seq2[string, string] {
+	return func(yield func(string, string) bool) {
+		for _, cookie := range r.Cookies() {
+			if !yield(cookie.Name, cookie.Value) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package httpx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func collectPairs(seq func(func(string, string) bool)) [][2]string {
+	var out [][2]string
+	seq(func(k, v string) bool {
+		out = append(out, [2]string{k, v})
+		return true
+	})
+	return out
+}
+
+func TestQueryPairs(t *testing.T) {
+	got := collectPairs(QueryPairs("b=2&a=1+1&&a=%2F&bad=%zz&semi=1;x=2&flag"))
+	want := [][2]string{{"b", "2"}, {"a", "1 1"}, {"a", "/"}, {"flag", ""}}
+	if !slices.Equal(got, want) {
+		t.Fatalf("QueryPairs() = %v, want %v", got, want)
+	}
+
+	var first []string
+	for k := range QueryPairs("a=1&b=2&c=3") {
+		first = append(first, k)
+		if k == "b" {
+			break
+		}
+	}
+	if !slices.Equal(first, []string{"a", "b"}) {
+		t.Fatalf("QueryPairs() after break = %v", first)
+	}
+}
+
+func TestHeaderAndCookiePairs(t *testing.T) {
+	header := http.Header{"x-multi": {"1", "2"}}
+	got := collectPairs(HeaderPairs(header))
+	if want := [][2]string{{"X-Multi", "1"}, {"X-Multi", "2"}}; !slices.Equal(got, want) {
+		t.Fatalf("HeaderPairs() = %v, want %v", got, want)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Cookie", "a=1; b=2")
+	got = collectPairs(CookiePairs(req))
+	if want := [][2]string{{"a", "1"}, {"b", "2"}}; !slices.Equal(got, want) {
+		t.Fatalf("CookiePairs() = %v, want %v", got, want)
+	}
+}
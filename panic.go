@@ -0,0 +1,80 @@
+package httpx
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+)
+
+// PanicPolicy governs what an engine does when a handler or middleware
+// panics. Adapters apply it with their WithPanicPolicy option, so the
+// behavior is the same on every framework.
+type PanicPolicy int
+
+const (
+	// PanicPropagate leaves panics to the framework, which is the default:
+	// net/http based servers log them and close the connection, and
+	// frameworks recover them when their recovery middleware is installed.
+	PanicPropagate PanicPolicy = iota
+	// PanicConvertToError recovers panics and passes a *PanicError to the
+	// engine's error handler, like an error returned by the handler.
+	PanicConvertToError
+	// PanicAbort500 recovers panics and responds with status 500 and the
+	// status text, without calling the error handler.
+	PanicAbort500
+)
+
+// PanicError is the error of a panic recovered under PanicConvertToError.
+// Its status is 500.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value any
+	// Stack is the stack trace of the panicking goroutine.
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("httpx: panic: %v", e.Value)
+}
+
+// Unwrap returns Value when it is an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// GetStatus returns 500.
+func (e *PanicError) GetStatus() int32 {
+	return http.StatusInternalServerError
+}
+
+// RecoverPanics returns middleware that applies policy to panics of the rest
+// of the chain. Adapters install it ahead of the other engine middleware for
+// their WithPanicPolicy option. Under PanicPropagate it only runs the chain.
+// http.ErrAbortHandler is always propagated, so handlers can still abort the
+// response on purpose.
+func RecoverPanics(policy PanicPolicy) Middleware {
+	if policy == PanicPropagate {
+		return func(ctx Context) error {
+			return ctx.Next()
+		}
+	}
+	return func(ctx Context) (err error) {
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+			if e, ok := r.(error); ok && errors.Is(e, http.ErrAbortHandler) {
+				panic(r)
+			}
+			if policy == PanicAbort500 {
+				err = ctx.Text(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+				return
+			}
+			err = &PanicError{Value: r, Stack: debug.Stack()}
+		}()
+		return ctx.Next()
+	}
+}
@@ -0,0 +1,43 @@
+package httpx
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRecoverPanics(t *testing.T) {
+	errBoom := errors.New("boom")
+	var got error
+	h := ToHTTPHandler(func(ctx Context) error {
+		panic(errBoom)
+	},
+		WithHTTPMiddleware(RecoverPanics(PanicConvertToError)),
+		WithHTTPErrorHandler(func(ctx Context, err error) {
+			got = err
+			_ = ctx.NoContent(http.StatusInternalServerError)
+		}),
+	)
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	var panicErr *PanicError
+	if !errors.As(got, &panicErr) || len(panicErr.Stack) == 0 {
+		t.Fatalf("error handler got %v, want a *PanicError with its stack", got)
+	}
+	if !errors.Is(got, errBoom) {
+		t.Fatalf("%v does not wrap the panic value", got)
+	}
+	if _, status, _ := ParseError(got); status != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", status)
+	}
+
+	h = ToHTTPHandler(func(ctx Context) error {
+		panic(http.ErrAbortHandler)
+	}, WithHTTPMiddleware(RecoverPanics(PanicAbort500)))
+	defer func() {
+		if r := recover(); r != http.ErrAbortHandler {
+			t.Fatalf("recovered %v, want http.ErrAbortHandler to propagate", r)
+		}
+	}()
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
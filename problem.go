@@ -0,0 +1,137 @@
+package httpx
+
+import (
+	"encoding/json"
+	"errors"
+	"maps"
+	"net/http"
+)
+
+// ProblemContentType is the media type of RFC 9457 problem details.
+const ProblemContentType = "application/problem+json"
+
+// ProblemDetails is an RFC 9457 problem details object. Extensions are
+// serialized as additional top-level members; they cannot replace the
+// standard members.
+//
+// A *ProblemDetails is also an error carrying its status, so handlers can
+// return one and have ProblemErrorHandler render it unchanged.
+type ProblemDetails struct {
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Instance   string
+	Extensions map[string]any
+}
+
+// MarshalJSON encodes the standard members, omitting empty ones, merged with
+// the extension members.
+func (p ProblemDetails) MarshalJSON() ([]byte, error) {
+	members := make(map[string]any, len(p.Extensions)+5)
+	maps.Copy(members, p.Extensions)
+	standard := []struct {
+		name  string
+		value any
+		set   bool
+	}{
+		{"type", p.Type, p.Type != ""},
+		{"title", p.Title, p.Title != ""},
+		{"status", p.Status, p.Status != 0},
+		{"detail", p.Detail, p.Detail != ""},
+		{"instance", p.Instance, p.Instance != ""},
+	}
+	for _, m := range standard {
+		delete(members, m.name)
+		if m.set {
+			members[m.name] = m.value
+		}
+	}
+	return json.Marshal(members)
+}
+
+func (p *ProblemDetails) Error() string {
+	title := p.Title
+	if title == "" {
+		title = http.StatusText(int(p.GetStatus()))
+	}
+	if p.Detail != "" {
+		return title + ": " + p.Detail
+	}
+	return title
+}
+
+func (p *ProblemDetails) GetStatus() int32 {
+	if p.Status == 0 {
+		return http.StatusInternalServerError
+	}
+	return int32(p.Status)
+}
+
+func (p *ProblemDetails) GetMessage() string {
+	if p.Detail != "" {
+		return p.Detail
+	}
+	return p.Title
+}
+
+// Problem writes p as an application/problem+json response with status
+// p.Status. A zero status is sent as 500, an empty type as "about:blank" and
+// an empty title as the status text.
+func Problem(ctx Context, p ProblemDetails) error {
+	if p.Status == 0 {
+		p.Status = http.StatusInternalServerError
+	}
+	if p.Type == "" {
+		p.Type = "about:blank"
+	}
+	if p.Title == "" {
+		p.Title = http.StatusText(p.Status)
+	}
+	body, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	return ctx.Bytes(p.Status, body, ProblemContentType)
+}
+
+// ProblemFromError converts err into problem details. A *ProblemDetails in
+// the chain is returned as is; otherwise status, code and message are taken
+// from ParseError. The message, or the error text when it is empty, becomes
+// the detail, a non-zero code that differs from the status becomes the
+// "code" extension member, and the fields of a *ValidationError become the
+// "errors" extension member.
+func ProblemFromError(err error) ProblemDetails {
+	var pd *ProblemDetails
+	if errors.As(err, &pd) {
+		return *pd
+	}
+	code, status, message := ParseError(err)
+	if message == "" {
+		message = err.Error()
+	}
+	p := ProblemDetails{Status: int(status), Detail: message}
+	if code != 0 && code != status {
+		p.Extensions = map[string]any{"code": code}
+	}
+	var ve *ValidationError
+	if errors.As(err, &ve) {
+		if p.Extensions == nil {
+			p.Extensions = make(map[string]any, 1)
+		}
+		p.Extensions["errors"] = ve.Errors
+	}
+	return p
+}
+
+// ProblemErrorHandler is an ErrorHandler rendering every error as problem
+// details with ProblemFromError, using the request path as the instance when
+// none is set, after translating the error with LocalizeError. Adapters
+// accept it through their AdaptErrorHandler helpers.
+func ProblemErrorHandler(ctx Context, err error) {
+	p := ProblemFromError(LocalizeError(ctx, err))
+	if p.Instance == "" {
+		p.Instance = ctx.Path()
+	}
+	_ = Problem(ctx, p)
+}
@@ -0,0 +1,57 @@
+package httpx
+
+import (
+	"context"
+	"net/http"
+)
+
+type outgoingHeadersContextKey struct{}
+
+// SetOutgoingHeader records a header that downstream HTTP requests made while
+// serving ctx should carry, such as a request ID or a traceparent. It is
+// called by the middleware that owns the header; handlers retrieve the set
+// with OutgoingHeaders or attach it automatically with OutgoingTransport.
+func SetOutgoingHeader(ctx Context, key, value string) {
+	headers := OutgoingHeaders(ctx.Context())
+	if headers == nil {
+		headers = make(http.Header)
+	}
+	headers.Set(key, value)
+	ctx.SetContext(context.WithValue(ctx.Context(), outgoingHeadersContextKey{}, headers))
+}
+
+// OutgoingHeaders returns a copy of the headers recorded with
+// SetOutgoingHeader in ctx, or nil when there are none.
+func OutgoingHeaders(ctx context.Context) http.Header {
+	headers, _ := ctx.Value(outgoingHeadersContextKey{}).(http.Header)
+	return headers.Clone()
+}
+
+// OutgoingTransport wraps base so that requests carry the OutgoingHeaders of
+// their context. Headers already set on a request are kept. A nil base uses
+// http.DefaultTransport.
+func OutgoingTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return outgoingTransport{base: base}
+}
+
+type outgoingTransport struct {
+	base http.RoundTripper
+}
+
+func (t outgoingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	headers := OutgoingHeaders(req.Context())
+	if len(headers) == 0 {
+		return t.base.RoundTrip(req)
+	}
+	// RoundTrippers must not modify the caller's request.
+	req = req.Clone(req.Context())
+	for key, values := range headers {
+		if _, ok := req.Header[key]; !ok {
+			req.Header[key] = values
+		}
+	}
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,48 @@
+package httpx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestOutgoingTransport(t *testing.T) {
+	var sent http.Header
+	transport := OutgoingTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		sent = req.Header
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+	}))
+
+	h := ToHTTPHandler(func(ctx Context) error {
+		SetOutgoingHeader(ctx, "X-Request-ID", "req-1")
+		SetOutgoingHeader(ctx, "Baggage", "tenant=acme")
+		req, err := http.NewRequestWithContext(ctx.Context(), http.MethodGet, "http://downstream", nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Baggage", "explicit")
+		resp, err := transport.RoundTrip(req)
+		if err != nil {
+			return err
+		}
+		_ = resp.Body.Close()
+		if req.Header.Get("X-Request-ID") != "" {
+			t.Errorf("caller's request was modified")
+		}
+		return ctx.NoContent(http.StatusNoContent)
+	})
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if got := sent.Get("X-Request-ID"); got != "req-1" {
+		t.Fatalf("X-Request-ID = %q, want req-1", got)
+	}
+	if got := sent.Get("Baggage"); got != "explicit" {
+		t.Fatalf("Baggage = %q, want the request's own value", got)
+	}
+}
@@ -0,0 +1,208 @@
+package httpx
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"sync"
+)
+
+// ProxyOption configures Proxy.
+type ProxyOption func(*proxyConfig)
+
+type proxyConfig struct {
+	rewrite        func(path string) string
+	transport      http.RoundTripper
+	preserveHost   bool
+	modifyResponse func(*http.Response) error
+}
+
+// WithProxyRewrite rewrites the request path before it is joined with the
+// target path, e.g. to strip the prefix a gateway route is mounted on.
+func WithProxyRewrite(rewrite func(path string) string) ProxyOption {
+	return func(conf *proxyConfig) {
+		conf.rewrite = rewrite
+	}
+}
+
+// WithProxyTransport sets the transport used to reach the target. The default
+// is http.DefaultTransport.
+func WithProxyTransport(transport http.RoundTripper) ProxyOption {
+	return func(conf *proxyConfig) {
+		conf.transport = transport
+	}
+}
+
+// WithProxyPreserveHost forwards the incoming Host header instead of the
+// target's host.
+func WithProxyPreserveHost() ProxyOption {
+	return func(conf *proxyConfig) {
+		conf.preserveHost = true
+	}
+}
+
+// WithProxyModifyResponse sets a hook that can modify the target's response
+// before it is forwarded. Returning an error fails the request with status
+// 502.
+func WithProxyModifyResponse(modify func(*http.Response) error) ProxyOption {
+	return func(conf *proxyConfig) {
+		conf.modifyResponse = modify
+	}
+}
+
+// Proxy returns a Handler that forwards requests to target using
+// httputil.ReverseProxy. The request path is joined with the target path and
+// the query strings are merged. Requests carry X-Forwarded-For,
+// X-Forwarded-Host and X-Forwarded-Proto, the latter two following Host and
+// Scheme of the incoming request. Request and response bodies are streamed.
+//
+// Failures to reach the target are returned as errors with status 502, or
+// 504 when the request deadline passes. On contexts implementing
+// HTTPInterop the proxy writes to the real response writer, so protocol
+// upgrades such as websockets are passed through when the writer supports
+// hijacking; on other contexts, such as those of fiberx and hertzx, upgrades
+// fail with status 502.
+func Proxy(target *url.URL, opts ...ProxyOption) Handler {
+	conf := proxyConfig{}
+	for _, opt := range opts {
+		opt(&conf)
+	}
+	return func(ctx Context) error {
+		var proxyErr error
+		proxy := &httputil.ReverseProxy{
+			Rewrite: func(pr *httputil.ProxyRequest) {
+				if conf.rewrite != nil {
+					pr.Out.URL.Path = conf.rewrite(pr.Out.URL.Path)
+					pr.Out.URL.RawPath = ""
+				}
+				pr.SetURL(target)
+				pr.SetXForwarded()
+				pr.Out.Header.Set("X-Forwarded-Host", ctx.Host())
+				pr.Out.Header.Set("X-Forwarded-Proto", ctx.Scheme())
+				if conf.preserveHost {
+					pr.Out.Host = pr.In.Host
+				}
+			},
+			Transport:      conf.transport,
+			ModifyResponse: conf.modifyResponse,
+			ErrorHandler: func(_ http.ResponseWriter, r *http.Request, err error) {
+				proxyErr = proxyError(r, err)
+			},
+		}
+		if hi, ok := AsHTTPInterop(ctx); ok {
+			proxy.ServeHTTP(proxyWriter{hi.HTTPResponseWriter()}, hi.HTTPRequest())
+			return proxyErr
+		}
+		return serveHandlerEmulated(ctx, proxy, nil, &proxyErr)
+	}
+}
+
+func proxyError(r *http.Request, err error) error {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(r.Context().Err(), context.DeadlineExceeded) {
+		return WithStatus(http.StatusGatewayTimeout, err)
+	}
+	return WithStatus(http.StatusBadGateway, err)
+}
+
+// proxyWriter hides the deprecated http.CloseNotifier, which some writers,
+// such as gin's, claim to implement but cannot always honour. Flushing and
+// hijacking remain available through Unwrap.
+type proxyWriter struct {
+	http.ResponseWriter
+}
+
+func (w proxyWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// serveHandlerEmulated runs h against an emulated request, adjusted by
+// rewrite when non-nil, and streams its response through ctx. h runs in its
+// own goroutine writing to a pipe; the response is committed once h writes
+// its header. handlerErr is returned when h writes nothing.
+func serveHandlerEmulated(ctx Context, h http.Handler, rewrite func(*http.Request), handlerErr *error) error {
+	body := ctx.BodyReader()
+	if ctx.ContentLength() == 0 {
+		body = http.NoBody
+	}
+	req, err := newEmulatedRequest(ctx, body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = ctx.ContentLength()
+	if rewrite != nil {
+		rewrite(req)
+	}
+
+	pr, pw := io.Pipe()
+	w := &pipeWriter{header: make(http.Header), pw: pw, committed: make(chan struct{})}
+	go func() {
+		defer func() {
+			w.commit()
+			_ = pw.Close()
+		}()
+		h.ServeHTTP(w, req)
+	}()
+	<-w.committed
+	if w.status == 0 {
+		// h returned without writing, such as a proxy failing before it
+		// reached the target or received its response.
+		_ = pr.Close()
+		return *handlerErr
+	}
+
+	size := -1
+	if n, err := strconv.Atoi(w.sent.Get("Content-Length")); err == nil {
+		size = n
+	}
+	contentType := w.sent.Get("Content-Type")
+	w.sent.Del("Content-Length")
+	w.sent.Del("Content-Type")
+	applyHeaders(ctx, w.sent)
+	return ctx.DataFromReader(w.status, contentType, pr, size)
+}
+
+// pipeWriter is the http.ResponseWriter given to the handler by
+// serveHandlerEmulated. sent is the header as of the first write; later
+// changes, such as trailers, are not forwarded.
+type pipeWriter struct {
+	header    http.Header
+	sent      http.Header
+	status    int
+	pw        *io.PipeWriter
+	once      sync.Once
+	committed chan struct{}
+}
+
+func (w *pipeWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *pipeWriter) WriteHeader(code int) {
+	// Informational responses are not forwarded.
+	if code >= 100 && code < 200 {
+		return
+	}
+	w.once.Do(func() {
+		w.sent = w.header.Clone()
+		w.status = code
+		close(w.committed)
+	})
+}
+
+func (w *pipeWriter) Write(b []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	return w.pw.Write(b)
+}
+
+// Flush is a no-op: every Write is handed to the reader directly.
+func (w *pipeWriter) Flush() {}
+
+func (w *pipeWriter) commit() {
+	w.once.Do(func() {
+		close(w.committed)
+	})
+}
@@ -0,0 +1,98 @@
+package httpx
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// ErrUnknownRoute is returned by URLFor for names no route was given, see
+// Route.Name.
+var ErrUnknownRoute = errors.New("httpx: unknown route name")
+
+var routeNames sync.Map // name -> route path
+
+// URLFor returns the path of the route named name with its parameters,
+// ":id" and "*path" segments, replaced by the escaped values of params,
+// followed by the encoded query when it is not empty:
+//
+//	httpx.URLFor("user", map[string]string{"id": "42"}, url.Values{"tab": {"posts"}})
+//	// "/users/42?tab=posts"
+//
+// Wildcard values keep their slashes. It returns ErrUnknownRoute for
+// unknown names and an error for parameters missing from params.
+func URLFor(name string, params map[string]string, query url.Values) (string, error) {
+	path, ok := routeNames.Load(name)
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnknownRoute, name)
+	}
+	segments := strings.Split(path.(string), "/")
+	for i, segment := range segments {
+		if segment == "" || (segment[0] != ':' && segment[0] != '*') {
+			continue
+		}
+		key := segment[1:]
+		if key == "" {
+			key = segment
+		}
+		value, ok := params[key]
+		if !ok {
+			return "", fmt.Errorf("httpx: route %q: missing parameter %q", name, key)
+		}
+		if segment[0] == ':' {
+			segments[i] = url.PathEscape(value)
+			continue
+		}
+		parts := strings.Split(strings.TrimPrefix(value, "/"), "/")
+		for j, part := range parts {
+			parts[j] = url.PathEscape(part)
+		}
+		segments[i] = strings.Join(parts, "/")
+	}
+	u := strings.Join(segments, "/")
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	return u, nil
+}
+
+// Redirect redirects the request to location with code, which must be a
+// redirect status from 300 to 308; other codes return an error instead of
+// the panics or malformed responses of some frameworks.
+func Redirect(ctx Context, code int, location string) error {
+	if code < http.StatusMultipleChoices || code > http.StatusPermanentRedirect {
+		return fmt.Errorf("httpx: invalid redirect status %d", code)
+	}
+	return ctx.Redirect(code, location)
+}
+
+// RedirectToRoute redirects the request with status 302 to the route named
+// name, see URLFor.
+func RedirectToRoute(ctx Context, name string, params map[string]string, query url.Values) error {
+	location, err := URLFor(name, params, query)
+	if err != nil {
+		return err
+	}
+	return Redirect(ctx, http.StatusFound, location)
+}
+
+// PermanentRedirect redirects the request with status 308, which keeps the
+// method and body, to location, for moved endpoints. The query string of the
+// request is kept unless location has one:
+//
+//	r.Any("/v1/items", func(ctx httpx.Context) error {
+//		return httpx.PermanentRedirect(ctx, "/v2/items")
+//	})
+func PermanentRedirect(ctx Context, location string) error {
+	if raw := ctx.RawQuery(); raw != "" && !strings.Contains(location, "?") {
+		path, fragment, ok := strings.Cut(location, "#")
+		location = path + "?" + raw
+		if ok {
+			location += "#" + fragment
+		}
+	}
+	return Redirect(ctx, http.StatusPermanentRedirect, location)
+}
@@ -0,0 +1,57 @@
+package httpx
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestURLFor(t *testing.T) {
+	NewRouteFor(RouteInfo{Method: http.MethodGet, Path: "/users/:id/files/*path"}).Name("test.file")
+	got, err := URLFor("test.file", map[string]string{"id": "a b", "path": "/docs/q&a.txt"}, url.Values{"v": {"2"}})
+	if want := "/users/a%20b/files/docs/q&a.txt?v=2"; err != nil || got != want {
+		t.Fatalf("URLFor() = %q, %v, want %q", got, err, want)
+	}
+	if _, err := URLFor("test.file", map[string]string{"id": "1"}, nil); err == nil {
+		t.Fatal("URLFor() without path succeeded")
+	}
+	if _, err := URLFor("test.missing", nil, nil); !errors.Is(err, ErrUnknownRoute) {
+		t.Fatalf("URLFor(missing) = %v, want ErrUnknownRoute", err)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Name of a route without path did not panic")
+		}
+	}()
+	NewRoute().Name("test.pathless")
+}
+
+func TestRedirects(t *testing.T) {
+	tests := []struct {
+		name, target string
+		h            Handler
+		wantStatus   int
+		wantLocation string
+	}{
+		{name: "permanent keeps query", target: "/v1/items?page=2", h: func(ctx Context) error {
+			return PermanentRedirect(ctx, "/v2/items#top")
+		}, wantStatus: http.StatusPermanentRedirect, wantLocation: "/v2/items?page=2#top"},
+		{name: "permanent with query", target: "/v1/items?page=2", h: func(ctx Context) error {
+			return PermanentRedirect(ctx, "/v2/items?page=1")
+		}, wantStatus: http.StatusPermanentRedirect, wantLocation: "/v2/items?page=1"},
+		{name: "invalid status", target: "/", h: func(ctx Context) error {
+			return Redirect(ctx, http.StatusOK, "/elsewhere")
+		}, wantStatus: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			ToHTTPHandler(tt.h).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+			if rec.Code != tt.wantStatus || rec.Header().Get("Location") != tt.wantLocation {
+				t.Fatalf("response %d, Location %q, want %d %q", rec.Code, rec.Header().Get("Location"), tt.wantStatus, tt.wantLocation)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package httpx
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+var (
+	// ErrTooManyRequests is wrapped by the errors of TooManyRequests.
+	ErrTooManyRequests = errors.New(http.StatusText(http.StatusTooManyRequests))
+	// ErrServiceUnavailable is wrapped by the errors of ServiceUnavailable.
+	ErrServiceUnavailable = errors.New(http.StatusText(http.StatusServiceUnavailable))
+)
+
+// TooManyRequests sets the Retry-After header of the response to retryAfter
+// and returns a status 429 error for the handler to return, so rate limiters
+// throttle clients the same way:
+//
+//	if !limiter.Allow() {
+//		return httpx.TooManyRequests(ctx, time.Second)
+//	}
+//
+// Retry-After is given in whole seconds, rounded up; it is not set when
+// retryAfter is zero or negative. The error wraps ErrTooManyRequests.
+func TooManyRequests(ctx Context, retryAfter time.Duration) error {
+	SetRetryAfter(ctx, retryAfter)
+	return WithStatus(http.StatusTooManyRequests, ErrTooManyRequests)
+}
+
+// ServiceUnavailable is TooManyRequests for status 503, for load shedding
+// and maintenance. The error wraps ErrServiceUnavailable.
+func ServiceUnavailable(ctx Context, retryAfter time.Duration) error {
+	SetRetryAfter(ctx, retryAfter)
+	return WithStatus(http.StatusServiceUnavailable, ErrServiceUnavailable)
+}
+
+// SetRetryAfter sets the Retry-After header of the response to retryAfter in
+// whole seconds, rounded up. It does nothing when retryAfter is zero or
+// negative.
+func SetRetryAfter(ctx Context, retryAfter time.Duration) {
+	if retryAfter <= 0 {
+		return
+	}
+	seconds := (retryAfter + time.Second - 1) / time.Second
+	ctx.SetHeader("Retry-After", strconv.FormatInt(int64(seconds), 10))
+}
@@ -0,0 +1,45 @@
+package httpx
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRetryAfterErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		fn         func(Context, time.Duration) error
+		retryAfter time.Duration
+		wantStatus int
+		wantErr    error
+		wantHeader string
+	}{
+		{name: "too many requests", fn: TooManyRequests, retryAfter: 2 * time.Second, wantStatus: http.StatusTooManyRequests, wantErr: ErrTooManyRequests, wantHeader: "2"},
+		{name: "rounded up", fn: TooManyRequests, retryAfter: 1500 * time.Millisecond, wantStatus: http.StatusTooManyRequests, wantErr: ErrTooManyRequests, wantHeader: "2"},
+		{name: "no retry after", fn: TooManyRequests, wantStatus: http.StatusTooManyRequests, wantErr: ErrTooManyRequests},
+		{name: "service unavailable", fn: ServiceUnavailable, retryAfter: time.Minute, wantStatus: http.StatusServiceUnavailable, wantErr: ErrServiceUnavailable, wantHeader: "60"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var handlerErr error
+			h := ToHTTPHandler(func(ctx Context) error {
+				handlerErr = tt.fn(ctx, tt.retryAfter)
+				return handlerErr
+			})
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+			if !errors.Is(handlerErr, tt.wantErr) {
+				t.Fatalf("error %v, want %v", handlerErr, tt.wantErr)
+			}
+			if _, status, _ := ParseError(handlerErr); status != int32(tt.wantStatus) {
+				t.Fatalf("error status %d, want %d", status, tt.wantStatus)
+			}
+			if got := rec.Header().Get("Retry-After"); got != tt.wantHeader {
+				t.Fatalf("Retry-After = %q, want %q", got, tt.wantHeader)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package httpx
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// RobotsRule is a group of robots.txt rules for some user agents.
+type RobotsRule struct {
+	// UserAgents are the crawlers the rule applies to, all of them ("*")
+	// when empty.
+	UserAgents []string
+	// Allow and Disallow are the path prefixes the crawlers may and may
+	// not fetch.
+	Allow    []string
+	Disallow []string
+}
+
+// RobotsPolicy is the robots.txt of a site, see ServeRobotsTxt.
+type RobotsPolicy struct {
+	// Rules are the groups of the file. A policy without rules allows
+	// every crawler everywhere.
+	Rules []RobotsRule
+	// Sitemaps are the absolute URLs of the sitemaps of the site.
+	Sitemaps []string
+}
+
+// String formats p as an RFC 9309 robots.txt file.
+func (p RobotsPolicy) String() string {
+	var b strings.Builder
+	rules := p.Rules
+	if len(rules) == 0 {
+		rules = []RobotsRule{{Disallow: []string{""}}}
+	}
+	for i, rule := range rules {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		agents := rule.UserAgents
+		if len(agents) == 0 {
+			agents = []string{"*"}
+		}
+		for _, agent := range agents {
+			b.WriteString("User-agent: " + agent + "\n")
+		}
+		for _, path := range rule.Allow {
+			b.WriteString("Allow: " + path + "\n")
+		}
+		for _, path := range rule.Disallow {
+			b.WriteString(strings.TrimSpace("Disallow: "+path) + "\n")
+		}
+	}
+	if len(p.Sitemaps) > 0 {
+		b.WriteString("\n")
+		for _, sitemap := range p.Sitemaps {
+			b.WriteString("Sitemap: " + sitemap + "\n")
+		}
+	}
+	return b.String()
+}
+
+// ServeRobotsTxt registers GET /robots.txt on r, serving policy as plain
+// text:
+//
+//	httpx.ServeRobotsTxt(engine.Group(""), httpx.RobotsPolicy{
+//		Rules:    []httpx.RobotsRule{{Disallow: []string{"/admin/"}}},
+//		Sitemaps: []string{"https://example.com/sitemap.xml"},
+//	})
+func ServeRobotsTxt(r Router, policy RobotsPolicy) {
+	body := policy.String()
+	r.GET("/robots.txt", func(ctx Context) error {
+		return ctx.Text(http.StatusOK, body)
+	})
+}
+
+// SecurityTxt is the RFC 9116 security.txt of a site, telling security
+// researchers how to report vulnerabilities, see ServeSecurityTxt. Contact
+// and Expires are required; the other fields are optional.
+type SecurityTxt struct {
+	// Contact are the URIs to report vulnerabilities to, such as
+	// "mailto:security@example.com", in order of preference.
+	Contact []string
+	// Expires is when the file should no longer be considered current,
+	// recommended to be less than a year away.
+	Expires time.Time
+	// Encryption are the URIs of keys to encrypt reports with.
+	Encryption []string
+	// Acknowledgments are the URIs of pages thanking reporters.
+	Acknowledgments []string
+	// PreferredLanguages are the language tags reports may be written in.
+	PreferredLanguages []string
+	// Canonical are the URIs the file is served at.
+	Canonical []string
+	// Policy are the URIs of the vulnerability disclosure policies.
+	Policy []string
+	// Hiring are the URIs of security related job openings.
+	Hiring []string
+}
+
+// String formats s as a security.txt file.
+func (s SecurityTxt) String() string {
+	var b strings.Builder
+	field := func(name string, values []string) {
+		for _, v := range values {
+			b.WriteString(name + ": " + v + "\n")
+		}
+	}
+	field("Contact", s.Contact)
+	if !s.Expires.IsZero() {
+		field("Expires", []string{s.Expires.UTC().Format(time.RFC3339)})
+	}
+	field("Encryption", s.Encryption)
+	field("Acknowledgments", s.Acknowledgments)
+	if len(s.PreferredLanguages) > 0 {
+		field("Preferred-Languages", []string{strings.Join(s.PreferredLanguages, ", ")})
+	}
+	field("Canonical", s.Canonical)
+	field("Policy", s.Policy)
+	field("Hiring", s.Hiring)
+	return b.String()
+}
+
+// ServeSecurityTxt registers GET /.well-known/security.txt on r, serving s
+// as plain text. It panics when s has no Contact or Expires, which RFC 9116
+// requires.
+func ServeSecurityTxt(r Router, s SecurityTxt) {
+	if len(s.Contact) == 0 || s.Expires.IsZero() {
+		panic("httpx: security.txt requires Contact and Expires")
+	}
+	body := s.String()
+	r.GET("/.well-known/security.txt", func(ctx Context) error {
+		return ctx.Text(http.StatusOK, body)
+	})
+}
@@ -0,0 +1,40 @@
+package httpx
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRobotsPolicyString(t *testing.T) {
+	if got, want := (RobotsPolicy{}).String(), "User-agent: *\nDisallow:\n"; got != want {
+		t.Fatalf("empty policy = %q, want %q", got, want)
+	}
+	policy := RobotsPolicy{
+		Rules: []RobotsRule{
+			{UserAgents: []string{"GPTBot", "CCBot"}, Disallow: []string{"/"}},
+			{Allow: []string{"/admin/public"}, Disallow: []string{"/admin/"}},
+		},
+		Sitemaps: []string{"https://example.com/sitemap.xml"},
+	}
+	want := "User-agent: GPTBot\nUser-agent: CCBot\nDisallow: /\n\n" +
+		"User-agent: *\nAllow: /admin/public\nDisallow: /admin/\n\n" +
+		"Sitemap: https://example.com/sitemap.xml\n"
+	if got := policy.String(); got != want {
+		t.Fatalf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestSecurityTxtString(t *testing.T) {
+	s := SecurityTxt{
+		Contact:            []string{"mailto:security@example.com", "https://example.com/report"},
+		Expires:            time.Date(2030, 1, 2, 3, 4, 5, 0, time.FixedZone("CET", 3600)),
+		PreferredLanguages: []string{"en", "de"},
+		Policy:             []string{"https://example.com/disclosure"},
+	}
+	want := "Contact: mailto:security@example.com\nContact: https://example.com/report\n" +
+		"Expires: 2030-01-02T02:04:05Z\nPreferred-Languages: en, de\n" +
+		"Policy: https://example.com/disclosure\n"
+	if got := s.String(); got != want {
+		t.Fatalf("String() = %q, want %q", got, want)
+	}
+}
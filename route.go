@@ -0,0 +1,147 @@
+package httpx
+
+import (
+	"fmt"
+	"maps"
+	"strconv"
+	"time"
+)
+
+// Route is a route registered on a Router. Metadata attached to it with
+// Meta is visible to the route's handler and router middleware through
+// RouteMeta, so middleware such as authentication or rate limiting can key
+// its decisions on the matched route instead of matching path prefixes:
+//
+//	r.GET("/health", health).Meta("auth", "public")
+//
+// Meta must be called while routes are registered, before the engine serves
+// requests.
+type Route struct {
+	info      RouteInfo
+	meta      map[string]string
+	timeout   time.Duration
+	bodyLimit int64
+}
+
+// RouteOption configures a Route, see Route.With.
+type RouteOption func(*Route)
+
+// NewRoute returns a route without metadata or path, for handlers that are
+// not registered as routes. Adapters serve the handler of each route
+// composed with Route.Compose.
+func NewRoute() *Route {
+	return &Route{}
+}
+
+// NewRouteFor returns a route without metadata registered as info, whose
+// Name maps a name to info.Path. Adapters return it from the registration
+// methods of Router.
+func NewRouteFor(info RouteInfo) *Route {
+	return &Route{info: info}
+}
+
+// Name names the route, so URLFor and RedirectToRoute build URLs of its path
+// from a name rather than a hard-coded path:
+//
+//	r.GET("/users/:id", showUser).Name("user")
+//
+// Names are shared by the engines of the process; naming another route
+// with a name replaces it, as when ReplaceRoutes registers the routes
+// again. Name panics for routes registered without a path, see
+// NewRouteFor.
+func (r *Route) Name(name string) *Route {
+	if r.info.Path == "" {
+		panic(fmt.Errorf("httpx: route %q has no path to name", name))
+	}
+	routeNames.Store(name, r.info.Path)
+	return r
+}
+
+// Meta sets the metadata value of key and returns r for chaining. The
+// values of RouteMetaTimeout and RouteMetaBodyLimit are parsed when they are
+// set; Meta panics when they are invalid.
+func (r *Route) Meta(key, value string) *Route {
+	switch key {
+	case RouteMetaTimeout:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			panic(fmt.Errorf("httpx: route metadata %s: %w", key, err))
+		}
+		r.timeout = d
+		limitsInUse.Store(true)
+	case RouteMetaBodyLimit:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			panic(fmt.Errorf("httpx: route metadata %s: %w", key, err))
+		}
+		r.bodyLimit = n
+		limitsInUse.Store(true)
+	}
+	if r.meta == nil {
+		r.meta = make(map[string]string)
+	}
+	r.meta[key] = value
+	return r
+}
+
+// With applies opts to r and returns r for chaining.
+func (r *Route) With(opts ...RouteOption) *Route {
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Metadata returns a copy of the metadata of r.
+func (r *Route) Metadata() map[string]string {
+	return maps.Clone(r.meta)
+}
+
+var routeKey = NewKey[*Route]("route")
+
+// Compose composes middlewares around h like Compose, making r the matched
+// route of the requests the result serves. Adapters compose each route's
+// handler with its router middleware through it, so the middleware sees the
+// metadata; engine middleware sees it once Next returns. The result applies
+// the route's timeout and body limit, and is traced for requests traced with
+// TraceChain.
+func (r *Route) Compose(h Handler, middlewares ...Middleware) Handler {
+	names := make([]string, 0, len(middlewares)+1)
+	for _, m := range middlewares {
+		names = append(names, funcName(m))
+	}
+	names = append(names, funcName(h))
+	traced := composeTraced(h, middlewares)
+	h = Compose(h, middlewares...)
+	return func(ctx Context) error {
+		if len(r.meta) > 0 {
+			SetTyped(ctx, routeKey, r)
+		}
+		if limitsInUse.Load() {
+			cancel, err := r.applyLimits(ctx)
+			if cancel != nil {
+				defer cancel()
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if chainTracing.Load() {
+			if t, ok := chainTraceKey.Get(ctx); ok && t != nil {
+				t.chain.Names = names
+				return traced(ctx)
+			}
+		}
+		return h(ctx)
+	}
+}
+
+// RouteMeta returns the metadata of the matched route, or nil when it has
+// none. The map must not be modified.
+func RouteMeta(ctx StateStore) map[string]string {
+	route, ok := routeKey.Get(ctx)
+	if !ok || route == nil {
+		return nil
+	}
+	return route.meta
+}
@@ -3,6 +3,8 @@ package httpx
 import (
 	"context"
 	"io/fs"
+	"net"
+	"net/http"
 )
 
 type H map[string]any
@@ -18,10 +20,11 @@ type MiddlewareScope interface {
 	Use(...Middleware)
 }
 
-// Registrar registers handlers on a router scope.
+// Registrar registers handlers on a router scope. Registering a handler
+// returns its Route, to attach metadata to.
 type Registrar interface {
-	Handle(method, path string, h Handler)
-	Any(path string, h Handler)
+	Handle(method, path string, h Handler) *Route
+	Any(path string, h Handler) *Route
 	Static(prefix, root string)
 	StaticFS(prefix string, fs fs.FS)
 }
@@ -51,13 +54,13 @@ type Router interface {
 
 	// HTTP method shortcuts for ergonomic API
 
-	GET(path string, h Handler)
-	POST(path string, h Handler)
-	PUT(path string, h Handler)
-	DELETE(path string, h Handler)
-	PATCH(path string, h Handler)
-	HEAD(path string, h Handler)
-	OPTIONS(path string, h Handler)
+	GET(path string, h Handler) *Route
+	POST(path string, h Handler) *Route
+	PUT(path string, h Handler) *Route
+	DELETE(path string, h Handler) *Route
+	PATCH(path string, h Handler) *Route
+	HEAD(path string, h Handler) *Route
+	OPTIONS(path string, h Handler) *Route
 }
 
 // Engine is the entrypoint: it can serve HTTP, apply global middleware,
@@ -70,7 +73,77 @@ type Engine interface {
 
 	Start() error
 	Stop(ctx context.Context) error
+
+	// StartTLS serves HTTPS using the adapter's TLS options. It returns
+	// ErrTLSNotConfigured when no certificate or TLS config was provided.
+	StartTLS() error
+
+	// StopGraceful stops accepting new connections, waits for in-flight
+	// requests to complete, and then runs drain when it is non-nil.
+	// drain runs even if the shutdown deadline is exceeded, so callers can
+	// always release resources such as connection pools or queues.
+	StopGraceful(ctx context.Context, drain func()) error
+
 	IsRunning() bool // Server status check
+
+	// BoundAddr returns the address the server is listening on once it is
+	// running, so callers starting on ":0" can discover the chosen port.
+	// It returns nil before Start and after the server has exited.
+	BoundAddr() net.Addr
+
+	// Test serves req in-process, without a listener, and returns the
+	// response, like fiber's App.Test. The engine need not be started, so
+	// integration tests can exercise its routes directly.
+	Test(req *http.Request) (*http.Response, error)
+
+	// Lifecycle hooks
+
+	// OnStart registers a hook that runs before the engine starts serving.
+	// If a hook returns an error, Start returns it without serving.
+	OnStart(fn func() error)
+
+	// OnStopping registers a hook that runs as soon as Stop is called, before
+	// the server stops accepting connections, so readiness can be withdrawn
+	// while in-flight requests drain.
+	OnStopping(fn func())
+
+	// OnStop registers a hook that runs once the server has stopped accepting
+	// connections and drained in-flight requests. Hooks run in reverse
+	// registration order and their errors are joined into Stop's result.
+	OnStop(fn func(ctx context.Context) error)
+
+	// OnRouteRegistered registers a hook invoked for every handler route
+	// registered on the engine's routers. Routes registered earlier are
+	// replayed to the hook when it is added.
+	OnRouteRegistered(fn func(RouteInfo))
+
+	// Freeze finalizes the routes: registering a route afterwards panics
+	// with an error wrapping ErrRoutesFrozen. Start freezes the engine once
+	// the start hooks have run, unless the adapter's WithAllowRuntimeRoutes
+	// option is set.
+	Freeze()
+}
+
+// NativeEngineProvider exposes the server behind an engine, so operators can
+// tune settings that Engine does not cover, such as connection state
+// callbacks or the TLS session cache. Adapters provide typed wrappers such
+// as ginx.Server, which document what the native value is.
+type NativeEngineProvider interface {
+	Native() any
+}
+
+// AsNativeEngine returns the native server behind e when supported.
+func AsNativeEngine[T any](e Engine) (T, bool) {
+	var zero T
+	nativeProvider, ok := e.(NativeEngineProvider)
+	if !ok {
+		return zero, false
+	}
+	native, ok := nativeProvider.Native().(T)
+	if !ok {
+		return zero, false
+	}
+	return native, true
 }
 
 // WithJson wraps a handler with JSON response.
@@ -2,11 +2,204 @@ package httpx
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
+	"fmt"
+	"io/fs"
+	"net"
 	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
+var (
+	// ErrTLSNotConfigured is returned by Engine.StartTLS when neither a
+	// certificate pair nor a TLS config was provided.
+	ErrTLSNotConfigured = errors.New("httpx: TLS is not configured")
+	// ErrH2CUnsupported is returned by Start when h2c was requested but the
+	// underlying framework cannot serve cleartext HTTP/2.
+	ErrH2CUnsupported = errors.New("httpx: h2c is not supported")
+)
+
+// NewTLSConfig builds a server TLS config from an optional base config and an
+// optional certificate/key file pair. The base config is cloned, and the
+// loaded certificate is appended to its certificates. It returns
+// ErrTLSNotConfigured when base is nil and no files are given.
+func NewTLSConfig(base *tls.Config, certFile, keyFile string) (*tls.Config, error) {
+	if base == nil && certFile == "" && keyFile == "" {
+		return nil, ErrTLSNotConfigured
+	}
+	conf := &tls.Config{}
+	if base != nil {
+		conf = base.Clone()
+	}
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, err
+		}
+		conf.Certificates = append(conf.Certificates, cert)
+	}
+	return conf, nil
+}
+
+// EnableH2C allows server to accept cleartext HTTP/2 (h2c) connections in
+// addition to HTTP/1 and HTTP/2 over TLS.
+func EnableH2C(server *http.Server) {
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	protocols.SetHTTP2(true)
+	protocols.SetUnencryptedHTTP2(true)
+	server.Protocols = protocols
+}
+
+// LimitConnRequests makes server close HTTP/1 connections once they have
+// served n requests, by answering the last one with "Connection: close", so
+// a client cannot keep one connection busy forever and load spreads again
+// after a deploy. It wraps the Handler and ConnContext of server, so it
+// must be called after they are set.
+func LimitConnRequests(server *http.Server, n int) {
+	connContext := server.ConnContext
+	server.ConnContext = func(ctx context.Context, c net.Conn) context.Context {
+		if connContext != nil {
+			ctx = connContext(ctx, c)
+		}
+		return context.WithValue(ctx, connRequestsKey{}, new(atomic.Int64))
+	}
+	handler := server.Handler
+	if handler == nil {
+		handler = http.DefaultServeMux
+	}
+	server.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if count, ok := r.Context().Value(connRequestsKey{}).(*atomic.Int64); ok && r.ProtoMajor == 1 && count.Add(1) >= int64(n) {
+			w.Header().Set("Connection", "close")
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// connRequestsKey is the context key of the number of requests served on
+// a connection, see LimitConnRequests.
+type connRequestsKey struct{}
+
+// ListenUnix listens on the unix domain socket at path and applies perm to
+// the socket file when perm is non-zero. A stale socket file left behind by a
+// crashed process is removed first; a socket that still accepts connections
+// is reported as in use. The file is unlinked when the listener is closed.
+func ListenUnix(path string, perm os.FileMode) (net.Listener, error) {
+	if info, err := os.Lstat(path); err == nil {
+		if info.Mode().Type() != fs.ModeSocket {
+			return nil, fmt.Errorf("httpx: %s exists and is not a socket", path)
+		}
+		if conn, err := net.Dial("unix", path); err == nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("httpx: unix socket %s is in use", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if perm != 0 {
+		if err := os.Chmod(path, perm); err != nil {
+			_ = ln.Close()
+			return nil, err
+		}
+	}
+	return ln, nil
+}
+
+// LimitListener returns a listener that keeps at most n accepted connections
+// open at once: Accept waits for one of them to be closed before accepting
+// another connection, which stays queued in the kernel's backlog meanwhile.
+func LimitListener(ln net.Listener, n int) net.Listener {
+	return &limitListener{
+		Listener: ln,
+		sem:      make(chan struct{}, n),
+		done:     make(chan struct{}),
+	}
+}
+
+type limitListener struct {
+	net.Listener
+	sem       chan struct{}
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+func (l *limitListener) Accept() (net.Conn, error) {
+	select {
+	case l.sem <- struct{}{}:
+	case <-l.done:
+		return nil, net.ErrClosed
+	}
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		<-l.sem
+		return nil, err
+	}
+	return &limitConn{Conn: conn, release: func() { <-l.sem }}, nil
+}
+
+func (l *limitListener) Close() error {
+	err := l.Listener.Close()
+	l.closeOnce.Do(func() { close(l.done) })
+	return err
+}
+
+type limitConn struct {
+	net.Conn
+	releaseOnce sync.Once
+	release     func()
+}
+
+// NetConn returns the wrapped connection.
+func (c *limitConn) NetConn() net.Conn {
+	return c.Conn
+}
+
+func (c *limitConn) Close() error {
+	err := c.Conn.Close()
+	c.releaseOnce.Do(c.release)
+	return err
+}
+
+// RemoveUnixSocket removes the socket file at path, ignoring a missing file.
+func RemoveUnixSocket(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// RequestTimeout returns middleware that gives the context.Context of each
+// request a deadline d after it starts, see Context.SetContext. Handlers and
+// the calls they make observe it through ctx.Context(); the response itself
+// is not interrupted. Adapters install it for their WithRequestTimeout option.
+// Routes set with WithRouteTimeout use their own timeout instead.
+func RequestTimeout(d time.Duration) Middleware {
+	return func(ctx Context) error {
+		if route, ok := routeKey.Get(ctx); ok && route != nil && route.timeout > 0 {
+			// The route's own timeout is already applied.
+			return ctx.Next()
+		}
+		if limitsInUse.Load() {
+			if l := requestLimitsOf(ctx); !l.route && l.base == nil {
+				l.base = ctx.Context()
+			}
+		}
+		c, cancel := context.WithTimeout(ctx.Context(), d)
+		defer cancel()
+		ctx.SetContext(c)
+		return ctx.Next()
+	}
+}
+
 // ListenAndAutoShutdown starts an HTTP server and automatically handles graceful shutdown.
 // It listens for context cancellation to trigger shutdown with the specified timeout.
 // Returns any error from server startup or shutdown, prioritizing startup errors.
@@ -0,0 +1,142 @@
+package httpx
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func shortTempDir(t *testing.T) string {
+	t.Helper()
+	// Unix socket paths are limited to roughly 100 bytes, which t.TempDir
+	// can exceed on some platforms.
+	dir, err := os.MkdirTemp("", "httpx")
+	if err != nil {
+		t.Fatalf("MkdirTemp() error = %v", err)
+	}
+	t.Cleanup(func() {
+		_ = os.RemoveAll(dir)
+	})
+	return dir
+}
+
+func TestListenUnix(t *testing.T) {
+	path := filepath.Join(shortTempDir(t), "app.sock")
+
+	stale, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("listen stale socket: %v", err)
+	}
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	_ = stale.Close()
+
+	ln, err := ListenUnix(path, 0o660)
+	if err != nil {
+		t.Fatalf("ListenUnix() with stale socket error = %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	if got := info.Mode().Perm(); got != 0o660 {
+		t.Fatalf("socket perm = %v, want %v", got, os.FileMode(0o660))
+	}
+
+	if _, err := ListenUnix(path, 0); err == nil {
+		t.Fatalf("ListenUnix() on a live socket should fail")
+	}
+
+	_ = ln.Close()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("socket file should be removed on close, stat err = %v", err)
+	}
+}
+
+func TestListenUnixRefusesRegularFile(t *testing.T) {
+	path := filepath.Join(shortTempDir(t), "app.sock")
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if _, err := ListenUnix(path, 0); err == nil {
+		t.Fatalf("ListenUnix() should refuse to remove a regular file")
+	}
+}
+
+func TestLimitListener(t *testing.T) {
+	inner, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	ln := LimitListener(inner, 1)
+	defer func() {
+		_ = ln.Close()
+	}()
+
+	accepted := make(chan net.Conn, 2)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				close(accepted)
+				return
+			}
+			accepted <- conn
+		}
+	}()
+	for range 2 {
+		conn, err := net.Dial("tcp", inner.Addr().String())
+		if err != nil {
+			t.Fatalf("Dial() error = %v", err)
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+	}
+
+	first := <-accepted
+	select {
+	case <-accepted:
+		t.Fatal("second connection accepted while the first is open")
+	case <-time.After(50 * time.Millisecond):
+	}
+	_ = first.Close()
+	select {
+	case conn := <-accepted:
+		_ = conn.Close()
+	case <-time.After(time.Second):
+		t.Fatal("second connection not accepted after the first was closed")
+	}
+
+	_ = ln.Close()
+	if _, ok := <-accepted; ok {
+		t.Fatal("Accept() succeeded after Close")
+	}
+}
+
+func TestLimitConnRequests(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, r.RemoteAddr)
+	}))
+	LimitConnRequests(srv.Config, 2)
+	srv.Start()
+	defer srv.Close()
+
+	var addrs []string
+	for range 3 {
+		resp, err := srv.Client().Get(srv.URL)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		addrs = append(addrs, string(body))
+	}
+	if addrs[0] != addrs[1] || addrs[1] == addrs[2] {
+		t.Fatalf("client addresses = %v, want the connection closed after 2 requests", addrs)
+	}
+}
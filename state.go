@@ -0,0 +1,170 @@
+package httpx
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// GetAs returns the value stored under key if it is present and of type T.
+func GetAs[T any](ctx StateStore, key string) (T, bool) {
+	val, ok := ctx.Get(key)
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	v, ok := val.(T)
+	return v, ok
+}
+
+// MustGet returns the value stored under key, panicking when it is missing
+// or not of type T. It suits values that earlier middleware always sets.
+func MustGet[T any](ctx StateStore, key string) T {
+	val, ok := ctx.Get(key)
+	if !ok {
+		panic(fmt.Sprintf("httpx: state key %q not set", key))
+	}
+	v, ok := val.(T)
+	if !ok {
+		var zero T
+		panic(fmt.Sprintf("httpx: state key %q holds %T, not %T", key, val, zero))
+	}
+	return v
+}
+
+// Key is a typed StateStore key. Keys created by NewKey never collide, even
+// when two packages choose the same name, so middleware can keep its state
+// private by not exporting its key.
+//
+//	var userKey = httpx.NewKey[*User]("user")
+//
+//	httpx.SetTyped(ctx, userKey, user)
+//	user, ok := userKey.Get(ctx)
+type Key[T any] struct {
+	name string
+}
+
+var keySeq atomic.Uint64
+
+// NewKey returns a new key; name is only used to make the stored key
+// readable.
+func NewKey[T any](name string) Key[T] {
+	return Key[T]{name: name + "#" + strconv.FormatUint(keySeq.Add(1), 10)}
+}
+
+// String returns the StateStore key the values are stored under.
+func (k Key[T]) String() string {
+	return k.name
+}
+
+// Value returns the value stored under k through a context.Context obtained
+// from Context.Context, see StateKey.
+func (k Key[T]) Value(ctx context.Context) (T, bool) {
+	v, ok := ctx.Value(StateKey(k.name)).(T)
+	return v, ok
+}
+
+// Get returns the value stored under k.
+func (k Key[T]) Get(ctx StateStore) (T, bool) {
+	return GetAs[T](ctx, k.name)
+}
+
+// MustGet returns the value stored under k, panicking when it is missing.
+func (k Key[T]) MustGet(ctx StateStore) T {
+	return MustGet[T](ctx, k.name)
+}
+
+// SetTyped stores val under key.
+func SetTyped[T any](ctx StateStore, key Key[T], val T) {
+	ctx.Set(key.name, val)
+}
+
+// StateKey is the context.Context key under which a StateStore value is
+// visible: for a request context obtained from Context.Context, and any
+// context derived from it,
+//
+//	ctx.Context().Value(httpx.StateKey("user"))
+//
+// returns the value of ctx.Get("user"), or nil when it is not set. Lookups
+// read the store at call time, so values set later are visible too, as long
+// as the request is in flight.
+type StateKey string
+
+type stateStoreKey struct{}
+
+type stateContext struct {
+	context.Context
+	store StateStore
+}
+
+func (c stateContext) Value(key any) any {
+	switch k := key.(type) {
+	case StateKey:
+		if val, ok := c.store.Get(string(k)); ok {
+			return val
+		}
+	case stateStoreKey:
+		return c.store
+	}
+	return c.Context.Value(key)
+}
+
+// WithState returns a context whose Value resolves StateKey keys from store.
+// A parent that already resolves a store, unless it is a closed
+// RequestState, is returned unchanged, so contexts passed back through
+// SetContext are not wrapped again. Adapters apply it in Context.Context.
+func WithState(parent context.Context, store StateStore) context.Context {
+	if s, ok := parent.Value(stateStoreKey{}).(StateStore); ok {
+		if rs, ok := s.(*RequestState); !ok || !rs.closed() {
+			return parent
+		}
+	}
+	return stateContext{Context: parent, store: store}
+}
+
+// RequestState is the StateStore adapters pass to WithState for a pooled
+// Context. It forwards to the Context until Close, which adapters call when
+// they release the Context, and resolves nothing after that, so that a
+// context.Context kept from a request never reads the state of a later
+// request reusing the pooled Context.
+type RequestState struct {
+	mu    sync.RWMutex
+	store StateStore
+}
+
+// NewRequestState returns a RequestState forwarding to store.
+func NewRequestState(store StateStore) *RequestState {
+	return &RequestState{store: store}
+}
+
+func (s *RequestState) Set(key string, val any) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.store != nil {
+		s.store.Set(key, val)
+	}
+}
+
+func (s *RequestState) Get(key string) (any, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.store == nil {
+		return nil, false
+	}
+	return s.store.Get(key)
+}
+
+// Close detaches s from its store, waiting for the lookups in progress.
+func (s *RequestState) Close() {
+	s.mu.Lock()
+	s.store = nil
+	s.mu.Unlock()
+}
+
+func (s *RequestState) closed() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.store == nil
+}
@@ -0,0 +1,89 @@
+package httpx
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTypedState(t *testing.T) {
+	ctx := newHTTPContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil), nil)
+
+	ctx.Set("count", 3)
+	if got, ok := GetAs[int](ctx, "count"); !ok || got != 3 {
+		t.Fatalf("GetAs[int](count) = %v, %v", got, ok)
+	}
+	if _, ok := GetAs[string](ctx, "count"); ok {
+		t.Fatalf("GetAs[string](count) should report a type mismatch")
+	}
+	if _, ok := GetAs[int](ctx, "missing"); ok {
+		t.Fatalf("GetAs[int](missing) should not be found")
+	}
+
+	first, second := NewKey[string]("user"), NewKey[int]("user")
+	if first.String() == second.String() {
+		t.Fatalf("keys with the same name collide: %q", first)
+	}
+	SetTyped(ctx, first, "gopher")
+	SetTyped(ctx, second, 7)
+	if got := first.MustGet(ctx); got != "gopher" {
+		t.Fatalf("first.MustGet = %q", got)
+	}
+	if got, ok := second.Get(ctx); !ok || got != 7 {
+		t.Fatalf("second.Get = %v, %v", got, ok)
+	}
+
+	assertPanics(t, func() { MustGet[int](ctx, "missing") })
+	assertPanics(t, func() { MustGet[bool](ctx, "count") })
+}
+
+func TestWithState(t *testing.T) {
+	ctx := newHTTPContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil), nil)
+	ctx.Set("user", "gopher")
+
+	std := ctx.Context()
+	if got := std.Value(StateKey("user")); got != "gopher" {
+		t.Fatalf("Value(StateKey(user)) = %v", got)
+	}
+	if got := std.Value(StateKey("missing")); got != nil {
+		t.Fatalf("Value(StateKey(missing)) = %v, want nil", got)
+	}
+	if WithState(std, ctx) != std {
+		t.Fatalf("WithState wrapped a context that already resolves state")
+	}
+	ctx.SetContext(context.WithValue(std, StateKey("other"), 1))
+	if got := ctx.Context().Value(StateKey("other")); got != 1 {
+		t.Fatalf("Value(StateKey(other)) = %v, want the parent value", got)
+	}
+}
+
+func TestRequestState(t *testing.T) {
+	ctx := newHTTPContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil), nil)
+	ctx.Set("user", "gopher")
+	state := NewRequestState(ctx)
+	kept := WithState(context.Background(), state)
+	if got := kept.Value(StateKey("user")); got != "gopher" {
+		t.Fatalf("Value(StateKey(user)) = %v", got)
+	}
+
+	state.Close()
+	ctx.Set("user", "other")
+	if got := kept.Value(StateKey("user")); got != nil {
+		t.Fatalf("closed state resolves user = %v", got)
+	}
+	// A context carrying a closed state is wrapped again, as when it was
+	// passed back through SetContext by a handler that has returned.
+	if got := WithState(kept, ctx).Value(StateKey("user")); got != "other" {
+		t.Fatalf("Value(StateKey(user)) over a closed state = %v", got)
+	}
+}
+
+func assertPanics(t *testing.T, fn func()) {
+	t.Helper()
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected panic")
+		}
+	}()
+	fn()
+}
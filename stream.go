@@ -0,0 +1,87 @@
+package httpx
+
+import (
+	"fmt"
+	"io"
+)
+
+// StreamKnownSize streams r as the response body with status code, such as
+// a PDF or an attachment read from object storage. When the size of the
+// body is known, the response has a Content-Length, which lets clients show
+// download progress; otherwise it is sent chunked. A negative size asks
+// StreamKnownSize to find the size itself, from the Len method of readers
+// such as *bytes.Reader, or by seeking an io.Seeker such as *os.File to its
+// end and back.
+//
+// The adapters disagree on what DataFromReader does with a size that does
+// not match the body; StreamKnownSize sends at most size bytes, and a
+// reader ending before that fails the body with io.ErrUnexpectedEOF, which
+// aborts the connection on every adapter rather than leaving the client
+// waiting. r is closed once sent when it implements io.Closer. An empty
+// contentType is sent as application/octet-stream.
+func StreamKnownSize(ctx Context, code int, contentType string, size int64, r io.Reader) error {
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	if size < 0 {
+		size = readerSize(r)
+	}
+	if size < 0 {
+		return ctx.DataFromReader(code, contentType, r, -1)
+	}
+	return ctx.DataFromReader(code, contentType, &sizedReader{r: r, n: size}, int(size))
+}
+
+// readerSize returns the number of bytes left in r, or -1 when it cannot
+// tell.
+func readerSize(r io.Reader) int64 {
+	switch r := r.(type) {
+	case interface{ Len() int }:
+		return int64(r.Len())
+	case io.Seeker:
+		cur, err := r.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+		end, err := r.Seek(0, io.SeekEnd)
+		if err != nil {
+			return -1
+		}
+		if _, err := r.Seek(cur, io.SeekStart); err != nil {
+			return -1
+		}
+		return end - cur
+	}
+	return -1
+}
+
+// sizedReader reads exactly n bytes from r.
+type sizedReader struct {
+	r io.Reader
+	n int64
+}
+
+func (s *sizedReader) Read(p []byte) (int, error) {
+	if s.n <= 0 {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > s.n {
+		p = p[:s.n]
+	}
+	n, err := s.r.Read(p)
+	s.n -= int64(n)
+	if err == io.EOF && s.n > 0 {
+		return n, fmt.Errorf("httpx: body ended %d bytes short: %w", s.n, io.ErrUnexpectedEOF)
+	}
+	if err == io.EOF {
+		err = nil
+	}
+	return n, err
+}
+
+func (s *sizedReader) Close() error {
+	if c, ok := s.r.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
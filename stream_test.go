@@ -0,0 +1,58 @@
+package httpx
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReaderSize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.pdf")
+	if err := os.WriteFile(path, []byte("0123456789"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	if _, err := f.Seek(4, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	if got := readerSize(f); got != 6 {
+		t.Fatalf("readerSize(file at 4) = %d, want 6", got)
+	}
+	if rest, _ := io.ReadAll(f); string(rest) != "456789" {
+		t.Fatalf("file read %q after readerSize", rest)
+	}
+	if got := readerSize(bytes.NewBufferString("abc")); got != 3 {
+		t.Fatalf("readerSize(buffer) = %d", got)
+	}
+	if got := readerSize(io.MultiReader(strings.NewReader("abc"))); got != -1 {
+		t.Fatalf("readerSize(multi reader) = %d", got)
+	}
+}
+
+func TestStreamKnownSize(t *testing.T) {
+	rec := httptest.NewRecorder()
+	ctx := NewContext(rec, httptest.NewRequest(http.MethodGet, "/report", nil))
+	if err := StreamKnownSize(ctx, http.StatusOK, "", 4, strings.NewReader("0123456789")); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Body.String() != "0123" || rec.Header().Get("Content-Length") != "4" || rec.Header().Get("Content-Type") != "application/octet-stream" {
+		t.Fatalf("response = %q, headers %v", rec.Body.String(), rec.Header())
+	}
+
+	rec = httptest.NewRecorder()
+	ctx = NewContext(rec, httptest.NewRequest(http.MethodGet, "/report", nil))
+	err := StreamKnownSize(ctx, http.StatusOK, "application/pdf", 20, strings.NewReader("0123456789"))
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("short body: error %v, want io.ErrUnexpectedEOF", err)
+	}
+}
@@ -0,0 +1,277 @@
+// Package streamx streams office documents from httpx handlers, writing them
+// as rows are produced instead of building them in memory or in temporary
+// files.
+//
+//	ctx.SetHeader("Content-Disposition", `attachment; filename="orders.xlsx"`)
+//	return streamx.XLSX(ctx, []streamx.Sheet{{
+//		Name: "Orders",
+//		Rows: func(yield func([]any) bool) {
+//			_ = yield([]any{"ID", "Placed", "Total"})
+//			for _, o := range orders {
+//				if !yield([]any{o.ID, o.PlacedAt, o.Total}) {
+//					return
+//				}
+//			}
+//		},
+//	}})
+package streamx
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-sphere/httpx"
+)
+
+// XLSXContentType is the Content-Type of the workbooks written by XLSX.
+const XLSXContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+// Sheet is a worksheet of the workbook written by XLSX.
+type Sheet struct {
+	// Name is the name of the tab, "Sheet1", "Sheet2" and so on when
+	// empty. Names are at most 31 characters, none of []:*?/\, and unique
+	// in a workbook regardless of case.
+	Name string
+	// Rows yields the rows of the sheet, from the first. A cell may be a
+	// string, an integer, a float, a bool, a time.Time, which is written as
+	// a date and time, or nil, which leaves it empty. Other values are
+	// written as strings with fmt.Sprint.
+	Rows iter.Seq[[]any]
+}
+
+// XLSX responds with status 200 and a workbook of sheets, streamed as the
+// rows of each sheet are yielded. The workbook is written through
+// DataFromReader, so the Rows of sheets must not use ctx: fiber and hertz
+// send the body after the handler returns. Invalid sheet names are
+// returned before anything is written; a failure later, such as a client
+// going away, ends the body early, so the client sees a truncated
+// download.
+func XLSX(ctx httpx.Context, sheets []Sheet) error {
+	names, err := sheetNames(sheets)
+	if err != nil {
+		return err
+	}
+	pr, pw := io.Pipe()
+	go func() {
+		_ = pw.CloseWithError(writeWorkbook(pw, sheets, names))
+	}()
+	return ctx.DataFromReader(http.StatusOK, XLSXContentType, pr, -1)
+}
+
+// sheetNames returns the names of sheets, checking them against the rules
+// of Excel.
+func sheetNames(sheets []Sheet) ([]string, error) {
+	if len(sheets) == 0 {
+		return nil, errors.New("streamx: workbook without sheets")
+	}
+	names := make([]string, len(sheets))
+	seen := make(map[string]bool, len(sheets))
+	for i, s := range sheets {
+		name := s.Name
+		if name == "" {
+			name = "Sheet" + strconv.Itoa(i+1)
+		}
+		if len([]rune(name)) > 31 || strings.ContainsAny(name, `[]:*?/\`) {
+			return nil, fmt.Errorf("streamx: invalid sheet name %q", name)
+		}
+		if seen[strings.ToLower(name)] {
+			return nil, fmt.Errorf("streamx: duplicate sheet name %q", name)
+		}
+		seen[strings.ToLower(name)] = true
+		names[i] = name
+	}
+	return names, nil
+}
+
+const (
+	xmlHeader   = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n"
+	mainNS      = "http://schemas.openxmlformats.org/spreadsheetml/2006/main"
+	relsNS      = "http://schemas.openxmlformats.org/package/2006/relationships"
+	officeDocNS = "http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+)
+
+// stylesXML holds the default cell format and, at index 1, the date and
+// time format of time.Time cells.
+const stylesXML = xmlHeader + `<styleSheet xmlns="` + mainNS + `">` +
+	`<fonts count="1"><font><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="22" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/></cellXfs>` +
+	`</styleSheet>`
+
+func writeWorkbook(w io.Writer, sheets []Sheet, names []string) error {
+	zw := zip.NewWriter(w)
+	var types, workbook, rels strings.Builder
+	types.WriteString(xmlHeader + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	workbook.WriteString(xmlHeader + `<workbook xmlns="` + mainNS + `" xmlns:r="` + officeDocNS + `"><sheets>`)
+	rels.WriteString(xmlHeader + `<Relationships xmlns="` + relsNS + `">`)
+	for i, name := range names {
+		n := strconv.Itoa(i + 1)
+		types.WriteString(`<Override PartName="/xl/worksheets/sheet` + n + `.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`)
+		workbook.WriteString(`<sheet name="` + escapeXML(name) + `" sheetId="` + n + `" r:id="rId` + n + `"/>`)
+		rels.WriteString(`<Relationship Id="rId` + n + `" Type="` + officeDocNS + `/worksheet" Target="worksheets/sheet` + n + `.xml"/>`)
+	}
+	types.WriteString(`</Types>`)
+	workbook.WriteString(`</sheets></workbook>`)
+	rels.WriteString(`<Relationship Id="rId` + strconv.Itoa(len(names)+1) + `" Type="` + officeDocNS + `/styles" Target="styles.xml"/></Relationships>`)
+
+	parts := []struct{ name, body string }{
+		{"[Content_Types].xml", types.String()},
+		{"_rels/.rels", xmlHeader + `<Relationships xmlns="` + relsNS + `"><Relationship Id="rId1" Type="` + officeDocNS + `/officeDocument" Target="xl/workbook.xml"/></Relationships>`},
+		{"xl/workbook.xml", workbook.String()},
+		{"xl/_rels/workbook.xml.rels", rels.String()},
+		{"xl/styles.xml", stylesXML},
+	}
+	for _, p := range parts {
+		f, err := zw.Create(p.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, p.body); err != nil {
+			return err
+		}
+	}
+	for i, s := range sheets {
+		f, err := zw.Create("xl/worksheets/sheet" + strconv.Itoa(i+1) + ".xml")
+		if err != nil {
+			return err
+		}
+		if err := writeSheet(f, s.Rows); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func writeSheet(w io.Writer, rows iter.Seq[[]any]) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString(xmlHeader + `<worksheet xmlns="` + mainNS + `"><sheetData>`)
+	if rows != nil {
+		r := 0
+		for row := range rows {
+			r++
+			ref := strconv.Itoa(r)
+			bw.WriteString(`<row r="` + ref + `">`)
+			for c, v := range row {
+				writeCell(bw, columnName(c)+ref, v)
+			}
+			// bufio keeps the first write error, which ends the sheet once
+			// the client is gone.
+			if _, err := bw.WriteString(`</row>`); err != nil {
+				return err
+			}
+		}
+	}
+	bw.WriteString(`</sheetData></worksheet>`)
+	return bw.Flush()
+}
+
+// excelEpoch is day 0 of the serial dates of Excel, correct from March 1900.
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+func writeCell(w *bufio.Writer, ref string, v any) {
+	var typ, style, value string
+	switch v := v.(type) {
+	case nil:
+		return
+	case string:
+		writeStringCell(w, ref, v)
+		return
+	case bool:
+		typ, value = "b", "0"
+		if v {
+			value = "1"
+		}
+	case int:
+		value = strconv.FormatInt(int64(v), 10)
+	case int8:
+		value = strconv.FormatInt(int64(v), 10)
+	case int16:
+		value = strconv.FormatInt(int64(v), 10)
+	case int32:
+		value = strconv.FormatInt(int64(v), 10)
+	case int64:
+		value = strconv.FormatInt(v, 10)
+	case uint:
+		value = strconv.FormatUint(uint64(v), 10)
+	case uint8:
+		value = strconv.FormatUint(uint64(v), 10)
+	case uint16:
+		value = strconv.FormatUint(uint64(v), 10)
+	case uint32:
+		value = strconv.FormatUint(uint64(v), 10)
+	case uint64:
+		value = strconv.FormatUint(v, 10)
+	case float32:
+		value = formatFloat(float64(v), 32)
+	case float64:
+		value = formatFloat(v, 64)
+	case time.Time:
+		// Excel dates have no time zone: the wall clock time is written.
+		wall := time.Date(v.Year(), v.Month(), v.Day(), v.Hour(), v.Minute(), v.Second(), v.Nanosecond(), time.UTC)
+		style, value = "1", formatFloat(float64(wall.Sub(excelEpoch))/float64(24*time.Hour), 64)
+	default:
+		writeStringCell(w, ref, fmt.Sprint(v))
+		return
+	}
+	if value == "" {
+		// NaN and infinities have no number cell.
+		writeStringCell(w, ref, fmt.Sprint(v))
+		return
+	}
+	w.WriteString(`<c r="` + ref + `"`)
+	if typ != "" {
+		w.WriteString(` t="` + typ + `"`)
+	}
+	if style != "" {
+		w.WriteString(` s="` + style + `"`)
+	}
+	w.WriteString(`><v>` + value + `</v></c>`)
+}
+
+func formatFloat(f float64, bits int) string {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return ""
+	}
+	return strconv.FormatFloat(f, 'g', -1, bits)
+}
+
+func writeStringCell(w *bufio.Writer, ref, s string) {
+	w.WriteString(`<c r="` + ref + `" t="inlineStr"><is><t`)
+	if strings.TrimSpace(s) != s {
+		w.WriteString(` xml:space="preserve"`)
+	}
+	w.WriteString(`>` + escapeXML(s) + `</t></is></c>`)
+}
+
+func escapeXML(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// columnName returns the letters of the zero-based column i: A to Z, then
+// AA and so on.
+func columnName(i int) string {
+	var b []byte
+	for i++; i > 0; i = (i - 1) / 26 {
+		b = append([]byte{byte('A' + (i-1)%26)}, b...)
+	}
+	return string(b)
+}
@@ -0,0 +1,118 @@
+package streamx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-sphere/httpx"
+)
+
+type sheetXML struct {
+	Rows []struct {
+		R     string `xml:"r,attr"`
+		Cells []struct {
+			R      string `xml:"r,attr"`
+			T      string `xml:"t,attr"`
+			S      string `xml:"s,attr"`
+			V      string `xml:"v"`
+			Inline string `xml:"is>t"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+func TestXLSX(t *testing.T) {
+	rec := httptest.NewRecorder()
+	ctx := httpx.NewContext(rec, httptest.NewRequest(http.MethodGet, "/report", nil))
+	placed := time.Date(2024, 3, 1, 18, 0, 0, 0, time.FixedZone("CET", 3600))
+	err := XLSX(ctx, []Sheet{
+		{Name: "Orders & Totals", Rows: func(yield func([]any) bool) {
+			_ = yield([]any{"ID", "Placed", "Total", "Paid", "Note"}) &&
+				yield([]any{int64(7), placed, 12.5, true, " <fragile> "}) &&
+				yield([]any{uint8(8), nil, math.NaN(), false, struct{ A int }{1}})
+		}},
+		{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := rec.Header().Get("Content-Type"); got != XLSXContentType {
+		t.Fatalf("Content-Type = %q", got)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(rec.Body.Bytes()), int64(rec.Body.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(rc)
+		_ = rc.Close()
+		files[f.Name] = string(body)
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml", "xl/worksheets/sheet1.xml", "xl/worksheets/sheet2.xml"} {
+		body, ok := files[name]
+		if !ok {
+			t.Fatalf("workbook lacks %s", name)
+		}
+		if err := xml.Unmarshal([]byte(body), new(struct{})); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+	}
+	if wb := files["xl/workbook.xml"]; !strings.Contains(wb, `name="Orders &amp; Totals"`) || !strings.Contains(wb, `name="Sheet2"`) {
+		t.Fatalf("workbook.xml = %s", wb)
+	}
+
+	var sheet sheetXML
+	if err := xml.Unmarshal([]byte(files["xl/worksheets/sheet1.xml"]), &sheet); err != nil {
+		t.Fatal(err)
+	}
+	if len(sheet.Rows) != 3 {
+		t.Fatalf("sheet has %d rows", len(sheet.Rows))
+	}
+	row := sheet.Rows[1].Cells
+	got := []string{row[0].R + "=" + row[0].V, row[1].S + ":" + row[1].V, row[2].V, row[3].T + ":" + row[3].V, row[4].T + ":" + row[4].Inline}
+	want := []string{"A2=7", "1:45352.75", "12.5", "b:1", "inlineStr: <fragile> "}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("row 2 = %q, want %q", got, want)
+		}
+	}
+	row = sheet.Rows[2].Cells
+	if len(row) != 4 || row[1].R != "C3" || row[1].Inline != "NaN" || row[3].Inline != "{1}" {
+		t.Fatalf("row 3 = %+v", row)
+	}
+}
+
+func TestXLSXSheetNames(t *testing.T) {
+	for _, sheets := range [][]Sheet{
+		nil,
+		{{Name: "a/b"}},
+		{{Name: strings.Repeat("x", 32)}},
+		{{Name: "Data"}, {Name: "DATA"}},
+	} {
+		rec := httptest.NewRecorder()
+		ctx := httpx.NewContext(rec, httptest.NewRequest(http.MethodGet, "/report", nil))
+		if err := XLSX(ctx, sheets); err == nil || rec.Body.Len() != 0 {
+			t.Fatalf("%+v: error %v, %d bytes written", sheets, err, rec.Body.Len())
+		}
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	for i, want := range map[int]string{0: "A", 25: "Z", 26: "AA", 51: "AZ", 52: "BA", 701: "ZZ", 702: "AAA"} {
+		if got := columnName(i); got != want {
+			t.Errorf("columnName(%d) = %q, want %q", i, got, want)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package httpx
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+var (
+	// ErrUploadTooLarge reports an upload larger than UploadPolicy.MaxSize.
+	ErrUploadTooLarge = errors.New("httpx: upload too large")
+	// ErrUploadTypeNotAllowed reports an upload whose extension or sniffed
+	// content type is not allowed by an UploadPolicy.
+	ErrUploadTypeNotAllowed = errors.New("httpx: upload type not allowed")
+	// ErrUploadMissing reports a multipart request without a file in the
+	// field passed to StreamUpload.
+	ErrUploadMissing = errors.New("httpx: upload missing")
+)
+
+// UploadPolicy constrains uploaded files. Zero fields impose no constraint.
+type UploadPolicy struct {
+	// MaxSize is the maximum file size in bytes.
+	MaxSize int64
+	// AllowedMIME lists the allowed content types, such as "image/png" or
+	// "image/*". The type is sniffed from the file content with
+	// http.DetectContentType; the client's declared type is ignored.
+	AllowedMIME []string
+	// AllowedExt lists the allowed file name extensions, such as ".png",
+	// compared case-insensitively.
+	AllowedExt []string
+}
+
+// Validate checks fh against the policy. Violations are returned as errors
+// with status 413 wrapping ErrUploadTooLarge, or status 415 wrapping
+// ErrUploadTypeNotAllowed.
+func (p UploadPolicy) Validate(fh *multipart.FileHeader) error {
+	if p.MaxSize > 0 && fh.Size > p.MaxSize {
+		return WithStatus(http.StatusRequestEntityTooLarge,
+			fmt.Errorf("%w: %q is %d bytes, limit %d", ErrUploadTooLarge, fh.Filename, fh.Size, p.MaxSize))
+	}
+	if len(p.AllowedExt) > 0 {
+		ext := filepath.Ext(fh.Filename)
+		if !slices.ContainsFunc(p.AllowedExt, func(allowed string) bool { return strings.EqualFold(allowed, ext) }) {
+			return WithStatus(http.StatusUnsupportedMediaType,
+				fmt.Errorf("%w: extension %q of %q", ErrUploadTypeNotAllowed, ext, fh.Filename))
+		}
+	}
+	if len(p.AllowedMIME) > 0 {
+		contentType, err := DetectUploadType(fh)
+		if err != nil {
+			return err
+		}
+		if !slices.ContainsFunc(p.AllowedMIME, func(allowed string) bool { return matchMIME(allowed, contentType) }) {
+			return WithStatus(http.StatusUnsupportedMediaType,
+				fmt.Errorf("%w: content type %q of %q", ErrUploadTypeNotAllowed, contentType, fh.Filename))
+		}
+	}
+	return nil
+}
+
+// DetectUploadType sniffs the content type of an uploaded file from its
+// first 512 bytes with http.DetectContentType, without parameters.
+func DetectUploadType(fh *multipart.FileHeader) (string, error) {
+	f, err := fh.Open()
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	buf := make([]byte, 512)
+	n, err := io.ReadFull(f, buf)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	mediaType, _, err := mime.ParseMediaType(http.DetectContentType(buf[:n]))
+	if err != nil {
+		return "", err
+	}
+	return mediaType, nil
+}
+
+// matchMIME reports whether contentType matches pattern, which is a media
+// type or a "type/*" wildcard.
+func matchMIME(pattern, contentType string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "/*"); ok {
+		major, _, _ := strings.Cut(contentType, "/")
+		return strings.EqualFold(prefix, major)
+	}
+	return strings.EqualFold(pattern, contentType)
+}
+
+// SaveUploadedFile writes an uploaded file to dst, creating its parent
+// directories. dst should not be derived from fh.Filename, which is chosen by
+// the client; if it is, use filepath.Base to strip directory components.
+func SaveUploadedFile(fh *multipart.FileHeader, dst string) error {
+	src, err := fh.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0o750); err != nil {
+		return err
+	}
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, src); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// UploadInfo describes a file streamed by StreamUpload.
+type UploadInfo struct {
+	// Filename is the file name sent by the client, without directory
+	// components.
+	Filename string
+	// ContentType is the content type declared by the client.
+	ContentType string
+	// Size is the number of bytes written to the sink.
+	Size int64
+}
+
+// StreamUpload copies the file in field of a multipart/form-data request to
+// sink as it is received, without buffering it in memory or temporary
+// files, so uploads can be piped to object storage writers. The parts ahead
+// of the file are skipped, and those after it are left unread.
+//
+// On contexts implementing HTTPInterop the request body is read as it
+// arrives; fiber and hertz buffer it before the handler runs. Requests that
+// are not multipart fail with status 400, and requests without the file
+// with status 400 wrapping ErrUploadMissing. Wrap sink, or use BodyLimit,
+// to bound the size.
+func StreamUpload(ctx Context, field string, sink io.Writer) (UploadInfo, error) {
+	mediaType, params, err := mime.ParseMediaType(ctx.Header("Content-Type"))
+	if err != nil || mediaType != "multipart/form-data" || params["boundary"] == "" {
+		return UploadInfo{}, BadRequestError(http.ErrNotMultipart)
+	}
+	var body io.Reader
+	if hi, ok := AsHTTPInterop(ctx); ok {
+		// BodyReader of net/http based adapters reads the whole body first.
+		body = hi.HTTPRequest().Body
+	} else {
+		rc := ctx.BodyReader()
+		defer rc.Close()
+		body = rc
+	}
+	if body == nil {
+		body = http.NoBody
+	}
+	reader := multipart.NewReader(body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return UploadInfo{}, BadRequestError(fmt.Errorf("%w: field %q", ErrUploadMissing, field))
+		}
+		if err != nil {
+			return UploadInfo{}, BadRequestError(err)
+		}
+		if part.FormName() != field || part.FileName() == "" {
+			_ = part.Close()
+			continue
+		}
+		info := UploadInfo{Filename: part.FileName(), ContentType: part.Header.Get("Content-Type")}
+		info.Size, err = io.Copy(sink, part)
+		_ = part.Close()
+		return info, err
+	}
+}
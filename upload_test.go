@@ -0,0 +1,22 @@
+package httpx
+
+import "testing"
+
+func TestMatchMIME(t *testing.T) {
+	tests := []struct {
+		pattern     string
+		contentType string
+		want        bool
+	}{
+		{pattern: "image/png", contentType: "image/png", want: true},
+		{pattern: "IMAGE/PNG", contentType: "image/png", want: true},
+		{pattern: "image/*", contentType: "image/jpeg", want: true},
+		{pattern: "image/*", contentType: "text/plain", want: false},
+		{pattern: "image/png", contentType: "image/jpeg", want: false},
+	}
+	for _, tt := range tests {
+		if got := matchMIME(tt.pattern, tt.contentType); got != tt.want {
+			t.Fatalf("matchMIME(%q, %q) = %v, want %v", tt.pattern, tt.contentType, got, tt.want)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package httpx
 
-import "io"
+import (
+	"io"
+	"path"
+)
 
 type readCloser struct {
 	io.Reader
@@ -17,3 +20,16 @@ func (rc readCloser) Close() error {
 func NewReadCloser(r io.Reader, closeFn func() error) io.ReadCloser {
 	return readCloser{r, closeFn}
 }
+
+// JoinPaths joins a group base path and a relative route path, preserving
+// a trailing slash on the relative path.
+func JoinPaths(absolutePath, relativePath string) string {
+	if relativePath == "" {
+		return absolutePath
+	}
+	finalPath := path.Join(absolutePath, relativePath)
+	if relativePath[len(relativePath)-1] == '/' && finalPath[len(finalPath)-1] != '/' {
+		return finalPath + "/"
+	}
+	return finalPath
+}
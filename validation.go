@@ -0,0 +1,211 @@
+package httpx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// FieldError is a rule a request field failed.
+type FieldError struct {
+	// Field is the path of the field in the bound struct, such as
+	// "Address.City", whatever the source it is bound from.
+	Field string `json:"field"`
+	// Rule is the failed validation rule, such as "required" or "min".
+	Rule string `json:"rule"`
+	// Message describes the failure.
+	Message string `json:"message"`
+}
+
+// ValidationError reports the fields of a request that failed validation.
+// It is a StatusError with status 422. The Bind methods of the adapters
+// return it for the errors of their validator and for required fields
+// missing from any source, see ToValidationError, and the default error
+// handlers render it as a JSON body listing the fields:
+//
+//	{"error": "...", "errors": [{"field": "Name", "rule": "required", "message": "..."}]}
+//
+// It differs from openapix.ValidationError, which reports the mismatches
+// between a request and an OpenAPI document by location, with status 400.
+type ValidationError struct {
+	Errors []FieldError
+
+	// err is the error converted by ToValidationError, if any.
+	err error
+}
+
+func (e *ValidationError) Error() string {
+	return "httpx: " + e.GetMessage()
+}
+
+func (e *ValidationError) GetStatus() int32 {
+	return http.StatusUnprocessableEntity
+}
+
+// Unwrap returns the error the ValidationError was converted from, such as
+// a *MissingFieldsError, or nil.
+func (e *ValidationError) Unwrap() error {
+	return e.err
+}
+
+func (e *ValidationError) GetMessage() string {
+	parts := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		parts[i] = fe.Message
+	}
+	return "invalid request: " + strings.Join(parts, "; ")
+}
+
+// MarshalJSON encodes the error as the body written by the default error
+// handlers.
+func (e *ValidationError) MarshalJSON() ([]byte, error) {
+	errs := e.Errors
+	if errs == nil {
+		errs = []FieldError{}
+	}
+	return json.Marshal(struct {
+		Error  string       `json:"error"`
+		Errors []FieldError `json:"errors"`
+	}{e.GetMessage(), errs})
+}
+
+// validatorFieldError is the field error of struct validators such as
+// github.com/go-playground/validator, which gin uses for binding tags.
+type validatorFieldError interface {
+	Namespace() string
+	Field() string
+	Tag() string
+	Param() string
+}
+
+// ToValidationError returns err as a *ValidationError when it reports
+// failed fields, and err unchanged otherwise. It recognizes a
+// *ValidationError in the chain, a *MissingFieldsError, whose fields fail
+// the "required" rule, the error of BindValues for a value its field cannot
+// hold, which fails the "type" rule, and the errors of struct validators whose field
+// errors have Namespace, Field, Tag and Param methods, such as
+// validator.ValidationErrors of github.com/go-playground/validator.
+func ToValidationError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var ve *ValidationError
+	if errors.As(err, &ve) {
+		return ve
+	}
+	var mfe *MissingFieldsError
+	if errors.As(err, &mfe) {
+		errs := make([]FieldError, len(mfe.Fields))
+		for i, name := range mfe.Fields {
+			if i < len(mfe.paths) {
+				name = mfe.paths[i]
+			}
+			errs[i] = FieldError{Field: name, Rule: "required", Message: name + " failed the required rule"}
+		}
+		return &ValidationError{Errors: errs, err: err}
+	}
+	var be *bindError
+	if errors.As(err, &be) {
+		return &ValidationError{Errors: []FieldError{
+			{Field: be.path, Rule: "type", Message: be.path + " failed the type rule"},
+		}, err: err}
+	}
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if errs := validatorFieldErrors(e); len(errs) > 0 {
+			return &ValidationError{Errors: errs, err: err}
+		}
+	}
+	return err
+}
+
+// validatorFieldErrors converts err, a validator field error or a slice of
+// them, such as validator.ValidationErrors.
+func validatorFieldErrors(err error) []FieldError {
+	if fe, ok := err.(validatorFieldError); ok {
+		return []FieldError{newFieldError(fe)}
+	}
+	rv := reflect.ValueOf(err)
+	if rv.Kind() != reflect.Slice {
+		return nil
+	}
+	errs := make([]FieldError, 0, rv.Len())
+	for i := range rv.Len() {
+		fe, ok := rv.Index(i).Interface().(validatorFieldError)
+		if !ok {
+			return nil
+		}
+		errs = append(errs, newFieldError(fe))
+	}
+	return errs
+}
+
+func newFieldError(fe validatorFieldError) FieldError {
+	// The namespace starts with the name of the validated struct.
+	field := fe.Field()
+	if _, path, ok := strings.Cut(fe.Namespace(), "."); ok {
+		field = path
+	}
+	rule := fe.Tag()
+	message := fmt.Sprintf("%s failed the %s rule", field, rule)
+	if param := fe.Param(); param != "" {
+		message = fmt.Sprintf("%s failed the %s=%s rule", field, rule, param)
+	}
+	return FieldError{Field: field, Rule: rule, Message: message}
+}
+
+// ValidateRequired checks the fields of the struct pointed to by dst whose
+// binding tag includes "required", as in `binding:"required"`, descending
+// into nested structs, and returns a *ValidationError listing those left
+// zero, or nil. Pointers, slices and maps are zero when nil; struct fields
+// are only descended into, as with gin's validator. The adapters whose
+// framework has no validator run it after decoding JSON bodies, so that
+// required fields are reported as they are on ginx.
+func ValidateRequired(dst any) error {
+	rv := reflect.ValueOf(dst)
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+	var errs []FieldError
+	validateRequired(rv, "", make(map[uintptr]bool), &errs)
+	if len(errs) == 0 {
+		return nil
+	}
+	return &ValidationError{Errors: errs}
+}
+
+// validateRequired checks the struct rv, whose fields are named from
+// prefix. visited holds the pointers followed, in case of cycles.
+func validateRequired(rv reflect.Value, prefix string, visited map[uintptr]bool, errs *[]FieldError) {
+	rt := rv.Type()
+	for i := range rt.NumField() {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		fv := rv.Field(i)
+		path := prefix + field.Name
+		if isRequired(field) && fv.Kind() != reflect.Struct && fv.IsZero() {
+			*errs = append(*errs, FieldError{Field: path, Rule: "required", Message: path + " failed the required rule"})
+			continue
+		}
+		if fv.Kind() == reflect.Pointer {
+			if fv.IsNil() || visited[fv.Pointer()] {
+				continue
+			}
+			visited[fv.Pointer()] = true
+			fv = fv.Elem()
+		}
+		if fv.Kind() == reflect.Struct && fv.Type() != timeType {
+			validateRequired(fv, path+".", visited, errs)
+		}
+	}
+}
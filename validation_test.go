@@ -0,0 +1,119 @@
+package httpx
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type testFieldError struct {
+	namespace, field, tag, param string
+}
+
+func (e testFieldError) Error() string     { return "invalid " + e.field }
+func (e testFieldError) Namespace() string { return e.namespace }
+func (e testFieldError) Field() string     { return e.field }
+func (e testFieldError) Tag() string       { return e.tag }
+func (e testFieldError) Param() string     { return e.param }
+
+// testValidationErrors mirrors validator.ValidationErrors, a slice of field
+// errors.
+type testValidationErrors []testFieldError
+
+func (e testValidationErrors) Error() string { return "invalid" }
+
+func TestToValidationError(t *testing.T) {
+	errs := testValidationErrors{
+		{namespace: "input.Name", field: "Name", tag: "required"},
+		{namespace: "input.Address.Zip", field: "Zip", tag: "len", param: "5"},
+	}
+	err := ToValidationError(fmt.Errorf("bind: %w", errs))
+	var ve *ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("ToValidationError() = %v, want *ValidationError", err)
+	}
+	want := []FieldError{
+		{Field: "Name", Rule: "required", Message: "Name failed the required rule"},
+		{Field: "Address.Zip", Rule: "len", Message: "Address.Zip failed the len=5 rule"},
+	}
+	if len(ve.Errors) != 2 || ve.Errors[0] != want[0] || ve.Errors[1] != want[1] {
+		t.Fatalf("errors = %+v, want %+v", ve.Errors, want)
+	}
+	if _, status, message := ParseError(err); status != 422 || message != "invalid request: Name failed the required rule; Address.Zip failed the len=5 rule" {
+		t.Fatalf("ParseError() = %d %q", status, message)
+	}
+	body, err := ve.MarshalJSON()
+	if err != nil || string(body) != `{"error":"`+ve.GetMessage()+`","errors":[{"field":"Name","rule":"required","message":"Name failed the required rule"},{"field":"Address.Zip","rule":"len","message":"Address.Zip failed the len=5 rule"}]}` {
+		t.Fatalf("MarshalJSON() = %s, %v", body, err)
+	}
+
+	plain := errors.New("bad json")
+	if got := ToValidationError(plain); got != plain {
+		t.Fatalf("ToValidationError(plain) = %v", got)
+	}
+	if got := ToValidationError(nil); got != nil {
+		t.Fatalf("ToValidationError(nil) = %v", got)
+	}
+}
+
+func TestToValidationErrorMissingFields(t *testing.T) {
+	mfe := &MissingFieldsError{Source: "query", Fields: []string{"name"}}
+	err := ToValidationError(mfe)
+	var ve *ValidationError
+	if !errors.As(err, &ve) || len(ve.Errors) != 1 || ve.Errors[0] != (FieldError{Field: "name", Rule: "required", Message: "name failed the required rule"}) {
+		t.Fatalf("ToValidationError() = %#v", err)
+	}
+	var got *MissingFieldsError
+	if !errors.As(err, &got) || got != mfe {
+		t.Fatalf("ToValidationError() does not wrap the *MissingFieldsError")
+	}
+
+	type filter struct {
+		Status string `query:"status" binding:"required"`
+	}
+	var dst struct {
+		Filter filter `query:"filter"`
+	}
+	err = BindValues(&dst, "query", map[string][]string{})
+	if !errors.As(err, &ve) || len(ve.Errors) != 1 || ve.Errors[0].Field != "Filter.Status" {
+		t.Fatalf("BindValues() error = %#v, want Filter.Status required", err)
+	}
+	if !errors.As(err, &got) || got.Fields[0] != "filter[status]" {
+		t.Fatalf("BindValues() error = %v, want filter[status] missing", err)
+	}
+}
+
+func TestValidateRequired(t *testing.T) {
+	type address struct {
+		City string `json:"city" binding:"required"`
+	}
+	type node struct {
+		Next *node
+	}
+	type input struct {
+		Name    string   `json:"name" binding:"required,min=3"`
+		Tags    []string `json:"tags" binding:"required"`
+		Address address  `json:"address" binding:"required"`
+		Billing *address `json:"billing"`
+		Node    *node
+	}
+	n := &node{}
+	n.Next = n
+	in := input{Tags: []string{}, Billing: &address{City: "Paris"}, Node: n}
+	err := ValidateRequired(&in)
+	var ve *ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("ValidateRequired() = %v, want *ValidationError", err)
+	}
+	want := []FieldError{
+		{Field: "Name", Rule: "required", Message: "Name failed the required rule"},
+		{Field: "Address.City", Rule: "required", Message: "Address.City failed the required rule"},
+	}
+	if len(ve.Errors) != 2 || ve.Errors[0] != want[0] || ve.Errors[1] != want[1] {
+		t.Fatalf("errors = %+v, want %+v", ve.Errors, want)
+	}
+	in = input{Name: "gopher", Tags: []string{"a"}, Address: address{City: "Lyon"}}
+	if err := ValidateRequired(&in); err != nil {
+		t.Fatalf("ValidateRequired() = %v", err)
+	}
+}
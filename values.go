@@ -0,0 +1,153 @@
+package httpx
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The typed getters below read a query parameter, path parameter or header
+// and parse it, returning def when the value is missing or does not parse.
+// Surrounding whitespace is ignored. Booleans accept the forms of
+// strconv.ParseBool, and times are parsed like fields tagged with
+// time_format: layout is a time layout, "unix", "unixmilli" or "unixnano",
+// and an empty layout tries BindTimeLayouts in order.
+
+// QueryInt returns the query parameter key as an int, or def.
+func QueryInt(ctx RequestInfo, key string, def int) int {
+	return parseOr(ctx.Query(key), def, strconv.Atoi)
+}
+
+// QueryBool returns the query parameter key as a bool, or def.
+func QueryBool(ctx RequestInfo, key string, def bool) bool {
+	return parseOr(ctx.Query(key), def, strconv.ParseBool)
+}
+
+// QueryFloat returns the query parameter key as a float64, or def.
+func QueryFloat(ctx RequestInfo, key string, def float64) float64 {
+	return parseOr(ctx.Query(key), def, parseFloat)
+}
+
+// QueryTime returns the query parameter key as a time.Time, or def.
+func QueryTime(ctx RequestInfo, key, layout string, def time.Time) time.Time {
+	return parseOr(ctx.Query(key), def, timeParser(layout))
+}
+
+// ParamInt returns the path parameter key as an int, or def.
+func ParamInt(ctx RequestInfo, key string, def int) int {
+	return parseOr(ctx.Param(key), def, strconv.Atoi)
+}
+
+// ParamBool returns the path parameter key as a bool, or def.
+func ParamBool(ctx RequestInfo, key string, def bool) bool {
+	return parseOr(ctx.Param(key), def, strconv.ParseBool)
+}
+
+// ParamFloat returns the path parameter key as a float64, or def.
+func ParamFloat(ctx RequestInfo, key string, def float64) float64 {
+	return parseOr(ctx.Param(key), def, parseFloat)
+}
+
+// ParamTime returns the path parameter key as a time.Time, or def.
+func ParamTime(ctx RequestInfo, key, layout string, def time.Time) time.Time {
+	return parseOr(ctx.Param(key), def, timeParser(layout))
+}
+
+// HeaderInt returns the request header key as an int, or def.
+func HeaderInt(ctx RequestInfo, key string, def int) int {
+	return parseOr(ctx.Header(key), def, strconv.Atoi)
+}
+
+// HeaderBool returns the request header key as a bool, or def.
+func HeaderBool(ctx RequestInfo, key string, def bool) bool {
+	return parseOr(ctx.Header(key), def, strconv.ParseBool)
+}
+
+// HeaderFloat returns the request header key as a float64, or def.
+func HeaderFloat(ctx RequestInfo, key string, def float64) float64 {
+	return parseOr(ctx.Header(key), def, parseFloat)
+}
+
+// HeaderTime returns the request header key as a time.Time, or def.
+func HeaderTime(ctx RequestInfo, key, layout string, def time.Time) time.Time {
+	return parseOr(ctx.Header(key), def, timeParser(layout))
+}
+
+// Param returns the path parameter key parsed as a T, decoded like a bound
+// field: numbers, booleans, time.Time, time.Duration, types registered with
+// RegisterBindType and encoding.TextUnmarshaler implementations, which
+// include the UUID and ULID types of the common packages:
+//
+//	id, err := httpx.Param[uuid.UUID](ctx, "id")
+//	if err != nil {
+//		return err
+//	}
+//
+// A missing or malformed parameter returns a *ParamError with status 400,
+// so handlers can return it as is.
+func Param[T any](ctx RequestInfo, key string) (T, error) {
+	var v T
+	value := ctx.Param(key)
+	if value == "" {
+		return v, &ParamError{Name: key, Err: errParamMissing}
+	}
+	if err := setValue(reflect.ValueOf(&v).Elem(), value, ""); err != nil {
+		return v, &ParamError{Name: key, Value: value, Err: err}
+	}
+	return v, nil
+}
+
+var errParamMissing = errors.New("missing value")
+
+// ParamError reports a path parameter Param could not parse. It is a
+// StatusError with status 400.
+type ParamError struct {
+	// Name is the name of the parameter.
+	Name string
+	// Value is the value of the parameter, empty when it is missing.
+	Value string
+	// Err is the error of the parse.
+	Err error
+}
+
+func (e *ParamError) Error() string {
+	return fmt.Sprintf("httpx: invalid path parameter %s %q: %v", e.Name, e.Value, e.Err)
+}
+
+func (e *ParamError) Unwrap() error {
+	return e.Err
+}
+
+func (e *ParamError) GetStatus() int32 {
+	return http.StatusBadRequest
+}
+
+func (e *ParamError) GetMessage() string {
+	return "invalid path parameter " + e.Name
+}
+
+func parseOr[T any](value string, def T, parse func(string) (T, error)) T {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return def
+	}
+	v, err := parse(value)
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+func parseFloat(value string) (float64, error) {
+	return strconv.ParseFloat(value, 64)
+}
+
+func timeParser(layout string) func(string) (time.Time, error) {
+	return func(value string) (time.Time, error) {
+		return parseTime(value, layout)
+	}
+}
@@ -0,0 +1,81 @@
+package httpx
+
+import (
+	"errors"
+	"net"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTypedGetters(t *testing.T) {
+	req := httptest.NewRequest("GET", "/items/42?page=3&bad=x&on=true&ratio=0.5&since=2024-05-01&ts=1700000000", nil)
+	req.Header.Set("X-Retry", " 2 ")
+	req.Header.Set("X-Debug", "1")
+	ctx := newHTTPContext(httptest.NewRecorder(), req, nil)
+	ctx.params = map[string]string{"id": "42", "at": "2024-05-01T10:00:00Z"}
+
+	if got := QueryInt(ctx, "page", 1); got != 3 {
+		t.Fatalf("QueryInt(page) = %d, want 3", got)
+	}
+	if got := QueryInt(ctx, "bad", 1); got != 1 {
+		t.Fatalf("QueryInt(bad) = %d, want default 1", got)
+	}
+	if got := QueryInt(ctx, "missing", 7); got != 7 {
+		t.Fatalf("QueryInt(missing) = %d, want default 7", got)
+	}
+	if !QueryBool(ctx, "on", false) || !QueryBool(ctx, "missing", true) {
+		t.Fatalf("QueryBool did not parse or default")
+	}
+	if got := QueryFloat(ctx, "ratio", 0); got != 0.5 {
+		t.Fatalf("QueryFloat(ratio) = %v, want 0.5", got)
+	}
+	if got := QueryTime(ctx, "since", "", time.Time{}); !got.Equal(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("QueryTime(since) = %v", got)
+	}
+	if got := QueryTime(ctx, "ts", "unix", time.Time{}); got.Unix() != 1700000000 {
+		t.Fatalf("QueryTime(ts, unix) = %v", got)
+	}
+	def := time.Unix(1, 0)
+	if got := QueryTime(ctx, "bad", time.DateOnly, def); !got.Equal(def) {
+		t.Fatalf("QueryTime(bad) = %v, want default", got)
+	}
+
+	if got := ParamInt(ctx, "id", 0); got != 42 {
+		t.Fatalf("ParamInt(id) = %d, want 42", got)
+	}
+	if got := ParamTime(ctx, "at", time.RFC3339, time.Time{}); got.Hour() != 10 {
+		t.Fatalf("ParamTime(at) = %v", got)
+	}
+	if got := HeaderInt(ctx, "X-Retry", 0); got != 2 {
+		t.Fatalf("HeaderInt(X-Retry) = %d, want 2", got)
+	}
+	if !HeaderBool(ctx, "X-Debug", false) {
+		t.Fatalf("HeaderBool(X-Debug) = false, want true")
+	}
+	if got := HeaderFloat(ctx, "X-Missing", 1.5); got != 1.5 {
+		t.Fatalf("HeaderFloat(X-Missing) = %v, want default", got)
+	}
+}
+
+func TestParam(t *testing.T) {
+	ctx := newHTTPContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil), nil)
+	ctx.params = map[string]string{"id": "9007199254740993", "ip": "10.0.0.1", "bad": "x"}
+	if id, err := Param[int64](ctx, "id"); err != nil || id != 9007199254740993 {
+		t.Fatalf("Param[int64] = %d, %v", id, err)
+	}
+	// net.IP stands in for UUID types, which are text unmarshalers too.
+	if ip, err := Param[net.IP](ctx, "ip"); err != nil || ip.String() != "10.0.0.1" {
+		t.Fatalf("Param[net.IP] = %v, %v", ip, err)
+	}
+	for _, key := range []string{"bad", "missing"} {
+		_, err := Param[int](ctx, key)
+		var pe *ParamError
+		if !errors.As(err, &pe) || pe.Name != key {
+			t.Fatalf("Param[int](%s) error = %v, want *ParamError", key, err)
+		}
+		if _, status, message := ParseError(err); status != 400 || message != "invalid path parameter "+key {
+			t.Fatalf("ParseError() = %d %q", status, message)
+		}
+	}
+}
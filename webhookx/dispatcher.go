@@ -0,0 +1,353 @@
+package webhookx
+
+import (
+	"bytes"
+	"context"
+	cryptorand "crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-sphere/httpx"
+)
+
+const (
+	// DefaultMaxAttempts is the default number of times a Dispatcher tries a
+	// delivery.
+	DefaultMaxAttempts = 5
+	// DefaultHistorySize is the default number of deliveries a Dispatcher
+	// keeps for introspection.
+	DefaultHistorySize = 1000
+	// DefaultEventHeader is the default header carrying the event type of a
+	// delivery.
+	DefaultEventHeader = "X-Webhook-Event"
+	// DeliveryHeader is the header carrying the ID of a delivery, which
+	// stays the same across attempts so receivers can drop duplicates.
+	DeliveryHeader = "X-Webhook-Delivery"
+)
+
+// ErrDispatcherClosed reports a delivery dispatched after Close.
+var ErrDispatcherClosed = errors.New("webhookx: dispatcher closed")
+
+// DeliveryState is the state of a delivery.
+type DeliveryState string
+
+const (
+	DeliveryPending   DeliveryState = "pending"
+	DeliverySucceeded DeliveryState = "succeeded"
+	DeliveryFailed    DeliveryState = "failed"
+)
+
+// Delivery is the record of an event sent by a Dispatcher.
+type Delivery struct {
+	ID       string        `json:"id"`
+	Event    string        `json:"event"`
+	URL      string        `json:"url"`
+	State    DeliveryState `json:"state"`
+	Attempts int           `json:"attempts"`
+	// StatusCode is the status of the last response, or 0 when the last
+	// attempt got none.
+	StatusCode int       `json:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// DispatcherOption configures a Dispatcher.
+type DispatcherOption func(*dispatcherConfig)
+
+type dispatcherConfig struct {
+	client      *http.Client
+	maxAttempts int
+	backoff     func(attempt int) time.Duration
+	historySize int
+	eventHeader string
+}
+
+// WithHTTPClient sets the client deliveries are sent with,
+// http.DefaultClient by default.
+func WithHTTPClient(client *http.Client) DispatcherOption {
+	return func(c *dispatcherConfig) {
+		c.client = client
+	}
+}
+
+// WithMaxAttempts sets the number of times a delivery is tried,
+// DefaultMaxAttempts by default.
+func WithMaxAttempts(n int) DispatcherOption {
+	return func(c *dispatcherConfig) {
+		c.maxAttempts = n
+	}
+}
+
+// WithBackoff sets the wait before retrying after the given failed attempt,
+// counted from 1. The default doubles from one second up to a minute, with
+// jitter. A Retry-After header of the response takes precedence.
+func WithBackoff(backoff func(attempt int) time.Duration) DispatcherOption {
+	return func(c *dispatcherConfig) {
+		c.backoff = backoff
+	}
+}
+
+// WithHistorySize sets the number of deliveries kept for introspection,
+// DefaultHistorySize by default. The oldest are forgotten first.
+func WithHistorySize(n int) DispatcherOption {
+	return func(c *dispatcherConfig) {
+		c.historySize = n
+	}
+}
+
+// WithEventHeader sets the header carrying the event type, DefaultEventHeader
+// by default, such as X-GitHub-Event to mimic GitHub.
+func WithEventHeader(name string) DispatcherOption {
+	return func(c *dispatcherConfig) {
+		c.eventHeader = name
+	}
+}
+
+func defaultBackoff(attempt int) time.Duration {
+	d := min(time.Second<<min(attempt-1, 6), time.Minute)
+	return d/2 + rand.N(d/2+1)
+}
+
+// Dispatcher sends webhook events signed with a Scheme, retrying failed
+// deliveries in the background, and keeps a history of the deliveries that
+// Mount serves. It is safe for concurrent use.
+type Dispatcher struct {
+	scheme Scheme
+	conf   dispatcherConfig
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu         sync.RWMutex
+	closed     bool
+	deliveries map[string]*Delivery
+	order      []string
+}
+
+// NewDispatcher returns a Dispatcher signing payloads with scheme.
+func NewDispatcher(scheme Scheme, opts ...DispatcherOption) *Dispatcher {
+	conf := dispatcherConfig{
+		client:      http.DefaultClient,
+		maxAttempts: DefaultMaxAttempts,
+		backoff:     defaultBackoff,
+		historySize: DefaultHistorySize,
+		eventHeader: DefaultEventHeader,
+	}
+	for _, opt := range opts {
+		opt(&conf)
+	}
+	conf.maxAttempts = max(conf.maxAttempts, 1)
+	conf.historySize = max(conf.historySize, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Dispatcher{
+		scheme:     scheme,
+		conf:       conf,
+		ctx:        ctx,
+		cancel:     cancel,
+		deliveries: make(map[string]*Delivery),
+	}
+}
+
+// Dispatch sends the JSON payload of an event of type event to url in the
+// background and returns the ID of the delivery. A delivery succeeds on a 2xx
+// response. It is retried after network errors and responses with status
+// 408, 429 or 5xx, and fails on other responses or after the last attempt.
+//
+// The values of ctx are kept, but not its cancellation, so a delivery
+// dispatched by a handler outlives the request.
+func (d *Dispatcher) Dispatch(ctx context.Context, url, event string, payload []byte) (string, error) {
+	if _, err := http.NewRequest(http.MethodPost, url, nil); err != nil {
+		return "", err
+	}
+	id, err := newDeliveryID()
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	delivery := &Delivery{ID: id, Event: event, URL: url, State: DeliveryPending, CreatedAt: now, UpdatedAt: now}
+
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return "", ErrDispatcherClosed
+	}
+	d.deliveries[id] = delivery
+	d.order = append(d.order, id)
+	if len(d.order) > d.conf.historySize {
+		delete(d.deliveries, d.order[0])
+		d.order = d.order[1:]
+	}
+	d.wg.Add(1)
+	d.mu.Unlock()
+
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		defer d.wg.Done()
+		d.deliver(ctx, delivery, payload)
+	}()
+	return id, nil
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, delivery *Delivery, payload []byte) {
+	for attempt := 1; ; attempt++ {
+		status, retryAfter, err := d.attempt(ctx, delivery, payload)
+		state := DeliveryPending
+		switch {
+		case err == nil:
+			state = DeliverySucceeded
+		case attempt >= d.conf.maxAttempts || !retryable(status):
+			state = DeliveryFailed
+		}
+		d.update(delivery, func(rec *Delivery) {
+			rec.State = state
+			rec.Attempts = attempt
+			rec.StatusCode = status
+			rec.Error = ""
+			if err != nil {
+				rec.Error = err.Error()
+			}
+		})
+		if state != DeliveryPending {
+			return
+		}
+		wait := retryAfter
+		if wait <= 0 {
+			wait = d.conf.backoff(attempt)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-d.ctx.Done():
+			timer.Stop()
+			d.update(delivery, func(rec *Delivery) {
+				rec.State = DeliveryFailed
+				rec.Error = ErrDispatcherClosed.Error()
+			})
+			return
+		}
+	}
+}
+
+// attempt sends payload once. It returns the response status, or 0 without
+// a response, and the wait asked for by a Retry-After header.
+func (d *Dispatcher) attempt(ctx context.Context, delivery *Delivery, payload []byte) (int, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(d.conf.eventHeader, delivery.Event)
+	req.Header.Set(DeliveryHeader, delivery.ID)
+	d.scheme.Sign(req.Header, payload)
+	resp, err := d.conf.client.Do(req)
+	if err != nil {
+		return 0, 0, err
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	_ = resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp.StatusCode, 0, nil
+	}
+	var retryAfter time.Duration
+	if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+		retryAfter = time.Duration(secs) * time.Second
+	}
+	return resp.StatusCode, retryAfter, fmt.Errorf("webhookx: delivery got status %d", resp.StatusCode)
+}
+
+func retryable(status int) bool {
+	return status == 0 || status == http.StatusRequestTimeout || status == http.StatusTooManyRequests || status >= 500
+}
+
+func (d *Dispatcher) update(delivery *Delivery, fn func(*Delivery)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	fn(delivery)
+	delivery.UpdatedAt = time.Now()
+}
+
+// Delivery returns the delivery with the given ID, while it is in the
+// history.
+func (d *Dispatcher) Delivery(id string) (Delivery, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	delivery, ok := d.deliveries[id]
+	if !ok {
+		return Delivery{}, false
+	}
+	return *delivery, true
+}
+
+// Deliveries returns the deliveries in the history, newest first, keeping
+// those in state when it is not empty.
+func (d *Dispatcher) Deliveries(state DeliveryState) []Delivery {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	deliveries := make([]Delivery, 0, len(d.order))
+	for i := len(d.order) - 1; i >= 0; i-- {
+		delivery := d.deliveries[d.order[i]]
+		if state == "" || delivery.State == state {
+			deliveries = append(deliveries, *delivery)
+		}
+	}
+	return deliveries
+}
+
+// Mount registers the delivery status routes on r:
+//
+//   - GET /deliveries lists the deliveries, newest first, filtered by the
+//     state query parameter when set
+//   - GET /deliveries/:id returns a delivery, or fails with status 404
+//
+// The routes expose delivery URLs and errors; mount them on a group with
+// access control.
+func (d *Dispatcher) Mount(r httpx.Router) {
+	r.GET("/deliveries", func(ctx httpx.Context) error {
+		return ctx.JSON(http.StatusOK, d.Deliveries(DeliveryState(ctx.Query("state"))))
+	})
+	r.GET("/deliveries/:id", func(ctx httpx.Context) error {
+		delivery, ok := d.Delivery(ctx.Param("id"))
+		if !ok {
+			return httpx.NewNotFoundError("webhookx: delivery not found")
+		}
+		return ctx.JSON(http.StatusOK, delivery)
+	})
+}
+
+// Close stops accepting deliveries and waits for those in progress,
+// including their retries, until ctx is done. Deliveries still waiting for a
+// retry then fail with ErrDispatcherClosed.
+func (d *Dispatcher) Close(ctx context.Context) error {
+	d.mu.Lock()
+	d.closed = true
+	d.mu.Unlock()
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		d.cancel()
+		return nil
+	case <-ctx.Done():
+		d.cancel()
+		return ctx.Err()
+	}
+}
+
+func newDeliveryID() (string, error) {
+	var b [16]byte
+	if _, err := cryptorand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b[:]), nil
+}
@@ -0,0 +1,231 @@
+package webhookx
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-sphere/httpx"
+)
+
+// DefaultTolerance is the default time a signed timestamp is accepted for,
+// in either direction, by schemes that sign one.
+const DefaultTolerance = 5 * time.Minute
+
+var (
+	// ErrInvalidSignature reports a webhook request with a missing or wrong
+	// signature.
+	ErrInvalidSignature = errors.New("webhookx: invalid signature")
+	// ErrStaleTimestamp reports a webhook request whose signed timestamp is
+	// outside the tolerance, such as a replayed request.
+	ErrStaleTimestamp = errors.New("webhookx: timestamp outside tolerance")
+)
+
+// Scheme is the signature scheme of a webhook provider, which also tells the
+// type of the events it sends.
+type Scheme interface {
+	// Verify checks the signature of body, the raw request body. It returns
+	// an error wrapping ErrInvalidSignature or ErrStaleTimestamp when the
+	// request must be rejected.
+	Verify(ctx httpx.Context, body []byte) error
+	// EventType returns the type of the event carried by body.
+	EventType(ctx httpx.Context, body []byte) (string, error)
+	// Sign sets the signature headers of an outgoing request with body on
+	// header, the way the provider does.
+	Sign(header http.Header, body []byte)
+}
+
+// Option configures the timestamp checks of Stripe and Slack.
+type Option func(*options)
+
+type options struct {
+	tolerance time.Duration
+	now       func() time.Time
+}
+
+// WithTolerance sets the time a signed timestamp is accepted for,
+// DefaultTolerance by default. A negative tolerance disables the check.
+func WithTolerance(d time.Duration) Option {
+	return func(o *options) {
+		o.tolerance = d
+	}
+}
+
+// WithClock sets the clock timestamps are checked and signed with,
+// time.Now by default.
+func WithClock(now func() time.Time) Option {
+	return func(o *options) {
+		o.now = now
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{tolerance: DefaultTolerance, now: time.Now}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+func (o options) checkTimestamp(unix string) error {
+	sec, err := strconv.ParseInt(unix, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: malformed timestamp %q", ErrInvalidSignature, unix)
+	}
+	if o.tolerance < 0 {
+		return nil
+	}
+	if age := o.now().Sub(time.Unix(sec, 0)); age > o.tolerance || age < -o.tolerance {
+		return fmt.Errorf("%w: signed %v ago", ErrStaleTimestamp, age.Round(time.Second))
+	}
+	return nil
+}
+
+func hmacHex(secret []byte, parts ...string) string {
+	mac := hmac.New(sha256.New, secret)
+	for _, part := range parts {
+		mac.Write([]byte(part))
+	}
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func signatureEqual(got, want string) bool {
+	return hmac.Equal([]byte(got), []byte(want))
+}
+
+// GitHub returns the scheme of GitHub webhooks: an HMAC-SHA256 of the body
+// in X-Hub-Signature-256, and the event type in X-GitHub-Event. GitHub does
+// not sign a timestamp.
+func GitHub(secret string) Scheme {
+	return githubScheme{secret: []byte(secret)}
+}
+
+type githubScheme struct {
+	secret []byte
+}
+
+func (s githubScheme) Verify(ctx httpx.Context, body []byte) error {
+	sig, ok := strings.CutPrefix(ctx.Header("X-Hub-Signature-256"), "sha256=")
+	if !ok {
+		return fmt.Errorf("%w: missing X-Hub-Signature-256", ErrInvalidSignature)
+	}
+	if !signatureEqual(sig, hmacHex(s.secret, string(body))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func (githubScheme) EventType(ctx httpx.Context, _ []byte) (string, error) {
+	return ctx.Header("X-GitHub-Event"), nil
+}
+
+func (s githubScheme) Sign(header http.Header, body []byte) {
+	header.Set("X-Hub-Signature-256", "sha256="+hmacHex(s.secret, string(body)))
+}
+
+// Stripe returns the scheme of Stripe webhooks: a timestamp and HMAC-SHA256
+// signatures of the timestamp and body in Stripe-Signature, and the event
+// type in the type field of the JSON body. Any of the v1 signatures may
+// match, as during secret rotation.
+func Stripe(secret string, opts ...Option) Scheme {
+	return stripeScheme{secret: []byte(secret), options: newOptions(opts)}
+}
+
+type stripeScheme struct {
+	secret []byte
+	options
+}
+
+func (s stripeScheme) Verify(ctx httpx.Context, body []byte) error {
+	var timestamp string
+	var signatures []string
+	for _, field := range strings.Split(ctx.Header("Stripe-Signature"), ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(field), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	if timestamp == "" || len(signatures) == 0 {
+		return fmt.Errorf("%w: missing Stripe-Signature", ErrInvalidSignature)
+	}
+	want := hmacHex(s.secret, timestamp, ".", string(body))
+	for _, sig := range signatures {
+		if signatureEqual(sig, want) {
+			return s.checkTimestamp(timestamp)
+		}
+	}
+	return ErrInvalidSignature
+}
+
+func (stripeScheme) EventType(_ httpx.Context, body []byte) (string, error) {
+	var event struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(body, &event); err != nil {
+		return "", err
+	}
+	return event.Type, nil
+}
+
+func (s stripeScheme) Sign(header http.Header, body []byte) {
+	timestamp := strconv.FormatInt(s.now().Unix(), 10)
+	header.Set("Stripe-Signature", "t="+timestamp+",v1="+hmacHex(s.secret, timestamp, ".", string(body)))
+}
+
+// Slack returns the scheme of the Slack Events API: a timestamp in
+// X-Slack-Request-Timestamp and an HMAC-SHA256 of it and the body in
+// X-Slack-Signature. The event type is the type of the inner event of
+// event_callback bodies, and the type field of the JSON body otherwise,
+// such as url_verification.
+func Slack(secret string, opts ...Option) Scheme {
+	return slackScheme{secret: []byte(secret), options: newOptions(opts)}
+}
+
+type slackScheme struct {
+	secret []byte
+	options
+}
+
+func (s slackScheme) Verify(ctx httpx.Context, body []byte) error {
+	timestamp := ctx.Header("X-Slack-Request-Timestamp")
+	sig, ok := strings.CutPrefix(ctx.Header("X-Slack-Signature"), "v0=")
+	if timestamp == "" || !ok {
+		return fmt.Errorf("%w: missing X-Slack-Signature", ErrInvalidSignature)
+	}
+	if !signatureEqual(sig, hmacHex(s.secret, "v0:", timestamp, ":", string(body))) {
+		return ErrInvalidSignature
+	}
+	return s.checkTimestamp(timestamp)
+}
+
+func (slackScheme) EventType(_ httpx.Context, body []byte) (string, error) {
+	var event struct {
+		Type  string `json:"type"`
+		Event struct {
+			Type string `json:"type"`
+		} `json:"event"`
+	}
+	if err := json.Unmarshal(body, &event); err != nil {
+		return "", err
+	}
+	if event.Type == "event_callback" && event.Event.Type != "" {
+		return event.Event.Type, nil
+	}
+	return event.Type, nil
+}
+
+func (s slackScheme) Sign(header http.Header, body []byte) {
+	timestamp := strconv.FormatInt(s.now().Unix(), 10)
+	header.Set("X-Slack-Request-Timestamp", timestamp)
+	header.Set("X-Slack-Signature", "v0="+hmacHex(s.secret, "v0:", timestamp, ":", string(body)))
+}
@@ -0,0 +1,126 @@
+// Package webhookx receives webhooks on httpx routes: it verifies the
+// signature of the provider, then dispatches the event to the handler of its
+// type.
+//
+//	hooks := webhookx.New(webhookx.GitHub(secret))
+//	hooks.On("push", webhookx.Typed(func(ctx httpx.Context, push PushEvent) error {
+//		return ctx.NoContent(http.StatusNoContent)
+//	}))
+//	r.POST("/hooks/github", hooks.Handle)
+//
+// Signatures are computed over the raw request body, which is read with
+// Context.BodyRaw; adapters keep it, so handlers can read the body again.
+package webhookx
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/go-sphere/httpx"
+)
+
+// Event is a verified webhook event.
+type Event struct {
+	// Type is the event type reported by the scheme, such as "push".
+	Type string
+	// Payload is the raw request body, as signed.
+	Payload []byte
+}
+
+// Decode unmarshals the JSON payload of e into dst.
+func (e Event) Decode(dst any) error {
+	return json.Unmarshal(e.Payload, dst)
+}
+
+// Handler handles a verified event.
+type Handler func(ctx httpx.Context, event Event) error
+
+// Typed returns a Handler decoding the JSON payload into a T for h. Payloads
+// that do not decode fail with status 400.
+func Typed[T any](h func(ctx httpx.Context, event T) error) Handler {
+	return func(ctx httpx.Context, event Event) error {
+		var v T
+		if err := event.Decode(&v); err != nil {
+			return httpx.BadRequestError(err)
+		}
+		return h(ctx, v)
+	}
+}
+
+// Receiver verifies webhook requests with a Scheme and dispatches their
+// events by type. It is safe for concurrent use.
+type Receiver struct {
+	scheme   Scheme
+	mu       sync.RWMutex
+	handlers map[string]Handler
+	fallback Handler
+}
+
+// New returns a Receiver verifying requests with scheme.
+func New(scheme Scheme) *Receiver {
+	return &Receiver{scheme: scheme, handlers: make(map[string]Handler)}
+}
+
+// On sets the handler of the events of type event.
+func (r *Receiver) On(event string, h Handler) *Receiver {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers[event] = h
+	return r
+}
+
+// Default sets the handler of the events without a handler of their own.
+// Without one, such events are acknowledged with status 204.
+func (r *Receiver) Default(h Handler) *Receiver {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fallback = h
+	return r
+}
+
+// Handle is the httpx.Handler of the webhook route. Requests failing
+// verification fail with status 401 wrapping ErrInvalidSignature or
+// ErrStaleTimestamp, before any handler runs.
+func (r *Receiver) Handle(ctx httpx.Context) error {
+	body, err := verify(ctx, r.scheme)
+	if err != nil {
+		return err
+	}
+	eventType, err := r.scheme.EventType(ctx, body)
+	if err != nil {
+		return httpx.BadRequestError(err)
+	}
+	r.mu.RLock()
+	h, ok := r.handlers[eventType]
+	if !ok {
+		h = r.fallback
+	}
+	r.mu.RUnlock()
+	if h == nil {
+		return ctx.NoContent(http.StatusNoContent)
+	}
+	return h(ctx, Event{Type: eventType, Payload: body})
+}
+
+// Verify returns middleware rejecting requests that fail the verification of
+// scheme, for webhook routes handled without a Receiver.
+func Verify(scheme Scheme) httpx.Middleware {
+	return func(ctx httpx.Context) error {
+		if _, err := verify(ctx, scheme); err != nil {
+			return err
+		}
+		return ctx.Next()
+	}
+}
+
+func verify(ctx httpx.Context, scheme Scheme) ([]byte, error) {
+	body, err := ctx.BodyRaw()
+	if err != nil {
+		return nil, err
+	}
+	if err := scheme.Verify(ctx, body); err != nil {
+		return nil, httpx.UnauthorizedError(err)
+	}
+	return body, nil
+}
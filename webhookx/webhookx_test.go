@@ -0,0 +1,115 @@
+package webhookx
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-sphere/httpx"
+)
+
+func TestSchemes(t *testing.T) {
+	signedAt := time.Unix(1_700_000_000, 0)
+	clock := WithClock(func() time.Time { return signedAt })
+	later := WithClock(func() time.Time { return signedAt.Add(10 * time.Minute) })
+	tests := []struct {
+		name      string
+		signer    Scheme
+		verifier  Scheme
+		body      string
+		tamper    bool
+		header    map[string]string
+		wantErr   error
+		wantEvent string
+	}{
+		{name: "github", signer: GitHub("s3cret"), verifier: GitHub("s3cret"), body: `{"ref":"main"}`, header: map[string]string{"X-GitHub-Event": "push"}, wantEvent: "push"},
+		{name: "github wrong secret", signer: GitHub("other"), verifier: GitHub("s3cret"), body: `{}`, wantErr: ErrInvalidSignature},
+		{name: "github tampered", signer: GitHub("s3cret"), verifier: GitHub("s3cret"), body: `{}`, tamper: true, wantErr: ErrInvalidSignature},
+		{name: "stripe", signer: Stripe("whsec", clock), verifier: Stripe("whsec", clock), body: `{"type":"invoice.paid"}`, wantEvent: "invoice.paid"},
+		{name: "stripe stale", signer: Stripe("whsec", clock), verifier: Stripe("whsec", later), body: `{"type":"invoice.paid"}`, wantErr: ErrStaleTimestamp},
+		{name: "stripe no tolerance", signer: Stripe("whsec", clock), verifier: Stripe("whsec", later, WithTolerance(-1)), body: `{"type":"invoice.paid"}`, wantEvent: "invoice.paid"},
+		{name: "slack callback", signer: Slack("xoxs", clock), verifier: Slack("xoxs", clock), body: `{"type":"event_callback","event":{"type":"app_mention"}}`, wantEvent: "app_mention"},
+		{name: "slack verification", signer: Slack("xoxs", clock), verifier: Slack("xoxs", clock), body: `{"type":"url_verification"}`, wantEvent: "url_verification"},
+		{name: "slack stale", signer: Slack("xoxs", clock), verifier: Slack("xoxs", later), body: `{}`, wantErr: ErrStaleTimestamp},
+		{name: "unsigned", verifier: Slack("xoxs", clock), body: `{}`, wantErr: ErrInvalidSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var verifyErr error
+			var event string
+			h := httpx.ToHTTPHandler(func(ctx httpx.Context) error {
+				body, err := ctx.BodyRaw()
+				if err != nil {
+					return err
+				}
+				if verifyErr = tt.verifier.Verify(ctx, body); verifyErr == nil {
+					event, verifyErr = tt.verifier.EventType(ctx, body)
+				}
+				return nil
+			})
+			req := httptest.NewRequest(http.MethodPost, "/hooks", strings.NewReader(tt.body))
+			if tt.signer != nil {
+				tt.signer.Sign(req.Header, []byte(tt.body))
+			}
+			if tt.tamper {
+				req.Body = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body+" ")).Body
+			}
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+			h.ServeHTTP(httptest.NewRecorder(), req)
+			if tt.wantErr != nil {
+				if !errors.Is(verifyErr, tt.wantErr) {
+					t.Fatalf("error %v, want %v", verifyErr, tt.wantErr)
+				}
+				return
+			}
+			if verifyErr != nil || event != tt.wantEvent {
+				t.Fatalf("event %q, error %v, want %q", event, verifyErr, tt.wantEvent)
+			}
+		})
+	}
+}
+
+func TestDispatcherHistory(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	d := NewDispatcher(GitHub("s3cret"),
+		WithMaxAttempts(3),
+		WithHistorySize(2),
+		WithBackoff(func(int) time.Duration { return time.Millisecond }),
+	)
+	var ids []string
+	for range 3 {
+		id, err := d.Dispatch(t.Context(), server.URL, "push", []byte(`{}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+	if err := d.Close(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Dispatch(t.Context(), server.URL, "push", nil); !errors.Is(err, ErrDispatcherClosed) {
+		t.Fatalf("dispatch after close: %v", err)
+	}
+	if got := attempts.Load(); got != 9 {
+		t.Fatalf("server got %d attempts, want 9", got)
+	}
+	if _, ok := d.Delivery(ids[0]); ok {
+		t.Fatal("oldest delivery kept past the history size")
+	}
+	deliveries := d.Deliveries(DeliveryFailed)
+	if len(deliveries) != 2 || deliveries[0].ID != ids[2] || deliveries[1].Attempts != 3 {
+		t.Fatalf("deliveries: %+v", deliveries)
+	}
+}
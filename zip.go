@@ -0,0 +1,98 @@
+package httpx
+
+import (
+	"archive/zip"
+	"io"
+	"iter"
+	"net/http"
+	"time"
+)
+
+// ZipEntry is a file of the archive written by ZipStream.
+type ZipEntry struct {
+	// Name is the path of the file in the archive, with forward slashes.
+	Name string
+	// Modified is the modification time of the file, the time of writing
+	// when zero.
+	Modified time.Time
+	// Body is the content of the file. It is closed once written when it
+	// implements io.Closer.
+	Body io.Reader
+	// Store writes the file uncompressed, for content that is compressed
+	// already, such as images.
+	Store bool
+}
+
+// ZipStream responds with a zip archive of entries, streamed as it is built
+// rather than buffered, for "download all" endpoints:
+//
+//	ctx.SetHeader("Content-Disposition", `attachment; filename="photos.zip"`)
+//	return httpx.ZipStream(ctx, func(yield func(httpx.ZipEntry, error) bool) {
+//		for _, photo := range photos {
+//			f, err := os.Open(photo.Path)
+//			if !yield(httpx.ZipEntry{Name: photo.Name, Body: f, Store: true}, err) {
+//				return
+//			}
+//		}
+//	})
+//
+// entries is pulled lazily, one entry per file, while the response body is
+// written through DataFromReader, so it must not use ctx: fiber and hertz
+// send the body after the handler returns. An error yielded for the first
+// entry is returned before anything is written. Later errors, from entries
+// or their bodies, cannot change the committed response; they end the body
+// early, so the client sees a truncated download.
+func ZipStream(ctx Context, entries iter.Seq2[ZipEntry, error]) error {
+	next, stop := iter.Pull2(entries)
+	entry, err, ok := next()
+	if err != nil {
+		stop()
+		closeEntry(entry)
+		return err
+	}
+	pr, pw := io.Pipe()
+	go func() {
+		defer stop()
+		zw := zip.NewWriter(pw)
+		for ok {
+			if err := writeZipEntry(zw, entry); err != nil {
+				_ = pw.CloseWithError(err)
+				return
+			}
+			entry, err, ok = next()
+			if err != nil {
+				closeEntry(entry)
+				_ = pw.CloseWithError(err)
+				return
+			}
+		}
+		_ = pw.CloseWithError(zw.Close())
+	}()
+	return ctx.DataFromReader(http.StatusOK, "application/zip", pr, -1)
+}
+
+func writeZipEntry(zw *zip.Writer, entry ZipEntry) error {
+	defer closeEntry(entry)
+	header := &zip.FileHeader{Name: entry.Name, Method: zip.Deflate, Modified: entry.Modified}
+	if entry.Store {
+		header.Method = zip.Store
+	}
+	if header.Modified.IsZero() {
+		header.Modified = time.Now()
+	}
+	w, err := zw.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	if entry.Body == nil {
+		return nil
+	}
+	_, err = io.Copy(w, entry.Body)
+	return err
+}
+
+func closeEntry(entry ZipEntry) {
+	if c, ok := entry.Body.(io.Closer); ok {
+		_ = c.Close()
+	}
+}